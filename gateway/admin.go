@@ -0,0 +1,210 @@
+package gateway
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	gwCapabilities "github.com/gate4ai/gate4ai/gateway/capability"
+	"github.com/gate4ai/gate4ai/server/metrics"
+	"github.com/gate4ai/gate4ai/server/transport"
+	"github.com/gate4ai/gate4ai/shared/config"
+	"go.uber.org/zap"
+)
+
+// DefaultAdminAPIPath is where the admin API is usually mounted.
+const DefaultAdminAPIPath = "/admin/v1/"
+
+// AdminSession is an element of the GET sessions response.
+type AdminSession struct {
+	SessionID         string    `json:"sessionId"`
+	UserID            string    `json:"userId"`
+	Status            string    `json:"status"`
+	CreatedAt         time.Time `json:"createdAt"`
+	LastActivity      time.Time `json:"lastActivity"`
+	SubscribedServers []string  `json:"subscribedServers"`
+}
+
+// AdminBackendSession is a session the gateway holds to a backend server on behalf of a client
+// session, as listed by GET backends.
+type AdminBackendSession struct {
+	ClientSessionID  string    `json:"clientSessionId"`
+	BackendSessionID string    `json:"backendSessionId"`
+	Status           string    `json:"status"`
+	LastActivity     time.Time `json:"lastActivity"`
+}
+
+// AdminAPI serves the gateway admin REST API:
+//
+//	GET    sessions           the client sessions with their users and subscribed servers
+//	DELETE sessions/{id}      closes a client session
+//	GET    backends           the backend sessions by server slug
+//	GET    metrics/summary    request, error and task counts (see metrics.Summary)
+//
+// Every request must carry "Authorization: Bearer <token>" with the token of
+// config.IConfig.AdminToken, which is read per request so it can change at runtime. Without a
+// configured token every request is refused.
+type AdminAPI struct {
+	logger  *zap.Logger
+	cfg     config.IConfig
+	manager *transport.Manager
+	metrics *metrics.Metrics
+	mux     *http.ServeMux
+}
+
+// NewAdminAPI creates the admin API of the sessions held by manager. The paths of the requests
+// it serves are relative to where it is mounted, so strip the mount prefix first (Node.Start
+// does). Without metrics collectors the metrics summary is not available.
+func NewAdminAPI(logger *zap.Logger, cfg config.IConfig, manager *transport.Manager, m *metrics.Metrics) *AdminAPI {
+	a := &AdminAPI{
+		logger:  logger.With(zap.String("handler", "AdminAPI")),
+		cfg:     cfg,
+		manager: manager,
+		metrics: m,
+		mux:     http.NewServeMux(),
+	}
+	a.mux.HandleFunc("GET /sessions", a.listSessions)
+	a.mux.HandleFunc("DELETE /sessions/{id}", a.closeSession)
+	a.mux.HandleFunc("GET /backends", a.listBackends)
+	a.mux.HandleFunc("GET /metrics/summary", a.metricsSummary)
+	return a
+}
+
+// ServeHTTP implements http.Handler.
+func (a *AdminAPI) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if status, err := a.checkToken(r); err != nil {
+		a.logger.Warn("Rejected admin request", zap.String("path", r.URL.Path), zap.Error(err))
+		writeAdminError(w, status, err.Error())
+		return
+	}
+	a.mux.ServeHTTP(w, r)
+}
+
+func (a *AdminAPI) checkToken(r *http.Request) (int, error) {
+	adminToken, err := a.cfg.AdminToken()
+	if err != nil && !errors.Is(err, config.ErrNotFound) {
+		return http.StatusInternalServerError, fmt.Errorf("failed to read admin token: %w", err)
+	}
+	if adminToken == "" {
+		return http.StatusForbidden, errors.New("admin API disabled: no admin token configured")
+	}
+	token, found := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if !found {
+		return http.StatusUnauthorized, errors.New("missing bearer token")
+	}
+	if subtle.ConstantTimeCompare([]byte(token), []byte(adminToken)) != 1 {
+		return http.StatusUnauthorized, errors.New("invalid admin token")
+	}
+	return 0, nil
+}
+
+func (a *AdminAPI) listSessions(w http.ResponseWriter, r *http.Request) {
+	sessions := make([]AdminSession, 0)
+	for _, session := range a.manager.GetSessions() {
+		userID := transport.GetUserId(session.GetParams())
+		if serverSession, ok := session.(*transport.Session); ok && userID == "" {
+			userID = serverSession.GetUserID()
+		}
+		subscribes := []string{}
+		if userID != "" {
+			servers, err := a.cfg.GetUserSubscribes(userID)
+			if err != nil && !errors.Is(err, config.ErrNotFound) {
+				a.logger.Warn("Failed to get user subscriptions", zap.String("userID", userID), zap.Error(err))
+			}
+			subscribes = append(subscribes, servers...)
+		}
+		sessions = append(sessions, AdminSession{
+			SessionID:         session.GetID(),
+			UserID:            userID,
+			Status:            session.GetStatus().String(),
+			CreatedAt:         session.GetCreatedAt(),
+			LastActivity:      session.GetLastActivity(),
+			SubscribedServers: subscribes,
+		})
+	}
+	sort.Slice(sessions, func(i, j int) bool { return sessions[i].CreatedAt.Before(sessions[j].CreatedAt) })
+	writeAdminJSON(w, http.StatusOK, sessions)
+}
+
+func (a *AdminAPI) closeSession(w http.ResponseWriter, r *http.Request) {
+	id := r.PathValue("id")
+	if _, err := a.manager.GetSession(id); err != nil {
+		writeAdminError(w, http.StatusNotFound, err.Error())
+		return
+	}
+	a.manager.CloseSession(id)
+	a.logger.Info("Closed session via admin API", zap.String("sessionID", id))
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (a *AdminAPI) listBackends(w http.ResponseWriter, r *http.Request) {
+	backends := make(map[string][]AdminBackendSession)
+	for _, clientSession := range a.manager.GetSessions() {
+		backendSessions, _, _ := gwCapabilities.LoadBackendSessions(clientSession.GetParams())
+		for _, backendSession := range backendSessions {
+			if backendSession == nil || backendSession.Backend == nil {
+				continue
+			}
+			slug := backendSession.Backend.Slug
+			backends[slug] = append(backends[slug], AdminBackendSession{
+				ClientSessionID:  clientSession.GetID(),
+				BackendSessionID: backendSession.GetID(),
+				Status:           backendSession.GetStatus().String(),
+				LastActivity:     backendSession.GetLastActivity(),
+			})
+		}
+	}
+	writeAdminJSON(w, http.StatusOK, backends)
+}
+
+func (a *AdminAPI) metricsSummary(w http.ResponseWriter, r *http.Request) {
+	if a.metrics == nil {
+		writeAdminError(w, http.StatusNotFound, "metrics are not collected")
+		return
+	}
+	summary, err := a.metrics.Summary()
+	if err != nil {
+		writeAdminError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	writeAdminJSON(w, http.StatusOK, summary)
+}
+
+func writeAdminJSON(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(body)
+}
+
+func writeAdminError(w http.ResponseWriter, status int, message string) {
+	writeAdminJSON(w, status, map[string]string{"error": message})
+}
+
+// WithAdminAPI serves the AdminAPI under path, which must start and end with '/'
+// (DefaultAdminAPIPath is the usual choice). It also collects the metrics of the summary
+// endpoint, as WithMetricsEndpoint does, without serving them in the Prometheus format.
+func WithAdminAPI(path string) NodeOption {
+	return func(node *Node) error {
+		if !strings.HasPrefix(path, "/") || !strings.HasSuffix(path, "/") {
+			return fmt.Errorf("admin API path must start and end with '/', got %q", path)
+		}
+		node.adminPath = path
+		node.enableMetrics()
+		return nil
+	}
+}
+
+// registerAdminHandler serves the admin API on mux if WithAdminAPI was given.
+func (n *Node) registerAdminHandler(mux *http.ServeMux) {
+	if n.adminPath == "" {
+		return
+	}
+	n.logger.Info("Registering admin API", zap.String("path", n.adminPath))
+	api := NewAdminAPI(n.logger, n.cfg, n.sessionManager, n.metrics)
+	mux.Handle(n.adminPath, http.StripPrefix(strings.TrimSuffix(n.adminPath, "/"), api))
+}
@@ -0,0 +1,173 @@
+package gateway
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	gwCapabilities "github.com/gate4ai/gate4ai/gateway/capability"
+	"github.com/gate4ai/gate4ai/gateway/clients/mcpClient"
+	"github.com/gate4ai/gate4ai/server/metrics"
+	"github.com/gate4ai/gate4ai/server/transport"
+	"github.com/gate4ai/gate4ai/shared/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+const testAdminToken = "admin-secret"
+
+func freePort(t *testing.T) int {
+	listener, err := net.Listen("tcp", "localhost:0")
+	require.NoError(t, err)
+	defer listener.Close()
+	return listener.Addr().(*net.TCPAddr).Port
+}
+
+func waitForPort(t *testing.T, port int) {
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		conn, err := net.DialTimeout("tcp", fmt.Sprintf("localhost:%d", port), 100*time.Millisecond)
+		if err == nil {
+			conn.Close()
+			return
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+	t.Fatalf("Listener on port %d did not start in time", port)
+}
+
+// startAdminGateway starts a gateway serving the admin API on DefaultAdminAPIPath and returns
+// the node and the URL of the API.
+func startAdminGateway(t *testing.T, cfg *config.InternalConfig) (*Node, string) {
+	t.Helper()
+	ctx, cancel := context.WithCancel(context.Background())
+	port := freePort(t)
+	node, err := Start(ctx, zap.NewNop(), cfg, fmt.Sprintf("localhost:%d", port), WithAdminAPI(DefaultAdminAPIPath))
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		cancel()
+		node.WaitForShutdown(5 * time.Second)
+	})
+	waitForPort(t, port)
+	return node, fmt.Sprintf("http://localhost:%d%s", port, DefaultAdminAPIPath)
+}
+
+func newAdminTestConfig() *config.InternalConfig {
+	cfg := config.NewInternalConfig()
+	cfg.FrontendAddressValue = ""
+	cfg.AdminTokenValue = testAdminToken
+	cfg.UserSubscribes["user1"] = []string{"weather", "news"}
+	return cfg
+}
+
+// adminDo sends an admin API request and decodes the JSON response body into out, if given.
+func adminDo(t *testing.T, method, url, token string, out interface{}) int {
+	t.Helper()
+	req, err := http.NewRequest(method, url, nil)
+	require.NoError(t, err)
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	if out != nil && resp.StatusCode == http.StatusOK {
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(out))
+	}
+	return resp.StatusCode
+}
+
+func TestAdminAPIRequiresToken(t *testing.T) {
+	_, adminURL := startAdminGateway(t, newAdminTestConfig())
+
+	for _, path := range []string{"sessions", "backends", "metrics/summary"} {
+		assert.Equal(t, http.StatusUnauthorized, adminDo(t, http.MethodGet, adminURL+path, "", nil), path)
+		assert.Equal(t, http.StatusUnauthorized, adminDo(t, http.MethodGet, adminURL+path, "wrong", nil), path)
+	}
+	assert.Equal(t, http.StatusUnauthorized, adminDo(t, http.MethodDelete, adminURL+"sessions/any", "wrong", nil))
+}
+
+func TestAdminAPIDisabledWithoutToken(t *testing.T) {
+	cfg := newAdminTestConfig()
+	cfg.AdminTokenValue = ""
+	_, adminURL := startAdminGateway(t, cfg)
+
+	assert.Equal(t, http.StatusForbidden, adminDo(t, http.MethodGet, adminURL+"sessions", "", nil))
+	assert.Equal(t, http.StatusForbidden, adminDo(t, http.MethodGet, adminURL+"sessions", "anything", nil))
+}
+
+func TestAdminAPISessions(t *testing.T) {
+	node, adminURL := startAdminGateway(t, newAdminTestConfig())
+	userSession := node.sessionManager.CreateSession("user1", "", &sync.Map{})
+	anonymousSession := node.sessionManager.CreateSession("", "", &sync.Map{})
+
+	var sessions []AdminSession
+	require.Equal(t, http.StatusOK, adminDo(t, http.MethodGet, adminURL+"sessions", testAdminToken, &sessions))
+	require.Len(t, sessions, 2)
+	byID := map[string]AdminSession{}
+	for _, session := range sessions {
+		byID[session.SessionID] = session
+	}
+	user := byID[userSession.GetID()]
+	assert.Equal(t, "user1", user.UserID)
+	assert.Equal(t, []string{"weather", "news"}, user.SubscribedServers)
+	assert.WithinDuration(t, userSession.GetCreatedAt(), user.CreatedAt, time.Millisecond)
+	assert.Equal(t, "new", user.Status)
+	anonymous := byID[anonymousSession.GetID()]
+	assert.Empty(t, anonymous.UserID)
+	assert.Empty(t, anonymous.SubscribedServers)
+
+	// Closing removes the session; closing it again finds nothing
+	assert.Equal(t, http.StatusNoContent, adminDo(t, http.MethodDelete, adminURL+"sessions/"+userSession.GetID(), testAdminToken, nil))
+	_, err := node.sessionManager.GetSession(userSession.GetID())
+	assert.ErrorIs(t, err, transport.ErrSessionNotFound)
+	assert.Equal(t, http.StatusNotFound, adminDo(t, http.MethodDelete, adminURL+"sessions/"+userSession.GetID(), testAdminToken, nil))
+
+	require.Equal(t, http.StatusOK, adminDo(t, http.MethodGet, adminURL+"sessions", testAdminToken, &sessions))
+	require.Len(t, sessions, 1)
+	assert.Equal(t, anonymousSession.GetID(), sessions[0].SessionID)
+}
+
+func TestAdminAPIBackends(t *testing.T) {
+	node, adminURL := startAdminGateway(t, newAdminTestConfig())
+	clientSession := node.sessionManager.CreateSession("user1", "", &sync.Map{})
+	backend, err := mcpClient.New("weather", "http://localhost:1/sse", zap.NewNop())
+	require.NoError(t, err)
+	backendSession := backend.NewSession(context.Background())
+	gwCapabilities.SaveBackendSessions(clientSession.GetParams(), []*mcpClient.Session{backendSession})
+
+	var backends map[string][]AdminBackendSession
+	require.Equal(t, http.StatusOK, adminDo(t, http.MethodGet, adminURL+"backends", testAdminToken, &backends))
+	require.Len(t, backends["weather"], 1)
+	assert.Equal(t, clientSession.GetID(), backends["weather"][0].ClientSessionID)
+	assert.Equal(t, backendSession.GetID(), backends["weather"][0].BackendSessionID)
+	assert.Equal(t, backendSession.GetStatus().String(), backends["weather"][0].Status)
+	assert.Len(t, backends, 1)
+}
+
+func TestAdminAPIMetricsSummary(t *testing.T) {
+	node, adminURL := startAdminGateway(t, newAdminTestConfig())
+	node.sessionManager.CreateSession("user1", "", &sync.Map{})
+	node.Metrics().ObserveRequest("tools/call", metrics.StatusOK, time.Millisecond)
+	node.Metrics().ObserveRequest("tools/call", metrics.StatusError, time.Millisecond)
+	node.Metrics().BackendError("weather", "call")
+
+	var summary metrics.Summary
+	require.Equal(t, http.StatusOK, adminDo(t, http.MethodGet, adminURL+"metrics/summary", testAdminToken, &summary))
+	assert.Equal(t, 1, summary.ActiveSessions)
+	assert.Equal(t, metrics.RequestSummary{Total: 2, Errors: 1, ErrorRate: 0.5}, summary.Requests["tools/call"])
+	assert.Equal(t, map[string]int{"weather": 1}, summary.BackendErrors)
+}
+
+func TestWithAdminAPIValidation(t *testing.T) {
+	for _, path := range []string{"admin/", "/admin", ""} {
+		_, err := New(zap.NewNop(), config.NewInternalConfig(), WithAdminAPI(path))
+		assert.Error(t, err, path)
+	}
+}
@@ -0,0 +1,67 @@
+package capability
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net/http"
+	"os"
+
+	"github.com/gate4ai/gate4ai/shared/config"
+)
+
+// backendHTTPClient returns the HTTP client for the backend: http.DefaultClient, or a client
+// with the backend's TLS settings. Clients are cached per settings, so sessions to the same
+// backend share their connections.
+func (c *GatewayCapability) backendHTTPClient(backend *config.Backend) (*http.Client, error) {
+	if backend.TLS == nil {
+		return http.DefaultClient, nil
+	}
+	c.tlsClientsMu.Lock()
+	defer c.tlsClientsMu.Unlock()
+	if httpClient, ok := c.tlsClients[*backend.TLS]; ok {
+		return httpClient, nil
+	}
+	tlsConfig, err := newBackendTLSConfig(backend.TLS)
+	if err != nil {
+		return nil, err
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = tlsConfig
+	httpClient := &http.Client{Transport: transport}
+	if c.tlsClients == nil {
+		c.tlsClients = make(map[config.BackendTLSConfig]*http.Client)
+	}
+	c.tlsClients[*backend.TLS] = httpClient
+	return httpClient, nil
+}
+
+// newBackendTLSConfig loads the client certificate and CAs of cfg.
+func newBackendTLSConfig(cfg *config.BackendTLSConfig) (*tls.Config, error) {
+	tlsConfig := &tls.Config{
+		MinVersion:         tls.VersionTLS12,
+		InsecureSkipVerify: cfg.InsecureSkipVerify,
+	}
+	if cfg.CertFile != "" || cfg.KeyFile != "" {
+		if cfg.CertFile == "" || cfg.KeyFile == "" {
+			return nil, fmt.Errorf("backend TLS needs both a certificate and a key file")
+		}
+		cert, err := tls.LoadX509KeyPair(cfg.CertFile, cfg.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load backend client certificate: %w", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+	if cfg.CAFile != "" {
+		caPEM, err := os.ReadFile(cfg.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read backend CA file: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(caPEM) {
+			return nil, fmt.Errorf("no certificates found in backend CA file %s", cfg.CAFile)
+		}
+		tlsConfig.RootCAs = pool
+	}
+	return tlsConfig, nil
+}
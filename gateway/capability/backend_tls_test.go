@@ -0,0 +1,136 @@
+package capability
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/gate4ai/gate4ai/shared/config"
+	"go.uber.org/zap"
+)
+
+func writePEM(t *testing.T, path, blockType string, der []byte) {
+	t.Helper()
+	if err := os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: der}), 0o600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// newClientCertificate writes a CA-signed client certificate and its key to dir and returns the CA.
+func newClientCertificate(t *testing.T, dir string) (certFile, keyFile string, ca *x509.Certificate) {
+	t.Helper()
+	caKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	caTemplate := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "test CA"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+	}
+	caDER, err := x509.CreateCertificate(rand.Reader, caTemplate, caTemplate, &caKey.PublicKey, caKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ca, err = x509.ParseCertificate(caDER); err != nil {
+		t.Fatal(err)
+	}
+
+	clientKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	clientTemplate := &x509.Certificate{
+		SerialNumber: big.NewInt(2),
+		Subject:      pkix.Name{CommonName: "gateway"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	clientDER, err := x509.CreateCertificate(rand.Reader, clientTemplate, ca, &clientKey.PublicKey, caKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(clientKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	certFile, keyFile = filepath.Join(dir, "client.pem"), filepath.Join(dir, "client-key.pem")
+	writePEM(t, certFile, "CERTIFICATE", clientDER)
+	writePEM(t, keyFile, "EC PRIVATE KEY", keyDER)
+	return certFile, keyFile, ca
+}
+
+func TestBackendHTTPClientMutualTLS(t *testing.T) {
+	dir := t.TempDir()
+	certFile, keyFile, clientCA := newClientCertificate(t, dir)
+
+	backend := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if len(r.TLS.PeerCertificates) == 0 {
+			http.Error(w, "no client certificate", http.StatusUnauthorized)
+			return
+		}
+		_, _ = w.Write([]byte(r.TLS.PeerCertificates[0].Subject.CommonName))
+	}))
+	clientCAs := x509.NewCertPool()
+	clientCAs.AddCert(clientCA)
+	backend.TLS = &tls.Config{ClientAuth: tls.RequireAndVerifyClientCert, ClientCAs: clientCAs}
+	backend.StartTLS()
+	defer backend.Close()
+	caFile := filepath.Join(dir, "backend-ca.pem")
+	writePEM(t, caFile, "CERTIFICATE", backend.Certificate().Raw)
+
+	c := NewGatewayCapability(zap.NewNop(), config.NewMockConfig())
+
+	// The client certificate completes the handshake
+	tlsConfig := &config.BackendTLSConfig{CertFile: certFile, KeyFile: keyFile, CAFile: caFile}
+	httpClient, err := c.backendHTTPClient(&config.Backend{URL: backend.URL, TLS: tlsConfig})
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err := httpClient.Get(backend.URL)
+	if err != nil {
+		t.Fatalf("mTLS request failed: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("Expected 200, got %d", resp.StatusCode)
+	}
+	if again, _ := c.backendHTTPClient(&config.Backend{URL: backend.URL, TLS: &config.BackendTLSConfig{CertFile: certFile, KeyFile: keyFile, CAFile: caFile}}); again != httpClient {
+		t.Errorf("HTTP client was not reused for the same TLS settings")
+	}
+
+	// Without a client certificate the backend refuses the handshake
+	httpClient, err = c.backendHTTPClient(&config.Backend{URL: backend.URL, TLS: &config.BackendTLSConfig{CAFile: caFile}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp, err := httpClient.Get(backend.URL); err == nil {
+		resp.Body.Close()
+		t.Errorf("Request without a client certificate succeeded")
+	}
+
+	// Backends without TLS settings use the default client
+	if httpClient, err := c.backendHTTPClient(&config.Backend{URL: "http://plain"}); err != nil || httpClient != http.DefaultClient {
+		t.Errorf("Expected http.DefaultClient, got %v, %v", httpClient, err)
+	}
+	if _, err := c.backendHTTPClient(&config.Backend{TLS: &config.BackendTLSConfig{CertFile: certFile}}); err == nil {
+		t.Errorf("Expected an error for a certificate without key")
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"slices"
 	"strings"
 	"sync"
 	"time"
@@ -16,11 +17,15 @@ import (
 	"github.com/gate4ai/gate4ai/shared"
 	"github.com/gate4ai/gate4ai/shared/config"
 	schema "github.com/gate4ai/gate4ai/shared/mcp/2025/schema"
+	"go.opentelemetry.io/otel/trace"
 	"go.uber.org/zap"
 )
 
 const defaultCacheExpiration = 5 * time.Second
 
+// backendSessionOpenTimeout bounds each attempt to open a backend session.
+const backendSessionOpenTimeout = 10 * time.Second
+
 // ServerConnection represents a connection to a remote SSE server
 type ServerConnection struct {
 	URL       string
@@ -38,10 +43,32 @@ type GatewayCapability struct {
 	refreshRate  time.Duration
 	userSessions map[string]*transport.Session // UserID -> mcp session
 	config       config.IConfig
+	// Max backend sessions per (client session, server) used by tools/call, set by WithBackendPoolSize
+	backendPoolSize int
+	// Normalizes non-standard tools/call results of backends, set by WithResultNormalizer
+	resultNormalizer ResultNormalizer
+	// Receives tools/call measurements, set by WithMetrics
+	metrics MetricsRecorder
+	// Tracer provider for backend call spans, set by WithTracerProvider; nil uses the global provider
+	tracerProvider trace.TracerProvider
+	// Circuit breaker settings, set by WithCircuitBreaker and WithCircuitBreakerWindow; threshold <= 0 disables it
+	breakerThreshold int
+	breakerTimeout   time.Duration
+	breakerWindow    time.Duration
+	breakersMu       sync.Mutex
+	breakers         map[string]*circuitBreaker // serverSlug -> circuit breaker
+	// Backend URL changes of config reloads, see reload.go
+	backendChangesMu sync.Mutex
+	backendChanges   map[string]time.Time // serverSlug -> time of the last URL change
+	// HTTP clients of backends with TLS settings, see backend_tls.go
+	tlsClientsMu sync.Mutex
+	tlsClients   map[config.BackendTLSConfig]*http.Client
+	// Retries of creating new backend sessions, set by WithBackendRetryPolicy
+	retryPolicy RetryPolicy
 }
 
 // NewGatewayCapability creates a new gateway capability
-func NewGatewayCapability(logger *zap.Logger, cfg config.IConfig) *GatewayCapability {
+func NewGatewayCapability(logger *zap.Logger, cfg config.IConfig, options ...GatewayOption) *GatewayCapability {
 	ctx, cancel := context.WithCancel(context.Background())
 	cap := &GatewayCapability{
 		logger:       logger,
@@ -51,6 +78,14 @@ func NewGatewayCapability(logger *zap.Logger, cfg config.IConfig) *GatewayCapabi
 		userSessions: make(map[string]*transport.Session),
 		config:       cfg,
 	}
+	for _, option := range options {
+		option(cap)
+	}
+	if notifier, ok := cfg.(config.ChangeNotifier); ok {
+		events := make(chan config.ConfigChangeEvent, 16)
+		notifier.Subscribe(events)
+		go cap.listenConfigChanges(events)
+	}
 	return cap
 }
 
@@ -65,6 +100,7 @@ func (c *GatewayCapability) GetHandlers() map[string]func(*shared.Message) (inte
 	handlers["resources/unsubscribe"] = c.gw_resources_unsubscribe
 	handlers["tools/list"] = c.gw_tools_list
 	handlers["tools/call"] = c.gw_tools_call
+	handlers["roots/list"] = c.gw_roots_list
 	return handlers
 }
 
@@ -148,12 +184,22 @@ func (c *GatewayCapability) getMergedHeaders(clientSession shared.ISession, serv
 	return merged
 }
 
-// newBackendSession creates a new backend session for the given server
-func (c *GatewayCapability) newBackendSession(serverSlug string, clientSession shared.ISession, logger *zap.Logger) *client.Session {
+// newBackendSession creates and opens a new backend session for the given server. Each attempt
+// of the retry policy probes the backend health and opens a fresh session; a backend still
+// failing after the last attempt is returned as an unopened session marked unavailable. ctx
+// bounds the attempts and the waits between them. An error is returned when no session can be
+// created at all.
+func (c *GatewayCapability) newBackendSession(ctx context.Context, serverSlug string, clientSession shared.ISession, logger *zap.Logger) (*client.Session, error) {
 	backend, err := c.config.GetBackendBySlug(serverSlug)
 	if err != nil {
 		logger.Error("Failed to get backend server", zap.String("serverSlug", serverSlug), zap.Error(err))
-		return nil
+		return nil, fmt.Errorf("failed to get backend server: %w", err)
+	}
+
+	httpClient, err := c.backendHTTPClient(backend)
+	if err != nil {
+		logger.Error("Failed to configure backend TLS", zap.String("serverSlug", serverSlug), zap.Error(err))
+		return nil, err
 	}
 
 	// Get merged headers
@@ -162,25 +208,68 @@ func (c *GatewayCapability) newBackendSession(serverSlug string, clientSession s
 	backendServer, err := client.New(serverSlug, backend.URL, logger)
 	if err != nil {
 		logger.Error("Failed to create backend client", zap.String("serverSlug", serverSlug), zap.Error(err))
-		return nil
+		return nil, err
 	}
 
 	// --- Use functional options to create session ---
-	// Start with default HTTP client
+	// Start with the backend's HTTP client, which carries its TLS settings
 	options := []client.SessionOption{
-		client.WithHTTPClient(http.DefaultClient),
+		client.WithHTTPClient(httpClient),
 	}
 	// Add merged headers
 	options = append(options, client.WithHeaders(mergedHeaders))
 
-	newBackendSession := backendServer.NewSession(c.ctx, options...)
-	SaveServerSlug(newBackendSession.GetParams(), serverSlug)
-	// clientSession is an ISession, GetParams() is available.
-	// We need to pass the clientSession itself for callbacks later.
-	SaveClientSession(newBackendSession.GetParams(), clientSession)
-	newBackendSession.SubscribeOnResourceUpdated(c.gw_resources_notification_updated)
+	newSession := func() *client.Session {
+		// The session lives as long as the gateway, not as long as ctx
+		newBackendSession := backendServer.NewSession(c.ctx, options...)
+		SaveServerSlug(newBackendSession.GetParams(), serverSlug)
+		// clientSession is an ISession, GetParams() is available.
+		// We need to pass the clientSession itself for callbacks later.
+		SaveClientSession(newBackendSession.GetParams(), clientSession)
+		newBackendSession.SubscribeOnResourceUpdated(c.gw_resources_notification_updated)
+		newBackendSession.SubscribeOnRootsAdded(c.gw_roots_notification_added)
+		return newBackendSession
+	}
+
+	// Probe and open before the first request is routed; an unavailable backend is refused until the session is recreated
+	var session *client.Session
+	attempt := func() error {
+		if err := probeBackendHealth(ctx, httpClient, backend.URL, backend.HealthPath); err != nil {
+			return err
+		}
+		session = newSession()
+		if err := openBackendSession(ctx, session); err != nil {
+			session.Close()
+			session = nil
+			return err
+		}
+		return nil
+	}
+	onRetry := func(retry int, delay time.Duration, err error) {
+		logger.Info("Backend session failed, retrying", zap.String("serverSlug", serverSlug), zap.Int("retry", retry), zap.Duration("delay", delay), zap.Error(err))
+	}
+	if err := c.retryPolicy.do(ctx, attempt, onRetry); err != nil {
+		logger.Warn("Backend session failed, marking the backend unavailable", zap.String("serverSlug", serverSlug), zap.Error(err))
+		session = newSession()
+		SaveBackendUnavailable(session.GetParams(), err)
+	}
 
-	return newBackendSession
+	return session, nil
+}
+
+// openBackendSession opens session and waits for its initialization, at most backendSessionOpenTimeout.
+func openBackendSession(ctx context.Context, session *client.Session) error {
+	ctx, cancel := context.WithTimeout(ctx, backendSessionOpenTimeout)
+	defer cancel()
+	select {
+	case err := <-session.Open():
+		if err != nil {
+			return fmt.Errorf("failed to open backend session: %w", err)
+		}
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("failed to open backend session: %w", ctx.Err())
+	}
 }
 
 // getBackendSession returns an existing backend session for the given server or creates a new one
@@ -198,6 +287,9 @@ func (c *GatewayCapability) getBackendSession(clientSession shared.ISession, ser
 			return session, nil
 		}
 	}
+	if reason := GetBackendSessionError(clientSession.GetParams(), serverSlug); reason != nil {
+		return nil, errBackendSessionFailed(serverSlug, reason)
+	}
 	return nil, fmt.Errorf("backend session not found for server: %s", serverSlug)
 }
 
@@ -208,7 +300,7 @@ func (c *GatewayCapability) getBackendSessions(clientSession shared.ISession) ([
 	params := clientSession.GetParams()
 
 	backendSessions, timestamp, found := LoadBackendSessions(params)
-	if found && time.Since(timestamp) < defaultCacheExpiration {
+	if found && time.Since(timestamp) < defaultCacheExpiration && !c.anyBackendChangedSince(timestamp) {
 		validSessions := make([]*client.Session, 0, len(backendSessions))
 		for _, s := range backendSessions {
 			if s != nil {
@@ -238,24 +330,35 @@ func (c *GatewayCapability) getBackendSessions(clientSession shared.ISession) ([
 		return nil, err
 	}
 	logger.Debug("User subscribed servers", zap.String("userID", userID), zap.Strings("servers", userServers))
+	userServers = c.limitUserSubscriptions(userID, userServers)
 
 	var currentBackendSessions []*client.Session
 	var wg sync.WaitGroup
 	sessionChan := make(chan *client.Session, len(userServers))
+	var sessionErrorsMu sync.Mutex
+	sessionErrors := make(map[string]error) // serverSlug -> why its session could not be created
 
 	for _, serverSlug := range userServers {
 		wg.Add(1)
 		go func(serverSlug string) {
 			defer wg.Done()
 			var sess *client.Session
-			if session, exists := existingSessions[serverSlug]; exists && session != nil {
+			// Unavailable sessions are not reused, so the backend is probed again, and neither are
+			// sessions created before the backend URL changed
+			if session, exists := existingSessions[serverSlug]; exists && session != nil && GetBackendUnavailable(session.GetParams()) == nil && !c.backendSessionOutdated(session) {
 				// Reuse existing. Headers won't update unless session is recreated.
 				sess = session
 				logger.Debug("Reusing existing backend session", zap.String("serverSlug", serverSlug))
 				delete(existingSessions, serverSlug)
 			} else {
 				logger.Debug("Creating new backend session", zap.String("serverSlug", serverSlug))
-				sess = c.newBackendSession(serverSlug, clientSession, logger.With(zap.String("serverSlug", serverSlug))) // Gets headers on creation
+				var err error
+				sess, err = c.newBackendSession(c.ctx, serverSlug, clientSession, logger.With(zap.String("serverSlug", serverSlug))) // Gets headers on creation
+				if err != nil {
+					sessionErrorsMu.Lock()
+					sessionErrors[serverSlug] = err
+					sessionErrorsMu.Unlock()
+				}
 			}
 			if sess != nil {
 				sessionChan <- sess
@@ -280,6 +383,7 @@ func (c *GatewayCapability) getBackendSessions(clientSession shared.ISession) ([
 	}
 
 	SaveBackendSessions(params, currentBackendSessions)
+	SaveBackendSessionErrors(params, sessionErrors)
 
 	serverSlugs := make([]string, 0, len(currentBackendSessions))
 	for _, s := range currentBackendSessions {
@@ -292,6 +396,33 @@ func (c *GatewayCapability) getBackendSessions(clientSession shared.ISession) ([
 	return currentBackendSessions, nil
 }
 
+// limitUserSubscriptions applies config.IConfig.MaxSubscriptionsPerUser to the servers of userID.
+// Over the quota only the first servers in alphabetical order of their slugs are kept, so the
+// same ones stay active from one request to the next.
+func (c *GatewayCapability) limitUserSubscriptions(userID string, userServers []string) []string {
+	maxSubscriptions, err := c.config.MaxSubscriptionsPerUser()
+	if err != nil {
+		c.logger.Error("Failed to get subscription quota, not enforcing it", zap.Error(err))
+		return userServers
+	}
+	if maxSubscriptions <= 0 || len(userServers) <= maxSubscriptions {
+		return userServers
+	}
+	sorted := slices.Clone(userServers)
+	slices.Sort(sorted)
+	c.logger.Warn("User exceeds the subscription quota, ignoring the servers over it",
+		zap.String("userID", userID),
+		zap.Int("maxSubscriptions", maxSubscriptions),
+		zap.Strings("ignoredServers", sorted[maxSubscriptions:]))
+	return sorted[:maxSubscriptions]
+}
+
+// backendSessionOutdated reports whether the backend URL changed after the session was created.
+func (c *GatewayCapability) backendSessionOutdated(session *client.Session) bool {
+	serverSlug, created, ok := GetServerSlug(session.GetParams())
+	return ok && c.backendChangedSince(serverSlug, created)
+}
+
 // fetchAndCombineFromBackends - No changes needed here, it just calls the fetchFunc which uses the session (with its headers).
 func fetchAndCombineFromBackends[T any](
 	c *GatewayCapability,
@@ -383,6 +514,8 @@ func fetchAndCombineFromBackends[T any](
 				serverSlug = concreteItem.serverSlug
 			case *prompt:
 				serverSlug = concreteItem.serverSlug
+			case *rootWithServerInfo:
+				serverSlug = concreteItem.serverSlug
 			default:
 				logger.Error("Could not determine serverID for item during duplicate modification", zap.String("key", key), zap.Any("type", fmt.Sprintf("%T", item)))
 			}
@@ -451,6 +584,9 @@ func (c *GatewayCapability) SetCapabilities(s *schema.ServerCapabilities) {
 	s.Tools = &schema.Capability{ListChanged: true}
 }
 
+// SetEventBus is part of the IServerCapability interface; the gateway publishes no events.
+func (c *GatewayCapability) SetEventBus(bus shared.EventBus) {}
+
 // Helper function to associate client session with backend session parameters
 func SaveClientSession(sessionParams *sync.Map, clientSession shared.ISession) {
 	sessionParams.Store(clientSessionsKey, &SavedValue{
@@ -0,0 +1,196 @@
+package capability
+
+import (
+	"math"
+	"sync"
+	"time"
+
+	"github.com/gate4ai/gate4ai/shared"
+)
+
+const defaultCircuitBreakerWindow = time.Minute
+
+// CircuitState is the state of the circuit breaker of one backend.
+type CircuitState int
+
+const (
+	CircuitClosed   CircuitState = iota // Calls go to the backend
+	CircuitOpen                         // Calls are refused until the open timeout has passed
+	CircuitHalfOpen                     // One probe call decides whether the circuit closes again
+)
+
+func (s CircuitState) String() string {
+	switch s {
+	case CircuitClosed:
+		return "closed"
+	case CircuitOpen:
+		return "open"
+	case CircuitHalfOpen:
+		return "half-open"
+	default:
+		return "unknown"
+	}
+}
+
+// WithCircuitBreaker stops routing tools/call to a backend after threshold consecutive connection
+// or RPC errors within the failure window (see WithCircuitBreakerWindow). While the circuit is open,
+// calls fail fast with shared.JSONRPCErrorCircuitOpen; after timeout one probe call is let through,
+// and its success closes the circuit. Tool errors reported by the backend are not failures.
+// threshold <= 0 disables the breaker.
+func WithCircuitBreaker(threshold int, timeout time.Duration) GatewayOption {
+	return func(c *GatewayCapability) {
+		c.breakerThreshold = threshold
+		c.breakerTimeout = timeout
+	}
+}
+
+// WithCircuitBreakerWindow sets the window the threshold failures must fall into; a failure after
+// the window has passed starts a new count. Defaults to one minute.
+func WithCircuitBreakerWindow(window time.Duration) GatewayOption {
+	return func(c *GatewayCapability) {
+		c.breakerWindow = window
+	}
+}
+
+// CircuitState returns the circuit breaker state of a backend. Backends without calls yet are closed.
+func (c *GatewayCapability) CircuitState(serverSlug string) CircuitState {
+	c.breakersMu.Lock()
+	breaker, ok := c.breakers[serverSlug]
+	c.breakersMu.Unlock()
+	if !ok {
+		return CircuitClosed
+	}
+	return breaker.currentState(time.Now())
+}
+
+// acquireCircuit checks the breaker of a backend before a call. The returned done func must be
+// called with the outcome of the call; an open circuit returns an error instead.
+func (c *GatewayCapability) acquireCircuit(serverSlug string) (done func(failed bool), err error) {
+	if c.breakerThreshold <= 0 {
+		return func(bool) {}, nil
+	}
+	c.breakersMu.Lock()
+	if c.breakers == nil {
+		c.breakers = make(map[string]*circuitBreaker)
+	}
+	breaker, ok := c.breakers[serverSlug]
+	if !ok {
+		window := c.breakerWindow
+		if window <= 0 {
+			window = defaultCircuitBreakerWindow
+		}
+		breaker = newCircuitBreaker(c.breakerThreshold, c.breakerTimeout, window)
+		c.breakers[serverSlug] = breaker
+	}
+	c.breakersMu.Unlock()
+
+	if retryAfter, ok := breaker.allow(time.Now()); !ok {
+		return nil, errCircuitOpen(serverSlug, retryAfter)
+	}
+	return func(failed bool) {
+		from, to := breaker.record(failed, time.Now())
+		if from != to {
+			c.logger.Sugar().Infow("Backend circuit breaker changed state", "serverSlug", serverSlug, "from", from.String(), "to", to.String())
+		}
+	}, nil
+}
+
+// errCircuitOpen is returned to clients while the circuit breaker of the backend is open.
+func errCircuitOpen(serverSlug string, retryAfter time.Duration) *shared.JSONRPCError {
+	return &shared.JSONRPCError{
+		Code:    shared.JSONRPCErrorCircuitOpen,
+		Message: "Backend circuit open",
+		Data: map[string]interface{}{
+			"serverSlug": serverSlug,
+			"retryAfter": int(math.Ceil(retryAfter.Seconds())),
+		},
+	}
+}
+
+// circuitBreaker tracks the consecutive failures of one backend.
+type circuitBreaker struct {
+	threshold int
+	timeout   time.Duration
+	window    time.Duration
+
+	mu            sync.Mutex
+	state         CircuitState
+	failures      int
+	firstFailure  time.Time // Start of the current run of failures
+	openedAt      time.Time
+	probeInFlight bool // In HalfOpen, a probe call is running and other calls are refused
+}
+
+func newCircuitBreaker(threshold int, timeout time.Duration, window time.Duration) *circuitBreaker {
+	return &circuitBreaker{threshold: threshold, timeout: timeout, window: window}
+}
+
+// currentState reports Open as HalfOpen once the timeout has passed.
+func (b *circuitBreaker) currentState(now time.Time) CircuitState {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.state == CircuitOpen && !now.Before(b.openedAt.Add(b.timeout)) {
+		return CircuitHalfOpen
+	}
+	return b.state
+}
+
+// allow reports whether a call may go to the backend, or how long the circuit stays open.
+// After the open timeout, the first caller becomes the HalfOpen probe.
+func (b *circuitBreaker) allow(now time.Time) (time.Duration, bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	switch b.state {
+	case CircuitOpen:
+		if reopen := b.openedAt.Add(b.timeout); now.Before(reopen) {
+			return reopen.Sub(now), false
+		}
+		b.state = CircuitHalfOpen
+		b.probeInFlight = true
+		return 0, true
+	case CircuitHalfOpen:
+		if b.probeInFlight {
+			return b.timeout, false
+		}
+		b.probeInFlight = true
+		return 0, true
+	default:
+		return 0, true
+	}
+}
+
+// record applies the outcome of an allowed call and returns the state before and after it.
+func (b *circuitBreaker) record(failed bool, now time.Time) (from, to CircuitState) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	from = b.state
+	if !failed {
+		b.state = CircuitClosed
+		b.failures = 0
+		b.probeInFlight = false
+		return from, b.state
+	}
+
+	switch b.state {
+	case CircuitHalfOpen:
+		// The probe failed, wait another timeout
+		b.open(now)
+	case CircuitClosed:
+		if b.failures == 0 || now.Sub(b.firstFailure) > b.window {
+			b.failures = 0
+			b.firstFailure = now
+		}
+		b.failures++
+		if b.failures >= b.threshold {
+			b.open(now)
+		}
+	}
+	return from, b.state
+}
+
+func (b *circuitBreaker) open(now time.Time) {
+	b.state = CircuitOpen
+	b.openedAt = now
+	b.failures = 0
+	b.probeInFlight = false
+}
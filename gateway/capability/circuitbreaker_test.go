@@ -0,0 +1,125 @@
+package capability
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/gate4ai/gate4ai/shared"
+	"go.uber.org/zap"
+)
+
+func TestCircuitBreakerTransitions(t *testing.T) {
+	now := time.Now()
+	b := newCircuitBreaker(3, 10*time.Second, time.Minute)
+
+	// Two failures stay below the threshold
+	for i := 0; i < 2; i++ {
+		if _, ok := b.allow(now); !ok {
+			t.Fatalf("Call %d refused by closed circuit", i+1)
+		}
+		b.record(true, now)
+	}
+	if state := b.currentState(now); state != CircuitClosed {
+		t.Fatalf("Expected closed circuit after 2 failures, got %s", state)
+	}
+
+	// The third consecutive failure opens the circuit
+	b.allow(now)
+	if from, to := b.record(true, now); from != CircuitClosed || to != CircuitOpen {
+		t.Fatalf("Expected closed -> open, got %s -> %s", from, to)
+	}
+	retryAfter, ok := b.allow(now.Add(4 * time.Second))
+	if ok {
+		t.Fatalf("Open circuit let a call through")
+	}
+	if retryAfter != 6*time.Second {
+		t.Errorf("Expected retry after 6s, got %s", retryAfter)
+	}
+
+	// After the timeout one probe goes through, others wait for it
+	probeTime := now.Add(10 * time.Second)
+	if state := b.currentState(probeTime); state != CircuitHalfOpen {
+		t.Fatalf("Expected half-open circuit after timeout, got %s", state)
+	}
+	if _, ok := b.allow(probeTime); !ok {
+		t.Fatalf("Half-open circuit refused the probe")
+	}
+	if _, ok := b.allow(probeTime); ok {
+		t.Fatalf("Half-open circuit let a second call through during the probe")
+	}
+
+	// A failed probe opens the circuit again
+	if from, to := b.record(true, probeTime); from != CircuitHalfOpen || to != CircuitOpen {
+		t.Fatalf("Expected half-open -> open, got %s -> %s", from, to)
+	}
+	if _, ok := b.allow(probeTime.Add(time.Second)); ok {
+		t.Fatalf("Circuit let a call through right after a failed probe")
+	}
+
+	// A successful probe closes it
+	probeTime = probeTime.Add(10 * time.Second)
+	if _, ok := b.allow(probeTime); !ok {
+		t.Fatalf("Half-open circuit refused the second probe")
+	}
+	if from, to := b.record(false, probeTime); from != CircuitHalfOpen || to != CircuitClosed {
+		t.Fatalf("Expected half-open -> closed, got %s -> %s", from, to)
+	}
+	if _, ok := b.allow(probeTime); !ok {
+		t.Fatalf("Closed circuit refused a call")
+	}
+}
+
+func TestCircuitBreakerCountsFailuresWithinWindow(t *testing.T) {
+	now := time.Now()
+	b := newCircuitBreaker(2, 10*time.Second, time.Minute)
+
+	// A success resets the count
+	b.record(true, now)
+	b.record(false, now)
+	b.record(true, now)
+	if state := b.currentState(now); state != CircuitClosed {
+		t.Fatalf("Expected closed circuit after interrupted failures, got %s", state)
+	}
+
+	// A failure after the window starts a new count
+	later := now.Add(2 * time.Minute)
+	b.record(true, later)
+	if state := b.currentState(later); state != CircuitClosed {
+		t.Fatalf("Expected closed circuit for failures outside the window, got %s", state)
+	}
+	b.record(true, later.Add(time.Second))
+	if state := b.currentState(later.Add(time.Second)); state != CircuitOpen {
+		t.Fatalf("Expected open circuit for failures within the window, got %s", state)
+	}
+}
+
+func TestAcquireCircuitFailsFast(t *testing.T) {
+	c := NewGatewayCapability(zap.NewNop(), nil, WithCircuitBreaker(1, time.Minute))
+
+	done, err := c.acquireCircuit("db")
+	if err != nil {
+		t.Fatalf("Closed circuit refused a call: %v", err)
+	}
+	done(true)
+	if state := c.CircuitState("db"); state != CircuitOpen {
+		t.Fatalf("Expected open circuit, got %s", state)
+	}
+
+	_, err = c.acquireCircuit("db")
+	var rpcErr *shared.JSONRPCError
+	if !errors.As(err, &rpcErr) || rpcErr.Code != shared.JSONRPCErrorCircuitOpen {
+		t.Fatalf("Expected circuit open error, got %v", err)
+	}
+	if data, ok := rpcErr.Data.(map[string]interface{}); !ok || data["serverSlug"] != "db" || data["retryAfter"] != 60 {
+		t.Errorf("Unexpected error data: %v", rpcErr.Data)
+	}
+
+	// Other backends have their own breaker
+	if _, err := c.acquireCircuit("api"); err != nil {
+		t.Fatalf("Circuit of another backend refused a call: %v", err)
+	}
+	if state := c.CircuitState("api"); state != CircuitClosed {
+		t.Errorf("Expected closed circuit for another backend, got %s", state)
+	}
+}
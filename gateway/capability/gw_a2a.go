@@ -0,0 +1,334 @@
+package capability
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/gate4ai/gate4ai/gateway/clients/a2aClient"
+	"github.com/gate4ai/gate4ai/server/transport"
+	"github.com/gate4ai/gate4ai/shared"
+	a2aSchema "github.com/gate4ai/gate4ai/shared/a2a/2025-draft/schema"
+	"github.com/gate4ai/gate4ai/shared/config"
+	schema "github.com/gate4ai/gate4ai/shared/mcp/2025/schema"
+	"go.uber.org/zap"
+)
+
+// AgentSlugMetadataKey is the task metadata key a client can use to pick a specific backend agent.
+const AgentSlugMetadataKey = "agentSlug"
+
+const a2aBackendTimeout = 600 * time.Second
+
+// taskBackendTTL is how long the backend of a task is remembered after the last request about
+// it. Finished tasks are kept as well, so their owner can still get them and other users cannot
+// reuse their ID to reach the task history on the backend.
+const taskBackendTTL = 24 * time.Hour
+
+var _ shared.IServerCapability = (*GatewayA2ACapability)(nil)
+
+// GatewayA2ACapability routes A2A task requests to the backend agent selected for the user
+type GatewayA2ACapability struct {
+	logger   *zap.Logger
+	config   config.IConfig
+	handlers map[string]func(*shared.Message) (interface{}, error)
+	// Remember which backend runs a task, and for whom, so follow-up requests of the same user
+	// reach the same agent
+	taskBackendsMu sync.Mutex
+	taskBackends   map[string]*taskBackend // taskID -> backend
+	lastPrune      time.Time
+}
+
+type taskBackend struct {
+	backendURL string
+	userID     string
+	lastUsed   time.Time
+}
+
+// NewGatewayA2ACapability creates a new gateway A2A capability
+func NewGatewayA2ACapability(logger *zap.Logger, cfg config.IConfig) *GatewayA2ACapability {
+	c := &GatewayA2ACapability{
+		logger:       logger.Named("gateway-a2a"),
+		config:       cfg,
+		taskBackends: make(map[string]*taskBackend),
+	}
+	c.handlers = map[string]func(*shared.Message) (interface{}, error){
+		"tasks/send":          c.gw_tasks_send,
+		"tasks/sendSubscribe": c.gw_tasks_sendSubscribe,
+		"tasks/get":           c.gw_tasks_get,
+		"tasks/cancel":        c.gw_tasks_cancel,
+	}
+	return c
+}
+
+func (c *GatewayA2ACapability) GetHandlers() map[string]func(*shared.Message) (interface{}, error) {
+	return c.handlers
+}
+
+// SetCapabilities - A2A capabilities are advertised in the Agent Card, not MCP capabilities.
+func (c *GatewayA2ACapability) SetCapabilities(s *schema.ServerCapabilities) {}
+
+func (c *GatewayA2ACapability) SetEventBus(bus shared.EventBus) {}
+
+// gw_tasks_send forwards a synchronous "tasks/send" request to the user's backend agent.
+func (c *GatewayA2ACapability) gw_tasks_send(inputMsg *shared.Message) (interface{}, error) {
+	logger := c.logger.With(zap.String("sessionID", inputMsg.Session.GetID()), zap.String("method", "tasks/send"))
+
+	var params a2aSchema.TaskSendParams
+	if err := unmarshalA2AParams(inputMsg, &params); err != nil {
+		logger.Error("Failed to unmarshal tasks/send params", zap.Error(err))
+		return nil, err
+	}
+	logger = logger.With(zap.String("taskID", params.ID))
+
+	backendClient, err := c.newBackendClientForUser(inputMsg.Session, params.ID, params.Metadata, logger)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), a2aBackendTimeout)
+	defer cancel()
+	task, err := backendClient.SendTask(ctx, params)
+	if err != nil {
+		logger.Error("Backend agent failed tasks/send", zap.Error(err))
+		return nil, toJSONRPCError(err)
+	}
+	return task, nil
+}
+
+// gw_tasks_sendSubscribe forwards a streaming "tasks/sendSubscribe" request and relays
+// the backend's SSE events to the client session.
+func (c *GatewayA2ACapability) gw_tasks_sendSubscribe(inputMsg *shared.Message) (interface{}, error) {
+	logger := c.logger.With(zap.String("sessionID", inputMsg.Session.GetID()), zap.String("method", "tasks/sendSubscribe"))
+
+	var params a2aSchema.TaskSendParams
+	if err := unmarshalA2AParams(inputMsg, &params); err != nil {
+		logger.Error("Failed to unmarshal tasks/sendSubscribe params", zap.Error(err))
+		return nil, err
+	}
+	logger = logger.With(zap.String("taskID", params.ID))
+
+	userID := transport.GetUserId(inputMsg.Session.GetParams())
+	streaming, err := config.FeatureEnabled(c.config, config.FeatureA2AStreaming, userID, true)
+	if err != nil {
+		logger.Warn("Failed to read feature flag, using default", zap.String("flag", config.FeatureA2AStreaming), zap.Error(err))
+	}
+	if !streaming {
+		logger.Info("Streaming disabled by feature flag", zap.String("userID", userID))
+		return nil, shared.NewJSONRPCError(a2aSchema.NewUnsupportedOperationError("tasks/sendSubscribe"))
+	}
+
+	backendClient, err := c.newBackendClientForUser(inputMsg.Session, params.ID, params.Metadata, logger)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), a2aBackendTimeout)
+	events, err := backendClient.SendTaskSubscribe(ctx, params)
+	if err != nil {
+		cancel()
+		logger.Error("Backend agent failed tasks/sendSubscribe", zap.Error(err))
+		return nil, toJSONRPCError(err)
+	}
+
+	// The backend answers with the initial task state first; use it as our response.
+	initialTask := &a2aSchema.Task{
+		ID:     params.ID,
+		Status: a2aSchema.TaskStatus{State: a2aSchema.TaskStateSubmitted, Timestamp: time.Now()},
+	}
+	var pending *shared.A2AStreamEvent
+	first, ok := <-events
+	if !ok {
+		cancel()
+		return nil, &shared.JSONRPCError{Code: shared.JSONRPCErrorInternal, Message: "Backend agent closed the stream without a response"}
+	}
+	if first.Error != nil {
+		cancel()
+		logger.Error("Backend agent returned an error on stream start", zap.Error(first.Error))
+		return nil, toJSONRPCError(first.Error)
+	}
+	if first.Type == "status" && first.Status != nil && !first.Final {
+		initialTask.Status = first.Status.Status
+	} else {
+		pending = &first
+	}
+
+	go func() {
+		defer cancel()
+		if pending != nil {
+			if err := inputMsg.Session.SendA2AStreamEvent(pending); err != nil {
+				logger.Error("Failed to relay A2A stream event", zap.Error(err))
+				return
+			}
+		}
+		for event := range events {
+			if err := inputMsg.Session.SendA2AStreamEvent(&event); err != nil {
+				logger.Error("Failed to relay A2A stream event", zap.Error(err))
+				return
+			}
+		}
+		logger.Debug("Backend A2A stream finished")
+	}()
+
+	return initialTask, nil
+}
+
+// gw_tasks_get forwards "tasks/get" to the backend that owns the task.
+func (c *GatewayA2ACapability) gw_tasks_get(inputMsg *shared.Message) (interface{}, error) {
+	logger := c.logger.With(zap.String("sessionID", inputMsg.Session.GetID()), zap.String("method", "tasks/get"))
+
+	var params a2aSchema.TaskQueryParams
+	if err := unmarshalA2AParams(inputMsg, &params); err != nil {
+		logger.Error("Failed to unmarshal tasks/get params", zap.Error(err))
+		return nil, err
+	}
+	logger = logger.With(zap.String("taskID", params.ID))
+
+	backendClient, err := c.newBackendClientForTask(inputMsg.Session, params.ID, logger)
+	if err != nil {
+		return nil, err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), a2aBackendTimeout)
+	defer cancel()
+	task, err := backendClient.GetTask(ctx, params)
+	if err != nil {
+		logger.Error("Backend agent failed tasks/get", zap.Error(err))
+		return nil, toJSONRPCError(err)
+	}
+	return task, nil
+}
+
+// gw_tasks_cancel forwards "tasks/cancel" to the backend that owns the task.
+func (c *GatewayA2ACapability) gw_tasks_cancel(inputMsg *shared.Message) (interface{}, error) {
+	logger := c.logger.With(zap.String("sessionID", inputMsg.Session.GetID()), zap.String("method", "tasks/cancel"))
+
+	var params a2aSchema.TaskIdParams
+	if err := unmarshalA2AParams(inputMsg, &params); err != nil {
+		logger.Error("Failed to unmarshal tasks/cancel params", zap.Error(err))
+		return nil, err
+	}
+	logger = logger.With(zap.String("taskID", params.ID))
+
+	backendClient, err := c.newBackendClientForTask(inputMsg.Session, params.ID, logger)
+	if err != nil {
+		return nil, err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), a2aBackendTimeout)
+	defer cancel()
+	task, err := backendClient.CancelTask(ctx, params)
+	if err != nil {
+		logger.Error("Backend agent failed tasks/cancel", zap.Error(err))
+		return nil, toJSONRPCError(err)
+	}
+	return task, nil
+}
+
+// newBackendClientForUser selects the backend agent for the session's user and
+// remembers it as the backend of the task.
+func (c *GatewayA2ACapability) newBackendClientForUser(clientSession shared.ISession, taskID string, metadata *map[string]interface{}, logger *zap.Logger) (*a2aClient.Client, error) {
+	userID := transport.GetUserId(clientSession.GetParams())
+	agentSlug := ""
+	if metadata != nil {
+		if slug, ok := (*metadata)[AgentSlugMetadataKey].(string); ok {
+			agentSlug = slug
+		}
+	}
+
+	backendURL, err := c.config.GetA2ABackendForUser(userID, agentSlug)
+	if err != nil {
+		logger.Warn("No A2A backend available for user", zap.String("userID", userID), zap.String("agentSlug", agentSlug), zap.Error(err))
+		if errors.Is(err, config.ErrNotFound) {
+			return nil, &shared.JSONRPCError{Code: shared.JSONRPCErrorInvalidParams, Message: fmt.Sprintf("no A2A agent available for agentSlug '%s'", agentSlug)}
+		}
+		return nil, &shared.JSONRPCError{Code: shared.JSONRPCErrorInternal, Message: "Failed to resolve A2A backend"}
+	}
+	logger.Debug("Routing A2A task to backend", zap.String("userID", userID), zap.String("agentSlug", agentSlug), zap.String("backendURL", backendURL))
+
+	if err := c.rememberTaskBackend(userID, taskID, backendURL); err != nil {
+		logger.Warn("Task ID is used by another user", zap.String("userID", userID))
+		return nil, err
+	}
+	return c.newBackendClient(backendURL, logger)
+}
+
+// newBackendClientForTask returns a client for the backend that previously received the task
+// of the session's user.
+func (c *GatewayA2ACapability) newBackendClientForTask(clientSession shared.ISession, taskID string, logger *zap.Logger) (*a2aClient.Client, error) {
+	userID := transport.GetUserId(clientSession.GetParams())
+	backendURL, ok := c.taskBackendFor(userID, taskID)
+	if !ok {
+		logger.Warn("No backend known for task of user", zap.String("userID", userID))
+		return nil, shared.NewJSONRPCError(a2aSchema.NewTaskNotFoundError(taskID))
+	}
+	return c.newBackendClient(backendURL, logger)
+}
+
+// rememberTaskBackend records backendURL as the backend of the task of userID. Task IDs of other
+// users' tasks are refused, so a task cannot be taken over by sending to its ID.
+func (c *GatewayA2ACapability) rememberTaskBackend(userID, taskID, backendURL string) error {
+	c.taskBackendsMu.Lock()
+	defer c.taskBackendsMu.Unlock()
+	now := time.Now()
+	c.pruneTaskBackends(now)
+	if existing, ok := c.taskBackends[taskID]; ok && existing.userID != userID && now.Sub(existing.lastUsed) <= taskBackendTTL {
+		return &shared.JSONRPCError{Code: shared.JSONRPCErrorInvalidParams, Message: fmt.Sprintf("task ID '%s' is already in use", taskID)}
+	}
+	c.taskBackends[taskID] = &taskBackend{backendURL: backendURL, userID: userID, lastUsed: now}
+	return nil
+}
+
+// taskBackendFor returns the backend of the task if userID sent it.
+func (c *GatewayA2ACapability) taskBackendFor(userID, taskID string) (string, bool) {
+	c.taskBackendsMu.Lock()
+	defer c.taskBackendsMu.Unlock()
+	entry, ok := c.taskBackends[taskID]
+	if !ok || entry.userID != userID || time.Since(entry.lastUsed) > taskBackendTTL {
+		return "", false
+	}
+	entry.lastUsed = time.Now()
+	return entry.backendURL, true
+}
+
+// pruneTaskBackends drops the entries unused for taskBackendTTL, at most once a minute.
+// The caller holds taskBackendsMu.
+func (c *GatewayA2ACapability) pruneTaskBackends(now time.Time) {
+	if now.Sub(c.lastPrune) < time.Minute {
+		return
+	}
+	c.lastPrune = now
+	for taskID, entry := range c.taskBackends {
+		if now.Sub(entry.lastUsed) > taskBackendTTL {
+			delete(c.taskBackends, taskID)
+		}
+	}
+}
+
+func (c *GatewayA2ACapability) newBackendClient(backendURL string, logger *zap.Logger) (*a2aClient.Client, error) {
+	backendClient, err := a2aClient.New(backendURL, a2aClient.WithLogger(logger), a2aClient.DoNotTrustAgentInfoURL())
+	if err != nil {
+		logger.Error("Failed to create A2A backend client", zap.String("backendURL", backendURL), zap.Error(err))
+		return nil, &shared.JSONRPCError{Code: shared.JSONRPCErrorInternal, Message: "Failed to create A2A backend client"}
+	}
+	return backendClient, nil
+}
+
+func unmarshalA2AParams(inputMsg *shared.Message, target interface{}) error {
+	if inputMsg.Params == nil {
+		return &shared.JSONRPCError{Code: shared.JSONRPCErrorInvalidParams, Message: "missing parameters"}
+	}
+	if err := json.Unmarshal(*inputMsg.Params, target); err != nil {
+		return &shared.JSONRPCError{Code: shared.JSONRPCErrorInvalidParams, Message: err.Error()}
+	}
+	return nil
+}
+
+// toJSONRPCError keeps JSON-RPC errors returned by the backend intact and wraps anything else.
+func toJSONRPCError(err error) *shared.JSONRPCError {
+	var jsonRPCErr *shared.JSONRPCError
+	if errors.As(err, &jsonRPCErr) {
+		return jsonRPCErr
+	}
+	return &shared.JSONRPCError{Code: shared.JSONRPCErrorInternal, Message: err.Error()}
+}
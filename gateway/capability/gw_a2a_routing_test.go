@@ -0,0 +1,62 @@
+package capability
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gate4ai/gate4ai/shared/config"
+	"go.uber.org/zap"
+)
+
+func TestA2ATaskBackendsAreScopedToTheirUser(t *testing.T) {
+	c := NewGatewayA2ACapability(zap.NewNop(), config.NewInternalConfig())
+
+	if err := c.rememberTaskBackend("user1", "task-1", "http://agent1/a2a"); err != nil {
+		t.Fatalf("Failed to remember the backend of a new task: %v", err)
+	}
+	if backendURL, ok := c.taskBackendFor("user1", "task-1"); !ok || backendURL != "http://agent1/a2a" {
+		t.Errorf("Owner got backend %q, %v, want http://agent1/a2a", backendURL, ok)
+	}
+	if _, ok := c.taskBackendFor("user2", "task-1"); ok {
+		t.Error("Another user got the backend of the task")
+	}
+
+	if err := c.rememberTaskBackend("user2", "task-1", "http://agent2/a2a"); err == nil {
+		t.Error("Another user took over the task ID")
+	}
+	if backendURL, _ := c.taskBackendFor("user1", "task-1"); backendURL != "http://agent1/a2a" {
+		t.Errorf("Task was reassigned to %q", backendURL)
+	}
+	if err := c.rememberTaskBackend("user1", "task-1", "http://agent1/a2a"); err != nil {
+		t.Errorf("Owner could not send to its task again: %v", err)
+	}
+}
+
+func TestA2ATaskBackendsExpire(t *testing.T) {
+	c := NewGatewayA2ACapability(zap.NewNop(), config.NewInternalConfig())
+
+	for _, taskID := range []string{"recent", "stale"} {
+		if err := c.rememberTaskBackend("user1", taskID, "http://agent1/a2a"); err != nil {
+			t.Fatalf("Failed to remember the backend of %s: %v", taskID, err)
+		}
+	}
+
+	c.taskBackendsMu.Lock()
+	c.taskBackends["stale"].lastUsed = time.Now().Add(-taskBackendTTL - time.Minute)
+	c.lastPrune = time.Time{}
+	c.taskBackendsMu.Unlock()
+	if _, ok := c.taskBackendFor("user1", "stale"); ok {
+		t.Error("Backend unused for longer than the TTL was returned")
+	}
+	if err := c.rememberTaskBackend("user2", "stale", "http://agent2/a2a"); err != nil {
+		t.Errorf("Expired task ID could not be reused: %v", err)
+	}
+	if err := c.rememberTaskBackend("user2", "recent", "http://agent2/a2a"); err == nil {
+		t.Error("Another user took over a task ID before it expired")
+	}
+	c.taskBackendsMu.Lock()
+	defer c.taskBackendsMu.Unlock()
+	if len(c.taskBackends) != 2 {
+		t.Errorf("Expected the recent and the reused task to remain, got %d entries", len(c.taskBackends))
+	}
+}
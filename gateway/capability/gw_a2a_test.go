@@ -0,0 +1,165 @@
+package capability_test
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/gate4ai/gate4ai/gateway"
+	"github.com/gate4ai/gate4ai/gateway/capability"
+	"github.com/gate4ai/gate4ai/gateway/clients/a2aClient"
+	"github.com/gate4ai/gate4ai/server"
+	"github.com/gate4ai/gate4ai/server/a2a"
+	"github.com/gate4ai/gate4ai/shared"
+	a2aSchema "github.com/gate4ai/gate4ai/shared/a2a/2025-draft/schema"
+	"github.com/gate4ai/gate4ai/shared/config"
+	"github.com/gate4ai/gate4ai/tests"
+	"go.uber.org/zap"
+)
+
+// startNamedAgent runs an A2A server whose agent answers every task with its own name.
+func startNamedAgent(t *testing.T, ctx context.Context, name string) string {
+	port, err := tests.FindAvailablePort()
+	if err != nil {
+		t.Fatalf("Failed to find available port: %v", err)
+	}
+	cfg := config.NewInternalConfig()
+	cfg.AuthorizationTypeValue = config.NotAuthorizedEverywhere
+	cfg.A2AAgentNameValue = name
+	cfg.A2AAgentVersionValue = "1.0.0"
+
+	handler := func(ctx context.Context, task *a2aSchema.Task, updates chan<- a2a.A2AYieldUpdate, logger *zap.Logger) error {
+		updates <- a2a.A2AYieldUpdate{Status: &a2aSchema.TaskStatus{
+			State:   a2aSchema.TaskStateCompleted,
+			Message: &a2aSchema.Message{Role: "agent", Parts: []a2aSchema.Part{{Type: shared.PointerTo("text"), Text: shared.PointerTo(name)}}},
+		}}
+		return nil
+	}
+	_, err = server.Start(ctx, LOGGER.With(zap.String("s", name)), cfg,
+		server.WithA2ACapability(a2a.NewInMemoryTaskStore(), handler),
+		server.WithListenAddr(fmt.Sprintf(":%d", port)))
+	if err != nil {
+		t.Fatalf("Failed to start agent %s: %v", name, err)
+	}
+	waitForPort(t, port)
+	return "http://localhost:" + strconv.Itoa(port) + "/a2a"
+}
+
+// waitForPort blocks until the HTTP listener started in the background accepts connections.
+func waitForPort(t *testing.T, port int) {
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		conn, err := net.DialTimeout("tcp", fmt.Sprintf("localhost:%d", port), 100*time.Millisecond)
+		if err == nil {
+			conn.Close()
+			return
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+	t.Fatalf("Listener on port %d did not start in time", port)
+}
+
+func TestA2ARoutingPerUser(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	agent1URL := startNamedAgent(t, ctx, "agent1")
+	agent2URL := startNamedAgent(t, ctx, "agent2")
+
+	portForGateway, err := tests.FindAvailablePort()
+	if err != nil {
+		t.Fatalf("Failed to find available port: %v", err)
+	}
+	cfgGw := config.NewInternalConfig()
+	cfgGw.A2AAgentNameValue = "gateway"
+	cfgGw.A2AAgentVersionValue = "1.0.0"
+	cfgGw.UserKeyHashes[config.HashAPIKey("key-a2a-user1")] = "a2a-user1"
+	cfgGw.UserKeyHashes[config.HashAPIKey("key-a2a-user2")] = "a2a-user2"
+	cfgGw.Backends["agent1"] = &config.Backend{URL: agent1URL}
+	cfgGw.Backends["agent2"] = &config.Backend{URL: agent2URL}
+	cfgGw.UserA2AAgents["a2a-user1"] = "agent1"
+	cfgGw.UserA2AAgents["a2a-user2"] = "agent2"
+	cfgGw.UserSubscribes["a2a-user2"] = []string{"agent1", "agent2"}
+	_, err = gateway.Start(ctx, LOGGER.With(zap.String("s", "a2a-gateway")), cfgGw, fmt.Sprintf(":%d", portForGateway))
+	if err != nil {
+		t.Fatalf("Failed to start gateway: %v", err)
+	}
+	waitForPort(t, portForGateway)
+	gwA2AURL := "http://localhost:" + strconv.Itoa(portForGateway) + "/a2a"
+
+	newClient := func(key string) *a2aClient.Client {
+		client, err := a2aClient.New(gwA2AURL, a2aClient.WithAuthenticationBearer(key), a2aClient.DoNotTrustAgentInfoURL())
+		if err != nil {
+			t.Fatalf("Failed to create A2A client: %v", err)
+		}
+		return client
+	}
+	sendTaskWithID := func(key, taskID string, metadata *map[string]interface{}) (*a2aSchema.Task, error) {
+		reqCtx, reqCancel := context.WithTimeout(ctx, 10*time.Second)
+		defer reqCancel()
+		return newClient(key).SendTask(reqCtx, a2aSchema.TaskSendParams{
+			ID:       taskID,
+			Message:  a2aSchema.Message{Role: "user", Parts: []a2aSchema.Part{{Type: shared.PointerTo("text"), Text: shared.PointerTo("who are you?")}}},
+			Metadata: metadata,
+		})
+	}
+	sendTask := func(key string, metadata *map[string]interface{}) (*a2aSchema.Task, error) {
+		return sendTaskWithID(key, fmt.Sprintf("task-%s-%d", key, time.Now().UnixNano()), metadata)
+	}
+	getTask := func(key, taskID string) (*a2aSchema.Task, error) {
+		reqCtx, reqCancel := context.WithTimeout(ctx, 10*time.Second)
+		defer reqCancel()
+		return newClient(key).GetTask(reqCtx, a2aSchema.TaskQueryParams{ID: taskID})
+	}
+	answeredBy := func(task *a2aSchema.Task) string {
+		if task == nil || task.Status.Message == nil || len(task.Status.Message.Parts) == 0 || task.Status.Message.Parts[0].Text == nil {
+			return ""
+		}
+		return *task.Status.Message.Parts[0].Text
+	}
+
+	cases := []struct {
+		name     string
+		key      string
+		metadata *map[string]interface{}
+		want     string
+	}{
+		{"user1 default agent", "key-a2a-user1", nil, "agent1"},
+		{"user2 default agent", "key-a2a-user2", nil, "agent2"},
+		{"user2 explicit agentSlug", "key-a2a-user2", &map[string]interface{}{capability.AgentSlugMetadataKey: "agent1"}, "agent1"},
+	}
+	for _, tc := range cases {
+		task, err := sendTask(tc.key, tc.metadata)
+		if err != nil {
+			t.Fatalf("%s: SendTask failed: %v", tc.name, err)
+		}
+		if got := answeredBy(task); got != tc.want {
+			t.Fatalf("%s: task routed to %q, want %q", tc.name, got, tc.want)
+		}
+	}
+
+	// user1 is not subscribed to agent2, so an explicit request for it must be rejected
+	_, err = sendTask("key-a2a-user1", &map[string]interface{}{capability.AgentSlugMetadataKey: "agent2"})
+	if err == nil {
+		t.Fatalf("Expected routing to an unsubscribed agent to fail")
+	}
+
+	// A completed task stays with its owner: the owner can still get it, other users can
+	// neither get it nor send to its ID
+	task, err := sendTaskWithID("key-a2a-user1", "finished-task", nil)
+	if err != nil || task.Status.State != a2aSchema.TaskStateCompleted {
+		t.Fatalf("Expected a completed task, got %+v, %v", task, err)
+	}
+	if task, err := getTask("key-a2a-user1", "finished-task"); err != nil || answeredBy(task) != "agent1" {
+		t.Fatalf("Owner could not get the completed task: %+v, %v", task, err)
+	}
+	if _, err := getTask("key-a2a-user2", "finished-task"); err == nil {
+		t.Fatalf("Another user got the completed task")
+	}
+	if _, err := sendTaskWithID("key-a2a-user2", "finished-task", &map[string]interface{}{capability.AgentSlugMetadataKey: "agent1"}); err == nil {
+		t.Fatalf("Another user sent to the ID of a completed task")
+	}
+}
@@ -3,6 +3,7 @@ package capability
 import (
 	"context"
 	"fmt"
+	"strings"
 	"sync"
 	"time"
 
@@ -55,9 +56,10 @@ func (c *GatewayCapability) GetResources(inputMsg *shared.Message, logger *zap.L
 			results := make([]*resourceWithServerInfo, 0, len(backendResources))
 			for _, r := range backendResources {
 				rCopy := r // Create copy
+				rCopy.URI = gatewayResourceURI(session.Backend.Slug, r.URI)
 				results = append(results, &resourceWithServerInfo{
 					Resource:    rCopy,
-					originalURI: rCopy.URI, // Store original URI
+					originalURI: r.URI, // Store original URI
 					serverSlug:  session.Backend.Slug,
 				})
 			}
@@ -74,16 +76,8 @@ func (c *GatewayCapability) GetResources(inputMsg *shared.Message, logger *zap.L
 		return r.URI // Use the current URI (which might be modified later)
 	}
 
-	// Define the function to modify the resource URI in case of duplicates
+	// URIs are already prefixed with the server slug, so duplicates can only come from a single backend
 	modifyResourceKeyFunc := func(r *resourceWithServerInfo, serverSlug string) *resourceWithServerInfo {
-		// Use serverSlug passed to the function for prefixing
-		newURI := fmt.Sprintf("%s:%s", serverSlug, r.originalURI) // Use originalURI for prefixing
-		logger.Debug("Modifying duplicate resource URI",
-			zap.String("original", r.originalURI),
-			zap.String("server", serverSlug),
-			zap.String("modified", newURI),
-		)
-		r.URI = newURI // Update the URI field
 		return r
 	}
 
@@ -117,6 +111,21 @@ func (c *GatewayCapability) gw_resources_list(inputMsg *shared.Message) (interfa
 	return toListResourcesResult(allResources), nil
 }
 
+// gatewayResourceURI prefixes a backend resource URI with its server slug
+// (`myserver://resource/1` -> `myserver/myserver://resource/1`) so URIs from different backends never collide.
+func gatewayResourceURI(serverSlug string, uri string) string {
+	return serverSlug + "/" + uri
+}
+
+// splitGatewayResourceURI reverses gatewayResourceURI.
+func splitGatewayResourceURI(uri string) (serverSlug string, originalURI string, ok bool) {
+	serverSlug, originalURI, ok = strings.Cut(uri, "/")
+	if !ok || serverSlug == "" || originalURI == "" {
+		return "", "", false
+	}
+	return serverSlug, originalURI, true
+}
+
 // toListResourcesResult converts the internal representation to the schema result type.
 func toListResourcesResult(resources []*resourceWithServerInfo) schema.ListResourcesResult {
 	schemaResources := make([]schema.Resource, 0, len(resources))
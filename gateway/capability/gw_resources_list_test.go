@@ -0,0 +1,97 @@
+package capability_test
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/gate4ai/gate4ai/gateway"
+	"github.com/gate4ai/gate4ai/gateway/clients/mcpClient"
+	"github.com/gate4ai/gate4ai/server"
+	"github.com/gate4ai/gate4ai/shared"
+	"github.com/gate4ai/gate4ai/shared/config"
+	"github.com/gate4ai/gate4ai/shared/mcp/2025/schema"
+	"github.com/gate4ai/gate4ai/tests"
+	"go.uber.org/zap"
+)
+
+// startResourceServer runs an MCP server exposing two resources whose contents name the server.
+func startResourceServer(t *testing.T, ctx context.Context, name string) string {
+	port, err := tests.FindAvailablePort()
+	if err != nil {
+		t.Fatalf("Failed to find available port: %v", err)
+	}
+	cfg := config.NewInternalConfig()
+	cfg.UserKeyHashes[config.HashAPIKey("gateway")] = "gw"
+
+	options := []server.ServerOption{server.WithListenAddr(fmt.Sprintf(":%d", port))}
+	for _, uri := range []string{"res://item/1", "res://item/2"} {
+		uri := uri
+		handler := func(msg *shared.Message) (schema.Meta, []schema.ResourceContent, error) {
+			return nil, []schema.ResourceContent{{URI: uri, MimeType: "text/plain", Text: shared.PointerTo(name + " " + uri)}}, nil
+		}
+		options = append(options, server.WithMCPResource(uri, name+" "+uri, "", "text/plain", handler))
+	}
+	_, err = server.Start(ctx, LOGGER.With(zap.String("s", name)), cfg, options...)
+	if err != nil {
+		t.Fatalf("Failed to start server %s: %v", name, err)
+	}
+	waitForPort(t, port)
+	return "http://localhost:" + strconv.Itoa(port) + "/sse?key=gateway"
+}
+
+func TestResourcesAggregatedWithServerPrefix(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	portForGateway, err := tests.FindAvailablePort()
+	if err != nil {
+		t.Fatalf("Failed to find available port: %v", err)
+	}
+	cfgGw := config.NewInternalConfig()
+	cfgGw.UserKeyHashes[config.HashAPIKey("key-res-user")] = "res-user"
+	cfgGw.Backends["res1"] = &config.Backend{URL: startResourceServer(t, ctx, "res1")}
+	cfgGw.Backends["res2"] = &config.Backend{URL: startResourceServer(t, ctx, "res2")}
+	cfgGw.UserSubscribes["res-user"] = []string{"res1", "res2"}
+	_, err = gateway.Start(ctx, LOGGER.With(zap.String("s", "res-gateway")), cfgGw, fmt.Sprintf(":%d", portForGateway))
+	if err != nil {
+		t.Fatalf("Failed to start gateway: %v", err)
+	}
+	waitForPort(t, portForGateway)
+	gwURL := "http://localhost:" + strconv.Itoa(portForGateway) + "/sse"
+
+	reqCtx, reqCancel := context.WithTimeout(ctx, 15*time.Second)
+	defer reqCancel()
+	c, err := mcpClient.New(gwURL, gwURL, LOGGER.With(zap.String("s", "res-client")))
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	session := c.NewSession(reqCtx, mcpClient.WithAuthenticationBearer("key-res-user"))
+	defer session.Close()
+
+	list := <-session.GetResources(reqCtx)
+	if list.Err != nil {
+		t.Fatalf("Failed to list resources: %v", list.Err)
+	}
+	uris := make([]string, 0, len(list.Resources))
+	for _, r := range list.Resources {
+		uris = append(uris, r.URI)
+	}
+	sort.Strings(uris)
+	want := []string{"res1/res://item/1", "res1/res://item/2", "res2/res://item/1", "res2/res://item/2"}
+	if fmt.Sprint(uris) != fmt.Sprint(want) {
+		t.Fatalf("Aggregated resource URIs = %v, want %v", uris, want)
+	}
+
+	// Reading a prefixed URI must reach the backend that owns it
+	read := <-session.ReadResource(reqCtx, "res2/res://item/1")
+	if read.Err != nil {
+		t.Fatalf("Failed to read resource: %v", read.Err)
+	}
+	if len(read.Result.Contents) != 1 || read.Result.Contents[0].Text == nil || *read.Result.Contents[0].Text != "res2 res://item/1" {
+		t.Fatalf("Unexpected resource contents: %+v", read.Result.Contents)
+	}
+}
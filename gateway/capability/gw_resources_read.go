@@ -31,41 +31,26 @@ func (c *GatewayCapability) gw_resources_read(inputMsg *shared.Message) (interfa
 	}
 	logger = logger.With(zap.String("uri", params.URI))
 
-	// Get combined list of resources (handles fetching, conflict resolution, caching)
-	// This ensures we know which backend owns the potentially modified URI.
-	resources, err := c.GetResources(inputMsg, logger)
-	if err != nil {
-		// Error already logged by GetResources
-		return nil, fmt.Errorf("failed to get resource list: %w", err)
-	}
-
-	// Find the target resource by its (potentially modified) URI
-	var targetResource *resourceWithServerInfo
-	for _, res := range resources {
-		if res != nil && res.URI == params.URI { // Add nil check
-			targetResource = res
-			break
-		}
-	}
-
-	if targetResource == nil {
-		logger.Error("Resource not found in any backend")
+	// The gateway URI is "<serverSlug>/<originalURI>", so the owning backend can be resolved without listing resources
+	serverSlug, originalURI, ok := splitGatewayResourceURI(params.URI)
+	if !ok {
+		logger.Warn("Resource URI has no server prefix")
 		return nil, fmt.Errorf("resource not found: %s", params.URI)
 	}
 
-	logger.Debug("Found resource, forwarding read request to backend",
-		zap.String("backendServerSlug", targetResource.serverSlug),
-		zap.String("originalURI", targetResource.originalURI))
+	logger.Debug("Forwarding read request to backend",
+		zap.String("backendServerSlug", serverSlug),
+		zap.String("originalURI", originalURI))
 
 	// Get the backend session for the server that owns this resource
-	backendSession, err := c.getBackendSession(inputMsg.Session, targetResource.serverSlug)
+	backendSession, err := c.getBackendSession(inputMsg.Session, serverSlug)
 	if err != nil {
 		// Error logged by getBackendSession
 		return nil, err
 	}
 	if backendSession == nil {
-		logger.Error("Backend session is nil after successful retrieval", zap.String("serverSlug", targetResource.serverSlug))
-		return nil, fmt.Errorf("internal error: failed to get valid backend session for server %s", targetResource.serverSlug)
+		logger.Error("Backend session is nil after successful retrieval", zap.String("serverSlug", serverSlug))
+		return nil, fmt.Errorf("internal error: failed to get valid backend session for server %s", serverSlug)
 	}
 
 	// Use a timeout context for the backend call
@@ -73,22 +58,22 @@ func (c *GatewayCapability) gw_resources_read(inputMsg *shared.Message) (interfa
 	defer cancel()
 
 	// Forward the request to the backend using the ORIGINAL resource URI
-	resultChan := backendSession.ReadResource(ctx, targetResource.originalURI)
+	resultChan := backendSession.ReadResource(ctx, originalURI)
 	result := <-resultChan // Wait for the result from the backend
 
 	if result.Err != nil {
 		logger.Error("Failed to read resource from backend server",
-			zap.String("server", targetResource.serverSlug),
-			zap.String("originalURI", targetResource.originalURI),
+			zap.String("server", serverSlug),
+			zap.String("originalURI", originalURI),
 			zap.Error(result.Err))
 		// Return the error received from the backend
-		return nil, fmt.Errorf("backend error reading resource '%s': %w", targetResource.originalURI, result.Err)
+		return nil, fmt.Errorf("backend error reading resource '%s': %w", originalURI, result.Err)
 	}
 
 	if result.Result == nil {
 		// Should not happen if Err is nil, but check defensively
 		err := fmt.Errorf("nil result received from backend %s for resource %s",
-			targetResource.serverSlug, targetResource.originalURI)
+			serverSlug, originalURI)
 		logger.Error(err.Error())
 		return nil, err
 	}
@@ -145,7 +145,7 @@ func (c *GatewayCapability) gw_resources_notification_updated(backendMsg *shared
 		// Option 2: Send notification with original URI (client might not recognize it)?
 		// Option 3: Send notification with prefixed URI (serverID:originalURI)?
 		// Let's try Option 3 as a fallback.
-		gatewayURI := fmt.Sprintf("%s:%s", serverSlug, originalURI)
+		gatewayURI := gatewayResourceURI(serverSlug, originalURI)
 		clientSessionLogger.Warn("Sending notification with prefixed URI as fallback", zap.String("gatewayURI", gatewayURI))
 		clientSession.SendNotification("notifications/resources/updated", map[string]interface{}{
 			"uri": gatewayURI,
@@ -165,7 +165,7 @@ func (c *GatewayCapability) gw_resources_notification_updated(backendMsg *shared
 		clientSessionLogger.Error("Could not find gateway URI corresponding to updated resource", zap.String("originalURI", originalURI), zap.String("serverSlug", serverSlug))
 		// Fallback like above, or maybe don't send notification if mapping fails?
 		// Let's try sending prefixed URI again.
-		gatewayURI = fmt.Sprintf("%s:%s", serverSlug, originalURI)
+		gatewayURI = gatewayResourceURI(serverSlug, originalURI)
 		clientSessionLogger.Warn("Sending notification with prefixed URI as fallback (mapping failed)", zap.String("gatewayURI", gatewayURI))
 	} else {
 		clientSessionLogger.Debug("Mapped backend update to gateway URI", zap.String("originalURI", originalURI), zap.String("serverSlug", serverSlug), zap.String("gatewayURI", gatewayURI))
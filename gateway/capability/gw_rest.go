@@ -0,0 +1,248 @@
+package capability
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/gate4ai/gate4ai/shared"
+	"github.com/gate4ai/gate4ai/shared/config"
+	schema "github.com/gate4ai/gate4ai/shared/mcp/2025/schema"
+	"go.uber.org/zap"
+)
+
+const restBackendTimeout = 30 * time.Second
+
+var _ shared.IServerCapability = (*RESTBackendCapability)(nil)
+
+// RESTBackendCapability exposes the endpoints of a REST backend as MCP tools.
+// Each config.RestTool of the backend becomes a tool; tools/call performs the mapped HTTP request.
+type RESTBackendCapability struct {
+	logger      *zap.Logger
+	config      config.IConfig
+	backendSlug string
+	httpClient  *http.Client
+}
+
+// NewRESTBackendCapability creates a capability serving the RestTools of the backend backendSlug
+func NewRESTBackendCapability(logger *zap.Logger, cfg config.IConfig, backendSlug string) *RESTBackendCapability {
+	return &RESTBackendCapability{
+		logger:      logger.Named("gateway-rest").With(zap.String("serverSlug", backendSlug)),
+		config:      cfg,
+		backendSlug: backendSlug,
+		httpClient:  http.DefaultClient,
+	}
+}
+
+func (c *RESTBackendCapability) GetHandlers() map[string]func(*shared.Message) (interface{}, error) {
+	return map[string]func(*shared.Message) (interface{}, error){
+		"tools/list": c.gw_rest_tools_list,
+		"tools/call": c.gw_rest_tools_call,
+	}
+}
+
+func (c *RESTBackendCapability) SetCapabilities(s *schema.ServerCapabilities) {
+	s.Tools = &schema.Capability{}
+}
+
+func (c *RESTBackendCapability) SetEventBus(bus shared.EventBus) {}
+
+// gw_rest_tools_list lists the RestTools of the backend.
+func (c *RESTBackendCapability) gw_rest_tools_list(inputMsg *shared.Message) (interface{}, error) {
+	backend, err := c.config.GetBackendBySlug(c.backendSlug)
+	if err != nil {
+		c.logger.Error("Failed to get REST backend", zap.Error(err))
+		return nil, fmt.Errorf("failed to get backend %s: %w", c.backendSlug, err)
+	}
+	result := schema.ListToolsResult{Tools: make([]schema.Tool, 0, len(backend.RestTools))}
+	for _, restTool := range backend.RestTools {
+		result.Tools = append(result.Tools, restToolSchema(restTool))
+	}
+	return result, nil
+}
+
+// restToolSchema describes restTool as an MCP tool. Path parameters are required arguments.
+func restToolSchema(restTool config.RestTool) schema.Tool {
+	inputSchema := &schema.JSONSchemaProperty{Type: "object", Properties: make(map[string]schema.JSONSchemaProperty)}
+	for _, name := range restTool.PathParams {
+		inputSchema.Properties[name] = schema.JSONSchemaProperty{Type: "string"}
+		inputSchema.Required = append(inputSchema.Required, name)
+	}
+	for _, name := range restTool.BodyArgs {
+		inputSchema.Properties[name] = schema.JSONSchemaProperty{}
+	}
+	return schema.Tool{Name: restTool.Name, Description: restTool.Description, InputSchema: inputSchema}
+}
+
+// gw_rest_tools_call performs the HTTP request mapped to the called tool.
+func (c *RESTBackendCapability) gw_rest_tools_call(inputMsg *shared.Message) (interface{}, error) {
+	logger := c.logger.With(zap.String("msgID", inputMsg.ID.String()), zap.String("method", "tools/call"))
+
+	var params schema.CallToolRequestParams
+	if inputMsg.Params == nil {
+		return nil, fmt.Errorf("missing parameters")
+	}
+	if err := json.Unmarshal(*inputMsg.Params, &params); err != nil {
+		logger.Error("Failed to unmarshal parameters", zap.Error(err))
+		return nil, fmt.Errorf("invalid parameters: %w", err)
+	}
+	logger = logger.With(zap.String("toolName", params.Name))
+
+	backend, err := c.config.GetBackendBySlug(c.backendSlug)
+	if err != nil {
+		logger.Error("Failed to get REST backend", zap.Error(err))
+		return nil, fmt.Errorf("failed to get backend %s: %w", c.backendSlug, err)
+	}
+	index := slices.IndexFunc(backend.RestTools, func(t config.RestTool) bool { return t.Name == params.Name })
+	if index < 0 {
+		logger.Warn("Tool not found in REST backend")
+		return nil, fmt.Errorf("tool not found: %s", params.Name)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), restBackendTimeout)
+	defer cancel()
+	req, err := buildRESTRequest(ctx, backend, backend.RestTools[index], params.Arguments)
+	if err != nil {
+		logger.Warn("Failed to build REST request", zap.Error(err))
+		return nil, &shared.JSONRPCError{Code: shared.JSONRPCErrorInvalidParams, Message: err.Error()}
+	}
+
+	logger.Debug("Calling REST backend", zap.String("httpMethod", req.Method), zap.String("url", req.URL.String()))
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		logger.Error("REST request failed", zap.Error(err))
+		return nil, fmt.Errorf("failed to call tool '%s' on backend: %w", params.Name, err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		logger.Error("Failed to read REST response", zap.Error(err))
+		return nil, fmt.Errorf("failed to read response of tool '%s': %w", params.Name, err)
+	}
+
+	// Like MCP backends, failures of the endpoint are tool errors the model can see
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		logger.Warn("REST backend returned non-success status", zap.Int("status", resp.StatusCode))
+		return &schema.CallToolResult{
+			Content: schema.NewTextContent(fmt.Sprintf("HTTP %d: %s", resp.StatusCode, body)),
+			IsError: true,
+		}, nil
+	}
+	content, err := mapRESTResponse(body, backend.RestTools[index].ResponseMapping)
+	if err != nil {
+		logger.Error("Failed to map REST response", zap.Error(err))
+		return nil, fmt.Errorf("failed to map response of tool '%s': %w", params.Name, err)
+	}
+	return &schema.CallToolResult{Content: content}, nil
+}
+
+// buildRESTRequest substitutes the path parameters of restTool, sends the body arguments as a
+// JSON object and the other arguments as query parameters.
+func buildRESTRequest(ctx context.Context, backend *config.Backend, restTool config.RestTool, args schema.Arguments) (*http.Request, error) {
+	path := restTool.URL
+	for _, name := range restTool.PathParams {
+		value, ok := args[name]
+		if !ok {
+			return nil, fmt.Errorf("missing path parameter %q", name)
+		}
+		path = strings.ReplaceAll(path, "{"+name+"}", url.PathEscape(fmt.Sprint(value)))
+	}
+	base, err := url.Parse(backend.URL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid backend URL: %w", err)
+	}
+	target, err := base.Parse(path)
+	if err != nil {
+		return nil, fmt.Errorf("invalid tool URL %q: %w", path, err)
+	}
+
+	query := target.Query()
+	var bodyArgs map[string]interface{}
+	for name, value := range args {
+		switch {
+		case slices.Contains(restTool.PathParams, name):
+		case slices.Contains(restTool.BodyArgs, name):
+			if bodyArgs == nil {
+				bodyArgs = make(map[string]interface{})
+			}
+			bodyArgs[name] = value
+		default:
+			query.Set(name, queryValue(value))
+		}
+	}
+	target.RawQuery = query.Encode()
+
+	var body io.Reader
+	if bodyArgs != nil {
+		data, err := json.Marshal(bodyArgs)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal body arguments: %w", err)
+		}
+		body = bytes.NewReader(data)
+	}
+	method := strings.ToUpper(restTool.Method)
+	if method == "" {
+		method = http.MethodGet
+	}
+	req, err := http.NewRequestWithContext(ctx, method, target.String(), body)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if backend.Bearer != "" {
+		req.Header.Set("Authorization", "Bearer "+backend.Bearer)
+	}
+	return req, nil
+}
+
+// queryValue formats an argument as a query parameter; objects and arrays are sent as JSON.
+func queryValue(value interface{}) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case map[string]interface{}, []interface{}:
+		data, _ := json.Marshal(v)
+		return string(data)
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// mapRESTResponse converts a response body to tool content. With a mapping, the body must be
+// JSON and the value at the dot-separated mapping path is returned.
+func mapRESTResponse(body []byte, mapping string) ([]schema.Content, error) {
+	if mapping == "" {
+		return schema.NewTextContent(string(body)), nil
+	}
+	var value interface{}
+	if err := json.Unmarshal(body, &value); err != nil {
+		return nil, fmt.Errorf("response is not JSON: %w", err)
+	}
+	for _, key := range strings.Split(mapping, ".") {
+		object, ok := value.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("response has no object at %q of mapping %q", key, mapping)
+		}
+		if value, ok = object[key]; !ok {
+			return nil, fmt.Errorf("response has no field %q of mapping %q", key, mapping)
+		}
+	}
+	if text, ok := value.(string); ok {
+		return schema.NewTextContent(text), nil
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+	return schema.NewTextContent(string(data)), nil
+}
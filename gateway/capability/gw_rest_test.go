@@ -0,0 +1,130 @@
+package capability_test
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gate4ai/gate4ai/gateway/capability"
+	"github.com/gate4ai/gate4ai/shared/config"
+	schema "github.com/gate4ai/gate4ai/shared/mcp/2025/schema"
+	sharedtesting "github.com/gate4ai/gate4ai/shared/testing"
+)
+
+// recordedRequest is the request a mock REST backend received.
+type recordedRequest struct {
+	method, path, query, authorization string
+	body                               map[string]interface{}
+}
+
+// startRESTBackend runs a REST API answering every request with response, recording the requests.
+func startRESTBackend(t *testing.T, status int, response string) (*config.InternalConfig, chan recordedRequest) {
+	requests := make(chan recordedRequest, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		recorded := recordedRequest{method: r.Method, path: r.URL.Path, query: r.URL.RawQuery, authorization: r.Header.Get("Authorization")}
+		if data, _ := io.ReadAll(r.Body); len(data) > 0 {
+			if err := json.Unmarshal(data, &recorded.body); err != nil {
+				t.Errorf("Request body is not a JSON object: %s", data)
+			}
+		}
+		requests <- recorded
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		w.Write([]byte(response))
+	}))
+	t.Cleanup(server.Close)
+
+	cfg := config.NewInternalConfig()
+	cfg.Backends["shop"] = &config.Backend{URL: server.URL + "/api/", Bearer: "secret", RestTools: []config.RestTool{
+		{Name: "getUser", URL: "users/{id}", PathParams: []string{"id"}, ResponseMapping: "user.name"},
+		{Name: "createOrder", URL: "orders", Method: "post", BodyArgs: []string{"item", "quantity"}},
+	}}
+	return cfg, requests
+}
+
+func callRESTTool(t *testing.T, cfg config.IConfig, name string, args schema.Arguments) (*schema.CallToolResult, error) {
+	handlers := capability.NewRESTBackendCapability(LOGGER, cfg, "shop").GetHandlers()
+	result, err := handlers["tools/call"](sharedtesting.BuildMessage("tools/call", schema.CallToolRequestParams{Name: name, Arguments: args}))
+	if err != nil {
+		return nil, err
+	}
+	return result.(*schema.CallToolResult), nil
+}
+
+func TestRESTBackendToolCallWithPathParams(t *testing.T) {
+	cfg, requests := startRESTBackend(t, http.StatusOK, `{"user": {"name": "Alice"}}`)
+
+	result, err := callRESTTool(t, cfg, "getUser", schema.Arguments{"id": "42", "fields": "name"})
+	if err != nil {
+		t.Fatalf("Tool call failed: %v", err)
+	}
+	req := <-requests
+	if req.method != http.MethodGet || req.path != "/api/users/42" || req.query != "fields=name" {
+		t.Errorf("Unexpected request %s %s?%s", req.method, req.path, req.query)
+	}
+	if req.authorization != "Bearer secret" {
+		t.Errorf("Authorization = %q, want the backend bearer", req.authorization)
+	}
+	if req.body != nil {
+		t.Errorf("GET request has a body: %v", req.body)
+	}
+	if len(result.Content) != 1 || *result.Content[0].Text != "Alice" || result.IsError {
+		t.Errorf("Unexpected result: %+v", result)
+	}
+}
+
+func TestRESTBackendToolCallWithBodyArgs(t *testing.T) {
+	cfg, requests := startRESTBackend(t, http.StatusCreated, `{"id": 7}`)
+
+	result, err := callRESTTool(t, cfg, "createOrder", schema.Arguments{"item": "book", "quantity": 2, "dryRun": true})
+	if err != nil {
+		t.Fatalf("Tool call failed: %v", err)
+	}
+	req := <-requests
+	if req.method != http.MethodPost || req.path != "/api/orders" || req.query != "dryRun=true" {
+		t.Errorf("Unexpected request %s %s?%s", req.method, req.path, req.query)
+	}
+	if len(req.body) != 2 || req.body["item"] != "book" || req.body["quantity"] != float64(2) {
+		t.Errorf("Unexpected request body: %v", req.body)
+	}
+	if len(result.Content) != 1 || *result.Content[0].Text != `{"id": 7}` {
+		t.Errorf("Unexpected result: %+v", result)
+	}
+}
+
+func TestRESTBackendToolCallErrors(t *testing.T) {
+	cfg, _ := startRESTBackend(t, http.StatusNotFound, `{"error": "no such user"}`)
+
+	if _, err := callRESTTool(t, cfg, "getUser", schema.Arguments{}); err == nil {
+		t.Error("Expected an error for a missing path parameter")
+	}
+	if _, err := callRESTTool(t, cfg, "deleteUser", schema.Arguments{"id": "1"}); err == nil {
+		t.Error("Expected an error for an unknown tool")
+	}
+	result, err := callRESTTool(t, cfg, "getUser", schema.Arguments{"id": "1"})
+	if err != nil {
+		t.Fatalf("Tool call failed: %v", err)
+	}
+	if !result.IsError || *result.Content[0].Text != `HTTP 404: {"error": "no such user"}` {
+		t.Errorf("Expected a tool error with the response body, got %+v", result)
+	}
+}
+
+func TestRESTBackendToolsList(t *testing.T) {
+	cfg, _ := startRESTBackend(t, http.StatusOK, `{}`)
+	handlers := capability.NewRESTBackendCapability(LOGGER, cfg, "shop").GetHandlers()
+
+	result, err := handlers["tools/list"](sharedtesting.BuildMessage("tools/list", nil))
+	if err != nil {
+		t.Fatalf("tools/list failed: %v", err)
+	}
+	tools := result.(schema.ListToolsResult).Tools
+	if len(tools) != 2 || tools[0].Name != "getUser" || tools[1].Name != "createOrder" {
+		t.Fatalf("Unexpected tools: %+v", tools)
+	}
+	if required := tools[0].InputSchema.Required; len(required) != 1 || required[0] != "id" {
+		t.Errorf("Path parameters should be required, got %v", required)
+	}
+}
@@ -0,0 +1,106 @@
+package capability
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/gate4ai/gate4ai/gateway/clients/mcpClient"
+	"github.com/gate4ai/gate4ai/shared"
+	schema "github.com/gate4ai/gate4ai/shared/mcp/2025/schema"
+	"go.uber.org/zap"
+)
+
+// rootWithServerInfo extends the 2025 schema.Root with server information
+type rootWithServerInfo struct {
+	schema.Root
+	serverSlug string
+}
+
+// gw_roots_list handles the "roots/list" request from the client, merging the roots of all
+// subscribed backends. URIs are prefixed with the server slug like resource URIs.
+func (c *GatewayCapability) gw_roots_list(inputMsg *shared.Message) (interface{}, error) {
+	logger := c.logger.With(zap.String("msgID", inputMsg.ID.String()), zap.String("method", "roots/list"))
+	logger.Debug("Processing request")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+
+	fetchRootsFunc := func(ctx context.Context, session *mcpClient.Session) ([]*rootWithServerInfo, error) {
+		fetchLogger := logger.With(zap.String("server", session.Backend.Slug))
+		select {
+		case result := <-session.ListRoots(ctx):
+			if result.Err != nil {
+				fetchLogger.Error("Failed to get roots from backend", zap.Error(result.Err))
+				return nil, result.Err
+			}
+			roots := make([]*rootWithServerInfo, 0, len(result.Roots))
+			for _, r := range result.Roots {
+				r.URI = gatewayResourceURI(session.Backend.Slug, r.URI)
+				roots = append(roots, &rootWithServerInfo{Root: r, serverSlug: session.Backend.Slug})
+			}
+			fetchLogger.Debug("Received roots from backend", zap.Int("count", len(roots)))
+			return roots, nil
+		case <-ctx.Done():
+			fetchLogger.Warn("Context cancelled while waiting for roots from backend", zap.Error(ctx.Err()))
+			return nil, ctx.Err()
+		}
+	}
+	getRootKeyFunc := func(r *rootWithServerInfo) string {
+		return r.URI
+	}
+	// URIs are already prefixed with the server slug, so duplicates can only come from a single backend
+	modifyRootKeyFunc := func(r *rootWithServerInfo, serverSlug string) *rootWithServerInfo {
+		return r
+	}
+
+	allRoots, err := fetchAndCombineFromBackends(c, ctx, inputMsg.Session, fetchRootsFunc, getRootKeyFunc, modifyRootKeyFunc)
+	if err != nil {
+		logger.Error("Failed to fetch and combine roots", zap.Error(err))
+		return nil, fmt.Errorf("failed to get roots: %w", err)
+	}
+
+	roots := make([]schema.Root, 0, len(allRoots))
+	for _, r := range allRoots {
+		roots = append(roots, r.Root)
+	}
+	logger.Debug("Collected all roots", zap.Int("count", len(roots)))
+	return schema.ListRootsResult{Roots: roots}, nil
+}
+
+// gw_roots_notification_added is the callback invoked by a backend client.Session when it receives
+// a "roots/add" notification. It forwards the roots to the client with slug-prefixed URIs.
+func (c *GatewayCapability) gw_roots_notification_added(backendMsg *shared.Message) {
+	logger := c.logger.With(zap.String("method", "roots/add_callback"))
+
+	if backendMsg == nil || backendMsg.Params == nil {
+		logger.Error("Received nil message or params in roots added callback")
+		return
+	}
+	var params schema.RootsAddedNotificationParams
+	if err := json.Unmarshal(*backendMsg.Params, &params); err != nil {
+		logger.Error("Failed to unmarshal backend roots added notification params", zap.Error(err))
+		return
+	}
+
+	backendSessionParams := backendMsg.Session.GetParams()
+	serverSlug, _, okServer := GetServerSlug(backendSessionParams)
+	if !okServer || serverSlug == "" {
+		logger.Error("Could not determine server ID from backend session receiving the roots")
+		return
+	}
+	clientSession, _, okClient := GetClientSession(backendSessionParams)
+	if !okClient || clientSession == nil {
+		logger.Error("Could not retrieve gateway client session associated with the backend session")
+		return
+	}
+
+	roots := make([]schema.Root, 0, len(params.Roots))
+	for _, r := range params.Roots {
+		r.URI = gatewayResourceURI(serverSlug, r.URI)
+		roots = append(roots, r)
+	}
+	clientSession.SendNotification("roots/add", map[string]any{"roots": roots})
+	logger.Info("Forwarded roots added notification to client", zap.String("clientSessionID", clientSession.GetID()), zap.String("serverSlug", serverSlug), zap.Int("count", len(roots)))
+}
@@ -0,0 +1,109 @@
+package capability_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/gate4ai/gate4ai/gateway"
+	"github.com/gate4ai/gate4ai/gateway/clients/mcpClient"
+	mcpCapability "github.com/gate4ai/gate4ai/server/mcp/capability"
+	"github.com/gate4ai/gate4ai/server/transport"
+	"github.com/gate4ai/gate4ai/shared"
+	"github.com/gate4ai/gate4ai/shared/config"
+	"github.com/gate4ai/gate4ai/shared/mcp/2025/schema"
+	"github.com/gate4ai/gate4ai/tests"
+	"go.uber.org/zap"
+)
+
+// rootsBackend answers roots/list with a fixed list of roots.
+type rootsBackend struct {
+	roots []schema.Root
+}
+
+func (b *rootsBackend) GetHandlers() map[string]func(*shared.Message) (interface{}, error) {
+	return map[string]func(*shared.Message) (interface{}, error){
+		"roots/list": func(msg *shared.Message) (interface{}, error) {
+			return schema.ListRootsResult{Roots: b.roots}, nil
+		},
+	}
+}
+
+func (b *rootsBackend) SetCapabilities(s *schema.ServerCapabilities) {}
+func (b *rootsBackend) SetEventBus(bus shared.EventBus)              {}
+
+// startRootsServer runs an MCP server whose roots/list returns two roots.
+func startRootsServer(t *testing.T) string {
+	logger := LOGGER.With(zap.String("s", "roots-backend"))
+	cfg := config.NewInternalConfig()
+	cfg.UserKeyHashes[config.HashAPIKey("gateway")] = "gw"
+	manager, err := transport.NewManager(logger, cfg)
+	if err != nil {
+		t.Fatalf("Failed to create manager: %v", err)
+	}
+	tr, err := transport.New(manager, logger, cfg)
+	if err != nil {
+		t.Fatalf("Failed to create transport: %v", err)
+	}
+	manager.AddCapability(mcpCapability.NewBase(logger, manager), &rootsBackend{roots: []schema.Root{
+		{URI: "file:///workspace/a", Name: "a"},
+		{URI: "file:///workspace/b", Name: "b"},
+	}})
+	mux := http.NewServeMux()
+	tr.RegisterMCPHandlers(mux)
+	server := httptest.NewServer(mux)
+	t.Cleanup(func() {
+		server.CloseClientConnections() // The gateway keeps its SSE streams open
+		server.Close()
+	})
+	return server.URL + "/sse?key=gateway"
+}
+
+func TestRootsAggregatedWithServerPrefix(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	portForGateway, err := tests.FindAvailablePort()
+	if err != nil {
+		t.Fatalf("Failed to find available port: %v", err)
+	}
+	cfgGw := config.NewInternalConfig()
+	cfgGw.UserKeyHashes[config.HashAPIKey("key-roots-user")] = "roots-user"
+	cfgGw.Backends["roots1"] = &config.Backend{URL: startRootsServer(t)}
+	cfgGw.Backends["roots2"] = &config.Backend{URL: startRootsServer(t)}
+	cfgGw.UserSubscribes["roots-user"] = []string{"roots1", "roots2"}
+	_, err = gateway.Start(ctx, LOGGER.With(zap.String("s", "roots-gateway")), cfgGw, fmt.Sprintf(":%d", portForGateway))
+	if err != nil {
+		t.Fatalf("Failed to start gateway: %v", err)
+	}
+	waitForPort(t, portForGateway)
+	gwURL := "http://localhost:" + strconv.Itoa(portForGateway) + "/sse"
+
+	reqCtx, reqCancel := context.WithTimeout(ctx, 15*time.Second)
+	defer reqCancel()
+	c, err := mcpClient.New(gwURL, gwURL, LOGGER.With(zap.String("s", "roots-client")))
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	session := c.NewSession(reqCtx, mcpClient.WithAuthenticationBearer("key-roots-user"))
+	defer session.Close()
+
+	list := <-session.ListRoots(reqCtx)
+	if list.Err != nil {
+		t.Fatalf("Failed to list roots: %v", list.Err)
+	}
+	uris := make([]string, 0, len(list.Roots))
+	for _, r := range list.Roots {
+		uris = append(uris, r.URI)
+	}
+	sort.Strings(uris)
+	want := []string{"roots1/file:///workspace/a", "roots1/file:///workspace/b", "roots2/file:///workspace/a", "roots2/file:///workspace/b"}
+	if fmt.Sprint(uris) != fmt.Sprint(want) {
+		t.Fatalf("Aggregated root URIs = %v, want %v", uris, want)
+	}
+}
@@ -7,6 +7,9 @@ import (
 	"time" // Import time
 
 	"github.com/gate4ai/gate4ai/shared"
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 	// Use 2025 schema for request parsing, although structure is same as 2024
 	schema "github.com/gate4ai/gate4ai/shared/mcp/2025/schema"
 	"go.uber.org/zap"
@@ -14,8 +17,20 @@ import (
 
 // gw_tools_call handles the "tools/call" request from the client.
 func (c *GatewayCapability) gw_tools_call(inputMsg *shared.Message) (interface{}, error) {
-	// Use SugaredLogger and add context
-	logger := c.logger.Sugar().With("msgID", inputMsg.ID.String(), "method", "tools/call")
+	if c.metrics == nil {
+		return c.callTool(inputMsg)
+	}
+	start := time.Now()
+	result, err := c.callTool(inputMsg)
+	c.observeToolCall(start, result, err)
+	return result, err
+}
+
+// callTool routes a tools/call request to the backend that has the tool.
+func (c *GatewayCapability) callTool(inputMsg *shared.Message) (interface{}, error) {
+	// Use SugaredLogger and add context; the correlation ID also goes to the backend and back to the client
+	correlationID := uuid.NewString()
+	logger := c.logger.Sugar().With("msgID", inputMsg.ID.String(), "method", "tools/call", "correlationID", correlationID)
 	logger.Debug("Processing request")
 
 	// Parse the input parameters using 2025 schema type
@@ -31,7 +46,7 @@ func (c *GatewayCapability) gw_tools_call(inputMsg *shared.Message) (interface{}
 
 	// Get all tools from the available servers (handles fetching, conflict resolution)
 	// Pass the non-sugared logger to GetTools
-	tools, err := c.GetTools(inputMsg, c.logger.With(zap.String("msgID", inputMsg.ID.String())))
+	tools, err := c.GetTools(inputMsg, c.logger.With(zap.String("msgID", inputMsg.ID.String()), zap.String("correlationID", correlationID)))
 	if err != nil {
 		logger.Errorw("Failed to get tools list", "error", err)
 		return nil, fmt.Errorf("failed to get tools: %w", err)
@@ -55,16 +70,79 @@ func (c *GatewayCapability) gw_tools_call(inputMsg *shared.Message) (interface{}
 		"backendServerID", selectedTool.serverSlug,
 		"originalName", selectedTool.originalName)
 
+	// Server admins can temporarily block destructive tools on a backend
+	blocked, err := c.config.IsDestructiveBlocked(selectedTool.serverSlug)
+	if err != nil {
+		logger.Errorw("Failed to check destructive tool blocking", "serverID", selectedTool.serverSlug, "error", err)
+		return nil, fmt.Errorf("failed to check tool permissions for server %s: %w", selectedTool.serverSlug, err)
+	}
+	if blocked && selectedTool.Annotations != nil && selectedTool.Annotations.DestructiveHint != nil && *selectedTool.Annotations.DestructiveHint {
+		logger.Infow("Blocked destructive tool call", "serverID", selectedTool.serverSlug)
+		return nil, &shared.JSONRPCError{
+			Code:    shared.JSONRPCErrorInvalidRequest,
+			Message: "Destructive tool calls are temporarily blocked for this server",
+		}
+	}
+
+	// A backend with too many recent failures is refused without trying it
+	circuitDone, err := c.acquireCircuit(selectedTool.serverSlug)
+	if err != nil {
+		logger.Warnw("Refusing tool call, backend circuit is open", "serverID", selectedTool.serverSlug)
+		c.recordBackendError(selectedTool.serverSlug, backendErrorCircuitOpen)
+		return nil, err
+	}
+	backendFailed := true // Cleared once the backend has answered
+	defer func() { circuitDone(backendFailed) }()
+
 	// Get the backend session for the server that has this tool
 	backendSession, err := c.getBackendSession(inputMsg.Session, selectedTool.serverSlug)
 	if err != nil {
 		logger.Errorw("Failed to get backend session", "serverID", selectedTool.serverSlug, "error", err)
+		c.recordBackendError(selectedTool.serverSlug, backendErrorSession)
+		if rpcErr, ok := err.(*shared.JSONRPCError); ok {
+			return nil, rpcErr
+		}
 		return nil, fmt.Errorf("failed to get backend session for server %s: %w", selectedTool.serverSlug, err)
 	}
 	if backendSession == nil {
 		logger.Errorw("Backend session is nil after successful retrieval", "serverID", selectedTool.serverSlug)
+		c.recordBackendError(selectedTool.serverSlug, backendErrorSession)
 		return nil, fmt.Errorf("internal error: failed to get valid backend session for server %s", selectedTool.serverSlug)
 	}
+	if reason := GetBackendUnavailable(backendSession.GetParams()); reason != nil {
+		logger.Warnw("Refusing to route tool call to unavailable backend", "serverID", selectedTool.serverSlug, "reason", reason)
+		c.recordBackendError(selectedTool.serverSlug, backendErrorUnavailable)
+		return nil, errBackendUnavailable()
+	}
+
+	// Use a timeout context for the backend call (including the wait for a pooled session)
+	// The request context carries the client's trace; MCP transports never cancel it
+	ctx, cancel := context.WithTimeout(inputMsg.Context(), 30*time.Second) // Timeout for tool execution
+	defer cancel()
+	ctx = shared.ContextWithCorrelationID(ctx, correlationID)
+
+	// With a pool, concurrent calls run on separate backend sessions
+	if c.backendPoolSize > 1 {
+		pool := c.backendSessionPool(inputMsg.Session, selectedTool.serverSlug, backendSession, c.logger.With(zap.String("serverSlug", selectedTool.serverSlug), zap.String("correlationID", correlationID)))
+		pooledSession, err := pool.Acquire(ctx)
+		if rpcErr, ok := err.(*shared.JSONRPCError); ok {
+			logger.Warnw("Refusing to route tool call to unavailable backend", "serverID", selectedTool.serverSlug, "reason", err)
+			c.recordBackendError(selectedTool.serverSlug, backendErrorUnavailable)
+			return nil, rpcErr
+		}
+		if err != nil {
+			logger.Errorw("Failed to acquire pooled backend session", "serverID", selectedTool.serverSlug, "error", err)
+			c.recordBackendError(selectedTool.serverSlug, backendErrorSession)
+			return nil, fmt.Errorf("failed to get backend session for server %s: %w", selectedTool.serverSlug, err)
+		}
+		defer pool.Release(pooledSession)
+		if reason := GetBackendUnavailable(pooledSession.GetParams()); reason != nil {
+			logger.Warnw("Refusing to route tool call to unavailable backend", "serverID", selectedTool.serverSlug, "reason", reason)
+			c.recordBackendError(selectedTool.serverSlug, backendErrorUnavailable)
+			return nil, errBackendUnavailable()
+		}
+		backendSession = pooledSession
+	}
 
 	// Call the tool on the backend using the ORIGINAL tool name
 	toolName := selectedTool.originalName
@@ -72,12 +150,38 @@ func (c *GatewayCapability) gw_tools_call(inputMsg *shared.Message) (interface{}
 	// Arguments are already map[string]interface{} in V2025 params
 	args := params.Arguments
 
-	// Use a timeout context for the backend call
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second) // Timeout for tool execution
-	defer cancel()
-
-	resultChan := backendSession.CallTool(ctx, toolName, args)
+	spanCtx, span := c.tracer().Start(ctx, SpanBackendToolCall, trace.WithSpanKind(trace.SpanKindClient), trace.WithAttributes(
+		attribute.String("server_slug", selectedTool.serverSlug),
+		attribute.String("tool", toolName),
+		attribute.String("correlation_id", correlationID),
+	))
+	resultChan := backendSession.CallTool(spanCtx, toolName, args)
 	result := <-resultChan // Wait for the result from the backend
+	setSpanError(span, result.Error)
+	span.End()
+	// Connection and RPC errors leave no result; tool errors and unparsable results still mean the backend answered
+	backendFailed = result.Error != nil && result.Result == nil && result.Raw == nil
+
+	// Normalize successful results and results the client could not parse; tool errors keep their shape
+	if c.resultNormalizer != nil && result.Raw != nil && (result.Error == nil || result.Result == nil) {
+		content, err := c.resultNormalizer.Normalize(result.Raw)
+		if err != nil {
+			logger.Errorw("Failed to normalize tool result from backend",
+				"server", selectedTool.serverSlug,
+				"tool", toolName,
+				"error", err)
+			c.recordBackendError(selectedTool.serverSlug, backendErrorNormalize)
+			return nil, fmt.Errorf("failed to call tool '%s' on backend: %w", toolName, err)
+		}
+		normalized := &schema.CallToolResult{Content: content}
+		var flags struct {
+			IsError bool `json:"isError"`
+		}
+		if json.Unmarshal(result.Raw, &flags) == nil {
+			normalized.IsError = flags.IsError
+		}
+		result.Result, result.Error = normalized, nil
+	}
 
 	// Handle the result (CallToolResult uses 2025 schema)
 	if result.Error != nil {
@@ -86,6 +190,7 @@ func (c *GatewayCapability) gw_tools_call(inputMsg *shared.Message) (interface{}
 			"server", selectedTool.serverSlug,
 			"tool", toolName,
 			"error", result.Error)
+		c.recordBackendError(selectedTool.serverSlug, backendErrorCall)
 		// Return the error received from the client call wrapper
 		return nil, fmt.Errorf("failed to call tool '%s' on backend: %w", toolName, result.Error)
 	}
@@ -94,14 +199,22 @@ func (c *GatewayCapability) gw_tools_call(inputMsg *shared.Message) (interface{}
 		// Should not happen if Error is nil, but check defensively
 		err := fmt.Errorf("nil result received from backend %s for tool '%s'", selectedTool.serverSlug, toolName)
 		logger.Errorw(err.Error())
+		c.recordBackendError(selectedTool.serverSlug, backendErrorCall)
 		return nil, err
 	}
 
+	// Let clients log the correlation ID too
+	if result.Result.Meta == nil {
+		result.Result.Meta = &schema.Meta{}
+	}
+	(*result.Result.Meta)[shared.CorrelationIDMetaKey] = correlationID
+
 	// Check IsError flag within the result from the backend
 	if result.Result.IsError {
 		logger.Warnw("Tool call succeeded but backend reported tool error",
 			"server", selectedTool.serverSlug,
 			"tool", toolName)
+		c.recordBackendError(selectedTool.serverSlug, backendErrorTool)
 		// Return the result structure which indicates IsError=true
 		// The error message itself is typically within the Content field in this case.
 		return result.Result, nil // Return the result containing IsError=true
@@ -0,0 +1,472 @@
+package capability_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/http/httputil"
+	"net/url"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gate4ai/gate4ai/gateway"
+	"github.com/gate4ai/gate4ai/gateway/clients/mcpClient"
+	"github.com/gate4ai/gate4ai/server"
+	mcpCapability "github.com/gate4ai/gate4ai/server/mcp/capability"
+	"github.com/gate4ai/gate4ai/server/transport"
+	"github.com/gate4ai/gate4ai/shared"
+	"github.com/gate4ai/gate4ai/shared/config"
+	"github.com/gate4ai/gate4ai/shared/mcp/2025/schema"
+	"github.com/gate4ai/gate4ai/tests"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// startDestructiveToolServer runs an MCP server with one destructive and one read-only tool.
+func startDestructiveToolServer(t *testing.T, ctx context.Context) string {
+	port, err := tests.FindAvailablePort()
+	if err != nil {
+		t.Fatalf("Failed to find available port: %v", err)
+	}
+	cfg := config.NewInternalConfig()
+	cfg.UserKeyHashes[config.HashAPIKey("gateway")] = "gw"
+
+	handler := func(msg *shared.Message, arguments schema.Arguments) (*schema.Meta, []schema.Content, error) {
+		return nil, schema.NewTextContent("done"), nil
+	}
+	options := []server.ServerOption{
+		server.WithListenAddr(fmt.Sprintf(":%d", port)),
+		server.WithMCPTool("dropTable", "Drops a table", nil, &schema.ToolAnnotations{DestructiveHint: shared.PointerTo(true)}, handler),
+		server.WithMCPTool("listTables", "Lists tables", nil, &schema.ToolAnnotations{ReadOnlyHint: shared.PointerTo(true), DestructiveHint: shared.PointerTo(false)}, handler),
+	}
+	_, err = server.Start(ctx, LOGGER.With(zap.String("s", "destructive-server")), cfg, options...)
+	if err != nil {
+		t.Fatalf("Failed to start server: %v", err)
+	}
+	waitForPort(t, port)
+	return "http://localhost:" + strconv.Itoa(port) + "/sse?key=gateway"
+}
+
+func TestDestructiveToolsBlockedPerBackend(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	portForGateway, err := tests.FindAvailablePort()
+	if err != nil {
+		t.Fatalf("Failed to find available port: %v", err)
+	}
+	cfgGw := config.NewInternalConfig()
+	cfgGw.UserKeyHashes[config.HashAPIKey("key-destructive-user")] = "destructive-user"
+	cfgGw.Backends["db"] = &config.Backend{URL: startDestructiveToolServer(t, ctx), BlockDestructiveTools: true}
+	cfgGw.UserSubscribes["destructive-user"] = []string{"db"}
+	_, err = gateway.Start(ctx, LOGGER.With(zap.String("s", "destructive-gateway")), cfgGw, fmt.Sprintf(":%d", portForGateway))
+	if err != nil {
+		t.Fatalf("Failed to start gateway: %v", err)
+	}
+	waitForPort(t, portForGateway)
+	gwURL := "http://localhost:" + strconv.Itoa(portForGateway) + "/sse"
+
+	reqCtx, reqCancel := context.WithTimeout(ctx, 15*time.Second)
+	defer reqCancel()
+	c, err := mcpClient.New(gwURL, gwURL, LOGGER.With(zap.String("s", "destructive-client")))
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	session := c.NewSession(reqCtx, mcpClient.WithAuthenticationBearer("key-destructive-user"))
+	defer session.Close()
+	if err := <-session.Open(); err != nil {
+		t.Fatalf("Failed to open session: %v", err)
+	}
+
+	allowed := <-session.CallTool(reqCtx, "listTables", nil)
+	if allowed.Error != nil {
+		t.Fatalf("Non-destructive tool call failed: %v", allowed.Error)
+	}
+
+	blocked := <-session.CallTool(reqCtx, "dropTable", nil)
+	var rpcErr *shared.JSONRPCError
+	if !errors.As(blocked.Error, &rpcErr) {
+		t.Fatalf("Expected JSON-RPC error for destructive tool, got %v", blocked.Error)
+	}
+	if rpcErr.Code != shared.JSONRPCErrorInvalidRequest || rpcErr.Message != "Destructive tool calls are temporarily blocked for this server" {
+		t.Fatalf("Unexpected error for destructive tool: %d %s", rpcErr.Code, rpcErr.Message)
+	}
+
+	// Unblocking the backend lets the destructive tool through
+	if err := cfgGw.SetDestructiveBlocked("db", false); err != nil {
+		t.Fatalf("Failed to unblock backend: %v", err)
+	}
+	unblocked := <-session.CallTool(reqCtx, "dropTable", nil)
+	if unblocked.Error != nil {
+		t.Fatalf("Destructive tool call failed after unblocking: %v", unblocked.Error)
+	}
+}
+
+func TestToolCallRefusedForUnhealthyBackend(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// The backend works, but a proxy in front of it reports it unhealthy
+	backendURL, err := url.Parse(startDestructiveToolServer(t, ctx))
+	if err != nil {
+		t.Fatalf("Failed to parse backend URL: %v", err)
+	}
+	proxy := httputil.NewSingleHostReverseProxy(&url.URL{Scheme: backendURL.Scheme, Host: backendURL.Host})
+	proxy.FlushInterval = -1 // Forward SSE events immediately
+	var healthProbes atomic.Int32
+	mux := http.NewServeMux()
+	mux.HandleFunc("/health", func(w http.ResponseWriter, r *http.Request) {
+		healthProbes.Add(1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	})
+	mux.Handle("/", proxy)
+	unhealthy := httptest.NewServer(mux)
+	defer func() {
+		unhealthy.CloseClientConnections() // The gateway keeps an SSE stream open through the proxy
+		unhealthy.Close()
+	}()
+
+	portForGateway, err := tests.FindAvailablePort()
+	if err != nil {
+		t.Fatalf("Failed to find available port: %v", err)
+	}
+	cfgGw := config.NewInternalConfig()
+	cfgGw.UserKeyHashes[config.HashAPIKey("key-unhealthy-user")] = "unhealthy-user"
+	cfgGw.Backends["db"] = &config.Backend{URL: unhealthy.URL + backendURL.RequestURI()}
+	cfgGw.UserSubscribes["unhealthy-user"] = []string{"db"}
+	_, err = gateway.Start(ctx, LOGGER.With(zap.String("s", "unhealthy-gateway")), cfgGw, fmt.Sprintf(":%d", portForGateway))
+	if err != nil {
+		t.Fatalf("Failed to start gateway: %v", err)
+	}
+	waitForPort(t, portForGateway)
+	gwURL := "http://localhost:" + strconv.Itoa(portForGateway) + "/sse"
+
+	reqCtx, reqCancel := context.WithTimeout(ctx, 15*time.Second)
+	defer reqCancel()
+	c, err := mcpClient.New(gwURL, gwURL, LOGGER.With(zap.String("s", "unhealthy-client")))
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	session := c.NewSession(reqCtx, mcpClient.WithAuthenticationBearer("key-unhealthy-user"))
+	defer session.Close()
+	if err := <-session.Open(); err != nil {
+		t.Fatalf("Failed to open session: %v", err)
+	}
+
+	refused := <-session.CallTool(reqCtx, "listTables", nil)
+	var rpcErr *shared.JSONRPCError
+	if !errors.As(refused.Error, &rpcErr) {
+		t.Fatalf("Expected JSON-RPC error for unhealthy backend, got result %+v, error %v", refused.Result, refused.Error)
+	}
+	if rpcErr.Code != shared.JSONRPCErrorServerError || rpcErr.Message != "Backend unavailable" {
+		t.Fatalf("Unexpected error for unhealthy backend: %d %s", rpcErr.Code, rpcErr.Message)
+	}
+	if data, ok := rpcErr.Data.(map[string]interface{}); !ok || data["status"] != float64(http.StatusServiceUnavailable) {
+		t.Errorf("Expected status 503 in error data, got %v", rpcErr.Data)
+	}
+	if healthProbes.Load() == 0 {
+		t.Errorf("Expected the gateway to probe the backend health endpoint")
+	}
+}
+
+func TestToolCallsUseBackendSessionPool(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// The backend records which session served each call and how many calls overlap
+	var (
+		mu            sync.Mutex
+		sessionIDs    = make(map[string]bool)
+		inFlight, max int
+	)
+	handler := func(msg *shared.Message, arguments schema.Arguments) (*schema.Meta, []schema.Content, error) {
+		mu.Lock()
+		sessionIDs[msg.Session.GetID()] = true
+		inFlight++
+		if inFlight > max {
+			max = inFlight
+		}
+		mu.Unlock()
+		time.Sleep(200 * time.Millisecond)
+		mu.Lock()
+		inFlight--
+		mu.Unlock()
+		return nil, schema.NewTextContent(fmt.Sprintf("echo %v", arguments["n"])), nil
+	}
+	backendPort, err := tests.FindAvailablePort()
+	if err != nil {
+		t.Fatalf("Failed to find available port: %v", err)
+	}
+	cfgBackend := config.NewInternalConfig()
+	cfgBackend.UserKeyHashes[config.HashAPIKey("gateway")] = "gw"
+	_, err = server.Start(ctx, LOGGER.With(zap.String("s", "pool-server")), cfgBackend,
+		server.WithListenAddr(fmt.Sprintf(":%d", backendPort)),
+		server.WithMCPTool("slowEcho", "Echoes n after a delay", nil, nil, handler))
+	if err != nil {
+		t.Fatalf("Failed to start server: %v", err)
+	}
+	waitForPort(t, backendPort)
+
+	portForGateway, err := tests.FindAvailablePort()
+	if err != nil {
+		t.Fatalf("Failed to find available port: %v", err)
+	}
+	cfgGw := config.NewInternalConfig()
+	cfgGw.UserKeyHashes[config.HashAPIKey("key-pool-user")] = "pool-user"
+	cfgGw.Backends["slow"] = &config.Backend{URL: "http://localhost:" + strconv.Itoa(backendPort) + "/sse?key=gateway"}
+	cfgGw.UserSubscribes["pool-user"] = []string{"slow"}
+	_, err = gateway.Start(ctx, LOGGER.With(zap.String("s", "pool-gateway")), cfgGw, fmt.Sprintf(":%d", portForGateway), gateway.WithBackendPoolSize(3))
+	if err != nil {
+		t.Fatalf("Failed to start gateway: %v", err)
+	}
+	waitForPort(t, portForGateway)
+	gwURL := "http://localhost:" + strconv.Itoa(portForGateway) + "/sse"
+
+	reqCtx, reqCancel := context.WithTimeout(ctx, 15*time.Second)
+	defer reqCancel()
+	c, err := mcpClient.New(gwURL, gwURL, LOGGER.With(zap.String("s", "pool-client")))
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	session := c.NewSession(reqCtx, mcpClient.WithAuthenticationBearer("key-pool-user"))
+	defer session.Close()
+	if err := <-session.Open(); err != nil {
+		t.Fatalf("Failed to open session: %v", err)
+	}
+
+	const calls = 10
+	results := make([]mcpClient.CallToolResult, calls)
+	var wg sync.WaitGroup
+	for i := 0; i < calls; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			results[i] = <-session.CallTool(reqCtx, "slowEcho", map[string]interface{}{"n": i})
+		}(i)
+	}
+	wg.Wait()
+
+	for i, result := range results {
+		if result.Error != nil {
+			t.Fatalf("Call %d failed: %v", i, result.Error)
+		}
+		if len(result.Result.Content) != 1 || result.Result.Content[0].Text == nil || *result.Result.Content[0].Text != fmt.Sprintf("echo %d", i) {
+			t.Errorf("Call %d returned unexpected content: %+v", i, result.Result.Content)
+		}
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if len(sessionIDs) != 3 {
+		t.Errorf("Expected calls to be served by exactly 3 backend sessions, got %d", len(sessionIDs))
+	}
+	if max > 3 {
+		t.Errorf("Expected at most 3 concurrent backend calls, got %d", max)
+	}
+}
+
+// echoToolBackend offers one tool that answers "ok".
+type echoToolBackend struct{}
+
+func (b *echoToolBackend) GetHandlers() map[string]func(*shared.Message) (interface{}, error) {
+	return map[string]func(*shared.Message) (interface{}, error){
+		"tools/list": func(msg *shared.Message) (interface{}, error) {
+			return schema.ListToolsResult{Tools: []schema.Tool{{Name: "echo", InputSchema: &schema.JSONSchemaProperty{Type: "object"}}}}, nil
+		},
+		"tools/call": func(msg *shared.Message) (interface{}, error) {
+			return schema.CallToolResult{Content: schema.NewTextContent("ok")}, nil
+		},
+	}
+}
+
+func (b *echoToolBackend) SetCapabilities(s *schema.ServerCapabilities) {
+	s.Tools = &schema.Capability{}
+}
+
+func (b *echoToolBackend) SetEventBus(bus shared.EventBus) {}
+
+func TestToolCallCorrelationID(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// The backend records the correlation IDs of the tools/call requests it receives
+	logger := LOGGER.With(zap.String("s", "correlation-backend"))
+	cfg := config.NewInternalConfig()
+	cfg.UserKeyHashes[config.HashAPIKey("gateway")] = "gw"
+	manager, err := transport.NewManager(logger, cfg)
+	if err != nil {
+		t.Fatalf("Failed to create manager: %v", err)
+	}
+	tr, err := transport.New(manager, logger, cfg)
+	if err != nil {
+		t.Fatalf("Failed to create transport: %v", err)
+	}
+	manager.AddCapability(mcpCapability.NewBase(logger, manager), &echoToolBackend{})
+	mux := http.NewServeMux()
+	tr.RegisterMCPHandlers(mux)
+	mux.HandleFunc("/health", func(w http.ResponseWriter, r *http.Request) {}) // The gateway only routes to backends answering 2xx
+	backendIDs := make(chan string, 10)
+	backend := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost {
+			body, _ := io.ReadAll(r.Body)
+			r.Body = io.NopCloser(bytes.NewReader(body))
+			if bytes.Contains(body, []byte(`"tools/call"`)) {
+				backendIDs <- r.Header.Get(shared.CorrelationIDHeader)
+			}
+		}
+		mux.ServeHTTP(w, r)
+	}))
+	defer func() {
+		backend.CloseClientConnections() // The gateway keeps its SSE stream open
+		backend.Close()
+	}()
+
+	portForGateway, err := tests.FindAvailablePort()
+	if err != nil {
+		t.Fatalf("Failed to find available port: %v", err)
+	}
+	cfgGw := config.NewInternalConfig()
+	cfgGw.UserKeyHashes[config.HashAPIKey("key-correlation-user")] = "correlation-user"
+	cfgGw.Backends["echo"] = &config.Backend{URL: backend.URL + "/sse?key=gateway"}
+	cfgGw.UserSubscribes["correlation-user"] = []string{"echo"}
+	_, err = gateway.Start(ctx, LOGGER.With(zap.String("s", "correlation-gateway")), cfgGw, fmt.Sprintf(":%d", portForGateway))
+	if err != nil {
+		t.Fatalf("Failed to start gateway: %v", err)
+	}
+	waitForPort(t, portForGateway)
+	gwURL := "http://localhost:" + strconv.Itoa(portForGateway) + "/sse"
+
+	reqCtx, reqCancel := context.WithTimeout(ctx, 15*time.Second)
+	defer reqCancel()
+	c, err := mcpClient.New(gwURL, gwURL, LOGGER.With(zap.String("s", "correlation-client")))
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	session := c.NewSession(reqCtx, mcpClient.WithAuthenticationBearer("key-correlation-user"))
+	defer session.Close()
+	if err := <-session.Open(); err != nil {
+		t.Fatalf("Failed to open session: %v", err)
+	}
+
+	seen := map[string]bool{}
+	for i := 0; i < 2; i++ {
+		result := <-session.CallTool(reqCtx, "echo", nil)
+		if result.Error != nil {
+			t.Fatalf("Tool call failed: %v", result.Error)
+		}
+		if result.Result.Meta == nil {
+			t.Fatal("Tool call result has no _meta")
+		}
+		correlationID, _ := (*result.Result.Meta)[shared.CorrelationIDMetaKey].(string)
+		if _, err := uuid.Parse(correlationID); err != nil {
+			t.Fatalf("Expected a UUID correlation ID in _meta, got %q", correlationID)
+		}
+		select {
+		case backendID := <-backendIDs:
+			if backendID != correlationID {
+				t.Fatalf("Backend received correlation ID %q, client got %q", backendID, correlationID)
+			}
+		case <-reqCtx.Done():
+			t.Fatal("Backend did not receive the tools/call request")
+		}
+		if seen[correlationID] {
+			t.Fatalf("Correlation ID %s was reused", correlationID)
+		}
+		seen[correlationID] = true
+	}
+}
+
+func TestToolCallCircuitBreaker(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// A proxy in front of a working backend fails the health probe until it is switched back
+	backendURL, err := url.Parse(startDestructiveToolServer(t, ctx))
+	if err != nil {
+		t.Fatalf("Failed to parse backend URL: %v", err)
+	}
+	proxy := httputil.NewSingleHostReverseProxy(&url.URL{Scheme: backendURL.Scheme, Host: backendURL.Host})
+	proxy.FlushInterval = -1 // Forward SSE events immediately
+	var healthy atomic.Bool
+	mux := http.NewServeMux()
+	mux.HandleFunc("/health", func(w http.ResponseWriter, r *http.Request) {
+		if !healthy.Load() {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	})
+	mux.Handle("/", proxy)
+	flaky := httptest.NewServer(mux)
+	defer func() {
+		flaky.CloseClientConnections()
+		flaky.Close()
+	}()
+
+	portForGateway, err := tests.FindAvailablePort()
+	if err != nil {
+		t.Fatalf("Failed to find available port: %v", err)
+	}
+	cfgGw := config.NewInternalConfig()
+	cfgGw.UserKeyHashes[config.HashAPIKey("key-breaker-user")] = "breaker-user"
+	cfgGw.Backends["db"] = &config.Backend{URL: flaky.URL + backendURL.RequestURI()}
+	cfgGw.UserSubscribes["breaker-user"] = []string{"db"}
+	// The open timeout outlasts the backend session cache, so the probe gets a freshly checked session
+	openTimeout := 6 * time.Second
+	_, err = gateway.Start(ctx, LOGGER.With(zap.String("s", "breaker-gateway")), cfgGw, fmt.Sprintf(":%d", portForGateway), gateway.WithCircuitBreaker(2, openTimeout))
+	if err != nil {
+		t.Fatalf("Failed to start gateway: %v", err)
+	}
+	waitForPort(t, portForGateway)
+	gwURL := "http://localhost:" + strconv.Itoa(portForGateway) + "/sse"
+
+	reqCtx, reqCancel := context.WithTimeout(ctx, 30*time.Second)
+	defer reqCancel()
+	c, err := mcpClient.New(gwURL, gwURL, LOGGER.With(zap.String("s", "breaker-client")))
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	session := c.NewSession(reqCtx, mcpClient.WithAuthenticationBearer("key-breaker-user"))
+	defer session.Close()
+	if err := <-session.Open(); err != nil {
+		t.Fatalf("Failed to open session: %v", err)
+	}
+
+	callCode := func() int {
+		result := <-session.CallTool(reqCtx, "listTables", nil)
+		if result.Error == nil {
+			return 0
+		}
+		var rpcErr *shared.JSONRPCError
+		if !errors.As(result.Error, &rpcErr) {
+			t.Fatalf("Expected JSON-RPC error, got %v", result.Error)
+		}
+		return rpcErr.Code
+	}
+
+	// Closed: failures reach the backend until the threshold
+	for i := 0; i < 2; i++ {
+		if code := callCode(); code != shared.JSONRPCErrorServerError {
+			t.Fatalf("Call %d: expected backend unavailable error, got code %d", i+1, code)
+		}
+	}
+
+	// Open: calls fail fast even though the backend has recovered
+	healthy.Store(true)
+	if code := callCode(); code != shared.JSONRPCErrorCircuitOpen {
+		t.Fatalf("Expected circuit open error, got code %d", code)
+	}
+
+	// HalfOpen: after the timeout the probe succeeds and closes the circuit
+	time.Sleep(openTimeout)
+	if code := callCode(); code != 0 {
+		t.Fatalf("Probe call failed with code %d", code)
+	}
+	if code := callCode(); code != 0 {
+		t.Fatalf("Call after the circuit closed failed with code %d", code)
+	}
+}
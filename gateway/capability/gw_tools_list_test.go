@@ -1,8 +1,19 @@
 package capability_test
 
 import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
 	"testing"
+	"time"
 
+	"github.com/gate4ai/gate4ai/gateway"
+	"github.com/gate4ai/gate4ai/gateway/clients/mcpClient"
+	"github.com/gate4ai/gate4ai/server"
+	"github.com/gate4ai/gate4ai/shared"
+	"github.com/gate4ai/gate4ai/shared/config"
+	"github.com/gate4ai/gate4ai/shared/mcp/2025/schema"
 	"github.com/gate4ai/gate4ai/tests"
 	"go.uber.org/zap"
 )
@@ -13,7 +24,7 @@ func TestGetToolsList(t *testing.T) {
 		t.Fatalf("Failed to get tools list: %v", err)
 	}
 	t.Logf("user1 tools list: %v", list)
-	if len(list) != 7 {
+	if len(list) != 8 {
 		t.Fatalf("No tools found")
 	}
 	list, err = tests.GetToolsList(GW_URL, "key-user2", LOGGER.With(zap.String("s", "TestGetToolsList2")))
@@ -21,7 +32,7 @@ func TestGetToolsList(t *testing.T) {
 		t.Fatalf("Failed to get tools list: %v", err)
 	}
 	t.Logf("user2 tools list: %v", list)
-	if len(list) != 7 {
+	if len(list) != 8 {
 		t.Fatalf("No tools found")
 	}
 	list, err = tests.GetToolsList(GW_URL, "key-user3", LOGGER.With(zap.String("s", "TestGetToolsList3")))
@@ -29,7 +40,72 @@ func TestGetToolsList(t *testing.T) {
 		t.Fatalf("Failed to get tools list: %v", err)
 	}
 	t.Logf("user3 tools list: %v", list)
-	if len(list) != 14 {
+	if len(list) != 16 {
 		t.Fatalf("No tools found")
 	}
 }
+
+func TestToolsListMergesPaginatedBackend(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// The backend serves its 45 tools in pages of 20
+	backendPort, err := tests.FindAvailablePort()
+	if err != nil {
+		t.Fatalf("Failed to find available port: %v", err)
+	}
+	cfgBackend := config.NewInternalConfig()
+	cfgBackend.UserKeyHashes[config.HashAPIKey("gateway")] = "gw"
+	handler := func(msg *shared.Message, arguments schema.Arguments) (*schema.Meta, []schema.Content, error) {
+		return nil, nil, nil
+	}
+	options := []server.ServerOption{server.WithListenAddr(fmt.Sprintf(":%d", backendPort)), server.WithMCPToolsPageSize(20)}
+	var want []string
+	for i := 0; i < 45; i++ {
+		name := fmt.Sprintf("paged-%02d", i)
+		want = append(want, name)
+		options = append(options, server.WithMCPTool(name, "", nil, nil, handler))
+	}
+	_, err = server.Start(ctx, LOGGER.With(zap.String("s", "paged-server")), cfgBackend, options...)
+	if err != nil {
+		t.Fatalf("Failed to start server: %v", err)
+	}
+	waitForPort(t, backendPort)
+
+	portForGateway, err := tests.FindAvailablePort()
+	if err != nil {
+		t.Fatalf("Failed to find available port: %v", err)
+	}
+	cfgGw := config.NewInternalConfig()
+	cfgGw.UserKeyHashes[config.HashAPIKey("key-paged-user")] = "paged-user"
+	cfgGw.Backends["paged"] = &config.Backend{URL: "http://localhost:" + strconv.Itoa(backendPort) + "/sse?key=gateway"}
+	cfgGw.UserSubscribes["paged-user"] = []string{"paged"}
+	_, err = gateway.Start(ctx, LOGGER.With(zap.String("s", "paged-gateway")), cfgGw, fmt.Sprintf(":%d", portForGateway))
+	if err != nil {
+		t.Fatalf("Failed to start gateway: %v", err)
+	}
+	waitForPort(t, portForGateway)
+	gwURL := "http://localhost:" + strconv.Itoa(portForGateway) + "/sse"
+
+	reqCtx, reqCancel := context.WithTimeout(ctx, 15*time.Second)
+	defer reqCancel()
+	c, err := mcpClient.New(gwURL, gwURL, LOGGER.With(zap.String("s", "paged-client")))
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	session := c.NewSession(reqCtx, mcpClient.WithAuthenticationBearer("key-paged-user"))
+	defer session.Close()
+
+	list := <-session.GetTools(reqCtx)
+	if list.Err != nil {
+		t.Fatalf("Failed to list tools: %v", list.Err)
+	}
+	names := make([]string, 0, len(list.Tools))
+	for _, tool := range list.Tools {
+		names = append(names, tool.Name)
+	}
+	sort.Strings(names)
+	if fmt.Sprint(names) != fmt.Sprint(want) {
+		t.Fatalf("Gateway tools = %v, want all %d backend tools %v", names, len(want), want)
+	}
+}
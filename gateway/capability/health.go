@@ -0,0 +1,70 @@
+package capability
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/gate4ai/gate4ai/shared"
+)
+
+const (
+	defaultBackendHealthPath = "health"
+	backendHealthTimeout     = 2 * time.Second
+)
+
+// errBackendUnavailable is returned to clients instead of routing to a backend that failed its health
+// probe or initialization.
+func errBackendUnavailable() *shared.JSONRPCError {
+	return &shared.JSONRPCError{
+		Code:    shared.JSONRPCErrorServerError,
+		Message: "Backend unavailable",
+		Data:    map[string]interface{}{"error": "Backend unavailable", "status": http.StatusServiceUnavailable},
+	}
+}
+
+// backendHealthURL resolves healthPath against the backend URL like a link in a page at that URL:
+// for https://host/prefix/mcp, "health" is https://host/prefix/health and "/health" is
+// https://host/health.
+func backendHealthURL(backendURL string, healthPath string) (string, error) {
+	base, err := url.Parse(backendURL)
+	if err != nil {
+		return "", fmt.Errorf("invalid backend URL: %w", err)
+	}
+	if healthPath == "" {
+		healthPath = defaultBackendHealthPath
+	}
+	ref, err := url.Parse(healthPath)
+	if err != nil {
+		return "", fmt.Errorf("invalid health path: %w", err)
+	}
+	return base.ResolveReference(ref).String(), nil
+}
+
+// probeBackendHealth sends GET to the health endpoint of the backend (see backendHealthURL). Only
+// 2xx responses make the backend healthy; connection errors and other statuses, including a 404
+// from a missing endpoint, make it unhealthy.
+func probeBackendHealth(ctx context.Context, httpClient *http.Client, backendURL string, healthPath string) error {
+	healthURL, err := backendHealthURL(backendURL, healthPath)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, backendHealthTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, healthURL, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("health probe failed: %w", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("health probe returned HTTP %d", resp.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,61 @@
+package capability
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestBackendHealthURL(t *testing.T) {
+	tests := []struct {
+		backendURL string
+		healthPath string
+		want       string
+	}{
+		{"https://host/mcp", "", "https://host/health"},
+		{"https://host/prefix/mcp", "", "https://host/prefix/health"},
+		{"https://host/prefix/", "", "https://host/prefix/health"},
+		{"https://host/prefix/mcp?key=1", "", "https://host/prefix/health"},
+		{"https://host/prefix/mcp", "/health", "https://host/health"},
+		{"https://host/prefix/mcp", "status/live", "https://host/prefix/status/live"},
+		{"https://host/prefix/mcp", "../ready", "https://host/ready"},
+	}
+	for _, tt := range tests {
+		got, err := backendHealthURL(tt.backendURL, tt.healthPath)
+		if err != nil {
+			t.Errorf("backendHealthURL(%q, %q) failed: %v", tt.backendURL, tt.healthPath, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("backendHealthURL(%q, %q) = %q, want %q", tt.backendURL, tt.healthPath, got, tt.want)
+		}
+	}
+}
+
+func TestProbeBackendHealthRequires2xx(t *testing.T) {
+	for _, tt := range []struct {
+		status  int
+		healthy bool
+	}{
+		{http.StatusOK, true},
+		{http.StatusNoContent, true},
+		{http.StatusNotFound, false},
+		{http.StatusUnauthorized, false},
+		{http.StatusServiceUnavailable, false},
+	} {
+		var probedPath string
+		backend := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			probedPath = r.URL.Path
+			w.WriteHeader(tt.status)
+		}))
+		err := probeBackendHealth(context.Background(), backend.Client(), backend.URL+"/prefix/mcp", "")
+		backend.Close()
+		if (err == nil) != tt.healthy {
+			t.Errorf("HTTP %d: got error %v, want healthy %v", tt.status, err, tt.healthy)
+		}
+		if probedPath != "/prefix/health" {
+			t.Errorf("HTTP %d: probed %q, want /prefix/health", tt.status, probedPath)
+		}
+	}
+}
@@ -0,0 +1,53 @@
+package capability
+
+import (
+	"time"
+
+	schema "github.com/gate4ai/gate4ai/shared/mcp/2025/schema"
+)
+
+// MetricsRecorder receives the measurements of tools/call. *metrics.Metrics of the server
+// module implements it.
+type MetricsRecorder interface {
+	ObserveRequest(method string, status string, duration time.Duration)
+	BackendError(serverSlug string, errorType string)
+}
+
+// Request statuses and backend error types passed to MetricsRecorder.
+const (
+	metricsStatusOK        = "ok"
+	metricsStatusError     = "error"
+	metricsStatusToolError = "tool_error"
+
+	backendErrorSession     = "session"      // No backend session could be obtained
+	backendErrorUnavailable = "unavailable"  // The backend is marked unavailable
+	backendErrorCall        = "call"         // The call failed or returned no result
+	backendErrorNormalize   = "normalize"    // The result could not be normalized
+	backendErrorTool        = "tool_error"   // The backend reported a tool error
+	backendErrorCircuitOpen = "circuit_open" // The circuit breaker of the backend is open
+)
+
+// WithMetrics makes tools/call report its requests and backend errors to recorder.
+func WithMetrics(recorder MetricsRecorder) GatewayOption {
+	return func(c *GatewayCapability) {
+		c.metrics = recorder
+	}
+}
+
+// observeToolCall reports a finished tools/call request.
+func (c *GatewayCapability) observeToolCall(start time.Time, result interface{}, err error) {
+	status := metricsStatusOK
+	if err != nil {
+		status = metricsStatusError
+	} else if callResult, ok := result.(*schema.CallToolResult); ok && callResult.IsError {
+		status = metricsStatusToolError
+	}
+	c.metrics.ObserveRequest("tools/call", status, time.Since(start))
+}
+
+// recordBackendError reports a failed backend call, if metrics are enabled.
+func (c *GatewayCapability) recordBackendError(serverSlug string, errorType string) {
+	if c.metrics != nil {
+		c.metrics.BackendError(serverSlug, errorType)
+	}
+}
@@ -0,0 +1,92 @@
+package capability
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	schema "github.com/gate4ai/gate4ai/shared/mcp/2025/schema"
+)
+
+// ResultNormalizer converts the raw tools/call result of a backend into content blocks.
+type ResultNormalizer interface {
+	Normalize(raw json.RawMessage) ([]schema.Content, error)
+}
+
+// WithResultNormalizer makes tools/call pass backend results through normalizer, so
+// backends answering in non-standard shapes still produce a valid CallToolResult.
+func WithResultNormalizer(normalizer ResultNormalizer) GatewayOption {
+	return func(c *GatewayCapability) {
+		c.resultNormalizer = normalizer
+	}
+}
+
+// LenientResultNormalizer accepts the result shapes of legacy MCP backends: a plain string
+// result, a single content object instead of an array, and content without a type, which
+// defaults to "text". Standard results pass through unchanged.
+type LenientResultNormalizer struct{}
+
+var _ ResultNormalizer = LenientResultNormalizer{}
+
+// Normalize implements ResultNormalizer.
+func (LenientResultNormalizer) Normalize(raw json.RawMessage) ([]schema.Content, error) {
+	raw = bytes.TrimSpace(raw)
+	if len(raw) == 0 || bytes.Equal(raw, []byte("null")) {
+		return nil, errors.New("empty tool result")
+	}
+	if raw[0] == '"' {
+		return normalizeContent(raw)
+	}
+	var result struct {
+		Content json.RawMessage `json:"content"`
+	}
+	if err := json.Unmarshal(raw, &result); err != nil {
+		return nil, fmt.Errorf("unsupported tool result: %w", err)
+	}
+	if len(result.Content) == 0 {
+		return nil, errors.New("tool result has no content")
+	}
+	return normalizeContent(result.Content)
+}
+
+// normalizeContent decodes a content value that is an array, a single object or a string.
+func normalizeContent(raw json.RawMessage) ([]schema.Content, error) {
+	switch raw[0] {
+	case '[':
+		var items []json.RawMessage
+		if err := json.Unmarshal(raw, &items); err != nil {
+			return nil, fmt.Errorf("invalid content array: %w", err)
+		}
+		contents := make([]schema.Content, 0, len(items))
+		for i, item := range items {
+			item = bytes.TrimSpace(item)
+			if len(item) == 0 || item[0] == '[' {
+				return nil, fmt.Errorf("invalid content item %d", i)
+			}
+			content, err := normalizeContent(item)
+			if err != nil {
+				return nil, fmt.Errorf("content item %d: %w", i, err)
+			}
+			contents = append(contents, content...)
+		}
+		return contents, nil
+	case '"':
+		var text string
+		if err := json.Unmarshal(raw, &text); err != nil {
+			return nil, fmt.Errorf("invalid text content: %w", err)
+		}
+		return schema.NewTextContent(text), nil
+	case '{':
+		var content schema.Content
+		if err := json.Unmarshal(raw, &content); err != nil {
+			return nil, fmt.Errorf("invalid content object: %w", err)
+		}
+		if content.Type == "" {
+			content.Type = "text"
+		}
+		return []schema.Content{content}, nil
+	default:
+		return nil, fmt.Errorf("unsupported content value: %s", raw)
+	}
+}
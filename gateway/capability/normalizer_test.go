@@ -0,0 +1,129 @@
+package capability_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/gate4ai/gate4ai/gateway"
+	"github.com/gate4ai/gate4ai/gateway/capability"
+	"github.com/gate4ai/gate4ai/gateway/clients/mcpClient"
+	mcpCapability "github.com/gate4ai/gate4ai/server/mcp/capability"
+	"github.com/gate4ai/gate4ai/server/transport"
+	"github.com/gate4ai/gate4ai/shared"
+	"github.com/gate4ai/gate4ai/shared/config"
+	"github.com/gate4ai/gate4ai/shared/mcp/2025/schema"
+	"github.com/gate4ai/gate4ai/tests"
+	"go.uber.org/zap"
+)
+
+// legacyResults maps tool names to the raw tools/call results of a legacy backend.
+var legacyResults = map[string]string{
+	"stringResult":  `"plain text"`,
+	"objectContent": `{"content": {"type": "text", "text": "plain text"}}`,
+	"untypedItems":  `{"content": [{"text": "plain text"}]}`,
+}
+
+// legacyToolsBackend answers tools/call with the non-standard results in legacyResults.
+type legacyToolsBackend struct{}
+
+func (b *legacyToolsBackend) GetHandlers() map[string]func(*shared.Message) (interface{}, error) {
+	return map[string]func(*shared.Message) (interface{}, error){
+		"tools/list": func(msg *shared.Message) (interface{}, error) {
+			tools := make([]schema.Tool, 0, len(legacyResults))
+			for name := range legacyResults {
+				tools = append(tools, schema.Tool{Name: name, InputSchema: &schema.JSONSchemaProperty{Type: "object"}})
+			}
+			return schema.ListToolsResult{Tools: tools}, nil
+		},
+		"tools/call": func(msg *shared.Message) (interface{}, error) {
+			var params schema.CallToolRequestParams
+			if err := json.Unmarshal(*msg.Params, &params); err != nil {
+				return nil, err
+			}
+			return json.RawMessage(legacyResults[params.Name]), nil
+		},
+	}
+}
+
+func (b *legacyToolsBackend) SetCapabilities(s *schema.ServerCapabilities) {
+	s.Tools = &schema.Capability{}
+}
+
+func (b *legacyToolsBackend) SetEventBus(bus shared.EventBus) {}
+
+// startLegacyToolsServer runs an MCP server with the tools of legacyToolsBackend.
+func startLegacyToolsServer(t *testing.T) string {
+	logger := LOGGER.With(zap.String("s", "legacy-backend"))
+	cfg := config.NewInternalConfig()
+	cfg.UserKeyHashes[config.HashAPIKey("gateway")] = "gw"
+	manager, err := transport.NewManager(logger, cfg)
+	if err != nil {
+		t.Fatalf("Failed to create manager: %v", err)
+	}
+	tr, err := transport.New(manager, logger, cfg)
+	if err != nil {
+		t.Fatalf("Failed to create transport: %v", err)
+	}
+	manager.AddCapability(mcpCapability.NewBase(logger, manager), &legacyToolsBackend{})
+	mux := http.NewServeMux()
+	tr.RegisterMCPHandlers(mux)
+	mux.HandleFunc("/health", func(w http.ResponseWriter, r *http.Request) {}) // The gateway only routes to backends answering 2xx
+	server := httptest.NewServer(mux)
+	t.Cleanup(func() {
+		server.CloseClientConnections() // The gateway keeps its SSE streams open
+		server.Close()
+	})
+	return server.URL + "/sse?key=gateway"
+}
+
+func TestLenientResultNormalizerThroughGateway(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	portForGateway, err := tests.FindAvailablePort()
+	if err != nil {
+		t.Fatalf("Failed to find available port: %v", err)
+	}
+	cfgGw := config.NewInternalConfig()
+	cfgGw.UserKeyHashes[config.HashAPIKey("key-legacy-user")] = "legacy-user"
+	cfgGw.Backends["legacy"] = &config.Backend{URL: startLegacyToolsServer(t)}
+	cfgGw.UserSubscribes["legacy-user"] = []string{"legacy"}
+	_, err = gateway.Start(ctx, LOGGER.With(zap.String("s", "legacy-gateway")), cfgGw, fmt.Sprintf(":%d", portForGateway),
+		gateway.WithResultNormalizer(capability.LenientResultNormalizer{}))
+	if err != nil {
+		t.Fatalf("Failed to start gateway: %v", err)
+	}
+	waitForPort(t, portForGateway)
+	gwURL := "http://localhost:" + strconv.Itoa(portForGateway) + "/sse"
+
+	reqCtx, reqCancel := context.WithTimeout(ctx, 15*time.Second)
+	defer reqCancel()
+	c, err := mcpClient.New(gwURL, gwURL, LOGGER.With(zap.String("s", "legacy-client")))
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	session := c.NewSession(reqCtx, mcpClient.WithAuthenticationBearer("key-legacy-user"))
+	defer session.Close()
+	if err := <-session.Open(); err != nil {
+		t.Fatalf("Failed to open session: %v", err)
+	}
+
+	for name := range legacyResults {
+		t.Run(name, func(t *testing.T) {
+			result := <-session.CallTool(reqCtx, name, nil)
+			if result.Error != nil {
+				t.Fatalf("Tool call failed: %v", result.Error)
+			}
+			content := result.Result.Content
+			if len(content) != 1 || content[0].Type != "text" || content[0].Text == nil || *content[0].Text != "plain text" {
+				t.Fatalf("Unexpected normalized content: %+v", content)
+			}
+		})
+	}
+}
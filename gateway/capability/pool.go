@@ -0,0 +1,162 @@
+package capability
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	client "github.com/gate4ai/gate4ai/gateway/clients/mcpClient"
+	"github.com/gate4ai/gate4ai/shared"
+	"go.uber.org/zap"
+)
+
+// backendPoolKeyPrefix prefixes the client session parameter holding the pool of one server.
+const backendPoolKeyPrefix = "gw_backend_pool:"
+
+// GatewayOption configures a GatewayCapability.
+type GatewayOption func(*GatewayCapability)
+
+// WithBackendPoolSize lets tools/call use up to n backend sessions per (client session, server)
+// pair, so concurrent calls to one backend are not serialized on a single session.
+// n <= 1 keeps the single shared backend session.
+func WithBackendPoolSize(n int) GatewayOption {
+	return func(c *GatewayCapability) {
+		c.backendPoolSize = n
+	}
+}
+
+// BackendSessionPool hands out backend sessions of one server, one caller per session at a time.
+// Idle sessions are picked round-robin, new ones are created up to maxSize, and callers beyond
+// that wait until a session is released.
+type BackendSessionPool struct {
+	maxSize    int
+	newSession func(ctx context.Context) (*client.Session, error)
+	slots      chan struct{} // One token per session in use or being created
+	mu         sync.Mutex
+	sessions   []*client.Session
+	busy       map[*client.Session]bool
+	next       int       // Round-robin start for the next idle scan
+	created    time.Time // Sessions of a pool created before a backend URL change are replaced
+	retired    bool      // Set by Retire; released sessions are closed
+}
+
+// NewBackendSessionPool creates a pool seeded with the given sessions (which count towards maxSize).
+func NewBackendSessionPool(maxSize int, newSession func(ctx context.Context) (*client.Session, error), seed ...*client.Session) *BackendSessionPool {
+	if maxSize < 1 {
+		maxSize = 1
+	}
+	p := &BackendSessionPool{
+		maxSize:    maxSize,
+		newSession: newSession,
+		slots:      make(chan struct{}, maxSize),
+		busy:       make(map[*client.Session]bool),
+		created:    time.Now(),
+	}
+	for _, session := range seed {
+		if session != nil && len(p.sessions) < maxSize {
+			p.sessions = append(p.sessions, session)
+		}
+	}
+	return p
+}
+
+// Acquire returns a session for the caller's exclusive use; Release must be called when done.
+func (p *BackendSessionPool) Acquire(ctx context.Context) (*client.Session, error) {
+	select {
+	case p.slots <- struct{}{}:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+
+	p.mu.Lock()
+	for i := 0; i < len(p.sessions); i++ {
+		session := p.sessions[(p.next+i)%len(p.sessions)]
+		if !p.busy[session] {
+			p.busy[session] = true
+			p.next = (p.next + i + 1) % len(p.sessions)
+			p.mu.Unlock()
+			return session, nil
+		}
+	}
+	p.mu.Unlock()
+
+	// Every session is busy, and holding a slot guarantees there is room for one more
+	session, err := p.newSession(ctx)
+	if err != nil {
+		<-p.slots
+		return nil, err
+	}
+	p.mu.Lock()
+	p.sessions = append(p.sessions, session)
+	p.busy[session] = true
+	p.mu.Unlock()
+	return session, nil
+}
+
+// Release returns a session obtained from Acquire to the pool, closing it if the pool is retired.
+func (p *BackendSessionPool) Release(session *client.Session) {
+	p.mu.Lock()
+	delete(p.busy, session)
+	retired := p.retired
+	p.mu.Unlock()
+	if retired {
+		session.Close()
+	}
+	<-p.slots
+}
+
+// Retire closes the idle sessions of a replaced pool; sessions in use are closed on Release.
+func (p *BackendSessionPool) Retire() {
+	p.mu.Lock()
+	p.retired = true
+	idle := make([]*client.Session, 0, len(p.sessions))
+	for _, session := range p.sessions {
+		if !p.busy[session] {
+			idle = append(idle, session)
+		}
+	}
+	p.mu.Unlock()
+	for _, session := range idle {
+		session.Close()
+	}
+}
+
+// Size returns the number of sessions created by or seeded into the pool.
+func (p *BackendSessionPool) Size() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return len(p.sessions)
+}
+
+// backendSessionPool returns the pool of serverSlug for the client session, seeding a new pool with seed.
+func (c *GatewayCapability) backendSessionPool(clientSession shared.ISession, serverSlug string, seed *client.Session, logger *zap.Logger) *BackendSessionPool {
+	key := backendPoolKeyPrefix + serverSlug
+	if existing, ok := clientSession.GetParams().Load(key); ok {
+		pool := existing.(*BackendSessionPool)
+		if !c.backendChangedSince(serverSlug, pool.created) {
+			return pool
+		}
+		// The backend URL changed, so the pooled sessions point to the old backend
+		if clientSession.GetParams().CompareAndDelete(key, pool) {
+			logger.Info("Replacing backend session pool after a backend URL change", zap.String("serverSlug", serverSlug))
+			pool.Retire()
+		}
+	}
+	newSession := func(ctx context.Context) (*client.Session, error) {
+		logger.Debug("Creating pooled backend session", zap.String("serverSlug", serverSlug))
+		session, err := c.newBackendSession(ctx, serverSlug, clientSession, logger)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create backend session for server %s: %w", serverSlug, err)
+		}
+		if reason := GetBackendUnavailable(session.GetParams()); reason != nil {
+			// Not pooled, so the next call tries the backend again
+			session.Close()
+			logger.Warn("Backend unavailable for pooled session", zap.String("serverSlug", serverSlug), zap.Error(reason))
+			return nil, errBackendUnavailable()
+		}
+		return session, nil
+	}
+	pool, _ := clientSession.GetParams().LoadOrStore(key, NewBackendSessionPool(c.backendPoolSize, newSession, seed))
+	return pool.(*BackendSessionPool)
+}
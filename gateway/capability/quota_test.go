@@ -0,0 +1,55 @@
+package capability
+
+import (
+	"reflect"
+	"slices"
+	"testing"
+
+	"github.com/gate4ai/gate4ai/server/transport"
+	"github.com/gate4ai/gate4ai/shared/config"
+	sharedtesting "github.com/gate4ai/gate4ai/shared/testing"
+	"go.uber.org/zap"
+)
+
+func backendSlugs(t *testing.T, c *GatewayCapability, userID string) []string {
+	t.Helper()
+	clientSession := sharedtesting.NewMockSession("client-" + userID)
+	transport.SaveUserId(clientSession.GetParams(), userID)
+	sessions, err := c.getBackendSessions(clientSession)
+	if err != nil {
+		t.Fatalf("Failed to get backend sessions: %v", err)
+	}
+	slugs := make([]string, 0, len(sessions))
+	for _, session := range sessions {
+		slug, _, _ := GetServerSlug(session.GetParams())
+		slugs = append(slugs, slug)
+		session.Close()
+	}
+	slices.Sort(slugs)
+	return slugs
+}
+
+func TestBackendSessionsLimitedBySubscriptionQuota(t *testing.T) {
+	backend, _, _ := flakyBackend(t, 0, 0)
+
+	cfg := config.NewInternalConfig()
+	for _, slug := range []string{"alpha", "bravo", "charlie"} {
+		cfg.Backends[slug] = &config.Backend{URL: backend.URL + "/sse"}
+	}
+	cfg.UserSubscribes["over-quota"] = []string{"charlie", "alpha", "bravo"}
+	cfg.UserSubscribes["within-quota"] = []string{"charlie"}
+	cfg.MaxSubscriptionsPerUserValue = 2
+	c := NewGatewayCapability(zap.NewNop(), cfg)
+
+	if got, want := backendSlugs(t, c, "over-quota"), []string{"alpha", "bravo"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("User over the quota got sessions for %v, want %v", got, want)
+	}
+	if got, want := backendSlugs(t, c, "within-quota"), []string{"charlie"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("User within the quota got sessions for %v, want %v", got, want)
+	}
+
+	cfg.MaxSubscriptionsPerUserValue = 0
+	if got, want := backendSlugs(t, c, "over-quota"), []string{"alpha", "bravo", "charlie"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Without a quota got sessions for %v, want %v", got, want)
+	}
+}
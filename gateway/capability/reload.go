@@ -0,0 +1,62 @@
+package capability
+
+import (
+	"strings"
+	"time"
+
+	"github.com/gate4ai/gate4ai/shared/config"
+	"go.uber.org/zap"
+)
+
+// listenConfigChanges records the backends whose URL changed in a config reload. Backend
+// sessions created before the change are replaced the next time they are looked up.
+// It runs for the lifetime of the config, because a subscriber must keep reading.
+func (c *GatewayCapability) listenConfigChanges(events <-chan config.ConfigChangeEvent) {
+	for event := range events {
+		serverSlug, ok := backendURLChange(event.Field)
+		if !ok {
+			continue
+		}
+		c.logger.Info("Backend URL changed, recreating its sessions",
+			zap.String("serverSlug", serverSlug), zap.Any("oldURL", event.OldValue), zap.Any("newURL", event.NewValue))
+		c.backendChangesMu.Lock()
+		if c.backendChanges == nil {
+			c.backendChanges = make(map[string]time.Time)
+		}
+		c.backendChanges[serverSlug] = time.Now()
+		c.backendChangesMu.Unlock()
+	}
+}
+
+// backendURLChange returns the slug of a "backends.<slug>.url" field.
+func backendURLChange(field string) (string, bool) {
+	rest, ok := strings.CutPrefix(field, "backends.")
+	if !ok {
+		return "", false
+	}
+	serverSlug, ok := strings.CutSuffix(rest, ".url")
+	if !ok || serverSlug == "" {
+		return "", false
+	}
+	return serverSlug, true
+}
+
+// backendChangedSince reports whether the URL of serverSlug changed after t.
+func (c *GatewayCapability) backendChangedSince(serverSlug string, t time.Time) bool {
+	c.backendChangesMu.Lock()
+	defer c.backendChangesMu.Unlock()
+	changed, ok := c.backendChanges[serverSlug]
+	return ok && changed.After(t)
+}
+
+// anyBackendChangedSince reports whether the URL of any backend changed after t.
+func (c *GatewayCapability) anyBackendChangedSince(t time.Time) bool {
+	c.backendChangesMu.Lock()
+	defer c.backendChangesMu.Unlock()
+	for _, changed := range c.backendChanges {
+		if changed.After(t) {
+			return true
+		}
+	}
+	return false
+}
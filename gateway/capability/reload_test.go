@@ -0,0 +1,86 @@
+package capability
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/gate4ai/gate4ai/shared/config"
+	sharedtesting "github.com/gate4ai/gate4ai/shared/testing"
+	"go.uber.org/zap"
+)
+
+func TestGatewayTracksBackendURLChanges(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	writeConfig := func(url string) {
+		t.Helper()
+		data := "backends:\n  weather:\n    url: \"" + url + "\"\n  news:\n    url: \"http://news:4000/mcp\"\n"
+		if err := os.WriteFile(path, []byte(data), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	writeConfig("http://weather-1:4000/mcp")
+	cfg, err := config.NewYamlConfig(path, zap.NewNop(), config.WithWatchInterval(10*time.Millisecond))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cfg.Close()
+	c := NewGatewayCapability(zap.NewNop(), cfg)
+
+	start := time.Now()
+	writeConfig("http://weather-2:4000/mcp")
+	deadline := time.Now().Add(2 * time.Second)
+	for !c.backendChangedSince("weather", start) {
+		if time.Now().After(deadline) {
+			t.Fatal("Backend URL change did not reach the gateway")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if c.backendChangedSince("news", start) {
+		t.Errorf("Unchanged backend reported as changed")
+	}
+	if !c.anyBackendChangedSince(start) {
+		t.Errorf("Expected a backend change since the rewrite")
+	}
+	if c.backendChangedSince("weather", time.Now()) {
+		t.Errorf("Change reported for sessions created after it")
+	}
+}
+
+func TestBackendPoolReplacedAfterURLChange(t *testing.T) {
+	c := &GatewayCapability{logger: zap.NewNop(), backendPoolSize: 2}
+	clientSession := sharedtesting.NewMockSession("pool-session")
+
+	pool := c.backendSessionPool(clientSession, "weather", nil, c.logger)
+	if again := c.backendSessionPool(clientSession, "weather", nil, c.logger); again != pool {
+		t.Fatalf("Pool was not reused without a backend change")
+	}
+
+	time.Sleep(time.Millisecond)
+	c.backendChanges = map[string]time.Time{"weather": time.Now()}
+	replaced := c.backendSessionPool(clientSession, "weather", nil, c.logger)
+	if replaced == pool {
+		t.Fatalf("Pool was reused after the backend URL changed")
+	}
+	if !pool.retired {
+		t.Errorf("Replaced pool was not retired")
+	}
+	if again := c.backendSessionPool(clientSession, "weather", nil, c.logger); again != replaced {
+		t.Errorf("Replacement pool was not reused")
+	}
+}
+
+func TestBackendURLChangeField(t *testing.T) {
+	for field, want := range map[string]string{
+		"backends.weather.url":    "weather",
+		"backends.weather.bearer": "",
+		"server.address":          "",
+		"backends..url":           "",
+	} {
+		serverSlug, ok := backendURLChange(field)
+		if serverSlug != want || ok != (want != "") {
+			t.Errorf("backendURLChange(%q) = %q, %v; want %q", field, serverSlug, ok, want)
+		}
+	}
+}
@@ -0,0 +1,72 @@
+package capability
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/gate4ai/gate4ai/shared"
+)
+
+// RetryPolicy controls how the gateway retries creating a backend session (the health probe and
+// the MCP initialization), so a backend that is briefly unavailable (e.g. restarting) does not
+// fail the session.
+type RetryPolicy struct {
+	// MaxAttempts includes the first attempt; values below 2 disable retries
+	MaxAttempts int
+	// InitialDelay is the wait before the first retry
+	InitialDelay time.Duration
+	// Multiplier grows the delay after each retry; values below 1 keep it constant
+	Multiplier float64
+	// MaxDelay caps the delay between attempts (0 means no cap)
+	MaxDelay time.Duration
+}
+
+// WithBackendRetryPolicy retries the health probe and initialization of new backend sessions
+// according to policy. A backend still failing after the last attempt is marked unavailable, and
+// the calls routed to it fail with a "Backend unavailable" JSON-RPC error. Without this option
+// a backend session is attempted once.
+func WithBackendRetryPolicy(policy RetryPolicy) GatewayOption {
+	return func(c *GatewayCapability) {
+		c.retryPolicy = policy
+	}
+}
+
+// delay returns the wait before retry number retry (1 for the first retry).
+func (p RetryPolicy) delay(retry int) time.Duration {
+	multiplier := math.Max(p.Multiplier, 1)
+	delay := time.Duration(float64(p.InitialDelay) * math.Pow(multiplier, float64(retry-1)))
+	if p.MaxDelay > 0 && (delay > p.MaxDelay || delay < 0) {
+		return p.MaxDelay
+	}
+	return delay
+}
+
+// do calls attempt until it succeeds, the attempts are used up or ctx is done, and returns the
+// last error. onRetry is called before each wait.
+func (p RetryPolicy) do(ctx context.Context, attempt func() error, onRetry func(retry int, delay time.Duration, err error)) error {
+	err := attempt()
+	for retry := 1; err != nil && retry < p.MaxAttempts; retry++ {
+		delay := p.delay(retry)
+		onRetry(retry, delay, err)
+		timer := time.NewTimer(delay)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return fmt.Errorf("%w (retries stopped: %w)", err, ctx.Err())
+		}
+		err = attempt()
+	}
+	return err
+}
+
+// errBackendSessionFailed is returned to clients routed to a backend whose session could not be created.
+func errBackendSessionFailed(serverSlug string, reason error) *shared.JSONRPCError {
+	return &shared.JSONRPCError{
+		Code:    shared.JSONRPCErrorServerError,
+		Message: fmt.Sprintf("Failed to create backend session for server %s", serverSlug),
+		Data:    map[string]interface{}{"error": reason.Error()},
+	}
+}
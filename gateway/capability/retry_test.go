@@ -0,0 +1,199 @@
+package capability
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	mcpCapability "github.com/gate4ai/gate4ai/server/mcp/capability"
+	"github.com/gate4ai/gate4ai/server/transport"
+	"github.com/gate4ai/gate4ai/shared"
+	"github.com/gate4ai/gate4ai/shared/config"
+	sharedtesting "github.com/gate4ai/gate4ai/shared/testing"
+	"go.uber.org/zap"
+)
+
+// flakyBackend runs an MCP server whose health probe answers 503 for the first healthFailures
+// probes and whose initialize requests answer 500 for the first initFailures attempts.
+func flakyBackend(t *testing.T, healthFailures, initFailures int32) (*httptest.Server, *atomic.Int32, *atomic.Int32) {
+	t.Helper()
+	logger := zap.NewNop()
+	cfg := config.NewInternalConfig()
+	cfg.AuthorizationTypeValue = config.NotAuthorizedEverywhere
+	manager, err := transport.NewManager(logger, cfg)
+	if err != nil {
+		t.Fatalf("Failed to create manager: %v", err)
+	}
+	tr, err := transport.New(manager, logger, cfg)
+	if err != nil {
+		t.Fatalf("Failed to create transport: %v", err)
+	}
+	manager.AddCapability(mcpCapability.NewBase(logger, manager))
+	mux := http.NewServeMux()
+	tr.RegisterMCPHandlers(mux)
+
+	var probes, inits atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/health" {
+			if probes.Add(1) <= healthFailures {
+				w.WriteHeader(http.StatusServiceUnavailable)
+			}
+			return
+		}
+		if r.Method == http.MethodPost {
+			body, _ := io.ReadAll(r.Body)
+			r.Body = io.NopCloser(bytes.NewReader(body))
+			if bytes.Contains(body, []byte(`"initialize"`)) && inits.Add(1) <= initFailures {
+				w.WriteHeader(http.StatusInternalServerError)
+				return
+			}
+		}
+		mux.ServeHTTP(w, r)
+	}))
+	t.Cleanup(func() {
+		server.CloseClientConnections() // Opened sessions keep their SSE streams
+		server.Close()
+	})
+	return server, &probes, &inits
+}
+
+func newRetryTestCapability(backendURL string, policy RetryPolicy) *GatewayCapability {
+	cfg := config.NewInternalConfig()
+	cfg.Backends["flaky"] = &config.Backend{URL: backendURL + "/sse"}
+	return NewGatewayCapability(zap.NewNop(), cfg, WithBackendRetryPolicy(policy))
+}
+
+func TestBackendSessionRetriesHealthProbe(t *testing.T) {
+	backend, probes, _ := flakyBackend(t, 2, 0)
+	c := newRetryTestCapability(backend.URL, RetryPolicy{MaxAttempts: 3, InitialDelay: 10 * time.Millisecond, Multiplier: 2})
+
+	session, err := c.newBackendSession(c.ctx, "flaky", sharedtesting.NewMockSession("client"), c.logger)
+	if err != nil {
+		t.Fatalf("Failed to create backend session: %v", err)
+	}
+	defer session.Close()
+	if reason := GetBackendUnavailable(session.GetParams()); reason != nil {
+		t.Errorf("Backend healthy on the third attempt was marked unavailable: %v", reason)
+	}
+	if got := probes.Load(); got != 3 {
+		t.Errorf("Expected 3 health probes, got %d", got)
+	}
+}
+
+func TestBackendSessionUnavailableAfterRetries(t *testing.T) {
+	backend, probes, _ := flakyBackend(t, 2, 0)
+	c := newRetryTestCapability(backend.URL, RetryPolicy{MaxAttempts: 2, InitialDelay: 10 * time.Millisecond, Multiplier: 2})
+
+	session, err := c.newBackendSession(c.ctx, "flaky", sharedtesting.NewMockSession("client"), c.logger)
+	if err != nil {
+		t.Fatalf("Failed to create backend session: %v", err)
+	}
+	defer session.Close()
+	if GetBackendUnavailable(session.GetParams()) == nil {
+		t.Errorf("Backend failing every attempt was not marked unavailable")
+	}
+	if got := probes.Load(); got != 2 {
+		t.Errorf("Expected 2 health probes, got %d", got)
+	}
+}
+
+func TestBackendSessionRetriesStopOnShutdown(t *testing.T) {
+	backend, probes, _ := flakyBackend(t, 100, 0)
+	c := newRetryTestCapability(backend.URL, RetryPolicy{MaxAttempts: 5, InitialDelay: time.Minute})
+	go func() {
+		for probes.Load() == 0 {
+			time.Sleep(time.Millisecond)
+		}
+		c.cancel()
+	}()
+
+	done := make(chan error, 1)
+	go func() {
+		session, err := c.newBackendSession(c.ctx, "flaky", sharedtesting.NewMockSession("client"), c.logger)
+		if err == nil {
+			err = GetBackendUnavailable(session.GetParams())
+			session.Close()
+		}
+		done <- err
+	}()
+	select {
+	case err := <-done:
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("Expected the unavailable reason to wrap context.Canceled, got %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Retries did not stop when the gateway context was cancelled")
+	}
+}
+
+func TestBackendSessionRetriesInitialization(t *testing.T) {
+	backend, probes, inits := flakyBackend(t, 0, 2)
+	c := newRetryTestCapability(backend.URL, RetryPolicy{MaxAttempts: 3, InitialDelay: 10 * time.Millisecond, Multiplier: 2})
+
+	session, err := c.newBackendSession(c.ctx, "flaky", sharedtesting.NewMockSession("client"), c.logger)
+	if err != nil {
+		t.Fatalf("Failed to create backend session: %v", err)
+	}
+	defer session.Close()
+	if reason := GetBackendUnavailable(session.GetParams()); reason != nil {
+		t.Errorf("Backend initialized on the third attempt was marked unavailable: %v", reason)
+	}
+	if got := inits.Load(); got != 3 {
+		t.Errorf("Expected 3 initialize attempts, got %d", got)
+	}
+	if got := probes.Load(); got != 3 {
+		t.Errorf("Expected a health probe per attempt, got %d", got)
+	}
+
+	// Without retries the first failure makes the backend unavailable
+	backend, _, inits = flakyBackend(t, 0, 1)
+	c = newRetryTestCapability(backend.URL, RetryPolicy{})
+	session, err = c.newBackendSession(c.ctx, "flaky", sharedtesting.NewMockSession("client"), c.logger)
+	if err != nil {
+		t.Fatalf("Failed to create backend session: %v", err)
+	}
+	defer session.Close()
+	if GetBackendUnavailable(session.GetParams()) == nil {
+		t.Errorf("Backend failing its only initialization was not marked unavailable")
+	}
+	if got := inits.Load(); got != 1 {
+		t.Errorf("Expected 1 initialize attempt, got %d", got)
+	}
+}
+
+func TestRetryPolicyDelay(t *testing.T) {
+	policy := RetryPolicy{MaxAttempts: 6, InitialDelay: 100 * time.Millisecond, Multiplier: 2, MaxDelay: 500 * time.Millisecond}
+	expected := []time.Duration{100 * time.Millisecond, 200 * time.Millisecond, 400 * time.Millisecond, 500 * time.Millisecond, 500 * time.Millisecond}
+	for i, want := range expected {
+		if got := policy.delay(i + 1); got != want {
+			t.Errorf("Retry %d: expected delay %s, got %s", i+1, want, got)
+		}
+	}
+	constant := RetryPolicy{InitialDelay: 50 * time.Millisecond}
+	if got := constant.delay(4); got != 50*time.Millisecond {
+		t.Errorf("Expected a constant delay without multiplier, got %s", got)
+	}
+}
+
+func TestBackendSessionErrorSurfacedAsJSONRPCError(t *testing.T) {
+	cfg := config.NewInternalConfig()
+	cfg.UserSubscribes["user"] = []string{"missing"}
+	c := NewGatewayCapability(zap.NewNop(), cfg)
+	clientSession := sharedtesting.NewMockSession("client")
+	transport.SaveUserId(clientSession.GetParams(), "user")
+
+	_, err := c.getBackendSession(clientSession, "missing")
+	var rpcErr *shared.JSONRPCError
+	if !errors.As(err, &rpcErr) {
+		t.Fatalf("Expected a JSON-RPC error, got %v", err)
+	}
+	if rpcErr.Code != shared.JSONRPCErrorServerError {
+		t.Errorf("Expected server error code, got %d", rpcErr.Code)
+	}
+}
@@ -12,6 +12,8 @@ const (
 	backendSessionsKey = "gw_backend_sessions"
 	clientSessionsKey  = "gw_client_sessions"
 	serverSlugKey      = "gw_server_id"
+	unavailableKey     = "gw_backend_unavailable"
+	sessionErrorsKey   = "gw_backend_session_errors"
 )
 
 // SavedValue represents a cached value with its timestamp
@@ -73,3 +75,49 @@ func GetServerSlug(sessionParams *sync.Map) (string, time.Time, bool) {
 
 	return serverSlug, saved.Timestamp, true
 }
+
+// SaveBackendUnavailable marks a backend session as failing its health probe.
+func SaveBackendUnavailable(sessionParams *sync.Map, reason error) {
+	sessionParams.Store(unavailableKey, &SavedValue{
+		Value:     reason,
+		Timestamp: time.Now(),
+	})
+}
+
+// GetBackendUnavailable returns why the backend session is unavailable, or nil if it is usable.
+func GetBackendUnavailable(sessionParams *sync.Map) error {
+	savedValue, ok := sessionParams.Load(unavailableKey)
+	if !ok {
+		return nil
+	}
+	saved, ok := savedValue.(*SavedValue)
+	if !ok {
+		return nil
+	}
+	reason, _ := saved.Value.(error)
+	return reason
+}
+
+// SaveBackendSessionErrors records why the backend sessions of some servers could not be created,
+// keyed by server slug. It replaces the errors of the previous attempt.
+func SaveBackendSessionErrors(sessionParams *sync.Map, errs map[string]error) {
+	sessionParams.Store(sessionErrorsKey, &SavedValue{
+		Value:     errs,
+		Timestamp: time.Now(),
+	})
+}
+
+// GetBackendSessionError returns why the last attempt to create the backend session of the
+// server failed, or nil.
+func GetBackendSessionError(sessionParams *sync.Map, serverSlug string) error {
+	savedValue, ok := sessionParams.Load(sessionErrorsKey)
+	if !ok {
+		return nil
+	}
+	saved, ok := savedValue.(*SavedValue)
+	if !ok {
+		return nil
+	}
+	errs, _ := saved.Value.(map[string]error)
+	return errs[serverSlug]
+}
@@ -0,0 +1,39 @@
+package capability
+
+import (
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// tracerName is the instrumentation scope of the spans created by this package.
+const tracerName = "github.com/gate4ai/gate4ai/gateway/capability"
+
+// SpanBackendToolCall is the span of one tools/call forwarded to a backend, a child of the
+// client's request span. Its context is sent to the backend in the traceparent header.
+const SpanBackendToolCall = "gateway.backend.tools_call"
+
+// WithTracerProvider records backend call spans with tp instead of the global OpenTelemetry
+// tracer provider.
+func WithTracerProvider(tp trace.TracerProvider) GatewayOption {
+	return func(c *GatewayCapability) {
+		c.tracerProvider = tp
+	}
+}
+
+// tracer returns the tracer for backend call spans; it is a no-op tracer unless a provider
+// is set by WithTracerProvider or installed globally.
+func (c *GatewayCapability) tracer() trace.Tracer {
+	if c.tracerProvider != nil {
+		return c.tracerProvider.Tracer(tracerName)
+	}
+	return otel.GetTracerProvider().Tracer(tracerName)
+}
+
+// setSpanError marks span as failed if err is not nil.
+func setSpanError(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+}
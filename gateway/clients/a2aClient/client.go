@@ -229,10 +229,14 @@ func (c *Client) _processSSEStream(ctx context.Context, resp *http.Response, req
 	defer resp.Body.Close()
 	scanner := bufio.NewScanner(resp.Body)
 	var dataBuffer bytes.Buffer
+	eventName := ""                               // Value of the "event:" field for the event being read
+	var deltaDecoder a2aSchema.StatusDeltaDecoder // Rebuilds delta-encoded status messages
 	for scanner.Scan() {
 		line := scanner.Text()
 		//logger.Debug("SSE line received", zap.String("line", line)) // Reduce logging
 		if line == "" {
+			currentEventName := eventName
+			eventName = ""
 			if dataBuffer.Len() > 0 {
 				var rpcResponse struct {
 					Result *json.RawMessage     `json:"result"`
@@ -268,7 +272,24 @@ func (c *Client) _processSSEStream(ctx context.Context, resp *http.Response, req
 				rawResult := *rpcResponse.Result
 				currentEvent := shared.A2AStreamEvent{}
 				var statusEvent a2aSchema.TaskStatusUpdateEvent
-				if err := json.Unmarshal(rawResult, &statusEvent); err == nil && statusEvent.Status.State != "" {
+				if currentEventName == shared.A2ASSEEventHistory {
+					var historyEvent a2aSchema.TaskHistoryUpdateEvent
+					if err := json.Unmarshal(rawResult, &historyEvent); err != nil {
+						logger.Warn("Failed to parse SSE history event", zap.Error(err), zap.ByteString("result", rawResult))
+						dataBuffer.Reset()
+						continue
+					}
+					currentEvent.Type = shared.A2ASSEEventHistory
+					currentEvent.History = &historyEvent
+				} else if err := json.Unmarshal(rawResult, &statusEvent); err == nil && statusEvent.Status.State != "" {
+					if statusEvent, err = deltaDecoder.Decode(statusEvent); err != nil {
+						logger.Error("Failed to decode status delta", zap.Error(err))
+						select {
+						case eventChan <- shared.A2AStreamEvent{Error: fmt.Errorf("decode status delta: %w", err), Final: true}:
+						case <-ctx.Done():
+						}
+						return
+					}
 					currentEvent.Type = "status"
 					currentEvent.Status = &statusEvent
 					currentEvent.Final = statusEvent.Final
@@ -299,8 +320,10 @@ func (c *Client) _processSSEStream(ctx context.Context, resp *http.Response, req
 		}
 		if strings.HasPrefix(line, "data:") {
 			dataBuffer.WriteString(strings.TrimSpace(strings.TrimPrefix(line, "data:")))
+		} else if strings.HasPrefix(line, "event:") {
+			eventName = strings.TrimSpace(strings.TrimPrefix(line, "event:"))
 		}
-		// Ignore other SSE fields (event:, id:, retry:) for simplicity
+		// Ignore other SSE fields (id:, retry:) for simplicity
 	}
 	if err := scanner.Err(); err != nil {
 		logger.Error("Error reading SSE stream", zap.Error(err))
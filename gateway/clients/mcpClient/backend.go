@@ -67,6 +67,9 @@ func (backend *Backend) NewSession(ctx context.Context, opts ...SessionOption) *
 		baseSession.Logger.Error("Failed to apply session options", zap.Error(err))
 	}
 
+	// The SSE stream uses the same HTTP client (and so TLS settings) as requests
+	sseClient.Connection = clientSession.httpClient
+
 	// Set headers for the SSE client connection *after* applying options
 	sseClient.Headers = make(map[string]string)
 	for k, v := range clientSession.currentHeaders {
@@ -83,12 +86,14 @@ func (backend *Backend) NewSession(ctx context.Context, opts ...SessionOption) *
 	resourcesCap := capability.NewResourcesCapability(backend.Logger, clientSession)
 	resourceTemplatesCap := capability.NewResourceTemplatesCapability(backend.Logger, clientSession)
 	samplingCap := capability.NewSamplingCapability(backend.Logger)
+	rootsCap := capability.NewRootsCapability(backend.Logger)
 
-	input.AddClientCapability(resourcesCap, resourceTemplatesCap, samplingCap)
+	input.AddClientCapability(resourcesCap, resourceTemplatesCap, samplingCap, rootsCap)
 
 	clientSession.ResourcesCapability = resourcesCap
 	clientSession.ResourceTemplatesCapability = resourceTemplatesCap
 	clientSession.SamplingCapability = samplingCap
+	clientSession.RootsCapability = rootsCap
 
 	go input.Process()
 	baseSession.Logger.Info("Client session created", zap.Int("finalHeaderCount", len(clientSession.currentHeaders)))
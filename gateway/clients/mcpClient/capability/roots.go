@@ -0,0 +1,88 @@
+package capability
+
+import (
+	"encoding/json"
+	"errors"
+	"sync"
+
+	"github.com/gate4ai/gate4ai/shared"
+	schema "github.com/gate4ai/gate4ai/shared/mcp/2025/schema"
+	"go.uber.org/zap"
+)
+
+// RootsAddedFunc defines the callback function type for handling "roots/add" notifications.
+type RootsAddedFunc func(msg *shared.Message)
+
+var _ shared.IClientCapability = (*RootsCapability)(nil)
+
+// RootsCapability handles "roots/add" notifications sent by the server.
+type RootsCapability struct {
+	logger                *zap.Logger
+	mu                    sync.RWMutex
+	rootsAddedSubscribers []RootsAddedFunc
+	handlers              map[string]func(*shared.Message) (interface{}, error)
+}
+
+// NewRootsCapability creates a new RootsCapability.
+func NewRootsCapability(logger *zap.Logger) *RootsCapability {
+	rc := &RootsCapability{
+		logger: logger,
+	}
+	rc.handlers = map[string]func(*shared.Message) (interface{}, error){
+		"roots/add": rc.handleRootsAdded,
+	}
+	return rc
+}
+
+// GetHandlers returns the map of method handlers for this capability.
+func (rc *RootsCapability) GetHandlers() map[string]func(*shared.Message) (interface{}, error) {
+	return rc.handlers
+}
+
+// SetCapabilities implements the IClientCapability interface. Nothing is advertised:
+// the client only receives roots/add, it does not answer roots/list from the server.
+func (rc *RootsCapability) SetCapabilities(s *schema.ClientCapabilities) {}
+
+// SubscribeOnRootsAdded registers a callback function to be invoked when a roots/add notification is received.
+func (rc *RootsCapability) SubscribeOnRootsAdded(f RootsAddedFunc) {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	rc.rootsAddedSubscribers = append(rc.rootsAddedSubscribers, f)
+	rc.logger.Debug("Added roots added subscriber", zap.Int("totalSubscribers", len(rc.rootsAddedSubscribers)))
+}
+
+// handleRootsAdded handles incoming "roots/add" notifications.
+func (rc *RootsCapability) handleRootsAdded(msg *shared.Message) (interface{}, error) {
+	logger := rc.logger.With(zap.String("method", *msg.Method))
+	if msg.Params == nil {
+		errMsg := "Roots added notification params are nil"
+		logger.Error(errMsg)
+		return nil, errors.New(errMsg)
+	}
+	var params schema.RootsAddedNotificationParams
+	if err := json.Unmarshal(*msg.Params, &params); err != nil {
+		logger.Error("Failed to unmarshal roots added notification params", zap.Error(err))
+		return nil, err
+	}
+	logger.Debug("Processing roots added notification", zap.Int("count", len(params.Roots)))
+
+	rc.mu.RLock()
+	subscribersCopy := make([]RootsAddedFunc, len(rc.rootsAddedSubscribers))
+	copy(subscribersCopy, rc.rootsAddedSubscribers)
+	rc.mu.RUnlock()
+
+	msg.Processed = true
+	for _, subscriber := range subscribersCopy {
+		go func(cb RootsAddedFunc, m *shared.Message) {
+			defer func() {
+				if r := recover(); r != nil {
+					rc.logger.Error("Panic recovered in roots added subscriber", zap.Any("panic", r))
+				}
+			}()
+			cb(m)
+		}(subscriber, msg)
+	}
+
+	// Return nil because notifications should not have responses
+	return nil, nil
+}
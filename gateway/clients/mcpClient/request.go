@@ -69,6 +69,9 @@ func (s *Session) executeSendRequest(msg *shared.Message) {
 	for key, value := range currentHeaders {
 		req.Header.Set(key, value) // Add all stored headers
 	}
+	for key, value := range msg.Headers {
+		req.Header.Set(key, value) // Per-request headers override session headers
+	}
 
 	logger.Debug("Sending HTTP POST request", zap.String("endpoint", endpoint), zap.Int("headerCount", len(currentHeaders)))
 
@@ -1,5 +1,17 @@
 package mcpClient
 
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/gate4ai/gate4ai/gateway/clients/mcpClient/capability"
+	"github.com/gate4ai/gate4ai/shared"
+	schema "github.com/gate4ai/gate4ai/shared/mcp/2025/schema"
+	"go.uber.org/zap"
+)
+
 // Root represents a root directory or file for server operations.
 type Root struct {
 	Name string `json:"name,omitempty"` // Optional human-readable name
@@ -23,3 +35,69 @@ type ListRootsResult struct {
 	Meta  map[string]interface{} `json:"_meta,omitempty"` // Reserved for metadata
 	Roots []Root                 `json:"roots"`           // Available roots
 }
+
+// GetRootsResult contains the result of a roots/list request (using 2025 schema).
+type GetRootsResult struct {
+	Roots []schema.Root
+	Err   error
+}
+
+// ListRoots asks the server for its root URIs.
+// It returns a channel that will emit the 2025 schema roots list result.
+func (s *Session) ListRoots(ctx context.Context) chan GetRootsResult {
+	logger := s.BaseSession.Logger.With(zap.String("operation", "ListRoots"))
+	resultChan := make(chan GetRootsResult, 1)
+
+	go func() {
+		if initErr := <-s.Open(); initErr != nil {
+			logger.Error("Session initialization failed", zap.Error(initErr))
+			resultChan <- GetRootsResult{nil, fmt.Errorf("session init failed: %w", initErr)}
+			close(resultChan)
+			return
+		}
+
+		callback := func(msg *shared.Message) {
+			defer close(resultChan)
+			if msg == nil {
+				resultChan <- GetRootsResult{nil, errors.New("protocol error: received nil response")}
+				return
+			}
+			if msg.Error != nil {
+				logger.Warn("Backend returned error", zap.Error(msg.Error))
+				resultChan <- GetRootsResult{nil, fmt.Errorf("backend error: %w", msg.Error)}
+				return
+			}
+			if msg.Result == nil {
+				resultChan <- GetRootsResult{nil, errors.New("protocol error: roots list result is nil")}
+				return
+			}
+			var listRootsResult schema.ListRootsResult
+			if err := json.Unmarshal(*msg.Result, &listRootsResult); err != nil {
+				logger.Error("Failed to unmarshal roots list result", zap.Error(err))
+				resultChan <- GetRootsResult{nil, fmt.Errorf("failed to parse backend response: %w", err)}
+				return
+			}
+			msg.Processed = true
+			logger.Debug("Received roots list", zap.Int("count", len(listRootsResult.Roots)))
+			resultChan <- GetRootsResult{listRootsResult.Roots, nil}
+		}
+
+		logger.Debug("Sending roots/list request")
+		if _, err := s.SendRequest("roots/list", map[string]interface{}{}, callback); err != nil {
+			logger.Error("Failed to send roots list request", zap.Error(err))
+			select {
+			case resultChan <- GetRootsResult{nil, fmt.Errorf("failed to send request: %w", err)}:
+			default:
+			}
+		}
+	}()
+	return resultChan
+}
+
+// SubscribeOnRootsAdded registers a callback function to be invoked when a roots/add notification is received.
+func (s *Session) SubscribeOnRootsAdded(f capability.RootsAddedFunc) {
+	s.Locker.RLock()
+	rootsCapability := s.RootsCapability
+	s.Locker.RUnlock()
+	rootsCapability.SubscribeOnRootsAdded(f)
+}
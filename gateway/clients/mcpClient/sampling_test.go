@@ -0,0 +1,102 @@
+package mcpClient_test
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gate4ai/gate4ai/gateway/clients/mcpClient"
+	"github.com/gate4ai/gate4ai/server"
+	"github.com/gate4ai/gate4ai/server/cmd/mcp-example-server/exampleCapability"
+	"github.com/gate4ai/gate4ai/shared"
+	"github.com/gate4ai/gate4ai/shared/config"
+	"github.com/gate4ai/gate4ai/shared/mcp/2025/schema"
+	"github.com/gate4ai/gate4ai/tests"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest"
+)
+
+// startExampleServer runs the example MCP server and returns its SSE URL.
+func startExampleServer(t *testing.T, ctx context.Context, logger *zap.Logger) string {
+	port, err := tests.FindAvailablePort()
+	if err != nil {
+		t.Fatalf("Failed to find available port: %v", err)
+	}
+	cfg := config.NewInternalConfig()
+	cfg.UserKeyHashes[config.HashAPIKey("sampling-key")] = "sampling-user"
+	options := append(exampleCapability.BuildOptions(logger), server.WithListenAddr(fmt.Sprintf(":%d", port)))
+	if _, err := server.Start(ctx, logger, cfg, options...); err != nil {
+		t.Fatalf("Failed to start example server: %v", err)
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		conn, err := net.DialTimeout("tcp", fmt.Sprintf("localhost:%d", port), 100*time.Millisecond)
+		if err == nil {
+			conn.Close()
+			return fmt.Sprintf("http://localhost:%d/sse", port)
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+	t.Fatalf("Example server on port %d did not start in time", port)
+	return ""
+}
+
+// TestSamplingRoundTrip calls the example server's sampleLLM tool, which asks the client
+// to sample via sampling/createMessage before returning the tool result.
+func TestSamplingRoundTrip(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+	logger := zaptest.NewLogger(t)
+	serverURL := startExampleServer(t, ctx, logger.With(zap.String("s", "example-server")))
+
+	c, err := mcpClient.New(serverURL, serverURL, logger.With(zap.String("s", "sampling-client")))
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	session := c.NewSession(ctx, mcpClient.WithAuthenticationBearer("sampling-key"))
+	defer session.Close()
+
+	var mu sync.Mutex
+	var received []schema.CreateMessageRequestParams
+	session.SamplingCapability.SubscribeOnSampling(func(params schema.CreateMessageRequestParams) (*schema.CreateMessageResult, error) {
+		mu.Lock()
+		received = append(received, params)
+		mu.Unlock()
+		return &schema.CreateMessageResult{
+			Role:    "assistant",
+			Content: schema.Content{Type: "text", Text: shared.PointerTo("mocked completion")},
+			Model:   "mock-model",
+		}, nil
+	})
+	if err := <-session.Open(); err != nil {
+		t.Fatalf("Failed to open session: %v", err)
+	}
+
+	result := <-session.CallTool(ctx, "sampleLLM", map[string]interface{}{"prompt": "tell a joke", "maxTokens": 50})
+	if result.Error != nil {
+		t.Fatalf("sampleLLM call failed: %v", result.Error)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(received) != 1 {
+		t.Fatalf("Expected one sampling/createMessage request, got %d", len(received))
+	}
+	request := received[0]
+	if request.MaxTokens != 50 || len(request.Messages) != 1 || request.Messages[0].Content.Text == nil ||
+		!strings.Contains(*request.Messages[0].Content.Text, "tell a joke") {
+		t.Fatalf("Unexpected sampling request: %+v", request)
+	}
+
+	if len(result.Result.Content) != 1 || result.Result.Content[0].Text == nil {
+		t.Fatalf("Unexpected tool result: %+v", result.Result)
+	}
+	if text := *result.Result.Content[0].Text; text != "LLM sampling result: mocked completion" {
+		t.Fatalf("Tool result = %q, want the sampled text with the \"LLM sampling result: \" prefix", text)
+	}
+}
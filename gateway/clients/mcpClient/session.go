@@ -46,6 +46,7 @@ type Session struct {
 	SamplingCapability           *capability.SamplingCapability
 	ResourcesCapability          *capability.ResourcesCapability
 	ResourceTemplatesCapability  *capability.ResourceTemplatesCapability
+	RootsCapability              *capability.RootsCapability
 	currentHeaders               map[string]string
 }
 
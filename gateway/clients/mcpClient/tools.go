@@ -9,6 +9,7 @@ import (
 	"github.com/gate4ai/gate4ai/shared"
 	// Use 2025 schema
 	schema "github.com/gate4ai/gate4ai/shared/mcp/2025/schema"
+	"go.opentelemetry.io/otel/propagation"
 	"go.uber.org/zap"
 )
 
@@ -114,11 +115,14 @@ func (s *Session) GetTools(ctx context.Context) chan GetToolsResult {
 // CallToolResult contains the result of a tool call request (using 2025 schema).
 type CallToolResult struct {
 	Result *schema.CallToolResult // Use 2025 schema type
+	Raw    json.RawMessage        // Unparsed result as sent by the server, set even if parsing failed
 	Error  error
 }
 
 // CallTool invokes a specific tool on the server by name with given arguments.
-// Returns a channel emitting a 2025 schema result.
+// Returns a channel emitting a 2025 schema result. A correlation ID stored in ctx with
+// shared.ContextWithCorrelationID is sent to the server in the shared.CorrelationIDHeader header,
+// and the span in ctx in the W3C traceparent header.
 func (s *Session) CallTool(ctx context.Context, name string, arguments map[string]interface{}) chan CallToolResult {
 	logger := s.BaseSession.Logger.With(zap.String("operation", "CallTool"), zap.String("toolName", name))
 	resultChan := make(chan CallToolResult, 1) // Buffered channel
@@ -167,7 +171,7 @@ func (s *Session) CallTool(ctx context.Context, name string, arguments map[strin
 			var callToolResult schema.CallToolResult
 			if err := json.Unmarshal(*msg.Result, &callToolResult); err != nil {
 				responseLogger.Error("Failed to unmarshal tool call result", zap.Error(err))
-				resultChan <- CallToolResult{Error: fmt.Errorf("failed to parse backend response: %w", err)}
+				resultChan <- CallToolResult{Raw: *msg.Result, Error: fmt.Errorf("failed to parse backend response: %w", err)}
 				return
 			}
 			msg.Processed = true
@@ -178,16 +182,21 @@ func (s *Session) CallTool(ctx context.Context, name string, arguments map[strin
 				// Construct an error message indicating the tool itself failed
 				toolErr := fmt.Errorf("tool '%s' execution failed on backend", name)
 				// Optionally try to extract more details from callToolResult.Content if available
-				resultChan <- CallToolResult{Result: &callToolResult, Error: toolErr}
+				resultChan <- CallToolResult{Result: &callToolResult, Raw: *msg.Result, Error: toolErr}
 			} else {
 				responseLogger.Debug("Successfully called tool")
-				resultChan <- CallToolResult{Result: &callToolResult, Error: nil}
+				resultChan <- CallToolResult{Result: &callToolResult, Raw: *msg.Result, Error: nil}
 			}
 		}
 
-		// Send the request
+		// Send the request, passing the caller's correlation ID on to the server
+		headers := map[string]string{}
+		if correlationID := shared.CorrelationIDFromContext(ctx); correlationID != "" {
+			headers[shared.CorrelationIDHeader] = correlationID
+		}
+		propagation.TraceContext{}.Inject(ctx, propagation.MapCarrier(headers))
 		logger.Debug("Sending tools/call request")
-		_, err := s.SendRequest("tools/call", params, callback)
+		_, err := s.SendRequestWithHeaders("tools/call", params, headers, callback)
 		if err != nil {
 			logger.Error("Failed to send tool call request", zap.Error(err))
 			// Try to send error through channel
@@ -17,10 +17,15 @@ import (
 
 // Environment variable names
 const (
-	EnvDatabaseURL = "GATE4AI_DATABASE_URL"
-	EnvConfigYAML  = "GATE4AI_CONFIG_YAML"
+	EnvDatabaseURL   = "GATE4AI_DATABASE_URL"
+	EnvConfigYAML    = "GATE4AI_CONFIG_YAML"
+	EnvConsulURL     = "GATE4AI_CONSUL_URL"
+	EnvConsulService = "GATE4AI_CONSUL_SERVICE"
 )
 
+// consulRefreshInterval is how often backends are re-read from Consul
+const consulRefreshInterval = 30 * time.Second
+
 func main() {
 	logerConfig := zap.NewProductionConfig()
 	logerConfig.EncoderConfig.EncodeTime = zapcore.ISO8601TimeEncoder
@@ -33,6 +38,11 @@ func main() {
 
 	configDB := flag.String("database-url", "", "PostgreSQL connection string for configuration")
 	configYAML := flag.String("config-yaml", "", "Path to YAML configuration file")
+	consulURLFlag := flag.String("consul-url", "", "Consul HTTP API address for backend discovery")
+	consulServiceFlag := flag.String("consul-service", "", "Consul service name whose instances are backends")
+	consulBackendPath := flag.String("consul-backend-path", "/sse", "Path appended to discovered backend addresses")
+	metricsPath := flag.String("metrics-path", "", "Serve Prometheus metrics on this path (disabled if empty)")
+	adminAPIPath := flag.String("admin-api-path", "", "Serve the admin API under this path, e.g. /admin/v1/ (disabled if empty)")
 	flag.Parse()
 
 	if configDB != nil && *configDB != "" && configYAML != nil && *configYAML != "" {
@@ -76,6 +86,14 @@ func main() {
 	}
 	defer cfg.Close()
 
+	// Report every invalid setting at once instead of failing on the first one used
+	if violations := cfg.Schema().Validate(cfg); len(violations) > 0 {
+		for _, violation := range violations {
+			logger.Error("Invalid configuration setting", zap.String("field", violation.Field), zap.String("problem", violation.Message))
+		}
+		logger.Fatal("Configuration is invalid", zap.Int("violations", len(violations)))
+	}
+
 	// Update logger level based on configuration
 	logLevel, err := cfg.LogLevel()
 	if err != nil {
@@ -111,8 +129,31 @@ func main() {
 		cancel()
 	}()
 
+	// Overlay backends discovered in Consul on top of the static configuration
+	consulURL := os.Getenv(EnvConsulURL)
+	if *consulURLFlag != "" {
+		consulURL = *consulURLFlag
+	}
+	consulService := os.Getenv(EnvConsulService)
+	if *consulServiceFlag != "" {
+		consulService = *consulServiceFlag
+	}
+	if consulURL != "" && consulService != "" {
+		logger.Info("Discovering backends from Consul", zap.String("url", consulURL), zap.String("service", consulService))
+		dynamicCfg := config.NewDynamicConfig(cfg, config.NewConsulBackendDiscovery(consulURL, consulService, *consulBackendPath, logger), logger)
+		go dynamicCfg.Run(ctx, consulRefreshInterval)
+		cfg = dynamicCfg
+	}
+
 	// Create and start the node
-	node, err := gateway.Start(ctx, logger, cfg, "")
+	var nodeOptions []gateway.NodeOption
+	if *metricsPath != "" {
+		nodeOptions = append(nodeOptions, gateway.WithMetricsEndpoint(*metricsPath))
+	}
+	if *adminAPIPath != "" {
+		nodeOptions = append(nodeOptions, gateway.WithAdminAPI(*adminAPIPath))
+	}
+	node, err := gateway.Start(ctx, logger, cfg, "", nodeOptions...)
 	if err != nil {
 		logger.Fatal("Node failed to start", zap.Error(err))
 	}
@@ -0,0 +1,47 @@
+package gateway
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	gwCapabilities "github.com/gate4ai/gate4ai/gateway/capability"
+	"github.com/gate4ai/gate4ai/server/metrics"
+	"go.uber.org/zap"
+)
+
+// WithMetricsEndpoint serves Prometheus metrics on path: tools/call requests and their
+// duration, backend errors by server and active client sessions (see the metrics package).
+func WithMetricsEndpoint(path string) NodeOption {
+	return func(node *Node) error {
+		if !strings.HasPrefix(path, "/") {
+			return fmt.Errorf("metrics path must start with '/', got %q", path)
+		}
+		node.metricsPath = path
+		node.enableMetrics()
+		return nil
+	}
+}
+
+// enableMetrics creates the collectors once, whichever options need them.
+func (n *Node) enableMetrics() {
+	if n.metrics != nil {
+		return
+	}
+	n.metrics = metrics.New()
+	n.gatewayOptions = append(n.gatewayOptions, gwCapabilities.WithMetrics(n.metrics))
+}
+
+// Metrics returns the collectors of the node, or nil without WithMetricsEndpoint or WithAdminAPI.
+func (n *Node) Metrics() *metrics.Metrics {
+	return n.metrics
+}
+
+// registerMetricsHandler serves the metrics on mux if WithMetricsEndpoint was given.
+func (n *Node) registerMetricsHandler(mux *http.ServeMux) {
+	if n.metricsPath == "" {
+		return
+	}
+	n.logger.Info("Registering metrics handler", zap.String("path", n.metricsPath))
+	mux.Handle(n.metricsPath, n.metrics.Handler())
+}
@@ -15,27 +15,119 @@ import (
 	serverextra "github.com/gate4ai/gate4ai/server/extra"
 	serverCapabilities "github.com/gate4ai/gate4ai/server/mcp/capability"
 	"github.com/gate4ai/gate4ai/server/mcp/validators"
+	"github.com/gate4ai/gate4ai/server/metrics"
 	"github.com/gate4ai/gate4ai/server/transport"
+	"github.com/gate4ai/gate4ai/shared"
 	"github.com/gate4ai/gate4ai/shared/config"
+	"go.opentelemetry.io/otel/trace"
 	"go.uber.org/zap"
 )
 
 // Node represents the main gateway component that coordinates all services
 type Node struct {
-	logger          *zap.Logger
-	cfg             config.IConfig
-	serverTransport *transport.Transport
-	sessionManager  *transport.Manager
-	httpServer      *http.Server   // Store the server instance
-	listenerErrChan <-chan error   // Channel for listener errors
-	shutdownWg      sync.WaitGroup // WaitGroup for shutdown
+	logger           *zap.Logger
+	cfg              config.IConfig
+	serverTransport  *transport.Transport
+	sessionManager   *transport.Manager
+	httpServer       *http.Server   // Store the server instance
+	listenerErrChan  <-chan error   // Channel for listener errors
+	shutdownWg       sync.WaitGroup // WaitGroup for shutdown
+	gatewayOptions   []gwCapabilities.GatewayOption
+	transportOptions []transport.TransportOption
+	// Prometheus metrics, set by WithMetricsEndpoint
+	metrics     *metrics.Metrics
+	metricsPath string
+	rateLimiter shared.RateLimiter // Set by WithRateLimiter
+	adminPath   string             // Set by WithAdminAPI
 }
 
 // NodeOption is a functional option for configuring the Node
 type NodeOption func(*Node) error
 
+// WithBackendPoolSize lets tools/call spread concurrent calls over up to n backend sessions
+// per client session and server (see capability.WithBackendPoolSize).
+func WithBackendPoolSize(n int) NodeOption {
+	return func(node *Node) error {
+		if n < 1 {
+			return fmt.Errorf("backend pool size must be at least 1, got %d", n)
+		}
+		node.gatewayOptions = append(node.gatewayOptions, gwCapabilities.WithBackendPoolSize(n))
+		return nil
+	}
+}
+
+// WithResultNormalizer makes tools/call normalize backend results with normalizer
+// (see capability.WithResultNormalizer).
+func WithResultNormalizer(normalizer gwCapabilities.ResultNormalizer) NodeOption {
+	return func(node *Node) error {
+		if normalizer == nil {
+			return errors.New("result normalizer cannot be nil")
+		}
+		node.gatewayOptions = append(node.gatewayOptions, gwCapabilities.WithResultNormalizer(normalizer))
+		return nil
+	}
+}
+
+// WithTracerProvider records request and backend call spans with tp instead of the global
+// OpenTelemetry tracer provider.
+func WithTracerProvider(tp trace.TracerProvider) NodeOption {
+	return func(node *Node) error {
+		if tp == nil {
+			return errors.New("tracer provider cannot be nil")
+		}
+		node.transportOptions = append(node.transportOptions, transport.WithTracerProvider(tp))
+		node.gatewayOptions = append(node.gatewayOptions, gwCapabilities.WithTracerProvider(tp))
+		return nil
+	}
+}
+
+// WithCircuitBreaker refuses tools/call to a backend for timeout after threshold consecutive
+// connection or RPC errors (see capability.WithCircuitBreaker).
+func WithCircuitBreaker(threshold int, timeout time.Duration) NodeOption {
+	return func(node *Node) error {
+		if threshold < 1 {
+			return fmt.Errorf("circuit breaker threshold must be at least 1, got %d", threshold)
+		}
+		if timeout <= 0 {
+			return fmt.Errorf("circuit breaker timeout must be positive, got %s", timeout)
+		}
+		node.gatewayOptions = append(node.gatewayOptions, gwCapabilities.WithCircuitBreaker(threshold, timeout))
+		return nil
+	}
+}
+
+// WithBackendRetryPolicy retries the health probe and initialization of new backend sessions
+// with exponential backoff (see capability.WithBackendRetryPolicy).
+func WithBackendRetryPolicy(policy gwCapabilities.RetryPolicy) NodeOption {
+	return func(node *Node) error {
+		if policy.MaxAttempts < 1 {
+			return fmt.Errorf("retry policy max attempts must be at least 1, got %d", policy.MaxAttempts)
+		}
+		if policy.InitialDelay < 0 || policy.MaxDelay < 0 {
+			return fmt.Errorf("retry policy delays cannot be negative, got initial %s and max %s", policy.InitialDelay, policy.MaxDelay)
+		}
+		if policy.Multiplier < 1 {
+			return fmt.Errorf("retry policy multiplier must be at least 1, got %g", policy.Multiplier)
+		}
+		node.gatewayOptions = append(node.gatewayOptions, gwCapabilities.WithBackendRetryPolicy(policy))
+		return nil
+	}
+}
+
+// WithRateLimiter refuses the client requests and notifications rl does not allow, with a
+// "Rate limit exceeded" error, before they are routed to any backend.
+func WithRateLimiter(rl shared.RateLimiter) NodeOption {
+	return func(node *Node) error {
+		if rl == nil {
+			return errors.New("rate limiter cannot be nil")
+		}
+		node.rateLimiter = rl
+		return nil
+	}
+}
+
 // New creates a new gateway node with the provided logger and config
-func New(logger *zap.Logger, cfg config.IConfig) (*Node, error) {
+func New(logger *zap.Logger, cfg config.IConfig, options ...NodeOption) (*Node, error) {
 	if logger == nil {
 		// Default logger if needed, though Start usually provides one
 		logger, _ = zap.NewProduction()
@@ -49,19 +141,31 @@ func New(logger *zap.Logger, cfg config.IConfig) (*Node, error) {
 		// shutdownWg initialization needed
 	}
 	n.shutdownWg.Add(1) // Initialize WaitGroup counter for the main server loop
+	for _, option := range options {
+		if err := option(n); err != nil {
+			return nil, err
+		}
+	}
 
 	var err error
 	n.sessionManager, err = transport.NewManager(n.logger, n.cfg)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create session manager: %w", err)
 	}
+	if n.metrics != nil {
+		n.sessionManager.AddSessionObserver(n.metrics)
+	}
+	if n.rateLimiter != nil {
+		n.sessionManager.Input().SetRateLimiter(n.rateLimiter)
+	}
 	// Add default validators and gateway-specific capabilities
 	n.sessionManager.AddValidator(validators.CreateDefaultValidators()...)
 	n.sessionManager.AddCapability(
-		serverCapabilities.NewBase(n.logger, n.sessionManager), // Base MCP handlers
-		gwCapabilities.NewGatewayCapability(n.logger, n.cfg),   // Gateway routing logic
+		serverCapabilities.NewBase(n.logger, n.sessionManager),                    // Base MCP handlers
+		gwCapabilities.NewGatewayCapability(n.logger, n.cfg, n.gatewayOptions...), // Gateway routing logic
+		gwCapabilities.NewGatewayA2ACapability(n.logger, n.cfg),                   // A2A routing to per-user backend agents
 	)
-	n.serverTransport, err = transport.New(n.sessionManager, n.logger, n.cfg)
+	n.serverTransport, err = transport.New(n.sessionManager, n.logger, n.cfg, n.transportOptions...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create server transport: %w", err)
 	}
@@ -75,6 +179,13 @@ func (n *Node) Start(ctx context.Context, mux *http.ServeMux, overwriteListenAdd
 	// --- Register Handlers ---
 	n.serverTransport.RegisterMCPHandlers(mux)
 
+	agentCard, err := n.cfg.GetA2AAgentCard(transport.A2A_PATH)
+	if err != nil {
+		n.logger.Warn("A2A agent card not configured, A2A routing disabled", zap.Error(err))
+	} else {
+		n.serverTransport.RegisterA2AHandlers(mux, agentCard)
+	}
+
 	discoveringHandlerPath, err := n.cfg.DiscoveringHandlerPath()
 	if err != nil {
 		n.logger.Warn("Failed to get discovering handler path from config", zap.Error(err))
@@ -87,9 +198,17 @@ func (n *Node) Start(ctx context.Context, mux *http.ServeMux, overwriteListenAdd
 		mux.HandleFunc(discoveringHandlerPath, discovering.Handler(n.logger))
 	}
 
+	n.registerMetricsHandler(mux)
+	n.registerAdminHandler(mux)
+
 	n.logger.Info("Registering status handler", zap.String("path", "/status"))
 	mux.HandleFunc("/status", serverextra.StatusHandler(n.cfg, n.logger))
 
+	startTime := time.Now()
+	n.logger.Info("Registering health handlers", zap.Strings("paths", []string{"/health", "/ready"}))
+	mux.HandleFunc("/health", serverextra.HealthHandler(n.cfg, n.logger, startTime, n.backendSessionsCheck(false)))
+	mux.HandleFunc("/ready", serverextra.HealthHandler(n.cfg, n.logger, startTime, n.backendSessionsCheck(true)))
+
 	frontendAddress, err := n.cfg.FrontendAddressForProxy()
 	if err != nil {
 		n.logger.Warn("Failed to get frontend address for proxy from config", zap.Error(err))
@@ -147,6 +266,7 @@ func (n *Node) Start(ctx context.Context, mux *http.ServeMux, overwriteListenAdd
 		defer cancel()
 
 		// Close MCP sessions first
+		n.serverTransport.Close()
 		n.sessionManager.CloseAllSessions()
 
 		// Shutdown HTTP server using the shared utility function
@@ -162,6 +282,33 @@ func (n *Node) Start(ctx context.Context, mux *http.ServeMux, overwriteListenAdd
 	return nil
 }
 
+// backendSessionsCheck fails when backend sessions exist but none of them is connected.
+// With requireConnected it also fails when no backend session has been established yet.
+func (n *Node) backendSessionsCheck(requireConnected bool) serverextra.HealthCheck {
+	return func(ctx context.Context) error {
+		total, connected := 0, 0
+		for _, clientSession := range n.sessionManager.GetSessions() {
+			backendSessions, _, _ := gwCapabilities.LoadBackendSessions(clientSession.GetParams())
+			for _, backendSession := range backendSessions {
+				if backendSession == nil {
+					continue
+				}
+				total++
+				if backendSession.GetStatus() == shared.StatusConnected {
+					connected++
+				}
+			}
+		}
+		if connected > 0 || (total == 0 && !requireConnected) {
+			return nil
+		}
+		if total == 0 {
+			return errors.New("no backend session established")
+		}
+		return fmt.Errorf("all %d backend sessions are failing", total)
+	}
+}
+
 // WaitForShutdown waits for the node's main server loop to finish.
 func (n *Node) WaitForShutdown(timeout time.Duration) bool {
 	doneChan := make(chan struct{})
@@ -184,8 +331,8 @@ func (n *Node) WaitForShutdown(timeout time.Duration) bool {
 }
 
 // Start is a convenience function to create and start the node
-func Start(ctx context.Context, logger *zap.Logger, cfg config.IConfig, overwriteListenAddr string) (*Node, error) {
-	node, err := New(logger, cfg)
+func Start(ctx context.Context, logger *zap.Logger, cfg config.IConfig, overwriteListenAddr string, options ...NodeOption) (*Node, error) {
+	node, err := New(logger, cfg, options...)
 	if err != nil {
 		// Use Fatalf only if called directly from main, otherwise return error
 		return nil, fmt.Errorf("failed to create gateway node: %w", err)
@@ -199,4 +346,4 @@ func Start(ctx context.Context, logger *zap.Logger, cfg config.IConfig, overwrit
 		return nil, fmt.Errorf("gateway node failed to start: %w", err)
 	}
 	return node, nil
-}
\ No newline at end of file
+}
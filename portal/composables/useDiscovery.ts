@@ -24,6 +24,7 @@ interface DiscoveredTool {
   name: string;
   description?: string;
   inputSchema?: Record<string, unknown>;
+  annotations?: { title?: string };
 }
 interface DiscoveredSkill {
   id: string;
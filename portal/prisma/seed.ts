@@ -220,6 +220,42 @@ async function main() {
       value: "",
       frontend: false,
     },
+    {
+      key: "gateway_allowed_origins",
+      group: "gateway",
+      name: "Gateway Allowed Origins",
+      description:
+        "Origins allowed to make cross-site browser requests to the gateway MCP endpoints (JSON array, \"*\" allows any).",
+      value: [],
+      frontend: false,
+    },
+    {
+      key: "gateway_sse_keepalive_interval",
+      group: "gateway",
+      name: "Gateway SSE Keepalive Interval",
+      description:
+        "How often idle SSE streams get a keepalive comment so proxies do not close them (Go duration, \"0s\" disables).",
+      value: "15s",
+      frontend: false,
+    },
+    {
+      key: "gateway_admin_token",
+      group: "gateway",
+      name: "Gateway Admin Token",
+      description:
+        "Bearer token of the gateway admin API (empty disables the API).",
+      value: "",
+      frontend: false,
+    },
+    {
+      key: "gateway_max_subscriptions_per_user",
+      group: "gateway",
+      name: "Max Subscriptions Per User",
+      description:
+        "Maximum number of servers a user can subscribe to (0 means no limit). Over the limit the gateway only connects the first servers by slug.",
+      value: 0,
+      frontend: false,
+    },
     {
       key: "gateway_ssl_acme_domains",
       group: "gateway",
@@ -154,6 +154,7 @@ export default defineEventHandler(async (event) => {
       tools: server.tools.map((tool) => ({
         id: tool.id,
         name: tool.name,
+        title: tool.title,
         description: tool.description,
         parameters: tool.parameters,
       })),
@@ -15,6 +15,7 @@ const parameterSchema = z.object({
 
 const toolSchema = z.object({
   name: z.string().min(1, "Tool name is required"),
+  title: z.string().optional().nullable(),
   description: z.string().optional().nullable(),
   parameters: z.array(parameterSchema).optional().default([]),
 });
@@ -71,6 +72,21 @@ const subscriptionHeaderTemplateSchema = z
   .optional()
   .default([]);
 
+/** Highest numeric suffix tried when the requested slug is taken */
+const MAX_SLUG_SUFFIX = 99;
+
+/** Whether error is the unique constraint violation of the server slug */
+function isSlugConflict(error: unknown): boolean {
+  if (!(error instanceof Error) || !("code" in error)) {
+    return false;
+  }
+  const { code, meta } = error as {
+    code: string;
+    meta?: { target?: string[] };
+  };
+  return code === "P2002" && !!meta?.target?.includes("slug");
+}
+
 // --- Main Create Server Schema ---
 const createServerSchema = z
   .object({
@@ -122,166 +138,186 @@ export default defineEventHandler(async (event) => {
     const validatedData = validationResult.data;
 
     // 3. Create server with related data in a transaction
-    const newServer = await prisma.$transaction(async (tx) => {
-      // Create the base server
-      const server = await tx.server.create({
-        data: {
-          name: validatedData.name,
-          slug: validatedData.slug,
-          protocol: validatedData.protocol as ServerProtocol,
-          description: validatedData.description,
-          website: validatedData.website,
-          email: validatedData.email,
-          imageUrl: validatedData.imageUrl,
-          serverUrl: validatedData.serverUrl,
-          protocolVersion: validatedData.protocolVersion,
-          headers:
-            (validatedData.headers as Prisma.JsonObject) ?? Prisma.JsonNull, // Save headers
-          // status and availability will use Prisma schema defaults
-          owners: {
-            create: [{ userId: authenticatedUser.id }],
-          },
-          // Create template items if provided
-          ...(validatedData.subscriptionHeaderTemplate &&
-            validatedData.subscriptionHeaderTemplate.length > 0 && {
-              subscriptionHeaderTemplate: {
-                createMany: {
-                  data: validatedData.subscriptionHeaderTemplate.map(
-                    (item) => ({
-                      key: item.key,
-                      description: item.description,
-                      required: item.required,
-                    })
-                  ),
+    const createServer = (slug: string) =>
+      prisma.$transaction(async (tx) => {
+        // Create the base server
+        const server = await tx.server.create({
+          data: {
+            name: validatedData.name,
+            slug,
+            protocol: validatedData.protocol as ServerProtocol,
+            description: validatedData.description,
+            website: validatedData.website,
+            email: validatedData.email,
+            imageUrl: validatedData.imageUrl,
+            serverUrl: validatedData.serverUrl,
+            protocolVersion: validatedData.protocolVersion,
+            headers:
+              (validatedData.headers as Prisma.JsonObject) ?? Prisma.JsonNull, // Save headers
+            // status and availability will use Prisma schema defaults
+            owners: {
+              create: [{ userId: authenticatedUser.id }],
+            },
+            // Create template items if provided
+            ...(validatedData.subscriptionHeaderTemplate &&
+              validatedData.subscriptionHeaderTemplate.length > 0 && {
+                subscriptionHeaderTemplate: {
+                  createMany: {
+                    data: validatedData.subscriptionHeaderTemplate.map(
+                      (item) => ({
+                        key: item.key,
+                        description: item.description,
+                        required: item.required,
+                      })
+                    ),
+                  },
                 },
-              },
-            }),
-        },
-        select: {
-          // Select fields needed for response and navigation
-          id: true,
-          slug: true,
-          name: true,
-          description: true,
-          website: true,
-          email: true,
-          imageUrl: true,
-          serverUrl: true,
-          status: true,
-          availability: true,
-          protocol: true,
-          protocolVersion: true,
-          createdAt: true,
-          updatedAt: true,
-          owners: {
-            select: { user: { select: { id: true, name: true, email: true } } },
+              }),
           },
-        },
-      });
-
-      // Create MCP tools and parameters if protocol is MCP
-      if (
-        validatedData.protocol === "MCP" &&
-        validatedData.tools &&
-        validatedData.tools.length > 0
-      ) {
-        for (const toolData of validatedData.tools) {
-          const newTool = await tx.tool.create({
-            data: {
-              name: toolData.name,
-              description: toolData.description,
-              serverId: server.id,
+          select: {
+            // Select fields needed for response and navigation
+            id: true,
+            slug: true,
+            name: true,
+            description: true,
+            website: true,
+            email: true,
+            imageUrl: true,
+            serverUrl: true,
+            status: true,
+            availability: true,
+            protocol: true,
+            protocolVersion: true,
+            createdAt: true,
+            updatedAt: true,
+            owners: {
+              select: {
+                user: { select: { id: true, name: true, email: true } },
+              },
             },
-            select: { id: true },
-          });
+          },
+        });
 
-          if (toolData.parameters && toolData.parameters.length > 0) {
-            await tx.toolParameter.createMany({
-              data: toolData.parameters.map((param) => ({
-                name: param.name,
-                type: param.type,
-                description: param.description,
-                required: param.required,
-                toolId: newTool.id,
-              })),
+        // Create MCP tools and parameters if protocol is MCP
+        if (
+          validatedData.protocol === "MCP" &&
+          validatedData.tools &&
+          validatedData.tools.length > 0
+        ) {
+          for (const toolData of validatedData.tools) {
+            const newTool = await tx.tool.create({
+              data: {
+                name: toolData.name,
+                title: toolData.title,
+                description: toolData.description,
+                serverId: server.id,
+              },
+              select: { id: true },
             });
+
+            if (toolData.parameters && toolData.parameters.length > 0) {
+              await tx.toolParameter.createMany({
+                data: toolData.parameters.map((param) => ({
+                  name: param.name,
+                  type: param.type,
+                  description: param.description,
+                  required: param.required,
+                  toolId: newTool.id,
+                })),
+              });
+            }
           }
         }
-      }
-
-      // Create A2A skills if protocol is A2A
-      if (
-        validatedData.protocol === "A2A" &&
-        validatedData.a2aSkills &&
-        validatedData.a2aSkills.length > 0
-      ) {
-        await tx.a2ASkill.createMany({
-          data: validatedData.a2aSkills.map((skill) => ({
-            name: skill.name,
-            description: skill.description,
-            tags: skill.tags,
-            examples: skill.examples,
-            inputModes: skill.inputModes,
-            outputModes: skill.outputModes,
-            serverId: server.id,
-          })),
-        });
-      }
 
-      // Create REST endpoints if protocol is REST
-      if (
-        validatedData.protocol === "REST" &&
-        validatedData.restEndpoints &&
-        validatedData.restEndpoints.length > 0
-      ) {
-        for (const endpointData of validatedData.restEndpoints) {
-          const newEndpoint = await tx.rESTEndpoint.create({
-            data: {
-              path: endpointData.path,
-              method: endpointData.method,
-              description: endpointData.description,
+        // Create A2A skills if protocol is A2A
+        if (
+          validatedData.protocol === "A2A" &&
+          validatedData.a2aSkills &&
+          validatedData.a2aSkills.length > 0
+        ) {
+          await tx.a2ASkill.createMany({
+            data: validatedData.a2aSkills.map((skill) => ({
+              name: skill.name,
+              description: skill.description,
+              tags: skill.tags,
+              examples: skill.examples,
+              inputModes: skill.inputModes,
+              outputModes: skill.outputModes,
               serverId: server.id,
-            },
-            select: { id: true },
+            })),
           });
+        }
 
-          if (endpointData.queryParams && endpointData.queryParams.length > 0) {
-            await tx.rESTParameter.createMany({
-              data: endpointData.queryParams.map((param) => ({
-                name: param.name,
-                type: param.type,
-                description: param.description,
-                required: param.required,
-                endpointId: newEndpoint.id,
-              })),
-            });
-          }
-
-          if (endpointData.requestBody) {
-            await tx.rESTRequestBody.create({
+        // Create REST endpoints if protocol is REST
+        if (
+          validatedData.protocol === "REST" &&
+          validatedData.restEndpoints &&
+          validatedData.restEndpoints.length > 0
+        ) {
+          for (const endpointData of validatedData.restEndpoints) {
+            const newEndpoint = await tx.rESTEndpoint.create({
               data: {
-                description: endpointData.requestBody.description,
-                example: endpointData.requestBody.example,
-                endpointId: newEndpoint.id,
+                path: endpointData.path,
+                method: endpointData.method,
+                description: endpointData.description,
+                serverId: server.id,
               },
+              select: { id: true },
             });
-          }
 
-          if (endpointData.responses && endpointData.responses.length > 0) {
-            await tx.rESTResponse.createMany({
-              data: endpointData.responses.map((response) => ({
-                statusCode: response.statusCode,
-                description: response.description,
-                example: response.example,
-                endpointId: newEndpoint.id,
-              })),
-            });
+            if (
+              endpointData.queryParams &&
+              endpointData.queryParams.length > 0
+            ) {
+              await tx.rESTParameter.createMany({
+                data: endpointData.queryParams.map((param) => ({
+                  name: param.name,
+                  type: param.type,
+                  description: param.description,
+                  required: param.required,
+                  endpointId: newEndpoint.id,
+                })),
+              });
+            }
+
+            if (endpointData.requestBody) {
+              await tx.rESTRequestBody.create({
+                data: {
+                  description: endpointData.requestBody.description,
+                  example: endpointData.requestBody.example,
+                  endpointId: newEndpoint.id,
+                },
+              });
+            }
+
+            if (endpointData.responses && endpointData.responses.length > 0) {
+              await tx.rESTResponse.createMany({
+                data: endpointData.responses.map((response) => ({
+                  statusCode: response.statusCode,
+                  description: response.description,
+                  example: response.example,
+                  endpointId: newEndpoint.id,
+                })),
+              });
+            }
           }
         }
-      }
 
-      return server; // Return the created server data
-    });
+        return server; // Return the created server data
+      });
+
+    // A taken slug gets the lowest free numeric suffix, e.g. "my-server-2"
+    let newServer: Awaited<ReturnType<typeof createServer>> | undefined;
+    for (let n = 1; !newServer; n++) {
+      const slug = n === 1 ? validatedData.slug : `${validatedData.slug}-${n}`;
+      try {
+        newServer = await createServer(slug);
+      } catch (error) {
+        if (!isSlugConflict(error) || n >= MAX_SLUG_SUFFIX) {
+          throw error;
+        }
+      }
+    }
 
     // 4. Set status code and return response
     event.node.res.statusCode = 201;
@@ -294,13 +330,8 @@ export default defineEventHandler(async (event) => {
     ) {
       throw error;
     }
-    // Handle potential Prisma errors (e.g., unique constraint on slug)
-    if (
-      error instanceof Error &&
-      "code" in error &&
-      (error as { code: string }).code === "P2002" &&
-      (error as { meta?: { target?: string[] } }).meta?.target?.includes("slug")
-    ) {
+    // Every suffix up to MAX_SLUG_SUFFIX was taken
+    if (isSlugConflict(error)) {
       throw createError({
         statusCode: 409,
         statusMessage: "A server with this slug already exists.",
@@ -23,6 +23,17 @@ const subscribeSchema = subscribeBaseSchema.extend({
   headerValues: headerValuesSchema,
 }); //.strict(); // Consider if strict is needed
 
+// Reads the "gateway_max_subscriptions_per_user" setting; 0 (or no setting) means no limit.
+// The gateway reads the same setting and ignores the subscriptions over it.
+async function getMaxSubscriptionsPerUser(): Promise<number> {
+  const setting = await prisma.settings.findUnique({
+    where: { key: "gateway_max_subscriptions_per_user" },
+    select: { value: true },
+  });
+  const value = Number(setting?.value ?? 0);
+  return Number.isInteger(value) && value > 0 ? value : 0;
+}
+
 export default defineEventHandler(async (event) => {
   const user = checkAuth(event); // Ensure user is authenticated
 
@@ -100,26 +111,44 @@ export default defineEventHandler(async (event) => {
     }
     // --- End Header Validation ---
 
-    // 5. Create subscription
-    const newSubscription = await prisma.subscription.create({
-      data: {
-        userId: user.id,
-        serverId: serverId,
-        status: "ACTIVE", // Defaulting to ACTIVE, adjust if PENDING needed
-        headerValues:
-          Object.keys(validatedHeaderValues).length > 0
-            ? (validatedHeaderValues as Prisma.JsonObject)
-            : Prisma.JsonNull, // Save validated headers or null
-      },
-      select: {
-        // Return necessary fields
-        id: true,
-        serverId: true,
-        userId: true,
-        status: true,
-        headerValues: true, // Return the saved headers
+    // 5. Create subscription, unless the user reached the subscription quota.
+    // Counting inside the transaction keeps concurrent requests from both passing the check.
+    const maxSubscriptions = await getMaxSubscriptionsPerUser();
+    const newSubscription = await prisma.$transaction(
+      async (tx) => {
+        if (maxSubscriptions > 0) {
+          const subscriptionCount = await tx.subscription.count({
+            where: { userId: user.id, status: { not: "BLOCKED" } },
+          });
+          if (subscriptionCount >= maxSubscriptions) {
+            throw createError({
+              statusCode: 403,
+              statusMessage: `Subscription limit reached: users can subscribe to at most ${maxSubscriptions} servers.`,
+            });
+          }
+        }
+        return tx.subscription.create({
+          data: {
+            userId: user.id,
+            serverId: serverId,
+            status: "ACTIVE", // Defaulting to ACTIVE, adjust if PENDING needed
+            headerValues:
+              Object.keys(validatedHeaderValues).length > 0
+                ? (validatedHeaderValues as Prisma.JsonObject)
+                : Prisma.JsonNull, // Save validated headers or null
+          },
+          select: {
+            // Return necessary fields
+            id: true,
+            serverId: true,
+            userId: true,
+            status: true,
+            headerValues: true, // Return the saved headers
+          },
+        });
       },
-    });
+      { isolationLevel: Prisma.TransactionIsolationLevel.Serializable }
+    );
 
     event.node.res.statusCode = 201; // Created
     return newSubscription;
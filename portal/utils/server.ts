@@ -51,6 +51,7 @@ export interface ServerParameter {
 export interface ServerTool {
   id: string;
   name: string;
+  title?: string | null;
   description?: string;
   parameters: ServerParameter[];
 }
@@ -2,19 +2,24 @@ package a2a
 
 import (
 	"context"
+	"encoding/base64"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"log"
+	"maps"
 	"sync"
 	"time"
 
+	"github.com/gate4ai/gate4ai/server/mcp/capability"
 	// Needed for manager interface dependency
 	"github.com/gate4ai/gate4ai/server/transport"
 	"github.com/gate4ai/gate4ai/shared"
 	a2aSchema "github.com/gate4ai/gate4ai/shared/a2a/2025-draft/schema"
+	"github.com/gate4ai/gate4ai/shared/config"
 	mcpSchema "github.com/gate4ai/gate4ai/shared/mcp/2025/schema"
 
+	"go.opentelemetry.io/otel/trace"
 	"go.uber.org/zap"
 )
 
@@ -28,12 +33,85 @@ var _ shared.IServerCapability = (*A2ACapability)(nil)
 type A2ACapability struct {
 	logger       *zap.Logger
 	manager      transport.ISessionManager // To interact with sessions for SSE/Resubscribe
-	taskStore    TaskStore  // Interface for task persistence
-	agentHandler A2AHandler // The actual agent logic implementation
+	taskStore    TaskStore                 // Interface for task persistence
+	agentHandler A2AHandler                // The actual agent logic implementation
 	handlers     map[string]func(*shared.Message) (interface{}, error)
 	// Track running handlers for cancellation
 	runningHandlersMu sync.Mutex
-	runningHandlers   map[string]context.CancelFunc // taskID -> cancelFunc
+	runningHandlers   map[string]*runningHandler // taskID -> cancel func and event hub
+	// Send only new status message parts in sendSubscribe streams
+	statusDeltaEncoding bool
+	// Fail the task on malformed artifacts instead of only logging them
+	strictArtifactValidation bool
+	// Exported task results: set by WithAutoExportCompletedTasks, tracked for DeleteTask
+	autoExportResources *capability.ResourcesCapability
+	exportsMu           sync.Mutex
+	exports             map[string]*capability.ResourcesCapability // taskID -> capability holding its resource
+	// Per-user limit on new tasks, set by WithTaskCreationRateLimit
+	taskRateLimiter *taskRateLimiter
+	// Webhooks notified when a task reaches a terminal state, set by tasks/setWebhook
+	webhooksMu     sync.Mutex
+	webhooks       map[string]taskWebhook // taskID -> webhook
+	webhookBackoff time.Duration
+	// Push notification configs set by tasks/pushNotification/set; retries use webhookBackoff
+	pushTargetsMu sync.Mutex
+	pushTargets   map[string]*taskPushTarget // taskID -> config and cached OAuth token
+	// Deadline for a sendSubscribe handler's first update, set by WithHandlerStartupTimeout
+	handlerStartupTimeout time.Duration
+	// Record Task.Timeline, set by WithTimelineRecording
+	timelineRecording bool
+	// Per-user feature flags, set by WithFeatureFlags
+	featureFlags config.IConfig
+	// Tracer provider for task spans, set by WithTracerProvider; nil uses the global provider
+	tracerProvider trace.TracerProvider
+	// Poll interval of the deferred task scheduler, set by WithScheduler
+	schedulerInterval time.Duration
+	// Stream events kept per task for tasks/resubscribe, set by WithReplayBufferSize
+	replayBufferSize int
+	replayBuffersMu  sync.Mutex
+	replayBuffers    map[string]*ReplayBuffer // taskID -> buffer of its latest sendSubscribe run
+	// Merges appended artifact chunks, set by WithArtifactMergeStrategy
+	artifactMergeStrategy ArtifactMergeStrategy
+	// Updates buffered per task run, set by WithUpdateBufferSize; 0 is unbounded
+	updateBufferSize int
+	// Inline user file parts given by URI, set by WithFileDownload* options
+	fileDownloadEnabled  bool
+	fileDownloadMaxBytes int64
+	fileDownloadTimeout  time.Duration
+	// NDJSON log of raw messages, set by WithDebugMessageLog
+	debugLog *debugMessageLog
+	// Workers running agent handlers, set by WithWorkerPool; nil starts a goroutine per task
+	workerPool            *workerPool
+	workerPoolWaitTimeout time.Duration
+	// Notified of task state changes, set by WithStateTransitionObserver
+	stateObserver StateTransitionObserver
+	// Messages kept in stored task histories, set by WithMaxHistoryLength; 0 keeps all
+	maxHistoryLength int
+	// Running and queued sendSubscribe tasks per user, set by WithMaxConcurrentTasksPerUser
+	userTaskSlots *userTaskSlots
+	// Closed by Close to stop the background routines
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+// A2AOption configures an A2ACapability.
+type A2AOption func(*A2ACapability)
+
+// WithStatusDeltaEncoding makes tasks/sendSubscribe streams send only the status message
+// parts added since the previous status event. Clients rebuild the full message with
+// a2aSchema.StatusDeltaDecoder.
+func WithStatusDeltaEncoding(enabled bool) A2AOption {
+	return func(ac *A2ACapability) {
+		ac.statusDeltaEncoding = enabled
+	}
+}
+
+// WithStrictArtifactValidation makes artifacts that fail validateArtifact fail the task.
+// By default such artifacts are stored and a warning is logged.
+func WithStrictArtifactValidation(enabled bool) A2AOption {
+	return func(ac *A2ACapability) {
+		ac.strictArtifactValidation = enabled
+	}
 }
 
 // NewA2ACapability creates a new A2A capability.
@@ -42,6 +120,7 @@ func NewA2ACapability(
 	manager transport.ISessionManager, // Manager is needed for SSE/Resubscribe
 	store TaskStore,
 	handler A2AHandler,
+	options ...A2AOption,
 ) *A2ACapability {
 	// While A2A *could* potentially operate without MCP/SSE, the current architecture
 	// relies on the manager for session handling, especially for sendSubscribe/resubscribe.
@@ -59,21 +138,59 @@ func NewA2ACapability(
 		manager:         manager,
 		taskStore:       store,
 		agentHandler:    handler,
-		runningHandlers: make(map[string]context.CancelFunc),
+		runningHandlers: make(map[string]*runningHandler),
+		exports:         make(map[string]*capability.ResourcesCapability),
+		webhooks:        make(map[string]taskWebhook),
+		webhookBackoff:  DefaultWebhookRetryBackoff,
+		pushTargets:     make(map[string]*taskPushTarget),
+		replayBuffers:   make(map[string]*ReplayBuffer),
+		done:            make(chan struct{}),
+
+		updateBufferSize:     DefaultUpdateBufferSize,
+		fileDownloadMaxBytes: DefaultFileDownloadMaxBytes,
+		fileDownloadTimeout:  DefaultFileDownloadTimeout,
+
+		workerPoolWaitTimeout: DefaultWorkerPoolWaitTimeout,
 	}
-	// Map JSON-RPC method names to handler functions within this capability
+	for _, option := range options {
+		option(ac)
+	}
+	if ac.artifactMergeStrategy == nil {
+		ac.artifactMergeStrategy = AppendParts{}
+	}
+	if ac.taskRateLimiter != nil {
+		go ac.startTaskLimiterCleanup()
+	}
+	if ac.schedulerInterval > 0 {
+		go ac.startScheduler()
+	}
+	// Map JSON-RPC method names to handler functions within this capability.
+	// Registering tasks/pushNotification/set makes the agent card advertise push notifications
+	// (see transport.DeriveCapabilities).
 	ac.handlers = map[string]func(*shared.Message) (interface{}, error){
-		"tasks/send":                 ac.handleTaskSend,
-		"tasks/sendSubscribe":        ac.handleTaskSendSubscribe,
-		"tasks/get":                  ac.handleTaskGet,
-		"tasks/cancel":               ac.handleTaskCancel,
-		"tasks/pushNotification/set": ac.handleTaskPushNotificationSet, // Returns unsupported
-		"tasks/pushNotification/get": ac.handleTaskPushNotificationGet, // Returns unsupported
-		"tasks/resubscribe":          ac.handleTaskResubscribe,         // Basic implementation
+		"tasks/send":          ac.handleTaskSend,
+		"tasks/sendSubscribe": ac.handleTaskSendSubscribe,
+		"tasks/get":           ac.handleTaskGet,
+		"tasks/cancel":        ac.handleTaskCancel,
+		"tasks/resubscribe":   ac.handleTaskResubscribe, // Basic implementation
+		"tasks/setWebhook":    ac.handleTaskSetWebhook,
+		"tasks/list":          ac.handleTaskList,
+
+		"tasks/pushNotification/set": ac.handleTaskPushNotificationSet,
+		"tasks/pushNotification/get": ac.handleTaskPushNotificationGet,
+	}
+	for method, handler := range ac.handlers {
+		ac.handlers[method] = ac.withDebugLog(method, handler)
 	}
 	return ac
 }
 
+// Close stops the background routines of the capability, such as the scheduler. Running
+// tasks are not affected.
+func (ac *A2ACapability) Close() {
+	ac.closeOnce.Do(func() { close(ac.done) })
+}
+
 // GetHandlers returns the map of JSON-RPC method handlers this capability provides.
 func (ac *A2ACapability) GetHandlers() map[string]func(*shared.Message) (interface{}, error) {
 	return ac.handlers
@@ -93,19 +210,58 @@ func (ac *A2ACapability) handleTaskSend(msg *shared.Message) (interface{}, error
 	// Add taskID to logger *after* parsing params
 	logger = logger.With(zap.String("taskID", params.ID))
 	logger.Debug("Handling tasks/send request")
+	if params.ScheduledAt != nil && ac.schedulerInterval <= 0 {
+		return nil, &shared.JSONRPCError{Code: shared.JSONRPCErrorInvalidParams, Message: "Scheduled tasks are not enabled on this server"}
+	}
+	// Derive from the HTTP request context so a disconnected client stops the handler
+	ctx, span := ac.startTaskSpan(msg.Context(), params.ID, msg.Session.GetID())
+	defer span.End()
+	storeCtx := context.WithoutCancel(ctx) // Store calls are traced under the task span but never cancelled
+	if err := ac.prepareUserMessage(ctx, &params.Message); err != nil {
+		logger.Warn("Rejected tasks/send message", zap.Error(err))
+		return nil, err
+	}
+	var pushOAuth *PushNotificationOAuth2Credentials // Registered once the task may be used
+	if params.PushNotification != nil {
+		var configErr *shared.JSONRPCError
+		if pushOAuth, configErr = parsePushConfig(*params.PushNotification); configErr != nil {
+			logger.Warn("Rejected tasks/send push notification config", zap.String("reason", configErr.Message))
+			return nil, configErr
+		}
+	}
+
+	// --- Reserve a Worker Unless the Task Is Deferred ---
+	var slot *workerSlot
+	deferred := params.ScheduledAt != nil && params.ScheduledAt.After(time.Now())
+	if !deferred {
+		var busyErr *a2aSchema.JSONRPCError
+		if slot, busyErr = ac.reserveWorker(); busyErr != nil {
+			logger.Warn("No free worker for tasks/send")
+			return nil, busyErr
+		}
+		defer slot.release()
+	}
 
 	// --- Load or Create Task State ---
-	task, err := ac.loadOrCreateTask(context.Background(), params.ID, msg.Session.GetID(), params.Metadata)
+	loadStart := time.Now()
+	task, err := ac.loadOrCreateTask(storeCtx, params.ID, msg.Session.GetID(), transport.GetUserId(msg.Session.GetParams()), params.Metadata)
 	if err != nil {
 		logger.Error("Failed to load/create task", zap.Error(err))
+		if errors.As(err, new(*a2aSchema.JSONRPCError)) {
+			return nil, err
+		}
 		return nil, &shared.JSONRPCError{Code: shared.JSONRPCErrorInternal, Message: "Failed to initialize task"}
 	}
+	ac.recordPhase(task, TimelinePhaseLoadOrCreate, loadStart, time.Now())
 
 	// --- Prevent Concurrent Execution ---
 	if ac.isHandlerRunning(task.ID) {
 		logger.Warn("Received tasks/send for already active task")
 		return nil, &shared.JSONRPCError{Code: shared.JSONRPCErrorInvalidRequest, Message: "Task is already processing"}
 	}
+	if params.PushNotification != nil {
+		ac.setPushConfig(task.ID, *params.PushNotification, pushOAuth)
+	}
 
 	// --- Handle Task Continuation/Restart ---
 	if task.Status.State == a2aSchema.TaskStateInputRequired && params.Message.Role == "user" {
@@ -123,30 +279,86 @@ func (ac *A2ACapability) handleTaskSend(msg *shared.Message) (interface{}, error
 		if task.History == nil {
 			task.History = []a2aSchema.Message{}
 		}
-		task.History = append(task.History, params.Message)
+		task.History = ac.pruneHistory(append(task.History, params.Message))
+	}
+
+	// --- Defer Scheduled Tasks ---
+	task.ScheduledAt = nil // A send without a future ScheduledAt starts the task now
+	if deferred {
+		task.ScheduledAt = params.ScheduledAt
 	}
 
 	// --- Save Task State Before Starting Handler ---
-	if err := ac.taskStore.Save(context.Background(), task); err != nil {
+	if err := ac.saveTask(storeCtx, task); err != nil {
 		logger.Error("Failed to save task state before handler start", zap.Error(err))
 		return nil, &shared.JSONRPCError{Code: shared.JSONRPCErrorInternal, Message: "Failed to save task state"}
 	}
 
-	// --- Prepare and Run Handler Synchronously ---
-	handlerCtx, cancel := context.WithCancel(context.Background())
-	ac.storeCancelFunc(task.ID, cancel) // Store cancel func for potential task cancellation
-	defer ac.removeCancelFunc(task.ID)  // Ensure cleanup when this function returns
+	lastTaskState := task
+	if task.ScheduledAt != nil {
+		// The scheduler started by WithScheduler runs the task once it is due
+		logger.Info("Deferred task until scheduled time", zap.Time("scheduledAt", *task.ScheduledAt))
+	} else {
+		// --- Run Handler Synchronously ---
+		var finalJsonRpcError *shared.JSONRPCError
+		done := make(chan struct{})
+		slot.run(func() {
+			defer close(done)
+			lastTaskState, finalJsonRpcError = ac.runTask(ctx, task, logger)
+		})
+		<-done
+		if finalJsonRpcError != nil {
+			setSpanError(span, finalJsonRpcError)
+			// Return the specific error determined during processing
+			logger.Info("Returning error to client", zap.Int("code", finalJsonRpcError.Code), zap.String("message", finalJsonRpcError.Message))
+			return nil, finalJsonRpcError
+		}
+	}
 
-	updates := make(chan A2AYieldUpdate, 20) // Buffered channel for agent updates
-	handlerErrChan := make(chan error, 1)    // Channel for handler's final return error
-	handlerLogger := logger                  // Pass logger with task context
+	// --- Prepare Successful Response ---
+	finalResponseTask := *lastTaskState // Copy final state for response
+	// Handle history length trimming
+	if params.HistoryLength != nil && *params.HistoryLength >= 0 {
+		historyLen := *params.HistoryLength
+		if len(finalResponseTask.History) > historyLen {
+			finalResponseTask.History = finalResponseTask.History[len(finalResponseTask.History)-historyLen:]
+		}
+	} else {
+		finalResponseTask.History = nil // Omit history if not requested or negative length
+	}
+	finalResponseTask.Timeline = nil // Only tasks/get returns the timeline
+
+	logger.Debug("tasks/send completed successfully", zap.String("finalState", string(finalResponseTask.Status.State)))
+	return &finalResponseTask, nil // Return the final task object
+}
+
+// runTask runs the agent handler for task until it finishes or needs input, applying and
+// saving its updates. It returns the final task state, or the error to report to the
+// client. Handler failures are recorded in the returned task's status as well. Spans of
+// the run are children of the span in ctx.
+func (ac *A2ACapability) runTask(ctx context.Context, task *a2aSchema.Task, logger *zap.Logger) (*a2aSchema.Task, *shared.JSONRPCError) {
+	handlerCtx, cancel := context.WithCancel(ctx)
+	hub := ac.storeCancelFunc(task.ID, cancel) // Store cancel func for potential task cancellation
+	defer hub.close()                          // Resubscribed streams end with the stored final status
+	defer ac.removeCancelFunc(task.ID)         // Ensure cleanup when this function returns
+
+	updates, received := ac.newUpdateChannels() // Buffered channel for agent updates
+	handlerErrChan := make(chan error, 1)       // Channel for handler's final return error
+	handlerLogger := logger                     // Pass logger with task context
+	snapshotVersion := ac.snapshotTask(task.ID, logger)
 
 	// Run the agent handler in a separate goroutine
+	handlerStart := time.Now()
+	var handlerEnd time.Time // Set before updates is closed, so it can be read once draining finishes
 	go func(currentTaskState *a2aSchema.Task) {
 		defer close(handlerErrChan) // Signal completion by closing the channel
 		defer close(updates)        // Close updates channel when handler goroutine finishes
+		spanCtx, span := ac.tracer().Start(handlerCtx, SpanTaskHandler)
+		defer span.End()
 		// Pass the task *as saved just before the call*
-		handlerErr := ac.agentHandler(handlerCtx, currentTaskState, updates, handlerLogger)
+		handlerErr := ac.callAgentHandler(spanCtx, currentTaskState, updates, handlerLogger)
+		handlerEnd = time.Now()
+		setSpanError(span, handlerErr)
 		handlerErrChan <- handlerErr // Send final error (or nil) back
 	}(task) // Pass the current task state
 
@@ -158,7 +370,7 @@ func (ac *A2ACapability) handleTaskSend(msg *shared.Message) (interface{}, error
 	keepProcessing := true
 	for keepProcessing {
 		select {
-		case update, ok := <-updates:
+		case update, ok := <-received:
 			if !ok {
 				// Updates channel closed, handler finished or panicked
 				logger.Debug("Updates channel closed by handler.")
@@ -169,7 +381,9 @@ func (ac *A2ACapability) handleTaskSend(msg *shared.Message) (interface{}, error
 
 			// Apply the update yielded by the handler
 			var applyErr error
-			lastTaskState, applyErr = ac.applyUpdateToTask(lastTaskState, update)
+			updateStart := time.Now()
+			lastTaskState, applyErr = ac.applyUpdate(ctx, lastTaskState, update)
+			ac.recordPhase(lastTaskState, TimelinePhaseUpdate, updateStart, time.Now())
 			if applyErr != nil {
 				logger.Error("Internal error applying update from handler", zap.Error(applyErr), zap.Any("update", update))
 				lastTaskState.Status = createErrorStatus(applyErr, nil) // Mark task failed due to internal error
@@ -193,7 +407,7 @@ func (ac *A2ACapability) handleTaskSend(msg *shared.Message) (interface{}, error
 			// Save intermediate state only if it's significant (input-required or terminal)
 			currentState := lastTaskState.Status.State
 			if currentState == a2aSchema.TaskStateInputRequired || isTerminalState(currentState) {
-				if err := ac.taskStore.Save(context.Background(), lastTaskState); err != nil {
+				if err := ac.saveTask(context.WithoutCancel(ctx), lastTaskState); err != nil {
 					logger.Error("Failed to save intermediate task state", zap.Error(err), zap.String("state", string(currentState)))
 					// If save fails, consider it an internal error
 					handlerError = fmt.Errorf("failed to save state (%s): %w", currentState, err)
@@ -220,10 +434,12 @@ func (ac *A2ACapability) handleTaskSend(msg *shared.Message) (interface{}, error
 	// Drain any remaining updates that might have been sent just before finishing,
 	// *after* the main loop has exited.
 	logger.Debug("Draining remaining updates channel after main loop")
-	for update := range updates { // Read until the channel is closed and empty
+	for update := range received { // Read until the channel is closed and empty
 		logger.Debug("Draining remaining update after handler finished", zap.Any("update", update))
 		var applyErr error
-		lastTaskState, applyErr = ac.applyUpdateToTask(lastTaskState, update)
+		updateStart := time.Now()
+		lastTaskState, applyErr = ac.applyUpdate(ctx, lastTaskState, update)
+		ac.recordPhase(lastTaskState, TimelinePhaseUpdate, updateStart, time.Now())
 		if applyErr != nil {
 			// Handle potential error during draining, maybe log and mark task as failed
 			logger.Error("Error applying drained update", zap.Error(applyErr), zap.Any("update", update))
@@ -249,6 +465,16 @@ func (ac *A2ACapability) handleTaskSend(msg *shared.Message) (interface{}, error
 		}
 	}
 	logger.Debug("Finished draining updates channel")
+	if handlerError == nil {
+		// The loop may have ended on the closed updates channel before reading the handler's error
+		if errFromHandler, ok := <-handlerErrChan; ok {
+			handlerError = errFromHandler
+		}
+	}
+	if restored := ac.rollbackOnPanic(task.ID, snapshotVersion, handlerError, logger); restored != nil {
+		lastTaskState = restored // Marked failed below
+	}
+	ac.recordPhase(lastTaskState, TimelinePhaseHandler, handlerStart, handlerEnd)
 
 	// --- Final State Handling and Response Preparation ---
 	// Ensure task is in a final state if the handler finished without error or yielding input-required/terminal
@@ -284,33 +510,23 @@ func (ac *A2ACapability) handleTaskSend(msg *shared.Message) (interface{}, error
 	}
 
 	// --- Save the final determined state ---
-	if err := ac.taskStore.Save(context.Background(), lastTaskState); err != nil {
+	saveStart := time.Now()
+	if err := ac.saveTask(context.WithoutCancel(ctx), lastTaskState); err != nil {
 		logger.Error("Failed to save final task state", zap.Error(err))
 		// If final save fails, return internal error to client
 		return nil, &shared.JSONRPCError{Code: shared.JSONRPCErrorInternal, Message: "Failed to save final task state"}
 	}
-
-	// --- Return Result or Error to Client ---
-	if finalJsonRpcError != nil {
-		// Return the specific error determined during processing
-		logger.Info("Returning error to client", zap.Int("code", finalJsonRpcError.Code), zap.String("message", finalJsonRpcError.Message))
-		return nil, finalJsonRpcError
-	}
-
-	// --- Prepare Successful Response ---
-	finalResponseTask := *lastTaskState // Copy final state for response
-	// Handle history length trimming
-	if params.HistoryLength != nil && *params.HistoryLength >= 0 {
-		historyLen := *params.HistoryLength
-		if len(finalResponseTask.History) > historyLen {
-			finalResponseTask.History = finalResponseTask.History[len(finalResponseTask.History)-historyLen:]
+	if ac.timelineRecording {
+		// The save cannot include its own duration, so store the timeline once more
+		ac.recordPhase(lastTaskState, TimelinePhaseSave, saveStart, time.Now())
+		if err := ac.saveTask(context.WithoutCancel(ctx), lastTaskState); err != nil {
+			logger.Warn("Failed to save task timeline", zap.Error(err))
 		}
-	} else {
-		finalResponseTask.History = nil // Omit history if not requested or negative length
 	}
-
-	logger.Debug("tasks/send completed successfully", zap.String("finalState", string(finalResponseTask.Status.State)))
-	return &finalResponseTask, nil // Return the final task object
+	ac.autoExport(lastTaskState)
+	ac.notifyWebhook(lastTaskState)
+	ac.notifyPush(lastTaskState)
+	return lastTaskState, finalJsonRpcError
 }
 
 // handleTaskSendSubscribe handles asynchronous task requests with SSE streaming (`tasks/sendSubscribe`).
@@ -324,19 +540,84 @@ func (ac *A2ACapability) handleTaskSendSubscribe(msg *shared.Message) (interface
 	}
 	logger = logger.With(zap.String("taskID", params.ID))
 	logger.Debug("Handling tasks/sendSubscribe request")
+	if err := ac.requireStreaming(msg, "tasks/sendSubscribe", logger); err != nil {
+		return nil, err
+	}
+	// Continues the request's trace; the task outlives the request, so nothing derived from it is cancelled
+	requestCtx := context.WithoutCancel(msg.Context())
+	if err := ac.prepareUserMessage(msg.Context(), &params.Message); err != nil {
+		logger.Warn("Rejected tasks/sendSubscribe message", zap.Error(err))
+		return nil, err
+	}
+	var pushOAuth *PushNotificationOAuth2Credentials // Registered once the task may be used
+	if params.PushNotification != nil {
+		var configErr *shared.JSONRPCError
+		if pushOAuth, configErr = parsePushConfig(*params.PushNotification); configErr != nil {
+			logger.Warn("Rejected tasks/sendSubscribe push notification config", zap.String("reason", configErr.Message))
+			return nil, configErr
+		}
+	}
+	// --- Limit Concurrent Tasks of the User ---
+	userID := transport.GetUserId(msg.Session.GetParams())
+	var turn <-chan struct{} // Closed when the task may start; nil without a limit
+	queued := false
+	if ac.userTaskSlots != nil {
+		var limitErr *a2aSchema.JSONRPCError
+		turn, limitErr = ac.userTaskSlots.acquire(userID)
+		if limitErr != nil {
+			logger.Warn("User runs too many tasks for tasks/sendSubscribe")
+			return nil, limitErr
+		}
+		select {
+		case <-turn:
+		default:
+			queued = true
+		}
+	}
+	handedOver := false // Set once the task started or was queued
+	defer func() {
+		if turn != nil && !handedOver {
+			go func() {
+				<-turn
+				ac.userTaskSlots.release(userID)
+			}()
+		}
+	}()
+	var slot *workerSlot
+	if !queued { // Queued tasks reserve their worker when they start
+		var busyErr *a2aSchema.JSONRPCError
+		slot, busyErr = ac.reserveWorker()
+		if busyErr != nil {
+			logger.Warn("No free worker for tasks/sendSubscribe")
+			return nil, busyErr
+		}
+		defer slot.release() // No-op once the handler started on it
+	}
 
 	// --- Load or Create Task ---
-	task, err := ac.loadOrCreateTask(context.Background(), params.ID, msg.Session.GetID(), params.Metadata)
+	loadStart := time.Now()
+	task, err := ac.loadOrCreateTask(requestCtx, params.ID, msg.Session.GetID(), userID, params.Metadata)
 	if err != nil {
 		logger.Error("Failed to load/create task", zap.Error(err))
+		if errors.As(err, new(*a2aSchema.JSONRPCError)) {
+			return nil, err
+		}
 		return nil, &shared.JSONRPCError{Code: shared.JSONRPCErrorInternal, Message: "Failed to initialize task"}
 	}
+	ac.recordPhase(task, TimelinePhaseLoadOrCreate, loadStart, time.Now())
 
 	// --- Prevent Concurrent Execution ---
 	if ac.isHandlerRunning(task.ID) {
 		logger.Warn("Received tasks/sendSubscribe for already active task")
 		return nil, &shared.JSONRPCError{Code: shared.JSONRPCErrorInvalidRequest, Message: "Task is already processing, use tasks/resubscribe"}
 	}
+	if task.Status.State == a2aSchema.TaskStateQueued {
+		logger.Warn("Received tasks/sendSubscribe for queued task")
+		return nil, &shared.JSONRPCError{Code: shared.JSONRPCErrorInvalidRequest, Message: "Task is queued, use tasks/resubscribe"}
+	}
+	if params.PushNotification != nil {
+		ac.setPushConfig(task.ID, *params.PushNotification, pushOAuth)
+	}
 
 	// --- Handle Task Continuation/Restart ---
 	if task.Status.State == a2aSchema.TaskStateInputRequired && params.Message.Role == "user" {
@@ -353,31 +634,123 @@ func (ac *A2ACapability) handleTaskSendSubscribe(msg *shared.Message) (interface
 		if task.History == nil {
 			task.History = []a2aSchema.Message{}
 		}
-		task.History = append(task.History, params.Message)
+		task.History = ac.pruneHistory(append(task.History, params.Message))
+	}
+
+	if queued {
+		task.Status = a2aSchema.TaskStatus{State: a2aSchema.TaskStateQueued, Timestamp: time.Now()}
 	}
 
 	// --- Save Task State Before Starting Handler ---
-	if err := ac.taskStore.Save(context.Background(), task); err != nil {
+	if err := ac.saveTask(requestCtx, task); err != nil {
 		logger.Error("Failed to save task state before handler start (sendSubscribe)", zap.Error(err))
 		return nil, &shared.JSONRPCError{Code: shared.JSONRPCErrorInternal, Message: "Failed to save task state"}
 	}
 
+	if queued {
+		logger.Info("Session runs its maximum of tasks, queuing the task")
+		if err := msg.Session.SendA2AStreamEvent(queuedStatusEvent(task)); err != nil {
+			logger.Warn("Failed to send queued status event", zap.Error(err))
+		}
+		go ac.runQueuedTask(requestCtx, msg, task.ID, turn, logger)
+	} else {
+		ac.startStreamingTask(requestCtx, msg, task, slot, logger)
+	}
+	handedOver = true
+
+	// For tasks/sendSubscribe, the initial JSON-RPC response acknowledges the request initiation.
+	// Return the initial task state (without artifacts, potentially trimmed history).
+	initialResponseTask := *task // Copy initial task state
+	if params.HistoryLength != nil && *params.HistoryLength >= 0 {
+		historyLen := *params.HistoryLength
+		if len(initialResponseTask.History) > historyLen {
+			initialResponseTask.History = initialResponseTask.History[len(initialResponseTask.History)-historyLen:]
+		}
+	} else {
+		initialResponseTask.History = nil // No history requested
+	}
+	initialResponseTask.Artifacts = nil // Artifacts are sent via SSE events
+	initialResponseTask.Timeline = nil
+
+	logger.Debug("tasks/sendSubscribe initiated, returning initial task state", zap.String("initialState", string(initialResponseTask.Status.State)))
+	return &initialResponseTask, nil
+}
+
+// startStreamingTask starts the agent handler of a tasks/sendSubscribe task on the reserved
+// worker slot, and the goroutine streaming its updates to the session of msg.
+func (ac *A2ACapability) startStreamingTask(requestCtx context.Context, msg *shared.Message, task *a2aSchema.Task, slot *workerSlot, logger *zap.Logger) {
 	// --- Prepare and Start Handler Asynchronously ---
-	handlerCtx, cancel := context.WithCancel(context.Background())
-	ac.storeCancelFunc(task.ID, cancel)      // Store cancel func
-	updates := make(chan A2AYieldUpdate, 20) // Buffered channel for agent updates
-	handlerLogger := logger                  // Pass logger with task context
+	// The task outlives this request, so its span ends when the handler goroutine finishes
+	taskCtx, taskSpan := ac.startTaskSpan(requestCtx, task.ID, msg.Session.GetID())
+	handlerCtx, cancel := context.WithCancel(taskCtx)
+	hub := ac.storeCancelFunc(task.ID, cancel)  // Store cancel func; resubscribed streams follow the hub
+	updates, received := ac.newUpdateChannels() // Buffered channel for agent updates
+	handlerLogger := logger                     // Pass logger with task context
+	snapshotVersion := ac.snapshotTask(task.ID, logger)
 
 	var wait4TaskUpdates sync.WaitGroup
 	wait4TaskUpdates.Add(1)
 
-	// Goroutine to run the agent's logic
-	go func(initialTaskState *a2aSchema.Task) {
+	// Both goroutines below send events, but the handler goroutine only does so after
+	// the update goroutine has finished, so they can share one encoder.
+	var deltaEncoder *a2aSchema.StatusDeltaEncoder
+	if ac.statusDeltaEncoding {
+		deltaEncoder = &a2aSchema.StatusDeltaEncoder{}
+	}
+	replay := ac.newReplayBuffer(task.ID)
+	sendEvent := func(event *shared.A2AStreamEvent) error {
+		ac.logStreamEvent(event)
+		if replay != nil {
+			replay.Add(event) // Before delta encoding, as resumed streams start without the previous status
+		}
+		hub.publish(event)
+		if deltaEncoder != nil && event.Status != nil {
+			encoded := deltaEncoder.Encode(*event.Status)
+			event.Status = &encoded
+		}
+		return msg.Session.SendA2AStreamEvent(event)
+	}
+
+	var startup *handlerStartup
+	if ac.handlerStartupTimeout > 0 {
+		startup = newHandlerStartup()
+		go ac.watchHandlerStartup(handlerCtx, startup, task.ID, sendEvent, logger)
+	}
+
+	// Goroutine to run the agent's logic, on the reserved worker
+	initialTaskState := task
+	slot.run(func() {
+		if ac.userTaskSlots != nil {
+			defer ac.userTaskSlots.release(transport.GetUserId(msg.Session.GetParams())) // Last, after the task is saved
+		}
+		defer taskSpan.End()
+		if replay != nil {
+			defer replay.Close() // After the final events below
+		}
+		defer hub.close()
 		defer ac.removeCancelFunc(task.ID) // Remove cancel func ref when handler exits
-		defer close(updates)               // Close updates chan when handler exits
 
-		handlerErr := ac.agentHandler(handlerCtx, initialTaskState, updates, handlerLogger)
+		handlerStart := time.Now()
+		spanCtx, span := ac.tracer().Start(handlerCtx, SpanTaskHandler)
+		handlerErr := ac.callAgentHandler(spanCtx, initialTaskState, updates, handlerLogger)
+		handlerEnd := time.Now()
+		setSpanError(span, handlerErr)
+		span.End()
+		close(updates) // Lets the update goroutine drain and finish before we wait for it
 		wait4TaskUpdates.Wait()
+		if startup != nil && !startup.confirm() {
+			logger.Debug("Agent handler exited after its startup timeout")
+			return
+		}
+		ac.rollbackOnPanic(task.ID, snapshotVersion, handlerErr, logger) // The failed status is saved below
+		if ac.timelineRecording {
+			if current, loadErr := ac.loadTask(taskCtx, task.ID); loadErr == nil {
+				ac.recordPhase(current, TimelinePhaseHandler, handlerStart, handlerEnd)
+				if saveErr := ac.saveTask(taskCtx, current); saveErr != nil {
+					logger.Warn("Failed to save task timeline", zap.Error(saveErr))
+				}
+			}
+		}
 
 		// --- Handle Handler Completion/Error (after update processing finishes) ---
 		if handlerErr != nil && !errors.Is(handlerErr, context.Canceled) {
@@ -387,7 +760,7 @@ func (ac *A2ACapability) handleTaskSendSubscribe(msg *shared.Message) (interface
 			var finalEvent *shared.A2AStreamEvent
 			if jsonRPCErr, ok := handlerErr.(*a2aSchema.JSONRPCError); ok {
 				finalStatus = createErrorStatus(jsonRPCErr, jsonRPCErr)
-				finalEvent = &shared.A2AStreamEvent{Type: "error", Error: jsonRPCErr, Final: true}
+				finalEvent = streamErrorEvent(jsonRPCErr, true)
 			} else {
 				finalStatus = createErrorStatus(handlerErr, nil) // Generic internal error
 				finalEvent = &shared.A2AStreamEvent{
@@ -397,16 +770,16 @@ func (ac *A2ACapability) handleTaskSendSubscribe(msg *shared.Message) (interface
 				}
 			}
 			// Send final event via session
-			if sendErr := msg.Session.SendA2AStreamEvent(finalEvent); sendErr != nil {
+			if sendErr := sendEvent(finalEvent); sendErr != nil {
 				logger.Error("Failed to send final failed status/error event", zap.Error(sendErr))
 			}
 			// Save final failed state
-			finalTaskState, loadErr := ac.taskStore.Load(context.Background(), task.ID)
+			finalTaskState, loadErr := ac.loadTask(taskCtx, task.ID)
 			if loadErr != nil {
 				finalTaskState = task // Fallback
 			}
 			finalTaskState.Status = finalStatus
-			if saveErr := ac.taskStore.Save(context.Background(), finalTaskState); saveErr != nil {
+			if saveErr := ac.saveTask(taskCtx, finalTaskState); saveErr != nil {
 				logger.Error("Failed to save final failed task state", zap.Error(saveErr))
 			}
 		} else if errors.Is(handlerErr, context.Canceled) {
@@ -415,7 +788,7 @@ func (ac *A2ACapability) handleTaskSendSubscribe(msg *shared.Message) (interface
 		} else {
 			logger.Debug("Agent handler finished processing stream normally")
 			// Ensure task completion if not already terminal/input-required
-			finalTaskState, loadErr := ac.taskStore.Load(context.Background(), task.ID)
+			finalTaskState, loadErr := ac.loadTask(taskCtx, task.ID)
 			if loadErr != nil {
 				logger.Error("Failed to load task state after handler completion", zap.Error(loadErr))
 			} else if !isTerminalState(finalTaskState.Status.State) && finalTaskState.Status.State != a2aSchema.TaskStateInputRequired {
@@ -430,16 +803,16 @@ func (ac *A2ACapability) handleTaskSendSubscribe(msg *shared.Message) (interface
 					Status: &a2aSchema.TaskStatusUpdateEvent{ID: task.ID, Status: finalStatus, Final: true},
 					Final:  true,
 				}
-				if sendErr := msg.Session.SendA2AStreamEvent(finalEvent); sendErr != nil {
+				if sendErr := sendEvent(finalEvent); sendErr != nil {
 					logger.Error("Failed to send final completed status event", zap.Error(sendErr))
 				}
 				finalTaskState.Status = finalStatus
-				if saveErr := ac.taskStore.Save(context.Background(), finalTaskState); saveErr != nil {
+				if saveErr := ac.saveTask(taskCtx, finalTaskState); saveErr != nil {
 					logger.Error("Failed to save final completed task state", zap.Error(saveErr))
 				}
 			}
 		}
-	}(task) // Pass the task state
+	})
 
 	// Goroutine to process updates from the handler and send SSE events via the session
 	go func(currentTaskState *a2aSchema.Task) {
@@ -448,46 +821,70 @@ func (ac *A2ACapability) handleTaskSendSubscribe(msg *shared.Message) (interface
 		lastTaskState := currentTaskState // Track state locally for saving
 		isFinalEventSent := false
 
-		for update := range updates { // Read from updates channel until closed
+		for update := range received { // Read from updates channel until closed
+			if startup != nil && !startup.confirm() {
+				continue // The startup timeout already failed the task, drop late updates
+			}
 			// Apply update to local task state copy
 			var applyErr error
-			lastTaskState, applyErr = ac.applyUpdateToTask(lastTaskState, update)
+			updateStart := time.Now()
+			lastTaskState, applyErr = ac.applyUpdate(taskCtx, lastTaskState, update)
+			ac.recordPhase(lastTaskState, TimelinePhaseUpdate, updateStart, time.Now())
 			if applyErr != nil {
 				logger.Error("Failed to apply update to task during streaming", zap.Error(applyErr), zap.Any("update", update))
-				errorEvent := &shared.A2AStreamEvent{Type: "error", Error: &a2aSchema.JSONRPCError{Code: a2aSchema.ErrorInternalError, Message: fmt.Sprintf("Internal error applying update: %v", applyErr)}, Final: false}
-				_ = msg.Session.SendA2AStreamEvent(errorEvent) // Try to send error event
-				continue                                       // Skip saving/sending this broken update
+				errorEvent := streamErrorEvent(&a2aSchema.JSONRPCError{Code: a2aSchema.ErrorInternalError, Message: fmt.Sprintf("Internal error applying update: %v", applyErr)}, false)
+				_ = sendEvent(errorEvent) // Try to send error event
+				continue                  // Skip saving/sending this broken update
 			}
 
 			// Handle yielded JSONRPCError
 			if update.Error != nil {
 				logger.Error("Handler yielded JSONRPCError during stream", zap.Any("error", update.Error))
-				errorEvent := &shared.A2AStreamEvent{Type: "error", Error: update.Error, Final: true}
-				_ = msg.Session.SendA2AStreamEvent(errorEvent)
-				lastTaskState.Status = createErrorStatus(update.Error, update.Error)           // Update local state copy
-				if err := ac.taskStore.Save(context.Background(), lastTaskState); err != nil { // Save failed state
+				errorEvent := streamErrorEvent(update.Error, true)
+				_ = sendEvent(errorEvent)
+				lastTaskState.Status = createErrorStatus(update.Error, update.Error) // Update local state copy
+				if err := ac.saveTask(taskCtx, lastTaskState); err != nil {          // Save failed state
 					logger.Error("Failed to save task state after yielded error", zap.Error(err))
+				} else {
+					ac.notifyWebhook(lastTaskState)
+					ac.notifyPush(lastTaskState)
 				}
 				ac.cancelHandler(task.ID) // Cancel original context
 				return                    // Stop processing updates
 			}
 
 			// Save the updated task state
-			if err := ac.taskStore.Save(context.Background(), lastTaskState); err != nil {
+			if err := ac.saveTask(taskCtx, lastTaskState); err != nil {
 				logger.Error("Failed to save task state during streaming", zap.Error(err))
 				// Consider if failure to save should stop the stream? Potentially yes.
 				// Let's send an error event and stop.
-				errorEvent := &shared.A2AStreamEvent{Type: "error", Error: &a2aSchema.JSONRPCError{Code: a2aSchema.ErrorInternalError, Message: fmt.Sprintf("Internal error saving state: %v", err)}, Final: true}
-				_ = msg.Session.SendA2AStreamEvent(errorEvent)
+				errorEvent := streamErrorEvent(&a2aSchema.JSONRPCError{Code: a2aSchema.ErrorInternalError, Message: fmt.Sprintf("Internal error saving state: %v", err)}, true)
+				_ = sendEvent(errorEvent)
 				ac.cancelHandler(task.ID)
 				return
 			}
+			ac.autoExport(lastTaskState)
+			ac.notifyWebhook(lastTaskState)
+			ac.notifyPush(lastTaskState)
 
 			// Prepare A2AStreamEvent to send to client
 			var eventToSend *shared.A2AStreamEvent
 			isFinal := isTerminalState(lastTaskState.Status.State) || lastTaskState.Status.State == a2aSchema.TaskStateInputRequired
 
 			if update.Status != nil {
+				// Agent messages were appended to history by applyUpdateToTask; stream them before the status,
+				// which may be final and end the stream.
+				if update.Status.Message != nil && update.Status.Message.Role == "agent" {
+					historyEvent := &shared.A2AStreamEvent{
+						Type:    shared.A2ASSEEventHistory,
+						History: &a2aSchema.TaskHistoryUpdateEvent{ID: task.ID, Message: lastTaskState.History[len(lastTaskState.History)-1]},
+					}
+					if err := sendEvent(historyEvent); err != nil {
+						logger.Error("Failed to send A2A history event, cancelling handler", zap.Error(err))
+						ac.cancelHandler(task.ID)
+						return
+					}
+				}
 				eventToSend = &shared.A2AStreamEvent{
 					Type: "status",
 					Status: &a2aSchema.TaskStatusUpdateEvent{
@@ -510,7 +907,7 @@ func (ac *A2ACapability) handleTaskSendSubscribe(msg *shared.Message) (interface
 
 			// Send event via session's output channel
 			if eventToSend != nil {
-				if err := msg.Session.SendA2AStreamEvent(eventToSend); err != nil {
+				if err := sendEvent(eventToSend); err != nil {
 					logger.Error("Failed to send A2A stream event, cancelling handler", zap.Error(err))
 					ac.cancelHandler(task.ID) // Cancel the agent handler
 					return                    // Stop processing updates
@@ -528,22 +925,6 @@ func (ac *A2ACapability) handleTaskSendSubscribe(msg *shared.Message) (interface
 			logger.Debug("No final event was sent explicitly during update processing (handler might send one)")
 		}
 	}(task) // End update processing goroutine
-
-	// For tasks/sendSubscribe, the initial JSON-RPC response acknowledges the request initiation.
-	// Return the initial task state (without artifacts, potentially trimmed history).
-	initialResponseTask := *task // Copy initial task state
-	if params.HistoryLength != nil && *params.HistoryLength >= 0 {
-		historyLen := *params.HistoryLength
-		if len(initialResponseTask.History) > historyLen {
-			initialResponseTask.History = initialResponseTask.History[len(initialResponseTask.History)-historyLen:]
-		}
-	} else {
-		initialResponseTask.History = nil // No history requested
-	}
-	initialResponseTask.Artifacts = nil // Artifacts are sent via SSE events
-
-	logger.Debug("tasks/sendSubscribe initiated, returning initial task state", zap.String("initialState", string(initialResponseTask.Status.State)))
-	return &initialResponseTask, nil
 }
 
 // handleTaskGet handles `tasks/get` requests.
@@ -557,8 +938,9 @@ func (ac *A2ACapability) handleTaskGet(msg *shared.Message) (interface{}, error)
 	}
 	logger = logger.With(zap.String("taskID", params.ID))
 	logger.Debug("Handling tasks/get request")
+	requestCtx := context.WithoutCancel(msg.Context())
 
-	task, err := ac.taskStore.Load(context.Background(), params.ID)
+	task, err := ac.loadTask(requestCtx, params.ID)
 	if err != nil {
 		logger.Warn("Failed to load task for get", zap.Error(err))
 		var jsonRPCErr *a2aSchema.JSONRPCError
@@ -578,6 +960,9 @@ func (ac *A2ACapability) handleTaskGet(msg *shared.Message) (interface{}, error)
 	} else {
 		responseTask.History = nil // Omit history if not requested or negative length
 	}
+	if !params.IncludeTimeline {
+		responseTask.Timeline = nil
+	}
 
 	logger.Debug("Returning task state", zap.String("state", string(responseTask.Status.State)))
 	return &responseTask, nil
@@ -594,8 +979,9 @@ func (ac *A2ACapability) handleTaskCancel(msg *shared.Message) (interface{}, err
 	}
 	logger = logger.With(zap.String("taskID", params.ID))
 	logger.Debug("Handling tasks/cancel request")
+	requestCtx := context.WithoutCancel(msg.Context())
 
-	task, err := ac.taskStore.Load(context.Background(), params.ID)
+	task, err := ac.loadTask(requestCtx, params.ID)
 	if err != nil {
 		logger.Warn("Failed to load task for cancellation", zap.Error(err))
 		var jsonRPCErr *a2aSchema.JSONRPCError
@@ -608,7 +994,7 @@ func (ac *A2ACapability) handleTaskCancel(msg *shared.Message) (interface{}, err
 	// --- Check if Cancellable ---
 	if isTerminalState(task.Status.State) {
 		logger.Warn("Task already in terminal state, cannot cancel", zap.String("state", string(task.Status.State)))
-		return nil, shared.NewJSONRPCError(a2aSchema.NewTaskNotCancelableError(params.ID))
+		return nil, shared.NewJSONRPCError(a2aSchema.NewTaskNotCancelableError(params.ID, task.Status.State))
 	}
 
 	// --- Cancel Running Handler ---
@@ -624,10 +1010,12 @@ func (ac *A2ACapability) handleTaskCancel(msg *shared.Message) (interface{}, err
 	task.Status.Message = &a2aSchema.Message{Role: "agent", Parts: []a2aSchema.Part{{Type: shared.PointerTo("text"), Text: &cancelMsgText}}}
 
 	// --- Save Final Canceled State ---
-	if err := ac.taskStore.Save(context.Background(), task); err != nil {
+	if err := ac.saveTask(requestCtx, task); err != nil {
 		logger.Error("Failed to save canceled task state", zap.Error(err))
 		return nil, &shared.JSONRPCError{Code: shared.JSONRPCErrorInternal, Message: "Failed to save canceled task state"}
 	}
+	ac.notifyWebhook(task)
+	ac.notifyPush(task)
 
 	// The transport layer's SSE handler (`streamA2AResponse`) associated with the *original*
 	// `sendSubscribe` request should detect the context cancellation triggered by `cancelHandler`
@@ -638,36 +1026,30 @@ func (ac *A2ACapability) handleTaskCancel(msg *shared.Message) (interface{}, err
 	responseTask := *task
 	responseTask.History = nil
 	responseTask.Artifacts = nil // Don't return artifacts for cancel response
+	responseTask.Timeline = nil
 	return &responseTask, nil
 }
 
-// handleTaskPushNotificationSet returns "Unsupported Operation".
-func (ac *A2ACapability) handleTaskPushNotificationSet(msg *shared.Message) (interface{}, error) {
-	return nil, shared.NewJSONRPCError(a2aSchema.NewUnsupportedOperationError("tasks/pushNotification/set"))
-}
-
-// handleTaskPushNotificationGet returns "Unsupported Operation".
-func (ac *A2ACapability) handleTaskPushNotificationGet(msg *shared.Message) (interface{}, error) {
-	return nil, shared.NewJSONRPCError(a2aSchema.NewUnsupportedOperationError("tasks/pushNotification/get"))
-}
-
 // handleTaskResubscribe handles `tasks/resubscribe` requests.
-// Note: Full resumption of live updates from an *existing* handler run is complex.
-// This implementation provides a snapshot and starts a *new* stream that won't get
-// updates from the original handler instance.
+// Without lastEventId it returns a snapshot of the task. With lastEventId, the stream also
+// gets the events after it: buffered events of the latest run (see WithReplayBufferSize)
+// followed by its live events, or a final status event if the task already finished.
 func (ac *A2ACapability) handleTaskResubscribe(msg *shared.Message) (interface{}, error) {
 	logger := ac.logger.With(zap.String("sessionID", msg.Session.GetID()), zap.String("method", "tasks/resubscribe"))
 
-	var params a2aSchema.TaskQueryParams // Resubscribe uses TaskQueryParams according to spec example
+	var params a2aSchema.TaskResubscribeParams // TaskQueryParams according to spec example, plus lastEventId
 	if err := json.Unmarshal(*msg.Params, &params); err != nil {
 		logger.Error("Failed to unmarshal tasks/resubscribe params", zap.Error(err))
 		return nil, &shared.JSONRPCError{Code: shared.JSONRPCErrorInvalidParams, Message: err.Error()}
 	}
 	logger = logger.With(zap.String("taskID", params.ID))
 	logger.Debug("Handling tasks/resubscribe request")
+	if err := ac.requireStreaming(msg, "tasks/resubscribe", logger); err != nil {
+		return nil, err
+	}
 
 	// --- Load Task State ---
-	task, err := ac.taskStore.Load(context.Background(), params.ID)
+	task, err := ac.loadTask(context.Background(), params.ID)
 	if err != nil {
 		logger.Warn("Failed to load task for resubscribe", zap.Error(err))
 		var jsonRPCErr *a2aSchema.JSONRPCError
@@ -690,42 +1072,80 @@ func (ac *A2ACapability) handleTaskResubscribe(msg *shared.Message) (interface{}
 	// Don't include artifacts in the initial resubscribe response.
 	// The client should use tasks/get if they need the full current artifact state.
 	responseTask.Artifacts = nil
+	if !params.IncludeTimeline {
+		responseTask.Timeline = nil
+	}
+
+	// --- Replay Missed Events ---
+	buffer := ac.replayBuffer(task.ID)
+	lastEventID, finalSent := uint64(0), false
+	if params.LastEventID != nil && buffer != nil {
+		lastEventID, finalSent, err = replayEvents(buffer, msg.Session, *params.LastEventID, logger)
+		if err != nil {
+			logger.Warn("Failed to replay stream events", zap.Error(err))
+			return nil, &shared.JSONRPCError{Code: shared.JSONRPCErrorInternal, Message: "Failed to replay stream events"}
+		}
+	}
 
 	// --- Check Task Status for Response/Stream Behavior ---
 	if isTerminalState(task.Status.State) {
 		logger.Info("Task already terminated, returning final state for resubscribe", zap.String("state", string(task.Status.State)))
+		if !finalSent {
+			if err := msg.Session.SendA2AStreamEvent(finalStatusEvent(task)); err != nil {
+				logger.Warn("Failed to send final status event", zap.Error(err))
+			}
+		}
 		// The transport layer should see the terminal state and send *only* the final status event.
 		// We return the task object containing the terminal status.
 		return &responseTask, nil
 	}
 
-	if !ac.isHandlerRunning(task.ID) {
+	events, unsubscribe, running := ac.subscribeHandler(task.ID)
+	if !running {
+		if current, loadErr := ac.loadTask(context.Background(), task.ID); loadErr == nil && isTerminalState(current.Status.State) {
+			// The run ended after the task was loaded above
+			if !finalSent {
+				if err := msg.Session.SendA2AStreamEvent(finalStatusEvent(current)); err != nil {
+					logger.Warn("Failed to send final status event", zap.Error(err))
+				}
+			}
+			responseTask.Status = current.Status
+			return &responseTask, nil
+		}
 		// Task is not terminal, but handler isn't running (inconsistent state).
 		logger.Error("Resubscribe requested for non-terminal task with no running handler", zap.String("state", string(task.Status.State)))
 		// Mark task as failed and return error
 		task.Status = createErrorStatus(errors.New("inconsistent state: task not terminal but handler not running"), nil)
-		_ = ac.taskStore.Save(context.Background(), task) // Attempt to save error state
+		_ = ac.saveTask(context.Background(), task) // Attempt to save error state
 		return nil, &shared.JSONRPCError{Code: shared.JSONRPCErrorInternal, Message: "Task in inconsistent state, cannot resubscribe"}
 	}
 
 	// --- Task is Running - Provide Current State ---
 	// The transport layer will start an SSE stream upon seeing the Accept header.
 	// This response provides the initial state snapshot for the resubscribing client.
-	// WARNING: Updates from the original agent handler run will NOT be sent to this new stream.
-	logger.Warn("Resuming stream not fully implemented. Returning current state. New updates from the original handler run won't be sent to this new stream.")
+	if params.LastEventID != nil && buffer != nil {
+		unsubscribe() // The replay buffer carries the live events too
+		if !finalSent {
+			go followEvents(buffer, msg.Session, lastEventID, logger)
+		}
+		return &responseTask, nil
+	}
+	// Without a last event ID the stream continues with the run's next events
+	go ac.forwardHubEvents(task.ID, events, unsubscribe, msg.Session, logger)
 	return &responseTask, nil
-
-	// Proper resumption would require complex state management (sharing update channels, fan-out).
-	// return nil, shared.NewJSONRPCError(a2aSchema.NewUnsupportedOperationError("tasks/resubscribe (full streaming resumption not implemented)"))
 }
 
 // --- Helper Methods ---
 
 // loadOrCreateTask retrieves a task or creates a new one if not found.
-func (ac *A2ACapability) loadOrCreateTask(ctx context.Context, taskID string, sessionID string, metadata *map[string]interface{}) (*a2aSchema.Task, error) {
-	task, err := ac.taskStore.Load(ctx, taskID)
+func (ac *A2ACapability) loadOrCreateTask(ctx context.Context, taskID string, sessionID string, userID string, metadata *map[string]interface{}) (*a2aSchema.Task, error) {
+	task, err := ac.loadTask(ctx, taskID)
 	if err == nil { // Task found
 		ac.logger.Debug("Loaded existing task", zap.String("taskID", taskID), zap.String("state", string(task.Status.State)))
+		if task.UserID != userID {
+			ac.logger.Warn("Task ID used by another user", zap.String("taskID", taskID), zap.String("userID", userID))
+			return nil, &a2aSchema.JSONRPCError{Code: a2aSchema.ErrorInvalidParams, Message: fmt.Sprintf("Task ID '%s' is already in use", taskID)}
+		}
 		// Update metadata if provided in the current request? Let's merge/overwrite.
 		if metadata != nil {
 			task.Metadata = metadata // Replace metadata
@@ -738,19 +1158,26 @@ func (ac *A2ACapability) loadOrCreateTask(ctx context.Context, taskID string, se
 	var jsonRPCErr *a2aSchema.JSONRPCError
 	if errors.As(err, &jsonRPCErr) && jsonRPCErr.Code == a2aSchema.ErrorCodeTaskNotFound {
 		// Task not found, proceed to create a new one
+		if ac.taskRateLimiter != nil && !ac.taskRateLimiter.allow(userID) {
+			ac.logger.Warn("Task creation rate limit exceeded", zap.String("taskID", taskID), zap.String("userID", userID))
+			return nil, &a2aSchema.JSONRPCError{Code: a2aSchema.ErrorCodeRateLimitExceeded, Message: "Task creation rate limit exceeded"}
+		}
 		ac.logger.Info("Task not found, creating new task", zap.String("taskID", taskID))
+		now := time.Now()
 		newTask := &a2aSchema.Task{
 			ID:        taskID,
 			SessionID: sessionID,
+			UserID:    userID,
+			CreatedAt: now,
 			Status: a2aSchema.TaskStatus{
 				State:     a2aSchema.TaskStateSubmitted,
-				Timestamp: time.Now(),
+				Timestamp: now,
 			},
 			Artifacts: []a2aSchema.Artifact{}, // Initialize slices
 			History:   []a2aSchema.Message{},
 			Metadata:  metadata, // Set initial metadata
 		}
-		if err := ac.taskStore.Save(ctx, newTask); err != nil {
+		if err := ac.saveTask(ctx, newTask); err != nil {
 			ac.logger.Error("Failed to save newly created task", zap.String("taskID", taskID), zap.Error(err))
 			return nil, fmt.Errorf("failed to save newly created task: %w", err)
 		}
@@ -762,12 +1189,33 @@ func (ac *A2ACapability) loadOrCreateTask(ctx context.Context, taskID string, se
 	return nil, fmt.Errorf("internal error loading task: %w", err) // Return internal error
 }
 
+// loadOwnedTask loads a task created by userID. Tasks of other users are reported as not found,
+// so their IDs are not revealed.
+func (ac *A2ACapability) loadOwnedTask(ctx context.Context, taskID string, userID string) (*a2aSchema.Task, error) {
+	task, err := ac.loadTask(ctx, taskID)
+	if err != nil {
+		return nil, err
+	}
+	if task.UserID != userID {
+		return nil, a2aSchema.NewTaskNotFoundError(taskID)
+	}
+	return task, nil
+}
+
 // applyUpdateToTask modifies the task based on the yielded update from the handler.
-// It returns a *new* task instance with the update applied.
+// It returns a *new* task instance with the update applied, or the unchanged task with an error.
 func (ac *A2ACapability) applyUpdateToTask(task *a2aSchema.Task, update A2AYieldUpdate) (*a2aSchema.Task, error) {
 	if task == nil {
 		return nil, errors.New("cannot apply update to nil task")
 	}
+	if update.Artifact != nil {
+		if err := validateArtifact(*update.Artifact); err != nil {
+			if ac.strictArtifactValidation {
+				return task, &a2aSchema.JSONRPCError{Code: a2aSchema.ErrorInternalError, Message: err.Error()}
+			}
+			ac.logger.Warn("Storing artifact that failed validation", zap.String("taskID", task.ID), zap.Error(err))
+		}
+	}
 
 	// --- Create a Deep Copy ---
 	// This prevents modifying the state shared with the handler or other potential readers.
@@ -775,8 +1223,9 @@ func (ac *A2ACapability) applyUpdateToTask(task *a2aSchema.Task, update A2AYield
 	// Deep copy slices and maps within the task
 	if task.Artifacts != nil {
 		taskCopy.Artifacts = make([]a2aSchema.Artifact, len(task.Artifacts))
-		copy(taskCopy.Artifacts, task.Artifacts)
-		// Further deep copy Parts within artifacts if necessary (structs are usually copied by value)
+		for i, artifact := range task.Artifacts {
+			taskCopy.Artifacts[i] = deepCopyArtifact(artifact)
+		}
 	} else {
 		taskCopy.Artifacts = []a2aSchema.Artifact{} // Ensure initialized
 	}
@@ -795,34 +1244,28 @@ func (ac *A2ACapability) applyUpdateToTask(task *a2aSchema.Task, update A2AYield
 		taskCopy.Metadata = &newMeta
 	}
 	// Status is a struct, copied by value. Message inside status is a pointer, handle defensively.
-	if task.Status.Message != nil {
-		msgCopy := *task.Status.Message
-		// Deep copy parts in status message
-		if task.Status.Message.Parts != nil {
-			msgCopy.Parts = make([]a2aSchema.Part, len(task.Status.Message.Parts))
-			copy(msgCopy.Parts, task.Status.Message.Parts)
-		}
-		taskCopy.Status.Message = &msgCopy
-	}
+	taskCopy.Status.Message = deepCopyMessage(task.Status.Message)
 
 	// --- Apply Update to the Copy ---
+	// The handler may reuse the structs it yielded, so nothing stored may share memory with the update
 	if update.Status != nil {
 		taskCopy.Status = *update.Status
+		taskCopy.Status.Message = deepCopyMessage(update.Status.Message)
 		if taskCopy.Status.Timestamp.IsZero() {
 			taskCopy.Status.Timestamp = time.Now()
 		}
 		if taskCopy.Status.Message != nil && taskCopy.Status.Message.Role == "agent" {
 			// Add agent message from status to history (avoiding duplicates is complex, let's just append)
-			taskCopy.History = append(taskCopy.History, *taskCopy.Status.Message)
+			taskCopy.History = ac.pruneHistory(append(taskCopy.History, *taskCopy.Status.Message))
 		}
 	} else if update.Artifact != nil {
-		artifactUpdate := *update.Artifact
+		artifactUpdate := deepCopyArtifact(*update.Artifact)
 		found := false
 		for i := range taskCopy.Artifacts {
 			if taskCopy.Artifacts[i].Index == artifactUpdate.Index {
 				existingArtifact := &taskCopy.Artifacts[i]
 				if artifactUpdate.Append != nil && *artifactUpdate.Append {
-					existingArtifact.Parts = append(existingArtifact.Parts, artifactUpdate.Parts...)
+					*existingArtifact = ac.artifactMergeStrategy.Merge(*existingArtifact, artifactUpdate)
 					if artifactUpdate.LastChunk != nil {
 						existingArtifact.LastChunk = artifactUpdate.LastChunk
 					} // Update other fields as needed
@@ -847,16 +1290,119 @@ func (ac *A2ACapability) applyUpdateToTask(task *a2aSchema.Task, update A2AYield
 	return &taskCopy, nil
 }
 
-// storeCancelFunc stores the cancel function associated with a running task handler.
-func (ac *A2ACapability) storeCancelFunc(taskID string, cancel context.CancelFunc) {
+// deepCopyParts returns a copy of parts that shares no pointers with it. Data and metadata
+// maps are copied one level deep.
+func deepCopyParts(parts []a2aSchema.Part) []a2aSchema.Part {
+	if parts == nil {
+		return nil
+	}
+	copied := make([]a2aSchema.Part, len(parts))
+	for i, part := range parts {
+		copied[i] = a2aSchema.Part{
+			Type:     copyPointer(part.Type),
+			Text:     copyPointer(part.Text),
+			Data:     copyMapPointer(part.Data),
+			Metadata: copyMapPointer(part.Metadata),
+		}
+		if part.File != nil {
+			copied[i].File = &a2aSchema.FileContent{
+				Name:     copyPointer(part.File.Name),
+				MimeType: copyPointer(part.File.MimeType),
+				Bytes:    copyPointer(part.File.Bytes),
+				URI:      copyPointer(part.File.URI),
+			}
+		}
+	}
+	return copied
+}
+
+// deepCopyMessage returns a copy of message with its own parts, or nil.
+func deepCopyMessage(message *a2aSchema.Message) *a2aSchema.Message {
+	if message == nil {
+		return nil
+	}
+	copied := *message
+	copied.Parts = deepCopyParts(message.Parts)
+	copied.Metadata = copyMapPointer(message.Metadata)
+	return &copied
+}
+
+// deepCopyArtifact returns a copy of artifact with its own parts.
+func deepCopyArtifact(artifact a2aSchema.Artifact) a2aSchema.Artifact {
+	copied := artifact
+	copied.Name = copyPointer(artifact.Name)
+	copied.Description = copyPointer(artifact.Description)
+	copied.Parts = deepCopyParts(artifact.Parts)
+	copied.Metadata = copyMapPointer(artifact.Metadata)
+	copied.Append = copyPointer(artifact.Append)
+	copied.LastChunk = copyPointer(artifact.LastChunk)
+	return copied
+}
+
+// copyPointer returns a pointer to a copy of *p, or nil.
+func copyPointer[T any](p *T) *T {
+	if p == nil {
+		return nil
+	}
+	v := *p
+	return &v
+}
+
+// copyMapPointer returns a pointer to a shallow copy of *m, or nil.
+func copyMapPointer(m *map[string]interface{}) *map[string]interface{} {
+	if m == nil {
+		return nil
+	}
+	copied := maps.Clone(*m)
+	return &copied
+}
+
+// artifactBase64SampleSize is how much of a file part's bytes is decoded to detect malformed base64.
+const artifactBase64SampleSize = 100
+
+// validateArtifact checks that every part carries the content its type promises:
+// text parts need text, file parts (e.g. image/*) need bytes or a URI, and bytes must look like base64.
+func validateArtifact(artifact a2aSchema.Artifact) error {
+	for i, part := range artifact.Parts {
+		if part.Type == nil {
+			continue
+		}
+		switch *part.Type {
+		case "text":
+			if part.Text == nil {
+				return fmt.Errorf("invalid artifact %d: text part %d has no text", artifact.Index, i)
+			}
+		case "file":
+			if part.File == nil || (part.File.Bytes == nil && part.File.URI == nil) {
+				return fmt.Errorf("invalid artifact %d: file part %d has neither bytes nor uri", artifact.Index, i)
+			}
+			if part.File.Bytes != nil {
+				sample := *part.File.Bytes
+				if len(sample) > artifactBase64SampleSize {
+					sample = sample[:artifactBase64SampleSize]
+				}
+				if _, err := base64.StdEncoding.DecodeString(sample); err != nil {
+					return fmt.Errorf("invalid artifact %d: file part %d bytes are not base64: %w", artifact.Index, i, err)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// storeCancelFunc stores the cancel function associated with a running task handler and
+// returns the hub the run publishes its stream events to.
+func (ac *A2ACapability) storeCancelFunc(taskID string, cancel context.CancelFunc) *taskHub {
 	ac.runningHandlersMu.Lock()
 	defer ac.runningHandlersMu.Unlock()
-	if existingCancel, ok := ac.runningHandlers[taskID]; ok {
+	if existing, ok := ac.runningHandlers[taskID]; ok {
 		ac.logger.Warn("Handler already running for task, cancelling previous one", zap.String("taskID", taskID))
-		existingCancel() // Cancel the old one before storing the new one
+		existing.cancel() // Cancel the old one before storing the new one
 	}
-	ac.runningHandlers[taskID] = cancel
+	hub := newTaskHub()
+	ac.runningHandlers[taskID] = &runningHandler{cancel: cancel, hub: hub}
 	ac.logger.Debug("Stored cancel function for task", zap.String("taskID", taskID))
+	return hub
 }
 
 // removeCancelFunc removes the reference to the cancel function for a task.
@@ -883,7 +1429,7 @@ func (ac *A2ACapability) isHandlerRunning(taskID string) bool {
 // Returns true if a handler was found and its context cancellation was invoked.
 func (ac *A2ACapability) cancelHandler(taskID string) bool {
 	ac.runningHandlersMu.Lock()
-	cancel, ok := ac.runningHandlers[taskID]
+	running, ok := ac.runningHandlers[taskID]
 	if ok {
 		// Remove immediately while holding lock to prevent race conditions
 		delete(ac.runningHandlers, taskID)
@@ -892,7 +1438,7 @@ func (ac *A2ACapability) cancelHandler(taskID string) bool {
 
 	if ok {
 		ac.logger.Info("Cancelling running handler context for task", zap.String("taskID", taskID))
-		cancel() // Call the actual context cancellation function
+		running.cancel() // Call the actual context cancellation function
 		return true
 	}
 	ac.logger.Debug("No running handler found to cancel for task", zap.String("taskID", taskID))
@@ -905,6 +1451,9 @@ func (ac *A2ACapability) SetCapabilities(s *mcpSchema.ServerCapabilities) {
 	ac.logger.Debug("SetCapabilities called on A2ACapability (no MCP fields modified)")
 }
 
+// SetEventBus is part of the IServerCapability interface; A2A tasks publish no events.
+func (ac *A2ACapability) SetEventBus(bus shared.EventBus) {}
+
 // isTerminalState checks if a task state indicates final completion (success, failure, or cancellation).
 func isTerminalState(state a2aSchema.TaskState) bool {
 	switch state {
@@ -915,6 +1464,16 @@ func isTerminalState(state a2aSchema.TaskState) bool {
 	}
 }
 
+// CheckTaskStore verifies that the task store can be queried. A missing task is a valid answer.
+func (ac *A2ACapability) CheckTaskStore(ctx context.Context) error {
+	_, err := ac.taskStore.Load(ctx, "health-check")
+	var jsonRPCErr *a2aSchema.JSONRPCError
+	if err != nil && !(errors.As(err, &jsonRPCErr) && jsonRPCErr.Code == a2aSchema.ErrorCodeTaskNotFound) {
+		return fmt.Errorf("task store unavailable: %w", err)
+	}
+	return nil
+}
+
 // SetManager allows setting the session manager (needed for interface compatibility if used in MCP context).
 func (ac *A2ACapability) SetManager(manager transport.ISessionManager) {
 	ac.manager = manager
@@ -947,4 +1506,4 @@ func createErrorStatus(err error, jsonErr *a2aSchema.JSONRPCError) a2aSchema.Tas
 		Timestamp: time.Now(),
 		Message:   agentMessage, // Embed the error message details
 	}
-}
\ No newline at end of file
+}
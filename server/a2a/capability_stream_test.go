@@ -0,0 +1,305 @@
+package a2a_test
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gate4ai/gate4ai/server/a2a"
+	"github.com/gate4ai/gate4ai/server/cmd/a2a-example-server/agent"
+	"github.com/gate4ai/gate4ai/server/transport"
+	"github.com/gate4ai/gate4ai/shared"
+	a2aSchema "github.com/gate4ai/gate4ai/shared/a2a/2025-draft/schema"
+	"github.com/gate4ai/gate4ai/shared/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// streamedEvent is one SSE event classified the same way the A2A client does it.
+type streamedEvent struct {
+	Type     string // "status" or "artifact"
+	Status   *a2aSchema.TaskStatusUpdateEvent
+	Artifact *a2aSchema.TaskArtifactUpdateEvent
+}
+
+// newA2ATestServer wires the manager, transport and A2A capability the way server.Start does.
+func newA2ATestServer(t *testing.T, handler a2a.A2AHandler, options ...a2a.A2AOption) *httptest.Server {
+	logger := zap.NewNop()
+	cfg := config.NewInternalConfig()
+	cfg.AuthorizationTypeValue = config.NotAuthorizedEverywhere
+	cfg.A2AAgentNameValue = "stream-test-agent"
+	cfg.A2AAgentVersionValue = "1.0.0"
+
+	manager, err := transport.NewManager(logger, cfg)
+	require.NoError(t, err)
+	tr, err := transport.New(manager, logger, cfg)
+	require.NoError(t, err)
+	manager.AddCapability(a2a.NewA2ACapability(logger, manager, a2a.NewInMemoryTaskStore(), handler, options...))
+
+	agentCard, err := cfg.GetA2AAgentCard(transport.A2A_PATH)
+	require.NoError(t, err)
+	mux := http.NewServeMux()
+	tr.RegisterA2AHandlers(mux, agentCard)
+
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestA2AStreamingEndToEnd(t *testing.T) {
+	server := newA2ATestServer(t, agent.DemoAgentHandler)
+
+	params := a2aSchema.TaskSendParams{
+		ID:      "stream-task-1",
+		Message: a2aSchema.Message{Role: "user", Parts: []a2aSchema.Part{{Type: shared.PointerTo("text"), Text: shared.PointerTo("stream_test stream 3 chunks")}}},
+	}
+	body, err := json.Marshal(map[string]interface{}{"jsonrpc": "2.0", "id": 1, "method": "tasks/sendSubscribe", "params": params})
+	require.NoError(t, err)
+
+	req, err := http.NewRequest(http.MethodPost, server.URL+transport.A2A_PATH, strings.NewReader(string(body)))
+	require.NoError(t, err)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "text/event-stream")
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	require.Contains(t, resp.Header.Get("Content-Type"), "text/event-stream")
+
+	var mu sync.Mutex
+	var events []streamedEvent
+	go func() {
+		reader := bufio.NewReader(resp.Body)
+		for {
+			line, err := reader.ReadString('\n')
+			if err != nil {
+				return
+			}
+			if !strings.HasPrefix(line, "data:") {
+				continue
+			}
+			var rpcResponse struct {
+				Result json.RawMessage `json:"result"`
+			}
+			if json.Unmarshal([]byte(strings.TrimSpace(strings.TrimPrefix(line, "data:"))), &rpcResponse) != nil {
+				continue
+			}
+			// The first result is the initial task; it has an "id" and "status" but no "final" flag
+			var probe map[string]json.RawMessage
+			if json.Unmarshal(rpcResponse.Result, &probe) != nil {
+				continue
+			}
+			event := streamedEvent{}
+			if _, ok := probe["artifact"]; ok {
+				event.Type = "artifact"
+				event.Artifact = &a2aSchema.TaskArtifactUpdateEvent{}
+				if json.Unmarshal(rpcResponse.Result, event.Artifact) != nil {
+					continue
+				}
+			} else if _, ok := probe["final"]; ok {
+				event.Type = "status"
+				event.Status = &a2aSchema.TaskStatusUpdateEvent{}
+				if json.Unmarshal(rpcResponse.Result, event.Status) != nil {
+					continue
+				}
+			} else {
+				continue
+			}
+			mu.Lock()
+			events = append(events, event)
+			mu.Unlock()
+		}
+	}()
+
+	require.EventuallyWithT(t, func(c *assert.CollectT) {
+		mu.Lock()
+		defer mu.Unlock()
+		if !assert.NotEmpty(c, events) {
+			return
+		}
+		last := events[len(events)-1]
+		if !assert.Equal(c, "status", last.Type) {
+			return
+		}
+		assert.True(c, last.Status.Final, "last event must be final")
+		assert.Equal(c, a2aSchema.TaskStateCompleted, last.Status.Status.State)
+	}, 10*time.Second, 50*time.Millisecond)
+
+	mu.Lock()
+	defer mu.Unlock()
+	artifacts := 0
+	for _, event := range events {
+		if event.Type == "artifact" {
+			artifacts++
+		}
+	}
+	assert.Equal(t, 3, artifacts, "expected one artifact event per streamed chunk")
+}
+
+// rawSSEEvent is one SSE event as written by the transport.
+type rawSSEEvent struct {
+	ID     string
+	Name   string
+	Result json.RawMessage
+}
+
+// postA2A sends one A2A request and returns the SSE events of the response, or the plain result for non-streaming calls.
+func postA2A(t *testing.T, serverURL string, method string, params interface{}) []rawSSEEvent {
+	body, err := json.Marshal(map[string]interface{}{"jsonrpc": "2.0", "id": 1, "method": method, "params": params})
+	require.NoError(t, err)
+	req, err := http.NewRequest(http.MethodPost, serverURL+transport.A2A_PATH, strings.NewReader(string(body)))
+	require.NoError(t, err)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json, text/event-stream")
+	resp, err := (&http.Client{Timeout: 10 * time.Second}).Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	type rpcResponse struct {
+		Result json.RawMessage      `json:"result"`
+		Error  *shared.JSONRPCError `json:"error"`
+	}
+	if !strings.Contains(resp.Header.Get("Content-Type"), "text/event-stream") {
+		var response rpcResponse
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&response))
+		require.Nil(t, response.Error)
+		return []rawSSEEvent{{Result: response.Result}}
+	}
+
+	// The transport closes the stream after the final status event
+	var events []rawSSEEvent
+	id, name := "", ""
+	scanner := bufio.NewScanner(resp.Body)
+	for scanner.Scan() {
+		line := scanner.Text()
+		switch {
+		case strings.HasPrefix(line, "id:"):
+			id = strings.TrimSpace(strings.TrimPrefix(line, "id:"))
+		case strings.HasPrefix(line, "event:"):
+			name = strings.TrimSpace(strings.TrimPrefix(line, "event:"))
+		case strings.HasPrefix(line, "data:"):
+			var response rpcResponse
+			require.NoError(t, json.Unmarshal([]byte(strings.TrimSpace(strings.TrimPrefix(line, "data:"))), &response))
+			require.Nil(t, response.Error)
+			events = append(events, rawSSEEvent{ID: id, Name: name, Result: response.Result})
+			id, name = "", ""
+		}
+	}
+	return events
+}
+
+func TestA2AStreamingHistoryEvents(t *testing.T) {
+	// Asks for a name first, then greets
+	handler := func(ctx context.Context, task *a2aSchema.Task, updates chan<- a2a.A2AYieldUpdate, logger *zap.Logger) error {
+		agentText := func(text string) *a2aSchema.Message {
+			return &a2aSchema.Message{Role: "agent", Parts: []a2aSchema.Part{{Type: shared.PointerTo("text"), Text: shared.PointerTo(text)}}}
+		}
+		if len(task.History) < 2 {
+			updates <- a2a.A2AYieldUpdate{Status: &a2aSchema.TaskStatus{State: a2aSchema.TaskStateInputRequired, Message: agentText("What is your name?")}}
+			return nil
+		}
+		name := *task.History[len(task.History)-1].Parts[0].Text
+		updates <- a2a.A2AYieldUpdate{Status: &a2aSchema.TaskStatus{State: a2aSchema.TaskStateCompleted, Message: agentText("Hello, " + name)}}
+		return nil
+	}
+	server := newA2ATestServer(t, handler)
+
+	userTurn := func(text string) a2aSchema.TaskSendParams {
+		return a2aSchema.TaskSendParams{
+			ID:      "history-task",
+			Message: a2aSchema.Message{Role: "user", Parts: []a2aSchema.Part{{Type: shared.PointerTo("text"), Text: shared.PointerTo(text)}}},
+		}
+	}
+	historyTexts := func(events []rawSSEEvent) []string {
+		var texts []string
+		for _, event := range events {
+			if event.Name != shared.A2ASSEEventHistory {
+				continue
+			}
+			var history a2aSchema.TaskHistoryUpdateEvent
+			require.NoError(t, json.Unmarshal(event.Result, &history))
+			assert.Equal(t, "history-task", history.ID)
+			assert.Equal(t, "agent", history.Message.Role)
+			texts = append(texts, *history.Message.Parts[0].Text)
+		}
+		return texts
+	}
+
+	assert.Equal(t, []string{"What is your name?"}, historyTexts(postA2A(t, server.URL, "tasks/sendSubscribe", userTurn("hi"))))
+	// The stream ends with the final event, slightly before the handler goroutine deregisters the task
+	time.Sleep(100 * time.Millisecond)
+	assert.Equal(t, []string{"Hello, Ada"}, historyTexts(postA2A(t, server.URL, "tasks/sendSubscribe", userTurn("Ada"))))
+
+	result := postA2A(t, server.URL, "tasks/get", a2aSchema.TaskQueryParams{ID: "history-task", HistoryLength: shared.PointerTo(10)})
+	var task a2aSchema.Task
+	require.NoError(t, json.Unmarshal(result[0].Result, &task))
+	var conversation []string
+	for _, message := range task.History {
+		conversation = append(conversation, message.Role+": "+*message.Parts[0].Text)
+	}
+	assert.Equal(t, []string{"user: hi", "agent: What is your name?", "user: Ada", "agent: Hello, Ada"}, conversation)
+	assert.Equal(t, a2aSchema.TaskStateCompleted, task.Status.State)
+}
+
+func TestA2AStreamingHandlerStartupTimeout(t *testing.T) {
+	// The handler takes 5 seconds before its first update
+	handler := func(ctx context.Context, task *a2aSchema.Task, updates chan<- a2a.A2AYieldUpdate, logger *zap.Logger) error {
+		select {
+		case <-time.After(5 * time.Second):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+		updates <- a2a.A2AYieldUpdate{Status: &a2aSchema.TaskStatus{State: a2aSchema.TaskStateCompleted}}
+		return nil
+	}
+	server := newA2ATestServer(t, handler, a2a.WithHandlerStartupTimeout(time.Second))
+
+	params := a2aSchema.TaskSendParams{
+		ID:      "slow-start-task",
+		Message: a2aSchema.Message{Role: "user", Parts: []a2aSchema.Part{{Type: shared.PointerTo("text"), Text: shared.PointerTo("hello")}}},
+	}
+	body, err := json.Marshal(map[string]interface{}{"jsonrpc": "2.0", "id": 1, "method": "tasks/sendSubscribe", "params": params})
+	require.NoError(t, err)
+	req, err := http.NewRequest(http.MethodPost, server.URL+transport.A2A_PATH, strings.NewReader(string(body)))
+	require.NoError(t, err)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "text/event-stream")
+	start := time.Now()
+	resp, err := (&http.Client{Timeout: 10 * time.Second}).Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Contains(t, resp.Header.Get("Content-Type"), "text/event-stream")
+
+	var streamErr *shared.JSONRPCError
+	scanner := bufio.NewScanner(resp.Body)
+	for scanner.Scan() && streamErr == nil {
+		line := scanner.Text()
+		if !strings.HasPrefix(line, "data:") {
+			continue
+		}
+		var response struct {
+			Error *shared.JSONRPCError `json:"error"`
+		}
+		require.NoError(t, json.Unmarshal([]byte(strings.TrimSpace(strings.TrimPrefix(line, "data:"))), &response))
+		streamErr = response.Error
+	}
+	elapsed := time.Since(start)
+	require.NotNil(t, streamErr, "expected an error event on the stream")
+	assert.Contains(t, streamErr.Message, "did not start")
+	assert.Equal(t, shared.A2AErrorTransient, streamErr.Category)
+	assert.GreaterOrEqual(t, elapsed, 900*time.Millisecond)
+	assert.Less(t, elapsed, 2500*time.Millisecond, "error event must arrive around the startup timeout, not after the handler's first update")
+
+	events := postA2A(t, server.URL, "tasks/get", a2aSchema.TaskQueryParams{ID: "slow-start-task"})
+	var task a2aSchema.Task
+	require.NoError(t, json.Unmarshal(events[0].Result, &task))
+	assert.Equal(t, a2aSchema.TaskStateFailed, task.Status.State)
+}
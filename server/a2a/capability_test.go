@@ -0,0 +1,296 @@
+package a2a_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gate4ai/gate4ai/server/a2a"
+	"github.com/gate4ai/gate4ai/server/transport"
+	"github.com/gate4ai/gate4ai/shared"
+	a2aSchema "github.com/gate4ai/gate4ai/shared/a2a/2025-draft/schema"
+	"github.com/gate4ai/gate4ai/shared/config"
+	sharedtesting "github.com/gate4ai/gate4ai/shared/testing"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestTaskSendStopsWhenRequestContextIsCancelled(t *testing.T) {
+	logger := zap.NewNop()
+	manager, err := transport.NewManager(logger, config.NewInternalConfig())
+	require.NoError(t, err)
+
+	handlerStopped := make(chan time.Time, 1)
+	handler := func(ctx context.Context, task *a2aSchema.Task, updates chan<- a2a.A2AYieldUpdate, logger *zap.Logger) error {
+		<-ctx.Done()
+		handlerStopped <- time.Now()
+		return ctx.Err()
+	}
+	capability := a2a.NewA2ACapability(logger, manager, a2a.NewInMemoryTaskStore(), handler)
+
+	// Stands in for the HTTP request context set by the transport
+	requestCtx, dropConnection := context.WithCancel(context.Background())
+	defer dropConnection()
+	msg := sharedtesting.BuildMessage("tasks/send", a2aSchema.TaskSendParams{
+		ID:      "ctx-task",
+		Message: a2aSchema.Message{Role: "user", Parts: []a2aSchema.Part{{Type: shared.PointerTo("text"), Text: shared.PointerTo("wait")}}},
+	})
+	msg.SetContext(requestCtx)
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		_, _ = capability.GetHandlers()["tasks/send"](msg)
+	}()
+
+	time.Sleep(100 * time.Millisecond)
+	droppedAt := time.Now()
+	dropConnection()
+
+	select {
+	case stoppedAt := <-handlerStopped:
+		require.Less(t, stoppedAt.Sub(droppedAt), 500*time.Millisecond)
+	case <-time.After(500 * time.Millisecond):
+		t.Fatal("handler did not observe the dropped connection within 500ms")
+	}
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("tasks/send did not return after the handler stopped")
+	}
+}
+
+func TestArtifactValidation(t *testing.T) {
+	malformed := map[string]a2aSchema.Part{
+		"text without text":  {Type: shared.PointerTo("text")},
+		"image without data": {Type: shared.PointerTo("file"), File: &a2aSchema.FileContent{MimeType: shared.PointerTo("image/png")}},
+		"malformed base64":   {Type: shared.PointerTo("file"), File: &a2aSchema.FileContent{MimeType: shared.PointerTo("image/png"), Bytes: shared.PointerTo("not*base64!")}},
+	}
+
+	for name, part := range malformed {
+		for _, strict := range []bool{false, true} {
+			t.Run(fmt.Sprintf("%s strict=%v", name, strict), func(t *testing.T) {
+				core, logs := observer.New(zapcore.WarnLevel)
+				logger := zap.New(core)
+				manager, err := transport.NewManager(zap.NewNop(), config.NewInternalConfig())
+				require.NoError(t, err)
+				handler := func(ctx context.Context, task *a2aSchema.Task, updates chan<- a2a.A2AYieldUpdate, logger *zap.Logger) error {
+					updates <- a2a.A2AYieldUpdate{Artifact: &a2aSchema.Artifact{Parts: []a2aSchema.Part{part}}}
+					updates <- a2a.A2AYieldUpdate{Status: &a2aSchema.TaskStatus{State: a2aSchema.TaskStateCompleted}}
+					return nil
+				}
+				store := a2a.NewInMemoryTaskStore()
+				capability := a2a.NewA2ACapability(logger, manager, store, handler, a2a.WithStrictArtifactValidation(strict))
+
+				result, err := capability.GetHandlers()["tasks/send"](sharedtesting.BuildMessage("tasks/send", a2aSchema.TaskSendParams{
+					ID:      "artifact-task",
+					Message: a2aSchema.Message{Role: "user", Parts: []a2aSchema.Part{{Type: shared.PointerTo("text"), Text: shared.PointerTo("go")}}},
+				}))
+				task, getErr := store.Load(context.Background(), "artifact-task")
+				require.NoError(t, getErr)
+
+				if strict {
+					sharedtesting.AssertJSONRPCError(t, result, err, shared.JSONRPCErrorInternal)
+					assert.Contains(t, err.Error(), "invalid artifact")
+					assert.Equal(t, a2aSchema.TaskStateFailed, task.Status.State)
+					assert.Empty(t, task.Artifacts)
+				} else {
+					sharedtesting.AssertJSONRPCSuccess[*a2aSchema.Task](t, result, err)
+					assert.Equal(t, a2aSchema.TaskStateCompleted, task.Status.State)
+					assert.Len(t, task.Artifacts, 1)
+					assert.Equal(t, 1, logs.FilterMessage("Storing artifact that failed validation").Len())
+				}
+			})
+		}
+	}
+}
+
+func TestTaskCreationRateLimit(t *testing.T) {
+	logger := zap.NewNop()
+	manager, err := transport.NewManager(logger, config.NewInternalConfig())
+	require.NoError(t, err)
+	handler := func(ctx context.Context, task *a2aSchema.Task, updates chan<- a2a.A2AYieldUpdate, logger *zap.Logger) error {
+		updates <- a2a.A2AYieldUpdate{Status: &a2aSchema.TaskStatus{State: a2aSchema.TaskStateCompleted}}
+		return nil
+	}
+	const burst = 5
+	capability := a2a.NewA2ACapability(logger, manager, a2a.NewInMemoryTaskStore(), handler, a2a.WithTaskCreationRateLimit(0.01, burst))
+	sendTask := capability.GetHandlers()["tasks/send"]
+
+	session := userSession("spammer", "spammer")
+	send := func(session shared.ISession, taskID string) (interface{}, error) {
+		msg := sharedtesting.BuildMessage("tasks/send", a2aSchema.TaskSendParams{
+			ID:      taskID,
+			Message: a2aSchema.Message{Role: "user", Parts: []a2aSchema.Part{{Type: shared.PointerTo("text"), Text: shared.PointerTo("hi")}}},
+		})
+		msg.Session = session
+		return sendTask(msg)
+	}
+
+	for i := 0; i < 20; i++ {
+		result, err := send(session, fmt.Sprintf("task-%d", i))
+		if i < burst {
+			sharedtesting.AssertJSONRPCSuccess[*a2aSchema.Task](t, result, err)
+		} else {
+			sharedtesting.AssertJSONRPCError(t, result, err, a2aSchema.ErrorCodeRateLimitExceeded)
+		}
+	}
+
+	// Continuing an existing task does not create one
+	result, err := send(session, "task-0")
+	sharedtesting.AssertJSONRPCSuccess[*a2aSchema.Task](t, result, err)
+	// A new session does not bring a new budget, another user has its own
+	result, err = send(userSession("fresh-session", "spammer"), "fresh-task")
+	sharedtesting.AssertJSONRPCError(t, result, err, a2aSchema.ErrorCodeRateLimitExceeded)
+	result, err = send(userSession("other", "other"), "other-task")
+	sharedtesting.AssertJSONRPCSuccess[*a2aSchema.Task](t, result, err)
+}
+
+func TestTaskErrorsCarryStructuredData(t *testing.T) {
+	logger := zap.NewNop()
+	manager, err := transport.NewManager(logger, config.NewInternalConfig())
+	require.NoError(t, err)
+	store := a2a.NewInMemoryTaskStore()
+	require.NoError(t, store.Save(context.Background(), &a2aSchema.Task{ID: "done-task", Status: a2aSchema.TaskStatus{State: a2aSchema.TaskStateCompleted}}))
+	handler := func(ctx context.Context, task *a2aSchema.Task, updates chan<- a2a.A2AYieldUpdate, logger *zap.Logger) error {
+		return nil
+	}
+	capability := a2a.NewA2ACapability(logger, manager, store, handler)
+
+	// errorData round-trips err through a JSON-RPC error response and returns its data member.
+	errorData := func(err error) (int, map[string]string) {
+		raw, marshalErr := json.Marshal(shared.JSONRPCErrorResponse{JSONRPC: shared.JSONRPCVersion, Error: shared.NewJSONRPCError(err)})
+		require.NoError(t, marshalErr)
+		var resp struct {
+			Error struct {
+				Code int               `json:"code"`
+				Data map[string]string `json:"data"`
+			} `json:"error"`
+		}
+		require.NoError(t, json.Unmarshal(raw, &resp))
+		return resp.Error.Code, resp.Error.Data
+	}
+
+	_, err = capability.GetHandlers()["tasks/cancel"](sharedtesting.BuildMessage("tasks/cancel", a2aSchema.TaskIdParams{ID: "done-task"}))
+	code, data := errorData(err)
+	assert.Equal(t, a2aSchema.ErrorCodeTaskNotCancelable, code)
+	assert.Equal(t, map[string]string{"taskId": "done-task", "currentState": "completed"}, data)
+
+	_, err = capability.GetHandlers()["tasks/get"](sharedtesting.BuildMessage("tasks/get", a2aSchema.TaskQueryParams{ID: "missing-task"}))
+	code, data = errorData(err)
+	assert.Equal(t, a2aSchema.ErrorCodeTaskNotFound, code)
+	assert.Equal(t, map[string]string{"taskId": "missing-task"}, data)
+
+	withData := (&shared.JSONRPCError{Code: shared.JSONRPCErrorInvalidParams, Message: "bad"}).WithData(map[string]string{"field": "id"})
+	code, data = errorData(withData)
+	assert.Equal(t, shared.JSONRPCErrorInvalidParams, code)
+	assert.Equal(t, map[string]string{"field": "id"}, data)
+}
+
+// savedStateStore signals saved once a task with state is saved.
+type savedStateStore struct {
+	*a2a.InMemoryTaskStore
+	state a2aSchema.TaskState
+	once  sync.Once
+	saved chan struct{}
+}
+
+func (s *savedStateStore) Save(ctx context.Context, task *a2aSchema.Task) error {
+	err := s.InMemoryTaskStore.Save(ctx, task)
+	if task.Status.State == s.state {
+		s.once.Do(func() { close(s.saved) })
+	}
+	return err
+}
+
+func TestYieldedPartsAreCopied(t *testing.T) {
+	store := &savedStateStore{InMemoryTaskStore: a2a.NewInMemoryTaskStore(), state: a2aSchema.TaskStateCompleted, saved: make(chan struct{})}
+	// Yields an artifact and a final message, then reuses their parts once the task was saved
+	handler := func(ctx context.Context, task *a2aSchema.Task, updates chan<- a2a.A2AYieldUpdate, logger *zap.Logger) error {
+		text := "original"
+		data := map[string]interface{}{"key": "original"}
+		parts := []a2aSchema.Part{{Type: shared.PointerTo("text"), Text: &text}, {Type: shared.PointerTo("data"), Data: &data}}
+		artifact := a2aSchema.Artifact{Parts: parts}
+		message := a2aSchema.Message{Role: "agent", Parts: parts}
+		updates <- a2a.A2AYieldUpdate{Artifact: &artifact}
+		updates <- a2a.A2AYieldUpdate{Status: &a2aSchema.TaskStatus{State: a2aSchema.TaskStateCompleted, Message: &message}}
+
+		<-store.saved
+		text = "mutated"
+		data["key"] = "mutated"
+		parts[0].Text = shared.PointerTo("replaced")
+		message.Parts[1] = a2aSchema.Part{Type: shared.PointerTo("text"), Text: shared.PointerTo("replaced")}
+		return nil
+	}
+	logger := zap.NewNop()
+	manager, err := transport.NewManager(logger, config.NewInternalConfig())
+	require.NoError(t, err)
+	handlers := a2a.NewA2ACapability(logger, manager, store, handler).GetHandlers()
+
+	result, err := handlers["tasks/send"](sharedtesting.BuildMessage("tasks/send", a2aSchema.TaskSendParams{
+		ID:      "reused-parts",
+		Message: a2aSchema.Message{Role: "user", Parts: []a2aSchema.Part{{Type: shared.PointerTo("text"), Text: shared.PointerTo("go")}}},
+	}))
+	sharedtesting.AssertJSONRPCSuccess[*a2aSchema.Task](t, result, err)
+
+	result, err = handlers["tasks/get"](sharedtesting.BuildMessage("tasks/get", a2aSchema.TaskQueryParams{ID: "reused-parts", HistoryLength: shared.PointerTo(10)}))
+	task := sharedtesting.AssertJSONRPCSuccess[*a2aSchema.Task](t, result, err)
+	assertOriginalParts := func(what string, parts []a2aSchema.Part) {
+		require.Len(t, parts, 2, what)
+		assert.Equal(t, "original", *parts[0].Text, what)
+		require.NotNil(t, parts[1].Data, what)
+		assert.Equal(t, "original", (*parts[1].Data)["key"], what)
+	}
+	require.Len(t, task.Artifacts, 1)
+	assertOriginalParts("artifact", task.Artifacts[0].Parts)
+	require.NotNil(t, task.Status.Message)
+	assertOriginalParts("status message", task.Status.Message.Parts)
+	require.NotEmpty(t, task.History)
+	assertOriginalParts("history", task.History[len(task.History)-1].Parts)
+}
+
+func TestYieldedErrorFailsTask(t *testing.T) {
+	handler := func(ctx context.Context, task *a2aSchema.Task, updates chan<- a2a.A2AYieldUpdate, logger *zap.Logger) error {
+		updates <- a2a.A2AYieldUpdate{Status: &a2aSchema.TaskStatus{State: a2aSchema.TaskStateWorking}}
+		updates <- a2a.A2AYieldError(a2aSchema.ErrorCodeContentTypeNotSupported, "audio is not supported")
+		return nil
+	}
+	t.Run("tasks/send returns the error", func(t *testing.T) {
+		manager, err := transport.NewManager(zap.NewNop(), config.NewInternalConfig())
+		require.NoError(t, err)
+		store := a2a.NewInMemoryTaskStore()
+		capability := a2a.NewA2ACapability(zap.NewNop(), manager, store, handler)
+
+		result, err := capability.GetHandlers()["tasks/send"](sharedtesting.BuildMessage("tasks/send", a2aSchema.TaskSendParams{
+			ID:      "yield-error-task",
+			Message: a2aSchema.Message{Role: "user", Parts: textParts("go")},
+		}))
+		sharedtesting.AssertJSONRPCError(t, result, err, a2aSchema.ErrorCodeContentTypeNotSupported)
+		task, err := store.Load(context.Background(), "yield-error-task")
+		require.NoError(t, err)
+		assert.Equal(t, a2aSchema.TaskStateFailed, task.Status.State)
+		require.NotNil(t, task.Status.Message)
+		assert.Equal(t, "audio is not supported", *task.Status.Message.Parts[0].Text)
+	})
+
+	t.Run("tasks/sendSubscribe streams the error", func(t *testing.T) {
+		server := newA2ATestServer(t, handler)
+		streamErr := streamError(t, server.URL)
+		assert.Equal(t, a2aSchema.ErrorCodeContentTypeNotSupported, streamErr.Code)
+		assert.Equal(t, "audio is not supported", streamErr.Message)
+
+		var task a2aSchema.Task
+		require.Eventually(t, func() bool {
+			events := postA2A(t, server.URL, "tasks/get", a2aSchema.TaskQueryParams{ID: "failing-task"})
+			return len(events) == 1 && json.Unmarshal(events[0].Result, &task) == nil && task.Status.State == a2aSchema.TaskStateFailed
+		}, 5*time.Second, 20*time.Millisecond)
+		require.NotNil(t, task.Status.Message)
+		assert.Equal(t, "audio is not supported", *task.Status.Message.Parts[0].Text)
+	})
+}
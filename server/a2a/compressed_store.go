@@ -0,0 +1,155 @@
+package a2a
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"maps"
+	"strings"
+
+	a2aSchema "github.com/gate4ai/gate4ai/shared/a2a/2025-draft/schema"
+)
+
+// CompressedPayloadMetadataKey is the task metadata key holding the compressed task in
+// tasks saved by a CompressedTaskStore.
+const CompressedPayloadMetadataKey = "gate4aiCompressedTask"
+
+// compressedPayloadPrefix marks payloads written by CompressedTaskStore. Tasks saved
+// without it, e.g. before compression was enabled, are loaded unchanged.
+const compressedPayloadPrefix = "gz1:"
+
+// DefaultCompressionThreshold is the serialized task size in bytes above which
+// CompressedTaskStore compresses tasks.
+const DefaultCompressionThreshold = 64 * 1024
+
+// CompressedStoreOption configures a CompressedTaskStore.
+type CompressedStoreOption func(*compressedTaskStore)
+
+// WithCompressionThreshold compresses only tasks whose JSON is longer than threshold bytes.
+func WithCompressionThreshold(threshold int) CompressedStoreOption {
+	return func(s *compressedTaskStore) {
+		s.threshold = threshold
+	}
+}
+
+type compressedTaskStore struct {
+	underlying TaskStore
+	level      int
+	threshold  int
+}
+
+// CompressedTaskStore wraps underlying so large tasks are saved gzip-compressed at
+// compressionLevel (see compress/gzip). A compressed task is stored as a copy that keeps
+// the ID, session, owner, status and metadata, so List filters still apply, and carries the rest
+// in CompressedPayloadMetadataKey. Load and List return the original task.
+func CompressedTaskStore(underlying TaskStore, compressionLevel int, options ...CompressedStoreOption) TaskStore {
+	s := &compressedTaskStore{
+		underlying: underlying,
+		level:      compressionLevel,
+		threshold:  DefaultCompressionThreshold,
+	}
+	for _, option := range options {
+		option(s)
+	}
+	return s
+}
+
+// Save compresses the task if its JSON exceeds the threshold and saves it in the underlying store.
+func (s *compressedTaskStore) Save(ctx context.Context, task *a2aSchema.Task) error {
+	data, err := json.Marshal(task)
+	if err != nil {
+		return fmt.Errorf("failed to marshal task %s: %w", task.ID, err)
+	}
+	if len(data) <= s.threshold {
+		return s.underlying.Save(ctx, task)
+	}
+
+	var buf bytes.Buffer
+	zw, err := gzip.NewWriterLevel(&buf, s.level)
+	if err != nil {
+		return fmt.Errorf("failed to create gzip writer: %w", err)
+	}
+	if _, err := zw.Write(data); err != nil {
+		return fmt.Errorf("failed to compress task %s: %w", task.ID, err)
+	}
+	if err := zw.Close(); err != nil {
+		return fmt.Errorf("failed to compress task %s: %w", task.ID, err)
+	}
+
+	metadata := map[string]interface{}{}
+	if task.Metadata != nil {
+		metadata = maps.Clone(*task.Metadata)
+	}
+	metadata[CompressedPayloadMetadataKey] = compressedPayloadPrefix + base64.StdEncoding.EncodeToString(buf.Bytes())
+	stored := &a2aSchema.Task{
+		ID:          task.ID,
+		SessionID:   task.SessionID,
+		Status:      task.Status,
+		Metadata:    &metadata,
+		ScheduledAt: task.ScheduledAt,
+		CreatedAt:   task.CreatedAt,
+		UserID:      task.UserID,
+	}
+	return s.underlying.Save(ctx, stored)
+}
+
+// Load loads the task from the underlying store, decompressing it if needed.
+func (s *compressedTaskStore) Load(ctx context.Context, taskID string) (*a2aSchema.Task, error) {
+	task, err := s.underlying.Load(ctx, taskID)
+	if err != nil {
+		return nil, err
+	}
+	return decompressTask(task)
+}
+
+// Delete removes the task from the underlying store.
+func (s *compressedTaskStore) Delete(ctx context.Context, taskID string) error {
+	return s.underlying.Delete(ctx, taskID)
+}
+
+// List lists tasks from the underlying store, decompressing them if needed.
+func (s *compressedTaskStore) List(ctx context.Context, filter TaskFilter) ([]*a2aSchema.Task, string, error) {
+	tasks, nextCursor, err := s.underlying.List(ctx, filter)
+	if err != nil {
+		return nil, "", err
+	}
+	for i, task := range tasks {
+		if tasks[i], err = decompressTask(task); err != nil {
+			return nil, "", err
+		}
+	}
+	return tasks, nextCursor, nil
+}
+
+// decompressTask returns the original task if task was saved compressed, otherwise task itself.
+func decompressTask(task *a2aSchema.Task) (*a2aSchema.Task, error) {
+	if task.Metadata == nil {
+		return task, nil
+	}
+	payload, _ := (*task.Metadata)[CompressedPayloadMetadataKey].(string)
+	encoded, ok := strings.CutPrefix(payload, compressedPayloadPrefix)
+	if !ok {
+		return task, nil
+	}
+	compressed, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode compressed task %s: %w", task.ID, err)
+	}
+	zr, err := gzip.NewReader(bytes.NewReader(compressed))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decompress task %s: %w", task.ID, err)
+	}
+	data, err := io.ReadAll(zr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decompress task %s: %w", task.ID, err)
+	}
+	var original a2aSchema.Task
+	if err := json.Unmarshal(data, &original); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal decompressed task %s: %w", task.ID, err)
+	}
+	return &original, nil
+}
@@ -0,0 +1,79 @@
+package a2a_test
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gate4ai/gate4ai/server/a2a"
+	"github.com/gate4ai/gate4ai/shared"
+	a2aSchema "github.com/gate4ai/gate4ai/shared/a2a/2025-draft/schema"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// payloadRecordingStore records the JSON size of the last task it saved.
+type payloadRecordingStore struct {
+	*a2a.InMemoryTaskStore
+	lastPayload int
+}
+
+func (s *payloadRecordingStore) Save(ctx context.Context, task *a2aSchema.Task) error {
+	data, err := json.Marshal(task)
+	if err != nil {
+		return err
+	}
+	s.lastPayload = len(data)
+	return s.InMemoryTaskStore.Save(ctx, task)
+}
+
+func TestCompressedTaskStore(t *testing.T) {
+	ctx := context.Background()
+	underlying := &payloadRecordingStore{InMemoryTaskStore: a2a.NewInMemoryTaskStore()}
+	store := a2a.CompressedTaskStore(underlying, 6, a2a.WithCompressionThreshold(1024))
+
+	task := &a2aSchema.Task{
+		ID:        "large-task",
+		SessionID: "session-1",
+		Status:    a2aSchema.TaskStatus{State: a2aSchema.TaskStateCompleted, Timestamp: time.Now().UTC()},
+		Artifacts: []a2aSchema.Artifact{{
+			Name:  shared.PointerTo("report"),
+			Parts: []a2aSchema.Part{{Type: shared.PointerTo("text"), Text: shared.PointerTo(strings.Repeat("quarterly report line\n", 100*1024/22))}},
+		}},
+		Metadata: &map[string]interface{}{"owner": "alice"},
+	}
+	original, err := json.Marshal(task)
+	require.NoError(t, err)
+	require.Greater(t, len(original), 100*1000)
+
+	require.NoError(t, store.Save(ctx, task))
+	assert.Less(t, underlying.lastPayload, len(original)/10, "Underlying store should receive the compressed task")
+
+	loaded, err := store.Load(ctx, task.ID)
+	require.NoError(t, err)
+	roundTrip, err := json.Marshal(loaded)
+	require.NoError(t, err)
+	assert.JSONEq(t, string(original), string(roundTrip))
+
+	// Metadata filters still match the compressed task
+	tasks, _, err := store.List(ctx, a2a.TaskFilter{MetadataFilter: map[string]interface{}{"owner": "alice"}})
+	require.NoError(t, err)
+	require.Len(t, tasks, 1)
+	assert.Len(t, tasks[0].Artifacts, 1)
+
+	t.Run("small tasks and tasks saved before compression load unchanged", func(t *testing.T) {
+		small := &a2aSchema.Task{ID: "small-task", Status: a2aSchema.TaskStatus{State: a2aSchema.TaskStateSubmitted}}
+		require.NoError(t, store.Save(ctx, small))
+		assert.Less(t, underlying.lastPayload, 1024)
+		require.NoError(t, underlying.InMemoryTaskStore.Save(ctx, &a2aSchema.Task{ID: "legacy-task", History: []a2aSchema.Message{{Role: "user"}}}))
+
+		loaded, err := store.Load(ctx, "small-task")
+		require.NoError(t, err)
+		assert.Equal(t, small, loaded)
+		legacy, err := store.Load(ctx, "legacy-task")
+		require.NoError(t, err)
+		assert.Len(t, legacy.History, 1)
+	})
+}
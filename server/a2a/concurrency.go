@@ -0,0 +1,159 @@
+package a2a
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/gate4ai/gate4ai/server/transport"
+	"github.com/gate4ai/gate4ai/shared"
+	a2aSchema "github.com/gate4ai/gate4ai/shared/a2a/2025-draft/schema"
+	"go.uber.org/zap"
+)
+
+// WithMaxConcurrentTasksPerUser lets each user run at most n tasks/sendSubscribe handlers at
+// once, whatever sessions they use. Anonymous callers share one limit, as they cannot be told
+// apart. Tasks over the limit are queued: they are saved in TaskStateQueued, their stream gets a
+// "queued" status event, and they start in FIFO order as the user's running tasks end.
+// See WithMaxQueuedTasksPerUser to bound the queue. n <= 0 disables the limit.
+func WithMaxConcurrentTasksPerUser(n int) A2AOption {
+	return func(ac *A2ACapability) {
+		if n > 0 {
+			ac.userTaskSlots = &userTaskSlots{max: n, maxQueued: -1, users: make(map[string]*userSlots)}
+		}
+	}
+}
+
+// WithMaxQueuedTasksPerUser bounds the tasks each user can queue while running their maximum
+// of tasks (see WithMaxConcurrentTasksPerUser). Tasks beyond the queue are rejected with
+// ErrorCodeTooManyConcurrentTasks; n = 0 rejects every task over the limit instead of
+// queuing it. Must come after WithMaxConcurrentTasksPerUser.
+func WithMaxQueuedTasksPerUser(n int) A2AOption {
+	return func(ac *A2ACapability) {
+		if ac.userTaskSlots != nil && n >= 0 {
+			ac.userTaskSlots.maxQueued = n
+		}
+	}
+}
+
+// userTaskSlots counts the running tasks of each user and queues the tasks over the limit.
+type userTaskSlots struct {
+	max       int
+	maxQueued int // < 0 is unbounded
+	mu        sync.Mutex
+	users     map[string]*userSlots
+}
+
+type userSlots struct {
+	running int
+	queue   []chan struct{} // Turns of the queued tasks, oldest first
+}
+
+// acquire takes a slot of the user. The returned turn is closed once the task may start:
+// at once when a slot is free, otherwise when an earlier task hands its slot over.
+func (s *userTaskSlots) acquire(userID string) (<-chan struct{}, *a2aSchema.JSONRPCError) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	slots, ok := s.users[userID]
+	if !ok {
+		slots = &userSlots{}
+		s.users[userID] = slots
+	}
+	turn := make(chan struct{})
+	if slots.running < s.max {
+		slots.running++
+		close(turn)
+		return turn, nil
+	}
+	if s.maxQueued >= 0 && len(slots.queue) >= s.maxQueued {
+		return nil, &a2aSchema.JSONRPCError{
+			Code:    a2aSchema.ErrorCodeTooManyConcurrentTasks,
+			Message: fmt.Sprintf("User already runs %d tasks, wait for one to finish", s.max),
+		}
+	}
+	slots.queue = append(slots.queue, turn)
+	return turn, nil
+}
+
+// release frees a slot of the user, handing it to the oldest queued task if there is one.
+func (s *userTaskSlots) release(userID string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	slots, ok := s.users[userID]
+	if !ok {
+		return
+	}
+	if len(slots.queue) > 0 {
+		next := slots.queue[0]
+		slots.queue = slots.queue[1:]
+		close(next)
+		return
+	}
+	slots.running--
+	if slots.running <= 0 {
+		delete(s.users, userID)
+	}
+}
+
+// runQueuedTask waits for the turn of a task queued by tasks/sendSubscribe and starts it. A task
+// canceled while queued is not started; its slot goes to the next queued task.
+func (ac *A2ACapability) runQueuedTask(ctx context.Context, msg *shared.Message, taskID string, turn <-chan struct{}, logger *zap.Logger) {
+	userID := transport.GetUserId(msg.Session.GetParams())
+	<-turn
+	started := false
+	defer func() {
+		if !started {
+			ac.userTaskSlots.release(userID)
+		}
+	}()
+
+	task, err := ac.loadTask(ctx, taskID)
+	if err != nil {
+		logger.Error("Failed to load queued task", zap.Error(err))
+		return
+	}
+	if task.Status.State != a2aSchema.TaskStateQueued {
+		// E.g. canceled; the final event ends the stream that waited for the task
+		logger.Info("Queued task left the queue before it started", zap.String("state", string(task.Status.State)))
+		event := &shared.A2AStreamEvent{
+			Type:   "status",
+			Status: &a2aSchema.TaskStatusUpdateEvent{ID: task.ID, Status: task.Status, Final: true},
+			Final:  true,
+		}
+		if sendErr := msg.Session.SendA2AStreamEvent(event); sendErr != nil {
+			logger.Warn("Failed to send final event of dequeued task", zap.Error(sendErr))
+		}
+		return
+	}
+	slot, busyErr := ac.reserveWorker()
+	if busyErr != nil {
+		logger.Warn("No free worker for queued task")
+		task.Status = createErrorStatus(busyErr, busyErr)
+		if saveErr := ac.saveTask(ctx, task); saveErr != nil {
+			logger.Error("Failed to save failed queued task", zap.Error(saveErr))
+		}
+		if sendErr := msg.Session.SendA2AStreamEvent(streamErrorEvent(busyErr, true)); sendErr != nil {
+			logger.Warn("Failed to send error event of queued task", zap.Error(sendErr))
+		}
+		return
+	}
+	defer slot.release() // No-op once the handler started on it
+
+	task.Status = a2aSchema.TaskStatus{State: a2aSchema.TaskStateSubmitted, Timestamp: time.Now()}
+	if err := ac.saveTask(ctx, task); err != nil {
+		logger.Error("Failed to save dequeued task", zap.Error(err))
+		return
+	}
+	logger.Info("Starting queued task")
+	ac.startStreamingTask(ctx, msg, task, slot, logger)
+	started = true
+}
+
+// queuedStatusEvent is the stream event telling the client its task waits in the queue.
+func queuedStatusEvent(task *a2aSchema.Task) *shared.A2AStreamEvent {
+	return &shared.A2AStreamEvent{
+		Type:   "status",
+		Status: &a2aSchema.TaskStatusUpdateEvent{ID: task.ID, Status: task.Status},
+	}
+}
@@ -0,0 +1,165 @@
+package a2a_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/gate4ai/gate4ai/server/a2a"
+	"github.com/gate4ai/gate4ai/server/transport"
+	a2aSchema "github.com/gate4ai/gate4ai/shared/a2a/2025-draft/schema"
+	"github.com/gate4ai/gate4ai/shared/config"
+	sharedtesting "github.com/gate4ai/gate4ai/shared/testing"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// blockingTasks is an agent handler whose tasks run until released by ID, reporting each start.
+type blockingTasks struct {
+	started chan string
+	release map[string]chan struct{}
+}
+
+func newBlockingTasks(ids ...string) *blockingTasks {
+	b := &blockingTasks{started: make(chan string, len(ids)), release: make(map[string]chan struct{})}
+	for _, id := range ids {
+		b.release[id] = make(chan struct{})
+	}
+	return b
+}
+
+func (b *blockingTasks) handle(ctx context.Context, task *a2aSchema.Task, updates chan<- a2a.A2AYieldUpdate, logger *zap.Logger) error {
+	b.started <- task.ID
+	select {
+	case <-b.release[task.ID]:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	updates <- a2a.A2AYieldUpdate{Status: &a2aSchema.TaskStatus{State: a2aSchema.TaskStateCompleted}}
+	return nil
+}
+
+func (b *blockingTasks) expectStarted(t *testing.T, id string) {
+	t.Helper()
+	select {
+	case started := <-b.started:
+		assert.Equal(t, id, started)
+	case <-time.After(5 * time.Second):
+		t.Fatalf("Task %s did not start", id)
+	}
+}
+
+func (b *blockingTasks) expectNotStarted(t *testing.T) {
+	t.Helper()
+	select {
+	case started := <-b.started:
+		t.Fatalf("Task %s started while the user ran their maximum of tasks", started)
+	case <-time.After(100 * time.Millisecond):
+	}
+}
+
+// userSession returns a mock session authenticated as userID.
+func userSession(sessionID, userID string) *sharedtesting.MockSession {
+	session := sharedtesting.NewMockSession(sessionID)
+	transport.SaveUserId(session.GetParams(), userID)
+	return session
+}
+
+func newConcurrencyTestCapability(t *testing.T, b *blockingTasks, options ...a2a.A2AOption) *a2a.A2ACapability {
+	manager, err := transport.NewManager(zap.NewNop(), config.NewInternalConfig())
+	require.NoError(t, err)
+	return a2a.NewA2ACapability(zap.NewNop(), manager, a2a.NewInMemoryTaskStore(), b.handle, options...)
+}
+
+// sendSubscribeOn sends tasks/sendSubscribe for task id on session and returns the initial task.
+func sendSubscribeOn(t *testing.T, capability *a2a.A2ACapability, session *sharedtesting.MockSession, id string) (*a2aSchema.Task, error) {
+	msg := sharedtesting.BuildMessage("tasks/sendSubscribe", a2aSchema.TaskSendParams{
+		ID:      id,
+		Message: a2aSchema.Message{Role: "user", Parts: textParts("go")},
+	})
+	msg.Session = session
+	result, err := capability.GetHandlers()["tasks/sendSubscribe"](msg)
+	if err != nil {
+		return nil, err
+	}
+	task, ok := result.(*a2aSchema.Task)
+	require.True(t, ok, "unexpected result type %T", result)
+	return task, nil
+}
+
+// sentStates returns the task states of the status events queued on session.
+func sentStates(t *testing.T, session *sharedtesting.MockSession) []a2aSchema.TaskState {
+	var states []a2aSchema.TaskState
+	for _, msg := range session.Sent() {
+		if msg.Result == nil {
+			continue
+		}
+		var event a2aSchema.TaskStatusUpdateEvent
+		require.NoError(t, json.Unmarshal(*msg.Result, &event))
+		states = append(states, event.Status.State)
+	}
+	return states
+}
+
+func TestUserTaskLimitQueuesTasks(t *testing.T) {
+	b := newBlockingTasks("first", "second", "third", "other")
+	capability := newConcurrencyTestCapability(t, b, a2a.WithMaxConcurrentTasksPerUser(1))
+	session := userSession("limited", "limited-user")
+
+	_, err := sendSubscribeOn(t, capability, session, "first")
+	require.NoError(t, err)
+	b.expectStarted(t, "first")
+	session.Sent()
+
+	// A new session of the same user gets no slots of its own
+	secondSession := userSession("limited-again", "limited-user")
+	task, err := sendSubscribeOn(t, capability, session, "second")
+	require.NoError(t, err)
+	assert.Equal(t, a2aSchema.TaskStateQueued, task.Status.State)
+	task, err = sendSubscribeOn(t, capability, secondSession, "third")
+	require.NoError(t, err)
+	assert.Equal(t, a2aSchema.TaskStateQueued, task.Status.State)
+	b.expectNotStarted(t)
+	assert.Equal(t, []a2aSchema.TaskState{a2aSchema.TaskStateQueued}, sentStates(t, session))
+	assert.Equal(t, []a2aSchema.TaskState{a2aSchema.TaskStateQueued}, sentStates(t, secondSession))
+
+	// Another user is not limited by this one
+	other := userSession("other", "other-user")
+	_, err = sendSubscribeOn(t, capability, other, "other")
+	require.NoError(t, err)
+	b.expectStarted(t, "other")
+	close(b.release["other"])
+
+	// Queued tasks start in FIFO order as running tasks complete
+	close(b.release["first"])
+	b.expectStarted(t, "second")
+	b.expectNotStarted(t)
+	close(b.release["second"])
+	b.expectStarted(t, "third")
+	close(b.release["third"])
+}
+
+func TestUserTaskLimitRejectsWhenQueueFull(t *testing.T) {
+	b := newBlockingTasks("first", "second")
+	capability := newConcurrencyTestCapability(t, b,
+		a2a.WithMaxConcurrentTasksPerUser(1), a2a.WithMaxQueuedTasksPerUser(0))
+	session := userSession("limited", "limited-user")
+
+	_, err := sendSubscribeOn(t, capability, session, "first")
+	require.NoError(t, err)
+	b.expectStarted(t, "first")
+
+	result, err := sendSubscribeOn(t, capability, session, "second")
+	sharedtesting.AssertJSONRPCError(t, result, err, a2aSchema.ErrorCodeTooManyConcurrentTasks)
+
+	// The rejected task took no slot: once the first completes, the next one starts at once
+	close(b.release["first"])
+	require.Eventually(t, func() bool {
+		task, err := sendSubscribeOn(t, capability, session, "second")
+		return err == nil && task.Status.State != a2aSchema.TaskStateQueued
+	}, 5*time.Second, 20*time.Millisecond)
+	b.expectStarted(t, "second")
+	close(b.release["second"])
+}
@@ -0,0 +1,142 @@
+package a2a
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/gate4ai/gate4ai/shared"
+	a2aSchema "github.com/gate4ai/gate4ai/shared/a2a/2025-draft/schema"
+	"go.uber.org/zap"
+)
+
+// DebugMessageLogFlushInterval is how often buffered debug log entries are written to the file.
+const DebugMessageLogFlushInterval = time.Second
+
+// Directions of debug log entries.
+const (
+	DebugDirectionIn  = "in"
+	DebugDirectionOut = "out"
+)
+
+// DebugMessage is one line of the debug message log.
+type DebugMessage struct {
+	Direction string           `json:"direction"`
+	Method    string           `json:"method"`
+	Params    *json.RawMessage `json:"params,omitempty"`
+	Result    interface{}      `json:"result,omitempty"`
+	Error     interface{}      `json:"error,omitempty"`
+	Timestamp time.Time        `json:"timestamp"`
+}
+
+// WithDebugMessageLog appends every request to the capability, its response and the events of
+// tasks/sendSubscribe streams to the file at path as NDJSON DebugMessage lines. Entries are
+// buffered and flushed every DebugMessageLogFlushInterval, after each response, after errors and
+// after the final event of a stream. Meant for development: the log holds full message contents.
+func WithDebugMessageLog(path string) A2AOption {
+	return func(ac *A2ACapability) {
+		debugLog, err := openDebugMessageLog(path, ac.logger)
+		if err != nil {
+			ac.logger.Error("Failed to open debug message log, messages are not logged", zap.String("path", path), zap.Error(err))
+			return
+		}
+		ac.debugLog = debugLog
+	}
+}
+
+// debugMessageLog writes DebugMessage lines through a buffer flushed periodically.
+type debugMessageLog struct {
+	mu     sync.Mutex
+	writer *bufio.Writer
+	logger *zap.Logger
+}
+
+func openDebugMessageLog(path string, logger *zap.Logger) (*debugMessageLog, error) {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return nil, err
+	}
+	l := &debugMessageLog{writer: bufio.NewWriter(file), logger: logger}
+	go l.flushPeriodically()
+	return l, nil
+}
+
+// write appends entry, flushing right away if flush is set.
+func (l *debugMessageLog) write(entry DebugMessage, flush bool) {
+	line, err := json.Marshal(entry)
+	if err != nil {
+		l.logger.Warn("Failed to marshal debug message", zap.String("method", entry.Method), zap.Error(err))
+		return
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.writer.Write(append(line, '\n'))
+	if flush {
+		l.flushLocked()
+	}
+}
+
+func (l *debugMessageLog) flushPeriodically() {
+	ticker := time.NewTicker(DebugMessageLogFlushInterval)
+	defer ticker.Stop()
+	for range ticker.C {
+		l.mu.Lock()
+		l.flushLocked()
+		l.mu.Unlock()
+	}
+}
+
+func (l *debugMessageLog) flushLocked() {
+	if err := l.writer.Flush(); err != nil {
+		l.logger.Warn("Failed to flush debug message log", zap.Error(err))
+	}
+}
+
+// withDebugLog wraps handler to log its request and response while a debug log is configured.
+func (ac *A2ACapability) withDebugLog(method string, handler func(*shared.Message) (interface{}, error)) func(*shared.Message) (interface{}, error) {
+	return func(msg *shared.Message) (interface{}, error) {
+		if ac.debugLog == nil {
+			return handler(msg)
+		}
+		ac.debugLog.write(DebugMessage{Direction: DebugDirectionIn, Method: method, Params: msg.Params, Timestamp: time.Now()}, false)
+		result, err := handler(msg)
+		ac.debugLog.write(DebugMessage{Direction: DebugDirectionOut, Method: method, Result: result, Error: debugError(err), Timestamp: time.Now()}, true)
+		return result, err
+	}
+}
+
+// logStreamEvent logs an event sent on a tasks/sendSubscribe stream.
+func (ac *A2ACapability) logStreamEvent(event *shared.A2AStreamEvent) {
+	if ac.debugLog == nil {
+		return
+	}
+	entry := DebugMessage{Direction: DebugDirectionOut, Method: "tasks/sendSubscribe", Error: debugError(event.Error), Timestamp: time.Now()}
+	switch {
+	case event.Status != nil:
+		entry.Result = event.Status
+	case event.Artifact != nil:
+		entry.Result = event.Artifact
+	case event.History != nil:
+		entry.Result = event.History
+	}
+	ac.debugLog.write(entry, event.Final || event.Error != nil)
+}
+
+// debugError returns err as it is sent to the client: JSON-RPC errors as they are, others as their message.
+func debugError(err error) interface{} {
+	if err == nil {
+		return nil
+	}
+	var sharedErr *shared.JSONRPCError
+	var a2aErr *a2aSchema.JSONRPCError
+	switch {
+	case errors.As(err, &sharedErr):
+		return sharedErr
+	case errors.As(err, &a2aErr):
+		return a2aErr
+	}
+	return map[string]string{"message": err.Error()}
+}
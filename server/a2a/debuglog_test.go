@@ -0,0 +1,55 @@
+package a2a_test
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gate4ai/gate4ai/server/a2a"
+	"github.com/gate4ai/gate4ai/shared"
+	a2aSchema "github.com/gate4ai/gate4ai/shared/a2a/2025-draft/schema"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestDebugMessageLog(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "a2a-messages.ndjson")
+	handler := func(ctx context.Context, task *a2aSchema.Task, updates chan<- a2a.A2AYieldUpdate, logger *zap.Logger) error {
+		updates <- a2a.A2AYieldUpdate{Status: &a2aSchema.TaskStatus{State: a2aSchema.TaskStateWorking}}
+		updates <- a2a.A2AYieldUpdate{Status: &a2aSchema.TaskStatus{State: a2aSchema.TaskStateCompleted}}
+		return nil
+	}
+	server := newA2ATestServer(t, handler, a2a.WithDebugMessageLog(path))
+
+	postA2A(t, server.URL, "tasks/sendSubscribe", a2aSchema.TaskSendParams{
+		ID:      "logged-task",
+		Message: a2aSchema.Message{Role: "user", Parts: []a2aSchema.Part{{Type: shared.PointerTo("text"), Text: shared.PointerTo("hello")}}},
+	})
+	postA2A(t, server.URL, "tasks/get", a2aSchema.TaskQueryParams{ID: "logged-task"})
+
+	file, err := os.Open(path)
+	require.NoError(t, err)
+	defer file.Close()
+	counts := make(map[string]int)
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var entry a2a.DebugMessage
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &entry), "every line must be a JSON object")
+		assert.False(t, entry.Timestamp.IsZero())
+		if entry.Direction == a2a.DebugDirectionIn {
+			require.NotNil(t, entry.Params, "requests are logged with their params")
+		}
+		counts[entry.Direction+" "+entry.Method]++
+	}
+	require.NoError(t, scanner.Err())
+
+	assert.Equal(t, 1, counts["in tasks/sendSubscribe"])
+	// The response and the working and completed status events
+	assert.Equal(t, 3, counts["out tasks/sendSubscribe"])
+	assert.Equal(t, 1, counts["in tasks/get"])
+	assert.Equal(t, 1, counts["out tasks/get"])
+}
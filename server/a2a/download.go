@@ -0,0 +1,136 @@
+package a2a
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"path"
+	"time"
+
+	"github.com/gate4ai/gate4ai/shared"
+	a2aSchema "github.com/gate4ai/gate4ai/shared/a2a/2025-draft/schema"
+)
+
+// Defaults for downloading file parts, see WithFileDownloadEnabled.
+const (
+	DefaultFileDownloadMaxBytes = 10 << 20
+	DefaultFileDownloadTimeout  = 30 * time.Second
+)
+
+// WithFileDownloadEnabled makes tasks/send and tasks/sendSubscribe download the http(s) URI of
+// user file parts and pass the content to the handler as base64 bytes. The server fetches
+// whatever URI a client sends, so enable it only where that is acceptable.
+func WithFileDownloadEnabled(enabled bool) A2AOption {
+	return func(ac *A2ACapability) {
+		ac.fileDownloadEnabled = enabled
+	}
+}
+
+// WithFileDownloadMaxBytes rejects messages with files larger than n bytes.
+func WithFileDownloadMaxBytes(n int64) A2AOption {
+	return func(ac *A2ACapability) {
+		ac.fileDownloadMaxBytes = n
+	}
+}
+
+// WithFileDownloadTimeout limits how long downloading one file may take.
+func WithFileDownloadTimeout(timeout time.Duration) A2AOption {
+	return func(ac *A2ACapability) {
+		ac.fileDownloadTimeout = timeout
+	}
+}
+
+// prepareUserMessage checks the parts of a message sent by a client, which may mix types,
+// and inlines downloadable file parts if enabled.
+func (ac *A2ACapability) prepareUserMessage(ctx context.Context, message *a2aSchema.Message) error {
+	for i, part := range message.Parts {
+		if err := validateMessagePart(part); err != nil {
+			return &shared.JSONRPCError{Code: shared.JSONRPCErrorInvalidParams, Message: fmt.Sprintf("Invalid message part %d: %v", i, err)}
+		}
+	}
+	if !ac.fileDownloadEnabled {
+		return nil
+	}
+	for i := range message.Parts {
+		part := &message.Parts[i]
+		if part.Type == nil || *part.Type != "file" || part.File.URI == nil || part.File.Bytes != nil {
+			continue
+		}
+		if err := ac.inlineFile(ctx, part.File); err != nil {
+			return &shared.JSONRPCError{Code: shared.JSONRPCErrorInvalidParams, Message: fmt.Sprintf("Failed to download file part %d: %v", i, err)}
+		}
+	}
+	return nil
+}
+
+// validateMessagePart checks that a part carries the content its type promises.
+func validateMessagePart(part a2aSchema.Part) error {
+	if part.Type == nil {
+		return nil
+	}
+	switch *part.Type {
+	case "text":
+		if part.Text == nil {
+			return fmt.Errorf("text part has no text")
+		}
+	case "file":
+		if part.File == nil || (part.File.Bytes == nil && part.File.URI == nil) {
+			return fmt.Errorf("file part has neither bytes nor uri")
+		}
+	case "data":
+		if part.Data == nil {
+			return fmt.Errorf("data part has no data")
+		}
+	}
+	return nil
+}
+
+// inlineFile replaces the http(s) URI of file with its downloaded content. Other URIs are kept.
+func (ac *A2ACapability) inlineFile(ctx context.Context, file *a2aSchema.FileContent) error {
+	uri, err := url.Parse(*file.URI)
+	if err != nil || (uri.Scheme != "http" && uri.Scheme != "https") {
+		return nil
+	}
+	ctx, cancel := context.WithTimeout(ctx, ac.fileDownloadTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, uri.String(), nil)
+	if err != nil {
+		return err
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("HTTP %d", resp.StatusCode)
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, ac.fileDownloadMaxBytes+1))
+	if err != nil {
+		return err
+	}
+	if int64(len(data)) > ac.fileDownloadMaxBytes {
+		return fmt.Errorf("file is larger than %d bytes", ac.fileDownloadMaxBytes)
+	}
+
+	if file.MimeType == nil {
+		mimeType, _, err := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+		if err != nil {
+			mimeType, _, _ = mime.ParseMediaType(http.DetectContentType(data))
+		}
+		file.MimeType = &mimeType
+	}
+	if file.Name == nil {
+		if name := path.Base(uri.Path); name != "." && name != "/" {
+			file.Name = &name
+		}
+	}
+	encoded := base64.StdEncoding.EncodeToString(data)
+	file.Bytes = &encoded
+	file.URI = nil // Bytes and URI are mutually exclusive
+	return nil
+}
@@ -0,0 +1,95 @@
+package a2a_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"image"
+	"image/png"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gate4ai/gate4ai/server/a2a"
+	"github.com/gate4ai/gate4ai/server/transport"
+	"github.com/gate4ai/gate4ai/shared"
+	a2aSchema "github.com/gate4ai/gate4ai/shared/a2a/2025-draft/schema"
+	"github.com/gate4ai/gate4ai/shared/config"
+	sharedtesting "github.com/gate4ai/gate4ai/shared/testing"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// newDownloadCapability returns the handlers of a capability with file downloads enabled and a
+// channel receiving the user message its agent handler was given.
+func newDownloadCapability(t *testing.T, options ...a2a.A2AOption) (map[string]func(*shared.Message) (interface{}, error), chan a2aSchema.Message) {
+	received := make(chan a2aSchema.Message, 1)
+	handler := func(ctx context.Context, task *a2aSchema.Task, updates chan<- a2a.A2AYieldUpdate, logger *zap.Logger) error {
+		received <- task.History[len(task.History)-1]
+		updates <- a2a.A2AYieldUpdate{Status: &a2aSchema.TaskStatus{State: a2aSchema.TaskStateCompleted}}
+		return nil
+	}
+	logger := zap.NewNop()
+	manager, err := transport.NewManager(logger, config.NewInternalConfig())
+	require.NoError(t, err)
+	options = append([]a2a.A2AOption{a2a.WithFileDownloadEnabled(true)}, options...)
+	return a2a.NewA2ACapability(logger, manager, a2a.NewInMemoryTaskStore(), handler, options...).GetHandlers(), received
+}
+
+func sendWithFile(handlers map[string]func(*shared.Message) (interface{}, error), uri string) (interface{}, error) {
+	return handlers["tasks/send"](sharedtesting.BuildMessage("tasks/send", a2aSchema.TaskSendParams{
+		ID: "document-task",
+		Message: a2aSchema.Message{Role: "user", Parts: []a2aSchema.Part{
+			{Type: shared.PointerTo("text"), Text: shared.PointerTo("Describe this image")},
+			{Type: shared.PointerTo("file"), File: &a2aSchema.FileContent{URI: &uri}},
+		}},
+	}))
+}
+
+func TestFilePartsAreDownloaded(t *testing.T) {
+	var pngData bytes.Buffer
+	require.NoError(t, png.Encode(&pngData, image.NewRGBA(image.Rect(0, 0, 2, 2))))
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "image/png")
+		w.Write(pngData.Bytes())
+	}))
+	defer server.Close()
+	handlers, received := newDownloadCapability(t)
+
+	result, err := sendWithFile(handlers, server.URL+"/files/chart.png")
+	sharedtesting.AssertJSONRPCSuccess[*a2aSchema.Task](t, result, err)
+
+	message := <-received
+	require.Len(t, message.Parts, 2)
+	assert.Equal(t, "Describe this image", *message.Parts[0].Text)
+	file := message.Parts[1].File
+	require.NotNil(t, file.Bytes, "file part was not inlined")
+	assert.Nil(t, file.URI)
+	assert.Equal(t, "image/png", *file.MimeType)
+	assert.Equal(t, "chart.png", *file.Name)
+	assert.Equal(t, base64.StdEncoding.EncodeToString(pngData.Bytes()), *file.Bytes)
+}
+
+func TestFileDownloadErrors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/missing" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write(make([]byte, 1024))
+	}))
+	defer server.Close()
+	handlers, _ := newDownloadCapability(t, a2a.WithFileDownloadMaxBytes(100))
+
+	result, err := sendWithFile(handlers, server.URL+"/large")
+	sharedtesting.AssertJSONRPCError(t, result, err, shared.JSONRPCErrorInvalidParams)
+	result, err = sendWithFile(handlers, server.URL+"/missing")
+	sharedtesting.AssertJSONRPCError(t, result, err, shared.JSONRPCErrorInvalidParams)
+
+	result, err = handlers["tasks/send"](sharedtesting.BuildMessage("tasks/send", a2aSchema.TaskSendParams{
+		ID:      "malformed-task",
+		Message: a2aSchema.Message{Role: "user", Parts: []a2aSchema.Part{{Type: shared.PointerTo("file"), File: &a2aSchema.FileContent{}}}},
+	}))
+	sharedtesting.AssertJSONRPCError(t, result, err, shared.JSONRPCErrorInvalidParams)
+}
@@ -0,0 +1,336 @@
+package a2a
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"slices"
+	"sync"
+	"time"
+
+	a2aSchema "github.com/gate4ai/gate4ai/shared/a2a/2025-draft/schema"
+)
+
+// TaskEventType is the kind of change a TaskEvent records.
+type TaskEventType string
+
+const (
+	// TaskEventCreated carries the whole task as first saved
+	TaskEventCreated TaskEventType = "created"
+	// TaskEventStatus carries the new TaskStatus
+	TaskEventStatus TaskEventType = "status"
+	// TaskEventArtifact carries an ArtifactEventPayload
+	TaskEventArtifact TaskEventType = "artifact"
+	// TaskEventMessage carries a Message appended to the history
+	TaskEventMessage TaskEventType = "message"
+	// TaskEventReplaced carries the whole task, for changes the other events cannot express
+	TaskEventReplaced TaskEventType = "replaced"
+)
+
+// TaskEvent is one entry of a task's append-only event log. Versions of a task's events
+// start at 1 and have no gaps.
+type TaskEvent struct {
+	ID        string          `json:"id"`
+	TaskID    string          `json:"taskId"`
+	Type      TaskEventType   `json:"type"`
+	Payload   json.RawMessage `json:"payload"`
+	Timestamp time.Time       `json:"timestamp"`
+	Version   int             `json:"version"`
+}
+
+// ArtifactEventPayload sets the artifact at Position of the task's artifacts; Position equal to
+// the number of artifacts appends it.
+type ArtifactEventPayload struct {
+	Position int                `json:"position"`
+	Artifact a2aSchema.Artifact `json:"artifact"`
+}
+
+// DefaultSnapshotInterval is the number of events after which EventSourcedTaskStore snapshots a task.
+const DefaultSnapshotInterval = 50
+
+// EventSourcedStoreOption configures an EventSourcedTaskStore.
+type EventSourcedStoreOption func(*EventSourcedTaskStore)
+
+// WithSnapshotInterval snapshots a task every n events, so loading it replays fewer than n events.
+func WithSnapshotInterval(n int) EventSourcedStoreOption {
+	return func(s *EventSourcedTaskStore) {
+		s.snapshotInterval = n
+	}
+}
+
+// taskSnapshot is the state of a task after the event with Version.
+type taskSnapshot struct {
+	version int
+	task    *a2aSchema.Task
+}
+
+// EventSourcedTaskStore implements TaskStore with an in-memory append-only event log per
+// task. Save records the difference to the stored state as events, and Load rebuilds the
+// task from its latest snapshot and the events after it.
+type EventSourcedTaskStore struct {
+	mu               sync.RWMutex
+	events           map[string][]TaskEvent // taskID -> log, oldest first
+	snapshots        map[string]taskSnapshot
+	snapshotInterval int
+}
+
+var _ TaskStore = (*EventSourcedTaskStore)(nil)
+
+// NewEventSourcedTaskStore creates an empty EventSourcedTaskStore.
+func NewEventSourcedTaskStore(options ...EventSourcedStoreOption) *EventSourcedTaskStore {
+	s := &EventSourcedTaskStore{
+		events:           make(map[string][]TaskEvent),
+		snapshots:        make(map[string]taskSnapshot),
+		snapshotInterval: DefaultSnapshotInterval,
+	}
+	for _, option := range options {
+		option(s)
+	}
+	return s
+}
+
+// Append adds event to the log of its task. The event's Version must follow the last
+// version of the task; ID and Timestamp are set if empty.
+func (s *EventSourcedTaskStore) Append(ctx context.Context, event TaskEvent) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.appendLocked(event)
+}
+
+func (s *EventSourcedTaskStore) appendLocked(event TaskEvent) error {
+	log := s.events[event.TaskID]
+	if want := len(log) + 1; event.Version != want {
+		return fmt.Errorf("version conflict for task %s: event version %d, expected %d", event.TaskID, event.Version, want)
+	}
+	if len(log) == 0 && event.Type != TaskEventCreated && event.Type != TaskEventReplaced {
+		return fmt.Errorf("first event of task %s must carry the whole task, got %q", event.TaskID, event.Type)
+	}
+	if event.ID == "" {
+		event.ID = fmt.Sprintf("%s/%d", event.TaskID, event.Version)
+	}
+	if event.Timestamp.IsZero() {
+		event.Timestamp = time.Now()
+	}
+	s.events[event.TaskID] = append(log, event)
+
+	if s.snapshotInterval > 0 && event.Version%s.snapshotInterval == 0 {
+		task, err := s.replayLocked(event.TaskID)
+		if err != nil {
+			return fmt.Errorf("failed to snapshot task %s: %w", event.TaskID, err)
+		}
+		s.snapshots[event.TaskID] = taskSnapshot{version: event.Version, task: task}
+	}
+	return nil
+}
+
+// LoadEvents returns the events of a task with a version of at least fromVersion.
+func (s *EventSourcedTaskStore) LoadEvents(ctx context.Context, taskID string, fromVersion int) ([]TaskEvent, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	log, ok := s.events[taskID]
+	if !ok {
+		return nil, a2aSchema.NewTaskNotFoundError(taskID)
+	}
+	from := min(max(fromVersion-1, 0), len(log))
+	return append([]TaskEvent(nil), log[from:]...), nil
+}
+
+// Save appends the events turning the stored task into task.
+func (s *EventSourcedTaskStore) Save(ctx context.Context, task *a2aSchema.Task) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var current *a2aSchema.Task
+	if _, ok := s.events[task.ID]; ok {
+		var err error
+		if current, err = s.replayLocked(task.ID); err != nil {
+			return err
+		}
+	}
+	events, err := diffTaskEvents(current, task)
+	if err != nil {
+		return err
+	}
+	version := len(s.events[task.ID])
+	for _, event := range events {
+		version++
+		event.Version = version
+		if err := s.appendLocked(event); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Load rebuilds the task from its latest snapshot and the events after it.
+func (s *EventSourcedTaskStore) Load(ctx context.Context, taskID string) (*a2aSchema.Task, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if _, ok := s.events[taskID]; !ok {
+		return nil, a2aSchema.NewTaskNotFoundError(taskID)
+	}
+	return s.replayLocked(taskID)
+}
+
+// Delete drops the events and snapshot of a task.
+func (s *EventSourcedTaskStore) Delete(ctx context.Context, taskID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.events[taskID]; !ok {
+		return a2aSchema.NewTaskNotFoundError(taskID)
+	}
+	delete(s.events, taskID)
+	delete(s.snapshots, taskID)
+	return nil
+}
+
+// List rebuilds every task and returns those matching filter, oldest status first.
+func (s *EventSourcedTaskStore) List(ctx context.Context, filter TaskFilter) ([]*a2aSchema.Task, string, error) {
+	s.mu.RLock()
+	tasks := make([]*a2aSchema.Task, 0, len(s.events))
+	for taskID := range s.events {
+		task, err := s.replayLocked(taskID)
+		if err != nil {
+			s.mu.RUnlock()
+			return nil, "", err
+		}
+		tasks = append(tasks, task)
+	}
+	s.mu.RUnlock()
+	return listTasks(tasks, filter)
+}
+
+// replayLocked rebuilds a task from its snapshot and log. The caller holds s.mu.
+func (s *EventSourcedTaskStore) replayLocked(taskID string) (*a2aSchema.Task, error) {
+	var task *a2aSchema.Task
+	log := s.events[taskID]
+	if snapshot, ok := s.snapshots[taskID]; ok {
+		task = snapshot.task // Copied by ReplayTaskEvents
+		log = log[snapshot.version:]
+	}
+	return ReplayTaskEvents(task, log)
+}
+
+// ReplayTaskEvents applies events in order to a copy of task, which may be nil if the first
+// event carries the whole task.
+func ReplayTaskEvents(task *a2aSchema.Task, events []TaskEvent) (*a2aSchema.Task, error) {
+	if task != nil {
+		task = copyTask(task)
+	}
+	for _, event := range events {
+		if task == nil && event.Type != TaskEventCreated && event.Type != TaskEventReplaced {
+			return nil, fmt.Errorf("event %s of type %q has no task to apply to", event.ID, event.Type)
+		}
+		var err error
+		switch event.Type {
+		case TaskEventCreated, TaskEventReplaced:
+			task = &a2aSchema.Task{}
+			err = json.Unmarshal(event.Payload, task)
+		case TaskEventStatus:
+			err = json.Unmarshal(event.Payload, &task.Status)
+		case TaskEventMessage:
+			var message a2aSchema.Message
+			if err = json.Unmarshal(event.Payload, &message); err == nil {
+				task.History = append(task.History, message)
+			}
+		case TaskEventArtifact:
+			var payload ArtifactEventPayload
+			if err = json.Unmarshal(event.Payload, &payload); err == nil {
+				switch {
+				case payload.Position < len(task.Artifacts):
+					task.Artifacts[payload.Position] = payload.Artifact
+				case payload.Position == len(task.Artifacts):
+					task.Artifacts = append(task.Artifacts, payload.Artifact)
+				default:
+					err = fmt.Errorf("artifact position %d is past the %d artifacts", payload.Position, len(task.Artifacts))
+				}
+			}
+		default:
+			err = fmt.Errorf("unknown event type %q", event.Type)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to apply event %s: %w", event.ID, err)
+		}
+	}
+	return task, nil
+}
+
+// diffTaskEvents returns the events turning current (nil for a new task) into task. Changes
+// besides new messages, artifacts and status are recorded as one TaskEventReplaced.
+func diffTaskEvents(current, task *a2aSchema.Task) ([]TaskEvent, error) {
+	if current == nil {
+		event, err := newTaskEvent(task.ID, TaskEventCreated, task)
+		return []TaskEvent{event}, err
+	}
+
+	var events []TaskEvent
+	add := func(eventType TaskEventType, payload interface{}) error {
+		event, err := newTaskEvent(task.ID, eventType, payload)
+		events = append(events, event)
+		return err
+	}
+	if len(task.History) >= len(current.History) && len(task.Artifacts) >= len(current.Artifacts) {
+		for _, message := range task.History[len(current.History):] {
+			if err := add(TaskEventMessage, message); err != nil {
+				return nil, err
+			}
+		}
+		for i, artifact := range task.Artifacts {
+			if i < len(current.Artifacts) && reflect.DeepEqual(current.Artifacts[i], artifact) {
+				continue
+			}
+			if err := add(TaskEventArtifact, ArtifactEventPayload{Position: i, Artifact: artifact}); err != nil {
+				return nil, err
+			}
+		}
+		if !reflect.DeepEqual(current.Status, task.Status) {
+			if err := add(TaskEventStatus, task.Status); err != nil {
+				return nil, err
+			}
+		}
+		// Keep the incremental events only if they reproduce task exactly
+		if replayed, err := ReplayTaskEvents(current, events); err == nil && sameTaskJSON(replayed, task) {
+			return events, nil
+		}
+	}
+	event, err := newTaskEvent(task.ID, TaskEventReplaced, task)
+	return []TaskEvent{event}, err
+}
+
+func newTaskEvent(taskID string, eventType TaskEventType, payload interface{}) (TaskEvent, error) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return TaskEvent{}, fmt.Errorf("failed to marshal %s event of task %s: %w", eventType, taskID, err)
+	}
+	return TaskEvent{TaskID: taskID, Type: eventType, Payload: data}, nil
+}
+
+// sameTaskJSON reports whether a and b serialize identically.
+func sameTaskJSON(a, b *a2aSchema.Task) bool {
+	dataA, errA := json.Marshal(a)
+	dataB, errB := json.Marshal(b)
+	return errA == nil && errB == nil && bytes.Equal(dataA, dataB)
+}
+
+// copyTask returns a copy of task sharing no memory with it.
+func copyTask(task *a2aSchema.Task) *a2aSchema.Task {
+	copied := *task
+	if task.Artifacts != nil {
+		copied.Artifacts = make([]a2aSchema.Artifact, len(task.Artifacts))
+		for i, artifact := range task.Artifacts {
+			copied.Artifacts[i] = deepCopyArtifact(artifact)
+		}
+	}
+	if task.History != nil {
+		copied.History = make([]a2aSchema.Message, len(task.History))
+		for i := range task.History {
+			copied.History[i] = *deepCopyMessage(&task.History[i])
+		}
+	}
+	copied.Status.Message = deepCopyMessage(task.Status.Message)
+	copied.Metadata = copyMapPointer(task.Metadata)
+	copied.Timeline = slices.Clone(task.Timeline)
+	copied.ScheduledAt = copyPointer(task.ScheduledAt)
+	return &copied
+}
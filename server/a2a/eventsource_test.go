@@ -0,0 +1,113 @@
+package a2a_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/gate4ai/gate4ai/server/a2a"
+	a2aSchema "github.com/gate4ai/gate4ai/shared/a2a/2025-draft/schema"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// taskHistory returns the 21 states a task goes through while an agent yields 20 updates.
+func taskHistory() []*a2aSchema.Task {
+	base := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	task := &a2aSchema.Task{
+		ID:        "sourced",
+		SessionID: "session",
+		Status:    a2aSchema.TaskStatus{State: a2aSchema.TaskStateSubmitted, Timestamp: base},
+		History:   []a2aSchema.Message{{Role: "user", Parts: textParts("start")}},
+		Metadata:  &map[string]interface{}{"origin": "test"},
+	}
+	states := []*a2aSchema.Task{task}
+	for i := 1; i < 20; i++ {
+		next := *states[len(states)-1]
+		next.Artifacts = append([]a2aSchema.Artifact(nil), next.Artifacts...)
+		next.History = append([]a2aSchema.Message(nil), next.History...)
+		timestamp := base.Add(time.Duration(i) * time.Second)
+		switch i % 4 {
+		case 0: // Status with an agent message, as applyUpdateToTask records it
+			message := a2aSchema.Message{Role: "agent", Parts: textParts(fmt.Sprintf("step %d", i))}
+			next.Status = a2aSchema.TaskStatus{State: a2aSchema.TaskStateWorking, Message: &message, Timestamp: timestamp}
+			next.History = append(next.History, message)
+		case 1: // New artifact
+			next.Artifacts = append(next.Artifacts, a2aSchema.Artifact{Index: len(next.Artifacts), Parts: textParts(fmt.Sprint(i))})
+			next.Status.Timestamp = timestamp
+		case 2: // Appended artifact chunk
+			last := &next.Artifacts[len(next.Artifacts)-1]
+			last.Parts = append(append([]a2aSchema.Part(nil), last.Parts...), textParts(fmt.Sprint(i))...)
+			next.Status.Timestamp = timestamp
+		case 3: // Metadata change
+			next.Metadata = &map[string]interface{}{"origin": "test", "step": fmt.Sprint(i)}
+		}
+		states = append(states, &next)
+	}
+	final := *states[len(states)-1]
+	final.Status = a2aSchema.TaskStatus{State: a2aSchema.TaskStateCompleted, Timestamp: base.Add(time.Minute)}
+	return append(states, &final)
+}
+
+func TestEventSourcedTaskStoreMatchesDirectSave(t *testing.T) {
+	ctx := context.Background()
+	direct := a2a.NewInMemoryTaskStore()
+	sourced := a2a.NewEventSourcedTaskStore(a2a.WithSnapshotInterval(5))
+	for _, task := range taskHistory() {
+		require.NoError(t, direct.Save(ctx, task))
+		require.NoError(t, sourced.Save(ctx, task))
+	}
+
+	want, err := direct.Load(ctx, "sourced")
+	require.NoError(t, err)
+	got, err := sourced.Load(ctx, "sourced")
+	require.NoError(t, err)
+	assert.Equal(t, want, got)
+
+	events, err := sourced.LoadEvents(ctx, "sourced", 1)
+	require.NoError(t, err)
+	assert.Equal(t, a2a.TaskEventCreated, events[0].Type)
+	types := make(map[a2a.TaskEventType]bool)
+	for i, event := range events {
+		assert.Equal(t, i+1, event.Version)
+		types[event.Type] = true
+	}
+	// Metadata changes cannot be expressed incrementally
+	for _, eventType := range []a2a.TaskEventType{a2a.TaskEventStatus, a2a.TaskEventArtifact, a2a.TaskEventMessage, a2a.TaskEventReplaced} {
+		assert.True(t, types[eventType], "no %s event recorded", eventType)
+	}
+
+	// Replaying the whole log without snapshots gives the same task
+	replayed, err := a2a.ReplayTaskEvents(nil, events)
+	require.NoError(t, err)
+	assert.Equal(t, want, replayed)
+
+	tasks, _, err := sourced.List(ctx, a2a.TaskFilter{States: []a2aSchema.TaskState{a2aSchema.TaskStateCompleted}})
+	require.NoError(t, err)
+	require.Len(t, tasks, 1)
+	assert.Equal(t, want, tasks[0])
+}
+
+func TestEventSourcedTaskStoreAppend(t *testing.T) {
+	ctx := context.Background()
+	store := a2a.NewEventSourcedTaskStore()
+	require.Error(t, store.Append(ctx, a2a.TaskEvent{TaskID: "t", Type: a2a.TaskEventStatus, Payload: []byte(`{"state":"working"}`), Version: 1}),
+		"a log must start with the whole task")
+	require.NoError(t, store.Append(ctx, a2a.TaskEvent{TaskID: "t", Type: a2a.TaskEventCreated, Payload: []byte(`{"id":"t","status":{"state":"submitted"}}`), Version: 1}))
+	require.Error(t, store.Append(ctx, a2a.TaskEvent{TaskID: "t", Type: a2a.TaskEventStatus, Payload: []byte(`{"state":"working"}`), Version: 1}),
+		"a version already taken must conflict")
+	require.NoError(t, store.Append(ctx, a2a.TaskEvent{TaskID: "t", Type: a2a.TaskEventStatus, Payload: []byte(`{"state":"working"}`), Version: 2}))
+
+	task, err := store.Load(ctx, "t")
+	require.NoError(t, err)
+	assert.Equal(t, a2aSchema.TaskStateWorking, task.Status.State)
+	events, err := store.LoadEvents(ctx, "t", 2)
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+	assert.Equal(t, "t/2", events[0].ID)
+
+	require.NoError(t, store.Delete(ctx, "t"))
+	_, err = store.LoadEvents(ctx, "t", 1)
+	require.Error(t, err)
+}
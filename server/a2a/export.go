@@ -0,0 +1,142 @@
+package a2a
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/gate4ai/gate4ai/server/mcp/capability"
+	"github.com/gate4ai/gate4ai/shared"
+	a2aSchema "github.com/gate4ai/gate4ai/shared/a2a/2025-draft/schema"
+	mcpSchema "github.com/gate4ai/gate4ai/shared/mcp/2025/schema"
+	"go.uber.org/zap"
+)
+
+// WithAutoExportCompletedTasks exports every task that completes with artifacts
+// to resourcesCapability (see ExportTaskAsResource).
+func WithAutoExportCompletedTasks(resourcesCapability *capability.ResourcesCapability) A2AOption {
+	return func(ac *A2ACapability) {
+		ac.autoExportResources = resourcesCapability
+	}
+}
+
+// TaskResultResourceURI is the MCP resource URI a task's result is exported to.
+func TaskResultResourceURI(taskID string) string {
+	return "a2a://task/" + taskID + "/result"
+}
+
+// ExportTaskAsResource creates or updates the MCP resource a2a://task/<id>/result holding
+// the task's final artifact, so MCP clients can fetch it with resources/read.
+// Text, data and text-like file parts become text contents; other file parts become blobs.
+func (ac *A2ACapability) ExportTaskAsResource(taskID string, resourcesCapability *capability.ResourcesCapability) error {
+	if resourcesCapability == nil {
+		return fmt.Errorf("resources capability is required to export task '%s'", taskID)
+	}
+	task, err := ac.loadTask(context.Background(), taskID)
+	if err != nil {
+		return fmt.Errorf("failed to load task '%s': %w", taskID, err)
+	}
+	if len(task.Artifacts) == 0 {
+		return fmt.Errorf("task '%s' has no artifacts to export", taskID)
+	}
+	artifact := task.Artifacts[len(task.Artifacts)-1]
+
+	uri := TaskResultResourceURI(taskID)
+	contents, err := artifactToResourceContents(uri, artifact)
+	if err != nil {
+		return fmt.Errorf("failed to export task '%s': %w", taskID, err)
+	}
+	name := "A2A task " + taskID + " result"
+	if artifact.Name != nil {
+		name = *artifact.Name
+	}
+	description := ""
+	if artifact.Description != nil {
+		description = *artifact.Description
+	}
+	handler := func(msg *shared.Message) (mcpSchema.Meta, []mcpSchema.ResourceContent, error) {
+		return nil, contents, nil
+	}
+
+	// Update first so re-exports keep existing subscriptions
+	if err := resourcesCapability.UpdateResource(uri, name, description, contents[0].MimeType, handler); err != nil {
+		if err := resourcesCapability.AddResource(uri, name, description, contents[0].MimeType, nil, handler); err != nil {
+			return fmt.Errorf("failed to export task '%s': %w", taskID, err)
+		}
+	}
+	ac.exportsMu.Lock()
+	ac.exports[taskID] = resourcesCapability
+	ac.exportsMu.Unlock()
+	ac.logger.Debug("Exported task as resource", zap.String("taskID", taskID), zap.String("uri", uri))
+	return nil
+}
+
+// DeleteTask removes the task from the store together with its exported resource and
+// replay buffer, if any.
+func (ac *A2ACapability) DeleteTask(ctx context.Context, taskID string) error {
+	if err := ac.taskStore.Delete(ctx, taskID); err != nil {
+		return err
+	}
+	ac.removeReplayBuffer(taskID)
+	ac.removePushConfig(taskID)
+	ac.exportsMu.Lock()
+	resourcesCapability, exported := ac.exports[taskID]
+	delete(ac.exports, taskID)
+	ac.exportsMu.Unlock()
+	if exported {
+		if err := resourcesCapability.DeleteResource(TaskResultResourceURI(taskID)); err != nil {
+			ac.logger.Warn("Failed to remove exported task resource", zap.String("taskID", taskID), zap.Error(err))
+		}
+	}
+	return nil
+}
+
+// autoExport exports completed tasks when WithAutoExportCompletedTasks is set.
+func (ac *A2ACapability) autoExport(task *a2aSchema.Task) {
+	if ac.autoExportResources == nil || task.Status.State != a2aSchema.TaskStateCompleted || len(task.Artifacts) == 0 {
+		return
+	}
+	if err := ac.ExportTaskAsResource(task.ID, ac.autoExportResources); err != nil {
+		ac.logger.Error("Failed to auto-export completed task", zap.String("taskID", task.ID), zap.Error(err))
+	}
+}
+
+func artifactToResourceContents(uri string, artifact a2aSchema.Artifact) ([]mcpSchema.ResourceContent, error) {
+	contents := make([]mcpSchema.ResourceContent, 0, len(artifact.Parts))
+	for _, part := range artifact.Parts {
+		switch {
+		case part.Text != nil:
+			contents = append(contents, mcpSchema.ResourceContent{URI: uri, MimeType: "text/plain", Text: part.Text})
+		case part.Data != nil:
+			data, err := json.Marshal(*part.Data)
+			if err != nil {
+				return nil, fmt.Errorf("failed to marshal data part: %w", err)
+			}
+			contents = append(contents, mcpSchema.ResourceContent{URI: uri, MimeType: "application/json", Text: shared.PointerTo(string(data))})
+		case part.File != nil && part.File.Bytes != nil:
+			mimeType := "application/octet-stream"
+			if part.File.MimeType != nil {
+				mimeType = *part.File.MimeType
+			}
+			if !isTextMimeType(mimeType) {
+				contents = append(contents, mcpSchema.ResourceContent{URI: uri, MimeType: mimeType, Blob: part.File.Bytes})
+				continue
+			}
+			text, err := base64.StdEncoding.DecodeString(*part.File.Bytes)
+			if err != nil {
+				return nil, fmt.Errorf("file part bytes are not base64: %w", err)
+			}
+			contents = append(contents, mcpSchema.ResourceContent{URI: uri, MimeType: mimeType, Text: shared.PointerTo(string(text))})
+		}
+	}
+	if len(contents) == 0 {
+		return nil, fmt.Errorf("artifact %d has no exportable content", artifact.Index)
+	}
+	return contents, nil
+}
+
+func isTextMimeType(mimeType string) bool {
+	return strings.HasPrefix(mimeType, "text/") || mimeType == "application/json" || strings.HasSuffix(mimeType, "+json")
+}
@@ -0,0 +1,59 @@
+package a2a_test
+
+import (
+	"context"
+	"encoding/base64"
+	"testing"
+
+	"github.com/gate4ai/gate4ai/server/a2a"
+	mcpCapability "github.com/gate4ai/gate4ai/server/mcp/capability"
+	"github.com/gate4ai/gate4ai/server/transport"
+	"github.com/gate4ai/gate4ai/shared"
+	a2aSchema "github.com/gate4ai/gate4ai/shared/a2a/2025-draft/schema"
+	"github.com/gate4ai/gate4ai/shared/config"
+	mcpSchema "github.com/gate4ai/gate4ai/shared/mcp/2025/schema"
+	sharedtesting "github.com/gate4ai/gate4ai/shared/testing"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestAutoExportCompletedTaskAsResource(t *testing.T) {
+	logger := zap.NewNop()
+	manager, err := transport.NewManager(logger, config.NewInternalConfig())
+	require.NoError(t, err)
+	resources := mcpCapability.NewResourcesCapability(manager, logger)
+	png := base64.StdEncoding.EncodeToString([]byte{0x89, 'P', 'N', 'G'})
+
+	handler := func(ctx context.Context, task *a2aSchema.Task, updates chan<- a2a.A2AYieldUpdate, logger *zap.Logger) error {
+		updates <- a2a.A2AYieldUpdate{Artifact: &a2aSchema.Artifact{Parts: []a2aSchema.Part{
+			{Type: shared.PointerTo("text"), Text: shared.PointerTo("The answer is 42")},
+			{Type: shared.PointerTo("file"), File: &a2aSchema.FileContent{MimeType: shared.PointerTo("image/png"), Bytes: &png}},
+		}}}
+		updates <- a2a.A2AYieldUpdate{Status: &a2aSchema.TaskStatus{State: a2aSchema.TaskStateCompleted}}
+		return nil
+	}
+	capability := a2a.NewA2ACapability(logger, manager, a2a.NewInMemoryTaskStore(), handler, a2a.WithAutoExportCompletedTasks(resources))
+	result, err := capability.GetHandlers()["tasks/send"](sharedtesting.BuildMessage("tasks/send", a2aSchema.TaskSendParams{
+		ID:      "export-task",
+		Message: a2aSchema.Message{Role: "user", Parts: []a2aSchema.Part{{Type: shared.PointerTo("text"), Text: shared.PointerTo("question")}}},
+	}))
+	sharedtesting.AssertJSONRPCSuccess[*a2aSchema.Task](t, result, err)
+
+	readResource := func() (interface{}, error) {
+		return resources.GetHandlers()["resources/read"](sharedtesting.BuildMessage("resources/read", mcpSchema.ReadResourceRequestParams{URI: a2a.TaskResultResourceURI("export-task")}))
+	}
+	result, err = readResource()
+	contents := sharedtesting.AssertJSONRPCSuccess[mcpSchema.ReadResourceResult](t, result, err).Contents
+	require.Len(t, contents, 2)
+	assert.Equal(t, "a2a://task/export-task/result", contents[0].URI)
+	require.NotNil(t, contents[0].Text)
+	assert.Equal(t, "The answer is 42", *contents[0].Text)
+	assert.Equal(t, "image/png", contents[1].MimeType)
+	require.NotNil(t, contents[1].Blob)
+	assert.Equal(t, png, *contents[1].Blob)
+
+	require.NoError(t, capability.DeleteTask(context.Background(), "export-task"))
+	result, err = readResource()
+	sharedtesting.AssertJSONRPCError(t, result, err, shared.JSONRPCErrorServerError)
+}
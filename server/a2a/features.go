@@ -0,0 +1,40 @@
+package a2a
+
+import (
+	"github.com/gate4ai/gate4ai/server/transport"
+	"github.com/gate4ai/gate4ai/shared"
+	a2aSchema "github.com/gate4ai/gate4ai/shared/a2a/2025-draft/schema"
+	"github.com/gate4ai/gate4ai/shared/config"
+	"go.uber.org/zap"
+)
+
+// WithFeatureFlags checks the feature flags in cfg for the session's user before optional
+// behaviors. Currently config.FeatureA2AStreaming gates tasks/sendSubscribe and
+// tasks/resubscribe. Flags that are not configured leave the behavior enabled.
+func WithFeatureFlags(cfg config.IConfig) A2AOption {
+	return func(ac *A2ACapability) {
+		ac.featureFlags = cfg
+	}
+}
+
+// featureEnabled reports whether flag name is enabled for the user of msg's session.
+func (ac *A2ACapability) featureEnabled(msg *shared.Message, name string, logger *zap.Logger) bool {
+	if ac.featureFlags == nil {
+		return true
+	}
+	userID := transport.GetUserId(msg.Session.GetParams())
+	enabled, err := config.FeatureEnabled(ac.featureFlags, name, userID, true)
+	if err != nil {
+		logger.Warn("Failed to read feature flag, using default", zap.String("flag", name), zap.Error(err))
+	}
+	return enabled
+}
+
+// requireStreaming returns an error if streaming is disabled for the user of msg's session.
+func (ac *A2ACapability) requireStreaming(msg *shared.Message, method string, logger *zap.Logger) error {
+	if ac.featureEnabled(msg, config.FeatureA2AStreaming, logger) {
+		return nil
+	}
+	logger.Info("Streaming disabled by feature flag")
+	return shared.NewJSONRPCError(a2aSchema.NewUnsupportedOperationError(method))
+}
@@ -0,0 +1,50 @@
+package a2a_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/gate4ai/gate4ai/server/a2a"
+	"github.com/gate4ai/gate4ai/server/transport"
+	"github.com/gate4ai/gate4ai/shared"
+	a2aSchema "github.com/gate4ai/gate4ai/shared/a2a/2025-draft/schema"
+	"github.com/gate4ai/gate4ai/shared/config"
+	sharedtesting "github.com/gate4ai/gate4ai/shared/testing"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestStreamingFeatureFlagUserOverride(t *testing.T) {
+	logger := zap.NewNop()
+	cfg := config.NewInternalConfig()
+	cfg.SetFeatureFlag(config.FeatureA2AStreaming, true)
+	require.NoError(t, cfg.SetUserFeatureFlag(config.FeatureA2AStreaming, "blocked-user", false))
+
+	manager, err := transport.NewManager(logger, cfg)
+	require.NoError(t, err)
+	noop := func(ctx context.Context, task *a2aSchema.Task, updates chan<- a2a.A2AYieldUpdate, logger *zap.Logger) error {
+		return nil
+	}
+	handlers := a2a.NewA2ACapability(logger, manager, a2a.NewInMemoryTaskStore(), noop, a2a.WithFeatureFlags(cfg)).GetHandlers()
+
+	resubscribe := func(userID string) (interface{}, error) {
+		msg := sharedtesting.BuildMessage("tasks/resubscribe", a2aSchema.TaskQueryParams{ID: "missing-task"})
+		transport.SaveUserId(msg.Session.GetParams(), userID)
+		return handlers["tasks/resubscribe"](msg)
+	}
+
+	// Enabled globally: the request gets past the flag and fails on the unknown task
+	result, err := resubscribe("other-user")
+	sharedtesting.AssertJSONRPCError(t, result, err, a2aSchema.ErrorCodeTaskNotFound)
+
+	result, err = resubscribe("blocked-user")
+	sharedtesting.AssertJSONRPCError(t, result, err, a2aSchema.ErrorCodeUnsupportedOperation)
+
+	msg := sharedtesting.BuildMessage("tasks/sendSubscribe", a2aSchema.TaskSendParams{
+		ID:      "flagged-task",
+		Message: a2aSchema.Message{Role: "user", Parts: []a2aSchema.Part{{Type: shared.PointerTo("text"), Text: shared.PointerTo("hi")}}},
+	})
+	transport.SaveUserId(msg.Session.GetParams(), "blocked-user")
+	result, err = handlers["tasks/sendSubscribe"](msg)
+	sharedtesting.AssertJSONRPCError(t, result, err, a2aSchema.ErrorCodeUnsupportedOperation)
+}
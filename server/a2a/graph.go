@@ -0,0 +1,121 @@
+package a2a
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	a2aSchema "github.com/gate4ai/gate4ai/shared/a2a/2025-draft/schema"
+	"go.uber.org/zap"
+)
+
+// ParentTaskIDMetadataKey is the task metadata key that links a subtask to the task that spawned it.
+// It is set by the client in the tasks/send metadata of the subtask.
+const ParentTaskIDMetadataKey = "parentTaskId"
+
+// TaskGraphContentType is the Content-Type of the task graph.
+const TaskGraphContentType = "text/vnd.graphviz"
+
+// taskStateColors are the DOT fill colors of the task states.
+var taskStateColors = map[a2aSchema.TaskState]string{
+	a2aSchema.TaskStateSubmitted:     "lightgray",
+	a2aSchema.TaskStateQueued:        "lavender",
+	a2aSchema.TaskStateWorking:       "lightblue",
+	a2aSchema.TaskStateInputRequired: "gold",
+	a2aSchema.TaskStateCompleted:     "palegreen",
+	a2aSchema.TaskStateCanceled:      "gray",
+	a2aSchema.TaskStateFailed:        "salmon",
+}
+
+// ParentTaskID returns the ID of the task's parent, or "" for a top-level task.
+func ParentTaskID(task *a2aSchema.Task) string {
+	if task == nil || task.Metadata == nil {
+		return ""
+	}
+	parentID, _ := (*task.Metadata)[ParentTaskIDMetadataKey].(string)
+	return parentID
+}
+
+// TaskGraphHandler serves GET ?rootTaskID=<id> with a GraphViz DOT graph of the task and all
+// its descendants: one node per task labelled and colored by state, and parent -> child edges.
+func (ac *A2ACapability) TaskGraphHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			http.Error(w, "Method Not Allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		rootTaskID := r.URL.Query().Get("rootTaskID")
+		if rootTaskID == "" {
+			http.Error(w, "rootTaskID is required", http.StatusBadRequest)
+			return
+		}
+		logger := ac.logger.With(zap.String("rootTaskID", rootTaskID))
+
+		graph, err := ac.renderTaskGraph(r.Context(), rootTaskID)
+		if err != nil {
+			var rpcErr *a2aSchema.JSONRPCError
+			if errors.As(err, &rpcErr) && rpcErr.Code == a2aSchema.ErrorCodeTaskNotFound {
+				http.Error(w, fmt.Sprintf("Task not found: %s", rootTaskID), http.StatusNotFound)
+				return
+			}
+			logger.Error("Failed to render task graph", zap.Error(err))
+			http.Error(w, "Failed to render task graph", http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", TaskGraphContentType)
+		if _, err := w.Write([]byte(graph)); err != nil {
+			logger.Warn("Failed to write task graph", zap.Error(err))
+		}
+	}
+}
+
+// renderTaskGraph walks the task tree breadth first from rootTaskID and renders it as DOT.
+func (ac *A2ACapability) renderTaskGraph(ctx context.Context, rootTaskID string) (string, error) {
+	root, err := ac.loadTask(ctx, rootTaskID)
+	if err != nil {
+		return "", err
+	}
+
+	var b strings.Builder
+	b.WriteString("digraph tasks {\n")
+	b.WriteString("  node [shape=box, style=filled];\n")
+	var edges []string
+	visited := map[string]bool{root.ID: true}
+	queue := []*a2aSchema.Task{root}
+	for len(queue) > 0 {
+		task := queue[0]
+		queue = queue[1:]
+		color, ok := taskStateColors[task.Status.State]
+		if !ok {
+			color = "white"
+		}
+		fmt.Fprintf(&b, "  %s [label=\"%s\\n%s\", fillcolor=%s];\n", dotQuote(task.ID), dotEscape(task.ID), dotEscape(string(task.Status.State)), color)
+
+		children, _, err := ac.taskStore.List(ctx, TaskFilter{ParentTaskID: task.ID})
+		if err != nil {
+			return "", fmt.Errorf("failed to list subtasks of %s: %w", task.ID, err)
+		}
+		for _, child := range children {
+			edges = append(edges, fmt.Sprintf("  %s -> %s;\n", dotQuote(task.ID), dotQuote(child.ID)))
+			if !visited[child.ID] { // Guards against metadata cycles
+				visited[child.ID] = true
+				queue = append(queue, child)
+			}
+		}
+	}
+	for _, edge := range edges {
+		b.WriteString(edge)
+	}
+	b.WriteString("}\n")
+	return b.String(), nil
+}
+
+func dotQuote(s string) string {
+	return `"` + dotEscape(s) + `"`
+}
+
+func dotEscape(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(s)
+}
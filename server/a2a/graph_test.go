@@ -0,0 +1,89 @@
+package a2a_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"testing"
+
+	"github.com/gate4ai/gate4ai/server/a2a"
+	"github.com/gate4ai/gate4ai/server/transport"
+	"github.com/gate4ai/gate4ai/shared"
+	a2aSchema "github.com/gate4ai/gate4ai/shared/a2a/2025-draft/schema"
+	"github.com/gate4ai/gate4ai/shared/config"
+	sharedtesting "github.com/gate4ai/gate4ai/shared/testing"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+var (
+	dotNodePattern = regexp.MustCompile(`"([^"]+)" \[label="[^"\\]+\\n([a-z-]+)"`)
+	dotEdgePattern = regexp.MustCompile(`"([^"]+)" -> "([^"]+)";`)
+)
+
+func TestTaskGraphRendersSubtasks(t *testing.T) {
+	logger := zap.NewNop()
+	manager, err := transport.NewManager(logger, config.NewInternalConfig())
+	require.NoError(t, err)
+
+	// Tasks asked to "fail" fail, all others complete
+	handler := func(ctx context.Context, task *a2aSchema.Task, updates chan<- a2a.A2AYieldUpdate, logger *zap.Logger) error {
+		state := a2aSchema.TaskStateCompleted
+		if text := task.History[len(task.History)-1].Parts[0].Text; text != nil && *text == "fail" {
+			state = a2aSchema.TaskStateFailed
+		}
+		updates <- a2a.A2AYieldUpdate{Status: &a2aSchema.TaskStatus{State: state}}
+		return nil
+	}
+	capability := a2a.NewA2ACapability(logger, manager, a2a.NewInMemoryTaskStore(), handler)
+	send := func(taskID string, parentID string, text string) {
+		params := a2aSchema.TaskSendParams{
+			ID:      taskID,
+			Message: a2aSchema.Message{Role: "user", Parts: []a2aSchema.Part{{Type: shared.PointerTo("text"), Text: shared.PointerTo(text)}}},
+		}
+		if parentID != "" {
+			params.Metadata = &map[string]interface{}{a2a.ParentTaskIDMetadataKey: parentID}
+		}
+		result, err := capability.GetHandlers()["tasks/send"](sharedtesting.BuildMessage("tasks/send", params))
+		sharedtesting.AssertJSONRPCSuccess[*a2aSchema.Task](t, result, err)
+	}
+	send("parent", "", "plan")
+	send("child-ok", "parent", "work")
+	send("child-failed", "parent", "fail")
+	send("unrelated", "", "other")
+
+	server := httptest.NewServer(capability.TaskGraphHandler())
+	defer server.Close()
+
+	resp, err := http.Get(server.URL + "/tasks/graph?rootTaskID=parent")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, a2a.TaskGraphContentType, resp.Header.Get("Content-Type"))
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+
+	states := make(map[string]string)
+	for _, match := range dotNodePattern.FindAllStringSubmatch(string(body), -1) {
+		states[match[1]] = match[2]
+	}
+	assert.Equal(t, map[string]string{
+		"parent":       "completed",
+		"child-ok":     "completed",
+		"child-failed": "failed",
+	}, states, "DOT output:\n%s", body)
+
+	var edges [][2]string
+	for _, match := range dotEdgePattern.FindAllStringSubmatch(string(body), -1) {
+		edges = append(edges, [2]string{match[1], match[2]})
+	}
+	assert.ElementsMatch(t, [][2]string{{"parent", "child-ok"}, {"parent", "child-failed"}}, edges, "DOT output:\n%s", body)
+
+	resp, err = http.Get(server.URL + "/tasks/graph?rootTaskID=missing")
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+}
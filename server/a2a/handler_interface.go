@@ -18,6 +18,12 @@ type A2AYieldUpdate struct {
 	Error *a2aSchema.JSONRPCError `json:"error,omitempty"`
 }
 
+// A2AYieldError returns an update that fails the task with a JSON-RPC error. tasks/send returns
+// the error to the caller; tasks/sendSubscribe streams it as a final error event.
+func A2AYieldError(code int, msg string) A2AYieldUpdate {
+	return A2AYieldUpdate{Error: &a2aSchema.JSONRPCError{Code: code, Message: msg}}
+}
+
 // A2AHandler defines the function signature for the core logic implementation of an A2A agent.
 // This function is responsible for the "business logic" of handling a task.
 //
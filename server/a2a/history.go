@@ -0,0 +1,25 @@
+package a2a
+
+import (
+	a2aSchema "github.com/gate4ai/gate4ai/shared/a2a/2025-draft/schema"
+)
+
+// WithMaxHistoryLength keeps at most the last n messages of each task's history in the
+// store; older ones are dropped when a message is added. n <= 0 keeps the whole history.
+// This is independent of the historyLength a client requests, which only trims responses.
+func WithMaxHistoryLength(n int) A2AOption {
+	return func(ac *A2ACapability) {
+		ac.maxHistoryLength = n
+	}
+}
+
+// pruneHistory returns the last maxHistoryLength messages of history. A pruned history is
+// copied, so the dropped messages are not kept alive by the backing array.
+func (ac *A2ACapability) pruneHistory(history []a2aSchema.Message) []a2aSchema.Message {
+	if ac.maxHistoryLength <= 0 || len(history) <= ac.maxHistoryLength {
+		return history
+	}
+	pruned := make([]a2aSchema.Message, ac.maxHistoryLength)
+	copy(pruned, history[len(history)-ac.maxHistoryLength:])
+	return pruned
+}
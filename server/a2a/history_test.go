@@ -0,0 +1,92 @@
+package a2a_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/gate4ai/gate4ai/server/a2a"
+	"github.com/gate4ai/gate4ai/server/transport"
+	"github.com/gate4ai/gate4ai/shared"
+	a2aSchema "github.com/gate4ai/gate4ai/shared/a2a/2025-draft/schema"
+	"github.com/gate4ai/gate4ai/shared/config"
+	sharedtesting "github.com/gate4ai/gate4ai/shared/testing"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// echoHandler completes the task with an agent message repeating the last user message.
+func echoHandler(ctx context.Context, task *a2aSchema.Task, updates chan<- a2a.A2AYieldUpdate, logger *zap.Logger) error {
+	last := task.History[len(task.History)-1]
+	updates <- a2a.A2AYieldUpdate{Status: &a2aSchema.TaskStatus{
+		State:   a2aSchema.TaskStateCompleted,
+		Message: &a2aSchema.Message{Role: "agent", Parts: textParts("echo " + *last.Parts[0].Text)},
+	}}
+	return nil
+}
+
+func historyTexts(history []a2aSchema.Message) []string {
+	texts := make([]string, 0, len(history))
+	for _, message := range history {
+		texts = append(texts, message.Role+": "+*message.Parts[0].Text)
+	}
+	return texts
+}
+
+func TestMaxHistoryLengthPrunesStoredHistory(t *testing.T) {
+	manager, err := transport.NewManager(zap.NewNop(), config.NewInternalConfig())
+	require.NoError(t, err)
+	store := a2a.NewInMemoryTaskStore()
+	capability := a2a.NewA2ACapability(zap.NewNop(), manager, store, echoHandler, a2a.WithMaxHistoryLength(3))
+	send := func(text string, historyLength *int) *a2aSchema.Task {
+		t.Helper()
+		result, err := capability.GetHandlers()["tasks/send"](sharedtesting.BuildMessage("tasks/send", a2aSchema.TaskSendParams{
+			ID:            "pruned-task",
+			Message:       a2aSchema.Message{Role: "user", Parts: textParts(text)},
+			HistoryLength: historyLength,
+		}))
+		return sharedtesting.AssertJSONRPCSuccess[*a2aSchema.Task](t, result, err)
+	}
+
+	// A new task starts with an empty history, which stays below the maximum
+	task := send("one", shared.PointerTo(0))
+	assert.Empty(t, task.History, "historyLength 0 omits the history from the response")
+	stored, err := store.Load(context.Background(), "pruned-task")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"user: one", "agent: echo one"}, historyTexts(stored.History))
+
+	// The stored history keeps the last 3 messages, whatever the client requests
+	task = send("two", shared.PointerTo(10))
+	assert.Equal(t, []string{"agent: echo one", "user: two", "agent: echo two"}, historyTexts(task.History),
+		"a requested historyLength above the maximum returns the pruned history")
+	stored, err = store.Load(context.Background(), "pruned-task")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"agent: echo one", "user: two", "agent: echo two"}, historyTexts(stored.History))
+
+	// A requested historyLength below the maximum only trims the response
+	task = send("three", shared.PointerTo(1))
+	assert.Equal(t, []string{"agent: echo three"}, historyTexts(task.History))
+	stored, err = store.Load(context.Background(), "pruned-task")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"agent: echo two", "user: three", "agent: echo three"}, historyTexts(stored.History))
+}
+
+func TestHistoryIsKeptWithoutMaxHistoryLength(t *testing.T) {
+	manager, err := transport.NewManager(zap.NewNop(), config.NewInternalConfig())
+	require.NoError(t, err)
+	store := a2a.NewInMemoryTaskStore()
+	capability := a2a.NewA2ACapability(zap.NewNop(), manager, store, echoHandler)
+
+	for _, text := range []string{"one", "two", "three"} {
+		result, err := capability.GetHandlers()["tasks/send"](sharedtesting.BuildMessage("tasks/send", a2aSchema.TaskSendParams{
+			ID:            "full-history-task",
+			Message:       a2aSchema.Message{Role: "user", Parts: textParts(text)},
+			HistoryLength: shared.PointerTo(2),
+		}))
+		task := sharedtesting.AssertJSONRPCSuccess[*a2aSchema.Task](t, result, err)
+		assert.Len(t, task.History, 2)
+	}
+	stored, err := store.Load(context.Background(), "full-history-task")
+	require.NoError(t, err)
+	assert.Len(t, stored.History, 6)
+}
@@ -0,0 +1,117 @@
+package a2a
+
+import (
+	"context"
+	"sync"
+
+	"github.com/gate4ai/gate4ai/shared"
+	"go.uber.org/zap"
+)
+
+// hubSubscriberBuffer is how many events a resubscribed stream may fall behind the handler
+// before the hub drops it.
+const hubSubscriberBuffer = 64
+
+// runningHandler is a running task handler: the func cancelling it and the hub fanning out
+// the stream events of its run.
+type runningHandler struct {
+	cancel context.CancelFunc
+	hub    *taskHub
+}
+
+// taskHub fans out the stream events of one handler run to the clients that resubscribed to it.
+type taskHub struct {
+	mu          sync.Mutex
+	subscribers map[chan shared.A2AStreamEvent]struct{}
+	closed      bool
+}
+
+func newTaskHub() *taskHub {
+	return &taskHub{subscribers: make(map[chan shared.A2AStreamEvent]struct{})}
+}
+
+// subscribe returns a channel receiving the events published from now on, closed when the run
+// ends or the subscriber falls behind, and a func ending the subscription.
+func (h *taskHub) subscribe() (<-chan shared.A2AStreamEvent, func()) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	events := make(chan shared.A2AStreamEvent, hubSubscriberBuffer)
+	if h.closed {
+		close(events)
+		return events, func() {}
+	}
+	h.subscribers[events] = struct{}{}
+	return events, func() { h.remove(events) }
+}
+
+func (h *taskHub) remove(events chan shared.A2AStreamEvent) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if _, ok := h.subscribers[events]; ok {
+		delete(h.subscribers, events)
+		close(events)
+	}
+}
+
+// publish sends a copy of event to every subscriber, dropping those whose buffer is full.
+func (h *taskHub) publish(event *shared.A2AStreamEvent) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for events := range h.subscribers {
+		select {
+		case events <- *event:
+		default:
+			delete(h.subscribers, events)
+			close(events)
+		}
+	}
+}
+
+// close ends the subscriptions once the run has sent its last event.
+func (h *taskHub) close() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.closed = true
+	for events := range h.subscribers {
+		close(events)
+	}
+	h.subscribers = nil
+}
+
+// subscribeHandler subscribes to the stream events of the task's running handler. ok is false
+// if no handler is running.
+func (ac *A2ACapability) subscribeHandler(taskID string) (events <-chan shared.A2AStreamEvent, unsubscribe func(), ok bool) {
+	ac.runningHandlersMu.Lock()
+	defer ac.runningHandlersMu.Unlock()
+	running, ok := ac.runningHandlers[taskID]
+	if !ok {
+		return nil, nil, false
+	}
+	events, unsubscribe = running.hub.subscribe()
+	return events, unsubscribe, true
+}
+
+// forwardHubEvents sends the events of a subscription to session until the final event. If the
+// run ends without one, e.g. because it was cancelled, the stored final status is sent instead.
+func (ac *A2ACapability) forwardHubEvents(taskID string, events <-chan shared.A2AStreamEvent, unsubscribe func(), session shared.ISession, logger *zap.Logger) {
+	defer unsubscribe()
+	for event := range events {
+		if err := session.SendA2AStreamEvent(&event); err != nil {
+			logger.Debug("Stopped forwarding task events", zap.Error(err))
+			return
+		}
+		if event.Final {
+			return
+		}
+	}
+	task, err := ac.loadTask(context.Background(), taskID)
+	if err != nil {
+		logger.Warn("Failed to load task after its run ended", zap.Error(err))
+		return
+	}
+	if isTerminalState(task.Status.State) {
+		if err := session.SendA2AStreamEvent(finalStatusEvent(task)); err != nil {
+			logger.Debug("Failed to send final status event", zap.Error(err))
+		}
+	}
+}
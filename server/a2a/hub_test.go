@@ -0,0 +1,84 @@
+package a2a_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/gate4ai/gate4ai/server/a2a"
+	a2aSchema "github.com/gate4ai/gate4ai/shared/a2a/2025-draft/schema"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestResubscribeFollowsRunningHandler(t *testing.T) {
+	started, release := make(chan struct{}), make(chan struct{})
+	handler := func(ctx context.Context, task *a2aSchema.Task, updates chan<- a2a.A2AYieldUpdate, logger *zap.Logger) error {
+		updates <- a2a.A2AYieldUpdate{Artifact: &a2aSchema.Artifact{Parts: textParts("before")}}
+		close(started)
+		<-release
+		updates <- a2a.A2AYieldUpdate{Artifact: &a2aSchema.Artifact{Index: 1, Parts: textParts("after")}}
+		updates <- a2a.A2AYieldUpdate{Status: &a2aSchema.TaskStatus{State: a2aSchema.TaskStateCompleted}}
+		return nil
+	}
+	server := newA2ATestServer(t, handler)
+
+	original := make(chan []rawSSEEvent)
+	go func() {
+		original <- postA2A(t, server.URL, "tasks/sendSubscribe", a2aSchema.TaskSendParams{
+			ID:      "hub-task",
+			Message: a2aSchema.Message{Role: "user", Parts: textParts("go")},
+		})
+	}()
+	<-started
+
+	// A client that lost its stream reconnects while the handler is still running
+	resumed := make(chan []rawSSEEvent)
+	go func() {
+		resumed <- postA2A(t, server.URL, "tasks/resubscribe", a2aSchema.TaskResubscribeParams{
+			TaskQueryParams: a2aSchema.TaskQueryParams{ID: "hub-task"},
+		})
+	}()
+	time.Sleep(200 * time.Millisecond) // Let the resubscribe join the hub
+	close(release)
+
+	for name, events := range map[string][]rawSSEEvent{"original": <-original, "resumed": <-resumed} {
+		require.NotEmpty(t, events, name)
+		var final a2aSchema.TaskStatusUpdateEvent
+		require.NoError(t, json.Unmarshal(events[len(events)-1].Result, &final), name)
+		assert.True(t, final.Final, name)
+		assert.Equal(t, a2aSchema.TaskStateCompleted, final.Status.State, name)
+
+		var artifacts []string
+		for _, event := range events {
+			var update a2aSchema.TaskArtifactUpdateEvent
+			if json.Unmarshal(event.Result, &update) == nil && len(update.Artifact.Parts) > 0 {
+				artifacts = append(artifacts, *update.Artifact.Parts[0].Text)
+			}
+		}
+		assert.Contains(t, artifacts, "after", name)
+	}
+}
+
+func TestResubscribeToFinishedTaskSendsFinalStatus(t *testing.T) {
+	handler := func(ctx context.Context, task *a2aSchema.Task, updates chan<- a2a.A2AYieldUpdate, logger *zap.Logger) error {
+		updates <- a2a.A2AYieldUpdate{Status: &a2aSchema.TaskStatus{State: a2aSchema.TaskStateCompleted}}
+		return nil
+	}
+	server := newA2ATestServer(t, handler)
+	postA2A(t, server.URL, "tasks/sendSubscribe", a2aSchema.TaskSendParams{
+		ID:      "finished-task",
+		Message: a2aSchema.Message{Role: "user", Parts: textParts("go")},
+	})
+
+	events := postA2A(t, server.URL, "tasks/resubscribe", a2aSchema.TaskResubscribeParams{
+		TaskQueryParams: a2aSchema.TaskQueryParams{ID: "finished-task"},
+	})
+	require.Len(t, events, 1)
+	var final a2aSchema.TaskStatusUpdateEvent
+	require.NoError(t, json.Unmarshal(events[0].Result, &final))
+	assert.True(t, final.Final)
+	assert.Equal(t, a2aSchema.TaskStateCompleted, final.Status.State)
+}
@@ -0,0 +1,87 @@
+package a2a
+
+import (
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/gate4ai/gate4ai/server/transport"
+	"github.com/gate4ai/gate4ai/shared"
+	a2aSchema "github.com/gate4ai/gate4ai/shared/a2a/2025-draft/schema"
+	"go.uber.org/zap"
+)
+
+// MaxTaskListLimit is the largest page tasks/list returns; a zero or larger limit is clamped to it.
+const MaxTaskListLimit = 100
+
+// TaskListParams are the parameters of tasks/list. They mirror TaskFilter; omitted fields do not filter.
+type TaskListParams struct {
+	States       []a2aSchema.TaskState  `json:"states,omitempty"`
+	SessionID    string                 `json:"sessionId,omitempty"`
+	ParentTaskID string                 `json:"parentTaskId,omitempty"`
+	Since        *time.Time             `json:"since,omitempty"`
+	CreatedAfter *time.Time             `json:"createdAfter,omitempty"`
+	Metadata     map[string]interface{} `json:"metadata,omitempty"`
+	Limit        int                    `json:"limit,omitempty"`
+	Cursor       string                 `json:"cursor,omitempty"`
+}
+
+// TaskListResult is the result of tasks/list.
+type TaskListResult struct {
+	Tasks      []*a2aSchema.Task `json:"tasks"`
+	NextCursor string            `json:"nextCursor,omitempty"`
+}
+
+// handleTaskList handles `tasks/list`, returning one page of the caller's tasks matching the params,
+// oldest first. Anonymous callers cannot be told apart, so they must name the session to list.
+func (ac *A2ACapability) handleTaskList(msg *shared.Message) (interface{}, error) {
+	logger := ac.logger.With(zap.String("sessionID", msg.Session.GetID()), zap.String("method", "tasks/list"))
+
+	var params TaskListParams
+	if msg.Params != nil {
+		if err := json.Unmarshal(*msg.Params, &params); err != nil {
+			logger.Error("Failed to unmarshal tasks/list params", zap.Error(err))
+			return nil, &shared.JSONRPCError{Code: shared.JSONRPCErrorInvalidParams, Message: err.Error()}
+		}
+	}
+	if params.Limit < 0 {
+		return nil, &shared.JSONRPCError{Code: shared.JSONRPCErrorInvalidParams, Message: "Limit must not be negative"}
+	}
+	userID := transport.GetUserId(msg.Session.GetParams())
+	if userID == "" && params.SessionID == "" {
+		return nil, &shared.JSONRPCError{Code: shared.JSONRPCErrorInvalidParams, Message: "Anonymous callers must specify sessionId"}
+	}
+	filter := TaskFilter{
+		States:         params.States,
+		SessionID:      params.SessionID,
+		UserID:         &userID,
+		ParentTaskID:   params.ParentTaskID,
+		MetadataFilter: params.Metadata,
+		Limit:          params.Limit,
+		Cursor:         params.Cursor,
+	}
+	if filter.Limit == 0 || filter.Limit > MaxTaskListLimit {
+		filter.Limit = MaxTaskListLimit
+	}
+	if params.Since != nil {
+		filter.Since = *params.Since
+	}
+	if params.CreatedAfter != nil {
+		filter.CreatedAfter = *params.CreatedAfter
+	}
+
+	tasks, nextCursor, err := ac.taskStore.List(msg.Context(), filter)
+	if err != nil {
+		logger.Warn("Failed to list tasks", zap.Error(err))
+		if errors.Is(err, ErrInvalidCursor) {
+			return nil, &shared.JSONRPCError{Code: shared.JSONRPCErrorInvalidParams, Message: err.Error()}
+		}
+		return nil, &shared.JSONRPCError{Code: shared.JSONRPCErrorInternal, Message: "Failed to list tasks"}
+	}
+	for _, task := range tasks {
+		task.History = nil // Same as tasks/get without historyLength
+		task.Timeline = nil
+	}
+	logger.Debug("Listed tasks", zap.Int("count", len(tasks)))
+	return &TaskListResult{Tasks: tasks, NextCursor: nextCursor}, nil
+}
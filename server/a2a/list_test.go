@@ -0,0 +1,168 @@
+package a2a_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/gate4ai/gate4ai/server/a2a"
+	"github.com/gate4ai/gate4ai/server/transport"
+	"github.com/gate4ai/gate4ai/shared"
+	a2aSchema "github.com/gate4ai/gate4ai/shared/a2a/2025-draft/schema"
+	"github.com/gate4ai/gate4ai/shared/config"
+	sharedtesting "github.com/gate4ai/gate4ai/shared/testing"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestTaskListFiltersByMetadata(t *testing.T) {
+	logger := zap.NewNop()
+	manager, err := transport.NewManager(logger, config.NewInternalConfig())
+	require.NoError(t, err)
+	store := a2a.NewInMemoryTaskStore()
+	base := time.Date(2025, 5, 1, 12, 0, 0, 0, time.UTC)
+	for i, tenant := range []string{"acme", "globex", "acme", "globex", "acme"} {
+		metadata := map[string]interface{}{"tenantID": tenant, "config": map[string]interface{}{"env": []string{"dev", "prod"}[i%2]}}
+		require.NoError(t, store.Save(context.Background(), &a2aSchema.Task{
+			ID:        fmt.Sprintf("task-%d", i),
+			UserID:    "operator",
+			CreatedAt: base.Add(time.Duration(i) * time.Minute),
+			Status:    a2aSchema.TaskStatus{State: a2aSchema.TaskStateCompleted, Timestamp: base.Add(time.Duration(i) * time.Minute)},
+			History:   []a2aSchema.Message{{Role: "user", Parts: []a2aSchema.Part{{Type: shared.PointerTo("text"), Text: shared.PointerTo("hi")}}}},
+			Metadata:  &metadata,
+		}))
+	}
+	handler := func(ctx context.Context, task *a2aSchema.Task, updates chan<- a2a.A2AYieldUpdate, logger *zap.Logger) error {
+		return nil
+	}
+	handlers := a2a.NewA2ACapability(logger, manager, store, handler).GetHandlers()
+	listTasks := func(msg *shared.Message) (interface{}, error) {
+		msg.Session = userSession("list-session", "operator")
+		return handlers["tasks/list"](msg)
+	}
+
+	// Params arrive as JSON, as they would from a client
+	params := json.RawMessage(`{"metadata": {"tenantID": "acme"}, "limit": 2}`)
+	result, err := listTasks(sharedtesting.BuildMessage("tasks/list", params))
+	page := sharedtesting.AssertJSONRPCSuccess[*a2a.TaskListResult](t, result, err)
+	assert.Equal(t, []string{"task-0", "task-2"}, taskIDs(page.Tasks))
+	assert.Nil(t, page.Tasks[0].History)
+	require.NotEmpty(t, page.NextCursor)
+
+	params = json.RawMessage(fmt.Sprintf(`{"metadata": {"tenantID": "acme"}, "limit": 2, "cursor": %q}`, page.NextCursor))
+	result, err = listTasks(sharedtesting.BuildMessage("tasks/list", params))
+	page = sharedtesting.AssertJSONRPCSuccess[*a2a.TaskListResult](t, result, err)
+	assert.Equal(t, []string{"task-4"}, taskIDs(page.Tasks))
+	assert.Empty(t, page.NextCursor)
+
+	params = json.RawMessage(`{"metadata": {"tenantID": "acme"}, "createdAfter": "2025-05-01T12:01:00Z"}`)
+	result, err = listTasks(sharedtesting.BuildMessage("tasks/list", params))
+	page = sharedtesting.AssertJSONRPCSuccess[*a2a.TaskListResult](t, result, err)
+	assert.Equal(t, []string{"task-2", "task-4"}, taskIDs(page.Tasks))
+
+	params = json.RawMessage(`{"metadata": {"config": {"env": "prod"}}}`)
+	result, err = listTasks(sharedtesting.BuildMessage("tasks/list", params))
+	page = sharedtesting.AssertJSONRPCSuccess[*a2a.TaskListResult](t, result, err)
+	assert.Equal(t, []string{"task-1", "task-3"}, taskIDs(page.Tasks))
+
+	result, err = listTasks(sharedtesting.BuildMessage("tasks/list", json.RawMessage(`{"cursor": "bogus"}`)))
+	sharedtesting.AssertJSONRPCError(t, result, err, shared.JSONRPCErrorInvalidParams)
+	result, err = listTasks(sharedtesting.BuildMessage("tasks/list", json.RawMessage(`{"limit": -1}`)))
+	sharedtesting.AssertJSONRPCError(t, result, err, shared.JSONRPCErrorInvalidParams)
+}
+
+// failingListStore fails every List call, as a store whose backend is down would.
+type failingListStore struct {
+	*a2a.InMemoryTaskStore
+}
+
+func (s *failingListStore) List(ctx context.Context, filter a2a.TaskFilter) ([]*a2aSchema.Task, string, error) {
+	return nil, "", errors.New("backend unavailable")
+}
+
+func TestTaskListIsScopedToTheCaller(t *testing.T) {
+	logger := zap.NewNop()
+	manager, err := transport.NewManager(logger, config.NewInternalConfig())
+	require.NoError(t, err)
+	store := a2a.NewInMemoryTaskStore()
+	base := time.Date(2025, 5, 1, 12, 0, 0, 0, time.UTC)
+	for i, owner := range []struct{ user, session string }{{"alice", "s1"}, {"bob", "s1"}, {"", "s1"}, {"", "s2"}, {"alice", "s2"}} {
+		require.NoError(t, store.Save(context.Background(), &a2aSchema.Task{
+			ID: fmt.Sprintf("task-%d", i), UserID: owner.user, SessionID: owner.session, CreatedAt: base.Add(time.Duration(i) * time.Minute),
+			Status: a2aSchema.TaskStatus{State: a2aSchema.TaskStateCompleted, Timestamp: base},
+		}))
+	}
+	handler := func(ctx context.Context, task *a2aSchema.Task, updates chan<- a2a.A2AYieldUpdate, logger *zap.Logger) error {
+		return nil
+	}
+	handlers := a2a.NewA2ACapability(logger, manager, store, handler).GetHandlers()
+	list := func(userID string, params a2a.TaskListParams) (interface{}, error) {
+		msg := sharedtesting.BuildMessage("tasks/list", params)
+		msg.Session = userSession("list-session", userID)
+		return handlers["tasks/list"](msg)
+	}
+
+	for _, tc := range []struct {
+		name   string
+		userID string
+		params a2a.TaskListParams
+		want   []string
+	}{
+		{name: "user", userID: "alice", want: []string{"task-0", "task-4"}},
+		{name: "user and session", userID: "alice", params: a2a.TaskListParams{SessionID: "s2"}, want: []string{"task-4"}},
+		{name: "other user", userID: "bob", want: []string{"task-1"}},
+		{name: "anonymous with session", params: a2a.TaskListParams{SessionID: "s1"}, want: []string{"task-2"}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			result, err := list(tc.userID, tc.params)
+			page := sharedtesting.AssertJSONRPCSuccess[*a2a.TaskListResult](t, result, err)
+			assert.Equal(t, tc.want, taskIDs(page.Tasks))
+		})
+	}
+
+	result, err := list("", a2a.TaskListParams{})
+	sharedtesting.AssertJSONRPCError(t, result, err, shared.JSONRPCErrorInvalidParams)
+}
+
+func TestTaskListClampsLimit(t *testing.T) {
+	logger := zap.NewNop()
+	manager, err := transport.NewManager(logger, config.NewInternalConfig())
+	require.NoError(t, err)
+	store := a2a.NewInMemoryTaskStore()
+	for i := 0; i <= a2a.MaxTaskListLimit; i++ {
+		require.NoError(t, store.Save(context.Background(), &a2aSchema.Task{ID: fmt.Sprintf("task-%03d", i), UserID: "operator"}))
+	}
+	handler := func(ctx context.Context, task *a2aSchema.Task, updates chan<- a2a.A2AYieldUpdate, logger *zap.Logger) error {
+		return nil
+	}
+	handlers := a2a.NewA2ACapability(logger, manager, store, handler).GetHandlers()
+
+	for _, limit := range []int{0, a2a.MaxTaskListLimit + 1} {
+		t.Run(fmt.Sprintf("limit %d", limit), func(t *testing.T) {
+			msg := sharedtesting.BuildMessage("tasks/list", a2a.TaskListParams{Limit: limit})
+			msg.Session = userSession("list-session", "operator")
+			result, err := handlers["tasks/list"](msg)
+			page := sharedtesting.AssertJSONRPCSuccess[*a2a.TaskListResult](t, result, err)
+			assert.Len(t, page.Tasks, a2a.MaxTaskListLimit)
+			assert.NotEmpty(t, page.NextCursor)
+		})
+	}
+}
+
+func TestTaskListStoreFailureIsInternalError(t *testing.T) {
+	logger := zap.NewNop()
+	manager, err := transport.NewManager(logger, config.NewInternalConfig())
+	require.NoError(t, err)
+	handler := func(ctx context.Context, task *a2aSchema.Task, updates chan<- a2a.A2AYieldUpdate, logger *zap.Logger) error {
+		return nil
+	}
+	store := &failingListStore{InMemoryTaskStore: a2a.NewInMemoryTaskStore()}
+	msg := sharedtesting.BuildMessage("tasks/list", a2a.TaskListParams{})
+	msg.Session = userSession("list-session", "operator")
+	result, err := a2a.NewA2ACapability(logger, manager, store, handler).GetHandlers()["tasks/list"](msg)
+	sharedtesting.AssertJSONRPCError(t, result, err, shared.JSONRPCErrorInternal)
+}
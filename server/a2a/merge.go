@@ -0,0 +1,90 @@
+package a2a
+
+import (
+	"encoding/json"
+
+	a2aSchema "github.com/gate4ai/gate4ai/shared/a2a/2025-draft/schema"
+)
+
+// ArtifactMergeStrategy combines an artifact update having Append set with the stored artifact
+// of the same index. Both artifacts are copies the strategy may modify; the capability takes
+// LastChunk from the update afterwards.
+type ArtifactMergeStrategy interface {
+	Merge(existing, update a2aSchema.Artifact) a2aSchema.Artifact
+}
+
+// WithArtifactMergeStrategy sets how appended artifact chunks are merged. The default is AppendParts.
+func WithArtifactMergeStrategy(strategy ArtifactMergeStrategy) A2AOption {
+	return func(ac *A2ACapability) {
+		ac.artifactMergeStrategy = strategy
+	}
+}
+
+// AppendParts adds the parts of the update after the existing parts.
+type AppendParts struct{}
+
+func (AppendParts) Merge(existing, update a2aSchema.Artifact) a2aSchema.Artifact {
+	existing.Parts = append(existing.Parts, update.Parts...)
+	return existing
+}
+
+// ReplaceLastPart replaces the last existing part with the parts of the update, for agents that
+// stream growing snapshots of their output.
+type ReplaceLastPart struct{}
+
+func (ReplaceLastPart) Merge(existing, update a2aSchema.Artifact) a2aSchema.Artifact {
+	if len(existing.Parts) > 0 {
+		existing.Parts = existing.Parts[:len(existing.Parts)-1]
+	}
+	existing.Parts = append(existing.Parts, update.Parts...)
+	return existing
+}
+
+// MergeJSON deep-merges text parts holding JSON objects into the last existing part when it
+// holds a JSON object too: nested objects are merged and other values are replaced. Other
+// parts are appended.
+type MergeJSON struct{}
+
+func (MergeJSON) Merge(existing, update a2aSchema.Artifact) a2aSchema.Artifact {
+	for _, part := range update.Parts {
+		if n := len(existing.Parts); n > 0 {
+			target, ok := jsonObjectText(existing.Parts[n-1])
+			if patch, patchOK := jsonObjectText(part); ok && patchOK {
+				merged, err := json.Marshal(deepMergeJSON(target, patch))
+				if err == nil {
+					text := string(merged)
+					existing.Parts[n-1].Text = &text
+					continue
+				}
+			}
+		}
+		existing.Parts = append(existing.Parts, part)
+	}
+	return existing
+}
+
+// jsonObjectText decodes a text part holding a JSON object.
+func jsonObjectText(part a2aSchema.Part) (map[string]interface{}, bool) {
+	if part.Text == nil || (part.Type != nil && *part.Type != "text") {
+		return nil, false
+	}
+	var object map[string]interface{}
+	if err := json.Unmarshal([]byte(*part.Text), &object); err != nil || object == nil {
+		return nil, false
+	}
+	return object, true
+}
+
+// deepMergeJSON merges patch into target, recursing into objects present in both.
+func deepMergeJSON(target, patch map[string]interface{}) map[string]interface{} {
+	for key, value := range patch {
+		patchObject, isObject := value.(map[string]interface{})
+		targetObject, targetIsObject := target[key].(map[string]interface{})
+		if isObject && targetIsObject {
+			target[key] = deepMergeJSON(targetObject, patchObject)
+		} else {
+			target[key] = value
+		}
+	}
+	return target
+}
@@ -0,0 +1,85 @@
+package a2a_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/gate4ai/gate4ai/server/a2a"
+	"github.com/gate4ai/gate4ai/server/transport"
+	"github.com/gate4ai/gate4ai/shared"
+	a2aSchema "github.com/gate4ai/gate4ai/shared/a2a/2025-draft/schema"
+	"github.com/gate4ai/gate4ai/shared/config"
+	sharedtesting "github.com/gate4ai/gate4ai/shared/testing"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func textParts(texts ...string) []a2aSchema.Part {
+	parts := make([]a2aSchema.Part, len(texts))
+	for i, text := range texts {
+		parts[i] = a2aSchema.Part{Type: shared.PointerTo("text"), Text: shared.PointerTo(text)}
+	}
+	return parts
+}
+
+func partTexts(parts []a2aSchema.Part) []string {
+	texts := make([]string, len(parts))
+	for i, part := range parts {
+		texts[i] = *part.Text
+	}
+	return texts
+}
+
+func TestArtifactMergeStrategies(t *testing.T) {
+	tests := []struct {
+		name     string
+		strategy a2a.ArtifactMergeStrategy
+		existing []string
+		update   []string
+		want     []string
+	}{
+		{"append parts", a2a.AppendParts{}, []string{"a", "b"}, []string{"c"}, []string{"a", "b", "c"}},
+		{"replace last part", a2a.ReplaceLastPart{}, []string{"a", "draft"}, []string{"final"}, []string{"a", "final"}},
+		{"replace last part of empty artifact", a2a.ReplaceLastPart{}, nil, []string{"first"}, []string{"first"}},
+		{
+			"merge JSON",
+			a2a.MergeJSON{},
+			[]string{`{"title":"Report","meta":{"author":"a","pages":1}}`},
+			[]string{`{"meta":{"pages":2},"sections":["intro"]}`},
+			[]string{`{"meta":{"author":"a","pages":2},"sections":["intro"],"title":"Report"}`},
+		},
+		{"merge JSON appends non-JSON parts", a2a.MergeJSON{}, []string{`{"a":1}`}, []string{"# Notes"}, []string{`{"a":1}`, "# Notes"}},
+		{"merge JSON does not merge into non-JSON parts", a2a.MergeJSON{}, []string{"# Notes"}, []string{`{"a":1}`}, []string{"# Notes", `{"a":1}`}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			merged := tt.strategy.Merge(a2aSchema.Artifact{Parts: textParts(tt.existing...)}, a2aSchema.Artifact{Parts: textParts(tt.update...)})
+			assert.Equal(t, tt.want, partTexts(merged.Parts))
+		})
+	}
+}
+
+func TestWithArtifactMergeStrategy(t *testing.T) {
+	handler := func(ctx context.Context, task *a2aSchema.Task, updates chan<- a2a.A2AYieldUpdate, logger *zap.Logger) error {
+		updates <- a2a.A2AYieldUpdate{Artifact: &a2aSchema.Artifact{Parts: textParts(`{"status":"draft","items":{"a":1}}`)}}
+		updates <- a2a.A2AYieldUpdate{Artifact: &a2aSchema.Artifact{Parts: textParts(`{"items":{"b":2}}`), Append: shared.PointerTo(true)}}
+		updates <- a2a.A2AYieldUpdate{Artifact: &a2aSchema.Artifact{Parts: textParts(`{"status":"done"}`), Append: shared.PointerTo(true), LastChunk: shared.PointerTo(true)}}
+		updates <- a2a.A2AYieldUpdate{Status: &a2aSchema.TaskStatus{State: a2aSchema.TaskStateCompleted}}
+		return nil
+	}
+	logger := zap.NewNop()
+	manager, err := transport.NewManager(logger, config.NewInternalConfig())
+	require.NoError(t, err)
+	handlers := a2a.NewA2ACapability(logger, manager, a2a.NewInMemoryTaskStore(), handler, a2a.WithArtifactMergeStrategy(a2a.MergeJSON{})).GetHandlers()
+
+	result, err := handlers["tasks/send"](sharedtesting.BuildMessage("tasks/send", a2aSchema.TaskSendParams{
+		ID:      "merged-artifact",
+		Message: a2aSchema.Message{Role: "user", Parts: textParts("go")},
+	}))
+	task := sharedtesting.AssertJSONRPCSuccess[*a2aSchema.Task](t, result, err)
+	require.Len(t, task.Artifacts, 1)
+	assert.Equal(t, []string{`{"items":{"a":1,"b":2},"status":"done"}`}, partTexts(task.Artifacts[0].Parts))
+	require.NotNil(t, task.Artifacts[0].LastChunk)
+	assert.True(t, *task.Artifacts[0].LastChunk)
+}
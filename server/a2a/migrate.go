@@ -0,0 +1,53 @@
+package a2a
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	a2aSchema "github.com/gate4ai/gate4ai/shared/a2a/2025-draft/schema"
+	"go.uber.org/zap"
+)
+
+// MigrateTaskStore copies the tasks of from matching filter to to, following List cursors
+// until every page is read. Both stores stay usable meanwhile: a task the target already
+// holds with a newer status (updated there since the migration started) is not overwritten
+// and counts as failed, like tasks that cannot be saved. The error is set only when listing
+// the source fails, which stops the migration.
+func MigrateTaskStore(ctx context.Context, from TaskStore, to TaskStore, filter TaskFilter, logger *zap.Logger) (migrated int, failed int, err error) {
+	for {
+		tasks, nextCursor, err := from.List(ctx, filter)
+		if err != nil {
+			return migrated, failed, fmt.Errorf("failed to list tasks to migrate: %w", err)
+		}
+		for _, task := range tasks {
+			taskLogger := logger.With(zap.String("taskID", task.ID))
+			if err := migrateTask(ctx, to, task); err != nil {
+				taskLogger.Warn("Failed to migrate task", zap.Error(err))
+				failed++
+				continue
+			}
+			taskLogger.Debug("Migrated task")
+			migrated++
+		}
+		if nextCursor == "" {
+			break
+		}
+		filter.Cursor = nextCursor
+	}
+	logger.Info("Task store migration finished", zap.Int("migrated", migrated), zap.Int("failed", failed))
+	return migrated, failed, nil
+}
+
+// migrateTask saves task to store unless the store holds a newer version of it.
+func migrateTask(ctx context.Context, store TaskStore, task *a2aSchema.Task) error {
+	existing, err := store.Load(ctx, task.ID)
+	var jsonRPCErr *a2aSchema.JSONRPCError
+	switch {
+	case err == nil && existing.Status.Timestamp.After(task.Status.Timestamp):
+		return fmt.Errorf("target holds a newer version of the task (status at %s)", existing.Status.Timestamp)
+	case err != nil && !(errors.As(err, &jsonRPCErr) && jsonRPCErr.Code == a2aSchema.ErrorCodeTaskNotFound):
+		return fmt.Errorf("failed to check the target store: %w", err)
+	}
+	return store.Save(ctx, task)
+}
@@ -0,0 +1,66 @@
+package a2a_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/gate4ai/gate4ai/server/a2a"
+	"github.com/gate4ai/gate4ai/shared"
+	a2aSchema "github.com/gate4ai/gate4ai/shared/a2a/2025-draft/schema"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestMigrateTaskStore(t *testing.T) {
+	ctx := context.Background()
+	from, to := a2a.NewInMemoryTaskStore(), a2a.NewInMemoryTaskStore()
+	base := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	for i := range 50 {
+		metadata := map[string]interface{}{"index": i}
+		require.NoError(t, from.Save(ctx, &a2aSchema.Task{
+			ID:        fmt.Sprintf("task-%02d", i),
+			SessionID: fmt.Sprintf("session-%d", i%3),
+			Status:    a2aSchema.TaskStatus{State: a2aSchema.TaskStateCompleted, Timestamp: base.Add(time.Duration(i) * time.Second)},
+			History:   []a2aSchema.Message{{Role: "user", Parts: []a2aSchema.Part{{Type: shared.PointerTo("text"), Text: shared.PointerTo(fmt.Sprintf("request %d", i))}}}},
+			Artifacts: []a2aSchema.Artifact{{Parts: []a2aSchema.Part{{Type: shared.PointerTo("text"), Text: shared.PointerTo(fmt.Sprintf("result %d", i))}}}},
+			Metadata:  &metadata,
+		}))
+	}
+
+	// A small page size makes the migration follow List cursors
+	migrated, failed, err := a2a.MigrateTaskStore(ctx, from, to, a2a.TaskFilter{Limit: 7}, zap.NewNop())
+	require.NoError(t, err)
+	assert.Equal(t, 50, migrated)
+	assert.Equal(t, 0, failed)
+
+	for i := range 50 {
+		id := fmt.Sprintf("task-%02d", i)
+		want, err := from.Load(ctx, id)
+		require.NoError(t, err)
+		got, err := to.Load(ctx, id)
+		require.NoError(t, err, "task %s missing in the target", id)
+		assert.Equal(t, want, got)
+	}
+}
+
+func TestMigrateTaskStoreKeepsNewerTargetTasks(t *testing.T) {
+	ctx := context.Background()
+	from, to := a2a.NewInMemoryTaskStore(), a2a.NewInMemoryTaskStore()
+	now := time.Now()
+	require.NoError(t, from.Save(ctx, &a2aSchema.Task{ID: "stale", Status: a2aSchema.TaskStatus{State: a2aSchema.TaskStateWorking, Timestamp: now}}))
+	require.NoError(t, from.Save(ctx, &a2aSchema.Task{ID: "other", Status: a2aSchema.TaskStatus{State: a2aSchema.TaskStateWorking, Timestamp: now}}))
+	// The task already moved on in the target
+	require.NoError(t, to.Save(ctx, &a2aSchema.Task{ID: "stale", Status: a2aSchema.TaskStatus{State: a2aSchema.TaskStateCompleted, Timestamp: now.Add(time.Second)}}))
+
+	migrated, failed, err := a2a.MigrateTaskStore(ctx, from, to, a2a.TaskFilter{}, zap.NewNop())
+	require.NoError(t, err)
+	assert.Equal(t, 1, migrated)
+	assert.Equal(t, 1, failed)
+
+	task, err := to.Load(ctx, "stale")
+	require.NoError(t, err)
+	assert.Equal(t, a2aSchema.TaskStateCompleted, task.Status.State)
+}
@@ -0,0 +1,327 @@
+package a2a
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gate4ai/gate4ai/server/transport"
+	"github.com/gate4ai/gate4ai/shared"
+	a2aSchema "github.com/gate4ai/gate4ai/shared/a2a/2025-draft/schema"
+	"go.uber.org/zap"
+)
+
+const (
+	// PushNotificationMethod is the JSON-RPC method of the payload POSTed to a push notification URL.
+	PushNotificationMethod = "tasks/pushNotification"
+	// PushNotificationSchemeOAuth2 is the authentication scheme whose credentials are a
+	// PushNotificationOAuth2Credentials used to fetch the bearer token.
+	PushNotificationSchemeOAuth2 = "oauth2"
+)
+
+// errPushUnauthorized is returned by postPushNotification when the receiver rejects the token.
+var errPushUnauthorized = errors.New("push notification receiver responded with HTTP 401")
+
+// PushNotificationOAuth2Credentials are the Authentication.Credentials of a push notification
+// config using the oauth2 scheme. The token is fetched with the client credentials grant.
+type PushNotificationOAuth2Credentials struct {
+	TokenURL     string `json:"tokenUrl"`
+	ClientID     string `json:"clientId"`
+	ClientSecret string `json:"clientSecret"`
+	Scope        string `json:"scope,omitempty"`
+}
+
+// taskPushTarget is the push notification config of one task with its cached OAuth token.
+type taskPushTarget struct {
+	config a2aSchema.PushNotificationConfig
+	oauth  *PushNotificationOAuth2Credentials
+
+	mu          sync.Mutex
+	token       string
+	tokenExpiry time.Time
+	lastPushed  string // State and timestamp of the last status pushed
+}
+
+// parsePushConfig validates config and decodes its OAuth credentials, if any.
+func parsePushConfig(config a2aSchema.PushNotificationConfig) (*PushNotificationOAuth2Credentials, *shared.JSONRPCError) {
+	if !validNotificationURL(config.URL) {
+		return nil, &shared.JSONRPCError{Code: shared.JSONRPCErrorInvalidParams, Message: fmt.Sprintf("Invalid push notification URL: %q", config.URL)}
+	}
+	if config.Authentication == nil || !slices.Contains(config.Authentication.Schemes, PushNotificationSchemeOAuth2) {
+		return nil, nil
+	}
+	if config.Authentication.Credentials == nil {
+		return nil, &shared.JSONRPCError{Code: shared.JSONRPCErrorInvalidParams, Message: "OAuth2 push notification authentication requires credentials"}
+	}
+	var oauth PushNotificationOAuth2Credentials
+	if err := json.Unmarshal([]byte(*config.Authentication.Credentials), &oauth); err != nil {
+		return nil, &shared.JSONRPCError{Code: shared.JSONRPCErrorInvalidParams, Message: fmt.Sprintf("Invalid OAuth2 credentials: %v", err)}
+	}
+	if !validNotificationURL(oauth.TokenURL) {
+		return nil, &shared.JSONRPCError{Code: shared.JSONRPCErrorInvalidParams, Message: fmt.Sprintf("Invalid OAuth2 token URL: %q", oauth.TokenURL)}
+	}
+	return &oauth, nil
+}
+
+// setPushConfig replaces the push notification config of an existing task with config and the
+// OAuth credentials parsePushConfig returned for it.
+func (ac *A2ACapability) setPushConfig(taskID string, config a2aSchema.PushNotificationConfig, oauth *PushNotificationOAuth2Credentials) {
+	ac.pushTargetsMu.Lock()
+	ac.pushTargets[taskID] = &taskPushTarget{config: config, oauth: oauth}
+	ac.pushTargetsMu.Unlock()
+}
+
+// redactPushConfig returns config without the token and authentication credentials.
+func redactPushConfig(config a2aSchema.PushNotificationConfig) a2aSchema.PushNotificationConfig {
+	config.Token = nil
+	if config.Authentication != nil {
+		authentication := *config.Authentication
+		authentication.Credentials = nil
+		config.Authentication = &authentication
+	}
+	return config
+}
+
+// loadPushTask loads the task a push notification method refers to, which must belong to the caller.
+func (ac *A2ACapability) loadPushTask(msg *shared.Message, taskID string, logger *zap.Logger) (*a2aSchema.Task, error) {
+	task, err := ac.loadOwnedTask(msg.Context(), taskID, transport.GetUserId(msg.Session.GetParams()))
+	if err != nil {
+		logger.Warn("Failed to load task for push notification config", zap.Error(err))
+		var jsonRPCErr *a2aSchema.JSONRPCError
+		if errors.As(err, &jsonRPCErr) {
+			return nil, shared.NewJSONRPCError(jsonRPCErr) // Unknown tasks and tasks of other users are not found
+		}
+		return nil, &shared.JSONRPCError{Code: shared.JSONRPCErrorInternal, Message: "Failed to load task state"}
+	}
+	return task, nil
+}
+
+// removePushConfig drops the push notification config of a deleted task.
+func (ac *A2ACapability) removePushConfig(taskID string) {
+	ac.pushTargetsMu.Lock()
+	delete(ac.pushTargets, taskID)
+	ac.pushTargetsMu.Unlock()
+}
+
+// handleTaskPushNotificationSet handles `tasks/pushNotification/set`. The task must exist and belong
+// to the caller; tasks/send takes the config of a new task. Setting it on a task already waiting or
+// finished pushes its status right away.
+func (ac *A2ACapability) handleTaskPushNotificationSet(msg *shared.Message) (interface{}, error) {
+	logger := ac.logger.With(zap.String("sessionID", msg.Session.GetID()), zap.String("method", "tasks/pushNotification/set"))
+
+	var params a2aSchema.TaskPushNotificationConfig
+	if msg.Params == nil {
+		return nil, &shared.JSONRPCError{Code: shared.JSONRPCErrorInvalidParams, Message: "Missing params"}
+	}
+	if err := json.Unmarshal(*msg.Params, &params); err != nil {
+		logger.Error("Failed to unmarshal tasks/pushNotification/set params", zap.Error(err))
+		return nil, &shared.JSONRPCError{Code: shared.JSONRPCErrorInvalidParams, Message: err.Error()}
+	}
+	if params.ID == "" {
+		return nil, &shared.JSONRPCError{Code: shared.JSONRPCErrorInvalidParams, Message: "Task ID is required"}
+	}
+	logger = logger.With(zap.String("taskID", params.ID))
+	oauth, configErr := parsePushConfig(params.PushNotificationConfig)
+	if configErr != nil {
+		logger.Warn("Rejected push notification config", zap.String("reason", configErr.Message))
+		return nil, configErr
+	}
+	task, err := ac.loadPushTask(msg, params.ID, logger)
+	if err != nil {
+		return nil, err
+	}
+	ac.setPushConfig(params.ID, params.PushNotificationConfig, oauth)
+	logger.Debug("Push notification config set for task")
+
+	ac.notifyPush(task)
+	return &params, nil
+}
+
+// handleTaskPushNotificationGet handles `tasks/pushNotification/get`. The token and authentication
+// credentials of the config are not returned.
+func (ac *A2ACapability) handleTaskPushNotificationGet(msg *shared.Message) (interface{}, error) {
+	logger := ac.logger.With(zap.String("sessionID", msg.Session.GetID()), zap.String("method", "tasks/pushNotification/get"))
+
+	var params a2aSchema.TaskIdParams
+	if msg.Params == nil {
+		return nil, &shared.JSONRPCError{Code: shared.JSONRPCErrorInvalidParams, Message: "Missing params"}
+	}
+	if err := json.Unmarshal(*msg.Params, &params); err != nil {
+		logger.Error("Failed to unmarshal tasks/pushNotification/get params", zap.Error(err))
+		return nil, &shared.JSONRPCError{Code: shared.JSONRPCErrorInvalidParams, Message: err.Error()}
+	}
+	logger = logger.With(zap.String("taskID", params.ID))
+	if _, err := ac.loadPushTask(msg, params.ID, logger); err != nil {
+		return nil, err
+	}
+
+	ac.pushTargetsMu.Lock()
+	target, exists := ac.pushTargets[params.ID]
+	ac.pushTargetsMu.Unlock()
+	if !exists {
+		return nil, &shared.JSONRPCError{Code: shared.JSONRPCErrorInvalidParams, Message: fmt.Sprintf("No push notification config set for task %q", params.ID)}
+	}
+	return &a2aSchema.TaskPushNotificationConfig{ID: params.ID, PushNotificationConfig: redactPushConfig(target.config)}, nil
+}
+
+// notifyPush POSTs the status of a terminal or input-required task to its push notification URL
+// in the background. Each status is pushed once.
+func (ac *A2ACapability) notifyPush(task *a2aSchema.Task) {
+	if !isTerminalState(task.Status.State) && task.Status.State != a2aSchema.TaskStateInputRequired {
+		return
+	}
+	ac.pushTargetsMu.Lock()
+	target, exists := ac.pushTargets[task.ID]
+	ac.pushTargetsMu.Unlock()
+	if !exists {
+		return
+	}
+
+	pushed := string(task.Status.State) + "@" + task.Status.Timestamp.Format(time.RFC3339Nano)
+	target.mu.Lock()
+	if target.lastPushed == pushed {
+		target.mu.Unlock()
+		return
+	}
+	target.lastPushed = pushed
+	target.mu.Unlock()
+
+	body, err := json.Marshal(map[string]interface{}{
+		"jsonrpc": "2.0",
+		"method":  PushNotificationMethod,
+		"params": a2aSchema.TaskStatusUpdateEvent{
+			ID:     task.ID,
+			Status: task.Status,
+			Final:  isTerminalState(task.Status.State),
+		},
+	})
+	if err != nil {
+		ac.logger.Error("Failed to marshal push notification", zap.String("taskID", task.ID), zap.Error(err))
+		return
+	}
+	go ac.deliverPush(task.ID, target, body)
+}
+
+// deliverPush POSTs body to the push notification URL, retrying with exponential backoff. A 401
+// response drops the cached OAuth token so the next attempt fetches a new one.
+func (ac *A2ACapability) deliverPush(taskID string, target *taskPushTarget, body []byte) {
+	logger := ac.logger.With(zap.String("taskID", taskID), zap.String("pushURL", target.config.URL))
+	client := &http.Client{Timeout: 10 * time.Second}
+	backoff := ac.webhookBackoff
+
+	for attempt := 1; attempt <= webhookAttempts; attempt++ {
+		token, err := target.authToken(client)
+		if err == nil {
+			err = postPushNotification(client, target.config.URL, body, token)
+			if errors.Is(err, errPushUnauthorized) {
+				target.dropToken()
+			}
+		}
+		if err == nil {
+			logger.Debug("Push notification delivered", zap.Int("attempt", attempt))
+			return
+		}
+		if attempt == webhookAttempts {
+			logger.Error("Push notification delivery failed, giving up", zap.Int("attempts", attempt), zap.Error(err))
+			return
+		}
+		logger.Warn("Push notification delivery failed, retrying", zap.Int("attempt", attempt), zap.Duration("backoff", backoff), zap.Error(err))
+		time.Sleep(backoff)
+		backoff *= 2
+	}
+}
+
+// authToken returns the bearer token to send: the cached OAuth token, refreshed when expired,
+// or the token of the config.
+func (t *taskPushTarget) authToken(client *http.Client) (string, error) {
+	if t.oauth == nil {
+		if t.config.Token != nil {
+			return *t.config.Token, nil
+		}
+		return "", nil
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.token != "" && (t.tokenExpiry.IsZero() || time.Now().Before(t.tokenExpiry)) {
+		return t.token, nil
+	}
+	token, expiresIn, err := fetchOAuth2Token(client, t.oauth)
+	if err != nil {
+		return "", err
+	}
+	t.token = token
+	t.tokenExpiry = time.Time{}
+	if expiresIn > 0 {
+		t.tokenExpiry = time.Now().Add(expiresIn)
+	}
+	return token, nil
+}
+
+func (t *taskPushTarget) dropToken() {
+	t.mu.Lock()
+	t.token = ""
+	t.mu.Unlock()
+}
+
+// fetchOAuth2Token gets an access token with the client credentials grant.
+func fetchOAuth2Token(client *http.Client, credentials *PushNotificationOAuth2Credentials) (string, time.Duration, error) {
+	form := url.Values{"grant_type": {"client_credentials"}}
+	if credentials.Scope != "" {
+		form.Set("scope", credentials.Scope)
+	}
+	req, err := http.NewRequest(http.MethodPost, credentials.TokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", 0, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.SetBasicAuth(url.QueryEscape(credentials.ClientID), url.QueryEscape(credentials.ClientSecret))
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", 0, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return "", 0, fmt.Errorf("token endpoint responded with HTTP %d", resp.StatusCode)
+	}
+	var token struct {
+		AccessToken string `json:"access_token"`
+		ExpiresIn   int64  `json:"expires_in"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&token); err != nil {
+		return "", 0, fmt.Errorf("failed to decode token response: %w", err)
+	}
+	if token.AccessToken == "" {
+		return "", 0, errors.New("token endpoint returned no access token")
+	}
+	return token.AccessToken, time.Duration(token.ExpiresIn) * time.Second, nil
+}
+
+func postPushNotification(client *http.Client, pushURL string, body []byte, token string) error {
+	req, err := http.NewRequest(http.MethodPost, pushURL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusUnauthorized {
+		return errPushUnauthorized
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("push notification receiver responded with HTTP %d", resp.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,272 @@
+package a2a_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gate4ai/gate4ai/server/a2a"
+	"github.com/gate4ai/gate4ai/server/transport"
+	"github.com/gate4ai/gate4ai/shared"
+	a2aSchema "github.com/gate4ai/gate4ai/shared/a2a/2025-draft/schema"
+	"github.com/gate4ai/gate4ai/shared/config"
+	sharedtesting "github.com/gate4ai/gate4ai/shared/testing"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+type pushDelivery struct {
+	authorization string
+	payload       struct {
+		Method string                          `json:"method"`
+		Params a2aSchema.TaskStatusUpdateEvent `json:"params"`
+	}
+}
+
+// receivedNotification is a request recorded by a notification receiver.
+type receivedNotification struct {
+	header http.Header
+	body   []byte
+}
+
+// newNotificationReceiver answers the first requests with the given statuses and records the
+// rest. It serves both push notifications and webhooks.
+func newNotificationReceiver(t *testing.T, statuses ...int) (*httptest.Server, <-chan receivedNotification, *atomic.Int32) {
+	deliveries := make(chan receivedNotification, 4)
+	var attempts atomic.Int32
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if attempt := int(attempts.Add(1)); attempt <= len(statuses) {
+			w.WriteHeader(statuses[attempt-1])
+			return
+		}
+		body, err := io.ReadAll(r.Body)
+		assert.NoError(t, err)
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		deliveries <- receivedNotification{header: r.Header, body: body}
+	}))
+	t.Cleanup(receiver.Close)
+	return receiver, deliveries, &attempts
+}
+
+func newPushTestCapability(t *testing.T, finalState a2aSchema.TaskState) *a2a.A2ACapability {
+	manager, err := transport.NewManager(zap.NewNop(), config.NewInternalConfig())
+	require.NoError(t, err)
+	handler := func(ctx context.Context, task *a2aSchema.Task, updates chan<- a2a.A2AYieldUpdate, logger *zap.Logger) error {
+		updates <- a2a.A2AYieldUpdate{Status: &a2aSchema.TaskStatus{State: a2aSchema.TaskStateWorking}}
+		updates <- a2a.A2AYieldUpdate{Status: &a2aSchema.TaskStatus{State: finalState}}
+		return nil
+	}
+	return a2a.NewA2ACapability(zap.NewNop(), manager, a2a.NewInMemoryTaskStore(), handler, a2a.WithWebhookRetryBackoff(10*time.Millisecond))
+}
+
+func setPushConfig(t *testing.T, capability *a2a.A2ACapability, taskID string, pushConfig a2aSchema.PushNotificationConfig) {
+	result, err := capability.GetHandlers()["tasks/pushNotification/set"](sharedtesting.BuildMessage("tasks/pushNotification/set", a2aSchema.TaskPushNotificationConfig{
+		ID: taskID, PushNotificationConfig: pushConfig,
+	}))
+	sharedtesting.AssertJSONRPCSuccess[*a2aSchema.TaskPushNotificationConfig](t, result, err)
+}
+
+// sendPushTestTask runs a task, registering pushConfig for it if not nil.
+func sendPushTestTask(t *testing.T, capability *a2a.A2ACapability, taskID string, pushConfig *a2aSchema.PushNotificationConfig) {
+	result, err := capability.GetHandlers()["tasks/send"](sharedtesting.BuildMessage("tasks/send", a2aSchema.TaskSendParams{
+		ID:               taskID,
+		Message:          a2aSchema.Message{Role: "user", Parts: textParts("go")},
+		PushNotification: pushConfig,
+	}))
+	sharedtesting.AssertJSONRPCSuccess[*a2aSchema.Task](t, result, err)
+}
+
+func receivePush(t *testing.T, deliveries <-chan receivedNotification) pushDelivery {
+	select {
+	case received := <-deliveries:
+		delivery := pushDelivery{authorization: received.header.Get("Authorization")}
+		require.NoError(t, json.Unmarshal(received.body, &delivery.payload))
+		return delivery
+	case <-time.After(5 * time.Second):
+		t.Fatal("Push notification was not delivered")
+		return pushDelivery{}
+	}
+}
+
+func TestPushNotificationSetGet(t *testing.T) {
+	receiver, deliveries, _ := newNotificationReceiver(t)
+	capability := newPushTestCapability(t, a2aSchema.TaskStateCompleted)
+	get := func(taskID string) (interface{}, error) {
+		return capability.GetHandlers()["tasks/pushNotification/get"](sharedtesting.BuildMessage("tasks/pushNotification/get", a2aSchema.TaskIdParams{ID: taskID}))
+	}
+	pushConfig := a2aSchema.PushNotificationConfig{URL: receiver.URL, Token: shared.PointerTo("t0ken"), Authentication: &a2aSchema.AuthenticationInfo{
+		Schemes: []string{"bearer"}, Credentials: shared.PointerTo("s3cret"),
+	}}
+
+	// Configs can only be set on existing tasks
+	result, err := get("push-task")
+	sharedtesting.AssertJSONRPCError(t, result, err, a2aSchema.ErrorCodeTaskNotFound)
+	result, err = capability.GetHandlers()["tasks/pushNotification/set"](sharedtesting.BuildMessage("tasks/pushNotification/set", a2aSchema.TaskPushNotificationConfig{
+		ID: "push-task", PushNotificationConfig: pushConfig,
+	}))
+	sharedtesting.AssertJSONRPCError(t, result, err, a2aSchema.ErrorCodeTaskNotFound)
+
+	sendPushTestTask(t, capability, "push-task", nil)
+	result, err = get("push-task")
+	sharedtesting.AssertJSONRPCError(t, result, err, shared.JSONRPCErrorInvalidParams)
+
+	setPushConfig(t, capability, "push-task", pushConfig)
+	assert.Equal(t, "Bearer t0ken", receivePush(t, deliveries).authorization)
+	result, err = get("push-task")
+	stored := sharedtesting.AssertJSONRPCSuccess[*a2aSchema.TaskPushNotificationConfig](t, result, err)
+	assert.Equal(t, &a2aSchema.TaskPushNotificationConfig{ID: "push-task", PushNotificationConfig: a2aSchema.PushNotificationConfig{
+		URL: receiver.URL, Authentication: &a2aSchema.AuthenticationInfo{Schemes: []string{"bearer"}},
+	}}, stored, "The token and credentials are not returned")
+
+	for name, invalid := range map[string]a2aSchema.PushNotificationConfig{
+		"URL scheme": {URL: "ftp://example.com/push"},
+		"OAuth2 without credentials": {URL: "https://example.com/push", Authentication: &a2aSchema.AuthenticationInfo{
+			Schemes: []string{a2a.PushNotificationSchemeOAuth2},
+		}},
+		"OAuth2 token URL": {URL: "https://example.com/push", Authentication: &a2aSchema.AuthenticationInfo{
+			Schemes: []string{a2a.PushNotificationSchemeOAuth2}, Credentials: shared.PointerTo(`{"tokenUrl":"not a url"}`),
+		}},
+	} {
+		t.Run(name, func(t *testing.T) {
+			result, err := capability.GetHandlers()["tasks/pushNotification/set"](sharedtesting.BuildMessage("tasks/pushNotification/set", a2aSchema.TaskPushNotificationConfig{
+				ID: "push-task", PushNotificationConfig: invalid,
+			}))
+			sharedtesting.AssertJSONRPCError(t, result, err, shared.JSONRPCErrorInvalidParams)
+		})
+	}
+}
+
+func TestPushNotificationConfigRequiresOwnTask(t *testing.T) {
+	ownerReceiver, ownerDeliveries, _ := newNotificationReceiver(t)
+	otherReceiver, otherDeliveries, otherAttempts := newNotificationReceiver(t)
+	capability := newPushTestCapability(t, a2aSchema.TaskStateCompleted)
+	call := func(method string, userID string, params interface{}) (interface{}, error) {
+		msg := sharedtesting.BuildMessage(method, params)
+		msg.Session = userSession(userID+"-session", userID)
+		return capability.GetHandlers()[method](msg)
+	}
+
+	result, err := call("tasks/send", "owner", a2aSchema.TaskSendParams{
+		ID:               "push-task",
+		Message:          a2aSchema.Message{Role: "user", Parts: textParts("go")},
+		PushNotification: &a2aSchema.PushNotificationConfig{URL: ownerReceiver.URL},
+	})
+	sharedtesting.AssertJSONRPCSuccess[*a2aSchema.Task](t, result, err)
+	receivePush(t, ownerDeliveries)
+
+	result, err = call("tasks/pushNotification/get", "other", a2aSchema.TaskIdParams{ID: "push-task"})
+	sharedtesting.AssertJSONRPCError(t, result, err, a2aSchema.ErrorCodeTaskNotFound)
+	result, err = call("tasks/pushNotification/set", "other", a2aSchema.TaskPushNotificationConfig{
+		ID: "push-task", PushNotificationConfig: a2aSchema.PushNotificationConfig{URL: otherReceiver.URL},
+	})
+	sharedtesting.AssertJSONRPCError(t, result, err, a2aSchema.ErrorCodeTaskNotFound)
+	// Sending to the task of another user does not replace its config either
+	result, err = call("tasks/send", "other", a2aSchema.TaskSendParams{
+		ID:               "push-task",
+		Message:          a2aSchema.Message{Role: "user", Parts: textParts("go")},
+		PushNotification: &a2aSchema.PushNotificationConfig{URL: otherReceiver.URL},
+	})
+	sharedtesting.AssertJSONRPCError(t, result, err, shared.JSONRPCErrorInvalidParams)
+
+	result, err = call("tasks/pushNotification/get", "owner", a2aSchema.TaskIdParams{ID: "push-task"})
+	stored := sharedtesting.AssertJSONRPCSuccess[*a2aSchema.TaskPushNotificationConfig](t, result, err)
+	assert.Equal(t, ownerReceiver.URL, stored.PushNotificationConfig.URL)
+	time.Sleep(100 * time.Millisecond)
+	assert.Empty(t, otherDeliveries)
+	assert.Zero(t, otherAttempts.Load())
+}
+
+func TestPushNotificationDelivered(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		state    a2aSchema.TaskState
+		statuses []int
+	}{
+		{name: "completed", state: a2aSchema.TaskStateCompleted},
+		{name: "input required", state: a2aSchema.TaskStateInputRequired},
+		{name: "after retries", state: a2aSchema.TaskStateCompleted, statuses: []int{http.StatusBadGateway, http.StatusServiceUnavailable}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			receiver, deliveries, attempts := newNotificationReceiver(t, tc.statuses...)
+			capability := newPushTestCapability(t, tc.state)
+			sendPushTestTask(t, capability, "push-task", &a2aSchema.PushNotificationConfig{URL: receiver.URL, Token: shared.PointerTo("t0ken")})
+
+			delivery := receivePush(t, deliveries)
+			assert.Equal(t, "Bearer t0ken", delivery.authorization)
+			assert.Equal(t, a2a.PushNotificationMethod, delivery.payload.Method)
+			assert.Equal(t, "push-task", delivery.payload.Params.ID)
+			assert.Equal(t, tc.state, delivery.payload.Params.Status.State)
+			assert.Equal(t, tc.state == a2aSchema.TaskStateCompleted, delivery.payload.Params.Final)
+			assert.Equal(t, int32(len(tc.statuses)+1), attempts.Load())
+		})
+	}
+}
+
+func TestPushNotificationGivesUpAfterThreeAttempts(t *testing.T) {
+	receiver, deliveries, attempts := newNotificationReceiver(t, http.StatusInternalServerError, http.StatusInternalServerError, http.StatusInternalServerError, http.StatusInternalServerError)
+	capability := newPushTestCapability(t, a2aSchema.TaskStateFailed)
+	sendPushTestTask(t, capability, "push-task", &a2aSchema.PushNotificationConfig{URL: receiver.URL})
+
+	require.Eventually(t, func() bool { return attempts.Load() == 3 }, 5*time.Second, 10*time.Millisecond)
+	time.Sleep(100 * time.Millisecond)
+	assert.Equal(t, int32(3), attempts.Load())
+	assert.Empty(t, deliveries)
+}
+
+func TestPushNotificationOAuth2TokenRefresh(t *testing.T) {
+	var issued atomic.Int32
+	tokenServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		clientID, secret, ok := r.BasicAuth()
+		assert.True(t, ok)
+		assert.Equal(t, "client", clientID)
+		assert.Equal(t, "s3cret", secret)
+		assert.NoError(t, r.ParseForm())
+		assert.Equal(t, "client_credentials", r.PostForm.Get("grant_type"))
+		assert.Equal(t, "push", r.PostForm.Get("scope"))
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"access_token": fmt.Sprintf("token-%d", issued.Add(1)), "expires_in": 3600})
+	}))
+	defer tokenServer.Close()
+	// The receiver rejects the first token, as if it had been revoked
+	receiver, deliveries, _ := newNotificationReceiver(t, http.StatusUnauthorized)
+
+	capability := newPushTestCapability(t, a2aSchema.TaskStateCompleted)
+	credentials, err := json.Marshal(a2a.PushNotificationOAuth2Credentials{TokenURL: tokenServer.URL, ClientID: "client", ClientSecret: "s3cret", Scope: "push"})
+	require.NoError(t, err)
+	sendPushTestTask(t, capability, "push-task", &a2aSchema.PushNotificationConfig{URL: receiver.URL, Authentication: &a2aSchema.AuthenticationInfo{
+		Schemes: []string{a2a.PushNotificationSchemeOAuth2}, Credentials: shared.PointerTo(string(credentials)),
+	}})
+
+	delivery := receivePush(t, deliveries)
+	assert.Equal(t, "Bearer token-2", delivery.authorization)
+	assert.Equal(t, int32(2), issued.Load())
+}
+
+func TestPushNotificationConfigIsPerTask(t *testing.T) {
+	firstReceiver, firstDeliveries, _ := newNotificationReceiver(t)
+	secondReceiver, secondDeliveries, _ := newNotificationReceiver(t)
+	capability := newPushTestCapability(t, a2aSchema.TaskStateCompleted)
+	sendPushTestTask(t, capability, "first-task", &a2aSchema.PushNotificationConfig{URL: firstReceiver.URL, Token: shared.PointerTo("first")})
+	assert.Equal(t, "first-task", receivePush(t, firstDeliveries).payload.Params.ID)
+
+	sendPushTestTask(t, capability, "second-task", &a2aSchema.PushNotificationConfig{URL: secondReceiver.URL, Token: shared.PointerTo("second")})
+	delivery := receivePush(t, secondDeliveries)
+	assert.Equal(t, "Bearer second", delivery.authorization)
+	assert.Equal(t, "second-task", delivery.payload.Params.ID)
+
+	sendPushTestTask(t, capability, "unconfigured-task", nil)
+	time.Sleep(100 * time.Millisecond)
+	assert.Empty(t, firstDeliveries)
+	assert.Empty(t, secondDeliveries)
+
+	require.NoError(t, capability.DeleteTask(context.Background(), "second-task"))
+	result, err := capability.GetHandlers()["tasks/pushNotification/get"](sharedtesting.BuildMessage("tasks/pushNotification/get", a2aSchema.TaskIdParams{ID: "second-task"}))
+	sharedtesting.AssertJSONRPCError(t, result, err, a2aSchema.ErrorCodeTaskNotFound)
+}
@@ -0,0 +1,83 @@
+package a2a
+
+import (
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+	"golang.org/x/time/rate"
+)
+
+// taskLimiterIdleTimeout is how long a user's limiter is kept after its last task creation.
+const taskLimiterIdleTimeout = 10 * time.Minute
+
+// WithTaskCreationRateLimit limits how many new tasks each user can create: rps tasks per
+// second with bursts of up to burst tasks. Anonymous callers share one limit, as they cannot
+// be told apart. Continuing an existing task is not limited. Requests over the limit fail with
+// ErrorCodeRateLimitExceeded.
+func WithTaskCreationRateLimit(rps float64, burst int) A2AOption {
+	return func(ac *A2ACapability) {
+		ac.taskRateLimiter = &taskRateLimiter{
+			limit:    rate.Limit(rps),
+			burst:    burst,
+			limiters: make(map[string]*userTaskLimiter),
+		}
+	}
+}
+
+// taskRateLimiter tracks a token bucket per user ID. The key is the authenticated user rather
+// than a session, since clients choose their A2A session IDs freely.
+type taskRateLimiter struct {
+	limit    rate.Limit
+	burst    int
+	mu       sync.Mutex
+	limiters map[string]*userTaskLimiter
+}
+
+type userTaskLimiter struct {
+	limiter  *rate.Limiter
+	lastSeen time.Time
+}
+
+// allow reports whether the user may create another task now.
+func (l *taskRateLimiter) allow(userID string) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	entry, exists := l.limiters[userID]
+	if !exists {
+		entry = &userTaskLimiter{limiter: rate.NewLimiter(l.limit, l.burst)}
+		l.limiters[userID] = entry
+	}
+	entry.lastSeen = time.Now()
+	return entry.limiter.Allow()
+}
+
+// evictIdle drops limiters of users that created no task since idleBefore.
+func (l *taskRateLimiter) evictIdle(idleBefore time.Time) int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	evicted := 0
+	for userID, entry := range l.limiters {
+		if entry.lastSeen.Before(idleBefore) {
+			delete(l.limiters, userID)
+			evicted++
+		}
+	}
+	return evicted
+}
+
+// startTaskLimiterCleanup periodically evicts limiters of idle users until the capability is closed.
+func (ac *A2ACapability) startTaskLimiterCleanup() {
+	ticker := time.NewTicker(taskLimiterIdleTimeout / 2)
+	defer ticker.Stop()
+	for {
+		select {
+		case now := <-ticker.C:
+			if evicted := ac.taskRateLimiter.evictIdle(now.Add(-taskLimiterIdleTimeout)); evicted > 0 {
+				ac.logger.Debug("Evicted idle task creation limiters", zap.Int("count", evicted))
+			}
+		case <-ac.done:
+			return
+		}
+	}
+}
@@ -0,0 +1,218 @@
+package a2a
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	a2aSchema "github.com/gate4ai/gate4ai/shared/a2a/2025-draft/schema"
+	"github.com/redis/go-redis/v9"
+)
+
+// DefaultRedisKeyPrefix prefixes the keys RedisTaskStore writes.
+const DefaultRedisKeyPrefix = "gate4ai:a2a:"
+
+// redisScanBatch is the COUNT hint of the SCAN calls of RedisTaskStore.List.
+const redisScanBatch = 100
+
+// RedisStoreOption configures a RedisTaskStore.
+type RedisStoreOption func(*RedisTaskStore)
+
+// WithRedisKeyPrefix sets the prefix of the store's keys, so several deployments can share a Redis.
+func WithRedisKeyPrefix(prefix string) RedisStoreOption {
+	return func(s *RedisTaskStore) {
+		s.prefix = prefix
+	}
+}
+
+// WithRedisTaskTTL expires tasks ttl after they were last saved. By default tasks do not expire.
+func WithRedisTaskTTL(ttl time.Duration) RedisStoreOption {
+	return func(s *RedisTaskStore) {
+		s.ttl = ttl
+	}
+}
+
+// RedisTaskStore implements TaskStore in Redis, so tasks survive restarts and are shared by
+// every instance using the same Redis. Each task is stored as JSON under its ID; snapshots
+// taken by SnapshotBeforeUpdate are kept in a hash per task. Updates of a single task are
+// atomic, concurrent Saves of the same task keep the last one.
+type RedisTaskStore struct {
+	client redis.UniversalClient
+	prefix string
+	ttl    time.Duration
+}
+
+var _ TaskStore = (*RedisTaskStore)(nil)
+
+// NewRedisTaskStore creates a RedisTaskStore using client.
+func NewRedisTaskStore(client redis.UniversalClient, options ...RedisStoreOption) *RedisTaskStore {
+	s := &RedisTaskStore{client: client, prefix: DefaultRedisKeyPrefix}
+	for _, option := range options {
+		option(s)
+	}
+	return s
+}
+
+func (s *RedisTaskStore) taskKey(taskID string) string {
+	return s.prefix + "task:" + taskID
+}
+
+func (s *RedisTaskStore) snapshotsKey(taskID string) string {
+	return s.prefix + "snapshots:" + taskID
+}
+
+func (s *RedisTaskStore) snapshotVersionKey(taskID string) string {
+	return s.prefix + "snapshot-version:" + taskID
+}
+
+// Save stores the task, expiring it after the TTL set by WithRedisTaskTTL. Without one it
+// keeps the expiry set by SaveWithTTL, if any.
+func (s *RedisTaskStore) Save(ctx context.Context, task *a2aSchema.Task) error {
+	ttl := s.ttl
+	if ttl <= 0 {
+		ttl = redis.KeepTTL
+	}
+	return s.SaveWithTTL(ctx, task, ttl)
+}
+
+// SaveWithTTL stores the task and expires it after ttl; 0 removes its expiry.
+func (s *RedisTaskStore) SaveWithTTL(ctx context.Context, task *a2aSchema.Task, ttl time.Duration) error {
+	data, err := json.Marshal(task)
+	if err != nil {
+		return fmt.Errorf("failed to marshal task %s: %w", task.ID, err)
+	}
+	if err := s.client.Set(ctx, s.taskKey(task.ID), data, ttl).Err(); err != nil {
+		return fmt.Errorf("failed to save task %s: %w", task.ID, err)
+	}
+	return nil
+}
+
+// Load returns the task, a TaskNotFoundError if it does not exist or expired, and an
+// internal JSON-RPC error if the stored JSON cannot be decoded.
+func (s *RedisTaskStore) Load(ctx context.Context, taskID string) (*a2aSchema.Task, error) {
+	data, err := s.client.Get(ctx, s.taskKey(taskID)).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return nil, a2aSchema.NewTaskNotFoundError(taskID)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to load task %s: %w", taskID, err)
+	}
+	return decodeRedisTask(taskID, data)
+}
+
+func decodeRedisTask(taskID string, data []byte) (*a2aSchema.Task, error) {
+	var task a2aSchema.Task
+	if err := json.Unmarshal(data, &task); err != nil {
+		return nil, &a2aSchema.JSONRPCError{Code: a2aSchema.ErrorInternalError, Message: fmt.Sprintf("Failed to decode stored task %s: %v", taskID, err)}
+	}
+	return &task, nil
+}
+
+// Delete removes the task and its snapshots.
+func (s *RedisTaskStore) Delete(ctx context.Context, taskID string) error {
+	return s.client.Del(ctx, s.taskKey(taskID), s.snapshotsKey(taskID), s.snapshotVersionKey(taskID)).Err()
+}
+
+// List scans every task of the store, so its cost grows with the number of stored tasks.
+func (s *RedisTaskStore) List(ctx context.Context, filter TaskFilter) ([]*a2aSchema.Task, string, error) {
+	taskPrefix := s.taskKey("")
+	tasks := make([]*a2aSchema.Task, 0)
+	var cursor uint64
+	for {
+		keys, next, err := s.client.Scan(ctx, cursor, taskPrefix+"*", redisScanBatch).Result()
+		if err != nil {
+			return nil, "", fmt.Errorf("failed to scan tasks: %w", err)
+		}
+		if len(keys) > 0 {
+			values, err := s.client.MGet(ctx, keys...).Result()
+			if err != nil {
+				return nil, "", fmt.Errorf("failed to load tasks: %w", err)
+			}
+			for i, value := range values {
+				data, ok := value.(string)
+				if !ok {
+					continue // Expired or deleted since the scan
+				}
+				task, err := decodeRedisTask(keys[i][len(taskPrefix):], []byte(data))
+				if err != nil {
+					return nil, "", err
+				}
+				tasks = append(tasks, task)
+			}
+		}
+		if next == 0 {
+			break
+		}
+		cursor = next
+	}
+	return listTasks(tasks, filter)
+}
+
+// redisSnapshotScript copies the task into the snapshot hash under a new version, drops the
+// snapshot that falls out of the last MaxTaskSnapshots and gives the snapshot keys the task's
+// expiry. It returns -1 if the task does not exist.
+var redisSnapshotScript = redis.NewScript(`
+local task = redis.call('GET', KEYS[1])
+if not task then
+	return -1
+end
+local version = redis.call('INCR', KEYS[3])
+redis.call('HSET', KEYS[2], version, task)
+redis.call('HDEL', KEYS[2], version - tonumber(ARGV[1]))
+local ttl = redis.call('PTTL', KEYS[1])
+if ttl > 0 then
+	redis.call('PEXPIRE', KEYS[2], ttl)
+	redis.call('PEXPIRE', KEYS[3], ttl)
+end
+return version
+`)
+
+// redisRollbackScript restores the snapshot with version ARGV[1], keeping the task's expiry,
+// and drops newer snapshots. It returns -1 if the task does not exist and 0 if the snapshot does not.
+var redisRollbackScript = redis.NewScript(`
+if redis.call('EXISTS', KEYS[1]) == 0 then
+	return -1
+end
+local snapshot = redis.call('HGET', KEYS[2], ARGV[1])
+if not snapshot then
+	return 0
+end
+redis.call('SET', KEYS[1], snapshot, 'KEEPTTL')
+for _, version in ipairs(redis.call('HKEYS', KEYS[2])) do
+	if tonumber(version) > tonumber(ARGV[1]) then
+		redis.call('HDEL', KEYS[2], version)
+	end
+end
+return 1
+`)
+
+// SnapshotBeforeUpdate copies the stored task, keeping the latest MaxTaskSnapshots copies.
+func (s *RedisTaskStore) SnapshotBeforeUpdate(ctx context.Context, taskID string) (int, error) {
+	keys := []string{s.taskKey(taskID), s.snapshotsKey(taskID), s.snapshotVersionKey(taskID)}
+	version, err := redisSnapshotScript.Run(ctx, s.client, keys, MaxTaskSnapshots).Int()
+	if err != nil {
+		return 0, fmt.Errorf("failed to snapshot task %s: %w", taskID, err)
+	}
+	if version < 0 {
+		return 0, a2aSchema.NewTaskNotFoundError(taskID)
+	}
+	return version, nil
+}
+
+// RollbackToSnapshot restores the snapshot with snapshotVersion and drops the snapshots taken after it.
+func (s *RedisTaskStore) RollbackToSnapshot(ctx context.Context, taskID string, snapshotVersion int) error {
+	keys := []string{s.taskKey(taskID), s.snapshotsKey(taskID)}
+	result, err := redisRollbackScript.Run(ctx, s.client, keys, snapshotVersion).Int()
+	if err != nil {
+		return fmt.Errorf("failed to roll back task %s: %w", taskID, err)
+	}
+	switch result {
+	case -1:
+		return a2aSchema.NewTaskNotFoundError(taskID)
+	case 0:
+		return fmt.Errorf("snapshot %d of task %s does not exist or was dropped", snapshotVersion, taskID)
+	}
+	return nil
+}
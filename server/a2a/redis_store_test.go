@@ -0,0 +1,118 @@
+package a2a_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/gate4ai/gate4ai/server/a2a"
+	a2aSchema "github.com/gate4ai/gate4ai/shared/a2a/2025-draft/schema"
+	sharedtesting "github.com/gate4ai/gate4ai/shared/testing"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newRedisTestStore(t *testing.T, options ...a2a.RedisStoreOption) (*a2a.RedisTaskStore, *miniredis.Miniredis) {
+	mr := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { client.Close() })
+	return a2a.NewRedisTaskStore(client, options...), mr
+}
+
+func TestRedisTaskStoreSaveLoadDelete(t *testing.T) {
+	ctx := context.Background()
+	store, mr := newRedisTestStore(t)
+
+	_, err := store.Load(ctx, "missing")
+	sharedtesting.AssertJSONRPCError(t, nil, err, a2aSchema.ErrorCodeTaskNotFound)
+
+	task := &a2aSchema.Task{
+		ID:        "redis-task",
+		SessionID: "session",
+		Status:    a2aSchema.TaskStatus{State: a2aSchema.TaskStateWorking, Timestamp: time.Date(2025, 5, 1, 12, 0, 0, 0, time.UTC)},
+		History:   []a2aSchema.Message{{Role: "user", Parts: textParts("hello")}},
+		Artifacts: []a2aSchema.Artifact{{Parts: textParts("result")}},
+	}
+	require.NoError(t, store.Save(ctx, task))
+	loaded, err := store.Load(ctx, task.ID)
+	require.NoError(t, err)
+	assert.Equal(t, task, loaded)
+	assert.True(t, mr.Exists(a2a.DefaultRedisKeyPrefix+"task:redis-task"))
+
+	require.NoError(t, store.Delete(ctx, task.ID))
+	_, err = store.Load(ctx, task.ID)
+	sharedtesting.AssertJSONRPCError(t, nil, err, a2aSchema.ErrorCodeTaskNotFound)
+}
+
+func TestRedisTaskStoreCorruptTask(t *testing.T) {
+	store, mr := newRedisTestStore(t, a2a.WithRedisKeyPrefix("test:"))
+	require.NoError(t, mr.Set("test:task:corrupt", "{not json"))
+
+	_, err := store.Load(context.Background(), "corrupt")
+	sharedtesting.AssertJSONRPCError(t, nil, err, a2aSchema.ErrorInternalError)
+	_, _, err = store.List(context.Background(), a2a.TaskFilter{})
+	sharedtesting.AssertJSONRPCError(t, nil, err, a2aSchema.ErrorInternalError)
+}
+
+func TestRedisTaskStoreTTL(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("store TTL", func(t *testing.T) {
+		store, mr := newRedisTestStore(t, a2a.WithRedisTaskTTL(time.Minute))
+		require.NoError(t, store.Save(ctx, &a2aSchema.Task{ID: "expiring"}))
+		mr.FastForward(30 * time.Second)
+		require.NoError(t, store.Save(ctx, &a2aSchema.Task{ID: "expiring"})) // Refreshes the TTL
+		mr.FastForward(45 * time.Second)
+		_, err := store.Load(ctx, "expiring")
+		require.NoError(t, err)
+		mr.FastForward(time.Minute)
+		_, err = store.Load(ctx, "expiring")
+		sharedtesting.AssertJSONRPCError(t, nil, err, a2aSchema.ErrorCodeTaskNotFound)
+	})
+
+	t.Run("task TTL", func(t *testing.T) {
+		store, mr := newRedisTestStore(t)
+		require.NoError(t, store.SaveWithTTL(ctx, &a2aSchema.Task{ID: "short"}, time.Minute))
+		require.NoError(t, store.Save(ctx, &a2aSchema.Task{ID: "short"})) // Keeps the task's TTL
+		require.NoError(t, store.Save(ctx, &a2aSchema.Task{ID: "forever"}))
+		mr.FastForward(2 * time.Minute)
+		_, err := store.Load(ctx, "short")
+		sharedtesting.AssertJSONRPCError(t, nil, err, a2aSchema.ErrorCodeTaskNotFound)
+		_, err = store.Load(ctx, "forever")
+		assert.NoError(t, err)
+	})
+}
+
+func TestRedisTaskStoreList(t *testing.T) {
+	base := time.Date(2025, 5, 1, 12, 0, 0, 0, time.UTC)
+	store, _ := newRedisTestStore(t)
+	seedTasks(t, store, base)
+
+	tasks, next, err := store.List(context.Background(), a2a.TaskFilter{States: []a2aSchema.TaskState{a2aSchema.TaskStateWorking}, Limit: 3})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"task-01", "task-06", "task-11"}, taskIDs(tasks))
+	tasks, next, err = store.List(context.Background(), a2a.TaskFilter{States: []a2aSchema.TaskState{a2aSchema.TaskStateWorking}, Limit: 3, Cursor: next})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"task-16"}, taskIDs(tasks))
+	assert.Empty(t, next)
+}
+
+func TestRedisTaskStoreSharedBetweenInstances(t *testing.T) {
+	ctx := context.Background()
+	first, mr := newRedisTestStore(t)
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	defer client.Close()
+	second := a2a.NewRedisTaskStore(client)
+
+	require.NoError(t, first.Save(ctx, &a2aSchema.Task{ID: "shared", Status: a2aSchema.TaskStatus{State: a2aSchema.TaskStateSubmitted}}))
+	version, err := second.SnapshotBeforeUpdate(ctx, "shared")
+	require.NoError(t, err)
+	require.NoError(t, first.Save(ctx, &a2aSchema.Task{ID: "shared", Status: a2aSchema.TaskStatus{State: a2aSchema.TaskStateFailed}}))
+	require.NoError(t, second.RollbackToSnapshot(ctx, "shared", version))
+
+	task, err := first.Load(ctx, "shared")
+	require.NoError(t, err)
+	assert.Equal(t, a2aSchema.TaskStateSubmitted, task.Status.State)
+}
@@ -0,0 +1,170 @@
+package a2a
+
+import (
+	"sync"
+
+	"github.com/gate4ai/gate4ai/shared"
+	a2aSchema "github.com/gate4ai/gate4ai/shared/a2a/2025-draft/schema"
+	"go.uber.org/zap"
+)
+
+// WithReplayBufferSize keeps the last n stream events of each tasks/sendSubscribe run, so a
+// client resuming with tasks/resubscribe and lastEventId gets the events it missed, followed
+// by the live events of the run. Buffers of finished runs are kept until the task is deleted
+// or run again.
+func WithReplayBufferSize(n int) A2AOption {
+	return func(ac *A2ACapability) {
+		ac.replayBufferSize = n
+	}
+}
+
+// ReplayBuffer numbers the stream events of one task run and keeps the last ones in a ring.
+type ReplayBuffer struct {
+	mu      sync.Mutex
+	lastID  uint64
+	ring    []shared.A2AStreamEvent
+	head    int // Index of the oldest event
+	size    int
+	closed  bool
+	changed chan struct{} // Closed and replaced when an event is added or the buffer is closed
+}
+
+// NewReplayBuffer creates a buffer keeping the last capacity events.
+func NewReplayBuffer(capacity int) *ReplayBuffer {
+	return &ReplayBuffer{ring: make([]shared.A2AStreamEvent, capacity), changed: make(chan struct{})}
+}
+
+// Add assigns the next event ID to event, buffers a copy and returns the ID.
+func (b *ReplayBuffer) Add(event *shared.A2AStreamEvent) uint64 {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.lastID++
+	event.ID = b.lastID
+	if len(b.ring) > 0 {
+		if b.size < len(b.ring) {
+			b.ring[(b.head+b.size)%len(b.ring)] = *event
+			b.size++
+		} else {
+			b.ring[b.head] = *event
+			b.head = (b.head + 1) % len(b.ring)
+		}
+	}
+	close(b.changed)
+	b.changed = make(chan struct{})
+	return b.lastID
+}
+
+// Since returns the buffered events after lastEventID, oldest first. complete is false if
+// some of those events were already evicted.
+func (b *ReplayBuffer) Since(lastEventID uint64) (events []shared.A2AStreamEvent, complete bool) {
+	events, complete, _, _ = b.since(lastEventID)
+	return events, complete
+}
+
+// since is Since that also returns whether the run ended and a channel closed on the next change.
+func (b *ReplayBuffer) since(lastEventID uint64) (events []shared.A2AStreamEvent, complete bool, closed bool, changed <-chan struct{}) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	complete = lastEventID >= b.lastID-uint64(b.size)
+	for i := 0; i < b.size; i++ {
+		if event := b.ring[(b.head+i)%len(b.ring)]; event.ID > lastEventID {
+			events = append(events, event)
+		}
+	}
+	return events, complete, b.closed, b.changed
+}
+
+// Close marks the end of the run; clients following the buffer stop after its last event.
+func (b *ReplayBuffer) Close() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if !b.closed {
+		b.closed = true
+		close(b.changed)
+	}
+}
+
+// newReplayBuffer replaces the replay buffer of a task for a new run. It returns nil when
+// replay is disabled.
+func (ac *A2ACapability) newReplayBuffer(taskID string) *ReplayBuffer {
+	if ac.replayBufferSize <= 0 {
+		return nil
+	}
+	buffer := NewReplayBuffer(ac.replayBufferSize)
+	ac.replayBuffersMu.Lock()
+	if previous, ok := ac.replayBuffers[taskID]; ok {
+		previous.Close()
+	}
+	ac.replayBuffers[taskID] = buffer
+	ac.replayBuffersMu.Unlock()
+	return buffer
+}
+
+// replayBuffer returns the replay buffer of the task's latest run, or nil.
+func (ac *A2ACapability) replayBuffer(taskID string) *ReplayBuffer {
+	ac.replayBuffersMu.Lock()
+	defer ac.replayBuffersMu.Unlock()
+	return ac.replayBuffers[taskID]
+}
+
+// removeReplayBuffer drops the replay buffer of a deleted task.
+func (ac *A2ACapability) removeReplayBuffer(taskID string) {
+	ac.replayBuffersMu.Lock()
+	buffer, ok := ac.replayBuffers[taskID]
+	delete(ac.replayBuffers, taskID)
+	ac.replayBuffersMu.Unlock()
+	if ok {
+		buffer.Close()
+	}
+}
+
+// replayEvents sends the buffered events after lastEventID to session. It returns the ID of the
+// last event sent and whether it was final.
+func replayEvents(buffer *ReplayBuffer, session shared.ISession, lastEventID uint64, logger *zap.Logger) (uint64, bool, error) {
+	events, complete := buffer.Since(lastEventID)
+	if !complete {
+		logger.Warn("Some events after the last event ID were evicted from the replay buffer", zap.Uint64("lastEventId", lastEventID))
+	}
+	for i := range events {
+		if err := session.SendA2AStreamEvent(&events[i]); err != nil {
+			return lastEventID, false, err
+		}
+		lastEventID = events[i].ID
+		if events[i].Final {
+			return lastEventID, true, nil
+		}
+	}
+	logger.Debug("Replayed buffered stream events", zap.Int("count", len(events)))
+	return lastEventID, false, nil
+}
+
+// followEvents streams the events added to buffer after lastEventID until the final event,
+// the end of the run or a failed send.
+func followEvents(buffer *ReplayBuffer, session shared.ISession, lastEventID uint64, logger *zap.Logger) {
+	for {
+		events, _, closed, changed := buffer.since(lastEventID)
+		for i := range events {
+			if err := session.SendA2AStreamEvent(&events[i]); err != nil {
+				logger.Debug("Stopped following task events", zap.Error(err))
+				return
+			}
+			lastEventID = events[i].ID
+			if events[i].Final {
+				return
+			}
+		}
+		if closed {
+			return
+		}
+		<-changed
+	}
+}
+
+// finalStatusEvent is the event ending a resumed stream of a task that already finished.
+func finalStatusEvent(task *a2aSchema.Task) *shared.A2AStreamEvent {
+	return &shared.A2AStreamEvent{
+		Type:   "status",
+		Status: &a2aSchema.TaskStatusUpdateEvent{ID: task.ID, Status: task.Status, Final: true},
+		Final:  true,
+	}
+}
@@ -0,0 +1,58 @@
+package a2a_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"testing"
+
+	"github.com/gate4ai/gate4ai/server/a2a"
+	"github.com/gate4ai/gate4ai/shared"
+	a2aSchema "github.com/gate4ai/gate4ai/shared/a2a/2025-draft/schema"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestResubscribeReplaysEventsAfterLastEventID(t *testing.T) {
+	// Streams nine artifacts, then the final status: ten events
+	handler := func(ctx context.Context, task *a2aSchema.Task, updates chan<- a2a.A2AYieldUpdate, logger *zap.Logger) error {
+		for i := 1; i <= 9; i++ {
+			updates <- a2a.A2AYieldUpdate{Artifact: &a2aSchema.Artifact{Index: i, Parts: []a2aSchema.Part{{Type: shared.PointerTo("text"), Text: shared.PointerTo(fmt.Sprintf("chunk %d", i))}}}}
+		}
+		updates <- a2a.A2AYieldUpdate{Status: &a2aSchema.TaskStatus{State: a2aSchema.TaskStateCompleted}}
+		return nil
+	}
+	server := newA2ATestServer(t, handler, a2a.WithReplayBufferSize(16))
+
+	recorded := postA2A(t, server.URL, "tasks/sendSubscribe", a2aSchema.TaskSendParams{
+		ID:      "replay-task",
+		Message: a2aSchema.Message{Role: "user", Parts: []a2aSchema.Part{{Type: shared.PointerTo("text"), Text: shared.PointerTo("go")}}},
+	})
+	var streamed []rawSSEEvent
+	for _, event := range recorded {
+		if event.ID != "" { // The initial task snapshot has no event ID
+			streamed = append(streamed, event)
+		}
+	}
+	require.Len(t, streamed, 10)
+	for i, event := range streamed {
+		assert.Equal(t, strconv.Itoa(i+1), event.ID, "stream events are numbered by the replay buffer")
+	}
+
+	// The first stream is over; a new connection resumes after event 5
+	replayed := postA2A(t, server.URL, "tasks/resubscribe", a2aSchema.TaskResubscribeParams{
+		TaskQueryParams: a2aSchema.TaskQueryParams{ID: "replay-task"},
+		LastEventID:     shared.PointerTo(uint64(5)),
+	})
+	require.Len(t, replayed, 5)
+	for i, event := range replayed {
+		assert.Equal(t, strconv.Itoa(i+6), event.ID)
+		assert.JSONEq(t, string(streamed[i+5].Result), string(event.Result))
+	}
+	var final a2aSchema.TaskStatusUpdateEvent
+	require.NoError(t, json.Unmarshal(replayed[4].Result, &final))
+	assert.True(t, final.Final)
+	assert.Equal(t, a2aSchema.TaskStateCompleted, final.Status.State)
+}
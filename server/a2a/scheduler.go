@@ -0,0 +1,69 @@
+package a2a
+
+import (
+	"context"
+	"time"
+
+	a2aSchema "github.com/gate4ai/gate4ai/shared/a2a/2025-draft/schema"
+	"go.uber.org/zap"
+)
+
+// WithScheduler enables deferred tasks: tasks/send with ScheduledAt in the future stores
+// the task as submitted, and a background scheduler polling the task store every
+// pollInterval starts it once it is due. Without a scheduler such requests are rejected.
+func WithScheduler(pollInterval time.Duration) A2AOption {
+	return func(ac *A2ACapability) {
+		ac.schedulerInterval = pollInterval
+	}
+}
+
+// startScheduler periodically starts deferred tasks that are due, until the capability is closed.
+func (ac *A2ACapability) startScheduler() {
+	ticker := time.NewTicker(ac.schedulerInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case now := <-ticker.C:
+			ac.startDueTasks(now)
+		case <-ac.done:
+			return
+		}
+	}
+}
+
+// startDueTasks starts every submitted task whose ScheduledAt is not after now and whose
+// handler is not running yet.
+func (ac *A2ACapability) startDueTasks(now time.Time) {
+	tasks, _, err := ac.taskStore.List(context.Background(), TaskFilter{States: []a2aSchema.TaskState{a2aSchema.TaskStateSubmitted}})
+	if err != nil {
+		ac.logger.Error("Failed to list submitted tasks for scheduler", zap.Error(err))
+		return
+	}
+	for _, task := range tasks {
+		if task.ScheduledAt == nil || task.ScheduledAt.After(now) || ac.isHandlerRunning(task.ID) {
+			continue
+		}
+		logger := ac.logger.With(zap.String("taskID", task.ID), zap.String("sessionID", task.SessionID))
+		slot, busyErr := ac.reserveWorker()
+		if busyErr != nil {
+			logger.Debug("No free worker for scheduled task, retrying on the next poll")
+			continue
+		}
+		// Clear ScheduledAt before starting so the next poll does not start the task again
+		task.ScheduledAt = nil
+		if err := ac.saveTask(context.Background(), task); err != nil {
+			logger.Error("Failed to save scheduled task before start", zap.Error(err))
+			slot.release()
+			continue
+		}
+		logger.Info("Starting scheduled task")
+		slot.run(func() {
+			ctx, span := ac.startTaskSpan(context.Background(), task.ID, task.SessionID)
+			defer span.End()
+			if _, jsonErr := ac.runTask(ctx, task, logger); jsonErr != nil {
+				setSpanError(span, jsonErr)
+				logger.Warn("Scheduled task finished with an error", zap.Int("code", jsonErr.Code), zap.String("message", jsonErr.Message))
+			}
+		})
+	}
+}
@@ -0,0 +1,78 @@
+package a2a_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/gate4ai/gate4ai/server/a2a"
+	"github.com/gate4ai/gate4ai/server/transport"
+	"github.com/gate4ai/gate4ai/shared"
+	a2aSchema "github.com/gate4ai/gate4ai/shared/a2a/2025-draft/schema"
+	"github.com/gate4ai/gate4ai/shared/config"
+	sharedtesting "github.com/gate4ai/gate4ai/shared/testing"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestScheduledTaskStartsWhenDue(t *testing.T) {
+	started := make(chan time.Time, 1)
+	handler := func(ctx context.Context, task *a2aSchema.Task, updates chan<- a2a.A2AYieldUpdate, logger *zap.Logger) error {
+		started <- time.Now()
+		updates <- a2a.A2AYieldUpdate{Status: &a2aSchema.TaskStatus{State: a2aSchema.TaskStateCompleted}}
+		return nil
+	}
+	logger := zap.NewNop()
+	manager, err := transport.NewManager(logger, config.NewInternalConfig())
+	require.NoError(t, err)
+	handlers := a2a.NewA2ACapability(logger, manager, a2a.NewInMemoryTaskStore(), handler, a2a.WithScheduler(20*time.Millisecond)).GetHandlers()
+
+	sentAt := time.Now()
+	scheduledAt := sentAt.Add(200 * time.Millisecond)
+	result, err := handlers["tasks/send"](sharedtesting.BuildMessage("tasks/send", a2aSchema.TaskSendParams{
+		ID:          "scheduled-task",
+		Message:     a2aSchema.Message{Role: "user", Parts: []a2aSchema.Part{{Type: shared.PointerTo("text"), Text: shared.PointerTo("later")}}},
+		ScheduledAt: &scheduledAt,
+	}))
+	sent := sharedtesting.AssertJSONRPCSuccess[*a2aSchema.Task](t, result, err)
+	assert.Equal(t, a2aSchema.TaskStateSubmitted, sent.Status.State)
+	require.NotNil(t, sent.ScheduledAt)
+
+	select {
+	case <-started:
+		t.Fatal("Scheduled task started before its scheduled time")
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	select {
+	case startedAt := <-started:
+		assert.False(t, startedAt.Before(scheduledAt), "Task started before ScheduledAt")
+	case <-time.After(time.Until(sentAt.Add(300 * time.Millisecond))):
+		t.Fatal("Scheduled task did not start within 300ms")
+	}
+
+	assert.Eventually(t, func() bool {
+		result, err := handlers["tasks/get"](sharedtesting.BuildMessage("tasks/get", a2aSchema.TaskQueryParams{ID: "scheduled-task"}))
+		task, ok := result.(*a2aSchema.Task)
+		return err == nil && ok && task.Status.State == a2aSchema.TaskStateCompleted && task.ScheduledAt == nil
+	}, time.Second, 10*time.Millisecond)
+}
+
+func TestScheduledTaskRejectedWithoutScheduler(t *testing.T) {
+	handler := func(ctx context.Context, task *a2aSchema.Task, updates chan<- a2a.A2AYieldUpdate, logger *zap.Logger) error {
+		return nil
+	}
+	logger := zap.NewNop()
+	manager, err := transport.NewManager(logger, config.NewInternalConfig())
+	require.NoError(t, err)
+	handlers := a2a.NewA2ACapability(logger, manager, a2a.NewInMemoryTaskStore(), handler).GetHandlers()
+
+	scheduledAt := time.Now().Add(time.Hour)
+	result, err := handlers["tasks/send"](sharedtesting.BuildMessage("tasks/send", a2aSchema.TaskSendParams{
+		ID:          "scheduled-task",
+		Message:     a2aSchema.Message{Role: "user", Parts: []a2aSchema.Part{{Type: shared.PointerTo("text"), Text: shared.PointerTo("later")}}},
+		ScheduledAt: &scheduledAt,
+	}))
+	sharedtesting.AssertJSONRPCError(t, result, err, shared.JSONRPCErrorInvalidParams)
+}
@@ -0,0 +1,80 @@
+package a2a
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/gate4ai/gate4ai/shared"
+	a2aSchema "github.com/gate4ai/gate4ai/shared/a2a/2025-draft/schema"
+	"go.uber.org/zap"
+)
+
+// SkillIDMetadataKey is the task metadata key (set through TaskSendParams.Metadata) naming the
+// AgentSkill.ID a SkillRouter dispatches the task to.
+const SkillIDMetadataKey = "skillId"
+
+// SkillRouter dispatches tasks to the A2AHandler of the skill named in the task metadata, so
+// one agent server can host several specialized skills. Its Handle method is an A2AHandler.
+type SkillRouter struct {
+	skills   map[string]A2AHandler // AgentSkill.ID -> handler
+	fallback A2AHandler
+}
+
+// NewSkillRouter creates a router for the given skills, keyed by AgentSkill.ID.
+func NewSkillRouter(skills map[string]A2AHandler) *SkillRouter {
+	r := &SkillRouter{skills: make(map[string]A2AHandler, len(skills))}
+	for id, handler := range skills {
+		r.skills[id] = handler
+	}
+	return r
+}
+
+// SetFallback sets the handler of tasks that name no skill or an unknown one. Without a
+// fallback such tasks fail with an invalid params error.
+func (r *SkillRouter) SetFallback(handler A2AHandler) *SkillRouter {
+	r.fallback = handler
+	return r
+}
+
+// Handle implements A2AHandler by calling the handler of the task's skill.
+func (r *SkillRouter) Handle(ctx context.Context, task *a2aSchema.Task, updates chan<- A2AYieldUpdate, logger *zap.Logger) error {
+	skillID := TaskSkillID(task)
+	if handler, ok := r.skills[skillID]; ok {
+		return handler(ctx, task, updates, logger.With(zap.String("skillID", skillID)))
+	}
+	if r.fallback != nil {
+		logger.Debug("No handler for the task skill, using the fallback", zap.String("skillID", skillID))
+		return r.fallback(ctx, task, updates, logger)
+	}
+
+	message := fmt.Sprintf("Unknown skill '%s', expected one of: %s", skillID, strings.Join(r.skillIDs(), ", "))
+	if skillID == "" {
+		message = fmt.Sprintf("Task metadata must name a skill in '%s', one of: %s", SkillIDMetadataKey, strings.Join(r.skillIDs(), ", "))
+	}
+	select {
+	case updates <- A2AYieldError(shared.JSONRPCErrorInvalidParams, message):
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (r *SkillRouter) skillIDs() []string {
+	ids := make([]string, 0, len(r.skills))
+	for id := range r.skills {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// TaskSkillID returns the skill named in the task metadata, or "" if there is none.
+func TaskSkillID(task *a2aSchema.Task) string {
+	if task == nil || task.Metadata == nil {
+		return ""
+	}
+	skillID, _ := (*task.Metadata)[SkillIDMetadataKey].(string)
+	return skillID
+}
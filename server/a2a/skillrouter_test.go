@@ -0,0 +1,82 @@
+package a2a_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/gate4ai/gate4ai/server/a2a"
+	"github.com/gate4ai/gate4ai/server/transport"
+	"github.com/gate4ai/gate4ai/shared"
+	a2aSchema "github.com/gate4ai/gate4ai/shared/a2a/2025-draft/schema"
+	"github.com/gate4ai/gate4ai/shared/config"
+	sharedtesting "github.com/gate4ai/gate4ai/shared/testing"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// replyingHandler completes the task with reply as the status message.
+func replyingHandler(reply string) a2a.A2AHandler {
+	return func(ctx context.Context, task *a2aSchema.Task, updates chan<- a2a.A2AYieldUpdate, logger *zap.Logger) error {
+		updates <- a2a.A2AYieldUpdate{Status: &a2aSchema.TaskStatus{
+			State:   a2aSchema.TaskStateCompleted,
+			Message: &a2aSchema.Message{Role: "agent", Parts: textParts(reply)},
+		}}
+		return nil
+	}
+}
+
+func sendSkillTask(t *testing.T, capability *a2a.A2ACapability, taskID, skillID string) (interface{}, error) {
+	t.Helper()
+	params := a2aSchema.TaskSendParams{
+		ID:      taskID,
+		Message: a2aSchema.Message{Role: "user", Parts: textParts("go")},
+	}
+	if skillID != "" {
+		params.Metadata = &map[string]interface{}{a2a.SkillIDMetadataKey: skillID}
+	}
+	return capability.GetHandlers()["tasks/send"](sharedtesting.BuildMessage("tasks/send", params))
+}
+
+func newSkillCapability(t *testing.T, router *a2a.SkillRouter) *a2a.A2ACapability {
+	t.Helper()
+	manager, err := transport.NewManager(zap.NewNop(), config.NewInternalConfig())
+	require.NoError(t, err)
+	return a2a.NewA2ACapability(zap.NewNop(), manager, a2a.NewInMemoryTaskStore(), router.Handle)
+}
+
+func TestSkillRouterDispatchesBySkillID(t *testing.T) {
+	router := a2a.NewSkillRouter(map[string]a2a.A2AHandler{
+		"translate": replyingHandler("translated"),
+		"summarize": replyingHandler("summarized"),
+	})
+	capability := newSkillCapability(t, router)
+
+	for skillID, reply := range map[string]string{"translate": "translated", "summarize": "summarized"} {
+		result, err := sendSkillTask(t, capability, "task-"+skillID, skillID)
+		task := sharedtesting.AssertJSONRPCSuccess[*a2aSchema.Task](t, result, err)
+		assert.Equal(t, a2aSchema.TaskStateCompleted, task.Status.State, skillID)
+		require.NotNil(t, task.Status.Message, skillID)
+		assert.Equal(t, reply, *task.Status.Message.Parts[0].Text, skillID)
+	}
+
+	// Without a fallback, tasks for unknown or missing skills fail
+	for _, skillID := range []string{"unknown", ""} {
+		result, err := sendSkillTask(t, capability, "task-unrouted-"+skillID, skillID)
+		sharedtesting.AssertJSONRPCError(t, result, err, shared.JSONRPCErrorInvalidParams)
+	}
+}
+
+func TestSkillRouterFallback(t *testing.T) {
+	router := a2a.NewSkillRouter(map[string]a2a.A2AHandler{
+		"translate": replyingHandler("translated"),
+	}).SetFallback(replyingHandler("fallback"))
+	capability := newSkillCapability(t, router)
+
+	for _, skillID := range []string{"unknown", ""} {
+		result, err := sendSkillTask(t, capability, "task-fallback-"+skillID, skillID)
+		task := sharedtesting.AssertJSONRPCSuccess[*a2aSchema.Task](t, result, err)
+		require.NotNil(t, task.Status.Message)
+		assert.Equal(t, "fallback", *task.Status.Message.Parts[0].Text, "skill %q", skillID)
+	}
+}
@@ -0,0 +1,153 @@
+package a2a
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	a2aSchema "github.com/gate4ai/gate4ai/shared/a2a/2025-draft/schema"
+	"go.uber.org/zap"
+)
+
+// MaxTaskSnapshots is how many snapshots InMemoryTaskStore keeps per task; taking another
+// one drops the oldest.
+const MaxTaskSnapshots = 3
+
+// SnapshotBeforeUpdate pushes a copy of the stored task onto its snapshot stack.
+func (s *InMemoryTaskStore) SnapshotBeforeUpdate(ctx context.Context, taskID string) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	task, exists := s.liveTaskLocked(taskID, time.Now())
+	if !exists {
+		return 0, a2aSchema.NewTaskNotFoundError(taskID)
+	}
+	s.snapshotVersion[taskID]++
+	version := s.snapshotVersion[taskID]
+	stack := append(s.snapshots[taskID], taskSnapshot{version: version, task: copyTask(task)})
+	if len(stack) > MaxTaskSnapshots {
+		stack = stack[len(stack)-MaxTaskSnapshots:]
+	}
+	s.snapshots[taskID] = stack
+	return version, nil
+}
+
+// RollbackToSnapshot restores the snapshot with snapshotVersion and drops the snapshots taken after it.
+func (s *InMemoryTaskStore) RollbackToSnapshot(ctx context.Context, taskID string, snapshotVersion int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, exists := s.liveTaskLocked(taskID, time.Now()); !exists {
+		return a2aSchema.NewTaskNotFoundError(taskID)
+	}
+	stack := s.snapshots[taskID]
+	for i, snapshot := range stack {
+		if snapshot.version == snapshotVersion {
+			s.tasks[taskID] = copyTask(snapshot.task)
+			s.snapshots[taskID] = stack[:i+1]
+			return nil
+		}
+	}
+	return fmt.Errorf("snapshot %d of task %s does not exist or was dropped", snapshotVersion, taskID)
+}
+
+func (s *compressedTaskStore) SnapshotBeforeUpdate(ctx context.Context, taskID string) (int, error) {
+	return s.underlying.SnapshotBeforeUpdate(ctx, taskID)
+}
+
+func (s *compressedTaskStore) RollbackToSnapshot(ctx context.Context, taskID string, snapshotVersion int) error {
+	return s.underlying.RollbackToSnapshot(ctx, taskID, snapshotVersion)
+}
+
+// SnapshotBeforeUpdate returns the current version of the task's log; the log itself is the snapshot.
+func (s *EventSourcedTaskStore) SnapshotBeforeUpdate(ctx context.Context, taskID string) (int, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	log, ok := s.events[taskID]
+	if !ok {
+		return 0, a2aSchema.NewTaskNotFoundError(taskID)
+	}
+	return len(log), nil
+}
+
+// RollbackToSnapshot appends a replaced event restoring the task as of snapshotVersion, so the
+// log keeps the rolled back events.
+func (s *EventSourcedTaskStore) RollbackToSnapshot(ctx context.Context, taskID string, snapshotVersion int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	log, ok := s.events[taskID]
+	if !ok {
+		return a2aSchema.NewTaskNotFoundError(taskID)
+	}
+	if snapshotVersion < 1 || snapshotVersion > len(log) {
+		return fmt.Errorf("snapshot %d of task %s does not exist", snapshotVersion, taskID)
+	}
+	if snapshotVersion == len(log) {
+		return nil
+	}
+	var base *a2aSchema.Task
+	from := 0
+	if snapshot, ok := s.snapshots[taskID]; ok && snapshot.version <= snapshotVersion {
+		base, from = snapshot.task, snapshot.version
+	}
+	task, err := ReplayTaskEvents(base, log[from:snapshotVersion])
+	if err != nil {
+		return err
+	}
+	payload, err := json.Marshal(task)
+	if err != nil {
+		return err
+	}
+	return s.appendLocked(TaskEvent{TaskID: taskID, Type: TaskEventReplaced, Payload: payload, Version: len(log) + 1})
+}
+
+// handlerPanicError is the error of an agent handler that panicked.
+type handlerPanicError struct {
+	value interface{}
+}
+
+func (e *handlerPanicError) Error() string {
+	return fmt.Sprintf("agent handler panicked: %v", e.value)
+}
+
+// callAgentHandler runs the agent handler, recovering a panic as a handlerPanicError.
+func (ac *A2ACapability) callAgentHandler(ctx context.Context, task *a2aSchema.Task, updates chan<- A2AYieldUpdate, logger *zap.Logger) (err error) {
+	defer func() {
+		if value := recover(); value != nil {
+			logger.Error("Agent handler panicked", zap.Any("panic", value), zap.Stack("stack"))
+			err = &handlerPanicError{value: value}
+		}
+	}()
+	return ac.agentHandler(ctx, task, updates, logger)
+}
+
+// snapshotTask records the task before its handler starts; it returns 0 if that failed.
+func (ac *A2ACapability) snapshotTask(taskID string, logger *zap.Logger) int {
+	version, err := ac.taskStore.SnapshotBeforeUpdate(context.Background(), taskID)
+	if err != nil {
+		logger.Warn("Failed to snapshot task before handler start, it cannot be rolled back", zap.Error(err))
+		return 0
+	}
+	return version
+}
+
+// rollbackOnPanic restores the task saved before its handler panicked, discarding partial
+// updates such as half-written artifacts. It returns the restored task, or nil if handlerErr
+// is not a panic or the task cannot be rolled back.
+func (ac *A2ACapability) rollbackOnPanic(taskID string, snapshotVersion int, handlerErr error, logger *zap.Logger) *a2aSchema.Task {
+	var panicErr *handlerPanicError
+	if !errors.As(handlerErr, &panicErr) || snapshotVersion == 0 {
+		return nil
+	}
+	if err := ac.taskStore.RollbackToSnapshot(context.Background(), taskID, snapshotVersion); err != nil {
+		logger.Error("Failed to roll back task after handler panic", zap.Error(err))
+		return nil
+	}
+	task, err := ac.loadTask(context.Background(), taskID)
+	if err != nil {
+		logger.Error("Failed to load rolled back task", zap.Error(err))
+		return nil
+	}
+	logger.Info("Rolled back task after handler panic", zap.Int("snapshotVersion", snapshotVersion))
+	return task
+}
@@ -0,0 +1,91 @@
+package a2a_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/gate4ai/gate4ai/server/a2a"
+	"github.com/gate4ai/gate4ai/server/transport"
+	"github.com/gate4ai/gate4ai/shared"
+	a2aSchema "github.com/gate4ai/gate4ai/shared/a2a/2025-draft/schema"
+	"github.com/gate4ai/gate4ai/shared/config"
+	sharedtesting "github.com/gate4ai/gate4ai/shared/testing"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestTaskStoreRollbackToSnapshot(t *testing.T) {
+	redisStore, _ := newRedisTestStore(t)
+	stores := map[string]a2a.TaskStore{
+		"in-memory":     a2a.NewInMemoryTaskStore(),
+		"event-sourced": a2a.NewEventSourcedTaskStore(a2a.WithSnapshotInterval(2)),
+		"redis":         redisStore,
+	}
+	for name, store := range stores {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			task := &a2aSchema.Task{ID: "rollback", Status: a2aSchema.TaskStatus{State: a2aSchema.TaskStateSubmitted}}
+			_, err := store.SnapshotBeforeUpdate(ctx, task.ID)
+			assert.Error(t, err, "snapshot of an unknown task")
+
+			require.NoError(t, store.Save(ctx, task))
+			version, err := store.SnapshotBeforeUpdate(ctx, task.ID)
+			require.NoError(t, err)
+
+			updated := *task
+			updated.Status.State = a2aSchema.TaskStateWorking
+			updated.Artifacts = []a2aSchema.Artifact{{Parts: textParts("partial")}}
+			require.NoError(t, store.Save(ctx, &updated))
+			require.NoError(t, store.Save(ctx, &updated))
+
+			require.NoError(t, store.RollbackToSnapshot(ctx, task.ID, version))
+			restored, err := store.Load(ctx, task.ID)
+			require.NoError(t, err)
+			assert.Equal(t, a2aSchema.TaskStateSubmitted, restored.Status.State)
+			assert.Empty(t, restored.Artifacts)
+
+			assert.Error(t, store.RollbackToSnapshot(ctx, task.ID, version+10), "unknown version")
+		})
+	}
+}
+
+func TestInMemoryTaskStoreDropsOldSnapshots(t *testing.T) {
+	ctx := context.Background()
+	store := a2a.NewInMemoryTaskStore()
+	require.NoError(t, store.Save(ctx, &a2aSchema.Task{ID: "bounded"}))
+	var versions []int
+	for i := 0; i <= a2a.MaxTaskSnapshots; i++ {
+		version, err := store.SnapshotBeforeUpdate(ctx, "bounded")
+		require.NoError(t, err)
+		versions = append(versions, version)
+	}
+	assert.Error(t, store.RollbackToSnapshot(ctx, "bounded", versions[0]), "oldest snapshot was dropped")
+	assert.NoError(t, store.RollbackToSnapshot(ctx, "bounded", versions[1]))
+	assert.Error(t, store.RollbackToSnapshot(ctx, "bounded", versions[2]), "newer snapshots are dropped by a rollback")
+}
+
+func TestHandlerPanicRollsBackTask(t *testing.T) {
+	manager, err := transport.NewManager(zap.NewNop(), config.NewInternalConfig())
+	require.NoError(t, err)
+	handler := func(ctx context.Context, task *a2aSchema.Task, updates chan<- a2a.A2AYieldUpdate, logger *zap.Logger) error {
+		updates <- a2a.A2AYieldUpdate{Artifact: &a2aSchema.Artifact{Parts: textParts("half written")}}
+		updates <- a2a.A2AYieldUpdate{Status: &a2aSchema.TaskStatus{State: a2aSchema.TaskStateWorking}}
+		panic("agent bug")
+	}
+	store := a2a.NewInMemoryTaskStore()
+	capability := a2a.NewA2ACapability(zap.NewNop(), manager, store, handler)
+
+	result, err := capability.GetHandlers()["tasks/send"](sharedtesting.BuildMessage("tasks/send", a2aSchema.TaskSendParams{
+		ID:      "panicking-task",
+		Message: a2aSchema.Message{Role: "user", Parts: textParts("go")},
+	}))
+	sharedtesting.AssertJSONRPCError(t, result, err, shared.JSONRPCErrorInternal)
+
+	task, err := store.Load(context.Background(), "panicking-task")
+	require.NoError(t, err)
+	assert.Equal(t, a2aSchema.TaskStateFailed, task.Status.State)
+	assert.Empty(t, task.Artifacts, "partial artifact should be rolled back")
+	require.Len(t, task.History, 1)
+	assert.Equal(t, "user", task.History[0].Role)
+}
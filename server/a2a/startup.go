@@ -0,0 +1,88 @@
+package a2a
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/gate4ai/gate4ai/shared"
+	a2aSchema "github.com/gate4ai/gate4ai/shared/a2a/2025-draft/schema"
+	"go.uber.org/zap"
+)
+
+// WithHandlerStartupTimeout requires tasks/sendSubscribe handlers to yield their first update
+// (or return) within d. Otherwise the handler is cancelled, the task fails and the stream
+// ends with an error event. Zero disables the check.
+func WithHandlerStartupTimeout(d time.Duration) A2AOption {
+	return func(ac *A2ACapability) {
+		ac.handlerStartupTimeout = d
+	}
+}
+
+// handlerStartup settles, exactly once, whether a streaming handler started before its deadline.
+type handlerStartup struct {
+	once      sync.Once
+	expired   bool
+	confirmed chan struct{}
+}
+
+func newHandlerStartup() *handlerStartup {
+	return &handlerStartup{confirmed: make(chan struct{})}
+}
+
+// confirm records that the handler yielded an update or returned. It reports false if the
+// deadline expired first, in which case the caller must not send anything to the stream.
+func (s *handlerStartup) confirm() bool {
+	s.once.Do(func() { close(s.confirmed) })
+	return !s.expired
+}
+
+// expire marks the deadline as missed and reports whether it did so before a confirmation.
+func (s *handlerStartup) expire() bool {
+	expired := false
+	s.once.Do(func() {
+		s.expired = true
+		expired = true
+	})
+	return expired
+}
+
+// watchHandlerStartup fails the task if the handler neither yields nor returns before the startup timeout.
+func (ac *A2ACapability) watchHandlerStartup(ctx context.Context, startup *handlerStartup, taskID string, sendEvent func(*shared.A2AStreamEvent) error, logger *zap.Logger) {
+	timer := time.NewTimer(ac.handlerStartupTimeout)
+	defer timer.Stop()
+	select {
+	case <-startup.confirmed:
+		return
+	case <-ctx.Done():
+		return
+	case <-timer.C:
+	}
+	if !startup.expire() {
+		return
+	}
+
+	logger.Warn("Agent handler did not start in time, cancelling it", zap.Duration("startupTimeout", ac.handlerStartupTimeout))
+	startupErr := &a2aSchema.JSONRPCError{
+		Code:    a2aSchema.ErrorInternalError,
+		Message: fmt.Sprintf("Agent handler did not start within %s", ac.handlerStartupTimeout),
+	}
+	if err := sendEvent(streamErrorEvent(startupErr, true)); err != nil {
+		logger.Error("Failed to send startup timeout error event", zap.Error(err))
+	}
+	ac.cancelHandler(taskID)
+
+	task, err := ac.loadTask(context.Background(), taskID)
+	if err != nil {
+		logger.Error("Failed to load task after startup timeout", zap.Error(err))
+		return
+	}
+	task.Status = createErrorStatus(startupErr, startupErr)
+	if err := ac.saveTask(context.Background(), task); err != nil {
+		logger.Error("Failed to save failed task state after startup timeout", zap.Error(err))
+		return
+	}
+	ac.notifyWebhook(task)
+	ac.notifyPush(task)
+}
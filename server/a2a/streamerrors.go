@@ -0,0 +1,56 @@
+package a2a
+
+import (
+	"math"
+	"time"
+
+	"github.com/gate4ai/gate4ai/shared"
+	a2aSchema "github.com/gate4ai/gate4ai/shared/a2a/2025-draft/schema"
+)
+
+// DefaultRateLimitRetryAfter is suggested to clients of a rate-limited stream when the error does not say when to retry.
+const DefaultRateLimitRetryAfter = time.Second
+
+// retryAfterDataKey is the error data member holding the retry delay of a rate-limit error.
+const retryAfterDataKey = "retry_after_seconds"
+
+// NewRateLimitError returns an ErrorCodeRateLimitExceeded error telling the client to retry after
+// retryAfter. Handlers can yield it when a service they depend on throttles them.
+func NewRateLimitError(message string, retryAfter time.Duration) *a2aSchema.JSONRPCError {
+	var data any = map[string]interface{}{retryAfterDataKey: durationSeconds(retryAfter)}
+	return &a2aSchema.JSONRPCError{Code: a2aSchema.ErrorCodeRateLimitExceeded, Message: message, Data: &data}
+}
+
+// streamErrorEvent builds the SSE error event for err, classified so the client knows whether to retry.
+// Internal and rate-limit errors are transient; request errors such as an unknown task are permanent.
+func streamErrorEvent(err *a2aSchema.JSONRPCError, final bool) *shared.A2AStreamEvent {
+	event := &shared.A2AStreamEvent{Type: "error", Error: err, Final: final, ErrorCategory: shared.A2AErrorPermanent}
+	switch err.Code {
+	case a2aSchema.ErrorCodeRateLimitExceeded:
+		event.ErrorCategory = shared.A2AErrorTransient
+		event.RetryAfterSeconds = retryAfterSeconds(err)
+	case a2aSchema.ErrorInternalError, shared.JSONRPCErrorServerError:
+		event.ErrorCategory = shared.A2AErrorTransient
+	}
+	return event
+}
+
+// retryAfterSeconds reads the delay set by NewRateLimitError, falling back to DefaultRateLimitRetryAfter.
+func retryAfterSeconds(err *a2aSchema.JSONRPCError) int {
+	if err.Data != nil {
+		if data, ok := (*err.Data).(map[string]interface{}); ok {
+			switch seconds := data[retryAfterDataKey].(type) {
+			case int:
+				return seconds
+			case float64:
+				return int(math.Ceil(seconds))
+			}
+		}
+	}
+	return durationSeconds(DefaultRateLimitRetryAfter)
+}
+
+// durationSeconds rounds d up to whole seconds, at least one.
+func durationSeconds(d time.Duration) int {
+	return max(1, int(math.Ceil(d.Seconds())))
+}
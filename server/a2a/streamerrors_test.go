@@ -0,0 +1,120 @@
+package a2a_test
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gate4ai/gate4ai/server/a2a"
+	"github.com/gate4ai/gate4ai/server/transport"
+	"github.com/gate4ai/gate4ai/shared"
+	a2aSchema "github.com/gate4ai/gate4ai/shared/a2a/2025-draft/schema"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// streamError sends tasks/sendSubscribe and returns the first error event of the stream.
+func streamError(t *testing.T, serverURL string) *shared.JSONRPCError {
+	params := a2aSchema.TaskSendParams{
+		ID:      "failing-task",
+		Message: a2aSchema.Message{Role: "user", Parts: []a2aSchema.Part{{Type: shared.PointerTo("text"), Text: shared.PointerTo("hello")}}},
+	}
+	body, err := json.Marshal(map[string]interface{}{"jsonrpc": "2.0", "id": 1, "method": "tasks/sendSubscribe", "params": params})
+	require.NoError(t, err)
+	req, err := http.NewRequest(http.MethodPost, serverURL+transport.A2A_PATH, strings.NewReader(string(body)))
+	require.NoError(t, err)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "text/event-stream")
+	resp, err := (&http.Client{Timeout: 10 * time.Second}).Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Contains(t, resp.Header.Get("Content-Type"), "text/event-stream")
+
+	scanner := bufio.NewScanner(resp.Body)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if !strings.HasPrefix(line, "data:") {
+			continue
+		}
+		var response struct {
+			Error *shared.JSONRPCError `json:"error"`
+		}
+		require.NoError(t, json.Unmarshal([]byte(strings.TrimSpace(strings.TrimPrefix(line, "data:"))), &response))
+		if response.Error != nil {
+			return response.Error
+		}
+	}
+	t.Fatal("stream ended without an error event")
+	return nil
+}
+
+func TestA2AStreamErrorCategories(t *testing.T) {
+	tests := []struct {
+		name       string
+		err        *a2aSchema.JSONRPCError
+		code       int
+		category   shared.A2AErrorCategory
+		retryAfter int
+	}{
+		{
+			name:     "internal error is transient",
+			err:      &a2aSchema.JSONRPCError{Code: a2aSchema.ErrorInternalError, Message: "model backend unreachable"},
+			code:     a2aSchema.ErrorInternalError,
+			category: shared.A2AErrorTransient,
+		},
+		{
+			name:     "unauthorized is permanent",
+			err:      &a2aSchema.JSONRPCError{Code: shared.JSONRPCErrorUnauthorized, Message: "missing credentials for the document store"},
+			code:     shared.JSONRPCErrorUnauthorized,
+			category: shared.A2AErrorPermanent,
+		},
+		{
+			name:     "unsupported content is permanent",
+			err:      &a2aSchema.JSONRPCError{Code: a2aSchema.ErrorCodeContentTypeNotSupported, Message: "audio is not supported"},
+			code:     a2aSchema.ErrorCodeContentTypeNotSupported,
+			category: shared.A2AErrorPermanent,
+		},
+		{
+			name:       "rate limit tells when to retry",
+			err:        a2a.NewRateLimitError("upstream quota exceeded", 2500*time.Millisecond),
+			code:       a2aSchema.ErrorCodeRateLimitExceeded,
+			category:   shared.A2AErrorTransient,
+			retryAfter: 3,
+		},
+		{
+			name:       "rate limit without delay uses the default",
+			err:        &a2aSchema.JSONRPCError{Code: a2aSchema.ErrorCodeRateLimitExceeded, Message: "too many requests"},
+			code:       a2aSchema.ErrorCodeRateLimitExceeded,
+			category:   shared.A2AErrorTransient,
+			retryAfter: 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// The handler yields the error
+			server := newA2ATestServer(t, func(ctx context.Context, task *a2aSchema.Task, updates chan<- a2a.A2AYieldUpdate, logger *zap.Logger) error {
+				updates <- a2a.A2AYieldUpdate{Error: tt.err}
+				return nil
+			})
+			streamErr := streamError(t, server.URL)
+			assert.Equal(t, tt.code, streamErr.Code)
+			assert.Equal(t, tt.err.Message, streamErr.Message)
+			assert.Equal(t, tt.category, streamErr.Category)
+			assert.Equal(t, tt.retryAfter, streamErr.RetryAfterSeconds)
+		})
+	}
+
+	t.Run("returned error is classified like a yielded one", func(t *testing.T) {
+		server := newA2ATestServer(t, func(ctx context.Context, task *a2aSchema.Task, updates chan<- a2a.A2AYieldUpdate, logger *zap.Logger) error {
+			return &a2aSchema.JSONRPCError{Code: a2aSchema.ErrorCodeTaskNotFound, Message: "referenced task is gone"}
+		})
+		streamErr := streamError(t, server.URL)
+		assert.Equal(t, a2aSchema.ErrorCodeTaskNotFound, streamErr.Code)
+		assert.Equal(t, shared.A2AErrorPermanent, streamErr.Category)
+	})
+}
@@ -1,8 +1,17 @@
 package a2a
 
 import (
+	"container/list"
 	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
 	"sync"
+	"time"
 
 	a2aSchema "github.com/gate4ai/gate4ai/shared/a2a/2025-draft/schema"
 )
@@ -12,55 +21,295 @@ type TaskStore interface {
 	Save(ctx context.Context, task *a2aSchema.Task) error
 	Load(ctx context.Context, taskID string) (*a2aSchema.Task, error)
 	Delete(ctx context.Context, taskID string) error
+	// List returns the tasks matching filter ordered by creation time, plus the cursor
+	// of the next page ("" when there are no more results). A malformed cursor fails with
+	// ErrInvalidCursor.
+	List(ctx context.Context, filter TaskFilter) ([]*a2aSchema.Task, string, error)
+	// SnapshotBeforeUpdate records the stored state of a task and returns the version that
+	// RollbackToSnapshot restores it by.
+	SnapshotBeforeUpdate(ctx context.Context, taskID string) (snapshotVersion int, err error)
+	// RollbackToSnapshot replaces the stored task with the state recorded by SnapshotBeforeUpdate.
+	RollbackToSnapshot(ctx context.Context, taskID string, snapshotVersion int) error
+}
+
+// TaskFilter selects tasks for TaskStore.List. Zero-valued fields do not filter.
+type TaskFilter struct {
+	States       []a2aSchema.TaskState // Match any of these states
+	SessionID    string
+	UserID       *string   // Tasks created by this user; "" selects tasks of anonymous users
+	ParentTaskID string    // Subtasks of this task (see ParentTaskIDMetadataKey)
+	Since        time.Time // Status timestamp at or after Since
+	CreatedAfter time.Time // Created strictly after CreatedAfter
+	// Task metadata must contain these fields with equal values. Nested maps match
+	// recursively, like a JSONB @> containment query.
+	MetadataFilter map[string]interface{}
+	Limit          int    // Maximum page size; 0 means no limit
+	Cursor         string // Cursor returned by a previous List call
+}
+
+// matches reports whether the task satisfies every set field of the filter.
+func (f TaskFilter) matches(task *a2aSchema.Task) bool {
+	if len(f.States) > 0 {
+		found := false
+		for _, state := range f.States {
+			if task.Status.State == state {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	if f.SessionID != "" && task.SessionID != f.SessionID {
+		return false
+	}
+	if f.UserID != nil && task.UserID != *f.UserID {
+		return false
+	}
+	if f.ParentTaskID != "" && ParentTaskID(task) != f.ParentTaskID {
+		return false
+	}
+	if !f.Since.IsZero() && task.Status.Timestamp.Before(f.Since) {
+		return false
+	}
+	if !f.CreatedAfter.IsZero() && !task.CreatedAt.After(f.CreatedAfter) {
+		return false
+	}
+	if len(f.MetadataFilter) > 0 {
+		if task.Metadata == nil {
+			return false
+		}
+		return jsonContains(normalizeJSON(*task.Metadata), normalizeJSON(f.MetadataFilter))
+	}
+	return true
+}
+
+// normalizeJSON converts v to the generic form encoding/json decodes into (maps, slices,
+// float64, ...) so values built in Go compare equal to decoded ones.
+func normalizeJSON(v interface{}) interface{} {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return v
+	}
+	var normalized interface{}
+	if err := json.Unmarshal(data, &normalized); err != nil {
+		return v
+	}
+	return normalized
+}
+
+// jsonContains reports whether have contains want with the semantics of JSONB @>:
+// objects contain the fields of want, arrays contain every element of want, and scalars are equal.
+func jsonContains(have, want interface{}) bool {
+	switch w := want.(type) {
+	case map[string]interface{}:
+		h, ok := have.(map[string]interface{})
+		if !ok {
+			return false
+		}
+		for key, value := range w {
+			field, exists := h[key]
+			if !exists || !jsonContains(field, value) {
+				return false
+			}
+		}
+		return true
+	case []interface{}:
+		h, ok := have.([]interface{})
+		if !ok {
+			return false
+		}
+		for _, value := range w {
+			if !slices.ContainsFunc(h, func(element interface{}) bool { return jsonContains(element, value) }) {
+				return false
+			}
+		}
+		return true
+	default:
+		return have == want
+	}
+}
+
+// ErrInvalidCursor is returned by TaskStore.List for a cursor it did not issue.
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// taskCursor is the sort key of the last task on a page; the next page starts after it.
+// Creation times do not change, so tasks updated while paging are neither skipped nor repeated.
+type taskCursor struct {
+	createdAt time.Time
+	id        string
+}
+
+func cursorOf(task *a2aSchema.Task) taskCursor {
+	return taskCursor{createdAt: task.CreatedAt, id: task.ID}
+}
+
+func (c taskCursor) encode() string {
+	// RFC 3339 keeps the zero time of tasks stored before creation times were recorded
+	return base64.RawURLEncoding.EncodeToString([]byte(c.createdAt.UTC().Format(time.RFC3339Nano) + "|" + c.id))
+}
+
+func decodeTaskCursor(cursor string) (taskCursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return taskCursor{}, fmt.Errorf("%w: %v", ErrInvalidCursor, err)
+	}
+	createdAt, id, ok := strings.Cut(string(raw), "|")
+	if !ok {
+		return taskCursor{}, ErrInvalidCursor
+	}
+	c := taskCursor{id: id}
+	if c.createdAt, err = time.Parse(time.RFC3339Nano, createdAt); err != nil {
+		return taskCursor{}, fmt.Errorf("%w: %v", ErrInvalidCursor, err)
+	}
+	return c, nil
+}
+
+// before orders tasks by creation time, then by ID so the order is total.
+func (c taskCursor) before(other taskCursor) bool {
+	if !c.createdAt.Equal(other.createdAt) {
+		return c.createdAt.Before(other.createdAt)
+	}
+	return c.id < other.id
+}
+
+// InMemoryStoreOption configures an InMemoryTaskStore.
+type InMemoryStoreOption func(*InMemoryTaskStore)
+
+// WithMaxTasks bounds the store to n tasks; saving a new task beyond it evicts the least
+// recently saved or loaded one. By default the store is unbounded.
+func WithMaxTasks(n int) InMemoryStoreOption {
+	return func(s *InMemoryTaskStore) {
+		s.maxTasks = n
+	}
+}
+
+// WithDefaultTTL expires tasks ttl after they were last saved. By default tasks do not expire.
+func WithDefaultTTL(ttl time.Duration) InMemoryStoreOption {
+	return func(s *InMemoryTaskStore) {
+		s.defaultTTL = ttl
+	}
 }
 
 // InMemoryTaskStore implements TaskStore using an in-memory map.
 type InMemoryTaskStore struct {
 	mu    sync.RWMutex
 	tasks map[string]*a2aSchema.Task
+	// Up to MaxTaskSnapshots snapshots per task, oldest first
+	snapshots       map[string][]taskSnapshot
+	snapshotVersion map[string]int // Last snapshot version per task
+	// Expiry of the tasks saved with a TTL; tasks without one never expire
+	expiresAt  map[string]time.Time
+	defaultTTL time.Duration
+	// Task IDs by recent use, front first, for eviction once maxTasks is reached
+	maxTasks int
+	lru      *list.List
+	lruIndex map[string]*list.Element
 }
 
 // NewInMemoryTaskStore creates a new InMemoryTaskStore.
-func NewInMemoryTaskStore() *InMemoryTaskStore {
-	return &InMemoryTaskStore{
-		tasks: make(map[string]*a2aSchema.Task),
+func NewInMemoryTaskStore(options ...InMemoryStoreOption) *InMemoryTaskStore {
+	s := &InMemoryTaskStore{
+		tasks:           make(map[string]*a2aSchema.Task),
+		snapshots:       make(map[string][]taskSnapshot),
+		snapshotVersion: make(map[string]int),
+		expiresAt:       make(map[string]time.Time),
+		lru:             list.New(),
+		lruIndex:        make(map[string]*list.Element),
 	}
+	for _, option := range options {
+		option(s)
+	}
+	return s
 }
 
-// Save stores a copy of the task in the map.
+// Save stores a copy of the task in the map, expiring it after the TTL set by WithDefaultTTL.
+// Without one it keeps the expiry set by SaveWithTTL, if any.
 func (s *InMemoryTaskStore) Save(ctx context.Context, task *a2aSchema.Task) error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
+	if s.defaultTTL > 0 {
+		s.saveLocked(task, s.defaultTTL)
+		return nil
+	}
+	expiresAt, hasTTL := s.expiresAt[task.ID]
+	s.saveLocked(task, 0)
+	if hasTTL && time.Now().Before(expiresAt) {
+		s.expiresAt[task.ID] = expiresAt
+	}
+	return nil
+}
+
+// SaveWithTTL stores a copy of the task and expires it after ttl; 0 removes its expiry.
+func (s *InMemoryTaskStore) SaveWithTTL(ctx context.Context, task *a2aSchema.Task, ttl time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.saveLocked(task, ttl)
+	return nil
+}
+
+func (s *InMemoryTaskStore) saveLocked(task *a2aSchema.Task, ttl time.Duration) {
 	// Create a copy to store, avoid holding reference to caller's object
 	taskCopy := *task
-	// Ensure slices are copied if needed, though shallow copy is often sufficient here
-	// if task.Artifacts != nil {
-	// 	taskCopy.Artifacts = append([]a2aSchema.Artifact{}, task.Artifacts...)
-	// }
-	// if task.History != nil {
-	// 	taskCopy.History = append([]a2aSchema.Message{}, task.History...)
-	// }
 	s.tasks[task.ID] = &taskCopy
-	return nil
+	if ttl > 0 {
+		s.expiresAt[task.ID] = time.Now().Add(ttl)
+	} else {
+		delete(s.expiresAt, task.ID)
+	}
+	s.touchLocked(task.ID)
+	for s.maxTasks > 0 && len(s.tasks) > s.maxTasks {
+		s.removeLocked(s.lru.Back().Value.(string))
+	}
 }
 
-// Load retrieves a copy of the task from the map.
-func (s *InMemoryTaskStore) Load(ctx context.Context, taskID string) (*a2aSchema.Task, error) {
-	s.mu.RLock()
-	defer s.mu.RUnlock()
+// touchLocked marks the task as the most recently used one.
+func (s *InMemoryTaskStore) touchLocked(taskID string) {
+	if s.maxTasks <= 0 {
+		return
+	}
+	if element, exists := s.lruIndex[taskID]; exists {
+		s.lru.MoveToFront(element)
+		return
+	}
+	s.lruIndex[taskID] = s.lru.PushFront(taskID)
+}
+
+// liveTaskLocked returns the stored task unless it is missing or expired.
+func (s *InMemoryTaskStore) liveTaskLocked(taskID string, now time.Time) (*a2aSchema.Task, bool) {
 	task, exists := s.tasks[taskID]
+	if !exists {
+		return nil, false
+	}
+	if expiresAt, hasTTL := s.expiresAt[taskID]; hasTTL && !now.Before(expiresAt) {
+		return nil, false
+	}
+	return task, true
+}
+
+func (s *InMemoryTaskStore) removeLocked(taskID string) {
+	delete(s.tasks, taskID)
+	delete(s.snapshots, taskID)
+	delete(s.snapshotVersion, taskID)
+	delete(s.expiresAt, taskID)
+	if element, exists := s.lruIndex[taskID]; exists {
+		s.lru.Remove(element)
+		delete(s.lruIndex, taskID)
+	}
+}
+
+// Load retrieves a copy of the task from the map. Expired tasks are not found.
+func (s *InMemoryTaskStore) Load(ctx context.Context, taskID string) (*a2aSchema.Task, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	task, exists := s.liveTaskLocked(taskID, time.Now())
 	if !exists {
 		return nil, a2aSchema.NewTaskNotFoundError(taskID)
 	}
+	s.touchLocked(taskID)
 	// Return a copy to prevent mutation by caller
 	taskCopy := *task
-	// Ensure slices are copied if needed
-	// if task.Artifacts != nil {
-	// 	taskCopy.Artifacts = append([]a2aSchema.Artifact{}, task.Artifacts...)
-	// }
-	// if task.History != nil {
-	// 	taskCopy.History = append([]a2aSchema.Message{}, task.History...)
-	// }
 	return &taskCopy, nil
 }
 
@@ -68,9 +317,95 @@ func (s *InMemoryTaskStore) Load(ctx context.Context, taskID string) (*a2aSchema
 func (s *InMemoryTaskStore) Delete(ctx context.Context, taskID string) error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
-	if _, exists := s.tasks[taskID]; !exists {
+	if _, exists := s.liveTaskLocked(taskID, time.Now()); !exists {
 		return a2aSchema.NewTaskNotFoundError(taskID)
 	}
-	delete(s.tasks, taskID)
+	s.removeLocked(taskID)
 	return nil
 }
+
+// List returns copies of the unexpired tasks matching filter, oldest first.
+func (s *InMemoryTaskStore) List(ctx context.Context, filter TaskFilter) ([]*a2aSchema.Task, string, error) {
+	now := time.Now()
+	s.mu.RLock()
+	tasks := make([]*a2aSchema.Task, 0, len(s.tasks))
+	for taskID := range s.tasks {
+		task, live := s.liveTaskLocked(taskID, now)
+		if !live {
+			continue
+		}
+		taskCopy := *task
+		tasks = append(tasks, &taskCopy)
+	}
+	s.mu.RUnlock()
+	return listTasks(tasks, filter)
+}
+
+// StartGC deletes expired tasks every interval until ctx is done.
+func (s *InMemoryTaskStore) StartGC(ctx context.Context, interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case now := <-ticker.C:
+				s.deleteExpired(now)
+			}
+		}
+	}()
+}
+
+// deleteExpired removes the tasks expired at now.
+func (s *InMemoryTaskStore) deleteExpired(now time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for taskID, expiresAt := range s.expiresAt {
+		if !now.Before(expiresAt) {
+			s.removeLocked(taskID)
+		}
+	}
+}
+
+// Len returns how many tasks the store holds, including expired ones StartGC has not deleted yet.
+func (s *InMemoryTaskStore) Len() int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return len(s.tasks)
+}
+
+// listTasks returns the page of tasks selected by filter, ordered by creation time, and the
+// cursor of the next page. Stores holding tasks in memory use it to implement List.
+func listTasks(tasks []*a2aSchema.Task, filter TaskFilter) ([]*a2aSchema.Task, string, error) {
+	var after *taskCursor
+	if filter.Cursor != "" {
+		c, err := decodeTaskCursor(filter.Cursor)
+		if err != nil {
+			return nil, "", err
+		}
+		after = &c
+	}
+
+	matched := make([]*a2aSchema.Task, 0)
+	for _, task := range tasks {
+		if !filter.matches(task) {
+			continue
+		}
+		if after != nil && !after.before(cursorOf(task)) {
+			continue
+		}
+		matched = append(matched, task)
+	}
+
+	sort.Slice(matched, func(i, j int) bool {
+		return cursorOf(matched[i]).before(cursorOf(matched[j]))
+	})
+
+	nextCursor := ""
+	if filter.Limit > 0 && len(matched) > filter.Limit {
+		matched = matched[:filter.Limit]
+		nextCursor = cursorOf(matched[len(matched)-1]).encode()
+	}
+	return matched, nextCursor, nil
+}
@@ -0,0 +1,330 @@
+package a2a_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/gate4ai/gate4ai/server/a2a"
+	"github.com/gate4ai/gate4ai/shared"
+	a2aSchema "github.com/gate4ai/gate4ai/shared/a2a/2025-draft/schema"
+	sharedtesting "github.com/gate4ai/gate4ai/shared/testing"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// seedTasks stores task-00..task-19: states cycle through five values, sessions alternate
+// between "even" and "odd", the first ten belong to "alice", and each task was created and
+// updated one minute after the previous one.
+func seedTasks(t *testing.T, store a2a.TaskStore, base time.Time) {
+	states := []a2aSchema.TaskState{
+		a2aSchema.TaskStateSubmitted,
+		a2aSchema.TaskStateWorking,
+		a2aSchema.TaskStateInputRequired,
+		a2aSchema.TaskStateCompleted,
+		a2aSchema.TaskStateFailed,
+	}
+	// Save in reverse order so List has to sort
+	for i := 19; i >= 0; i-- {
+		session := "even"
+		if i%2 == 1 {
+			session = "odd"
+		}
+		user := ""
+		if i < 10 {
+			user = "alice"
+		}
+		require.NoError(t, store.Save(context.Background(), &a2aSchema.Task{
+			ID:        fmt.Sprintf("task-%02d", i),
+			SessionID: session,
+			UserID:    user,
+			CreatedAt: base.Add(time.Duration(i) * time.Minute),
+			Status:    a2aSchema.TaskStatus{State: states[i%len(states)], Timestamp: base.Add(time.Duration(i) * time.Minute)},
+		}))
+	}
+}
+
+func taskIDs(tasks []*a2aSchema.Task) []string {
+	ids := make([]string, 0, len(tasks))
+	for _, task := range tasks {
+		ids = append(ids, task.ID)
+	}
+	return ids
+}
+
+func TestInMemoryTaskStoreListFilters(t *testing.T) {
+	base := time.Date(2025, 5, 1, 12, 0, 0, 0, time.UTC)
+	store := a2a.NewInMemoryTaskStore()
+	seedTasks(t, store, base)
+
+	cases := []struct {
+		name   string
+		filter a2a.TaskFilter
+		want   []string
+	}{
+		{"no filter", a2a.TaskFilter{}, []string{
+			"task-00", "task-01", "task-02", "task-03", "task-04", "task-05", "task-06", "task-07", "task-08", "task-09",
+			"task-10", "task-11", "task-12", "task-13", "task-14", "task-15", "task-16", "task-17", "task-18", "task-19",
+		}},
+		{"single state", a2a.TaskFilter{States: []a2aSchema.TaskState{a2aSchema.TaskStateWorking}}, []string{"task-01", "task-06", "task-11", "task-16"}},
+		{"several states", a2a.TaskFilter{States: []a2aSchema.TaskState{a2aSchema.TaskStateCompleted, a2aSchema.TaskStateFailed}}, []string{"task-03", "task-04", "task-08", "task-09", "task-13", "task-14", "task-18", "task-19"}},
+		{"session", a2a.TaskFilter{SessionID: "odd"}, []string{"task-01", "task-03", "task-05", "task-07", "task-09", "task-11", "task-13", "task-15", "task-17", "task-19"}},
+		{"user", a2a.TaskFilter{UserID: shared.PointerTo("alice")}, []string{"task-00", "task-01", "task-02", "task-03", "task-04", "task-05", "task-06", "task-07", "task-08", "task-09"}},
+		{"anonymous user and session", a2a.TaskFilter{UserID: shared.PointerTo(""), SessionID: "odd"}, []string{"task-11", "task-13", "task-15", "task-17", "task-19"}},
+		{"since", a2a.TaskFilter{Since: base.Add(16 * time.Minute)}, []string{"task-16", "task-17", "task-18", "task-19"}},
+		{"state and session", a2a.TaskFilter{States: []a2aSchema.TaskState{a2aSchema.TaskStateSubmitted}, SessionID: "even"}, []string{"task-00", "task-10"}},
+		{"created after", a2a.TaskFilter{CreatedAfter: base.Add(16 * time.Minute)}, []string{"task-17", "task-18", "task-19"}},
+		{"state and created after", a2a.TaskFilter{States: []a2aSchema.TaskState{a2aSchema.TaskStateSubmitted}, CreatedAfter: base.Add(time.Minute)}, []string{"task-05", "task-10", "task-15"}},
+		{"session and created after", a2a.TaskFilter{SessionID: "even", CreatedAfter: base.Add(13 * time.Minute)}, []string{"task-14", "task-16", "task-18"}},
+		{"state and since", a2a.TaskFilter{States: []a2aSchema.TaskState{a2aSchema.TaskStateInputRequired}, Since: base.Add(5 * time.Minute)}, []string{"task-07", "task-12", "task-17"}},
+		{"state, session and since", a2a.TaskFilter{States: []a2aSchema.TaskState{a2aSchema.TaskStateCompleted, a2aSchema.TaskStateWorking}, SessionID: "odd", Since: base.Add(10 * time.Minute)}, []string{"task-11", "task-13"}},
+		{"limit", a2a.TaskFilter{SessionID: "even", Limit: 3}, []string{"task-00", "task-02", "task-04"}},
+		{"no match", a2a.TaskFilter{States: []a2aSchema.TaskState{a2aSchema.TaskStateCanceled}}, []string{}},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			tasks, _, err := store.List(context.Background(), tc.filter)
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, taskIDs(tasks))
+		})
+	}
+}
+
+func TestInMemoryTaskStoreListCreatedAfterIgnoresUpdates(t *testing.T) {
+	base := time.Date(2025, 5, 1, 12, 0, 0, 0, time.UTC)
+	store := a2a.NewInMemoryTaskStore()
+	// An old task updated recently and a newer task never updated since its creation
+	require.NoError(t, store.Save(context.Background(), &a2aSchema.Task{ID: "old", CreatedAt: base, Status: a2aSchema.TaskStatus{Timestamp: base.Add(time.Hour)}}))
+	require.NoError(t, store.Save(context.Background(), &a2aSchema.Task{ID: "new", CreatedAt: base.Add(30 * time.Minute), Status: a2aSchema.TaskStatus{Timestamp: base.Add(30 * time.Minute)}}))
+
+	tasks, _, err := store.List(context.Background(), a2a.TaskFilter{CreatedAfter: base.Add(10 * time.Minute)})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"new"}, taskIDs(tasks))
+	tasks, _, err = store.List(context.Background(), a2a.TaskFilter{Since: base.Add(10 * time.Minute)})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"old", "new"}, taskIDs(tasks))
+	tasks, _, err = store.List(context.Background(), a2a.TaskFilter{CreatedAfter: base.Add(30 * time.Minute)})
+	require.NoError(t, err)
+	assert.Empty(t, tasks, "CreatedAfter is exclusive")
+}
+
+func TestInMemoryTaskStoreListPagination(t *testing.T) {
+	base := time.Date(2025, 5, 1, 12, 0, 0, 0, time.UTC)
+	store := a2a.NewInMemoryTaskStore()
+	seedTasks(t, store, base)
+
+	filter := a2a.TaskFilter{SessionID: "odd", Limit: 3}
+	var pages [][]string
+	for {
+		tasks, next, err := store.List(context.Background(), filter)
+		require.NoError(t, err)
+		pages = append(pages, taskIDs(tasks))
+		if next == "" {
+			break
+		}
+		filter.Cursor = next
+	}
+	assert.Equal(t, [][]string{
+		{"task-01", "task-03", "task-05"},
+		{"task-07", "task-09", "task-11"},
+		{"task-13", "task-15", "task-17"},
+		{"task-19"},
+	}, pages)
+
+	_, _, err := store.List(context.Background(), a2a.TaskFilter{Cursor: "not a cursor"})
+	assert.ErrorIs(t, err, a2a.ErrInvalidCursor)
+}
+
+func TestInMemoryTaskStoreListPaginationBoundaries(t *testing.T) {
+	base := time.Date(2025, 5, 1, 12, 0, 0, 0, time.UTC)
+	store := a2a.NewInMemoryTaskStore()
+	seedTasks(t, store, base)
+	odd := a2a.TaskFilter{SessionID: "odd"} // 10 tasks
+
+	for _, limit := range []int{10, 11} {
+		t.Run(fmt.Sprintf("limit %d covers every match", limit), func(t *testing.T) {
+			tasks, next, err := store.List(context.Background(), a2a.TaskFilter{SessionID: "odd", Limit: limit})
+			require.NoError(t, err)
+			assert.Len(t, tasks, 10)
+			assert.Empty(t, next)
+		})
+	}
+
+	t.Run("limit one less than the matches", func(t *testing.T) {
+		filter := odd
+		filter.Limit = 9
+		tasks, next, err := store.List(context.Background(), filter)
+		require.NoError(t, err)
+		assert.Len(t, tasks, 9)
+		require.NotEmpty(t, next)
+		filter.Cursor = next
+		tasks, next, err = store.List(context.Background(), filter)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"task-19"}, taskIDs(tasks))
+		assert.Empty(t, next)
+	})
+
+	t.Run("cursor survives removal of its task", func(t *testing.T) {
+		store := a2a.NewInMemoryTaskStore()
+		seedTasks(t, store, base)
+		filter := a2a.TaskFilter{SessionID: "odd", Limit: 2}
+		_, next, err := store.List(context.Background(), filter)
+		require.NoError(t, err)
+		require.NoError(t, store.Delete(context.Background(), "task-03"))
+		filter.Cursor = next
+		tasks, _, err := store.List(context.Background(), filter)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"task-05", "task-07"}, taskIDs(tasks))
+	})
+
+	t.Run("updated tasks keep their place", func(t *testing.T) {
+		store := a2a.NewInMemoryTaskStore()
+		seedTasks(t, store, base)
+		filter := a2a.TaskFilter{SessionID: "odd", Limit: 2}
+		_, next, err := store.List(context.Background(), filter)
+		require.NoError(t, err)
+		updated, err := store.Load(context.Background(), "task-01")
+		require.NoError(t, err)
+		updated.Status.Timestamp = base.Add(time.Hour)
+		require.NoError(t, store.Save(context.Background(), updated))
+		filter.Cursor = next
+		tasks, _, err := store.List(context.Background(), filter)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"task-05", "task-07"}, taskIDs(tasks))
+	})
+
+	t.Run("tasks created at the same time are paged by ID", func(t *testing.T) {
+		store := a2a.NewInMemoryTaskStore()
+		for _, id := range []string{"c", "a", "d", "b"} {
+			require.NoError(t, store.Save(context.Background(), &a2aSchema.Task{ID: id, CreatedAt: base, Status: a2aSchema.TaskStatus{Timestamp: base}}))
+		}
+		first, next, err := store.List(context.Background(), a2a.TaskFilter{Limit: 2})
+		require.NoError(t, err)
+		second, _, err := store.List(context.Background(), a2a.TaskFilter{Limit: 2, Cursor: next})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"a", "b"}, taskIDs(first))
+		assert.Equal(t, []string{"c", "d"}, taskIDs(second))
+	})
+
+	t.Run("empty store", func(t *testing.T) {
+		tasks, next, err := a2a.NewInMemoryTaskStore().List(context.Background(), a2a.TaskFilter{Limit: 5})
+		require.NoError(t, err)
+		assert.Empty(t, tasks)
+		assert.Empty(t, next)
+	})
+}
+
+func TestInMemoryTaskStoreListMetadataFilter(t *testing.T) {
+	base := time.Date(2025, 5, 1, 12, 0, 0, 0, time.UTC)
+	store := a2a.NewInMemoryTaskStore()
+	tenants := []string{"acme", "globex", "initech", "acme", "globex", "initech", "acme", "globex", "initech", ""}
+	for i, tenant := range tenants {
+		metadata := map[string]interface{}{
+			"priority": i % 3,
+			"config":   map[string]interface{}{"env": []string{"dev", "prod"}[i%2], "region": "eu"},
+		}
+		if tenant != "" {
+			metadata["tenantID"] = tenant
+		}
+		require.NoError(t, store.Save(context.Background(), &a2aSchema.Task{
+			ID:       fmt.Sprintf("task-%02d", i),
+			Status:   a2aSchema.TaskStatus{State: a2aSchema.TaskStateWorking, Timestamp: base.Add(time.Duration(i) * time.Minute)},
+			Metadata: &metadata,
+		}))
+	}
+	require.NoError(t, store.Save(context.Background(), &a2aSchema.Task{ID: "no-metadata", Status: a2aSchema.TaskStatus{Timestamp: base}}))
+
+	cases := []struct {
+		name   string
+		filter map[string]interface{}
+		want   []string
+	}{
+		{"tenant", map[string]interface{}{"tenantID": "acme"}, []string{"task-00", "task-03", "task-06"}},
+		{"nested", map[string]interface{}{"config": map[string]interface{}{"env": "prod"}}, []string{"task-01", "task-03", "task-05", "task-07", "task-09"}},
+		{"tenant and nested", map[string]interface{}{"tenantID": "acme", "config": map[string]interface{}{"env": "prod"}}, []string{"task-03"}},
+		{"number", map[string]interface{}{"priority": 2}, []string{"task-02", "task-05", "task-08"}},
+		{"type mismatch", map[string]interface{}{"config": "prod"}, []string{}},
+		{"unknown tenant", map[string]interface{}{"tenantID": "umbrella"}, []string{}},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			tasks, _, err := store.List(context.Background(), a2a.TaskFilter{MetadataFilter: tc.filter})
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, taskIDs(tasks))
+		})
+	}
+}
+
+func TestInMemoryTaskStoreTTL(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("task TTL", func(t *testing.T) {
+		store := a2a.NewInMemoryTaskStore()
+		require.NoError(t, store.SaveWithTTL(ctx, &a2aSchema.Task{ID: "short"}, 50*time.Millisecond))
+		require.NoError(t, store.Save(ctx, &a2aSchema.Task{ID: "short"})) // Keeps the task's TTL
+		require.NoError(t, store.Save(ctx, &a2aSchema.Task{ID: "forever"}))
+		_, err := store.Load(ctx, "short")
+		require.NoError(t, err)
+
+		time.Sleep(100 * time.Millisecond)
+		_, err = store.Load(ctx, "short")
+		sharedtesting.AssertJSONRPCError(t, nil, err, a2aSchema.ErrorCodeTaskNotFound)
+		_, err = store.Load(ctx, "forever")
+		assert.NoError(t, err)
+		tasks, _, err := store.List(ctx, a2a.TaskFilter{})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"forever"}, taskIDs(tasks))
+	})
+
+	t.Run("default TTL", func(t *testing.T) {
+		store := a2a.NewInMemoryTaskStore(a2a.WithDefaultTTL(200 * time.Millisecond))
+		require.NoError(t, store.Save(ctx, &a2aSchema.Task{ID: "refreshed"}))
+		time.Sleep(120 * time.Millisecond)
+		require.NoError(t, store.Save(ctx, &a2aSchema.Task{ID: "refreshed"})) // Refreshes the TTL
+		time.Sleep(120 * time.Millisecond)
+		_, err := store.Load(ctx, "refreshed")
+		require.NoError(t, err)
+		time.Sleep(250 * time.Millisecond)
+		_, err = store.Load(ctx, "refreshed")
+		sharedtesting.AssertJSONRPCError(t, nil, err, a2aSchema.ErrorCodeTaskNotFound)
+	})
+}
+
+func TestInMemoryTaskStoreGC(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	store := a2a.NewInMemoryTaskStore()
+	for i := 0; i < 5; i++ {
+		require.NoError(t, store.SaveWithTTL(ctx, &a2aSchema.Task{ID: fmt.Sprintf("expiring-%d", i)}, 30*time.Millisecond))
+	}
+	require.NoError(t, store.Save(ctx, &a2aSchema.Task{ID: "kept"}))
+	require.Equal(t, 6, store.Len())
+
+	store.StartGC(ctx, 10*time.Millisecond)
+	require.Eventually(t, func() bool { return store.Len() == 1 }, time.Second, 10*time.Millisecond)
+	_, err := store.Load(ctx, "kept")
+	assert.NoError(t, err)
+}
+
+func TestInMemoryTaskStoreLRUEviction(t *testing.T) {
+	ctx := context.Background()
+	store := a2a.NewInMemoryTaskStore(a2a.WithMaxTasks(3))
+	for _, id := range []string{"a", "b", "c"} {
+		require.NoError(t, store.Save(ctx, &a2aSchema.Task{ID: id}))
+	}
+	_, err := store.Load(ctx, "a") // "b" is now the least recently used
+	require.NoError(t, err)
+	require.NoError(t, store.Save(ctx, &a2aSchema.Task{ID: "d"}))
+
+	assert.Equal(t, 3, store.Len())
+	_, err = store.Load(ctx, "b")
+	sharedtesting.AssertJSONRPCError(t, nil, err, a2aSchema.ErrorCodeTaskNotFound)
+	for _, id := range []string{"a", "c", "d"} {
+		_, err := store.Load(ctx, id)
+		assert.NoError(t, err, id)
+	}
+
+	require.NoError(t, store.Save(ctx, &a2aSchema.Task{ID: "c"})) // Saving an existing task evicts nothing
+	assert.Equal(t, 3, store.Len())
+}
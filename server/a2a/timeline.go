@@ -0,0 +1,34 @@
+package a2a
+
+import (
+	"slices"
+	"time"
+
+	a2aSchema "github.com/gate4ai/gate4ai/shared/a2a/2025-draft/schema"
+)
+
+// Phases recorded in a task's timeline when WithTimelineRecording is enabled.
+const (
+	TimelinePhaseLoadOrCreate = "loadOrCreate" // Loading the task from the store, or creating it
+	TimelinePhaseHandler      = "handler"      // Running the A2AHandler
+	TimelinePhaseUpdate       = "update"       // Applying one update yielded by the handler
+	TimelinePhaseSave         = "save"         // Saving the final state (tasks/send only)
+)
+
+// WithTimelineRecording records how long each execution phase of a task takes in
+// Task.Timeline and persists it with the task. Clients read it with tasks/get and
+// includeTimeline. Disabled by default.
+func WithTimelineRecording(enabled bool) A2AOption {
+	return func(ac *A2ACapability) {
+		ac.timelineRecording = enabled
+	}
+}
+
+// recordPhase appends a timeline entry for a phase that ran from start to end.
+func (ac *A2ACapability) recordPhase(task *a2aSchema.Task, phase string, start time.Time, end time.Time) {
+	if !ac.timelineRecording || task == nil {
+		return
+	}
+	// Clip so copies of the task sharing the backing array never overwrite each other's entries
+	task.Timeline = append(slices.Clip(task.Timeline), a2aSchema.TimelineEntry{Phase: phase, Timestamp: start, Duration: end.Sub(start)})
+}
@@ -0,0 +1,67 @@
+package a2a_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/gate4ai/gate4ai/server/a2a"
+	"github.com/gate4ai/gate4ai/server/transport"
+	"github.com/gate4ai/gate4ai/shared"
+	a2aSchema "github.com/gate4ai/gate4ai/shared/a2a/2025-draft/schema"
+	"github.com/gate4ai/gate4ai/shared/config"
+	sharedtesting "github.com/gate4ai/gate4ai/shared/testing"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestTaskTimelineRecording(t *testing.T) {
+	const handlerDelay = 100 * time.Millisecond
+	handler := func(ctx context.Context, task *a2aSchema.Task, updates chan<- a2a.A2AYieldUpdate, logger *zap.Logger) error {
+		time.Sleep(handlerDelay)
+		updates <- a2a.A2AYieldUpdate{Status: &a2aSchema.TaskStatus{State: a2aSchema.TaskStateCompleted}}
+		return nil
+	}
+	// runTask completes a task and returns it as tasks/get reports it with includeTimeline set as given.
+	runTask := func(t *testing.T, includeTimeline bool, options ...a2a.A2AOption) *a2aSchema.Task {
+		logger := zap.NewNop()
+		manager, err := transport.NewManager(logger, config.NewInternalConfig())
+		require.NoError(t, err)
+		handlers := a2a.NewA2ACapability(logger, manager, a2a.NewInMemoryTaskStore(), handler, options...).GetHandlers()
+
+		result, err := handlers["tasks/send"](sharedtesting.BuildMessage("tasks/send", a2aSchema.TaskSendParams{
+			ID:      "slow-task",
+			Message: a2aSchema.Message{Role: "user", Parts: []a2aSchema.Part{{Type: shared.PointerTo("text"), Text: shared.PointerTo("go")}}},
+		}))
+		sent := sharedtesting.AssertJSONRPCSuccess[*a2aSchema.Task](t, result, err)
+		assert.Nil(t, sent.Timeline, "tasks/send must not return the timeline")
+
+		result, err = handlers["tasks/get"](sharedtesting.BuildMessage("tasks/get", a2aSchema.TaskQueryParams{ID: "slow-task", IncludeTimeline: includeTimeline}))
+		return sharedtesting.AssertJSONRPCSuccess[*a2aSchema.Task](t, result, err)
+	}
+
+	t.Run("enabled", func(t *testing.T) {
+		task := runTask(t, true, a2a.WithTimelineRecording(true))
+		phases := map[string]a2aSchema.TimelineEntry{}
+		for _, entry := range task.Timeline {
+			phases[entry.Phase] = entry
+		}
+		require.Contains(t, phases, a2a.TimelinePhaseLoadOrCreate)
+		require.Contains(t, phases, a2a.TimelinePhaseUpdate)
+		require.Contains(t, phases, a2a.TimelinePhaseSave)
+		require.Contains(t, phases, a2a.TimelinePhaseHandler)
+		assert.GreaterOrEqual(t, phases[a2a.TimelinePhaseHandler].Duration, handlerDelay)
+		assert.False(t, phases[a2a.TimelinePhaseHandler].Timestamp.Before(phases[a2a.TimelinePhaseLoadOrCreate].Timestamp))
+	})
+
+	t.Run("not requested", func(t *testing.T) {
+		task := runTask(t, false, a2a.WithTimelineRecording(true))
+		assert.Nil(t, task.Timeline)
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		task := runTask(t, true)
+		assert.Nil(t, task.Timeline)
+	})
+}
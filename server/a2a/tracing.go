@@ -0,0 +1,97 @@
+package a2a
+
+import (
+	"context"
+	"errors"
+
+	a2aSchema "github.com/gate4ai/gate4ai/shared/a2a/2025-draft/schema"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// tracerName is the instrumentation scope of the spans created by this package.
+const tracerName = "github.com/gate4ai/gate4ai/server/a2a"
+
+// Spans recorded for each task run by tasks/send and tasks/sendSubscribe.
+const (
+	SpanTaskSend        = "a2a.task.send"         // The whole task run, parent of the spans below
+	SpanTaskHandler     = "a2a.task.handler"      // The A2AHandler call; its context is passed to the handler
+	SpanTaskUpdateApply = "a2a.task.update.apply" // Applying one update yielded by the handler
+)
+
+// Spans recorded for TaskStore calls, children of the task or request span that made them.
+const (
+	SpanTaskStoreSave = "a2a.task.store.save"
+	SpanTaskStoreLoad = "a2a.task.store.load"
+)
+
+// WithTracerProvider records task spans with tp instead of the global OpenTelemetry
+// tracer provider.
+func WithTracerProvider(tp trace.TracerProvider) A2AOption {
+	return func(ac *A2ACapability) {
+		ac.tracerProvider = tp
+	}
+}
+
+// tracer returns the tracer for task spans. The global provider is looked up on each call,
+// so it may be installed after the capability is created.
+func (ac *A2ACapability) tracer() trace.Tracer {
+	if ac.tracerProvider != nil {
+		return ac.tracerProvider.Tracer(tracerName)
+	}
+	return otel.GetTracerProvider().Tracer(tracerName)
+}
+
+// startTaskSpan starts the SpanTaskSend span of a task run.
+func (ac *A2ACapability) startTaskSpan(ctx context.Context, taskID string, sessionID string) (context.Context, trace.Span) {
+	return ac.tracer().Start(ctx, SpanTaskSend, trace.WithAttributes(
+		attribute.String("task_id", taskID),
+		attribute.String("session_id", sessionID),
+	))
+}
+
+// applyUpdate applies update to task like applyUpdateToTask, inside a SpanTaskUpdateApply span.
+func (ac *A2ACapability) applyUpdate(ctx context.Context, task *a2aSchema.Task, update A2AYieldUpdate) (*a2aSchema.Task, error) {
+	_, span := ac.tracer().Start(ctx, SpanTaskUpdateApply)
+	defer span.End()
+	updated, err := ac.applyUpdateToTask(task, update)
+	setSpanError(span, err)
+	return updated, err
+}
+
+// setSpanError marks span as failed if err is not nil.
+func setSpanError(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+}
+
+// saveTask saves task inside a SpanTaskStoreSave span.
+func (ac *A2ACapability) saveTask(ctx context.Context, task *a2aSchema.Task) error {
+	ctx, span := ac.tracer().Start(ctx, SpanTaskStoreSave, trace.WithAttributes(
+		attribute.String("task_id", task.ID),
+		attribute.String("task_state", string(task.Status.State)),
+	))
+	defer span.End()
+	err := ac.saveObserved(ctx, task)
+	setSpanError(span, err)
+	return err
+}
+
+// loadTask loads a task inside a SpanTaskStoreLoad span. A missing task is not a span error,
+// as callers such as loadOrCreateTask expect it.
+func (ac *A2ACapability) loadTask(ctx context.Context, taskID string) (*a2aSchema.Task, error) {
+	ctx, span := ac.tracer().Start(ctx, SpanTaskStoreLoad, trace.WithAttributes(attribute.String("task_id", taskID)))
+	defer span.End()
+	task, err := ac.taskStore.Load(ctx, taskID)
+	var jsonRPCErr *a2aSchema.JSONRPCError
+	if errors.As(err, &jsonRPCErr) && jsonRPCErr.Code == a2aSchema.ErrorCodeTaskNotFound {
+		span.SetAttributes(attribute.Bool("task_found", false))
+	} else {
+		setSpanError(span, err)
+	}
+	return task, err
+}
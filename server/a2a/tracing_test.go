@@ -0,0 +1,141 @@
+package a2a_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gate4ai/gate4ai/server/a2a"
+	"github.com/gate4ai/gate4ai/server/transport"
+	"github.com/gate4ai/gate4ai/shared"
+	a2aSchema "github.com/gate4ai/gate4ai/shared/a2a/2025-draft/schema"
+	"github.com/gate4ai/gate4ai/shared/config"
+	sharedtesting "github.com/gate4ai/gate4ai/shared/testing"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+)
+
+func TestTaskSpans(t *testing.T) {
+	// Yields two updates and records the span it was called with
+	handlerSpans := make(chan trace.SpanContext, 1)
+	handler := func(ctx context.Context, task *a2aSchema.Task, updates chan<- a2a.A2AYieldUpdate, logger *zap.Logger) error {
+		handlerSpans <- trace.SpanContextFromContext(ctx)
+		updates <- a2a.A2AYieldUpdate{Status: &a2aSchema.TaskStatus{State: a2aSchema.TaskStateWorking}}
+		updates <- a2a.A2AYieldUpdate{Status: &a2aSchema.TaskStatus{State: a2aSchema.TaskStateCompleted}}
+		return nil
+	}
+	params := a2aSchema.TaskSendParams{
+		ID:      "traced-task",
+		Message: a2aSchema.Message{Role: "user", Parts: []a2aSchema.Part{{Type: shared.PointerTo("text"), Text: shared.PointerTo("go")}}},
+	}
+
+	// assertSpanTree checks that the spans form one a2a.task.send tree and were all ended.
+	assertSpanTree := func(t *testing.T, recorder *tracetest.SpanRecorder) {
+		require.Eventually(t, func() bool { return len(recorder.Ended()) == len(recorder.Started()) }, time.Second, 10*time.Millisecond, "All started spans must end")
+		byName := map[string][]sdktrace.ReadOnlySpan{}
+		for _, span := range recorder.Ended() {
+			byName[span.Name()] = append(byName[span.Name()], span)
+		}
+		require.Len(t, byName[a2a.SpanTaskSend], 1)
+		require.Len(t, byName[a2a.SpanTaskHandler], 1)
+		require.Len(t, byName[a2a.SpanTaskUpdateApply], 2)
+
+		root := byName[a2a.SpanTaskSend][0]
+		assert.False(t, root.Parent().IsValid(), "The send span must be a root span")
+		assert.Contains(t, root.Attributes(), attribute.String("task_id", "traced-task"))
+		for _, name := range []string{a2a.SpanTaskHandler, a2a.SpanTaskUpdateApply} {
+			for _, span := range byName[name] {
+				assert.Equal(t, root.SpanContext().SpanID(), span.Parent().SpanID(), "%s must be a child of the send span", name)
+				assert.Equal(t, root.SpanContext().TraceID(), span.SpanContext().TraceID())
+			}
+		}
+		assert.Equal(t, byName[a2a.SpanTaskHandler][0].SpanContext(), <-handlerSpans, "The handler must receive its span in ctx")
+	}
+
+	t.Run("tasks/send", func(t *testing.T) {
+		recorder := tracetest.NewSpanRecorder()
+		tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+		logger := zap.NewNop()
+		manager, err := transport.NewManager(logger, config.NewInternalConfig())
+		require.NoError(t, err)
+		handlers := a2a.NewA2ACapability(logger, manager, a2a.NewInMemoryTaskStore(), handler, a2a.WithTracerProvider(tp)).GetHandlers()
+
+		result, err := handlers["tasks/send"](sharedtesting.BuildMessage("tasks/send", params))
+		sharedtesting.AssertJSONRPCSuccess[*a2aSchema.Task](t, result, err)
+		assertSpanTree(t, recorder)
+	})
+
+	t.Run("tasks/sendSubscribe", func(t *testing.T) {
+		recorder := tracetest.NewSpanRecorder()
+		tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+		server := newA2ATestServer(t, handler, a2a.WithTracerProvider(tp))
+
+		events := postA2A(t, server.URL, "tasks/sendSubscribe", params)
+		require.NotEmpty(t, events)
+		assertSpanTree(t, recorder)
+	})
+}
+
+func TestTaskSpansContinueRequestTrace(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	handler := func(ctx context.Context, task *a2aSchema.Task, updates chan<- a2a.A2AYieldUpdate, logger *zap.Logger) error {
+		updates <- a2a.A2AYieldUpdate{Status: &a2aSchema.TaskStatus{State: a2aSchema.TaskStateCompleted}}
+		return nil
+	}
+	logger := zap.NewNop()
+	cfg := config.NewInternalConfig()
+	cfg.AuthorizationTypeValue = config.NotAuthorizedEverywhere
+	cfg.A2AAgentNameValue = "traced-agent"
+	cfg.A2AAgentVersionValue = "1.0.0"
+	manager, err := transport.NewManager(logger, cfg)
+	require.NoError(t, err)
+	tr, err := transport.New(manager, logger, cfg, transport.WithTracerProvider(tp))
+	require.NoError(t, err)
+	manager.AddCapability(a2a.NewA2ACapability(logger, manager, a2a.NewInMemoryTaskStore(), handler, a2a.WithTracerProvider(tp)))
+	agentCard, err := cfg.GetA2AAgentCard(transport.A2A_PATH)
+	require.NoError(t, err)
+	mux := http.NewServeMux()
+	tr.RegisterA2AHandlers(mux, agentCard)
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	for _, method := range []string{"tasks/send", "tasks/sendSubscribe"} {
+		t.Run(method, func(t *testing.T) {
+			recorder.Reset()
+			postA2A(t, server.URL, method, a2aSchema.TaskSendParams{
+				ID:      "traced-" + method,
+				Message: a2aSchema.Message{Role: "user", Parts: textParts("go")},
+			})
+			require.Eventually(t, func() bool { return len(recorder.Ended()) > 0 && len(recorder.Ended()) == len(recorder.Started()) }, time.Second, 10*time.Millisecond)
+
+			byName := map[string][]sdktrace.ReadOnlySpan{}
+			for _, span := range recorder.Ended() {
+				byName[span.Name()] = append(byName[span.Name()], span)
+			}
+			require.Len(t, byName[transport.SpanPOST], 1)
+			require.Len(t, byName[a2a.SpanTaskSend], 1)
+			request, task := byName[transport.SpanPOST][0], byName[a2a.SpanTaskSend][0]
+			assert.Equal(t, request.SpanContext().SpanID(), task.Parent().SpanID(), "The task span must be a child of the request span")
+			assert.Contains(t, request.Attributes(), attribute.String("protocol", "A2A"))
+
+			require.NotEmpty(t, byName[a2a.SpanTaskStoreSave])
+			require.NotEmpty(t, byName[a2a.SpanTaskStoreLoad])
+			for _, name := range []string{a2a.SpanTaskStoreSave, a2a.SpanTaskStoreLoad} {
+				for _, span := range byName[name] {
+					assert.Equal(t, request.SpanContext().TraceID(), span.SpanContext().TraceID(), "%s must be in the request trace", name)
+					assert.Contains(t, span.Attributes(), attribute.String("task_id", "traced-"+method))
+				}
+			}
+			saves := byName[a2a.SpanTaskStoreSave]
+			assert.Contains(t, saves[len(saves)-1].Attributes(), attribute.String("task_state", string(a2aSchema.TaskStateCompleted)))
+		})
+	}
+}
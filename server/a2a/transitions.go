@@ -0,0 +1,37 @@
+package a2a
+
+import (
+	"context"
+
+	a2aSchema "github.com/gate4ai/gate4ai/shared/a2a/2025-draft/schema"
+)
+
+// StateTransitionObserver is called after a task is saved in a different state than the one
+// stored before. from is empty for new tasks. It runs on the saving goroutine and must not block.
+type StateTransitionObserver func(taskID string, from a2aSchema.TaskState, to a2aSchema.TaskState)
+
+// WithStateTransitionObserver reports every task state change to observer, e.g. to export
+// metrics. Finding the previous state costs one extra store Load per save.
+func WithStateTransitionObserver(observer StateTransitionObserver) A2AOption {
+	return func(ac *A2ACapability) {
+		ac.stateObserver = observer
+	}
+}
+
+// saveObserved saves task to the store and reports its state transition to the observer, if any.
+func (ac *A2ACapability) saveObserved(ctx context.Context, task *a2aSchema.Task) error {
+	if ac.stateObserver == nil {
+		return ac.taskStore.Save(ctx, task)
+	}
+	var from a2aSchema.TaskState
+	if previous, err := ac.taskStore.Load(ctx, task.ID); err == nil {
+		from = previous.Status.State
+	}
+	if err := ac.taskStore.Save(ctx, task); err != nil {
+		return err
+	}
+	if from != task.Status.State {
+		ac.stateObserver(task.ID, from, task.Status.State)
+	}
+	return nil
+}
@@ -0,0 +1,57 @@
+package a2a_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/gate4ai/gate4ai/server/a2a"
+	"github.com/gate4ai/gate4ai/server/transport"
+	a2aSchema "github.com/gate4ai/gate4ai/shared/a2a/2025-draft/schema"
+	"github.com/gate4ai/gate4ai/shared/config"
+	sharedtesting "github.com/gate4ai/gate4ai/shared/testing"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+type stateTransition struct {
+	taskID   string
+	from, to a2aSchema.TaskState
+}
+
+func TestStateTransitionObserver(t *testing.T) {
+	var mu sync.Mutex
+	var transitions []stateTransition
+	observer := func(taskID string, from a2aSchema.TaskState, to a2aSchema.TaskState) {
+		mu.Lock()
+		defer mu.Unlock()
+		transitions = append(transitions, stateTransition{taskID: taskID, from: from, to: to})
+	}
+	handler := func(ctx context.Context, task *a2aSchema.Task, updates chan<- a2a.A2AYieldUpdate, logger *zap.Logger) error {
+		updates <- a2a.A2AYieldUpdate{Status: &a2aSchema.TaskStatus{State: a2aSchema.TaskStateInputRequired}}
+		return nil
+	}
+	manager, err := transport.NewManager(zap.NewNop(), config.NewInternalConfig())
+	require.NoError(t, err)
+	capability := a2a.NewA2ACapability(zap.NewNop(), manager, a2a.NewInMemoryTaskStore(), handler, a2a.WithStateTransitionObserver(observer))
+
+	send := func() {
+		result, err := capability.GetHandlers()["tasks/send"](sharedtesting.BuildMessage("tasks/send", a2aSchema.TaskSendParams{
+			ID:      "observed-task",
+			Message: a2aSchema.Message{Role: "user", Parts: textParts("go")},
+		}))
+		sharedtesting.AssertJSONRPCSuccess[*a2aSchema.Task](t, result, err)
+	}
+	send()
+	send() // The user's answer resumes the task waiting for input
+
+	mu.Lock()
+	defer mu.Unlock()
+	assert.Equal(t, []stateTransition{
+		{taskID: "observed-task", from: "", to: a2aSchema.TaskStateSubmitted},
+		{taskID: "observed-task", from: a2aSchema.TaskStateSubmitted, to: a2aSchema.TaskStateInputRequired},
+		{taskID: "observed-task", from: a2aSchema.TaskStateInputRequired, to: a2aSchema.TaskStateSubmitted},
+		{taskID: "observed-task", from: a2aSchema.TaskStateSubmitted, to: a2aSchema.TaskStateInputRequired},
+	}, transitions)
+}
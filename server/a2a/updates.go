@@ -0,0 +1,51 @@
+package a2a
+
+// DefaultUpdateBufferSize is the number of updates a handler can yield before it blocks until
+// the capability has processed the earlier ones.
+const DefaultUpdateBufferSize = 20
+
+// WithUpdateBufferSize sets how many yielded updates are buffered per task run. 0 buffers
+// without limit, so fast handlers (e.g. streaming token by token) never wait for the capability
+// to save or send their updates.
+func WithUpdateBufferSize(n int) A2AOption {
+	return func(ac *A2ACapability) {
+		ac.updateBufferSize = n
+	}
+}
+
+// newUpdateChannels returns the channel the agent handler yields to and closes, and the channel
+// its updates are read from. They are the same channel unless buffering is unbounded.
+func (ac *A2ACapability) newUpdateChannels() (chan A2AYieldUpdate, <-chan A2AYieldUpdate) {
+	if ac.updateBufferSize > 0 {
+		updates := make(chan A2AYieldUpdate, ac.updateBufferSize)
+		return updates, updates
+	}
+	in, out := make(chan A2AYieldUpdate), make(chan A2AYieldUpdate)
+	go forwardUnbounded(in, out)
+	return in, out
+}
+
+// forwardUnbounded queues everything received on in and sends it on out in order, closing out
+// after in is closed and the queue is empty.
+func forwardUnbounded(in <-chan A2AYieldUpdate, out chan<- A2AYieldUpdate) {
+	defer close(out)
+	var queue []A2AYieldUpdate
+	for in != nil || len(queue) > 0 {
+		var send chan<- A2AYieldUpdate // nil while the queue is empty, disabling the send case
+		var next A2AYieldUpdate
+		if len(queue) > 0 {
+			send, next = out, queue[0]
+		}
+		select {
+		case update, ok := <-in:
+			if !ok {
+				in = nil
+				continue
+			}
+			queue = append(queue, update)
+		case send <- next:
+			queue[0] = A2AYieldUpdate{} // Release the sent update
+			queue = queue[1:]
+		}
+	}
+}
@@ -0,0 +1,60 @@
+package a2a_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/gate4ai/gate4ai/server/a2a"
+	"github.com/gate4ai/gate4ai/server/transport"
+	"github.com/gate4ai/gate4ai/shared"
+	a2aSchema "github.com/gate4ai/gate4ai/shared/a2a/2025-draft/schema"
+	"github.com/gate4ai/gate4ai/shared/config"
+	sharedtesting "github.com/gate4ai/gate4ai/shared/testing"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestUpdateBufferSize(t *testing.T) {
+	for _, size := range []int{3, 0} {
+		t.Run(fmt.Sprintf("buffer %d", size), func(t *testing.T) {
+			// Yields 100 artifact chunks as fast as possible
+			handler := func(ctx context.Context, task *a2aSchema.Task, updates chan<- a2a.A2AYieldUpdate, logger *zap.Logger) error {
+				for i := range 100 {
+					updates <- a2a.A2AYieldUpdate{Artifact: &a2aSchema.Artifact{Parts: textParts(fmt.Sprint(i)), Append: shared.PointerTo(i > 0)}}
+				}
+				updates <- a2a.A2AYieldUpdate{Status: &a2aSchema.TaskStatus{State: a2aSchema.TaskStateCompleted}}
+				return nil
+			}
+			logger := zap.NewNop()
+			manager, err := transport.NewManager(logger, config.NewInternalConfig())
+			require.NoError(t, err)
+			handlers := a2a.NewA2ACapability(logger, manager, a2a.NewInMemoryTaskStore(), handler, a2a.WithUpdateBufferSize(size)).GetHandlers()
+
+			var result interface{}
+			done := make(chan struct{})
+			go func() {
+				defer close(done)
+				result, err = handlers["tasks/send"](sharedtesting.BuildMessage("tasks/send", a2aSchema.TaskSendParams{
+					ID:      "fast-producer",
+					Message: a2aSchema.Message{Role: "user", Parts: textParts("go")},
+				}))
+			}()
+			select {
+			case <-done:
+			case <-time.After(5 * time.Second):
+				t.Fatal("tasks/send did not finish, the handler is blocked")
+			}
+			task := sharedtesting.AssertJSONRPCSuccess[*a2aSchema.Task](t, result, err)
+
+			require.Len(t, task.Artifacts, 1)
+			parts := partTexts(task.Artifacts[0].Parts)
+			require.Len(t, parts, 100)
+			for i, text := range parts {
+				assert.Equal(t, fmt.Sprint(i), text)
+			}
+		})
+	}
+}
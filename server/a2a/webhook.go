@@ -0,0 +1,163 @@
+package a2a
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/gate4ai/gate4ai/server/transport"
+	"github.com/gate4ai/gate4ai/shared"
+	a2aSchema "github.com/gate4ai/gate4ai/shared/a2a/2025-draft/schema"
+	"go.uber.org/zap"
+)
+
+const (
+	// WebhookSignatureHeader carries the hex HMAC-SHA256 of the webhook body keyed with the webhook secret.
+	WebhookSignatureHeader = "X-Gate4ai-Signature"
+	// webhookAttempts is how many times a webhook delivery is tried before giving up.
+	webhookAttempts = 3
+	// DefaultWebhookRetryBackoff is the delay before the first retry; it doubles for each further retry.
+	DefaultWebhookRetryBackoff = time.Second
+)
+
+// TaskWebhookParams are the parameters of tasks/setWebhook.
+type TaskWebhookParams struct {
+	ID     string `json:"id"`
+	URL    string `json:"url"`
+	Secret string `json:"secret"`
+}
+
+type taskWebhook struct {
+	url    string
+	secret string
+}
+
+// WithWebhookRetryBackoff sets the delay before the first webhook retry.
+func WithWebhookRetryBackoff(backoff time.Duration) A2AOption {
+	return func(ac *A2ACapability) {
+		ac.webhookBackoff = backoff
+	}
+}
+
+// SignWebhookBody returns the value of the WebhookSignatureHeader for body.
+func SignWebhookBody(body []byte, secret string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// handleTaskSetWebhook handles `tasks/setWebhook`: the task is POSTed to the URL once it reaches
+// a terminal state. The task must exist and belong to the caller; setting the webhook on a task
+// that already finished delivers right away.
+func (ac *A2ACapability) handleTaskSetWebhook(msg *shared.Message) (interface{}, error) {
+	logger := ac.logger.With(zap.String("sessionID", msg.Session.GetID()), zap.String("method", "tasks/setWebhook"))
+
+	var params TaskWebhookParams
+	if msg.Params == nil {
+		return nil, &shared.JSONRPCError{Code: shared.JSONRPCErrorInvalidParams, Message: "Missing params"}
+	}
+	if err := json.Unmarshal(*msg.Params, &params); err != nil {
+		logger.Error("Failed to unmarshal tasks/setWebhook params", zap.Error(err))
+		return nil, &shared.JSONRPCError{Code: shared.JSONRPCErrorInvalidParams, Message: err.Error()}
+	}
+	if params.ID == "" {
+		return nil, &shared.JSONRPCError{Code: shared.JSONRPCErrorInvalidParams, Message: "Task ID is required"}
+	}
+	if !validNotificationURL(params.URL) {
+		return nil, &shared.JSONRPCError{Code: shared.JSONRPCErrorInvalidParams, Message: fmt.Sprintf("Invalid webhook URL: %q", params.URL)}
+	}
+	logger = logger.With(zap.String("taskID", params.ID))
+
+	task, err := ac.loadOwnedTask(msg.Context(), params.ID, transport.GetUserId(msg.Session.GetParams()))
+	if err != nil {
+		logger.Warn("Failed to load task for webhook", zap.Error(err))
+		var jsonRPCErr *a2aSchema.JSONRPCError
+		if errors.As(err, &jsonRPCErr) {
+			return nil, shared.NewJSONRPCError(jsonRPCErr) // Unknown tasks and tasks of other users are not found
+		}
+		return nil, &shared.JSONRPCError{Code: shared.JSONRPCErrorInternal, Message: "Failed to load task state"}
+	}
+
+	ac.webhooksMu.Lock()
+	ac.webhooks[params.ID] = taskWebhook{url: params.URL, secret: params.Secret}
+	ac.webhooksMu.Unlock()
+	logger.Debug("Webhook set for task")
+
+	ac.notifyWebhook(task)
+	return params, nil
+}
+
+// validNotificationURL reports whether raw is an absolute http(s) URL.
+func validNotificationURL(raw string) bool {
+	parsed, err := url.Parse(raw)
+	return err == nil && (parsed.Scheme == "http" || parsed.Scheme == "https") && parsed.Host != ""
+}
+
+// notifyWebhook delivers a terminal task to its webhook in the background. Each webhook fires once.
+func (ac *A2ACapability) notifyWebhook(task *a2aSchema.Task) {
+	if !isTerminalState(task.Status.State) {
+		return
+	}
+	ac.webhooksMu.Lock()
+	webhook, exists := ac.webhooks[task.ID]
+	delete(ac.webhooks, task.ID)
+	ac.webhooksMu.Unlock()
+	if !exists {
+		return
+	}
+
+	body, err := json.Marshal(task)
+	if err != nil {
+		ac.logger.Error("Failed to marshal task for webhook", zap.String("taskID", task.ID), zap.Error(err))
+		return
+	}
+	go ac.deliverWebhook(task.ID, webhook, body)
+}
+
+// deliverWebhook POSTs body to the webhook, retrying with exponential backoff.
+func (ac *A2ACapability) deliverWebhook(taskID string, webhook taskWebhook, body []byte) {
+	logger := ac.logger.With(zap.String("taskID", taskID), zap.String("webhookURL", webhook.url))
+	signature := SignWebhookBody(body, webhook.secret)
+	client := &http.Client{Timeout: 10 * time.Second}
+	backoff := ac.webhookBackoff
+
+	for attempt := 1; attempt <= webhookAttempts; attempt++ {
+		err := postWebhook(client, webhook.url, body, signature)
+		if err == nil {
+			logger.Debug("Webhook delivered", zap.Int("attempt", attempt))
+			return
+		}
+		if attempt == webhookAttempts {
+			logger.Error("Webhook delivery failed, giving up", zap.Int("attempts", attempt), zap.Error(err))
+			return
+		}
+		logger.Warn("Webhook delivery failed, retrying", zap.Int("attempt", attempt), zap.Duration("backoff", backoff), zap.Error(err))
+		time.Sleep(backoff)
+		backoff *= 2
+	}
+}
+
+func postWebhook(client *http.Client, webhookURL string, body []byte, signature string) error {
+	req, err := http.NewRequest(http.MethodPost, webhookURL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(WebhookSignatureHeader, signature)
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook responded with HTTP %d", resp.StatusCode)
+	}
+	return nil
+}
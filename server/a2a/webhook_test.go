@@ -0,0 +1,154 @@
+package a2a_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/gate4ai/gate4ai/server/a2a"
+	"github.com/gate4ai/gate4ai/server/transport"
+	"github.com/gate4ai/gate4ai/shared"
+	a2aSchema "github.com/gate4ai/gate4ai/shared/a2a/2025-draft/schema"
+	"github.com/gate4ai/gate4ai/shared/config"
+	sharedtesting "github.com/gate4ai/gate4ai/shared/testing"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// newWebhookTestCapability creates a capability whose tasks complete with a "done" artifact
+// once release is closed.
+func newWebhookTestCapability(t *testing.T, release <-chan struct{}) *a2a.A2ACapability {
+	logger := zap.NewNop()
+	manager, err := transport.NewManager(logger, config.NewInternalConfig())
+	require.NoError(t, err)
+	handler := func(ctx context.Context, task *a2aSchema.Task, updates chan<- a2a.A2AYieldUpdate, logger *zap.Logger) error {
+		updates <- a2a.A2AYieldUpdate{Status: &a2aSchema.TaskStatus{State: a2aSchema.TaskStateWorking}}
+		<-release
+		updates <- a2a.A2AYieldUpdate{Artifact: &a2aSchema.Artifact{Parts: textParts("done")}}
+		updates <- a2a.A2AYieldUpdate{Status: &a2aSchema.TaskStatus{State: a2aSchema.TaskStateCompleted}}
+		return nil
+	}
+	return a2a.NewA2ACapability(logger, manager, a2a.NewInMemoryTaskStore(), handler, a2a.WithWebhookRetryBackoff(10*time.Millisecond))
+}
+
+func setWebhook(capability *a2a.A2ACapability, session shared.ISession, params a2a.TaskWebhookParams) (interface{}, error) {
+	msg := sharedtesting.BuildMessage("tasks/setWebhook", params)
+	msg.Session = session
+	return capability.GetHandlers()["tasks/setWebhook"](msg)
+}
+
+func sendWebhookTestTask(capability *a2a.A2ACapability, session shared.ISession, taskID string) (interface{}, error) {
+	msg := sharedtesting.BuildMessage("tasks/send", a2aSchema.TaskSendParams{
+		ID:      taskID,
+		Message: a2aSchema.Message{Role: "user", Parts: textParts("go")},
+	})
+	msg.Session = session
+	return capability.GetHandlers()["tasks/send"](msg)
+}
+
+func receiveWebhook(t *testing.T, deliveries <-chan receivedNotification, secret string) a2aSchema.Task {
+	var task a2aSchema.Task
+	select {
+	case delivery := <-deliveries:
+		assert.Equal(t, a2a.SignWebhookBody(delivery.body, secret), delivery.header.Get(a2a.WebhookSignatureHeader))
+		require.NoError(t, json.Unmarshal(delivery.body, &task))
+	case <-time.After(5 * time.Second):
+		t.Fatal("Webhook was not delivered")
+	}
+	return task
+}
+
+func TestTaskWebhookDeliveredOnCompletion(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		statuses []int
+	}{
+		{name: "first attempt"},
+		{name: "after retries", statuses: []int{http.StatusInternalServerError, http.StatusInternalServerError}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			receiver, deliveries, attempts := newNotificationReceiver(t, tc.statuses...)
+			release := make(chan struct{})
+			capability := newWebhookTestCapability(t, release)
+			session := userSession("webhook-session", "webhook-user")
+
+			sent := make(chan error, 1)
+			go func() {
+				_, err := sendWebhookTestTask(capability, session, "webhook-task")
+				sent <- err
+			}()
+			// The webhook can only be set once the task exists
+			require.Eventually(t, func() bool {
+				_, err := setWebhook(capability, session, a2a.TaskWebhookParams{ID: "webhook-task", URL: receiver.URL, Secret: "s3cret"})
+				return err == nil
+			}, 5*time.Second, 10*time.Millisecond)
+			assert.Empty(t, deliveries)
+			close(release)
+			require.NoError(t, <-sent)
+
+			task := receiveWebhook(t, deliveries, "s3cret")
+			assert.Equal(t, "webhook-task", task.ID)
+			assert.Equal(t, a2aSchema.TaskStateCompleted, task.Status.State)
+			require.Len(t, task.Artifacts, 1)
+			require.NotNil(t, task.Artifacts[0].Parts[0].Text)
+			assert.Equal(t, "done", *task.Artifacts[0].Parts[0].Text)
+			assert.NotEmpty(t, task.History)
+			assert.Equal(t, int32(len(tc.statuses)+1), attempts.Load())
+		})
+	}
+}
+
+func TestTaskWebhookDeliveredForFinishedTask(t *testing.T) {
+	receiver, deliveries, _ := newNotificationReceiver(t)
+	release := make(chan struct{})
+	close(release)
+	capability := newWebhookTestCapability(t, release)
+	session := userSession("webhook-session", "webhook-user")
+
+	result, err := sendWebhookTestTask(capability, session, "webhook-task")
+	sharedtesting.AssertJSONRPCSuccess[*a2aSchema.Task](t, result, err)
+	result, err = setWebhook(capability, session, a2a.TaskWebhookParams{ID: "webhook-task", URL: receiver.URL, Secret: "s3cret"})
+	sharedtesting.AssertJSONRPCSuccess[a2a.TaskWebhookParams](t, result, err)
+
+	task := receiveWebhook(t, deliveries, "s3cret")
+	assert.Equal(t, "webhook-task", task.ID)
+	assert.Equal(t, a2aSchema.TaskStateCompleted, task.Status.State)
+}
+
+func TestTaskSetWebhookRequiresOwnTask(t *testing.T) {
+	receiver, deliveries, attempts := newNotificationReceiver(t)
+	release := make(chan struct{})
+	close(release)
+	capability := newWebhookTestCapability(t, release)
+	owner := userSession("owner-session", "owner")
+
+	result, err := sendWebhookTestTask(capability, owner, "webhook-task")
+	sharedtesting.AssertJSONRPCSuccess[*a2aSchema.Task](t, result, err)
+
+	result, err = setWebhook(capability, owner, a2a.TaskWebhookParams{ID: "unknown-task", URL: receiver.URL})
+	sharedtesting.AssertJSONRPCError(t, result, err, a2aSchema.ErrorCodeTaskNotFound)
+	result, err = setWebhook(capability, userSession("other-session", "other"), a2a.TaskWebhookParams{ID: "webhook-task", URL: receiver.URL})
+	sharedtesting.AssertJSONRPCError(t, result, err, a2aSchema.ErrorCodeTaskNotFound)
+
+	// Another user cannot take over the task ID either
+	result, err = sendWebhookTestTask(capability, userSession("other-session", "other"), "webhook-task")
+	sharedtesting.AssertJSONRPCError(t, result, err, shared.JSONRPCErrorInvalidParams)
+
+	time.Sleep(100 * time.Millisecond)
+	assert.Empty(t, deliveries)
+	assert.Zero(t, attempts.Load())
+}
+
+func TestTaskSetWebhookRejectsInvalidURL(t *testing.T) {
+	release := make(chan struct{})
+	close(release)
+	capability := newWebhookTestCapability(t, release)
+
+	result, err := setWebhook(capability, sharedtesting.NewMockSession("test-session"), a2a.TaskWebhookParams{
+		ID: "webhook-task", URL: "ftp://example.com/hook",
+	})
+	sharedtesting.AssertJSONRPCError(t, result, err, shared.JSONRPCErrorInvalidParams)
+}
@@ -0,0 +1,96 @@
+package a2a
+
+import (
+	"sync"
+	"time"
+
+	a2aSchema "github.com/gate4ai/gate4ai/shared/a2a/2025-draft/schema"
+)
+
+// DefaultWorkerPoolWaitTimeout is how long a task waits for a free worker before it is rejected.
+const DefaultWorkerPoolWaitTimeout = 5 * time.Second
+
+// WithWorkerPool runs agent handlers on n worker goroutines started with the capability instead
+// of one goroutine per task. A task that finds no free worker within the wait timeout (see
+// WithWorkerPoolWaitTimeout) fails with ErrorCodeRateLimitExceeded before its state is changed.
+func WithWorkerPool(n int) A2AOption {
+	return func(ac *A2ACapability) {
+		if n > 0 {
+			ac.workerPool = newWorkerPool(n)
+		}
+	}
+}
+
+// WithWorkerPoolWaitTimeout sets how long a task waits for a free worker of the pool set by WithWorkerPool.
+func WithWorkerPoolWaitTimeout(d time.Duration) A2AOption {
+	return func(ac *A2ACapability) {
+		ac.workerPoolWaitTimeout = d
+	}
+}
+
+// workerPool runs jobs on a fixed number of goroutines.
+type workerPool struct {
+	jobs chan func() // Unbuffered, so a send succeeds only when a worker is idle
+}
+
+func newWorkerPool(n int) *workerPool {
+	p := &workerPool{jobs: make(chan func())}
+	for i := 0; i < n; i++ {
+		go p.work()
+	}
+	return p
+}
+
+func (p *workerPool) work() {
+	for job := range p.jobs {
+		job()
+	}
+}
+
+// workerSlot is a worker reserved for one task: run hands it the task's handler, release
+// frees it if the task ends before its handler starts.
+type workerSlot struct {
+	once sync.Once
+	work chan func() // nil without a pool
+}
+
+// run starts job on the reserved worker, or on a new goroutine without a pool.
+func (s *workerSlot) run(job func()) {
+	if s.work == nil {
+		go job()
+		return
+	}
+	s.once.Do(func() {
+		s.work <- job
+		close(s.work)
+	})
+}
+
+// release frees the worker if run was not called. It is safe to call after run.
+func (s *workerSlot) release() {
+	if s.work != nil {
+		s.once.Do(func() { close(s.work) })
+	}
+}
+
+// reserveWorker waits up to the pool's wait timeout for an idle worker. Without a pool it
+// always succeeds.
+func (ac *A2ACapability) reserveWorker() (*workerSlot, *a2aSchema.JSONRPCError) {
+	if ac.workerPool == nil {
+		return &workerSlot{}, nil
+	}
+	slot := &workerSlot{work: make(chan func(), 1)}
+	reserved := func() {
+		if job, ok := <-slot.work; ok {
+			job()
+		}
+	}
+	timer := time.NewTimer(ac.workerPoolWaitTimeout)
+	defer timer.Stop()
+	select {
+	case ac.workerPool.jobs <- reserved:
+		return slot, nil
+	case <-timer.C:
+		return nil, NewRateLimitError("Server busy, try again later", DefaultRateLimitRetryAfter)
+	}
+}
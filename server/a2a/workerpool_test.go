@@ -0,0 +1,79 @@
+package a2a_test
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gate4ai/gate4ai/server/a2a"
+	"github.com/gate4ai/gate4ai/server/transport"
+	a2aSchema "github.com/gate4ai/gate4ai/shared/a2a/2025-draft/schema"
+	"github.com/gate4ai/gate4ai/shared/config"
+	sharedtesting "github.com/gate4ai/gate4ai/shared/testing"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// sendConcurrently sends n tasks/send requests at once to a capability with a pool of 2 workers
+// whose handlers take hold each, and returns the errors and the peak number of running handlers.
+func sendConcurrently(t *testing.T, n int, hold, waitTimeout time.Duration) ([]error, int32) {
+	manager, err := transport.NewManager(zap.NewNop(), config.NewInternalConfig())
+	require.NoError(t, err)
+	var running, peak atomic.Int32
+	handler := func(ctx context.Context, task *a2aSchema.Task, updates chan<- a2a.A2AYieldUpdate, logger *zap.Logger) error {
+		now := running.Add(1)
+		for {
+			old := peak.Load()
+			if now <= old || peak.CompareAndSwap(old, now) {
+				break
+			}
+		}
+		time.Sleep(hold)
+		running.Add(-1)
+		updates <- a2a.A2AYieldUpdate{Status: &a2aSchema.TaskStatus{State: a2aSchema.TaskStateCompleted}}
+		return nil
+	}
+	capability := a2a.NewA2ACapability(zap.NewNop(), manager, a2a.NewInMemoryTaskStore(), handler,
+		a2a.WithWorkerPool(2), a2a.WithWorkerPoolWaitTimeout(waitTimeout))
+
+	errs := make([]error, n)
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			_, errs[i] = capability.GetHandlers()["tasks/send"](sharedtesting.BuildMessage("tasks/send", a2aSchema.TaskSendParams{
+				ID:      fmt.Sprintf("pooled-%d", i),
+				Message: a2aSchema.Message{Role: "user", Parts: textParts("go")},
+			}))
+		}(i)
+	}
+	wg.Wait()
+	return errs, peak.Load()
+}
+
+func TestWorkerPoolQueuesTasks(t *testing.T) {
+	errs, peak := sendConcurrently(t, 5, 50*time.Millisecond, 5*time.Second)
+	for _, err := range errs {
+		assert.NoError(t, err)
+	}
+	assert.Equal(t, int32(2), peak)
+}
+
+func TestWorkerPoolRejectsWhenBusy(t *testing.T) {
+	errs, peak := sendConcurrently(t, 5, 500*time.Millisecond, 50*time.Millisecond)
+	busy := 0
+	for _, err := range errs {
+		if err != nil {
+			sharedtesting.AssertJSONRPCError(t, nil, err, a2aSchema.ErrorCodeRateLimitExceeded)
+			assert.Contains(t, err.Error(), "Server busy")
+			busy++
+		}
+	}
+	assert.Equal(t, 3, busy)
+	assert.Equal(t, int32(2), peak)
+}
@@ -0,0 +1,119 @@
+package server_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/gate4ai/gate4ai/server"
+	"github.com/gate4ai/gate4ai/server/extra"
+	"github.com/gate4ai/gate4ai/shared/config"
+	"github.com/gate4ai/gate4ai/shared/mcp/2025/schema"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func freePort(t *testing.T) int {
+	listener, err := net.Listen("tcp", "localhost:0")
+	require.NoError(t, err)
+	defer listener.Close()
+	return listener.Addr().(*net.TCPAddr).Port
+}
+
+func waitForPort(t *testing.T, port int) {
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		conn, err := net.DialTimeout("tcp", fmt.Sprintf("localhost:%d", port), 100*time.Millisecond)
+		if err == nil {
+			conn.Close()
+			return
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+	t.Fatalf("Listener on port %d did not start in time", port)
+}
+
+func adminRequest(t *testing.T, method, url, token string, body interface{}) *http.Response {
+	var reader *bytes.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		require.NoError(t, err)
+		reader = bytes.NewReader(data)
+	} else {
+		reader = bytes.NewReader(nil)
+	}
+	req, err := http.NewRequest(method, url, reader)
+	require.NoError(t, err)
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	t.Cleanup(func() { resp.Body.Close() })
+	return resp
+}
+
+func TestAdminServer(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	mainPort, adminPort := freePort(t), freePort(t)
+	_, err := server.Start(ctx, zap.NewNop(), config.NewInternalConfig(),
+		server.WithListenAddr(fmt.Sprintf(":%d", mainPort)),
+		server.WithAdminServer(fmt.Sprintf(":%d", adminPort), "admin-secret"),
+	)
+	require.NoError(t, err)
+	waitForPort(t, mainPort)
+	waitForPort(t, adminPort)
+	adminURL := fmt.Sprintf("http://localhost:%d", adminPort)
+	mainURL := fmt.Sprintf("http://localhost:%d", mainPort)
+
+	tool := extra.RegisterToolRequest{
+		Name:    "runtimeTool",
+		Content: schema.NewTextContent("registered at runtime"),
+	}
+
+	t.Run("requires token", func(t *testing.T) {
+		assert.Equal(t, http.StatusUnauthorized, adminRequest(t, http.MethodGet, adminURL+"/admin/stats", "", nil).StatusCode)
+		assert.Equal(t, http.StatusUnauthorized, adminRequest(t, http.MethodGet, adminURL+"/admin/stats", "wrong", nil).StatusCode)
+		assert.Equal(t, http.StatusUnauthorized, adminRequest(t, http.MethodPost, adminURL+"/admin/tools/register", "wrong", tool).StatusCode)
+	})
+
+	t.Run("register, stats and delete", func(t *testing.T) {
+		resp := adminRequest(t, http.MethodPost, adminURL+"/admin/tools/register", "admin-secret", tool)
+		require.Equal(t, http.StatusCreated, resp.StatusCode)
+		assert.Equal(t, http.StatusConflict, adminRequest(t, http.MethodPost, adminURL+"/admin/tools/register", "admin-secret", tool).StatusCode)
+
+		resp = adminRequest(t, http.MethodGet, adminURL+"/admin/stats", "admin-secret", nil)
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		var stats extra.AdminStatsResponse
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&stats))
+		assert.Equal(t, []string{"runtimeTool"}, stats.Tools)
+		assert.NotEmpty(t, stats.Uptime)
+
+		assert.Equal(t, http.StatusNoContent, adminRequest(t, http.MethodDelete, adminURL+"/admin/tools/runtimeTool", "admin-secret", nil).StatusCode)
+		assert.Equal(t, http.StatusNotFound, adminRequest(t, http.MethodDelete, adminURL+"/admin/tools/runtimeTool", "admin-secret", nil).StatusCode)
+	})
+
+	t.Run("main port does not expose admin routes", func(t *testing.T) {
+		assert.Equal(t, http.StatusNotFound, adminRequest(t, http.MethodGet, mainURL+"/admin/stats", "admin-secret", nil).StatusCode)
+		assert.Equal(t, http.StatusNotFound, adminRequest(t, http.MethodPost, mainURL+"/admin/tools/register", "admin-secret", tool).StatusCode)
+		// The main port keeps serving its own routes
+		assert.Equal(t, http.StatusOK, adminRequest(t, http.MethodGet, mainURL+"/health", "", nil).StatusCode)
+	})
+}
+
+func TestWithAdminServerValidation(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	_, err := server.Start(ctx, zap.NewNop(), config.NewInternalConfig(), server.WithAdminServer(":0", ""))
+	assert.Error(t, err)
+	_, err = server.Start(ctx, zap.NewNop(), config.NewInternalConfig(), server.WithAdminServer("", "token"))
+	assert.Error(t, err)
+}
@@ -0,0 +1,21 @@
+//go:build !production
+
+package server
+
+import (
+	"errors"
+
+	"github.com/gate4ai/gate4ai/server/transport"
+	"go.uber.org/zap/zapcore"
+)
+
+// WithBodyLogging logs request and response bodies at level, truncated to maxBodySize bytes
+// (see transport.WithBodyLogging). Not available in builds with the "production" tag.
+func WithBodyLogging(level zapcore.Level, maxBodySize int) ServerOption {
+	return func(b *ServerBuilder) error {
+		if b.transport == nil {
+			return errors.New("transport not initialized in builder, cannot enable body logging")
+		}
+		return transport.WithBodyLogging(level, maxBodySize)(b.transport)
+	}
+}
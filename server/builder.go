@@ -7,9 +7,11 @@ import (
 
 	"github.com/gate4ai/gate4ai/server/a2a"
 	"github.com/gate4ai/gate4ai/server/mcp/capability"
+	"github.com/gate4ai/gate4ai/server/metrics"
 	"github.com/gate4ai/gate4ai/server/transport"
 	"github.com/gate4ai/gate4ai/shared"
 	"github.com/gate4ai/gate4ai/shared/config"
+	"go.opentelemetry.io/otel/trace"
 	"go.uber.org/zap"
 )
 
@@ -22,6 +24,7 @@ type ServerBuilder struct {
 	transport    *transport.Transport
 	mux          *http.ServeMux
 	capabilities []shared.ICapability // Store generic capabilities
+	eventBus     shared.EventBus      // Given to the server capabilities by Start
 
 	// Capability instances (created lazily)
 	baseCap       *capability.BaseCapability
@@ -29,11 +32,26 @@ type ServerBuilder struct {
 	resourcesCap  *capability.ResourcesCapability
 	promptsCap    *capability.PromptsCapability
 	completionCap *capability.CompletionCapability
+	rootsCap      *capability.RootsCapability
+	loggingCap    *capability.LoggingCapability
 	a2aCap        *a2a.A2ACapability
 
 	// Flags to control route registration
-	registerMCPRoutes bool
-	registerA2ARoutes bool
+	registerMCPRoutes       bool
+	registerA2ARoutes       bool
+	registerWebSocketRoutes bool // Set by WithWebSocketTransport
+	stdio                   bool // Set by WithStdioTransport
+
+	// Admin server settings (see WithAdminServer)
+	adminListenAddr string
+	adminToken      string
+
+	// Metrics endpoint settings (see WithMetricsEndpoint)
+	metricsPath string
+	metrics     *metrics.Metrics
+
+	// Tracer provider set by WithOTELTracerProvider; nil uses the global provider
+	tracerProvider trace.TracerProvider
 	// a2aAgentHandler  a2a.A2AHandler        // Store the A2A agent logic handler
 }
 
@@ -100,6 +118,32 @@ func (b *ServerBuilder) EnsureCompletionCapability() (*capability.CompletionCapa
 	return b.completionCap, nil
 }
 
+// EnsureRootsCapability creates the RootsCapability if it doesn't exist.
+func (b *ServerBuilder) EnsureRootsCapability() (*capability.RootsCapability, error) {
+	if err := b.EnsureMCPBaseCapability(); err != nil {
+		return nil, err
+	}
+	if b.rootsCap == nil {
+		b.logger.Debug("Initializing RootsCapability")
+		b.rootsCap = capability.NewRootsCapability(b.manager, b.logger)
+		b.capabilities = append(b.capabilities, b.rootsCap)
+	}
+	return b.rootsCap, nil
+}
+
+// EnsureLoggingCapability creates the LoggingCapability if it doesn't exist.
+func (b *ServerBuilder) EnsureLoggingCapability() (*capability.LoggingCapability, error) {
+	if err := b.EnsureMCPBaseCapability(); err != nil {
+		return nil, err
+	}
+	if b.loggingCap == nil {
+		b.logger.Debug("Initializing LoggingCapability")
+		b.loggingCap = capability.NewLoggingCapability(b.logger)
+		b.capabilities = append(b.capabilities, b.loggingCap)
+	}
+	return b.loggingCap, nil
+}
+
 // EnsureA2ACapability creates the A2ACapability if it doesn't exist.
 // Requires a TaskStore and A2AHandler to be provided via options (e.g., WithA2ACapability).
 func (b *ServerBuilder) EnsureA2ACapability(store a2a.TaskStore, handler a2a.A2AHandler) (*a2a.A2ACapability, error) {
@@ -109,7 +153,7 @@ func (b *ServerBuilder) EnsureA2ACapability(store a2a.TaskStore, handler a2a.A2A
 	if b.a2aCap == nil {
 		b.logger.Debug("Initializing A2ACapability")
 		// Manager is now passed during construction
-		b.a2aCap = a2a.NewA2ACapability(b.logger, b.manager, store, handler)
+		b.a2aCap = a2a.NewA2ACapability(b.logger, b.manager, store, handler, a2a.WithFeatureFlags(b.cfg))
 		b.capabilities = append(b.capabilities, b.a2aCap)
 		b.registerA2ARoutes = true // A2A capability implies A2A routes are needed
 	} else {
@@ -118,5 +162,13 @@ func (b *ServerBuilder) EnsureA2ACapability(store a2a.TaskStore, handler a2a.A2A
 	return b.a2aCap, nil
 }
 
+// close stops the background routines of the transport and the capabilities.
+func (b *ServerBuilder) close() {
+	b.transport.Close()
+	if b.a2aCap != nil {
+		b.a2aCap.Close()
+	}
+}
+
 // ServerOption defines a function type for configuring the ServerBuilder.
 type ServerOption func(*ServerBuilder) error
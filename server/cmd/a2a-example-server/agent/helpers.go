@@ -48,11 +48,7 @@ func sendArtifactUpdate(ctx context.Context, updates chan<- a2a.A2AYieldUpdate,
 
 // sendJsonRpcErrorUpdate creates and sends a JSONRPCError update.
 func sendJsonRpcErrorUpdate(ctx context.Context, updates chan<- a2a.A2AYieldUpdate, code int, message string) error {
-	jsonErr := &a2aSchema.JSONRPCError{
-		Code:    code,
-		Message: message,
-	}
-	return sendUpdate(ctx, updates, a2a.A2AYieldUpdate{Error: jsonErr})
+	return sendUpdate(ctx, updates, a2a.A2AYieldError(code, message))
 }
 
 // createTextArtifact creates a simple text artifact.
@@ -30,6 +30,7 @@ func main() {
 
 	listenAddr := flag.String("listen", ":4000", "Address and port to listen on (e.g., :4000 or 0.0.0.0:4000)")
 	configPath := flag.String("config", "", "Path to optional YAML config file")
+	debugMessages := flag.String("debug-messages", "", "Path of an NDJSON file receiving every raw A2A message (development only)")
 	flag.Parse()
 
 	// --- Configuration ---
@@ -96,6 +97,10 @@ func main() {
 		// server.WithListenAddr(*listenAddr), // Listen address is now handled by config
 		server.WithA2ACapability(taskStore, agentHandler), // Add the A2A capability with our store and specific agent handler
 	}
+	if *debugMessages != "" {
+		logger.Info("Logging raw A2A messages", zap.String("path", *debugMessages))
+		serverOptions = append(serverOptions, server.WithA2ADebugMessageLog(*debugMessages))
+	}
 
 	// Start the server
 	errChan, startErr := server.Start(ctx, logger, cfg, serverOptions...)
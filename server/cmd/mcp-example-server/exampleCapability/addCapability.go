@@ -4,6 +4,7 @@ import (
 	"encoding/base64"
 	"encoding/json"
 	"fmt"
+	"math"
 	"net/http"
 	"strconv" // Import sync package
 	"time"
@@ -18,19 +19,19 @@ import (
 	schema "github.com/gate4ai/gate4ai/shared/mcp/2025/schema"
 )
 
+// EchoArgs are the arguments of the echo tool; its input schema is generated from them.
+type EchoArgs struct {
+	Message string `json:"message" schema:"required" description:"The message to echo back"`
+}
+
 // Define Tool handlers directly in this package
 var EchoTool = schema.Tool{
 	Name:        "echo",
 	Description: "echo a message",
-	InputSchema: &schema.JSONSchemaProperty{
-		Type: "object",
-		Properties: map[string]schema.JSONSchemaProperty{
-			"message": {
-				Type:        "string",
-				Description: "The message to echo back",
-			},
-		},
-		Required: []string{"message"},
+	InputSchema: schema.FromStruct[EchoArgs](),
+	Annotations: &schema.ToolAnnotations{
+		Title:        shared.PointerTo("Echo Message"),
+		ReadOnlyHint: shared.PointerTo(true),
 	},
 }
 
@@ -46,22 +47,19 @@ func EchoToolHandler(_ *shared.Message, arguments schema.Arguments) (*schema.Met
 	}}, nil
 }
 
+// AddArgs are the arguments of the add tool; its input schema is generated from them.
+type AddArgs struct {
+	A float64 `json:"a" schema:"required" description:"First number to add"`
+	B float64 `json:"b" schema:"required" description:"Second number to add"`
+}
+
 var AddTool = schema.Tool{
 	Name:        "add",
 	Description: "add two numbers",
-	InputSchema: &schema.JSONSchemaProperty{ // Use 2025 schema here too
-		Type: "object",
-		Properties: map[string]schema.JSONSchemaProperty{
-			"a": {
-				Type:        "number",
-				Description: "First number to add",
-			},
-			"b": {
-				Type:        "number",
-				Description: "Second number to add",
-			},
-		},
-		Required: []string{"a", "b"},
+	InputSchema: schema.FromStruct[AddArgs](),
+	Annotations: &schema.ToolAnnotations{
+		Title:        shared.PointerTo("Add Numbers"),
+		ReadOnlyHint: shared.PointerTo(true),
 	},
 }
 
@@ -92,14 +90,33 @@ var LongRunningTool = schema.Tool{
 	},
 }
 
-func LongRunningHandler(_ *shared.Message, arguments schema.Arguments) (*schema.Meta, []schema.Content, error) {
+// LongRunningHandler runs for duration seconds and reports progress after each of steps equal
+// intervals (by default, every second). It stops early when the call times out.
+func LongRunningHandler(msg *shared.Message, arguments schema.Arguments, progress capability.ProgressReporter) (*schema.Meta, []schema.Content, error) {
 	durationFloat, ok := arguments["duration"].(float64)
 	if !ok {
 		return nil, nil, fmt.Errorf("invalid 'duration' argument type: expected number")
 	}
-	// steps, _ := arguments["steps"].(float64) // Ignoring steps for now
+	steps := int(math.Ceil(durationFloat))
+	if stepsFloat, ok := arguments["steps"].(float64); ok && stepsFloat >= 1 {
+		steps = int(stepsFloat)
+	}
+	if steps < 1 {
+		steps = 1
+	}
 
-	time.Sleep(time.Duration(durationFloat) * time.Second)
+	ctx := msg.Context()
+	interval := time.Duration(durationFloat * float64(time.Second) / float64(steps))
+	for step := 1; step <= steps; step++ {
+		select {
+		case <-time.After(interval):
+		case <-ctx.Done():
+			return nil, nil, ctx.Err()
+		}
+		if err := progress.Report(ctx, float64(step), float64(steps), fmt.Sprintf("Completed step %d of %d", step, steps)); err != nil {
+			return nil, nil, fmt.Errorf("failed to report progress: %w", err)
+		}
+	}
 
 	result := "completed"
 	return nil, []schema.Content{{
@@ -108,6 +125,46 @@ func LongRunningHandler(_ *shared.Message, arguments schema.Arguments) (*schema.
 	}}, nil
 }
 
+var CountdownTool = schema.Tool{
+	Name:        "countdown",
+	Description: "counts down from the given number of seconds, reporting progress every second",
+	InputSchema: &schema.JSONSchemaProperty{
+		Type: "object",
+		Properties: map[string]schema.JSONSchemaProperty{
+			"seconds": {Type: "number"},
+		},
+		Required: []string{"seconds"},
+	},
+}
+
+// CountdownHandler is a plain ToolHandler that reports progress with the token of the tool call.
+func CountdownHandler(msg *shared.Message, arguments schema.Arguments) (*schema.Meta, []schema.Content, error) {
+	secondsFloat, ok := arguments["seconds"].(float64)
+	if !ok || secondsFloat < 0 {
+		return nil, nil, fmt.Errorf("invalid 'seconds' argument: expected a non-negative number")
+	}
+	seconds := int(secondsFloat)
+
+	ctx := msg.Context()
+	progress := capability.GetProgressReporter(msg, capability.ProgressToken(msg))
+	for elapsed := 1; elapsed <= seconds; elapsed++ {
+		select {
+		case <-time.After(time.Second):
+		case <-ctx.Done():
+			return nil, nil, ctx.Err()
+		}
+		if err := progress.Report(ctx, float64(elapsed), float64(seconds), fmt.Sprintf("%d seconds left", seconds-elapsed)); err != nil {
+			return nil, nil, fmt.Errorf("failed to report progress: %w", err)
+		}
+	}
+
+	result := "liftoff"
+	return nil, []schema.Content{{
+		Type: "text",
+		Text: &result,
+	}}, nil
+}
+
 var SampleLLMTool = schema.Tool{
 	Name:        "sampleLLM",
 	Description: "The prompt to send to the LLM",
@@ -377,6 +434,21 @@ func ResourceHandlerEven(i int) capability.ResourceHandler {
 	}
 }
 
+// ResourceTemplateHandler reads test://static/resource/{id} for ids without a static resource.
+func ResourceTemplateHandler(msg *shared.Message, vars map[string]string) (schema.Meta, []schema.ResourceContent, error) {
+	id := vars["id"]
+	if id == "" {
+		return nil, nil, fmt.Errorf("missing resource id")
+	}
+	uri := "test://static/resource/" + id
+	text := fmt.Sprintf("Resource %s: This is a templated plaintext resource", id)
+	return nil, []schema.ResourceContent{{
+		URI:      uri,
+		MimeType: "text/plain",
+		Text:     &text,
+	}}, nil
+}
+
 // --- Completion Handler ---
 func CompletionHandler(msg *shared.Message, arg schema.CompleteArgument) (*schema.CompletionInfo, error) {
 	// Simple example: suggest based on prefix
@@ -411,7 +483,8 @@ func BuildOptions(logger *zap.Logger) []server.ServerOption {
 		// Add tools
 		server.WithMCPTool(EchoTool.Name, EchoTool.Description, EchoTool.InputSchema, EchoTool.Annotations, EchoToolHandler),
 		server.WithMCPTool(AddTool.Name, AddTool.Description, AddTool.InputSchema, AddTool.Annotations, AddToolHandler),
-		server.WithMCPTool(LongRunningTool.Name, LongRunningTool.Description, LongRunningTool.InputSchema, LongRunningTool.Annotations, LongRunningHandler),
+		server.WithMCPProgressTool(LongRunningTool.Name, LongRunningTool.Description, LongRunningTool.InputSchema, LongRunningTool.Annotations, LongRunningHandler),
+		server.WithMCPTool(CountdownTool.Name, CountdownTool.Description, CountdownTool.InputSchema, CountdownTool.Annotations, CountdownHandler),
 		server.WithMCPTool(SampleLLMTool.Name, SampleLLMTool.Description, SampleLLMTool.InputSchema, SampleLLMTool.Annotations, SampleLLMHandler),
 		server.WithMCPTool(TinyImageTool.Name, TinyImageTool.Description, TinyImageTool.InputSchema, TinyImageTool.Annotations, TinyImageHandler),
 		server.WithMCPTool(PrintEnvTool.Name, PrintEnvTool.Description, PrintEnvTool.InputSchema, PrintEnvTool.Annotations, PrintEnvHandler),
@@ -422,10 +495,10 @@ func BuildOptions(logger *zap.Logger) []server.ServerOption {
 		server.WithMCPPromptTemplate(ComplexPromptTemplate.Name, ComplexPromptTemplate.Description, ComplexPromptTemplate.Arguments, ComplexPromptHandler),
 
 		// Add resource template
-		server.WithMCPResourceTemplate("test://static/resource/{id}", "Static Resource Template", "Template for static resources", "text/plain", nil), // Handler can be nil for template definition
+		server.WithMCPResourceTemplate("test://static/resource/{id}", "Static Resource Template", "Template for static resources", "text/plain", ResourceTemplateHandler),
 
 		// Add completion capability
-		//server.WithMCPCompletionCapability(CompletionHandler),
+		server.WithMCPCompletionCapability(CompletionHandler),
 
 		// Add subscription handler
 		server.WithMCPSubscriptionHandler(SubscriptionLogger(logger)),
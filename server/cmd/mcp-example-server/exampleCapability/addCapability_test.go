@@ -0,0 +1,66 @@
+package exampleCapability
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/gate4ai/gate4ai/server/mcp/capability"
+	"github.com/gate4ai/gate4ai/shared/mcp/2025/schema"
+	sharedtesting "github.com/gate4ai/gate4ai/shared/testing"
+)
+
+func TestLongRunningOperationReportsProgress(t *testing.T) {
+	msg := sharedtesting.BuildMessage("tools/call", json.RawMessage(`{"name": "longRunningOperation", "_meta": {"progressToken": "op-1"}}`))
+	handler := capability.ProgressToolHandler(LongRunningHandler)
+
+	start := time.Now()
+	_, content, err := handler(msg, schema.Arguments{"duration": 0.2, "steps": 4.0})
+	if err != nil {
+		t.Fatalf("longRunningOperation failed: %v", err)
+	}
+	if len(content) != 1 || *content[0].Text != "completed" {
+		t.Fatalf("Expected completed, got %+v", content)
+	}
+	if elapsed := time.Since(start); elapsed < 200*time.Millisecond {
+		t.Errorf("Expected the operation to run for its duration, took %s", elapsed)
+	}
+
+	sent := msg.Session.(*sharedtesting.MockSession).Sent()
+	if len(sent) != 4 {
+		t.Fatalf("Expected 4 progress notifications, got %d", len(sent))
+	}
+	for i, notification := range sent {
+		var params struct {
+			ProgressToken string `json:"progressToken"`
+			Progress      int    `json:"progress"`
+			Total         int    `json:"total"`
+		}
+		if *notification.Method != capability.ProgressNotificationMethod {
+			t.Fatalf("Expected %s, got %s", capability.ProgressNotificationMethod, *notification.Method)
+		}
+		if err := json.Unmarshal(*notification.Params, &params); err != nil {
+			t.Fatalf("Failed to decode progress params: %v", err)
+		}
+		if params.ProgressToken != "op-1" || params.Progress != i+1 || params.Total != 4 {
+			t.Errorf("Unexpected progress notification %d: %+v", i, params)
+		}
+	}
+}
+
+func TestCountdownStopsWithTheRequest(t *testing.T) {
+	msg := sharedtesting.BuildMessage("tools/call", json.RawMessage(`{"name": "countdown"}`))
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	msg.SetContext(ctx)
+
+	start := time.Now()
+	if _, _, err := CountdownHandler(msg, schema.Arguments{"seconds": 10.0}); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Expected the countdown to stop with its context, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("Expected the countdown to stop at once, took %s", elapsed)
+	}
+}
@@ -20,7 +20,7 @@ func main() {
 	logerConfig.EncoderConfig.EncodeTime = zapcore.ISO8601TimeEncoder
 	logger, err := logerConfig.Build()
 	if err != nil {
-		fmt.Printf("Failed to initialize logger: %v\n", err)
+		fmt.Fprintf(os.Stderr, "Failed to initialize logger: %v\n", err)
 		os.Exit(1)
 	}
 	defer logger.Sync()
@@ -28,6 +28,7 @@ func main() {
 	// Parse command-line arguments
 	port := flag.Int("port", 0, "Port to run the server on")
 	configPath := flag.String("config", "config.yaml", "Path to configuration file")
+	stdio := flag.Bool("stdio", false, "Serve MCP over stdin/stdout instead of HTTP, for MCP hosts launching the server")
 	flag.Parse()
 
 	cfg, err := config.NewYamlConfig(*configPath, logger)
@@ -57,6 +58,9 @@ func main() {
 	if overwriteListenAddr != "" {
 		serverOptions = append(serverOptions, server.WithListenAddr(overwriteListenAddr))
 	}
+	if *stdio {
+		serverOptions = append(serverOptions, server.WithStdioTransport())
+	}
 
 	errChan, err := server.Start(ctx, logger, cfg, serverOptions...)
 	if err != nil {
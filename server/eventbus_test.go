@@ -0,0 +1,97 @@
+package server_test
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gate4ai/gate4ai/server"
+	"github.com/gate4ai/gate4ai/server/mcp/capability"
+	"github.com/gate4ai/gate4ai/server/transport"
+	"github.com/gate4ai/gate4ai/shared"
+	"github.com/gate4ai/gate4ai/shared/config"
+	"github.com/gate4ai/gate4ai/shared/mcp/2025/schema"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// toolCallWatcher is a capability without methods of its own that reports the calls of a tool.
+type toolCallWatcher struct {
+	tool  string
+	calls chan capability.ToolCalledEvent
+}
+
+func (w *toolCallWatcher) GetHandlers() map[string]func(*shared.Message) (interface{}, error) {
+	return map[string]func(*shared.Message) (interface{}, error){}
+}
+
+func (w *toolCallWatcher) SetCapabilities(s *schema.ServerCapabilities) {}
+
+func (w *toolCallWatcher) SetEventBus(bus shared.EventBus) {
+	bus.Subscribe(capability.ToolCalledTopic(w.tool), func(payload interface{}) {
+		w.calls <- payload.(capability.ToolCalledEvent)
+	})
+}
+
+func TestCapabilityReceivesToolCallEvents(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	echo := func(msg *shared.Message, arguments schema.Arguments) (*schema.Meta, []schema.Content, error) {
+		return nil, schema.NewTextContent(fmt.Sprint(arguments["text"])), nil
+	}
+	watcher := &toolCallWatcher{tool: "echo", calls: make(chan capability.ToolCalledEvent, 1)}
+	port := freePort(t)
+	cfg := config.NewInternalConfig()
+	cfg.UserKeyHashes[config.HashAPIKey("events-key")] = "events-user"
+	_, err := server.Start(ctx, zap.NewNop(), cfg,
+		server.WithListenAddr(fmt.Sprintf(":%d", port)),
+		server.WithMCPTool("echo", "Echoes its text", nil, nil, echo),
+		server.WithCapability(watcher),
+	)
+	require.NoError(t, err)
+	waitForPort(t, port)
+	baseURL := fmt.Sprintf("http://localhost:%d", port)
+
+	streamCtx, streamCancel := context.WithTimeout(ctx, 10*time.Second)
+	defer streamCancel()
+	req, err := http.NewRequestWithContext(streamCtx, http.MethodGet, baseURL+transport.MCP2024_PATH+"?key=events-key", nil)
+	require.NoError(t, err)
+	req.Header.Set("Accept", "text/event-stream")
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	reader := bufio.NewReader(resp.Body)
+
+	_, endpoint := readSSEData(t, reader)
+	if !strings.HasPrefix(endpoint, "http") {
+		endpoint = baseURL + endpoint
+	}
+	post := func(body string) {
+		resp, err := http.Post(endpoint, "application/json", strings.NewReader(body))
+		require.NoError(t, err)
+		resp.Body.Close()
+		require.Less(t, resp.StatusCode, 300, "POST %s", body)
+	}
+	post(`{"jsonrpc": "2.0", "id": 1, "method": "initialize", "params": {"protocolVersion": "2024-11-05", "capabilities": {}, "clientInfo": {"name": "events-test", "version": "1.0"}}}`)
+	readSSEData(t, reader)
+	post(`{"jsonrpc": "2.0", "method": "notifications/initialized"}`)
+
+	post(`{"jsonrpc": "2.0", "id": 2, "method": "tools/call", "params": {"name": "echo", "arguments": {"text": "hello"}}}`)
+	select {
+	case event := <-watcher.calls:
+		assert.Equal(t, "echo", event.Name)
+		assert.Equal(t, "hello", event.Arguments["text"])
+		assert.NotEmpty(t, event.SessionID)
+		require.Len(t, event.Result.Content, 1)
+		assert.Equal(t, "hello", *event.Result.Content[0].Text)
+	case <-time.After(5 * time.Second):
+		t.Fatal("The watching capability did not receive the tool call event")
+	}
+}
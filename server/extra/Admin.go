@@ -0,0 +1,109 @@
+package extra
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gate4ai/gate4ai/server/mcp/capability"
+	"github.com/gate4ai/gate4ai/server/transport"
+	"github.com/gate4ai/gate4ai/shared"
+	"github.com/gate4ai/gate4ai/shared/mcp/2025/schema"
+	"go.uber.org/zap"
+)
+
+// RegisterToolRequest is the body of POST /admin/tools/register.
+// Tools registered at runtime have no Go handler and answer every call with Content.
+type RegisterToolRequest struct {
+	Name        string                     `json:"name"`
+	Description string                     `json:"description,omitempty"`
+	InputSchema *schema.JSONSchemaProperty `json:"inputSchema,omitempty"`
+	Annotations *schema.ToolAnnotations    `json:"annotations,omitempty"`
+	Content     []schema.Content           `json:"content"`
+}
+
+// AdminStatsResponse is the body of GET /admin/stats.
+type AdminStatsResponse struct {
+	Sessions int      `json:"sessions"`
+	Tools    []string `json:"tools"`
+	Uptime   string   `json:"uptime"`
+}
+
+// AdminHandler creates the handler for the admin-only endpoints. Every request must carry
+// "Authorization: Bearer <adminToken>".
+func AdminHandler(tools *capability.ToolsCapability, manager *transport.Manager, adminToken string, logger *zap.Logger, startTime time.Time) http.Handler {
+	handlerLogger := logger.With(zap.String("handler", "AdminHandler"))
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("POST /admin/tools/register", func(w http.ResponseWriter, r *http.Request) {
+		var req RegisterToolRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			writeAdminError(w, http.StatusBadRequest, "invalid request body: "+err.Error())
+			return
+		}
+		if req.Name == "" {
+			writeAdminError(w, http.StatusBadRequest, "tool name is required")
+			return
+		}
+		content := req.Content
+		handler := func(msg *shared.Message, arguments schema.Arguments) (*schema.Meta, []schema.Content, error) {
+			return nil, content, nil
+		}
+		if err := tools.AddTool(req.Name, req.Description, req.InputSchema, req.Annotations, handler); err != nil {
+			writeAdminError(w, http.StatusConflict, err.Error())
+			return
+		}
+		handlerLogger.Info("Registered tool via admin endpoint", zap.String("tool", req.Name))
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(map[string]string{"name": req.Name})
+	})
+
+	mux.HandleFunc("DELETE /admin/tools/{name}", func(w http.ResponseWriter, r *http.Request) {
+		name := r.PathValue("name")
+		if err := tools.DeleteTool(name); err != nil {
+			writeAdminError(w, http.StatusNotFound, err.Error())
+			return
+		}
+		handlerLogger.Info("Deleted tool via admin endpoint", zap.String("tool", name))
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	mux.HandleFunc("GET /admin/stats", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(AdminStatsResponse{
+			Sessions: len(manager.GetSessions()),
+			Tools:    tools.ToolNames(),
+			Uptime:   time.Since(startTime).Round(time.Second).String(),
+		})
+	})
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := checkAdminToken(r, adminToken); err != nil {
+			handlerLogger.Warn("Rejected admin request", zap.String("path", r.URL.Path), zap.Error(err))
+			writeAdminError(w, http.StatusUnauthorized, err.Error())
+			return
+		}
+		mux.ServeHTTP(w, r)
+	})
+}
+
+func checkAdminToken(r *http.Request, adminToken string) error {
+	token, found := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if !found {
+		return errors.New("missing bearer token")
+	}
+	if subtle.ConstantTimeCompare([]byte(token), []byte(adminToken)) != 1 {
+		return errors.New("invalid admin token")
+	}
+	return nil
+}
+
+func writeAdminError(w http.ResponseWriter, status int, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(map[string]string{"error": message})
+}
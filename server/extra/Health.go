@@ -0,0 +1,53 @@
+package extra
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/gate4ai/gate4ai/shared/config"
+	"go.uber.org/zap"
+)
+
+// HealthCheck reports a problem that prevents the service from serving requests.
+type HealthCheck func(ctx context.Context) error
+
+// HealthResponse represents the response structure for the health and readiness endpoints
+type HealthResponse struct {
+	Status  string `json:"status"`
+	Version string `json:"version,omitempty"`
+	Uptime  string `json:"uptime,omitempty"`
+	Reason  string `json:"reason,omitempty"`
+}
+
+// HealthHandler creates an HTTP handler for liveness and readiness probes.
+// It answers 200 when the config is readable and all checks pass, and 503 otherwise.
+func HealthHandler(cfg config.IConfig, logger *zap.Logger, startTime time.Time, checks ...HealthCheck) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		handlerLogger := logger.With(zap.String("handler", "HealthHandler"), zap.String("path", r.URL.Path))
+		w.Header().Set("Content-Type", "application/json")
+
+		if r.Method != http.MethodGet {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+
+		response := HealthResponse{Status: "ok"}
+		err := cfg.Status(r.Context())
+		for i := 0; err == nil && i < len(checks); i++ {
+			err = checks[i](r.Context())
+		}
+		if err != nil {
+			handlerLogger.Warn("Health check failed", zap.Error(err))
+			response = HealthResponse{Status: "degraded", Reason: err.Error()}
+			w.WriteHeader(http.StatusServiceUnavailable)
+		} else {
+			response.Version, _ = cfg.ServerVersion()
+			response.Uptime = time.Since(startTime).Round(time.Second).String()
+			w.WriteHeader(http.StatusOK)
+		}
+
+		json.NewEncoder(w).Encode(response)
+	}
+}
@@ -0,0 +1,57 @@
+package extra
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gate4ai/gate4ai/shared/config"
+	"go.uber.org/zap"
+)
+
+func TestHealthHandler(t *testing.T) {
+	healthyCfg := config.NewInternalConfig()
+	healthyCfg.ServerVersionValue = "1.2.3"
+	failingCfg := config.NewMockConfig()
+	failingCfg.SetError("Status", errors.New("config unavailable"))
+
+	cases := []struct {
+		name       string
+		cfg        config.IConfig
+		checks     []HealthCheck
+		wantCode   int
+		wantStatus string
+	}{
+		{"healthy", healthyCfg, nil, http.StatusOK, "ok"},
+		{"config failure", failingCfg, nil, http.StatusServiceUnavailable, "degraded"},
+		{"check failure", healthyCfg, []HealthCheck{func(ctx context.Context) error { return errors.New("no backend session established") }}, http.StatusServiceUnavailable, "degraded"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			handler := HealthHandler(tc.cfg, zap.NewNop(), time.Now().Add(-time.Minute), tc.checks...)
+			rec := httptest.NewRecorder()
+			handler(rec, httptest.NewRequest(http.MethodGet, "/health", nil))
+
+			if rec.Code != tc.wantCode {
+				t.Fatalf("Expected HTTP %d, got %d", tc.wantCode, rec.Code)
+			}
+			var response HealthResponse
+			if err := json.NewDecoder(rec.Body).Decode(&response); err != nil {
+				t.Fatalf("Failed to decode response: %v", err)
+			}
+			if response.Status != tc.wantStatus {
+				t.Errorf("Expected status %q, got %q", tc.wantStatus, response.Status)
+			}
+			if tc.wantCode == http.StatusOK && (response.Version != "1.2.3" || response.Uptime == "") {
+				t.Errorf("Expected version and uptime in healthy response, got %+v", response)
+			}
+			if tc.wantCode != http.StatusOK && response.Reason == "" {
+				t.Errorf("Expected a reason in degraded response")
+			}
+		})
+	}
+}
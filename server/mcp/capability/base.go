@@ -63,6 +63,9 @@ func (bc *BaseCapability) SetCapabilities(s *schema.ServerCapabilities) {
 	bc.logger.Debug("SetCapabilities called on BaseCapability")
 }
 
+// SetEventBus is part of the IServerCapability interface; BaseCapability publishes no events.
+func (bc *BaseCapability) SetEventBus(bus shared.EventBus) {}
+
 func (bc *BaseCapability) handleNotificationPing(msg *shared.Message) (interface{}, error) {
 	// No response needed for notifications
 	return nil, nil
@@ -152,6 +155,14 @@ func (bc *BaseCapability) handleInitialize(msg *shared.Message) (interface{}, er
 	logger.Debug("Sending initialize response", zap.String("negotiatedVersion", negotiatedVersion))
 	// Set session status to Connecting *after* successfully preparing response
 	session.SetStatus(shared.StatusConnecting)
+
+	// Structured event for operators monitoring which clients use which capabilities
+	bc.logger.Info("Capabilities negotiated",
+		zap.String("session_id", sessionID),
+		zap.String("protocol_version", negotiatedVersion),
+		zap.Any("negotiated_client_capabilities", clientCaps),
+		zap.Any("negotiated_server_capabilities", response.Capabilities),
+	)
 	return response, nil
 }
 
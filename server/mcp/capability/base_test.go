@@ -0,0 +1,61 @@
+package capability
+
+import (
+	"testing"
+
+	"github.com/gate4ai/gate4ai/server/transport"
+	"github.com/gate4ai/gate4ai/shared"
+	"github.com/gate4ai/gate4ai/shared/config"
+	"github.com/gate4ai/gate4ai/shared/mcp/2025/schema"
+	sharedtesting "github.com/gate4ai/gate4ai/shared/testing"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestInitializeLogsNegotiatedCapabilities(t *testing.T) {
+	core, logs := observer.New(zapcore.InfoLevel)
+	logger := zap.New(core)
+	manager, err := transport.NewManager(zap.NewNop(), config.NewInternalConfig())
+	if err != nil {
+		t.Fatalf("Failed to create manager: %v", err)
+	}
+	bc := NewBase(logger, manager)
+	tc := NewToolsCapability(manager, zap.NewNop())
+	noop := func(msg *shared.Message, arguments schema.Arguments) (*schema.Meta, []schema.Content, error) {
+		return nil, nil, nil
+	}
+	if err := tc.AddTool("noop", "does nothing", nil, nil, noop); err != nil {
+		t.Fatalf("Failed to add tool: %v", err)
+	}
+	manager.AddCapability(bc, tc)
+
+	msg := sharedtesting.BuildMessage("initialize", schema.InitializeRequestParams{
+		ProtocolVersion: schema.PROTOCOL_VERSION,
+		Capabilities:    schema.ClientCapabilities{Sampling: &struct{}{}},
+		ClientInfo:      schema.Implementation{Name: "test-client", Version: "1.0"},
+	})
+	msg.Session = manager.CreateSession("user", "init-session", nil)
+	result, err := bc.handleInitialize(msg)
+	sharedtesting.AssertJSONRPCSuccess[schema.InitializeResult](t, result, err)
+
+	entries := logs.FilterMessage("Capabilities negotiated").All()
+	if len(entries) != 1 {
+		t.Fatalf("Expected one negotiation log entry, got %d", len(entries))
+	}
+	fields := entries[0].ContextMap()
+	if fields["session_id"] != "init-session" {
+		t.Errorf("Expected session_id %q, got %v", "init-session", fields["session_id"])
+	}
+	if fields["protocol_version"] != schema.PROTOCOL_VERSION {
+		t.Errorf("Expected protocol_version %q, got %v", schema.PROTOCOL_VERSION, fields["protocol_version"])
+	}
+	clientCaps, ok := fields["negotiated_client_capabilities"].(schema.ClientCapabilities)
+	if !ok || clientCaps.Sampling == nil {
+		t.Errorf("Expected client capabilities with sampling, got %#v", fields["negotiated_client_capabilities"])
+	}
+	serverCaps, ok := fields["negotiated_server_capabilities"].(schema.ServerCapabilities)
+	if !ok || serverCaps.Tools == nil {
+		t.Errorf("Expected server capabilities with tools, got %#v", fields["negotiated_server_capabilities"])
+	}
+}
@@ -0,0 +1,128 @@
+package capability
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/gate4ai/gate4ai/shared"
+	"github.com/gate4ai/gate4ai/shared/mcp/2025/schema"
+	"go.uber.org/zap"
+)
+
+// ChunkedResourceHandler writes the text of a resource piece by piece instead of returning it
+// in one value. resources/read answers are streamed to MCP 2025 POST clients while the handler
+// writes, so large resources (e.g. log files) are never held in memory as a whole.
+type ChunkedResourceHandler func(msg *shared.Message, w io.Writer) error
+
+// Buffered adapts the handler to a ResourceHandler that collects all chunks into one text content.
+func (h ChunkedResourceHandler) Buffered(uri string, mimeType string) ResourceHandler {
+	return func(msg *shared.Message) (schema.Meta, []schema.ResourceContent, error) {
+		var text strings.Builder
+		if err := h(msg, &text); err != nil {
+			return nil, nil, err
+		}
+		content := text.String()
+		return nil, []schema.ResourceContent{{URI: uri, MimeType: mimeType, Text: &content}}, nil
+	}
+}
+
+// AddChunkedResource adds a resource whose text is produced by a ChunkedResourceHandler.
+func (rc *ResourcesCapability) AddChunkedResource(uri string, name string, description string, mimeType string, handler ChunkedResourceHandler) error {
+	if handler == nil {
+		return fmt.Errorf("handler cannot be nil for resource '%s'", uri)
+	}
+	if err := rc.AddResource(uri, name, description, mimeType, nil, handler.Buffered(uri, mimeType)); err != nil {
+		return err
+	}
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	rc.resources[uri].Chunked = handler
+	return nil
+}
+
+// chunkedReadResult is the ReadResourceResult of a chunked resource, written as it is produced.
+type chunkedReadResult struct {
+	msg      *shared.Message
+	uri      string
+	mimeType string
+	handler  ChunkedResourceHandler
+	logger   *zap.Logger
+}
+
+var _ shared.StreamingHandler = (*chunkedReadResult)(nil)
+
+// StreamResult writes {"contents":[{"uri":...,"mimeType":...,"text":"..."}]}, escaping each chunk into the text string.
+func (r *chunkedReadResult) StreamResult(w io.Writer) error {
+	uri, err := json.Marshal(r.uri)
+	if err != nil {
+		return err
+	}
+	mimeType, err := json.Marshal(r.mimeType)
+	if err != nil {
+		return err
+	}
+	if _, err := fmt.Fprintf(w, `{"contents":[{"uri":%s,"mimeType":%s,"text":"`, uri, mimeType); err != nil {
+		return err
+	}
+	text := &jsonStringWriter{w: w}
+	started := time.Now()
+	if err := r.handler(r.msg, text); err != nil {
+		return err
+	}
+	if err := text.Close(); err != nil {
+		return err
+	}
+	r.logger.Debug("Streamed chunked resource", zap.Int64("bytes", text.written), zap.Duration("duration", time.Since(started)))
+	_, err = io.WriteString(w, `"}]}`)
+	return err
+}
+
+// jsonStringWriter escapes everything written to it as the inside of a JSON string.
+// A UTF-8 sequence split across writes is held back until it is complete.
+type jsonStringWriter struct {
+	w       io.Writer
+	pending []byte
+	written int64
+}
+
+func (j *jsonStringWriter) Write(p []byte) (int, error) {
+	data := append(j.pending, p...)
+	cut := len(data)
+	for i := len(data) - 1; i >= 0 && i >= len(data)-utf8.UTFMax; i-- {
+		if utf8.RuneStart(data[i]) {
+			if !utf8.FullRune(data[i:]) {
+				cut = i
+			}
+			break
+		}
+	}
+	if err := j.writeEscaped(data[:cut]); err != nil {
+		return 0, err
+	}
+	j.pending = append([]byte(nil), data[cut:]...)
+	j.written += int64(len(p))
+	return len(p), nil
+}
+
+// Close flushes a trailing incomplete UTF-8 sequence (encoded as U+FFFD).
+func (j *jsonStringWriter) Close() error {
+	err := j.writeEscaped(j.pending)
+	j.pending = nil
+	return err
+}
+
+func (j *jsonStringWriter) writeEscaped(data []byte) error {
+	if len(data) == 0 {
+		return nil
+	}
+	quoted, err := json.Marshal(string(data))
+	if err != nil {
+		return err
+	}
+	_, err = j.w.Write(quoted[1 : len(quoted)-1])
+	return err
+}
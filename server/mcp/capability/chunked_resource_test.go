@@ -0,0 +1,68 @@
+package capability
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"testing"
+
+	"github.com/gate4ai/gate4ai/server/transport"
+	"github.com/gate4ai/gate4ai/shared"
+	"github.com/gate4ai/gate4ai/shared/config"
+	"github.com/gate4ai/gate4ai/shared/mcp/2025/schema"
+	sharedtesting "github.com/gate4ai/gate4ai/shared/testing"
+	"go.uber.org/zap"
+)
+
+func TestChunkedResourceStreamsEscapedText(t *testing.T) {
+	logger := zap.NewNop()
+	manager, err := transport.NewManager(logger, config.NewInternalConfig())
+	if err != nil {
+		t.Fatalf("Failed to create manager: %v", err)
+	}
+	rc := NewResourcesCapability(manager, logger)
+
+	// "é" is split across writes to check that UTF-8 sequences survive chunk boundaries
+	chunks := [][]byte{[]byte("line \"1\"\n"), {0xC3}, {0xA9, '\t'}, []byte("<end>")}
+	want := "line \"1\"\né\t<end>"
+	handler := func(msg *shared.Message, w io.Writer) error {
+		for _, chunk := range chunks {
+			if _, err := w.Write(chunk); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	if err := rc.AddChunkedResource("test://log", "Log", "", "text/plain", handler); err != nil {
+		t.Fatalf("Failed to add chunked resource: %v", err)
+	}
+
+	result, err := rc.handleResourcesRead(sharedtesting.BuildMessage("resources/read", schema.ReadResourceRequestParams{URI: "test://log"}))
+	if err != nil {
+		t.Fatalf("resources/read failed: %v", err)
+	}
+	stream, ok := result.(shared.StreamingHandler)
+	if !ok {
+		t.Fatalf("Expected a streaming result, got %T", result)
+	}
+	var body bytes.Buffer
+	if err := stream.StreamResult(&body); err != nil {
+		t.Fatalf("StreamResult failed: %v", err)
+	}
+	var streamed schema.ReadResourceResult
+	if err := json.Unmarshal(body.Bytes(), &streamed); err != nil {
+		t.Fatalf("Streamed result is not valid JSON: %v (%s)", err, body.String())
+	}
+	if len(streamed.Contents) != 1 || streamed.Contents[0].Text == nil || *streamed.Contents[0].Text != want {
+		t.Fatalf("Expected text %q, got %s", want, body.String())
+	}
+	if streamed.Contents[0].URI != "test://log" || streamed.Contents[0].MimeType != "text/plain" {
+		t.Errorf("Unexpected content metadata: %+v", streamed.Contents[0])
+	}
+
+	// The buffered fallback used by non-streaming callers returns the same text
+	_, contents, err := rc.resources["test://log"].Handler(sharedtesting.BuildMessage("resources/read", nil))
+	if err != nil || len(contents) != 1 || *contents[0].Text != want {
+		t.Fatalf("Buffered handler returned %+v, %v", contents, err)
+	}
+}
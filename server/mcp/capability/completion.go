@@ -38,6 +38,7 @@ type CompletionCapability struct {
 	mu                 sync.RWMutex
 	promptCompleters   map[string]CompletionHandler                          // Map prompt name -> handler
 	resourceCompleters map[string]CompletionHandler                          // Map resource URI (or pattern) -> handler
+	defaultCompleter   CompletionHandler                                     // Used for references without their own completer, set by SetDefaultCompleter
 	handlers           map[string]func(*shared.Message) (interface{}, error) // Map method -> handler function
 }
 
@@ -63,6 +64,9 @@ func (cc *CompletionCapability) SetCapabilities(s *schema.ServerCapabilities) {
 	s.Completions = &struct{}{}
 }
 
+// SetEventBus is part of the IServerCapability interface; completions publish no events.
+func (cc *CompletionCapability) SetEventBus(bus shared.EventBus) {}
+
 // AddPromptCompleter adds a completer for a specific prompt name.
 func (cc *CompletionCapability) AddPromptCompleter(promptName string, handler CompletionHandler) {
 	cc.mu.Lock()
@@ -88,6 +92,15 @@ func (cc *CompletionCapability) AddResourceCompleter(resourceURI string, handler
 	cc.logger.Info("Added resource completer", zap.String("resourceURI", resourceURI))
 }
 
+// SetDefaultCompleter sets the handler for prompts and resources without their own completer;
+// nil removes it.
+func (cc *CompletionCapability) SetDefaultCompleter(handler CompletionHandler) {
+	cc.mu.Lock()
+	defer cc.mu.Unlock()
+	cc.defaultCompleter = handler
+	cc.logger.Info("Set default completer", zap.Bool("enabled", handler != nil))
+}
+
 // RemovePromptCompleter removes a prompt completer.
 func (cc *CompletionCapability) RemovePromptCompleter(promptName string) {
 	cc.mu.Lock()
@@ -111,7 +124,7 @@ func (cc *CompletionCapability) RemoveResourceCompleter(resourceURI string) {
 }
 
 // findResourceCompleter finds the most specific resource completer for a URI.
-// Currently only supports exact matches.
+// Currently only supports exact matches, then falls back to the default completer.
 func (cc *CompletionCapability) findResourceCompleter(uri string) (CompletionHandler, bool) {
 	cc.mu.RLock()
 	defer cc.mu.RUnlock()
@@ -124,6 +137,9 @@ func (cc *CompletionCapability) findResourceCompleter(uri string) (CompletionHan
 	// TODO: Add pattern matching (e.g., prefix matching, wildcard matching)
 	// Example: iterate through patterns and find the longest matching one.
 
+	if cc.defaultCompleter != nil {
+		return cc.defaultCompleter, true
+	}
 	return nil, false
 }
 
@@ -165,6 +181,9 @@ func (cc *CompletionCapability) handleCompletionComplete(msg *shared.Message) (i
 		logger.Debug("Completion requested for prompt", zap.String("promptName", refIdentifier))
 		cc.mu.RLock()
 		handler, exists = cc.promptCompleters[refIdentifier]
+		if !exists && cc.defaultCompleter != nil {
+			handler, exists = cc.defaultCompleter, true
+		}
 		cc.mu.RUnlock()
 		if !exists {
 			logger.Warn("No completion handler found for prompt", zap.String("promptName", refIdentifier))
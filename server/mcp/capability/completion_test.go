@@ -0,0 +1,98 @@
+package capability
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/gate4ai/gate4ai/shared"
+	"github.com/gate4ai/gate4ai/shared/mcp/2025/schema"
+	sharedtesting "github.com/gate4ai/gate4ai/shared/testing"
+	"go.uber.org/zap"
+)
+
+func buildCompletionMessage(ref interface{}, name, value string) *shared.Message {
+	rawRef, _ := json.Marshal(ref)
+	return sharedtesting.BuildMessage("completion/complete", schema.CompletionRequestParams{
+		Argument: schema.CompleteArgument{Name: name, Value: value},
+		Ref:      rawRef,
+	})
+}
+
+func completionValues(values ...string) CompletionHandler {
+	return func(msg *shared.Message, arg schema.CompleteArgument) (*schema.CompletionInfo, error) {
+		result := []string{}
+		for _, v := range values {
+			if strings.HasPrefix(v, arg.Value) {
+				result = append(result, v)
+			}
+		}
+		return &schema.CompletionInfo{Values: result}, nil
+	}
+}
+
+func TestCompletionForPromptArguments(t *testing.T) {
+	cc := NewCompletionCapability(zap.NewNop())
+	cc.AddPromptCompleter("code_review", completionValues("python", "pytorch", "go"))
+
+	msg := buildCompletionMessage(map[string]string{"type": "ref/prompt", "name": "code_review"}, "language", "py")
+	result, err := cc.handleCompletionComplete(msg)
+	completion := sharedtesting.AssertJSONRPCSuccess[schema.CompleteResult](t, result, err)
+	if !reflect.DeepEqual(completion.Completion.Values, []string{"python", "pytorch"}) {
+		t.Errorf("Expected [python pytorch], got %v", completion.Completion.Values)
+	}
+
+	msg = buildCompletionMessage(map[string]string{"type": "ref/prompt", "name": "unknown"}, "language", "py")
+	if _, err := cc.handleCompletionComplete(msg); err == nil {
+		t.Errorf("Expected an error for a prompt without completer")
+	}
+}
+
+func TestCompletionForResourceTemplateArguments(t *testing.T) {
+	cc := NewCompletionCapability(zap.NewNop())
+	cc.AddResourceCompleter("file:///{path}", completionValues("README.md", "go.mod"))
+
+	msg := buildCompletionMessage(map[string]string{"type": "ref/resource", "uri": "file:///{path}"}, "path", "go")
+	result, err := cc.handleCompletionComplete(msg)
+	completion := sharedtesting.AssertJSONRPCSuccess[schema.CompleteResult](t, result, err)
+	if !reflect.DeepEqual(completion.Completion.Values, []string{"go.mod"}) {
+		t.Errorf("Expected [go.mod], got %v", completion.Completion.Values)
+	}
+
+	msg = buildCompletionMessage(map[string]string{"type": "ref/resource", "uri": "db://{table}"}, "table", "")
+	if _, err := cc.handleCompletionComplete(msg); err == nil {
+		t.Errorf("Expected an error for a resource without completer")
+	}
+}
+
+func TestCompletionFallsBackToDefaultCompleter(t *testing.T) {
+	cc := NewCompletionCapability(zap.NewNop())
+	cc.AddPromptCompleter("code_review", completionValues("python"))
+	cc.SetDefaultCompleter(completionValues("default"))
+
+	// Registered completers take precedence
+	msg := buildCompletionMessage(map[string]string{"type": "ref/prompt", "name": "code_review"}, "language", "")
+	result, err := cc.handleCompletionComplete(msg)
+	completion := sharedtesting.AssertJSONRPCSuccess[schema.CompleteResult](t, result, err)
+	if !reflect.DeepEqual(completion.Completion.Values, []string{"python"}) {
+		t.Errorf("Expected [python], got %v", completion.Completion.Values)
+	}
+
+	for _, ref := range []map[string]string{
+		{"type": "ref/prompt", "name": "summarize"},
+		{"type": "ref/resource", "uri": "db://{table}"},
+	} {
+		msg := buildCompletionMessage(ref, "arg", "")
+		result, err := cc.handleCompletionComplete(msg)
+		completion := sharedtesting.AssertJSONRPCSuccess[schema.CompleteResult](t, result, err)
+		if !reflect.DeepEqual(completion.Completion.Values, []string{"default"}) {
+			t.Errorf("Expected [default] for %v, got %v", ref, completion.Completion.Values)
+		}
+	}
+
+	msg = buildCompletionMessage(map[string]string{"type": "ref/unknown"}, "arg", "")
+	if _, err := cc.handleCompletionComplete(msg); err == nil {
+		t.Errorf("Expected an error for an unsupported reference type")
+	}
+}
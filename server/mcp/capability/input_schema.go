@@ -0,0 +1,449 @@
+package capability
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/gate4ai/gate4ai/shared/mcp/2025/schema"
+)
+
+// errInvalidSchema marks a problem in the tool's own input schema, such as a pattern that
+// does not compile, as opposed to arguments that do not match it.
+var errInvalidSchema = errors.New("invalid input schema")
+
+// schemaValidator checks arguments against one input schema. It is built once per tool, so
+// every pattern in the schema is compiled only once.
+type schemaValidator struct {
+	root     *schema.JSONSchemaProperty
+	patterns map[string]*regexp.Regexp
+	err      error // First pattern that failed to compile
+}
+
+// newSchemaValidator compiles the patterns of inputSchema. A nil schema accepts any arguments.
+func newSchemaValidator(inputSchema *schema.JSONSchemaProperty) *schemaValidator {
+	v := &schemaValidator{root: inputSchema, patterns: make(map[string]*regexp.Regexp)}
+	if inputSchema != nil {
+		v.compilePatterns(inputSchema)
+	}
+	return v
+}
+
+func (v *schemaValidator) compilePatterns(s *schema.JSONSchemaProperty) {
+	compile := func(pattern string) {
+		if _, ok := v.patterns[pattern]; ok {
+			return
+		}
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			if v.err == nil {
+				v.err = fmt.Errorf("%w: pattern '%s': %v", errInvalidSchema, pattern, err)
+			}
+			return
+		}
+		v.patterns[pattern] = re
+	}
+	if s.Pattern != "" {
+		compile(s.Pattern)
+	}
+	for pattern, property := range s.PatternProperties {
+		compile(pattern)
+		v.compilePatterns(&property)
+	}
+	for _, property := range s.Properties {
+		v.compilePatterns(&property)
+	}
+	for _, definition := range s.Definitions {
+		v.compilePatterns(&definition)
+	}
+	if s.Items != nil {
+		v.compilePatterns(s.Items)
+	}
+	if s.Not != nil {
+		v.compilePatterns(s.Not)
+	}
+	for _, subschemas := range [][]schema.JSONSchemaProperty{s.AllOf, s.AnyOf, s.OneOf} {
+		for i := range subschemas {
+			v.compilePatterns(&subschemas[i])
+		}
+	}
+	if _, ok := s.AdditionalProperties.(bool); !ok && s.AdditionalProperties != nil {
+		if additional, err := toSchema(s.AdditionalProperties); err == nil {
+			v.compilePatterns(additional)
+		}
+	}
+}
+
+// validateArguments checks tool call arguments against the tool's input schema and returns
+// the first violation as a readable error ("arguments.count: expected integer, got string").
+// Missing arguments are validated as an empty object. Errors caused by the schema itself
+// wrap errInvalidSchema.
+//
+// The supported keywords are those of JSONSchemaProperty: type, properties, required,
+// additionalProperties, patternProperties, items, enum, const, the numeric, string, array
+// and object bounds, anyOf/oneOf/allOf/not and local "#/definitions/..." references.
+// format is an annotation and is not checked.
+func (v *schemaValidator) validateArguments(args schema.Arguments) error {
+	if v.root == nil {
+		return nil
+	}
+	if v.err != nil {
+		return v.err
+	}
+	var value interface{} = map[string]interface{}{}
+	if args != nil {
+		value = map[string]interface{}(args)
+	}
+	return v.validate(v.root, value, "arguments")
+}
+
+// validate checks value against s.
+func (v *schemaValidator) validate(s *schema.JSONSchemaProperty, value interface{}, path string) error {
+	return v.check(s, value, path, nil)
+}
+
+// check checks value against s. refs holds the references already followed for this value;
+// seeing one again means the schema refers to itself without descending into the value.
+func (v *schemaValidator) check(s *schema.JSONSchemaProperty, value interface{}, path string, refs []string) error {
+	for s.Ref != "" {
+		if slices.Contains(refs, s.Ref) {
+			return fmt.Errorf("%s: %w: circular reference '%s'", path, errInvalidSchema, s.Ref)
+		}
+		refs = append(refs, s.Ref)
+		resolved, err := v.resolve(s.Ref)
+		if err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+		s = resolved
+	}
+
+	if s.Type != "" && !matchesType(s.Type, value) {
+		return fmt.Errorf("%s: expected %s, got %s", path, s.Type, jsonTypeName(value))
+	}
+	if s.Const != nil && !jsonEqual(s.Const, value) {
+		return fmt.Errorf("%s: must be %s", path, jsonText(s.Const))
+	}
+	if len(s.Enum) > 0 {
+		found := false
+		for _, allowed := range s.Enum {
+			if jsonEqual(allowed, value) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			allowed := make([]string, len(s.Enum))
+			for i, e := range s.Enum {
+				allowed[i] = jsonText(e)
+			}
+			return fmt.Errorf("%s: must be one of %s", path, strings.Join(allowed, ", "))
+		}
+	}
+
+	switch typed := value.(type) {
+	case float64:
+		if err := checkNumber(s, typed, path); err != nil {
+			return err
+		}
+	case string:
+		if err := v.checkString(s, typed, path); err != nil {
+			return err
+		}
+	case []interface{}:
+		if err := v.checkArray(s, typed, path); err != nil {
+			return err
+		}
+	case map[string]interface{}:
+		if err := v.checkObject(s, typed, path); err != nil {
+			return err
+		}
+	}
+
+	for i := range s.AllOf {
+		if err := v.check(&s.AllOf[i], value, path, refs); err != nil {
+			return err
+		}
+	}
+	if len(s.AnyOf) > 0 {
+		var firstErr error
+		matched := false
+		for i := range s.AnyOf {
+			err := v.check(&s.AnyOf[i], value, path, refs)
+			if err == nil {
+				matched = true
+				break
+			}
+			if errors.Is(err, errInvalidSchema) {
+				return err
+			}
+			if firstErr == nil {
+				firstErr = err
+			}
+		}
+		if !matched {
+			return fmt.Errorf("%s: does not match any allowed schema (%v)", path, firstErr)
+		}
+	}
+	if len(s.OneOf) > 0 {
+		matches := 0
+		for i := range s.OneOf {
+			err := v.check(&s.OneOf[i], value, path, refs)
+			if err == nil {
+				matches++
+			} else if errors.Is(err, errInvalidSchema) {
+				return err
+			}
+		}
+		if matches != 1 {
+			return fmt.Errorf("%s: must match exactly one schema, matched %d", path, matches)
+		}
+	}
+	if s.Not != nil {
+		err := v.check(s.Not, value, path, refs)
+		if err == nil {
+			return fmt.Errorf("%s: matches a disallowed schema", path)
+		}
+		if errors.Is(err, errInvalidSchema) {
+			return err
+		}
+	}
+	return nil
+}
+
+func (v *schemaValidator) checkArray(s *schema.JSONSchemaProperty, items []interface{}, path string) error {
+	if s.MinItems != nil && len(items) < *s.MinItems {
+		return fmt.Errorf("%s: must have at least %d items", path, *s.MinItems)
+	}
+	if s.MaxItems != nil && len(items) > *s.MaxItems {
+		return fmt.Errorf("%s: must have at most %d items", path, *s.MaxItems)
+	}
+	if s.UniqueItems != nil && *s.UniqueItems {
+		for i := range items {
+			for j := i + 1; j < len(items); j++ {
+				if jsonEqual(items[i], items[j]) {
+					return fmt.Errorf("%s: items %d and %d are equal", path, i, j)
+				}
+			}
+		}
+	}
+	if s.Items != nil {
+		for i, item := range items {
+			if err := v.validate(s.Items, item, fmt.Sprintf("%s[%d]", path, i)); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (v *schemaValidator) checkObject(s *schema.JSONSchemaProperty, object map[string]interface{}, path string) error {
+	for _, name := range s.Required {
+		if _, ok := object[name]; !ok {
+			return fmt.Errorf("%s: missing required property '%s'", path, name)
+		}
+	}
+	if s.MinProperties != nil && len(object) < *s.MinProperties {
+		return fmt.Errorf("%s: must have at least %d properties", path, *s.MinProperties)
+	}
+	if s.MaxProperties != nil && len(object) > *s.MaxProperties {
+		return fmt.Errorf("%s: must have at most %d properties", path, *s.MaxProperties)
+	}
+
+	// Sorted names give the same error for the same arguments
+	names := make([]string, 0, len(object))
+	for name := range object {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		propertyPath := path + "." + name
+		matched := false
+		if property, ok := s.Properties[name]; ok {
+			matched = true
+			if err := v.validate(&property, object[name], propertyPath); err != nil {
+				return err
+			}
+		}
+		for pattern, property := range s.PatternProperties {
+			if v.patterns[pattern].MatchString(name) {
+				matched = true
+				if err := v.validate(&property, object[name], propertyPath); err != nil {
+					return err
+				}
+			}
+		}
+		if matched {
+			continue
+		}
+		switch additional := s.AdditionalProperties.(type) {
+		case bool:
+			if !additional {
+				return fmt.Errorf("%s: unexpected property '%s'", path, name)
+			}
+		case nil:
+		default:
+			additionalSchema, err := toSchema(additional)
+			if err != nil {
+				return fmt.Errorf("%s: %w: additionalProperties: %v", path, errInvalidSchema, err)
+			}
+			if err := v.validate(additionalSchema, object[name], propertyPath); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// resolve looks up a local "#/definitions/<name>" reference in the root schema.
+func (v *schemaValidator) resolve(ref string) (*schema.JSONSchemaProperty, error) {
+	name, ok := strings.CutPrefix(ref, "#/definitions/")
+	if !ok {
+		return nil, fmt.Errorf("%w: unsupported reference '%s'", errInvalidSchema, ref)
+	}
+	definition, ok := v.root.Definitions[name]
+	if !ok {
+		return nil, fmt.Errorf("%w: unknown reference '%s'", errInvalidSchema, ref)
+	}
+	return &definition, nil
+}
+
+func checkNumber(s *schema.JSONSchemaProperty, n float64, path string) error {
+	if s.Minimum != nil && n < *s.Minimum {
+		return fmt.Errorf("%s: must be >= %v", path, *s.Minimum)
+	}
+	if s.Maximum != nil && n > *s.Maximum {
+		return fmt.Errorf("%s: must be <= %v", path, *s.Maximum)
+	}
+	if s.ExclusiveMinimum != nil && n <= *s.ExclusiveMinimum {
+		return fmt.Errorf("%s: must be > %v", path, *s.ExclusiveMinimum)
+	}
+	if s.ExclusiveMaximum != nil && n >= *s.ExclusiveMaximum {
+		return fmt.Errorf("%s: must be < %v", path, *s.ExclusiveMaximum)
+	}
+	if s.MultipleOf != nil && *s.MultipleOf > 0 {
+		if q := n / *s.MultipleOf; q != math.Trunc(q) {
+			return fmt.Errorf("%s: must be a multiple of %v", path, *s.MultipleOf)
+		}
+	}
+	return nil
+}
+
+func (v *schemaValidator) checkString(s *schema.JSONSchemaProperty, str string, path string) error {
+	length := utf8.RuneCountInString(str)
+	if s.MinLength != nil && length < *s.MinLength {
+		return fmt.Errorf("%s: must be at least %d characters", path, *s.MinLength)
+	}
+	if s.MaxLength != nil && length > *s.MaxLength {
+		return fmt.Errorf("%s: must be at most %d characters", path, *s.MaxLength)
+	}
+	if s.Pattern != "" {
+		if !v.patterns[s.Pattern].MatchString(str) {
+			return fmt.Errorf("%s: must match pattern '%s'", path, s.Pattern)
+		}
+	}
+	return nil
+}
+
+// matchesType reports whether value has the JSON Schema type t. Arguments decoded by
+// encoding/json hold every number as float64, so "integer" accepts whole floats.
+func matchesType(t string, value interface{}) bool {
+	switch t {
+	case "object":
+		_, ok := value.(map[string]interface{})
+		return ok
+	case "array":
+		_, ok := value.([]interface{})
+		return ok
+	case "string":
+		_, ok := value.(string)
+		return ok
+	case "boolean":
+		_, ok := value.(bool)
+		return ok
+	case "number":
+		_, ok := value.(float64)
+		return ok
+	case "integer":
+		n, ok := value.(float64)
+		return ok && n == math.Trunc(n)
+	case "null":
+		return value == nil
+	default:
+		return true // Unknown types are not enforced
+	}
+}
+
+func jsonTypeName(value interface{}) string {
+	switch typed := value.(type) {
+	case nil:
+		return "null"
+	case map[string]interface{}:
+		return "object"
+	case []interface{}:
+		return "array"
+	case string:
+		return "string"
+	case bool:
+		return "boolean"
+	case float64:
+		if typed == math.Trunc(typed) {
+			return "integer"
+		}
+		return "number"
+	default:
+		return fmt.Sprintf("%T", value)
+	}
+}
+
+// jsonEqual compares values by their JSON form, so a Go int in the schema equals the float64
+// decoded from the arguments.
+func jsonEqual(a, b interface{}) bool {
+	return reflect.DeepEqual(normalizeJSON(a), normalizeJSON(b))
+}
+
+func normalizeJSON(value interface{}) interface{} {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return value
+	}
+	var normalized interface{}
+	if err := json.Unmarshal(data, &normalized); err != nil {
+		return value
+	}
+	return normalized
+}
+
+func jsonText(value interface{}) string {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprintf("%v", value)
+	}
+	return string(data)
+}
+
+// toSchema converts an additionalProperties schema, which is held as interface{}.
+func toSchema(value interface{}) (*schema.JSONSchemaProperty, error) {
+	if s, ok := value.(*schema.JSONSchemaProperty); ok {
+		return s, nil
+	}
+	if s, ok := value.(schema.JSONSchemaProperty); ok {
+		return &s, nil
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+	var s schema.JSONSchemaProperty
+	if err := json.Unmarshal(data, &s); err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
@@ -0,0 +1,112 @@
+package capability
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/gate4ai/gate4ai/shared"
+
+	// Use 2025 schema
+	schema "github.com/gate4ai/gate4ai/shared/mcp/2025/schema"
+	"go.uber.org/zap"
+)
+
+// LogMessageMethod is the notification that forwards server log messages to clients.
+const LogMessageMethod = "notifications/message"
+
+// DefaultLoggingLevel is the minimum level of sessions that have not sent logging/setLevel.
+const DefaultLoggingLevel = schema.LoggingLevelInfo
+
+// loggingLevelKey stores the minimum logging level of a session in its parameters.
+const loggingLevelKey = "mcpLoggingLevel"
+
+// loggingSeverity orders the syslog levels of the MCP spec, most verbose first.
+var loggingSeverity = map[schema.LoggingLevel]int{
+	schema.LoggingLevelDebug:     0,
+	schema.LoggingLevelInfo:      1,
+	schema.LoggingLevelNotice:    2,
+	schema.LoggingLevelWarning:   3,
+	schema.LoggingLevelError:     4,
+	schema.LoggingLevelCritical:  5,
+	schema.LoggingLevelAlert:     6,
+	schema.LoggingLevelEmergency: 7,
+}
+
+var _ shared.IServerCapability = (*LoggingCapability)(nil)
+
+// LoggingCapability forwards log messages to clients, filtered by the level each session set.
+type LoggingCapability struct {
+	logger   *zap.Logger
+	handlers map[string]func(*shared.Message) (interface{}, error) // Map method -> handler function
+}
+
+// NewLoggingCapability creates a new LoggingCapability.
+func NewLoggingCapability(logger *zap.Logger) *LoggingCapability {
+	lc := &LoggingCapability{
+		logger: logger.Named("logging-capability"),
+	}
+	lc.handlers = map[string]func(*shared.Message) (interface{}, error){
+		"logging/setLevel": lc.handleSetLevel,
+	}
+	return lc
+}
+
+func (lc *LoggingCapability) GetHandlers() map[string]func(*shared.Message) (interface{}, error) {
+	return lc.handlers
+}
+
+func (lc *LoggingCapability) SetCapabilities(s *schema.ServerCapabilities) {
+	s.Logging = &struct{}{}
+}
+
+// SetEventBus is part of the IServerCapability interface; logging publishes no events.
+func (lc *LoggingCapability) SetEventBus(bus shared.EventBus) {}
+
+// SessionLevel returns the minimum logging level of a session.
+func (lc *LoggingCapability) SessionLevel(session shared.ISession) schema.LoggingLevel {
+	if value, ok := session.GetParams().Load(loggingLevelKey); ok {
+		if level, ok := value.(schema.LoggingLevel); ok {
+			return level
+		}
+	}
+	return DefaultLoggingLevel
+}
+
+// SendLogMessage sends a notifications/message to the session if level is at or above the
+// session's minimum level. logger names the source of the message and may be empty.
+func (lc *LoggingCapability) SendLogMessage(session shared.ISession, level schema.LoggingLevel, logger string, data string) error {
+	severity, ok := loggingSeverity[level]
+	if !ok {
+		return fmt.Errorf("unknown logging level '%s'", level)
+	}
+	if severity < loggingSeverity[lc.SessionLevel(session)] {
+		return nil
+	}
+
+	params := map[string]any{"level": level, "data": data}
+	if logger != "" {
+		params["logger"] = logger
+	}
+	session.SendNotification(LogMessageMethod, params)
+	return nil
+}
+
+// handleSetLevel handles the "logging/setLevel" request, storing the level in the session.
+func (lc *LoggingCapability) handleSetLevel(msg *shared.Message) (interface{}, error) {
+	logger := lc.logger.With(zap.String("sessionID", msg.Session.GetID()), zap.String("method", "logging/setLevel"))
+
+	var params schema.SetLevelRequestParams
+	if msg.Params == nil {
+		return nil, shared.NewJSONRPCError(&shared.JSONRPCError{Code: shared.JSONRPCErrorInvalidParams, Message: "Missing params"})
+	}
+	if err := json.Unmarshal(*msg.Params, &params); err != nil {
+		return nil, shared.NewJSONRPCError(&shared.JSONRPCError{Code: shared.JSONRPCErrorInvalidParams, Message: fmt.Sprintf("Invalid parameters: %v", err)})
+	}
+	if _, ok := loggingSeverity[params.Level]; !ok {
+		return nil, shared.NewJSONRPCError(&shared.JSONRPCError{Code: shared.JSONRPCErrorInvalidParams, Message: fmt.Sprintf("Unknown logging level '%s'", params.Level)})
+	}
+
+	msg.Session.GetParams().Store(loggingLevelKey, params.Level)
+	logger.Debug("Set session logging level", zap.String("level", string(params.Level)))
+	return map[string]interface{}{}, nil
+}
@@ -0,0 +1,81 @@
+package capability
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/gate4ai/gate4ai/server/transport"
+	"github.com/gate4ai/gate4ai/shared"
+	"github.com/gate4ai/gate4ai/shared/config"
+	"github.com/gate4ai/gate4ai/shared/mcp/2025/schema"
+	sharedtesting "github.com/gate4ai/gate4ai/shared/testing"
+	"go.uber.org/zap"
+)
+
+func TestLogMessagesRespectSessionLevel(t *testing.T) {
+	logger := zap.NewNop()
+	manager, err := transport.NewManager(logger, config.NewInternalConfig())
+	if err != nil {
+		t.Fatalf("Failed to create manager: %v", err)
+	}
+	lc := NewLoggingCapability(logger)
+
+	session := manager.CreateSession("user", "logging-session", nil)
+	output, ok := session.AcquireOutput()
+	if !ok {
+		t.Fatalf("Failed to acquire session output")
+	}
+	defer session.ReleaseOutput()
+
+	msg := sharedtesting.BuildMessage("logging/setLevel", schema.SetLevelRequestParams{Level: schema.LoggingLevelError})
+	msg.Session = session
+	result, err := lc.handleSetLevel(msg)
+	sharedtesting.AssertJSONRPCSuccess[map[string]interface{}](t, result, err)
+	if level := lc.SessionLevel(session); level != schema.LoggingLevelError {
+		t.Fatalf("Expected session level error, got %s", level)
+	}
+
+	if err := lc.SendLogMessage(session, schema.LoggingLevelInfo, "indexer", "indexing started"); err != nil {
+		t.Fatalf("Failed to send info message: %v", err)
+	}
+	if err := lc.SendLogMessage(session, schema.LoggingLevelError, "indexer", "indexing failed"); err != nil {
+		t.Fatalf("Failed to send error message: %v", err)
+	}
+
+	select {
+	case notification := <-output:
+		if notification.Method == nil || *notification.Method != LogMessageMethod {
+			t.Fatalf("Expected %s, got %+v", LogMessageMethod, notification)
+		}
+		var params schema.LoggingMessageNotificationParams
+		if err := json.Unmarshal(*notification.Params, &params); err != nil {
+			t.Fatalf("Failed to decode log message params: %v", err)
+		}
+		if params.Level != schema.LoggingLevelError || params.Logger != "indexer" || params.Data != "indexing failed" {
+			t.Errorf("Expected only the error message, got %+v", params)
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("No log message notification for an error")
+	}
+	select {
+	case notification := <-output:
+		t.Fatalf("Unexpected second notification %+v", notification)
+	default:
+	}
+}
+
+func TestSetLevelRejectsUnknownLevel(t *testing.T) {
+	lc := NewLoggingCapability(zap.NewNop())
+	msg := sharedtesting.BuildMessage("logging/setLevel", schema.SetLevelRequestParams{Level: "verbose"})
+	result, err := lc.handleSetLevel(msg)
+	sharedtesting.AssertJSONRPCError(t, result, err, shared.JSONRPCErrorInvalidParams)
+
+	// Sessions without a level get the default
+	if level := lc.SessionLevel(msg.Session); level != DefaultLoggingLevel {
+		t.Errorf("Expected default level %s, got %s", DefaultLoggingLevel, level)
+	}
+	if err := lc.SendLogMessage(msg.Session, "verbose", "", "data"); err == nil {
+		t.Errorf("Expected an error for an unknown level")
+	}
+}
@@ -0,0 +1,86 @@
+package capability
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/gate4ai/gate4ai/shared"
+	schema "github.com/gate4ai/gate4ai/shared/mcp/2025/schema"
+)
+
+// ProgressNotificationMethod is the notification sent by ProgressReporter.Report.
+const ProgressNotificationMethod = "notifications/progress"
+
+// ProgressReporter sends the progress of a running request to the calling client.
+type ProgressReporter interface {
+	// Report tells the client how far the request is; total <= 0 means unknown and message may be empty.
+	Report(ctx context.Context, progress, total float64, message string) error
+}
+
+// ProgressHandler is a ToolHandler for long-running tools that report their progress.
+type ProgressHandler func(msg *shared.Message, arguments schema.Arguments, progress ProgressReporter) (*schema.Meta, []schema.Content, error)
+
+// AddProgressTool adds a tool whose handler reports progress while it runs.
+func (tc *ToolsCapability) AddProgressTool(name string, description string, inputSchema *schema.JSONSchemaProperty, annotations *schema.ToolAnnotations, handler ProgressHandler) error {
+	if handler == nil {
+		return fmt.Errorf("handler cannot be nil for tool '%s'", name)
+	}
+	return tc.AddTool(name, description, inputSchema, annotations, ProgressToolHandler(handler))
+}
+
+// ProgressToolHandler adapts handler to a ToolHandler that reports progress on the session of each call.
+func ProgressToolHandler(handler ProgressHandler) ToolHandler {
+	return func(msg *shared.Message, arguments schema.Arguments) (*schema.Meta, []schema.Content, error) {
+		return handler(msg, arguments, GetProgressReporter(msg, ProgressToken(msg)))
+	}
+}
+
+// GetProgressReporter returns a ProgressReporter that sends notifications/progress with token on
+// the session of msg. As the MCP spec requires, nothing is sent if the client gave no token.
+func GetProgressReporter(msg *shared.Message, token interface{}) ProgressReporter {
+	return &sessionProgressReporter{msg: msg, token: token}
+}
+
+// sessionProgressReporter sends ProgressNotificationMethod notifications on the session of a request.
+type sessionProgressReporter struct {
+	msg   *shared.Message
+	token schema.ProgressToken
+}
+
+func (r *sessionProgressReporter) Report(ctx context.Context, progress, total float64, message string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if r.token == nil {
+		return nil
+	}
+	if r.msg.Session == nil {
+		return fmt.Errorf("request has no session to report progress on")
+	}
+	params := map[string]any{"progressToken": r.token, "progress": progress}
+	if total > 0 {
+		params["total"] = total
+	}
+	if message != "" {
+		params["message"] = message
+	}
+	r.msg.Session.SendNotification(ProgressNotificationMethod, params)
+	return nil
+}
+
+// ProgressToken returns the token the client set in _meta of the request, or nil.
+func ProgressToken(msg *shared.Message) schema.ProgressToken {
+	if msg.Params == nil {
+		return nil
+	}
+	var params struct {
+		Meta *struct {
+			ProgressToken schema.ProgressToken `json:"progressToken"`
+		} `json:"_meta"`
+	}
+	if err := json.Unmarshal(*msg.Params, &params); err != nil || params.Meta == nil {
+		return nil
+	}
+	return params.Meta.ProgressToken
+}
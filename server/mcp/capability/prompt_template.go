@@ -0,0 +1,58 @@
+package capability
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+	"text/template"
+
+	"github.com/gate4ai/gate4ai/shared"
+	schema "github.com/gate4ai/gate4ai/shared/mcp/2025/schema"
+)
+
+// jinjaVariable matches a Jinja2-style "{{ name }}" placeholder.
+var jinjaVariable = regexp.MustCompile(`\{\{\s*([A-Za-z_][A-Za-z0-9_]*)\s*\}\}`)
+
+// NewTemplatePromptHandler creates a PromptHandler that renders templateStr with the
+// prompts/get arguments and returns it as a single user message.
+// Jinja2-style "{{ name }}" placeholders are accepted next to regular text/template
+// syntax ("{{ .name }}"). Missing required arguments are reported as errors;
+// missing optional arguments render as empty strings.
+func NewTemplatePromptHandler(templateStr string, arguments []schema.PromptArgument) (PromptHandler, error) {
+	tmpl, err := template.New("prompt").Option("missingkey=zero").Parse(jinjaVariable.ReplaceAllString(templateStr, `{{index . "$1"}}`))
+	if err != nil {
+		return nil, fmt.Errorf("invalid prompt template: %w", err)
+	}
+
+	return func(msg *shared.Message) (*schema.Meta, []schema.PromptMessage, error) {
+		var params schema.GetPromptRequestParams
+		if msg.Params != nil {
+			if err := json.Unmarshal(*msg.Params, &params); err != nil {
+				return nil, nil, fmt.Errorf("failed to parse parameters: %v", err)
+			}
+		}
+
+		data := make(map[string]string, len(arguments)+len(params.Arguments))
+		for _, arg := range arguments {
+			value, ok := params.Arguments[arg.Name]
+			if !ok && arg.Required {
+				return nil, nil, fmt.Errorf("missing required parameter: %s", arg.Name)
+			}
+			data[arg.Name] = value
+		}
+		for name, value := range params.Arguments {
+			data[name] = value
+		}
+
+		var rendered strings.Builder
+		if err := tmpl.Execute(&rendered, data); err != nil {
+			return nil, nil, fmt.Errorf("failed to render prompt template: %w", err)
+		}
+		text := rendered.String()
+		return nil, []schema.PromptMessage{{
+			Role:    "user",
+			Content: schema.Content{Type: "text", Text: &text},
+		}}, nil
+	}, nil
+}
@@ -0,0 +1,57 @@
+package capability
+
+import (
+	"testing"
+
+	"github.com/gate4ai/gate4ai/server/transport"
+	"github.com/gate4ai/gate4ai/shared"
+	"github.com/gate4ai/gate4ai/shared/config"
+	"github.com/gate4ai/gate4ai/shared/mcp/2025/schema"
+	sharedtesting "github.com/gate4ai/gate4ai/shared/testing"
+	"go.uber.org/zap"
+)
+
+func TestTemplatePromptRendersArguments(t *testing.T) {
+	logger := zap.NewNop()
+	manager, err := transport.NewManager(logger, config.NewInternalConfig())
+	if err != nil {
+		t.Fatalf("Failed to create manager: %v", err)
+	}
+	pc := NewPromptsCapability(logger, manager)
+
+	arguments := []schema.PromptArgument{
+		{Name: "language", Required: true},
+		{Name: "topic", Required: true},
+	}
+	handler, err := NewTemplatePromptHandler("Explain {{ topic }} in {{language}} to a {{ .audience }}.", arguments)
+	if err != nil {
+		t.Fatalf("Failed to create template handler: %v", err)
+	}
+	if err := pc.AddTemplate("explain", "Explain a topic", arguments, handler); err != nil {
+		t.Fatalf("Failed to add template: %v", err)
+	}
+
+	result, err := pc.handlePromptsGet(sharedtesting.BuildMessage("prompts/get", schema.GetPromptRequestParams{
+		Name:      "explain",
+		Arguments: map[string]string{"language": "Go", "topic": "channels", "audience": "beginner"},
+	}))
+	messages := sharedtesting.AssertJSONRPCSuccess[schema.GetPromptResult](t, result, err).Messages
+	if len(messages) != 1 || messages[0].Content.Text == nil {
+		t.Fatalf("Expected one text message, got %+v", messages)
+	}
+	if want := "Explain channels in Go to a beginner."; *messages[0].Content.Text != want {
+		t.Errorf("Expected %q, got %q", want, *messages[0].Content.Text)
+	}
+
+	result, err = pc.handlePromptsGet(sharedtesting.BuildMessage("prompts/get", schema.GetPromptRequestParams{
+		Name:      "explain",
+		Arguments: map[string]string{"language": "Go"},
+	}))
+	sharedtesting.AssertJSONRPCError(t, result, err, shared.JSONRPCErrorServerError)
+}
+
+func TestTemplatePromptRejectsInvalidTemplate(t *testing.T) {
+	if _, err := NewTemplatePromptHandler("Hello {{ name", nil); err == nil {
+		t.Fatalf("Expected an error for an unterminated placeholder")
+	}
+}
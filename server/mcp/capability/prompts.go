@@ -75,6 +75,9 @@ func (pc *PromptsCapability) SetCapabilities(s *schema.ServerCapabilities) {
 	}
 }
 
+// SetEventBus is part of the IServerCapability interface; prompts publish no events.
+func (pc *PromptsCapability) SetEventBus(bus shared.EventBus) {}
+
 // AddPrompt adds a new prompt (not a template) with the specified details.
 func (pc *PromptsCapability) AddPrompt(name string, description string, handler PromptHandler) error {
 	pc.mu.Lock()
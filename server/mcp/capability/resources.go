@@ -1,9 +1,13 @@
 package capability
 
 import (
+	"encoding/base64"
 	"encoding/json"
 	"fmt"
 	"reflect"
+	"sort"
+	"strconv"
+	"strings"
 	"sync"
 	"time"
 
@@ -28,7 +32,12 @@ type SubscriptionHandler func(session shared.ISession, operation SubscriptionOpe
 // ResourceHandler is a function that processes a resource read request.
 type ResourceHandler func(msg *shared.Message) (schema.Meta, []schema.ResourceContent, error)
 
+// ResourceTemplateHandler reads a URI matched by a resource template; vars are the decoded
+// values of the template's variables, e.g. {"id": "42"} for test://items/42 and test://items/{id}.
+type ResourceTemplateHandler func(msg *shared.Message, vars map[string]string) (schema.Meta, []schema.ResourceContent, error)
+
 var _ shared.IServerCapability = (*ResourcesCapability)(nil) // Ensure interface implementation
+var _ transport.SessionCloseListener = (*ResourcesCapability)(nil)
 
 // ResourcesCapability handles resource management, reading, and subscriptions.
 type ResourcesCapability struct {
@@ -37,33 +46,127 @@ type ResourcesCapability struct {
 	mu                    sync.RWMutex
 	resources             map[string]*Resource
 	templates             map[string]*ResourceTemplate
-	subscribers           map[string]map[string]bool // URI -> SessionID -> true
+	templateOrder         []string                              // URI templates in insertion order, the order resources/read matches them in
+	subscribers           map[string]map[string]shared.ISession // URI -> SessionID -> subscribed session
 	subscribeOnSubscribes []SubscriptionHandler
 	handlers              map[string]func(*shared.Message) (interface{}, error)
+	authzPolicy           ResourceAuthzPolicy // Optional, checked before reading a resource
+	pageSize              int                 // resources/list and resources/templates/list page size; 0 returns everything at once
+	snapshotTTL           time.Duration
+	snapshots             map[string]*resourceSnapshot // Snapshot ID -> snapshot referenced by cursors
+	currentSnapshot       *resourceSnapshot            // Reused by first-page calls until the list changes
+	snapshotSeq           uint64
+	eventBus              shared.EventBus // Set by SetEventBus; nil publishes no events
+}
+
+// ResourceUpdatedTopic is the EventBus topic TriggerResourceUpdate publishes the URI of an
+// updated resource to.
+func ResourceUpdatedTopic(uri string) string {
+	return "resource.updated:" + uri
+}
+
+// DefaultResourcesPageSize is the page size of resources/list and resources/templates/list
+// unless WithResourcesPageSize sets another.
+const DefaultResourcesPageSize = 100
+
+// DefaultResourceSnapshotTTL is how long a resources/list snapshot stays usable by cursors.
+const DefaultResourceSnapshotTTL = 5 * time.Minute
+
+// resourceSnapshot is an immutable, URI-sorted copy of the resource list that
+// all pages of one resources/list pagination are served from.
+type resourceSnapshot struct {
+	id        string
+	resources []schema.Resource
+	createdAt time.Time
+}
+
+// ResourceAuthzPolicy decides whether a session may read a resource with the given annotations.
+type ResourceAuthzPolicy interface {
+	Allow(session shared.ISession, uri string, annotations map[string]string) bool
+}
+
+// ResourcesOption configures a ResourcesCapability.
+type ResourcesOption func(*ResourcesCapability)
+
+// WithResourceAuthorizationPolicy sets the policy checked by resources/read.
+func WithResourceAuthorizationPolicy(policy ResourceAuthzPolicy) ResourcesOption {
+	return func(rc *ResourcesCapability) {
+		rc.authzPolicy = policy
+	}
+}
+
+// WithResourcesPageSize paginates resources/list and resources/templates/list with pageSize
+// entries per page; 0 disables pagination.
+func WithResourcesPageSize(pageSize int) ResourcesOption {
+	return func(rc *ResourcesCapability) {
+		rc.pageSize = pageSize
+	}
+}
+
+// WithResourceSnapshotTTL sets how long cursors into a resources/list snapshot stay valid.
+func WithResourceSnapshotTTL(ttl time.Duration) ResourcesOption {
+	return func(rc *ResourcesCapability) {
+		rc.snapshotTTL = ttl
+	}
+}
+
+// annotationMatchPolicy is the ResourceAuthzPolicy returned by AnnotationMatchPolicy.
+type annotationMatchPolicy struct {
+	key   string
+	value string
+}
+
+// AnnotationMatchPolicy restricts resources annotated with key=value to sessions whose
+// parameters hold the same key with the same value. Other resources stay readable by everyone.
+func AnnotationMatchPolicy(key, value string) ResourceAuthzPolicy {
+	return &annotationMatchPolicy{key: key, value: value}
+}
+
+func (p *annotationMatchPolicy) Allow(session shared.ISession, uri string, annotations map[string]string) bool {
+	if annotations[p.key] != p.value {
+		return true
+	}
+	if session == nil {
+		return false
+	}
+	sessionValue, ok := session.GetParams().Load(p.key)
+	if !ok {
+		return false
+	}
+	str, ok := sessionValue.(string)
+	return ok && str == p.value
 }
 
 // Resource represents a resource entity.
 type Resource struct {
 	schema.Resource
 	Handler      ResourceHandler
+	Chunked      ChunkedResourceHandler // Set by AddChunkedResource; streamed by resources/read
 	LastModified time.Time
 }
 
 // ResourceTemplate represents a resource template entity.
 type ResourceTemplate struct {
 	schema.ResourceTemplate
-	Handler ResourceHandler // Optional handler for templates
+	Handler ResourceTemplateHandler // Optional handler reading the URIs matching the template
+	matcher *uriTemplateMatcher     // nil if the template is not Level 1, it is listed but never read
 }
 
 // NewResourcesCapability creates a new ResourcesCapability.
-func NewResourcesCapability(manager *transport.Manager, logger *zap.Logger) *ResourcesCapability {
+func NewResourcesCapability(manager *transport.Manager, logger *zap.Logger, options ...ResourcesOption) *ResourcesCapability {
 	rc := &ResourcesCapability{
 		manager:               manager,
 		logger:                logger.Named("resources-capability"),
 		resources:             make(map[string]*Resource),
 		templates:             make(map[string]*ResourceTemplate),
-		subscribers:           make(map[string]map[string]bool),
+		subscribers:           make(map[string]map[string]shared.ISession),
 		subscribeOnSubscribes: make([]SubscriptionHandler, 0),
+		pageSize:              DefaultResourcesPageSize,
+		snapshotTTL:           DefaultResourceSnapshotTTL,
+		snapshots:             make(map[string]*resourceSnapshot),
+	}
+	for _, option := range options {
+		option(rc)
 	}
 	rc.handlers = map[string]func(*shared.Message) (interface{}, error){
 		"resources/list":           rc.handleResourcesList,
@@ -92,8 +195,36 @@ func (rc *ResourcesCapability) SetCapabilities(s *schema.ServerCapabilities) {
 	}
 }
 
-// AddResource adds a new resource.
-func (rc *ResourcesCapability) AddResource(uri string, name string, description string, mimeType string, handler ResourceHandler) error {
+// SetAuthorizationPolicy replaces the policy checked by resources/read. A nil policy allows everything.
+func (rc *ResourcesCapability) SetAuthorizationPolicy(policy ResourceAuthzPolicy) {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	rc.authzPolicy = policy
+}
+
+// SetEventBus is part of the IServerCapability interface.
+func (rc *ResourcesCapability) SetEventBus(bus shared.EventBus) {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	rc.eventBus = bus
+}
+
+// AddResource adds a new resource. Annotations are optional access control metadata.
+func (rc *ResourcesCapability) AddResource(uri string, name string, description string, mimeType string, annotations map[string]string, handler ResourceHandler) error {
+	return rc.addResource(schema.Resource{URI: uri, Name: name, Description: description, MimeType: mimeType, AccessAnnotations: annotations}, handler)
+}
+
+// AddResourceWithMetadata adds a new resource described by meta; empty metadata is omitted.
+func (rc *ResourcesCapability) AddResourceWithMetadata(uri string, name string, description string, mimeType string, meta schema.ResourceMetadata, handler ResourceHandler) error {
+	resource := schema.Resource{URI: uri, Name: name, Description: description, MimeType: mimeType}
+	if !meta.IsZero() {
+		resource.Metadata = &meta
+	}
+	return rc.addResource(resource, handler)
+}
+
+func (rc *ResourcesCapability) addResource(resource schema.Resource, handler ResourceHandler) error {
+	uri := resource.URI
 	rc.mu.Lock()
 	defer rc.mu.Unlock()
 	if _, exists := rc.resources[uri]; exists {
@@ -103,15 +234,11 @@ func (rc *ResourcesCapability) AddResource(uri string, name string, description
 		return fmt.Errorf("handler cannot be nil for resource '%s'", uri)
 	}
 	rc.resources[uri] = &Resource{
-		Resource: schema.Resource{
-			URI:         uri,
-			Name:        name,
-			Description: description,
-			MimeType:    mimeType,
-		},
+		Resource:     resource,
 		Handler:      handler,
 		LastModified: time.Now(),
 	}
+	rc.currentSnapshot = nil // New first pages must see the resource
 	rc.logger.Info("Added resource", zap.String("uri", uri))
 	go rc.broadcastResourcesListChanged() // Notify clients
 	return nil
@@ -133,7 +260,9 @@ func (rc *ResourcesCapability) UpdateResource(uri string, name string, descripti
 	resource.Description = description
 	resource.MimeType = mimeType
 	resource.Handler = handler
+	resource.Chunked = nil
 	resource.LastModified = time.Now()
+	rc.currentSnapshot = nil // New first pages must see the changes
 	rc.mu.Unlock()
 	rc.logger.Info("Updated resource", zap.String("uri", uri))
 	go rc.NotifyResourceUpdated(uri) // Notify subscribers immediately on update
@@ -149,6 +278,7 @@ func (rc *ResourcesCapability) DeleteResource(uri string) error {
 	}
 	delete(rc.resources, uri)
 	delete(rc.subscribers, uri) // Also remove subscribers
+	rc.currentSnapshot = nil    // New first pages must not list the resource
 	rc.mu.Unlock()
 	rc.logger.Info("Deleted resource", zap.String("uri", uri))
 	go rc.broadcastResourcesListChanged() // Notify clients about list change
@@ -156,12 +286,16 @@ func (rc *ResourcesCapability) DeleteResource(uri string) error {
 }
 
 // AddResourceTemplate adds a new resource template.
-func (rc *ResourcesCapability) AddResourceTemplate(uriTemplate string, name string, description string, mimeType string, handler ResourceHandler) error {
+func (rc *ResourcesCapability) AddResourceTemplate(uriTemplate string, name string, description string, mimeType string, handler ResourceTemplateHandler) error {
 	rc.mu.Lock()
 	defer rc.mu.Unlock()
 	if _, exists := rc.templates[uriTemplate]; exists {
 		return fmt.Errorf("template '%s' already exists", uriTemplate)
 	}
+	matcher, err := compileURITemplate(uriTemplate)
+	if err != nil {
+		rc.logger.Debug("Resource template will not be matched by resources/read", zap.String("uriTemplate", uriTemplate), zap.Error(err))
+	}
 	rc.templates[uriTemplate] = &ResourceTemplate{
 		ResourceTemplate: schema.ResourceTemplate{
 			URITemplate: uriTemplate,
@@ -170,7 +304,9 @@ func (rc *ResourcesCapability) AddResourceTemplate(uriTemplate string, name stri
 			MimeType:    mimeType,
 		},
 		Handler: handler, // Can be nil
+		matcher: matcher,
 	}
+	rc.templateOrder = append(rc.templateOrder, uriTemplate)
 	rc.logger.Info("Added resource template", zap.String("uriTemplate", uriTemplate))
 	// No standard notification for template list changes
 	return nil
@@ -184,6 +320,12 @@ func (rc *ResourcesCapability) DeleteResourceTemplate(uriTemplate string) error
 		return fmt.Errorf("template '%s' not found", uriTemplate)
 	}
 	delete(rc.templates, uriTemplate)
+	for i, t := range rc.templateOrder {
+		if t == uriTemplate {
+			rc.templateOrder = append(rc.templateOrder[:i], rc.templateOrder[i+1:]...)
+			break
+		}
+	}
 	rc.logger.Info("Deleted resource template", zap.String("uriTemplate", uriTemplate))
 	// No standard notification for template list changes
 	return nil
@@ -198,9 +340,13 @@ func (rc *ResourcesCapability) TriggerResourceUpdate(uri string) error {
 		return fmt.Errorf("resource '%s' not found", uri)
 	}
 	resource.LastModified = time.Now()
+	bus := rc.eventBus
 	rc.mu.Unlock()
 	rc.logger.Debug("Triggering update notification for resource", zap.String("uri", uri))
 	go rc.NotifyResourceUpdated(uri)
+	if bus != nil {
+		bus.Publish(ResourceUpdatedTopic(uri), uri)
+	}
 	return nil
 }
 
@@ -226,57 +372,147 @@ func (rc *ResourcesCapability) NotifyResourceUpdated(uri string) {
 		rc.mu.RUnlock()
 		return
 	}
-	subscriberIDs := make([]string, 0, len(subscribersMap))
-	for id := range subscribersMap {
-		subscriberIDs = append(subscriberIDs, id)
+	subscribed := make([]shared.ISession, 0, len(subscribersMap))
+	for _, session := range subscribersMap {
+		subscribed = append(subscribed, session)
 	}
 	rc.mu.RUnlock()
 
 	notificationParams := &schema.ResourceUpdatedNotificationParams{URI: uri}
-	rc.logger.Debug("Notifying subscribers about resource update", zap.String("uri", uri), zap.Int("count", len(subscriberIDs)))
+	rc.logger.Debug("Notifying subscribers about resource update", zap.String("uri", uri), zap.Int("count", len(subscribed)))
 
 	var wg sync.WaitGroup
-	for _, sessionID := range subscriberIDs {
+	for _, subscriber := range subscribed {
 		wg.Add(1)
-		go func(sID string) {
+		go func(subscriber shared.ISession) {
 			defer wg.Done()
-			s, err := rc.manager.GetSession(sID)
+			s, err := rc.manager.GetSession(subscriber.GetID())
 			if err != nil {
-				rc.logger.Warn("Failed to get session for notification, removing subscription", zap.Error(err), zap.String("uri", uri), zap.String("sessionID", sID))
-				rc.removeSubscription(s, uri)
+				rc.logger.Warn("Failed to get session for notification, removing subscription", zap.Error(err), zap.String("uri", uri), zap.String("sessionID", subscriber.GetID()))
+				rc.removeSubscription(subscriber, uri)
 				return
 			}
 			s.SendNotification("notifications/resources/updated", notificationParams.AsMap())
-		}(sessionID)
+		}(subscriber)
 	}
 	wg.Wait()
 }
 
 // handleResourcesList handles the "resources/list" request.
+// The first page takes a snapshot of the list; cursors point into that snapshot, so a
+// pagination in progress is not affected by resources added or deleted meanwhile.
 func (rc *ResourcesCapability) handleResourcesList(msg *shared.Message) (interface{}, error) {
 	logger := rc.logger.With(zap.String("sessionID", msg.Session.GetID()), zap.String("method", "resources/list"))
 	logger.Debug("Handling resources list request")
-	rc.mu.RLock()
-	defer rc.mu.RUnlock()
 	var params schema.ListResourcesRequestParams
 	if msg.Params != nil {
 		if err := json.Unmarshal(*msg.Params, &params); err != nil { /*...*/
 			return nil, shared.NewJSONRPCError(&shared.JSONRPCError{Code: shared.JSONRPCErrorInvalidParams, Message: fmt.Sprintf("Invalid parameters: %v", err)})
 		}
 	}
-	// TODO: Implement pagination
-	resourcesList := make([]schema.Resource, 0, len(rc.resources))
-	for _, r := range rc.resources {
-		resourcesList = append(resourcesList, r.Resource)
+
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	now := time.Now()
+	rc.pruneSnapshotsLocked(now)
+
+	snapshot, offset := rc.currentSnapshot, 0
+	if params.Cursor != nil && *params.Cursor != "" {
+		snapshotID, cursorOffset, err := decodeResourceCursor(*params.Cursor)
+		if err != nil {
+			return nil, &shared.JSONRPCError{Code: shared.JSONRPCErrorInvalidParams, Message: err.Error()}
+		}
+		var ok bool
+		if snapshot, ok = rc.snapshots[snapshotID]; !ok || cursorOffset > len(snapshot.resources) {
+			return nil, &shared.JSONRPCError{Code: shared.JSONRPCErrorInvalidParams, Message: "Cursor expired or unknown, restart listing without a cursor"}
+		}
+		offset = cursorOffset
+	} else if snapshot == nil {
+		snapshot = rc.takeSnapshotLocked(now)
+	}
+
+	// Cursors of a filtered listing point into the filtered snapshot, so every page must pass the
+	// same tags. Resources the authorization policy denies to the session are left out too.
+	resources := snapshot.resources
+	if len(params.Tags) > 0 || rc.authzPolicy != nil {
+		resources = make([]schema.Resource, 0, len(snapshot.resources))
+		for i := range snapshot.resources {
+			resource := &snapshot.resources[i]
+			if len(params.Tags) > 0 && !resource.HasAnyTag(params.Tags) {
+				continue
+			}
+			if rc.authzPolicy != nil && !rc.authzPolicy.Allow(msg.Session, resource.URI, resource.AccessAnnotations) {
+				continue
+			}
+			resources = append(resources, *resource)
+		}
+		if offset > len(resources) {
+			return nil, &shared.JSONRPCError{Code: shared.JSONRPCErrorInvalidParams, Message: "Cursor does not match the filter"}
+		}
+	}
+
+	end := len(resources)
+	if rc.pageSize > 0 && offset+rc.pageSize < end {
+		end = offset + rc.pageSize
 	}
 	result := schema.ListResourcesResult{
-		Resources:       resourcesList,
+		Resources:       append([]schema.Resource{}, resources[offset:end]...),
 		PaginatedResult: schema.PaginatedResult{NextCursor: nil},
+		Meta:            map[string]interface{}{"snapshotAgeMs": now.Sub(snapshot.createdAt).Milliseconds()},
 	}
-	logger.Debug("Returning resource list", zap.Int("count", len(result.Resources)))
+	if end < len(resources) {
+		result.NextCursor = shared.PointerTo(encodeResourceCursor(snapshot.id, end))
+	}
+	logger.Debug("Returning resource list", zap.Int("count", len(result.Resources)), zap.String("snapshotID", snapshot.id))
 	return result, nil
 }
 
+// takeSnapshotLocked copies the current resources, sorted by URI, and makes the copy current.
+func (rc *ResourcesCapability) takeSnapshotLocked(now time.Time) *resourceSnapshot {
+	resourcesList := make([]schema.Resource, 0, len(rc.resources))
+	for _, r := range rc.resources {
+		resourcesList = append(resourcesList, r.Resource)
+	}
+	sort.Slice(resourcesList, func(i, j int) bool { return resourcesList[i].URI < resourcesList[j].URI })
+	rc.snapshotSeq++
+	snapshot := &resourceSnapshot{id: strconv.FormatUint(rc.snapshotSeq, 10), resources: resourcesList, createdAt: now}
+	rc.snapshots[snapshot.id] = snapshot
+	rc.currentSnapshot = snapshot
+	return snapshot
+}
+
+// pruneSnapshotsLocked drops snapshots older than the TTL; their cursors become invalid.
+func (rc *ResourcesCapability) pruneSnapshotsLocked(now time.Time) {
+	for id, snapshot := range rc.snapshots {
+		if now.Sub(snapshot.createdAt) > rc.snapshotTTL {
+			delete(rc.snapshots, id)
+			if rc.currentSnapshot == snapshot {
+				rc.currentSnapshot = nil
+			}
+		}
+	}
+}
+
+func encodeResourceCursor(snapshotID string, offset int) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(snapshotID + "|" + strconv.Itoa(offset)))
+}
+
+func decodeResourceCursor(cursor string) (string, int, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return "", 0, fmt.Errorf("invalid cursor: %w", err)
+	}
+	snapshotID, offsetStr, ok := strings.Cut(string(raw), "|")
+	if !ok {
+		return "", 0, fmt.Errorf("invalid cursor")
+	}
+	offset, err := strconv.Atoi(offsetStr)
+	if err != nil || offset < 0 {
+		return "", 0, fmt.Errorf("invalid cursor")
+	}
+	return snapshotID, offset, nil
+}
+
 // handleResourcesRead handles the "resources/read" request.
 func (rc *ResourcesCapability) handleResourcesRead(msg *shared.Message) (interface{}, error) {
 	logger := rc.logger.With(zap.String("sessionID", msg.Session.GetID()), zap.String("method", "resources/read"))
@@ -291,15 +527,28 @@ func (rc *ResourcesCapability) handleResourcesRead(msg *shared.Message) (interfa
 	logger.Debug("Handling resource read request")
 	rc.mu.RLock()
 	resource, exists := rc.resources[params.URI]
+	policy := rc.authzPolicy
 	rc.mu.RUnlock()
 	if !exists {
+		if template, vars := rc.matchTemplate(params.URI); template != nil {
+			return rc.readTemplate(msg, template, vars, params.URI, policy, logger)
+		}
 		logger.Warn("Resource not found")
 		return nil, shared.NewJSONRPCError(&shared.JSONRPCError{Code: shared.JSONRPCErrorServerError, Message: fmt.Sprintf("Resource not found: %s", params.URI)})
 	} // Use ServerError range
+	if policy != nil && !policy.Allow(msg.Session, params.URI, resource.AccessAnnotations) {
+		logger.Warn("Resource read denied by authorization policy")
+		// JSON-RPC counterpart of HTTP 403
+		return nil, &shared.JSONRPCError{Code: shared.JSONRPCErrorInvalidRequest, Message: fmt.Sprintf("Forbidden: access to resource %s denied", params.URI)}
+	}
 	if resource.Handler == nil {
 		logger.Error("Handler is nil")
 		return nil, shared.NewJSONRPCError(&shared.JSONRPCError{Code: shared.JSONRPCErrorInternal, Message: fmt.Sprintf("Internal error: no handler for resource %s", params.URI)})
 	}
+	if resource.Chunked != nil {
+		logger.Debug("Streaming chunked resource")
+		return &chunkedReadResult{msg: msg, uri: resource.URI, mimeType: resource.MimeType, handler: resource.Chunked, logger: logger}, nil
+	}
 	logger.Debug("Calling resource handler")
 	meta, contents, err := resource.Handler(msg)
 	if err != nil {
@@ -311,7 +560,42 @@ func (rc *ResourcesCapability) handleResourcesRead(msg *shared.Message) (interfa
 	return result, nil
 }
 
+// matchTemplate returns the first template, in insertion order, with a handler that matches uri.
+func (rc *ResourcesCapability) matchTemplate(uri string) (*ResourceTemplate, map[string]string) {
+	rc.mu.RLock()
+	defer rc.mu.RUnlock()
+	for _, uriTemplate := range rc.templateOrder {
+		template := rc.templates[uriTemplate]
+		if template.Handler == nil || template.matcher == nil {
+			continue
+		}
+		if vars, ok := template.matcher.match(uri); ok {
+			return template, vars
+		}
+	}
+	return nil, nil
+}
+
+// readTemplate reads uri with the handler of a matching template, passing it the variables.
+func (rc *ResourcesCapability) readTemplate(msg *shared.Message, template *ResourceTemplate, vars map[string]string, uri string, policy ResourceAuthzPolicy, logger *zap.Logger) (interface{}, error) {
+	logger = logger.With(zap.String("uriTemplate", template.URITemplate))
+	if policy != nil && !policy.Allow(msg.Session, uri, nil) {
+		logger.Warn("Resource read denied by authorization policy")
+		return nil, &shared.JSONRPCError{Code: shared.JSONRPCErrorInvalidRequest, Message: fmt.Sprintf("Forbidden: access to resource %s denied", uri)}
+	}
+	logger.Debug("Calling resource template handler", zap.Any("vars", vars))
+	meta, contents, err := template.Handler(msg, vars)
+	if err != nil {
+		logger.Error("Resource template handler error", zap.Error(err))
+		return nil, shared.NewJSONRPCError(&shared.JSONRPCError{Code: shared.JSONRPCErrorServerError, Message: fmt.Sprintf("Handler failed: %v", err)})
+	}
+	result := schema.ReadResourceResult{Meta: meta, Contents: contents}
+	logger.Debug("Successfully read resource from template", zap.Int("contentParts", len(result.Contents)))
+	return result, nil
+}
+
 // handleResourceTemplatesList handles the "resources/templates/list" request.
+// Templates are listed by URI template; the cursor is the last URI template of the previous page.
 func (rc *ResourcesCapability) handleResourceTemplatesList(msg *shared.Message) (interface{}, error) {
 	logger := rc.logger.With(zap.String("sessionID", msg.Session.GetID()), zap.String("method", "resources/templates/list"))
 	logger.Debug("Handling resource templates list request")
@@ -323,15 +607,39 @@ func (rc *ResourcesCapability) handleResourceTemplatesList(msg *shared.Message)
 			return nil, shared.NewJSONRPCError(&shared.JSONRPCError{Code: shared.JSONRPCErrorInvalidParams, Message: fmt.Sprintf("Invalid parameters: %v", err)})
 		}
 	}
-	// TODO: Implement pagination
-	templatesList := make([]schema.ResourceTemplate, 0, len(rc.templates))
-	for _, t := range rc.templates {
-		templatesList = append(templatesList, t.ResourceTemplate)
+
+	uriTemplates := make([]string, 0, len(rc.templates))
+	for uriTemplate := range rc.templates {
+		uriTemplates = append(uriTemplates, uriTemplate)
+	}
+	sort.Strings(uriTemplates)
+	start := 0
+	if params.Cursor != nil && *params.Cursor != "" {
+		raw, err := base64.RawURLEncoding.DecodeString(*params.Cursor)
+		if err != nil {
+			return nil, &shared.JSONRPCError{Code: shared.JSONRPCErrorInvalidParams, Message: fmt.Sprintf("invalid cursor: %v", err)}
+		}
+		start = sort.SearchStrings(uriTemplates, string(raw))
+		if start < len(uriTemplates) && uriTemplates[start] == string(raw) {
+			start++
+		}
+	}
+	end := len(uriTemplates)
+	if rc.pageSize > 0 && start+rc.pageSize < end {
+		end = start + rc.pageSize
+	}
+
+	templatesList := make([]schema.ResourceTemplate, 0, end-start)
+	for _, uriTemplate := range uriTemplates[start:end] {
+		templatesList = append(templatesList, rc.templates[uriTemplate].ResourceTemplate)
 	}
 	result := schema.ListResourceTemplatesResult{
 		ResourceTemplates: templatesList,
 		PaginatedResult:   schema.PaginatedResult{NextCursor: nil},
 	}
+	if end < len(uriTemplates) {
+		result.NextCursor = shared.PointerTo(base64.RawURLEncoding.EncodeToString([]byte(uriTemplates[end-1])))
+	}
 	logger.Debug("Returning resource templates list", zap.Int("count", len(result.ResourceTemplates)))
 	return result, nil
 }
@@ -418,6 +726,39 @@ func (rc *ResourcesCapability) removeSubscription(session shared.ISession, uri s
 	}
 }
 
+// OnSessionClose removes the subscriptions of a closed session, notifying the subscription
+// handlers of each as an Unsubscribe. It implements transport.SessionCloseListener.
+func (rc *ResourcesCapability) OnSessionClose(sessionID string) {
+	type removed struct {
+		uri   string
+		count int
+	}
+	var session shared.ISession
+	var removedSubscriptions []removed
+	rc.mu.Lock()
+	for uri, subscribersMap := range rc.subscribers {
+		subscriber, subscribed := subscribersMap[sessionID]
+		if !subscribed {
+			continue
+		}
+		session = subscriber
+		delete(subscribersMap, sessionID)
+		if len(subscribersMap) == 0 {
+			delete(rc.subscribers, uri)
+		}
+		removedSubscriptions = append(removedSubscriptions, removed{uri: uri, count: len(subscribersMap)})
+	}
+	rc.mu.Unlock()
+
+	if len(removedSubscriptions) == 0 {
+		return
+	}
+	rc.logger.Info("Removed resource subscriptions of closed session", zap.String("sessionID", sessionID), zap.Int("count", len(removedSubscriptions)))
+	for _, subscription := range removedSubscriptions {
+		go rc.notifySubscriptionHandlers(session, Unsubscribe, subscription.uri, subscription.count)
+	}
+}
+
 // handleResourcesSubscribe handles the "resources/subscribe" request.
 func (rc *ResourcesCapability) handleResourcesSubscribe(msg *shared.Message) (interface{}, error) {
 	logger := rc.logger.With(zap.String("sessionID", msg.Session.GetID()), zap.String("method", "resources/subscribe"))
@@ -438,10 +779,11 @@ func (rc *ResourcesCapability) handleResourcesSubscribe(msg *shared.Message) (in
 		return nil, shared.NewJSONRPCError(&shared.JSONRPCError{Code: shared.JSONRPCErrorServerError, Message: fmt.Sprintf("Cannot subscribe to unknown resource: %s", params.URI)})
 	} // Use ServerError range
 	if rc.subscribers[params.URI] == nil {
-		rc.subscribers[params.URI] = make(map[string]bool)
+		rc.subscribers[params.URI] = make(map[string]shared.ISession)
 	}
-	isNewSubscription := !rc.subscribers[params.URI][msg.Session.GetID()]
-	rc.subscribers[params.URI][msg.Session.GetID()] = true
+	_, subscribed := rc.subscribers[params.URI][msg.Session.GetID()]
+	isNewSubscription := !subscribed
+	rc.subscribers[params.URI][msg.Session.GetID()] = msg.Session
 	currentCount := len(rc.subscribers[params.URI])
 	rc.mu.Unlock()
 
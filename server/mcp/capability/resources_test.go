@@ -0,0 +1,440 @@
+package capability
+
+import (
+	"encoding/json"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gate4ai/gate4ai/server/transport"
+	"github.com/gate4ai/gate4ai/shared"
+	"github.com/gate4ai/gate4ai/shared/config"
+	"github.com/gate4ai/gate4ai/shared/mcp/2025/schema"
+	sharedtesting "github.com/gate4ai/gate4ai/shared/testing"
+	"go.uber.org/zap"
+)
+
+func TestResourceAuthorizationPolicy(t *testing.T) {
+	logger := zap.NewNop()
+	manager, err := transport.NewManager(logger, config.NewInternalConfig())
+	if err != nil {
+		t.Fatalf("Failed to create manager: %v", err)
+	}
+	rc := NewResourcesCapability(manager, logger, WithResourceAuthorizationPolicy(AnnotationMatchPolicy("tier", "premium")))
+
+	handler := func(msg *shared.Message) (schema.Meta, []schema.ResourceContent, error) {
+		return nil, []schema.ResourceContent{{URI: "test://premium", Text: shared.PointerTo("secret")}}, nil
+	}
+	if err := rc.AddResource("test://premium", "Premium", "", "text/plain", map[string]string{"tier": "premium"}, handler); err != nil {
+		t.Fatalf("Failed to add resource: %v", err)
+	}
+
+	read := func(tier string) (interface{}, error) {
+		msg := sharedtesting.BuildMessage("resources/read", schema.ReadResourceRequestParams{URI: "test://premium"})
+		msg.Session.GetParams().Store("tier", tier)
+		return rc.handleResourcesRead(msg)
+	}
+
+	result, err := read("premium")
+	sharedtesting.AssertJSONRPCSuccess[schema.ReadResourceResult](t, result, err)
+
+	result, err = read("free")
+	sharedtesting.AssertJSONRPCError(t, result, err, shared.JSONRPCErrorInvalidRequest)
+
+	if err := rc.AddResource("test://public", "Public", "", "text/plain", nil, handler); err != nil {
+		t.Fatalf("Failed to add resource: %v", err)
+	}
+	list := func(tier string) []string {
+		t.Helper()
+		msg := sharedtesting.BuildMessage("resources/list", nil)
+		msg.Session.GetParams().Store("tier", tier)
+		result, err := rc.handleResourcesList(msg)
+		var listed []string
+		for _, r := range sharedtesting.AssertJSONRPCSuccess[schema.ListResourcesResult](t, result, err).Resources {
+			listed = append(listed, r.URI)
+		}
+		return listed
+	}
+	if got := list("premium"); len(got) != 2 {
+		t.Errorf("Expected both resources listed for a premium session, got %v", got)
+	}
+	if got := list("free"); len(got) != 1 || got[0] != "test://public" {
+		t.Errorf("Expected only test://public listed for a free session, got %v", got)
+	}
+
+	encoded, err := json.Marshal(rc.resources["test://premium"].Resource)
+	if err != nil {
+		t.Fatalf("Failed to encode resource: %v", err)
+	}
+	var fields map[string]interface{}
+	if err := json.Unmarshal(encoded, &fields); err != nil || len(fields) != 3 {
+		t.Errorf("Expected only uri, name and mimeType to be sent to clients, got %s", encoded)
+	}
+}
+
+func TestResourcesListPaginationUsesSnapshot(t *testing.T) {
+	logger := zap.NewNop()
+	manager, err := transport.NewManager(logger, config.NewInternalConfig())
+	if err != nil {
+		t.Fatalf("Failed to create manager: %v", err)
+	}
+	rc := NewResourcesCapability(manager, logger, WithResourcesPageSize(2))
+	handler := func(msg *shared.Message) (schema.Meta, []schema.ResourceContent, error) { return nil, nil, nil }
+	for _, uri := range []string{"test://c", "test://a", "test://b"} {
+		if err := rc.AddResource(uri, uri, "", "text/plain", nil, handler); err != nil {
+			t.Fatalf("Failed to add resource: %v", err)
+		}
+	}
+	list := func(cursor *string) schema.ListResourcesResult {
+		t.Helper()
+		result, err := rc.handleResourcesList(sharedtesting.BuildMessage("resources/list", schema.ListResourcesRequestParams{PaginatedRequestParams: schema.PaginatedRequestParams{Cursor: cursor}}))
+		return sharedtesting.AssertJSONRPCSuccess[schema.ListResourcesResult](t, result, err)
+	}
+	uris := func(result schema.ListResourcesResult) []string {
+		out := make([]string, 0, len(result.Resources))
+		for _, r := range result.Resources {
+			out = append(out, r.URI)
+		}
+		return out
+	}
+
+	first := list(nil)
+	if got := uris(first); len(got) != 2 || got[0] != "test://a" || got[1] != "test://b" {
+		t.Fatalf("Expected first page [test://a test://b], got %v", got)
+	}
+	if first.NextCursor == nil {
+		t.Fatalf("Expected a next cursor on the first page")
+	}
+	if _, ok := first.Meta["snapshotAgeMs"]; !ok {
+		t.Errorf("Expected snapshotAgeMs in result metadata, got %v", first.Meta)
+	}
+
+	// Registering resources triggers list_changed while the client is paginating
+	for _, uri := range []string{"test://a2", "test://d"} {
+		if err := rc.AddResource(uri, uri, "", "text/plain", nil, handler); err != nil {
+			t.Fatalf("Failed to add resource: %v", err)
+		}
+	}
+
+	second := list(first.NextCursor)
+	if got := uris(second); len(got) != 1 || got[0] != "test://c" {
+		t.Errorf("Expected second page from the snapshot [test://c], got %v", got)
+	}
+	if second.NextCursor != nil {
+		t.Errorf("Expected no cursor after the last snapshot page, got %q", *second.NextCursor)
+	}
+
+	var fresh []string
+	var cursor *string
+	for {
+		page := list(cursor)
+		fresh = append(fresh, uris(page)...)
+		if page.NextCursor == nil {
+			break
+		}
+		cursor = page.NextCursor
+	}
+	want := []string{"test://a", "test://a2", "test://b", "test://c", "test://d"}
+	if len(fresh) != len(want) {
+		t.Fatalf("Expected fresh listing %v, got %v", want, fresh)
+	}
+	for i := range want {
+		if fresh[i] != want[i] {
+			t.Fatalf("Expected fresh listing %v, got %v", want, fresh)
+		}
+	}
+
+	// Updates and deletions are visible to the next first page too
+	if err := rc.UpdateResource("test://a", "renamed", "", "text/plain", handler); err != nil {
+		t.Fatalf("Failed to update resource: %v", err)
+	}
+	if page := list(nil); page.Resources[0].Name != "renamed" {
+		t.Errorf("Expected the first page to show the updated name, got %q", page.Resources[0].Name)
+	}
+	if err := rc.DeleteResource("test://a"); err != nil {
+		t.Fatalf("Failed to delete resource: %v", err)
+	}
+	if got := uris(list(nil)); got[0] != "test://a2" {
+		t.Errorf("Expected the first page to start with test://a2 after the deletion, got %v", got)
+	}
+
+	result, err := rc.handleResourcesList(sharedtesting.BuildMessage("resources/list", json.RawMessage(`{"cursor":"not a cursor"}`)))
+	sharedtesting.AssertJSONRPCError(t, result, err, shared.JSONRPCErrorInvalidParams)
+}
+
+func TestResourcesListFiltersByMetadataTags(t *testing.T) {
+	logger := zap.NewNop()
+	manager, err := transport.NewManager(logger, config.NewInternalConfig())
+	if err != nil {
+		t.Fatalf("Failed to create manager: %v", err)
+	}
+	rc := NewResourcesCapability(manager, logger)
+	handler := func(msg *shared.Message) (schema.Meta, []schema.ResourceContent, error) { return nil, nil, nil }
+	resources := map[string]schema.ResourceMetadata{
+		"test://report":  {Version: "2", Tags: []string{"finance", "monthly"}},
+		"test://legacy":  {Deprecated: true, DeprecationMessage: "Use test://report", Tags: []string{"finance"}},
+		"test://weather": {Tags: []string{"public"}},
+		"test://plain":   {},
+	}
+	for uri, meta := range resources {
+		if err := rc.AddResourceWithMetadata(uri, uri, "", "text/plain", meta, handler); err != nil {
+			t.Fatalf("Failed to add resource: %v", err)
+		}
+	}
+	list := func(tags ...string) []schema.Resource {
+		t.Helper()
+		result, err := rc.handleResourcesList(sharedtesting.BuildMessage("resources/list", schema.ListResourcesRequestParams{Tags: tags}))
+		return sharedtesting.AssertJSONRPCSuccess[schema.ListResourcesResult](t, result, err).Resources
+	}
+
+	if got := list(); len(got) != 4 {
+		t.Errorf("Expected all 4 resources without a filter, got %d", len(got))
+	}
+	finance := list("finance")
+	if len(finance) != 2 || finance[0].URI != "test://legacy" || finance[1].URI != "test://report" {
+		t.Fatalf("Expected [test://legacy test://report] for tag finance, got %+v", finance)
+	}
+	if meta := finance[0].Metadata; meta == nil || !meta.Deprecated || meta.DeprecationMessage != "Use test://report" {
+		t.Errorf("Expected deprecation metadata on test://legacy, got %+v", meta)
+	}
+	if got := list("monthly", "public"); len(got) != 2 || got[0].URI != "test://report" || got[1].URI != "test://weather" {
+		t.Errorf("Expected resources with any of the tags, got %+v", got)
+	}
+	if got := list("unknown"); len(got) != 0 {
+		t.Errorf("Expected no resources for an unknown tag, got %+v", got)
+	}
+	for _, r := range list() {
+		if (r.Metadata != nil) != (r.URI != "test://plain") {
+			t.Errorf("Metadata on %s: got %+v, want it only when metadata is set", r.URI, r.Metadata)
+		}
+	}
+}
+
+func TestResourcesListDefaultPageSize(t *testing.T) {
+	logger := zap.NewNop()
+	manager, err := transport.NewManager(logger, config.NewInternalConfig())
+	if err != nil {
+		t.Fatalf("Failed to create manager: %v", err)
+	}
+	rc := NewResourcesCapability(manager, logger)
+	handler := func(msg *shared.Message) (schema.Meta, []schema.ResourceContent, error) { return nil, nil, nil }
+	total := 2*DefaultResourcesPageSize + 50
+	for i := total - 1; i >= 0; i-- {
+		uri := fmt.Sprintf("test://%04d", i)
+		if err := rc.AddResource(uri, uri, "", "text/plain", nil, handler); err != nil {
+			t.Fatalf("Failed to add resource: %v", err)
+		}
+	}
+
+	var uris []string
+	var cursor *string
+	pages := 0
+	for {
+		result, err := rc.handleResourcesList(sharedtesting.BuildMessage("resources/list", schema.ListResourcesRequestParams{PaginatedRequestParams: schema.PaginatedRequestParams{Cursor: cursor}}))
+		page := sharedtesting.AssertJSONRPCSuccess[schema.ListResourcesResult](t, result, err)
+		if len(page.Resources) > DefaultResourcesPageSize {
+			t.Fatalf("Page %d has %d resources, more than the default page size", pages, len(page.Resources))
+		}
+		for _, r := range page.Resources {
+			uris = append(uris, r.URI)
+		}
+		pages++
+		if page.NextCursor == nil {
+			break
+		}
+		cursor = page.NextCursor
+	}
+	if pages != 3 || len(uris) != total {
+		t.Fatalf("Expected %d resources on 3 pages, got %d on %d pages", total, len(uris), pages)
+	}
+	for i, uri := range uris {
+		if want := fmt.Sprintf("test://%04d", i); uri != want {
+			t.Fatalf("Expected %s at position %d, got %s", want, i, uri)
+		}
+	}
+}
+
+func TestResourceTemplatesListPagination(t *testing.T) {
+	logger := zap.NewNop()
+	manager, err := transport.NewManager(logger, config.NewInternalConfig())
+	if err != nil {
+		t.Fatalf("Failed to create manager: %v", err)
+	}
+	rc := NewResourcesCapability(manager, logger, WithResourcesPageSize(2))
+	for _, uriTemplate := range []string{"test://c/{id}", "test://a/{id}", "test://e/{id}", "test://b/{id}", "test://d/{id}"} {
+		if err := rc.AddResourceTemplate(uriTemplate, uriTemplate, "", "text/plain", nil); err != nil {
+			t.Fatalf("Failed to add resource template: %v", err)
+		}
+	}
+
+	var uriTemplates []string
+	var cursor *string
+	for {
+		result, err := rc.handleResourceTemplatesList(sharedtesting.BuildMessage("resources/templates/list", schema.ListResourceTemplatesRequestParams{PaginatedRequestParams: schema.PaginatedRequestParams{Cursor: cursor}}))
+		page := sharedtesting.AssertJSONRPCSuccess[schema.ListResourceTemplatesResult](t, result, err)
+		if len(page.ResourceTemplates) > 2 {
+			t.Fatalf("Expected at most 2 templates per page, got %d", len(page.ResourceTemplates))
+		}
+		for _, rt := range page.ResourceTemplates {
+			uriTemplates = append(uriTemplates, rt.URITemplate)
+		}
+		if page.NextCursor == nil {
+			break
+		}
+		cursor = page.NextCursor
+	}
+	want := []string{"test://a/{id}", "test://b/{id}", "test://c/{id}", "test://d/{id}", "test://e/{id}"}
+	if len(uriTemplates) != len(want) {
+		t.Fatalf("Expected templates %v, got %v", want, uriTemplates)
+	}
+	for i := range want {
+		if uriTemplates[i] != want[i] {
+			t.Fatalf("Expected templates %v, got %v", want, uriTemplates)
+		}
+	}
+
+	result, err := rc.handleResourceTemplatesList(sharedtesting.BuildMessage("resources/templates/list", json.RawMessage(`{"cursor":"not a cursor"}`)))
+	sharedtesting.AssertJSONRPCError(t, result, err, shared.JSONRPCErrorInvalidParams)
+}
+
+func TestResourcesReadMatchesTemplates(t *testing.T) {
+	logger := zap.NewNop()
+	manager, err := transport.NewManager(logger, config.NewInternalConfig())
+	if err != nil {
+		t.Fatalf("Failed to create manager: %v", err)
+	}
+	rc := NewResourcesCapability(manager, logger)
+	textHandler := func(text string) ResourceTemplateHandler {
+		return func(msg *shared.Message, vars map[string]string) (schema.Meta, []schema.ResourceContent, error) {
+			return nil, []schema.ResourceContent{{URI: "test://result", Text: shared.PointerTo(fmt.Sprintf("%s %v", text, vars))}}, nil
+		}
+	}
+	exactHandler := func(msg *shared.Message) (schema.Meta, []schema.ResourceContent, error) {
+		return textHandler("exact")(msg, nil)
+	}
+	if err := rc.AddResource("test://users/admin", "Admin", "", "text/plain", nil, exactHandler); err != nil {
+		t.Fatalf("Failed to add resource: %v", err)
+	}
+	// Both templates match test://users/{x}; the first registered wins
+	for _, tmpl := range []struct{ uriTemplate, text string }{
+		{"test://users/{id}", "user"},
+		{"test://users/{name}", "shadowed"},
+		{"test://users/{id}/posts/{post}", "post"},
+		{"test://search{?q}", "query"}, // Not Level 1, listed but never matched
+	} {
+		if err := rc.AddResourceTemplate(tmpl.uriTemplate, tmpl.uriTemplate, "", "text/plain", textHandler(tmpl.text)); err != nil {
+			t.Fatalf("Failed to add resource template: %v", err)
+		}
+	}
+
+	read := func(uri string) (interface{}, error) {
+		return rc.handleResourcesRead(sharedtesting.BuildMessage("resources/read", schema.ReadResourceRequestParams{URI: uri}))
+	}
+	for uri, want := range map[string]string{
+		"test://users/admin":           "exact map[]",
+		"test://users/42":              "user map[id:42]",
+		"test://users/j%20doe":         "user map[id:j doe]",
+		"test://users/42/posts/7":      "post map[id:42 post:7]",
+		"test://users/42/posts/7/edit": "",
+		"test://search?q=go":           "",
+	} {
+		result, err := read(uri)
+		if want == "" {
+			sharedtesting.AssertJSONRPCError(t, result, err, shared.JSONRPCErrorServerError)
+			continue
+		}
+		contents := sharedtesting.AssertJSONRPCSuccess[schema.ReadResourceResult](t, result, err).Contents
+		if len(contents) != 1 || contents[0].Text == nil || *contents[0].Text != want {
+			t.Errorf("Reading %s: expected %q, got %+v", uri, want, contents)
+		}
+	}
+
+	// Removing the first template lets the next one in insertion order match
+	if err := rc.DeleteResourceTemplate("test://users/{id}"); err != nil {
+		t.Fatalf("Failed to delete resource template: %v", err)
+	}
+	result, err := read("test://users/42")
+	contents := sharedtesting.AssertJSONRPCSuccess[schema.ReadResourceResult](t, result, err).Contents
+	if len(contents) != 1 || *contents[0].Text != "shadowed map[name:42]" {
+		t.Errorf("Expected the second template to match after deletion, got %+v", contents)
+	}
+}
+
+func TestSubscriptionCleanupOnDisconnect(t *testing.T) {
+	logger := zap.NewNop()
+	manager, err := transport.NewManager(logger, config.NewInternalConfig())
+	if err != nil {
+		t.Fatalf("Failed to create manager: %v", err)
+	}
+	rc := NewResourcesCapability(manager, logger)
+	manager.AddCapability(rc)
+	unsubscribed := make(chan string, 2)
+	rc.AddSubscriptionHandler(func(session shared.ISession, operation SubscriptionOperation, uri string, count int) {
+		if operation == Unsubscribe {
+			unsubscribed <- uri
+		}
+	})
+	for _, uri := range []string{"test://a", "test://b"} {
+		handler := func(msg *shared.Message) (schema.Meta, []schema.ResourceContent, error) { return nil, nil, nil }
+		if err := rc.AddResource(uri, uri, "", "text/plain", nil, handler); err != nil {
+			t.Fatalf("Failed to add resource: %v", err)
+		}
+	}
+
+	session := manager.CreateSession("user", "", &sync.Map{})
+	for _, uri := range []string{"test://a", "test://b"} {
+		msg := sharedtesting.BuildMessage("resources/subscribe", schema.SubscribeRequestParams{URI: uri})
+		msg.Session = session
+		if _, err := rc.handleResourcesSubscribe(msg); err != nil {
+			t.Fatalf("Failed to subscribe to %s: %v", uri, err)
+		}
+	}
+	if got := len(rc.GetSubscribedResources()); got != 2 {
+		t.Fatalf("Expected 2 subscribed resources, got %d", got)
+	}
+
+	manager.CloseSession(session.GetID())
+	rc.mu.RLock()
+	count := len(rc.subscribers)
+	rc.mu.RUnlock()
+	if count != 0 {
+		t.Errorf("Expected no subscribers after the session closed, got %d resources with subscribers", count)
+	}
+	got := map[string]bool{}
+	for range 2 {
+		select {
+		case uri := <-unsubscribed:
+			got[uri] = true
+		case <-time.After(5 * time.Second):
+			t.Fatalf("Subscription handlers were not notified of every unsubscribe, got %v", got)
+		}
+	}
+	if !got["test://a"] || !got["test://b"] {
+		t.Errorf("Expected unsubscribes of test://a and test://b, got %v", got)
+	}
+}
+
+func TestTriggerResourceUpdatePublishesEvent(t *testing.T) {
+	logger := zap.NewNop()
+	manager, err := transport.NewManager(logger, config.NewInternalConfig())
+	if err != nil {
+		t.Fatalf("Failed to create manager: %v", err)
+	}
+	rc := NewResourcesCapability(manager, logger)
+	bus := shared.NewLocalEventBus(logger)
+	rc.SetEventBus(bus)
+	handler := func(msg *shared.Message) (schema.Meta, []schema.ResourceContent, error) { return nil, nil, nil }
+	if err := rc.AddResource("test://a", "A", "", "text/plain", nil, handler); err != nil {
+		t.Fatalf("Failed to add resource: %v", err)
+	}
+	var updated []interface{}
+	bus.Subscribe(ResourceUpdatedTopic("test://a"), func(payload interface{}) { updated = append(updated, payload) })
+
+	if err := rc.TriggerResourceUpdate("test://a"); err != nil {
+		t.Fatalf("Failed to trigger update: %v", err)
+	}
+	if len(updated) != 1 || updated[0] != "test://a" {
+		t.Errorf("Expected one resource.updated event for test://a, got %v", updated)
+	}
+}
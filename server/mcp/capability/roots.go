@@ -0,0 +1,112 @@
+package capability
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/gate4ai/gate4ai/server/transport"
+	"github.com/gate4ai/gate4ai/shared"
+
+	// Use 2025 schema
+	schema "github.com/gate4ai/gate4ai/shared/mcp/2025/schema"
+	"go.uber.org/zap"
+)
+
+// RootsListChangedMethod is the notification sent to connected sessions when roots are added or removed.
+const RootsListChangedMethod = "notifications/roots/list_changed"
+
+var _ shared.IServerCapability = (*RootsCapability)(nil)
+
+// RootsCapability exposes the file system roots the server operates on.
+type RootsCapability struct {
+	manager  *transport.Manager
+	logger   *zap.Logger
+	mu       sync.RWMutex
+	roots    map[string]schema.Root                                // Map root URI -> Root
+	handlers map[string]func(*shared.Message) (interface{}, error) // Map method -> handler function
+}
+
+// NewRootsCapability creates a new RootsCapability.
+func NewRootsCapability(manager *transport.Manager, logger *zap.Logger) *RootsCapability {
+	rc := &RootsCapability{
+		manager: manager,
+		logger:  logger.Named("roots-capability"),
+		roots:   make(map[string]schema.Root),
+	}
+	rc.handlers = map[string]func(*shared.Message) (interface{}, error){
+		"roots/list": rc.handleRootsList,
+	}
+	return rc
+}
+
+func (rc *RootsCapability) GetHandlers() map[string]func(*shared.Message) (interface{}, error) {
+	return rc.handlers
+}
+
+func (rc *RootsCapability) SetCapabilities(s *schema.ServerCapabilities) {
+	s.Roots = &schema.Capability{ListChanged: true}
+}
+
+// SetEventBus is part of the IServerCapability interface; roots publish no events.
+func (rc *RootsCapability) SetEventBus(bus shared.EventBus) {}
+
+// AddRoot adds a root. The URI must start with file://.
+func (rc *RootsCapability) AddRoot(uri string, name string) error {
+	if !strings.HasPrefix(uri, "file://") {
+		return fmt.Errorf("root URI must start with file://, got '%s'", uri)
+	}
+
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	if _, exists := rc.roots[uri]; exists {
+		return fmt.Errorf("root with URI '%s' already exists", uri)
+	}
+	rc.roots[uri] = schema.Root{URI: uri, Name: name}
+
+	rc.logger.Info("Added root", zap.String("uri", uri))
+	go rc.broadcastRootsChanged()
+	return nil
+}
+
+// RemoveRoot removes a root by URI.
+func (rc *RootsCapability) RemoveRoot(uri string) error {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	if _, exists := rc.roots[uri]; !exists {
+		return fmt.Errorf("root with URI '%s' does not exist", uri)
+	}
+	delete(rc.roots, uri)
+
+	rc.logger.Info("Removed root", zap.String("uri", uri))
+	go rc.broadcastRootsChanged()
+	return nil
+}
+
+// broadcastRootsChanged sends a "notifications/roots/list_changed" notification to eligible sessions.
+func (rc *RootsCapability) broadcastRootsChanged() {
+	if rc.manager == nil {
+		rc.logger.Error("Cannot broadcast roots list changed: manager not set")
+		return
+	}
+	rc.manager.NotifyEligibleSessions(RootsListChangedMethod, nil)
+	rc.logger.Debug("Broadcasted roots list changed notification")
+}
+
+// handleRootsList handles the "roots/list" request, returning the roots ordered by URI.
+func (rc *RootsCapability) handleRootsList(msg *shared.Message) (interface{}, error) {
+	logger := rc.logger.With(zap.String("sessionID", msg.Session.GetID()), zap.String("method", "roots/list"))
+	logger.Debug("Handling roots list request")
+
+	rc.mu.RLock()
+	roots := make([]schema.Root, 0, len(rc.roots))
+	for _, root := range rc.roots {
+		roots = append(roots, root)
+	}
+	rc.mu.RUnlock()
+	sort.Slice(roots, func(i, j int) bool { return roots[i].URI < roots[j].URI })
+
+	logger.Debug("Returning roots list", zap.Int("count", len(roots)))
+	return schema.ListRootsResult{Roots: roots}, nil
+}
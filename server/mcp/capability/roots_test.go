@@ -0,0 +1,96 @@
+package capability
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gate4ai/gate4ai/server/transport"
+	"github.com/gate4ai/gate4ai/shared"
+	"github.com/gate4ai/gate4ai/shared/config"
+	"github.com/gate4ai/gate4ai/shared/mcp/2025/schema"
+	sharedtesting "github.com/gate4ai/gate4ai/shared/testing"
+	"go.uber.org/zap"
+)
+
+func TestRootsListReturnsRoots(t *testing.T) {
+	logger := zap.NewNop()
+	manager, err := transport.NewManager(logger, config.NewInternalConfig())
+	if err != nil {
+		t.Fatalf("Failed to create manager: %v", err)
+	}
+	rc := NewRootsCapability(manager, logger)
+	if err := rc.AddRoot("file:///srv/project", "project"); err != nil {
+		t.Fatalf("Failed to add root: %v", err)
+	}
+	if err := rc.AddRoot("file:///srv/data", ""); err != nil {
+		t.Fatalf("Failed to add root: %v", err)
+	}
+	if err := rc.AddRoot("https://example.com", "web"); err == nil {
+		t.Errorf("Expected an error for a non-file root URI")
+	}
+	if err := rc.AddRoot("file:///srv/data", "again"); err == nil {
+		t.Errorf("Expected an error for a duplicate root")
+	}
+
+	result, err := rc.handleRootsList(sharedtesting.BuildMessage("roots/list", nil))
+	roots := sharedtesting.AssertJSONRPCSuccess[schema.ListRootsResult](t, result, err).Roots
+	expected := []schema.Root{{URI: "file:///srv/data"}, {URI: "file:///srv/project", Name: "project"}}
+	if len(roots) != len(expected) || roots[0] != expected[0] || roots[1] != expected[1] {
+		t.Fatalf("Expected roots %+v, got %+v", expected, roots)
+	}
+
+	if err := rc.RemoveRoot("file:///srv/data"); err != nil {
+		t.Fatalf("Failed to remove root: %v", err)
+	}
+	if err := rc.RemoveRoot("file:///srv/data"); err == nil {
+		t.Errorf("Expected an error when removing a missing root")
+	}
+	result, err = rc.handleRootsList(sharedtesting.BuildMessage("roots/list", nil))
+	if roots := sharedtesting.AssertJSONRPCSuccess[schema.ListRootsResult](t, result, err).Roots; len(roots) != 1 || roots[0].URI != "file:///srv/project" {
+		t.Errorf("Expected only the project root after removal, got %+v", roots)
+	}
+
+	var caps schema.ServerCapabilities
+	rc.SetCapabilities(&caps)
+	if caps.Roots == nil || !caps.Roots.ListChanged {
+		t.Errorf("Expected roots capability with listChanged, got %+v", caps.Roots)
+	}
+}
+
+func TestRootsChangeNotifiesConnectedSessions(t *testing.T) {
+	logger := zap.NewNop()
+	manager, err := transport.NewManager(logger, config.NewInternalConfig())
+	if err != nil {
+		t.Fatalf("Failed to create manager: %v", err)
+	}
+	rc := NewRootsCapability(manager, logger)
+
+	session := manager.CreateSession("user", "roots-session", nil)
+	session.SetStatus(shared.StatusConnected)
+	output, ok := session.AcquireOutput()
+	if !ok {
+		t.Fatalf("Failed to acquire session output")
+	}
+	defer session.ReleaseOutput()
+
+	expectNotification := func(action string) {
+		t.Helper()
+		select {
+		case msg := <-output:
+			if msg.Method == nil || *msg.Method != RootsListChangedMethod {
+				t.Fatalf("Expected %s after %s, got %+v", RootsListChangedMethod, action, msg)
+			}
+		case <-time.After(2 * time.Second):
+			t.Fatalf("No %s notification after %s", RootsListChangedMethod, action)
+		}
+	}
+
+	if err := rc.AddRoot("file:///srv/project", "project"); err != nil {
+		t.Fatalf("Failed to add root: %v", err)
+	}
+	expectNotification("adding a root")
+	if err := rc.RemoveRoot("file:///srv/project"); err != nil {
+		t.Fatalf("Failed to remove root: %v", err)
+	}
+	expectNotification("removing a root")
+}
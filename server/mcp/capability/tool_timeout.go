@@ -0,0 +1,80 @@
+package capability
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/gate4ai/gate4ai/shared"
+	schema "github.com/gate4ai/gate4ai/shared/mcp/2025/schema"
+	"go.uber.org/zap"
+)
+
+// WithToolTimeout fails tool calls whose handler does not return within timeout with a
+// "Tool execution timed out" error, sent at the deadline. The context of the call (msg.Context()
+// in the handler) is cancelled then, so handlers watching it can stop; the others run on in the
+// background, but their result is dropped. A timeout <= 0 lets calls run as long as their handler.
+func WithToolTimeout(timeout time.Duration) ToolsOption {
+	return func(tc *ToolsCapability) {
+		tc.timeout = timeout
+	}
+}
+
+// errToolTimedOut is returned to clients for tool calls cancelled by WithToolTimeout.
+func errToolTimedOut(name string, timeout time.Duration) *shared.JSONRPCError {
+	return &shared.JSONRPCError{
+		Code:    shared.JSONRPCErrorServerError,
+		Message: "Tool execution timed out",
+		Data:    map[string]interface{}{"tool": name, "timeout": timeout.String()},
+	}
+}
+
+type toolCallResult struct {
+	meta    *schema.Meta
+	content []schema.Content
+	err     error
+}
+
+// callTool runs the handler of tool with the request context of msg, limited to the timeout of
+// the capability. It returns timedOut instead of a result once that timeout elapsed, without
+// waiting for the handler. A handler panic is raised again in the caller while it waits.
+func (tc *ToolsCapability) callTool(msg *shared.Message, tool *Tool, arguments schema.Arguments) (result toolCallResult, timedOut bool) {
+	if tc.timeout <= 0 {
+		meta, content, err := tool.Handler(msg, arguments)
+		return toolCallResult{meta: meta, content: content, err: err}, false
+	}
+	ctx, cancel := context.WithTimeout(msg.Context(), tc.timeout)
+	defer cancel()
+	msg.SetContext(ctx) // Not restored, the handler may still run after the deadline
+
+	done := make(chan toolCallResult, 1)
+	panicked := make(chan interface{}) // Unbuffered, so a panic is only handed over while callTool waits
+	returned := make(chan struct{})
+	defer close(returned)
+	go func() {
+		defer func() {
+			if r := recover(); r != nil {
+				select {
+				case panicked <- r:
+				case <-returned:
+					tc.logger.Error("Tool handler panicked after its call timed out", zap.String("tool", tool.Name), zap.Any("panic", r), zap.Stack("stack"))
+				}
+			}
+		}()
+		meta, content, err := tool.Handler(msg, arguments)
+		done <- toolCallResult{meta: meta, content: content, err: err}
+	}()
+
+	select {
+	case result = <-done:
+		return result, false
+	case r := <-panicked:
+		panic(r)
+	case <-ctx.Done():
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return toolCallResult{}, true
+		}
+		// The request was cancelled; the handler sees it through its context
+		return toolCallResult{err: ctx.Err()}, false
+	}
+}
@@ -1,8 +1,11 @@
 package capability
 
 import (
+	"encoding/base64"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"sort"
 	"sync"
 	"time"
 
@@ -27,16 +30,44 @@ type ToolsCapability struct {
 	mu       sync.RWMutex
 	tools    map[string]*Tool                                      // Map tool name -> Tool
 	handlers map[string]func(*shared.Message) (interface{}, error) // Map method -> handler function
+	pageSize int                                                   // tools/list page size; 0 returns everything at once
+	timeout  time.Duration                                         // Set by WithToolTimeout; 0 lets calls run until their handler returns
+	eventBus shared.EventBus                                       // Set by SetEventBus; nil publishes no events
+}
+
+// ToolCalledEvent is the payload of the events published to ToolCalledTopic.
+type ToolCalledEvent struct {
+	Name      string
+	SessionID string
+	Arguments schema.Arguments
+	Result    schema.CallToolResult
+}
+
+// ToolCalledTopic is the EventBus topic a ToolCalledEvent is published to after each
+// successful call of the tool.
+func ToolCalledTopic(name string) string {
+	return "tool.called:" + name
+}
+
+// ToolsOption configures a ToolsCapability.
+type ToolsOption func(*ToolsCapability)
+
+// WithToolsPageSize paginates tools/list with pageSize tools per page.
+func WithToolsPageSize(pageSize int) ToolsOption {
+	return func(tc *ToolsCapability) {
+		tc.pageSize = pageSize
+	}
 }
 
 // Tool represents a tool entity (using 2025 schema).
 type Tool struct {
 	schema.Tool // Embed the V2025 Tool definition (Name, Description, InputSchema, Annotations)
 	Handler     ToolHandler
+	validator   *schemaValidator // Built from InputSchema by AddTool and UpdateTool
 }
 
 // NewToolsCapability creates a new ToolsCapability.
-func NewToolsCapability(manager *transport.Manager, logger *zap.Logger) *ToolsCapability {
+func NewToolsCapability(manager *transport.Manager, logger *zap.Logger, options ...ToolsOption) *ToolsCapability {
 	tc := &ToolsCapability{
 		manager: manager,
 		logger:  logger.Named("tools-capability"),
@@ -46,6 +77,9 @@ func NewToolsCapability(manager *transport.Manager, logger *zap.Logger) *ToolsCa
 		"tools/list": tc.handleToolsList,
 		"tools/call": tc.handleToolsCall,
 	}
+	for _, option := range options {
+		option(tc)
+	}
 
 	return tc
 }
@@ -64,6 +98,13 @@ func (tc *ToolsCapability) SetCapabilities(s *schema.ServerCapabilities) {
 	}
 }
 
+// SetEventBus is part of the IServerCapability interface.
+func (tc *ToolsCapability) SetEventBus(bus shared.EventBus) {
+	tc.mu.Lock()
+	defer tc.mu.Unlock()
+	tc.eventBus = bus
+}
+
 // AddTool adds a new tool with the specified details (using 2025 schema).
 func (tc *ToolsCapability) AddTool(name string, description string, inputSchema *schema.JSONSchemaProperty, annotations *schema.ToolAnnotations, handler ToolHandler) error {
 	tc.mu.Lock()
@@ -83,7 +124,8 @@ func (tc *ToolsCapability) AddTool(name string, description string, inputSchema
 			InputSchema: inputSchema,
 			Annotations: annotations,
 		},
-		Handler: handler,
+		Handler:   handler,
+		validator: newSchemaValidator(inputSchema),
 	}
 
 	tc.logger.Info("Added tool", zap.String("name", name))
@@ -106,6 +148,7 @@ func (tc *ToolsCapability) UpdateTool(name string, description string, inputSche
 
 	tool.Description = description
 	tool.InputSchema = inputSchema
+	tool.validator = newSchemaValidator(inputSchema)
 	tool.Annotations = annotations
 	tool.Handler = handler
 
@@ -129,6 +172,18 @@ func (tc *ToolsCapability) DeleteTool(name string) error {
 	return nil
 }
 
+// ToolNames returns the names of the registered tools in alphabetical order.
+func (tc *ToolsCapability) ToolNames() []string {
+	tc.mu.RLock()
+	defer tc.mu.RUnlock()
+	names := make([]string, 0, len(tc.tools))
+	for name := range tc.tools {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
 // broadcastToolsChanged sends a "notifications/tools/list_changed" notification to eligible sessions.
 // Kept internal for potential future direct use or testing.
 func (tc *ToolsCapability) broadcastToolsChanged() {
@@ -156,11 +211,29 @@ func (tc *ToolsCapability) handleToolsList(msg *shared.Message) (interface{}, er
 			return nil, shared.NewJSONRPCError(&shared.JSONRPCError{Code: shared.JSONRPCErrorInvalidParams, Message: fmt.Sprintf("Invalid parameters: %v", err)})
 		}
 	}
-	// TODO: Implement pagination based on params.Cursor
 
-	toolsList := make([]schema.Tool, 0, len(tc.tools))
-	for _, tool := range tc.tools {
-		toolsList = append(toolsList, tool.Tool) // Add embedded V2025 Tool
+	// Tools are listed by name; the cursor is the last name of the previous page
+	names := make([]string, 0, len(tc.tools))
+	for name := range tc.tools {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	start := 0
+	if params.Cursor != nil && *params.Cursor != "" {
+		lastName, err := decodeToolCursor(*params.Cursor)
+		if err != nil {
+			return nil, &shared.JSONRPCError{Code: shared.JSONRPCErrorInvalidParams, Message: err.Error()}
+		}
+		start = sort.Search(len(names), func(i int) bool { return names[i] > lastName })
+	}
+	end := len(names)
+	if tc.pageSize > 0 && start+tc.pageSize < end {
+		end = start + tc.pageSize
+	}
+
+	toolsList := make([]schema.Tool, 0, end-start)
+	for _, name := range names[start:end] {
+		toolsList = append(toolsList, tc.tools[name].Tool) // Add embedded V2025 Tool
 	}
 
 	result := schema.ListToolsResult{
@@ -169,11 +242,22 @@ func (tc *ToolsCapability) handleToolsList(msg *shared.Message) (interface{}, er
 			NextCursor: nil,
 		},
 	}
+	if end < len(names) {
+		result.NextCursor = shared.PointerTo(base64.RawURLEncoding.EncodeToString([]byte(names[end-1])))
+	}
 
 	logger.Debug("Returning tool list", zap.Int("count", len(result.Tools)))
 	return result, nil
 }
 
+func decodeToolCursor(cursor string) (string, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return "", fmt.Errorf("invalid cursor: %w", err)
+	}
+	return string(raw), nil
+}
+
 // handleToolsCall handles the "tools/call" request from the client.
 func (tc *ToolsCapability) handleToolsCall(msg *shared.Message) (interface{}, error) {
 	logger := tc.logger.With(zap.String("sessionID", msg.Session.GetID()), zap.String("method", "tools/call"))
@@ -188,10 +272,18 @@ func (tc *ToolsCapability) handleToolsCall(msg *shared.Message) (interface{}, er
 		return nil, shared.NewJSONRPCError(&shared.JSONRPCError{Code: shared.JSONRPCErrorInvalidParams, Message: fmt.Sprintf("Invalid parameters: %v", err)})
 	}
 	logger = logger.With(zap.String("toolName", params.Name))
+	// Handlers get the token with ProgressToken(msg) to report progress via GetProgressReporter
+	if token := ProgressToken(msg); token != nil {
+		logger = logger.With(zap.Any("progressToken", token))
+	}
 	logger.Debug("Handling tool call request")
 
 	tc.mu.RLock()
 	tool, exists := tc.tools[params.Name]
+	var validator *schemaValidator
+	if exists {
+		validator = tool.validator
+	}
 	tc.mu.RUnlock()
 
 	if !exists {
@@ -200,12 +292,24 @@ func (tc *ToolsCapability) handleToolsCall(msg *shared.Message) (interface{}, er
 		return nil, shared.NewJSONRPCError(&shared.JSONRPCError{Code: shared.JSONRPCErrorMethodNotFound, Message: fmt.Sprintf("Tool not found: %s", params.Name)})
 	}
 
-	// TODO: Add validation of params.Arguments against tool.InputSchema
+	if err := validator.validateArguments(params.Arguments); errors.Is(err, errInvalidSchema) {
+		// The arguments cannot be checked, the tool's own schema is broken
+		logger.Error("Tool input schema is invalid", zap.Error(err))
+		return nil, shared.NewJSONRPCError(&shared.JSONRPCError{Code: shared.JSONRPCErrorInternal, Message: fmt.Sprintf("Invalid input schema of tool %s", params.Name)})
+	} else if err != nil {
+		logger.Warn("Tool arguments do not match the input schema", zap.Error(err))
+		return nil, shared.NewJSONRPCError(&shared.JSONRPCError{Code: shared.JSONRPCErrorInvalidParams, Message: fmt.Sprintf("Invalid arguments: %v", err)})
+	}
 
 	logger.Debug("Calling tool handler", zap.Any("arguments", params.Arguments))
 	startTime := time.Now()
-	meta, content, err := tool.Handler(msg, params.Arguments)
+	call, timedOut := tc.callTool(msg, tool, params.Arguments)
 	duration := time.Since(startTime)
+	if timedOut {
+		logger.Warn("Tool call timed out", zap.Duration("timeout", tc.timeout))
+		return nil, errToolTimedOut(params.Name, tc.timeout)
+	}
+	meta, content, err := call.meta, call.content, call.err
 
 	// Prepare V2025 result
 	result := schema.CallToolResult{
@@ -225,5 +329,16 @@ func (tc *ToolsCapability) handleToolsCall(msg *shared.Message) (interface{}, er
 	}
 
 	logger.Info("Tool call successful", zap.Duration("duration", duration))
+	tc.mu.RLock()
+	bus := tc.eventBus
+	tc.mu.RUnlock()
+	if bus != nil {
+		bus.Publish(ToolCalledTopic(params.Name), ToolCalledEvent{
+			Name:      params.Name,
+			SessionID: msg.Session.GetID(),
+			Arguments: params.Arguments,
+			Result:    result,
+		})
+	}
 	return result, nil
 }
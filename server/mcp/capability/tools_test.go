@@ -0,0 +1,426 @@
+package capability
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gate4ai/gate4ai/server/transport"
+	"github.com/gate4ai/gate4ai/shared"
+	"github.com/gate4ai/gate4ai/shared/config"
+	"github.com/gate4ai/gate4ai/shared/mcp/2025/schema"
+	sharedtesting "github.com/gate4ai/gate4ai/shared/testing"
+	"go.uber.org/zap"
+)
+
+func TestToolsListReturnsAnnotationTitle(t *testing.T) {
+	logger := zap.NewNop()
+	manager, err := transport.NewManager(logger, config.NewInternalConfig())
+	if err != nil {
+		t.Fatalf("Failed to create manager: %v", err)
+	}
+	tc := NewToolsCapability(manager, logger)
+
+	handler := func(msg *shared.Message, arguments schema.Arguments) (*schema.Meta, []schema.Content, error) {
+		return nil, nil, nil
+	}
+	if err := tc.AddTool("add", "add two numbers", nil, &schema.ToolAnnotations{Title: shared.PointerTo("Add Numbers")}, handler); err != nil {
+		t.Fatalf("Failed to add tool: %v", err)
+	}
+
+	result, err := tc.handleToolsList(sharedtesting.BuildMessage("tools/list", nil))
+	tools := sharedtesting.AssertJSONRPCSuccess[schema.ListToolsResult](t, result, err).Tools
+	if len(tools) != 1 || tools[0].Annotations == nil || tools[0].Annotations.Title == nil {
+		t.Fatalf("Expected one tool with an annotation title, got %+v", tools)
+	}
+	if *tools[0].Annotations.Title != "Add Numbers" {
+		t.Errorf("Expected title %q, got %q", "Add Numbers", *tools[0].Annotations.Title)
+	}
+}
+
+func TestToolsListPagination(t *testing.T) {
+	logger := zap.NewNop()
+	manager, err := transport.NewManager(logger, config.NewInternalConfig())
+	if err != nil {
+		t.Fatalf("Failed to create manager: %v", err)
+	}
+	tc := NewToolsCapability(manager, logger, WithToolsPageSize(20))
+	handler := func(msg *shared.Message, arguments schema.Arguments) (*schema.Meta, []schema.Content, error) {
+		return nil, nil, nil
+	}
+	for i := 0; i < 100; i++ {
+		if err := tc.AddTool(fmt.Sprintf("tool-%03d", i), "", nil, nil, handler); err != nil {
+			t.Fatalf("Failed to add tool: %v", err)
+		}
+	}
+
+	var names []string
+	var cursor *string
+	pages := 0
+	for {
+		result, err := tc.handleToolsList(sharedtesting.BuildMessage("tools/list", schema.ListToolsRequestParams{PaginatedRequestParams: schema.PaginatedRequestParams{Cursor: cursor}}))
+		page := sharedtesting.AssertJSONRPCSuccess[schema.ListToolsResult](t, result, err)
+		pages++
+		if len(page.Tools) != 20 {
+			t.Errorf("Expected 20 tools on page %d, got %d", pages, len(page.Tools))
+		}
+		for _, tool := range page.Tools {
+			names = append(names, tool.Name)
+		}
+		if page.NextCursor == nil {
+			break
+		}
+		if pages > 5 {
+			t.Fatalf("Expected pagination to end after 5 pages")
+		}
+		cursor = page.NextCursor
+	}
+	if pages != 5 {
+		t.Errorf("Expected 5 pages, got %d", pages)
+	}
+	if len(names) != 100 {
+		t.Fatalf("Expected 100 tools in total, got %d", len(names))
+	}
+	for i, name := range names {
+		if want := fmt.Sprintf("tool-%03d", i); name != want {
+			t.Fatalf("Expected tool %q at position %d, got %q", want, i, name)
+		}
+	}
+
+	result, err := tc.handleToolsList(sharedtesting.BuildMessage("tools/list", schema.ListToolsRequestParams{PaginatedRequestParams: schema.PaginatedRequestParams{Cursor: shared.PointerTo("not base64!")}}))
+	sharedtesting.AssertJSONRPCError(t, result, err, shared.JSONRPCErrorInvalidParams)
+}
+
+func TestProgressToolReportsOnSession(t *testing.T) {
+	logger := zap.NewNop()
+	manager, err := transport.NewManager(logger, config.NewInternalConfig())
+	if err != nil {
+		t.Fatalf("Failed to create manager: %v", err)
+	}
+	tc := NewToolsCapability(manager, logger)
+	handler := func(msg *shared.Message, arguments schema.Arguments, progress ProgressReporter) (*schema.Meta, []schema.Content, error) {
+		for step := 1; step <= 3; step++ {
+			if err := progress.Report(context.Background(), float64(step), 3, fmt.Sprintf("step %d", step)); err != nil {
+				return nil, nil, err
+			}
+		}
+		return nil, nil, nil
+	}
+	if err := tc.AddProgressTool("index", "index documents", nil, nil, handler); err != nil {
+		t.Fatalf("Failed to add tool: %v", err)
+	}
+
+	msg := sharedtesting.BuildMessage("tools/call", json.RawMessage(`{"name": "index", "arguments": {}, "_meta": {"progressToken": "index-1"}}`))
+	result, err := tc.handleToolsCall(msg)
+	if callResult := sharedtesting.AssertJSONRPCSuccess[schema.CallToolResult](t, result, err); callResult.IsError {
+		t.Fatalf("Expected a successful tool call, got %+v", callResult)
+	}
+
+	sent := msg.Session.(*sharedtesting.MockSession).Sent()
+	if len(sent) != 3 {
+		t.Fatalf("Expected 3 progress notifications, got %d", len(sent))
+	}
+	for i, notification := range sent {
+		if notification.Method == nil || *notification.Method != ProgressNotificationMethod {
+			t.Fatalf("Expected %s, got %+v", ProgressNotificationMethod, notification)
+		}
+		var params struct {
+			ProgressToken string `json:"progressToken"`
+			Progress      int    `json:"progress"`
+			Total         int    `json:"total"`
+			Message       string `json:"message"`
+		}
+		if err := json.Unmarshal(*notification.Params, &params); err != nil {
+			t.Fatalf("Failed to decode progress params: %v", err)
+		}
+		if params.ProgressToken != "index-1" || params.Progress != i+1 || params.Total != 3 || params.Message != fmt.Sprintf("step %d", i+1) {
+			t.Errorf("Unexpected progress notification %d: %+v", i, params)
+		}
+	}
+}
+
+func TestProgressReporterNeedsToken(t *testing.T) {
+	msg := sharedtesting.BuildMessage("tools/call", json.RawMessage(`{"name": "index", "arguments": {}}`))
+	if token := ProgressToken(msg); token != nil {
+		t.Fatalf("Expected no progress token, got %v", token)
+	}
+	if err := GetProgressReporter(msg, nil).Report(context.Background(), 1, 2, ""); err != nil {
+		t.Fatalf("Report without token failed: %v", err)
+	}
+	if sent := msg.Session.(*sharedtesting.MockSession).Sent(); len(sent) != 0 {
+		t.Errorf("Expected no notifications without a progress token, got %d", len(sent))
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := GetProgressReporter(msg, "index-1").Report(ctx, 1, 2, ""); err == nil {
+		t.Errorf("Expected an error when reporting on a cancelled context")
+	}
+}
+
+func TestToolsCallValidatesArguments(t *testing.T) {
+	logger := zap.NewNop()
+	manager, err := transport.NewManager(logger, config.NewInternalConfig())
+	if err != nil {
+		t.Fatalf("Failed to create manager: %v", err)
+	}
+	tc := NewToolsCapability(manager, logger)
+	inputSchema := &schema.JSONSchemaProperty{
+		Type: "object",
+		Properties: map[string]schema.JSONSchemaProperty{
+			"query": {Type: "string"},
+			"limit": {Type: "integer"},
+			"tags":  {Type: "array", Items: &schema.JSONSchemaProperty{Type: "string"}},
+		},
+		Required:             []string{"query"},
+		AdditionalProperties: false,
+	}
+	calls := 0
+	handler := func(msg *shared.Message, arguments schema.Arguments) (*schema.Meta, []schema.Content, error) {
+		calls++
+		return nil, nil, nil
+	}
+	if err := tc.AddTool("search", "search documents", inputSchema, nil, handler); err != nil {
+		t.Fatalf("Failed to add tool: %v", err)
+	}
+
+	tests := []struct {
+		name      string
+		arguments string
+		wantError string // Empty for valid arguments
+	}{
+		{"valid", `{"query": "go", "limit": 10, "tags": ["docs"]}`, ""},
+		{"required field missing", `{"limit": 10}`, "arguments: missing required property 'query'"},
+		{"wrong type", `{"query": "go", "limit": "ten"}`, "arguments.limit: expected integer, got string"},
+		{"fractional integer", `{"query": "go", "limit": 1.5}`, "arguments.limit: expected integer, got number"},
+		{"wrong item type", `{"query": "go", "tags": ["docs", 7]}`, "arguments.tags[1]: expected string, got integer"},
+		{"additional property", `{"query": "go", "offset": 5}`, "arguments: unexpected property 'offset'"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calls = 0
+			msg := sharedtesting.BuildMessage("tools/call", json.RawMessage(`{"name": "search", "arguments": `+tt.arguments+`}`))
+			result, err := tc.handleToolsCall(msg)
+			if tt.wantError == "" {
+				sharedtesting.AssertJSONRPCSuccess[schema.CallToolResult](t, result, err)
+				if calls != 1 {
+					t.Errorf("Expected the handler to be called once, got %d", calls)
+				}
+				return
+			}
+			sharedtesting.AssertJSONRPCError(t, result, err, shared.JSONRPCErrorInvalidParams)
+			if !strings.Contains(err.Error(), tt.wantError) {
+				t.Errorf("Expected error containing %q, got %q", tt.wantError, err.Error())
+			}
+			if calls != 0 {
+				t.Errorf("Handler called with invalid arguments")
+			}
+		})
+	}
+}
+
+func TestToolsCallSchemaReferencesAndPatterns(t *testing.T) {
+	logger := zap.NewNop()
+	manager, err := transport.NewManager(logger, config.NewInternalConfig())
+	if err != nil {
+		t.Fatalf("Failed to create manager: %v", err)
+	}
+	tc := NewToolsCapability(manager, logger)
+	handler := func(msg *shared.Message, arguments schema.Arguments) (*schema.Meta, []schema.Content, error) {
+		return nil, nil, nil
+	}
+	tools := map[string]*schema.JSONSchemaProperty{
+		// A tree is a recursive schema that ends with the value
+		"tree": {
+			Ref: "#/definitions/node",
+			Definitions: map[string]schema.JSONSchemaProperty{
+				"node": {
+					Type: "object",
+					Properties: map[string]schema.JSONSchemaProperty{
+						"name":     {Type: "string", Pattern: "^[a-z]+$"},
+						"children": {Type: "array", Items: &schema.JSONSchemaProperty{Ref: "#/definitions/node"}},
+					},
+				},
+			},
+		},
+		"loop": {
+			Ref: "#/definitions/a",
+			Definitions: map[string]schema.JSONSchemaProperty{
+				"a": {AnyOf: []schema.JSONSchemaProperty{{Ref: "#/definitions/b"}}},
+				"b": {Ref: "#/definitions/a"},
+			},
+		},
+		"bad-pattern": {
+			Type:       "object",
+			Properties: map[string]schema.JSONSchemaProperty{"name": {Type: "string", Pattern: "(unclosed"}},
+		},
+	}
+	for name, inputSchema := range tools {
+		if err := tc.AddTool(name, "", inputSchema, nil, handler); err != nil {
+			t.Fatalf("Failed to add tool: %v", err)
+		}
+	}
+
+	tests := []struct {
+		name      string
+		tool      string
+		arguments string
+		wantCode  int // 0 for valid arguments
+	}{
+		{"recursive schema", "tree", `{"name": "root", "children": [{"name": "leaf", "children": []}]}`, 0},
+		{"pattern mismatch in nested value", "tree", `{"name": "root", "children": [{"name": "Leaf"}]}`, shared.JSONRPCErrorInvalidParams},
+		{"circular reference", "loop", `{}`, shared.JSONRPCErrorInternal},
+		{"invalid pattern", "bad-pattern", `{"name": "x"}`, shared.JSONRPCErrorInternal},
+		{"invalid pattern without the property", "bad-pattern", `{}`, shared.JSONRPCErrorInternal},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			msg := sharedtesting.BuildMessage("tools/call", json.RawMessage(`{"name": "`+tt.tool+`", "arguments": `+tt.arguments+`}`))
+			result, err := tc.handleToolsCall(msg)
+			if tt.wantCode == 0 {
+				sharedtesting.AssertJSONRPCSuccess[schema.CallToolResult](t, result, err)
+				return
+			}
+			sharedtesting.AssertJSONRPCError(t, result, err, tt.wantCode)
+		})
+	}
+}
+
+func TestToolCallTimeout(t *testing.T) {
+	logger := zap.NewNop()
+	manager, err := transport.NewManager(logger, config.NewInternalConfig())
+	if err != nil {
+		t.Fatalf("Failed to create manager: %v", err)
+	}
+	tc := NewToolsCapability(manager, logger, WithToolTimeout(50*time.Millisecond))
+	stopped := make(chan error, 1)
+	slow := func(msg *shared.Message, arguments schema.Arguments) (*schema.Meta, []schema.Content, error) {
+		ctx := msg.Context()
+		select {
+		case <-ctx.Done():
+			stopped <- ctx.Err()
+			return nil, nil, ctx.Err()
+		case <-time.After(5 * time.Second):
+			stopped <- nil
+			return nil, schema.NewTextContent("too late"), nil
+		}
+	}
+	fast := func(msg *shared.Message, arguments schema.Arguments) (*schema.Meta, []schema.Content, error) {
+		return nil, schema.NewTextContent("done"), nil
+	}
+	if err := tc.AddTool("slow", "", nil, nil, slow); err != nil {
+		t.Fatalf("Failed to add tool: %v", err)
+	}
+	if err := tc.AddTool("fast", "", nil, nil, fast); err != nil {
+		t.Fatalf("Failed to add tool: %v", err)
+	}
+
+	start := time.Now()
+	msg := sharedtesting.BuildMessage("tools/call", schema.CallToolRequestParams{Name: "slow"})
+	result, err := tc.handleToolsCall(msg)
+	sharedtesting.AssertJSONRPCError(t, result, err, shared.JSONRPCErrorServerError)
+	if !strings.Contains(err.Error(), "Tool execution timed out") {
+		t.Errorf("Expected a timeout error, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("Expected the call to fail at the timeout, took %s", elapsed)
+	}
+	select {
+	case stopErr := <-stopped:
+		if !errors.Is(stopErr, context.DeadlineExceeded) {
+			t.Errorf("Expected the handler context to be cancelled at the deadline, got %v", stopErr)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Slow handler was not stopped")
+	}
+
+	result, err = tc.handleToolsCall(sharedtesting.BuildMessage("tools/call", schema.CallToolRequestParams{Name: "fast"}))
+	content := sharedtesting.AssertJSONRPCSuccess[schema.CallToolResult](t, result, err).Content
+	if len(content) != 1 || *content[0].Text != "done" {
+		t.Errorf("Expected the fast tool to answer within the timeout, got %+v", content)
+	}
+}
+
+func TestToolCallTimeoutDoesNotWaitForTheHandler(t *testing.T) {
+	logger := zap.NewNop()
+	manager, err := transport.NewManager(logger, config.NewInternalConfig())
+	if err != nil {
+		t.Fatalf("Failed to create manager: %v", err)
+	}
+	tc := NewToolsCapability(manager, logger, WithToolTimeout(50*time.Millisecond))
+	release := make(chan struct{})
+	defer close(release)
+	stuck := func(msg *shared.Message, arguments schema.Arguments) (*schema.Meta, []schema.Content, error) {
+		<-release // Ignores the context of the call
+		return nil, schema.NewTextContent("too late"), nil
+	}
+	if err := tc.AddTool("stuck", "", nil, nil, stuck); err != nil {
+		t.Fatalf("Failed to add tool: %v", err)
+	}
+
+	start := time.Now()
+	result, err := tc.handleToolsCall(sharedtesting.BuildMessage("tools/call", schema.CallToolRequestParams{Name: "stuck"}))
+	elapsed := time.Since(start)
+	sharedtesting.AssertJSONRPCError(t, result, err, shared.JSONRPCErrorServerError)
+	if !strings.Contains(err.Error(), "Tool execution timed out") {
+		t.Errorf("Expected a timeout error, got %v", err)
+	}
+	if elapsed < 50*time.Millisecond || elapsed > 500*time.Millisecond {
+		t.Errorf("Expected the timeout error at the deadline, got it after %s", elapsed)
+	}
+}
+
+func TestToolCallContextFollowsTheRequest(t *testing.T) {
+	logger := zap.NewNop()
+	manager, err := transport.NewManager(logger, config.NewInternalConfig())
+	if err != nil {
+		t.Fatalf("Failed to create manager: %v", err)
+	}
+	tc := NewToolsCapability(manager, logger, WithToolTimeout(5*time.Second))
+	started := make(chan struct{})
+	stopped := make(chan error, 1)
+	wait := func(msg *shared.Message, arguments schema.Arguments) (*schema.Meta, []schema.Content, error) {
+		close(started)
+		<-msg.Context().Done()
+		stopped <- msg.Context().Err()
+		return nil, nil, msg.Context().Err()
+	}
+	panics := func(msg *shared.Message, arguments schema.Arguments) (*schema.Meta, []schema.Content, error) {
+		panic("broken tool")
+	}
+	if err := tc.AddTool("wait", "", nil, nil, wait); err != nil {
+		t.Fatalf("Failed to add tool: %v", err)
+	}
+	if err := tc.AddTool("panics", "", nil, nil, panics); err != nil {
+		t.Fatalf("Failed to add tool: %v", err)
+	}
+
+	// Cancelling the request stops the handler, which is not a timeout
+	requestCtx, cancel := context.WithCancel(context.Background())
+	msg := sharedtesting.BuildMessage("tools/call", schema.CallToolRequestParams{Name: "wait"})
+	msg.SetContext(requestCtx)
+	go func() {
+		<-started
+		cancel()
+	}()
+	result, err := tc.handleToolsCall(msg)
+	if !sharedtesting.AssertJSONRPCSuccess[schema.CallToolResult](t, result, err).IsError {
+		t.Errorf("Expected a tool error for the cancelled request")
+	}
+	if stopErr := <-stopped; !errors.Is(stopErr, context.Canceled) {
+		t.Errorf("Expected the handler context to be cancelled with the request, got %v", stopErr)
+	}
+
+	// Panics are not turned into tool errors
+	defer func() {
+		if r := recover(); r != "broken tool" {
+			t.Errorf("Expected the handler panic to surface, got %v", r)
+		}
+	}()
+	_, _ = tc.handleToolsCall(sharedtesting.BuildMessage("tools/call", schema.CallToolRequestParams{Name: "panics"}))
+	t.Errorf("Expected the tool call to panic")
+}
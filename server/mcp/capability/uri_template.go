@@ -0,0 +1,76 @@
+package capability
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// uriTemplateMatcher matches URIs against an RFC 6570 Level 1 template ("test://items/{id}").
+// A variable matches one path segment, so it never spans a '/'.
+type uriTemplateMatcher struct {
+	pattern *regexp.Regexp
+	names   []string
+}
+
+var uriTemplateVarName = regexp.MustCompile(`^[A-Za-z0-9_]+$`)
+
+// compileURITemplate builds the matcher of a Level 1 template. Templates with operators
+// ({+var}, {?query}, ...) or malformed expressions return an error.
+func compileURITemplate(template string) (*uriTemplateMatcher, error) {
+	var pattern strings.Builder
+	var names []string
+	pattern.WriteString("^")
+	rest := template
+	for {
+		open := strings.IndexByte(rest, '{')
+		if open < 0 {
+			if strings.IndexByte(rest, '}') >= 0 {
+				return nil, fmt.Errorf("unbalanced '}' in URI template '%s'", template)
+			}
+			pattern.WriteString(regexp.QuoteMeta(rest))
+			break
+		}
+		closing := strings.IndexByte(rest[open:], '}')
+		if closing < 0 {
+			return nil, fmt.Errorf("unclosed '{' in URI template '%s'", template)
+		}
+		literal := rest[:open]
+		if strings.IndexByte(literal, '}') >= 0 {
+			return nil, fmt.Errorf("unbalanced '}' in URI template '%s'", template)
+		}
+		name := rest[open+1 : open+closing]
+		if !uriTemplateVarName.MatchString(name) {
+			return nil, fmt.Errorf("unsupported expression '{%s}' in URI template '%s', only Level 1 variables are matched", name, template)
+		}
+		pattern.WriteString(regexp.QuoteMeta(literal))
+		pattern.WriteString("([^/]*)")
+		names = append(names, name)
+		rest = rest[open+closing+1:]
+	}
+	pattern.WriteString("$")
+
+	compiled, err := regexp.Compile(pattern.String())
+	if err != nil {
+		return nil, fmt.Errorf("invalid URI template '%s': %w", template, err)
+	}
+	return &uriTemplateMatcher{pattern: compiled, names: names}, nil
+}
+
+// match returns the decoded variables of uri, or false if uri does not match the template.
+func (m *uriTemplateMatcher) match(uri string) (map[string]string, bool) {
+	groups := m.pattern.FindStringSubmatch(uri)
+	if groups == nil {
+		return nil, false
+	}
+	vars := make(map[string]string, len(m.names))
+	for i, name := range m.names {
+		value, err := url.PathUnescape(groups[i+1])
+		if err != nil {
+			return nil, false
+		}
+		vars[name] = value
+	}
+	return vars, true
+}
@@ -29,6 +29,7 @@ func NewMethodValidator() *MethodValidator {
 			"resources/unsubscribe":    true,
 			"prompts/get":              true,
 			"tools/call":               true,
+			"roots/list":               true,
 			// A2A Methods
 			"tasks/send":          true,
 			"tasks/sendSubscribe": true,
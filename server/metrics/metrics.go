@@ -0,0 +1,126 @@
+// Package metrics exports Prometheus metrics of gate4ai servers and gateways. It is only
+// linked into binaries that enable a metrics endpoint.
+package metrics
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gate4ai/gate4ai/server/transport"
+	"github.com/gate4ai/gate4ai/shared"
+	a2aSchema "github.com/gate4ai/gate4ai/shared/a2a/2025-draft/schema"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// Names of the exported metrics.
+const (
+	RequestsTotal             = "gate4ai_requests_total"
+	RequestDurationSeconds    = "gate4ai_request_duration_seconds"
+	ActiveSessions            = "gate4ai_active_sessions"
+	BackendErrorsTotal        = "gate4ai_backend_errors_total"
+	TaskStateTransitionsTotal = "gate4ai_task_state_transitions_total"
+)
+
+// Request statuses recorded by ObserveRequest.
+const (
+	StatusOK        = "ok"
+	StatusError     = "error"
+	StatusToolError = "tool_error" // The backend handled the call but reported a tool error
+)
+
+// noState is the from_state label of a task's first transition.
+const noState = "none"
+
+var _ transport.SessionObserver = (*Metrics)(nil)
+
+// Metrics holds the collectors in a registry of its own, so several servers in one process
+// do not conflict.
+type Metrics struct {
+	registry             *prometheus.Registry
+	requests             *prometheus.CounterVec
+	requestDuration      *prometheus.HistogramVec
+	activeSessions       prometheus.Gauge
+	backendErrors        *prometheus.CounterVec
+	taskStateTransitions *prometheus.CounterVec
+}
+
+// New creates the collectors and registers them, along with the Go runtime and process
+// collectors, in a new registry.
+func New() *Metrics {
+	m := &Metrics{
+		registry: prometheus.NewRegistry(),
+		requests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: RequestsTotal,
+			Help: "JSON-RPC requests handled, by method and status.",
+		}, []string{"method", "status"}),
+		requestDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    RequestDurationSeconds,
+			Help:    "Duration of JSON-RPC requests, by method.",
+			Buckets: prometheus.DefBuckets,
+		}, []string{"method"}),
+		activeSessions: prometheus.NewGauge(prometheus.GaugeOpts{
+			Name: ActiveSessions,
+			Help: "Sessions currently held by the session manager.",
+		}),
+		backendErrors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: BackendErrorsTotal,
+			Help: "Failed calls to backend servers, by server and error type.",
+		}, []string{"server_slug", "error_type"}),
+		taskStateTransitions: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: TaskStateTransitionsTotal,
+			Help: "A2A task state changes; from_state is \"none\" for new tasks.",
+		}, []string{"from_state", "to_state"}),
+	}
+	m.registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		m.requests,
+		m.requestDuration,
+		m.activeSessions,
+		m.backendErrors,
+		m.taskStateTransitions,
+	)
+	return m
+}
+
+// Handler serves the metrics in the Prometheus text format.
+func (m *Metrics) Handler() http.Handler {
+	return promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{Registry: m.registry})
+}
+
+// Registry returns the registry the collectors are registered in, to add custom collectors.
+func (m *Metrics) Registry() *prometheus.Registry {
+	return m.registry
+}
+
+// ObserveRequest counts a handled request and records its duration.
+func (m *Metrics) ObserveRequest(method string, status string, duration time.Duration) {
+	m.requests.WithLabelValues(method, status).Inc()
+	m.requestDuration.WithLabelValues(method).Observe(duration.Seconds())
+}
+
+// BackendError counts a failed call to the backend server serverSlug.
+func (m *Metrics) BackendError(serverSlug string, errorType string) {
+	m.backendErrors.WithLabelValues(serverSlug, errorType).Inc()
+}
+
+// SessionCreated implements transport.SessionObserver.
+func (m *Metrics) SessionCreated(session shared.ISession) {
+	m.activeSessions.Inc()
+}
+
+// SessionClosed implements transport.SessionObserver.
+func (m *Metrics) SessionClosed(session shared.ISession) {
+	m.activeSessions.Dec()
+}
+
+// TaskStateTransition counts a task state change. Pass it to a2a.WithStateTransitionObserver.
+func (m *Metrics) TaskStateTransition(taskID string, from a2aSchema.TaskState, to a2aSchema.TaskState) {
+	fromLabel := string(from)
+	if fromLabel == "" {
+		fromLabel = noState
+	}
+	m.taskStateTransitions.WithLabelValues(fromLabel, string(to)).Inc()
+}
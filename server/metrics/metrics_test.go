@@ -0,0 +1,94 @@
+package metrics_test
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gate4ai/gate4ai/server/metrics"
+	"github.com/gate4ai/gate4ai/server/transport"
+	a2aSchema "github.com/gate4ai/gate4ai/shared/a2a/2025-draft/schema"
+	"github.com/gate4ai/gate4ai/shared/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// scrape returns the metrics served by the handler in the Prometheus text format.
+func scrape(t *testing.T, m *metrics.Metrics) string {
+	t.Helper()
+	server := httptest.NewServer(m.Handler())
+	defer server.Close()
+	resp, err := http.Get(server.URL)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	return string(body)
+}
+
+func TestMetricsExport(t *testing.T) {
+	m := metrics.New()
+	m.ObserveRequest("tools/call", metrics.StatusOK, 20*time.Millisecond)
+	m.ObserveRequest("tools/call", metrics.StatusOK, 30*time.Millisecond)
+	m.ObserveRequest("tools/call", metrics.StatusError, time.Millisecond)
+	m.BackendError("weather", "call")
+	m.TaskStateTransition("task-1", "", a2aSchema.TaskStateSubmitted)
+	m.TaskStateTransition("task-1", a2aSchema.TaskStateSubmitted, a2aSchema.TaskStateCompleted)
+
+	body := scrape(t, m)
+	assert.Contains(t, body, `gate4ai_requests_total{method="tools/call",status="ok"} 2`)
+	assert.Contains(t, body, `gate4ai_requests_total{method="tools/call",status="error"} 1`)
+	assert.Contains(t, body, `gate4ai_request_duration_seconds_count{method="tools/call"} 3`)
+	assert.Contains(t, body, `gate4ai_backend_errors_total{error_type="call",server_slug="weather"} 1`)
+	assert.Contains(t, body, `gate4ai_task_state_transitions_total{from_state="none",to_state="submitted"} 1`)
+	assert.Contains(t, body, `gate4ai_task_state_transitions_total{from_state="submitted",to_state="completed"} 1`)
+	assert.Contains(t, body, "go_goroutines")
+}
+
+func TestMetricsSummary(t *testing.T) {
+	m := metrics.New()
+	m.ObserveRequest("tools/call", metrics.StatusOK, time.Millisecond)
+	m.ObserveRequest("tools/call", metrics.StatusOK, time.Millisecond)
+	m.ObserveRequest("tools/call", metrics.StatusError, time.Millisecond)
+	m.ObserveRequest("tools/call", metrics.StatusToolError, time.Millisecond)
+	m.ObserveRequest("tools/list", metrics.StatusOK, time.Millisecond)
+	m.BackendError("weather", "call")
+	m.BackendError("weather", "connect")
+	m.TaskStateTransition("task-1", "", a2aSchema.TaskStateSubmitted)
+	m.TaskStateTransition("task-2", "", a2aSchema.TaskStateSubmitted)
+	m.TaskStateTransition("task-1", a2aSchema.TaskStateSubmitted, a2aSchema.TaskStateCompleted)
+
+	summary, err := m.Summary()
+	require.NoError(t, err)
+	assert.Equal(t, metrics.RequestSummary{Total: 4, Errors: 2, ErrorRate: 0.5}, summary.Requests["tools/call"])
+	assert.Equal(t, metrics.RequestSummary{Total: 1}, summary.Requests["tools/list"])
+	assert.Equal(t, map[string]int{"weather": 2}, summary.BackendErrors)
+	assert.Equal(t, map[string]int{"submitted": 2, "completed": 1}, summary.Tasks)
+	assert.Zero(t, summary.ActiveSessions)
+}
+
+func TestMetricsCountActiveSessions(t *testing.T) {
+	m := metrics.New()
+	manager, err := transport.NewManager(zap.NewNop(), config.NewInternalConfig())
+	require.NoError(t, err)
+	manager.AddSessionObserver(m)
+
+	first := manager.CreateSession("user", "", nil)
+	second := manager.CreateSession("user", "", nil)
+	assert.Contains(t, scrape(t, m), "gate4ai_active_sessions 2")
+
+	manager.CloseSession(first.GetID())
+	manager.RemoveSession(second.GetID())
+	manager.CloseSession("unknown-session")
+	assert.Contains(t, scrape(t, m), "gate4ai_active_sessions 0")
+}
+
+func TestMetricsRegistriesAreIndependent(t *testing.T) {
+	first, second := metrics.New(), metrics.New()
+	first.BackendError("weather", "call")
+	assert.NotContains(t, scrape(t, second), "gate4ai_backend_errors_total{")
+}
@@ -0,0 +1,73 @@
+package metrics
+
+import (
+	"fmt"
+
+	dto "github.com/prometheus/client_model/go"
+)
+
+// Summary is a digest of the collected counters for consumers that do not read the
+// Prometheus format, such as admin APIs.
+type Summary struct {
+	ActiveSessions int                       `json:"activeSessions"`
+	Requests       map[string]RequestSummary `json:"requests"`      // By method
+	BackendErrors  map[string]int            `json:"backendErrors"` // By server slug
+	Tasks          map[string]int            `json:"tasks"`         // Tasks that entered each state
+}
+
+// RequestSummary counts the requests of one method.
+type RequestSummary struct {
+	Total     int     `json:"total"`
+	Errors    int     `json:"errors"`    // StatusError and StatusToolError
+	ErrorRate float64 `json:"errorRate"` // Errors / Total
+}
+
+// Summary reads the current values of the collectors.
+func (m *Metrics) Summary() (Summary, error) {
+	families, err := m.registry.Gather()
+	if err != nil {
+		return Summary{}, fmt.Errorf("failed to gather metrics: %w", err)
+	}
+	summary := Summary{
+		Requests:      make(map[string]RequestSummary),
+		BackendErrors: make(map[string]int),
+		Tasks:         make(map[string]int),
+	}
+	for _, family := range families {
+		for _, metric := range family.GetMetric() {
+			switch family.GetName() {
+			case ActiveSessions:
+				summary.ActiveSessions = int(metric.GetGauge().GetValue())
+			case RequestsTotal:
+				method := labelValue(metric, "method")
+				requests := summary.Requests[method]
+				count := int(metric.GetCounter().GetValue())
+				requests.Total += count
+				if status := labelValue(metric, "status"); status == StatusError || status == StatusToolError {
+					requests.Errors += count
+				}
+				summary.Requests[method] = requests
+			case BackendErrorsTotal:
+				summary.BackendErrors[labelValue(metric, "server_slug")] += int(metric.GetCounter().GetValue())
+			case TaskStateTransitionsTotal:
+				summary.Tasks[labelValue(metric, "to_state")] += int(metric.GetCounter().GetValue())
+			}
+		}
+	}
+	for method, requests := range summary.Requests {
+		if requests.Total > 0 {
+			requests.ErrorRate = float64(requests.Errors) / float64(requests.Total)
+			summary.Requests[method] = requests
+		}
+	}
+	return summary, nil
+}
+
+func labelValue(metric *dto.Metric, name string) string {
+	for _, label := range metric.GetLabel() {
+		if label.GetName() == name {
+			return label.GetValue()
+		}
+	}
+	return ""
+}
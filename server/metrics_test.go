@@ -0,0 +1,37 @@
+package server_test
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/gate4ai/gate4ai/server"
+	"github.com/gate4ai/gate4ai/shared/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestMetricsEndpoint(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	port := freePort(t)
+	_, err := server.Start(ctx, zap.NewNop(), config.NewInternalConfig(),
+		server.WithListenAddr(fmt.Sprintf(":%d", port)),
+		server.WithMetricsEndpoint("/internal/metrics"),
+	)
+	require.NoError(t, err)
+	waitForPort(t, port)
+
+	resp := adminRequest(t, http.MethodGet, fmt.Sprintf("http://localhost:%d/internal/metrics", port), "", nil)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	assert.Contains(t, string(body), "gate4ai_active_sessions 0")
+
+	_, err = server.Start(ctx, zap.NewNop(), config.NewInternalConfig(), server.WithMetricsEndpoint("metrics"))
+	assert.Error(t, err)
+}
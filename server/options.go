@@ -1,8 +1,12 @@
 package server
 
 import (
+	"fmt"
+	"time"
+
 	"github.com/gate4ai/gate4ai/server/a2a"
 	"github.com/gate4ai/gate4ai/server/mcp/capability"
+	"github.com/gate4ai/gate4ai/shared"
 	schema "github.com/gate4ai/gate4ai/shared/mcp/2025/schema"
 )
 
@@ -34,6 +38,18 @@ func WithMCPPromptTemplate(name string, description string, arguments []schema.P
 	}
 }
 
+// WithMCPPromptFromTemplate is a server option to add an MCP prompt template rendered from
+// templateStr without a Go handler (see capability.NewTemplatePromptHandler).
+func WithMCPPromptFromTemplate(name string, description string, templateStr string, arguments []schema.PromptArgument) ServerOption {
+	return func(b *ServerBuilder) error {
+		handler, err := capability.NewTemplatePromptHandler(templateStr, arguments)
+		if err != nil {
+			return fmt.Errorf("prompt '%s': %w", name, err)
+		}
+		return WithMCPPromptTemplate(name, description, arguments, handler)(b)
+	}
+}
+
 // WithMCPResource is a server option to add an MCP resource.
 func WithMCPResource(uri string, name string, description string, mimeType string, handler capability.ResourceHandler) ServerOption {
 	return func(b *ServerBuilder) error {
@@ -44,12 +60,56 @@ func WithMCPResource(uri string, name string, description string, mimeType strin
 		if err != nil {
 			return err
 		}
-		return resCap.AddResource(uri, name, description, mimeType, handler)
+		return resCap.AddResource(uri, name, description, mimeType, nil, handler)
+	}
+}
+
+// WithMCPChunkedResource is a server option to add an MCP resource whose text is streamed
+// as the handler writes it (see capability.ChunkedResourceHandler).
+func WithMCPChunkedResource(uri string, name string, description string, mimeType string, handler capability.ChunkedResourceHandler) ServerOption {
+	return func(b *ServerBuilder) error {
+		if err := b.EnsureMCPBaseCapability(); err != nil {
+			return err
+		}
+		resCap, err := b.EnsureResourcesCapability()
+		if err != nil {
+			return err
+		}
+		return resCap.AddChunkedResource(uri, name, description, mimeType, handler)
+	}
+}
+
+// WithMCPAnnotatedResource is a server option to add an MCP resource with access control annotations.
+func WithMCPAnnotatedResource(uri string, name string, description string, mimeType string, annotations map[string]string, handler capability.ResourceHandler) ServerOption {
+	return func(b *ServerBuilder) error {
+		if err := b.EnsureMCPBaseCapability(); err != nil {
+			return err
+		}
+		resCap, err := b.EnsureResourcesCapability()
+		if err != nil {
+			return err
+		}
+		return resCap.AddResource(uri, name, description, mimeType, annotations, handler)
+	}
+}
+
+// WithMCPResourceAuthorizationPolicy is a server option to check resources/read against a policy.
+func WithMCPResourceAuthorizationPolicy(policy capability.ResourceAuthzPolicy) ServerOption {
+	return func(b *ServerBuilder) error {
+		if err := b.EnsureMCPBaseCapability(); err != nil {
+			return err
+		}
+		resCap, err := b.EnsureResourcesCapability()
+		if err != nil {
+			return err
+		}
+		resCap.SetAuthorizationPolicy(policy)
+		return nil
 	}
 }
 
 // WithMCPResourceTemplate is a server option to add an MCP resource template.
-func WithMCPResourceTemplate(uriTemplate string, name string, description string, mimeType string, handler capability.ResourceHandler) ServerOption {
+func WithMCPResourceTemplate(uriTemplate string, name string, description string, mimeType string, handler capability.ResourceTemplateHandler) ServerOption {
 	return func(b *ServerBuilder) error {
 		if err := b.EnsureMCPBaseCapability(); err != nil {
 			return err
@@ -62,6 +122,22 @@ func WithMCPResourceTemplate(uriTemplate string, name string, description string
 	}
 }
 
+// WithMCPResourcesPageSize is a server option to paginate resources/list and resources/templates/list
+// with pageSize entries per page (capability.DefaultResourcesPageSize by default); 0 disables pagination.
+func WithMCPResourcesPageSize(pageSize int) ServerOption {
+	return func(b *ServerBuilder) error {
+		if pageSize < 0 {
+			return fmt.Errorf("resources page size must not be negative, got %d", pageSize)
+		}
+		resCap, err := b.EnsureResourcesCapability()
+		if err != nil {
+			return err
+		}
+		capability.WithResourcesPageSize(pageSize)(resCap)
+		return nil
+	}
+}
+
 // WithMCPSubscriptionHandler is a server option to add a handler for subscription events.
 func WithMCPSubscriptionHandler(handler capability.SubscriptionHandler) ServerOption {
 	return func(b *ServerBuilder) error {
@@ -77,6 +153,53 @@ func WithMCPSubscriptionHandler(handler capability.SubscriptionHandler) ServerOp
 	}
 }
 
+// WithMCPCompletionCapability is a server option to answer completion/complete with handler for
+// prompt and resource template arguments that have no completer of their own.
+func WithMCPCompletionCapability(handler capability.CompletionHandler) ServerOption {
+	return func(b *ServerBuilder) error {
+		if handler == nil {
+			return fmt.Errorf("completion handler cannot be nil")
+		}
+		completionCap, err := b.EnsureCompletionCapability()
+		if err != nil {
+			return err
+		}
+		completionCap.SetDefaultCompleter(handler)
+		return nil
+	}
+}
+
+// WithMCPPromptCompleter is a server option to complete the arguments of the prompt promptName.
+func WithMCPPromptCompleter(promptName string, handler capability.CompletionHandler) ServerOption {
+	return func(b *ServerBuilder) error {
+		if handler == nil {
+			return fmt.Errorf("completion handler cannot be nil for prompt '%s'", promptName)
+		}
+		completionCap, err := b.EnsureCompletionCapability()
+		if err != nil {
+			return err
+		}
+		completionCap.AddPromptCompleter(promptName, handler)
+		return nil
+	}
+}
+
+// WithMCPResourceCompleter is a server option to complete the arguments of a resource template,
+// referenced by its URI template.
+func WithMCPResourceCompleter(uriTemplate string, handler capability.CompletionHandler) ServerOption {
+	return func(b *ServerBuilder) error {
+		if handler == nil {
+			return fmt.Errorf("completion handler cannot be nil for resource '%s'", uriTemplate)
+		}
+		completionCap, err := b.EnsureCompletionCapability()
+		if err != nil {
+			return err
+		}
+		completionCap.AddResourceCompleter(uriTemplate, handler)
+		return nil
+	}
+}
+
 // WithMCPTool is a server option to add an MCP tool.
 func WithMCPTool(name string, description string, inputSchema *schema.JSONSchemaProperty, annotations *schema.ToolAnnotations, handler capability.ToolHandler) ServerOption {
 	return func(b *ServerBuilder) error {
@@ -94,6 +217,101 @@ func WithMCPTool(name string, description string, inputSchema *schema.JSONSchema
 	}
 }
 
+// WithMCPProgressTool is a server option like WithMCPTool for long-running tools whose handler
+// reports progress to the client.
+func WithMCPProgressTool(name string, description string, inputSchema *schema.JSONSchemaProperty, annotations *schema.ToolAnnotations, handler capability.ProgressHandler) ServerOption {
+	return func(b *ServerBuilder) error {
+		if err := b.EnsureMCPBaseCapability(); err != nil {
+			return err
+		}
+		toolsCap, err := b.EnsureToolsCapability()
+		if err != nil {
+			return err
+		}
+		return toolsCap.AddProgressTool(name, description, inputSchema, annotations, handler)
+	}
+}
+
+// WithMCPToolsPageSize is a server option to paginate tools/list with pageSize tools per page.
+func WithMCPToolsPageSize(pageSize int) ServerOption {
+	return func(b *ServerBuilder) error {
+		if pageSize < 0 {
+			return fmt.Errorf("tools page size must not be negative, got %d", pageSize)
+		}
+		toolsCap, err := b.EnsureToolsCapability()
+		if err != nil {
+			return err
+		}
+		capability.WithToolsPageSize(pageSize)(toolsCap)
+		return nil
+	}
+}
+
+// WithMCPToolTimeout is a server option to fail tool calls running longer than timeout with a
+// "Tool execution timed out" error (see capability.WithToolTimeout).
+func WithMCPToolTimeout(timeout time.Duration) ServerOption {
+	return func(b *ServerBuilder) error {
+		if timeout <= 0 {
+			return fmt.Errorf("tool timeout must be positive, got %s", timeout)
+		}
+		toolsCap, err := b.EnsureToolsCapability()
+		if err != nil {
+			return err
+		}
+		capability.WithToolTimeout(timeout)(toolsCap)
+		return nil
+	}
+}
+
+// WithMCPRoot is a server option to expose a file system root (a file:// URI) via roots/list.
+func WithMCPRoot(uri string, name string) ServerOption {
+	return func(b *ServerBuilder) error {
+		rootsCap, err := b.EnsureRootsCapability()
+		if err != nil {
+			return err
+		}
+		return rootsCap.AddRoot(uri, name)
+	}
+}
+
+// WithMCPLogging is a server option to accept logging/setLevel from clients and advertise
+// the logging capability. The level is kept in the session, so handlers can send log messages
+// with any LoggingCapability (see capability.NewLoggingCapability).
+func WithMCPLogging() ServerOption {
+	return func(b *ServerBuilder) error {
+		_, err := b.EnsureLoggingCapability()
+		return err
+	}
+}
+
+// WithRateLimiter is a server option to refuse the requests and notifications rl does not
+// allow, with a "Rate limit exceeded" error, before they reach any capability handler.
+func WithRateLimiter(rl shared.RateLimiter) ServerOption {
+	return func(b *ServerBuilder) error {
+		if rl == nil {
+			return fmt.Errorf("rate limiter cannot be nil")
+		}
+		b.manager.Input().SetRateLimiter(rl)
+		return nil
+	}
+}
+
+// WithCapability is a server option to register a capability of your own next to the MCP
+// capabilities. A server capability gets the server's EventBus, so it can react to the events
+// of the others, e.g. subscribe to capability.ToolCalledTopic.
+func WithCapability(cap shared.ICapability) ServerOption {
+	return func(b *ServerBuilder) error {
+		if cap == nil {
+			return fmt.Errorf("capability cannot be nil")
+		}
+		if err := b.EnsureMCPBaseCapability(); err != nil {
+			return err
+		}
+		b.capabilities = append(b.capabilities, cap)
+		return nil
+	}
+}
+
 // WithA2ACapability is a server option to add and configure the A2A capability.
 func WithA2ACapability(store a2a.TaskStore, handler a2a.A2AHandler) ServerOption {
 	return func(b *ServerBuilder) error {
@@ -103,3 +321,39 @@ func WithA2ACapability(store a2a.TaskStore, handler a2a.A2AHandler) ServerOption
 		return err
 	}
 }
+
+// WithA2ASkillCapability is a server option to add the A2A capability with a SkillRouter,
+// which dispatches each task to the handler of the skill named in its metadata.
+func WithA2ASkillCapability(store a2a.TaskStore, router *a2a.SkillRouter) ServerOption {
+	return func(b *ServerBuilder) error {
+		if router == nil {
+			return fmt.Errorf("skill router cannot be nil")
+		}
+		_, err := b.EnsureA2ACapability(store, router.Handle)
+		return err
+	}
+}
+
+// WithA2ADebugMessageLog is a server option to log every A2A message to the NDJSON file at path
+// (see a2a.WithDebugMessageLog). It must be applied after WithA2ACapability.
+func WithA2ADebugMessageLog(path string) ServerOption {
+	return func(b *ServerBuilder) error {
+		if b.a2aCap == nil {
+			return fmt.Errorf("WithA2ADebugMessageLog requires the A2A capability, apply WithA2ACapability first")
+		}
+		a2a.WithDebugMessageLog(path)(b.a2aCap)
+		return nil
+	}
+}
+
+// WithStatusDeltaEncoding is a server option to enable delta encoding of status messages
+// in A2A streams. It must be applied after WithA2ACapability.
+func WithStatusDeltaEncoding(enabled bool) ServerOption {
+	return func(b *ServerBuilder) error {
+		if b.a2aCap == nil {
+			return fmt.Errorf("WithStatusDeltaEncoding requires the A2A capability, apply WithA2ACapability first")
+		}
+		a2a.WithStatusDeltaEncoding(enabled)(b.a2aCap)
+		return nil
+	}
+}
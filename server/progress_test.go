@@ -0,0 +1,118 @@
+package server_test
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gate4ai/gate4ai/server"
+	"github.com/gate4ai/gate4ai/server/mcp/capability"
+	"github.com/gate4ai/gate4ai/server/transport"
+	"github.com/gate4ai/gate4ai/shared"
+	"github.com/gate4ai/gate4ai/shared/config"
+	"github.com/gate4ai/gate4ai/shared/mcp/2025/schema"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// readSSEData returns the data of the next SSE event with a data line.
+func readSSEData(t *testing.T, reader *bufio.Reader) (event string, data string) {
+	for {
+		line, err := reader.ReadString('\n')
+		require.NoError(t, err)
+		line = strings.TrimRight(line, "\r\n")
+		switch {
+		case strings.HasPrefix(line, "event:"):
+			event = strings.TrimSpace(strings.TrimPrefix(line, "event:"))
+		case strings.HasPrefix(line, "data:"):
+			data = strings.TrimSpace(strings.TrimPrefix(line, "data:"))
+		case line == "" && data != "":
+			return event, data
+		}
+	}
+}
+
+func TestToolProgressNotificationsOnSSEStream(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	handler := func(msg *shared.Message, arguments schema.Arguments) (*schema.Meta, []schema.Content, error) {
+		progress := capability.GetProgressReporter(msg, capability.ProgressToken(msg))
+		for step := 1; step <= 3; step++ {
+			if err := progress.Report(context.Background(), float64(step), 3, fmt.Sprintf("step %d", step)); err != nil {
+				return nil, nil, err
+			}
+		}
+		return nil, schema.NewTextContent("done"), nil
+	}
+	port := freePort(t)
+	cfg := config.NewInternalConfig()
+	cfg.UserKeyHashes[config.HashAPIKey("progress-key")] = "progress-user"
+	_, err := server.Start(ctx, zap.NewNop(), cfg,
+		server.WithListenAddr(fmt.Sprintf(":%d", port)),
+		server.WithMCPTool("index", "Indexes documents", nil, nil, handler),
+	)
+	require.NoError(t, err)
+	waitForPort(t, port)
+	baseURL := fmt.Sprintf("http://localhost:%d", port)
+
+	streamCtx, streamCancel := context.WithTimeout(ctx, 10*time.Second)
+	defer streamCancel()
+	req, err := http.NewRequestWithContext(streamCtx, http.MethodGet, baseURL+transport.MCP2024_PATH+"?key=progress-key", nil)
+	require.NoError(t, err)
+	req.Header.Set("Accept", "text/event-stream")
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	reader := bufio.NewReader(resp.Body)
+
+	event, endpoint := readSSEData(t, reader)
+	require.Equal(t, "endpoint", event)
+	if !strings.HasPrefix(endpoint, "http") {
+		endpoint = baseURL + endpoint
+	}
+	post := func(body string) {
+		resp, err := http.Post(endpoint, "application/json", strings.NewReader(body))
+		require.NoError(t, err)
+		resp.Body.Close()
+		require.Less(t, resp.StatusCode, 300, "POST %s", body)
+	}
+
+	post(`{"jsonrpc": "2.0", "id": 1, "method": "initialize", "params": {"protocolVersion": "2024-11-05", "capabilities": {}, "clientInfo": {"name": "progress-test", "version": "1.0"}}}`)
+	_, data := readSSEData(t, reader)
+	require.Contains(t, data, `"id":1`)
+	post(`{"jsonrpc": "2.0", "method": "notifications/initialized"}`)
+
+	post(`{"jsonrpc": "2.0", "id": 2, "method": "tools/call", "params": {"name": "index", "arguments": {}, "_meta": {"progressToken": "index-1"}}}`)
+	var progress []float64
+	for {
+		_, data := readSSEData(t, reader)
+		var msg struct {
+			ID     json.RawMessage `json:"id"`
+			Method string          `json:"method"`
+			Params struct {
+				ProgressToken string  `json:"progressToken"`
+				Progress      float64 `json:"progress"`
+				Total         float64 `json:"total"`
+			} `json:"params"`
+		}
+		require.NoError(t, json.Unmarshal([]byte(data), &msg))
+		if string(msg.ID) == "2" {
+			break // The tool result follows its progress notifications
+		}
+		if msg.Method != capability.ProgressNotificationMethod {
+			continue
+		}
+		assert.Equal(t, "index-1", msg.Params.ProgressToken)
+		assert.Equal(t, float64(3), msg.Params.Total)
+		progress = append(progress, msg.Params.Progress)
+	}
+	assert.Equal(t, []float64{1, 2, 3}, progress)
+}
@@ -10,9 +10,12 @@ import (
 	"strings"
 	"time"
 
+	"github.com/gate4ai/gate4ai/server/a2a"
+	"github.com/gate4ai/gate4ai/server/metrics"
 	"github.com/gate4ai/gate4ai/server/transport"
 	"github.com/gate4ai/gate4ai/shared"
 	"github.com/gate4ai/gate4ai/shared/config"
+	"go.opentelemetry.io/otel/trace"
 	"go.uber.org/zap"
 
 	"github.com/gate4ai/gate4ai/server/extra"
@@ -57,12 +60,14 @@ func Start(ctx context.Context, logger *zap.Logger, cfg config.IConfig, options
 		transport:    transportInstance,
 		mux:          http.NewServeMux(),
 		capabilities: make([]shared.ICapability, 0),
+		eventBus:     shared.NewLocalEventBus(logger),
 	}
 
 	// --- 2. Apply Server Options ---
 	logger.Info("Applying server configuration options...")
 	for _, option := range options {
 		if err := option(builder); err != nil {
+			builder.close()
 			return nil, fmt.Errorf("failed to apply server option: %w", err)
 		}
 	}
@@ -72,6 +77,13 @@ func Start(ctx context.Context, logger *zap.Logger, cfg config.IConfig, options
 	// Add default validators
 	sessionManager.AddValidator(validators.CreateDefaultValidators()...)
 
+	// Let the capabilities react to each other's events
+	for _, cap := range builder.capabilities {
+		if serverCap, ok := cap.(shared.IServerCapability); ok {
+			serverCap.SetEventBus(builder.eventBus)
+		}
+	}
+
 	// Register capabilities stored in the map with the session manager's input processor
 	if len(builder.capabilities) > 0 {
 		logger.Info("Registering capabilities with session manager", zap.Int("count", len(builder.capabilities)))
@@ -86,6 +98,9 @@ func Start(ctx context.Context, logger *zap.Logger, cfg config.IConfig, options
 	if builder.registerMCPRoutes {
 		builder.transport.RegisterMCPHandlers(builder.mux)
 	}
+	if builder.registerWebSocketRoutes {
+		transport.NewWebSocketTransport(builder.transport).RegisterHandlers(builder.mux)
+	}
 	if builder.registerA2ARoutes {
 		// Fetch agent card base info from config
 		// Construct agent URL based on listen address + A2A path
@@ -106,15 +121,56 @@ func Start(ctx context.Context, logger *zap.Logger, cfg config.IConfig, options
 
 		a2aInfo, err := cfg.GetA2AAgentCard(agentURL) // Use constructed URL
 		if err != nil {
+			builder.close()
 			return nil, fmt.Errorf("failed to load A2A agent card base info from config: %w", err)
 		}
 		builder.transport.RegisterA2AHandlers(builder.mux, a2aInfo)
+		logger.Info("Registering task graph handler", zap.String("path", "/tasks/graph"))
+		builder.mux.HandleFunc("/tasks/graph", builder.a2aCap.TaskGraphHandler())
+	}
+
+	// Like metrics, the tracer provider reaches the A2A capability whatever the option order
+	if builder.tracerProvider != nil && builder.a2aCap != nil {
+		a2a.WithTracerProvider(builder.tracerProvider)(builder.a2aCap)
+	}
+
+	// Metrics are wired last, so they cover capabilities added by any option
+	if builder.metrics != nil {
+		sessionManager.AddSessionObserver(builder.metrics)
+		if builder.a2aCap != nil {
+			a2a.WithStateTransitionObserver(builder.metrics.TaskStateTransition)(builder.a2aCap)
+		}
+		logger.Info("Registering metrics handler", zap.String("path", builder.metricsPath))
+		builder.mux.Handle(builder.metricsPath, builder.metrics.Handler())
 	}
 
 	// Register status handler
 	logger.Info("Registering status handler", zap.String("path", "/status"))
 	builder.mux.HandleFunc("/status", extra.StatusHandler(cfg, logger))
 
+	// Register health and readiness probes
+	startTime := time.Now()
+	var readyChecks []extra.HealthCheck
+	if builder.a2aCap != nil {
+		readyChecks = append(readyChecks, builder.a2aCap.CheckTaskStore)
+	}
+	logger.Info("Registering health handlers", zap.Strings("paths", []string{"/health", "/ready"}))
+	builder.mux.HandleFunc("/health", extra.HealthHandler(cfg, logger, startTime))
+	builder.mux.HandleFunc("/ready", extra.HealthHandler(cfg, logger, startTime, readyChecks...))
+
+	// --- Serve a single session over stdin/stdout instead of HTTP ---
+	if builder.stdio {
+		logger.Info("Serving MCP over stdio")
+		stdioErrChan := make(chan error, 1)
+		go func() {
+			defer close(stdioErrChan)
+			stdioErrChan <- transport.NewStdioTransport(sessionManager, logger, os.Stdin, os.Stdout).Run(ctx)
+			builder.close()
+			sessionManager.CloseAllSessions()
+		}()
+		return stdioErrChan, nil
+	}
+
 	// --- Start HTTP Server using Shared Utility ---
 	serverInstance, listenerErrChan, startErr := transport.StartHTTPServer(
 		ctx,
@@ -124,9 +180,29 @@ func Start(ctx context.Context, logger *zap.Logger, cfg config.IConfig, options
 		builder.listenAddr, // Use the potentially overridden address
 	)
 	if startErr != nil {
+		builder.close()
 		return nil, fmt.Errorf("failed to start HTTP server: %w", startErr)
 	}
 
+	// --- Start the admin server on its own listener, if configured ---
+	var adminInstance *http.Server
+	if builder.adminListenAddr != "" {
+		logger.Info("Starting admin server", zap.String("address", builder.adminListenAddr))
+		adminHandler := extra.AdminHandler(builder.toolsCap, sessionManager, builder.adminToken, logger, startTime)
+		var adminErrChan <-chan error
+		adminInstance, adminErrChan, startErr = transport.StartHTTPServer(ctx, logger, cfg, adminHandler, builder.adminListenAddr)
+		if startErr != nil {
+			transport.ShutdownHTTPServer(context.Background(), logger, serverInstance)
+			builder.close()
+			return nil, fmt.Errorf("failed to start admin HTTP server: %w", startErr)
+		}
+		go func() {
+			if err, ok := <-adminErrChan; ok && err != nil && !errors.Is(err, http.ErrServerClosed) {
+				logger.Error("Admin server listener failed", zap.Error(err))
+			}
+		}()
+	}
+
 	// --- Goroutine to handle listener errors and graceful shutdown ---
 	go func() {
 		select {
@@ -140,7 +216,11 @@ func Start(ctx context.Context, logger *zap.Logger, cfg config.IConfig, options
 			logger.Info("Shutdown signal received, stopping server...")
 			shutdownCtx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
 			defer cancel()
+			builder.close()
 			sessionManager.CloseAllSessions()
+			if adminInstance != nil {
+				transport.ShutdownHTTPServer(shutdownCtx, logger, adminInstance)
+			}
 			transport.ShutdownHTTPServer(shutdownCtx, logger, serverInstance)
 			logger.Info("Server stopped.")
 		}
@@ -174,3 +254,94 @@ func WithSessionTimeout(timeout time.Duration) ServerOption {
 		return transport.WithSessionTimeout(timeout)(b.transport) // Apply option to transport
 	}
 }
+
+// WithRequestIDGenerator configures how IDs of server-initiated requests are generated.
+func WithRequestIDGenerator(gen func() interface{}) ServerOption {
+	return func(b *ServerBuilder) error {
+		if b.transport == nil {
+			return errors.New("transport not initialized in builder, cannot set request ID generator")
+		}
+		return transport.WithRequestIDGenerator(gen)(b.transport)
+	}
+}
+
+// WithSSEEventBuffer keeps the last n events of each V2024 SSE stream for replay on reconnect.
+func WithSSEEventBuffer(n int) ServerOption {
+	return func(b *ServerBuilder) error {
+		if b.transport == nil {
+			return errors.New("transport not initialized in builder, cannot set SSE event buffer")
+		}
+		return transport.WithSSEEventBuffer(n)(b.transport)
+	}
+}
+
+// WithWebSocketTransport also serves MCP over a WebSocket on transport.WS_PATH, for clients
+// behind proxies that buffer SSE. Clients authenticate with the "key" query parameter.
+func WithWebSocketTransport() ServerOption {
+	return func(b *ServerBuilder) error {
+		if b.transport == nil {
+			return errors.New("transport not initialized in builder, cannot enable WebSocket transport")
+		}
+		b.registerWebSocketRoutes = true
+		return nil
+	}
+}
+
+// WithStdioTransport serves a single MCP session over stdin and stdout, for servers launched as
+// subprocesses by MCP hosts, instead of starting the HTTP listeners. The channel returned by
+// Start receives nil when stdin is closed. stdout carries only JSON-RPC messages, so the
+// logger must write to stderr (as zap's production and development configs do).
+func WithStdioTransport() ServerOption {
+	return func(b *ServerBuilder) error {
+		b.stdio = true
+		return nil
+	}
+}
+
+// WithMetricsEndpoint serves Prometheus metrics on path: active sessions and, with the A2A
+// capability, task state transitions.
+func WithMetricsEndpoint(path string) ServerOption {
+	return func(b *ServerBuilder) error {
+		if !strings.HasPrefix(path, "/") {
+			return fmt.Errorf("metrics path must start with '/', got %q", path)
+		}
+		b.metricsPath = path
+		b.metrics = metrics.New()
+		return nil
+	}
+}
+
+// WithOTELTracerProvider records request, task and task store spans with tp instead of the
+// global OpenTelemetry tracer provider. Without it and without a global provider, tracing is a no-op.
+func WithOTELTracerProvider(tp trace.TracerProvider) ServerOption {
+	return func(b *ServerBuilder) error {
+		if tp == nil {
+			return errors.New("tracer provider cannot be nil")
+		}
+		if b.transport == nil {
+			return errors.New("transport not initialized in builder, cannot set tracer provider")
+		}
+		b.tracerProvider = tp
+		return transport.WithTracerProvider(tp)(b.transport)
+	}
+}
+
+// WithAdminServer serves admin-only endpoints on a separate listener: POST /admin/tools/register,
+// DELETE /admin/tools/{name} and GET /admin/stats. Requests must carry "Authorization: Bearer <adminToken>".
+func WithAdminServer(listenAddr string, adminToken string) ServerOption {
+	return func(b *ServerBuilder) error {
+		if listenAddr == "" {
+			return errors.New("admin server listen address cannot be empty")
+		}
+		if adminToken == "" {
+			return errors.New("admin token cannot be empty")
+		}
+		// Runtime tool registration needs the tools capability even if no tool is configured yet
+		if _, err := b.EnsureToolsCapability(); err != nil {
+			return err
+		}
+		b.adminListenAddr = listenAddr
+		b.adminToken = adminToken
+		return nil
+	}
+}
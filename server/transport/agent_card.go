@@ -0,0 +1,47 @@
+package transport
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/gate4ai/gate4ai/shared"
+	a2aSchema "github.com/gate4ai/gate4ai/shared/a2a/2025-draft/schema"
+	"go.uber.org/zap"
+)
+
+// handlerSource is implemented by session managers that expose their input processor (e.g. Manager).
+type handlerSource interface {
+	Input() *shared.Input
+}
+
+// DeriveCapabilities reports the A2A capabilities backed by the registered method handlers.
+func DeriveCapabilities(handlers map[string]func(*shared.Message) (interface{}, error)) a2aSchema.AgentCapabilities {
+	registered := func(method string) bool {
+		handler, ok := handlers[method]
+		return ok && handler != nil
+	}
+	return a2aSchema.AgentCapabilities{
+		Streaming:              registered("tasks/sendSubscribe"),
+		PushNotifications:      registered("tasks/pushNotification/set"),
+		StateTransitionHistory: registered("tasks/get"), // tasks/get honors historyLength
+	}
+}
+
+// handleAgentCard serves the agent card with its URL pointing at the A2A endpoint. When the session
+// manager exposes its handlers, the capabilities are derived from them on every request.
+func (t *Transport) handleAgentCard(agentCard *a2aSchema.AgentCard) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Access-Control-Allow-Origin", "*")
+		urlCopy := *r.URL
+		urlCopy.Path = A2A_PATH
+		agentCardCopy := *agentCard
+		agentCardCopy.URL = urlCopy.String()
+		if source, ok := t.sessionManager.(handlerSource); ok {
+			agentCardCopy.Capabilities = DeriveCapabilities(source.Input().Handlers())
+		}
+		if err := json.NewEncoder(w).Encode(agentCardCopy); err != nil {
+			t.logger.Warn("Failed to write agent card", zap.Error(err))
+		}
+	}
+}
@@ -0,0 +1,65 @@
+package transport_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gate4ai/gate4ai/server/transport"
+	"github.com/gate4ai/gate4ai/shared"
+	a2aSchema "github.com/gate4ai/gate4ai/shared/a2a/2025-draft/schema"
+	"github.com/gate4ai/gate4ai/shared/config"
+	schema2025 "github.com/gate4ai/gate4ai/shared/mcp/2025/schema"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+// methodsCapability registers the given A2A methods with a no-op handler.
+type methodsCapability []string
+
+func (m methodsCapability) GetHandlers() map[string]func(*shared.Message) (interface{}, error) {
+	handlers := make(map[string]func(*shared.Message) (interface{}, error))
+	for _, method := range m {
+		handlers[method] = func(msg *shared.Message) (interface{}, error) { return struct{}{}, nil }
+	}
+	return handlers
+}
+
+func (m methodsCapability) SetCapabilities(s *schema2025.ServerCapabilities) {}
+func (m methodsCapability) SetEventBus(bus shared.EventBus)                  {}
+
+func TestAgentCardCapabilitiesFollowRegisteredHandlers(t *testing.T) {
+	logger := zap.NewNop()
+	cfg := config.NewInternalConfig()
+	manager, err := transport.NewManager(logger, cfg)
+	require.NoError(t, err)
+	tp, err := transport.New(manager, logger, cfg)
+	require.NoError(t, err)
+	mux := http.NewServeMux()
+	tp.RegisterA2AHandlers(mux, &a2aSchema.AgentCard{Name: "agent"})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	fetchCapabilities := func() a2aSchema.AgentCapabilities {
+		resp, err := http.Get(server.URL + "/.well-known/agent.json")
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		var card a2aSchema.AgentCard
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&card))
+		return card.Capabilities
+	}
+
+	assert.Equal(t, a2aSchema.AgentCapabilities{}, fetchCapabilities())
+
+	manager.AddCapability(methodsCapability{"tasks/send", "tasks/get"})
+	assert.Equal(t, a2aSchema.AgentCapabilities{StateTransitionHistory: true}, fetchCapabilities())
+
+	manager.AddCapability(methodsCapability{"tasks/sendSubscribe", "tasks/resubscribe"})
+	assert.Equal(t, a2aSchema.AgentCapabilities{Streaming: true, StateTransitionHistory: true}, fetchCapabilities())
+
+	manager.AddCapability(methodsCapability{"tasks/pushNotification/set", "tasks/pushNotification/get"})
+	assert.Equal(t, a2aSchema.AgentCapabilities{Streaming: true, PushNotifications: true, StateTransitionHistory: true}, fetchCapabilities())
+}
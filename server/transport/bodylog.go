@@ -0,0 +1,83 @@
+//go:build !production
+
+package transport
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"net/http"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// WithBodyLogging logs the body of every POST request and everything written to the
+// response at level, each truncated to maxBodySize bytes. It is meant for debugging and is
+// not compiled into builds with the "production" tag.
+func WithBodyLogging(level zapcore.Level, maxBodySize int) TransportOption {
+	return func(t *Transport) error {
+		if maxBodySize <= 0 {
+			return errors.New("max body size must be positive")
+		}
+		t.handlerWrapper = func(next http.HandlerFunc) http.HandlerFunc {
+			return t.logBodies(next, level, maxBodySize)
+		}
+		return nil
+	}
+}
+
+// logBodies wraps next so request and response bodies are logged.
+func (t *Transport) logBodies(next http.HandlerFunc, level zapcore.Level, maxBodySize int) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		logger := t.logger.With(zap.String("method", r.Method), zap.String("path", r.URL.Path))
+		if !logger.Core().Enabled(level) {
+			next(w, r)
+			return
+		}
+		if r.Method == http.MethodPost && r.Body != nil {
+			body, err := io.ReadAll(r.Body)
+			r.Body.Close()
+			if err != nil {
+				logger.Warn("Failed to read request body for logging", zap.Error(err))
+			}
+			logger.Log(level, "HTTP request body", zap.ByteString("body", truncateBody(body, maxBodySize)), zap.Int("size", len(body)))
+			r.Body = io.NopCloser(bytes.NewReader(body))
+		}
+		next(&bodyLoggingWriter{ResponseWriter: w, logger: logger, level: level, maxBodySize: maxBodySize}, r)
+	}
+}
+
+// truncateBody returns at most maxBodySize bytes of body.
+func truncateBody(body []byte, maxBodySize int) []byte {
+	if len(body) > maxBodySize {
+		return body[:maxBodySize]
+	}
+	return body
+}
+
+// bodyLoggingWriter logs response data before writing it. Each write is logged on its own
+// so SSE events show up as they are sent.
+type bodyLoggingWriter struct {
+	http.ResponseWriter
+	logger      *zap.Logger
+	level       zapcore.Level
+	maxBodySize int
+}
+
+func (w *bodyLoggingWriter) Write(p []byte) (int, error) {
+	w.logger.Log(w.level, "HTTP response body", zap.ByteString("body", truncateBody(p, w.maxBodySize)), zap.Int("size", len(p)))
+	return w.ResponseWriter.Write(p)
+}
+
+// Flush keeps streaming responses working through the wrapper.
+func (w *bodyLoggingWriter) Flush() {
+	if flusher, ok := w.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+// Unwrap lets http.ResponseController reach the underlying writer.
+func (w *bodyLoggingWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
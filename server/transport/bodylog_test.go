@@ -0,0 +1,60 @@
+package transport_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gate4ai/gate4ai/server/mcp/capability"
+	"github.com/gate4ai/gate4ai/server/transport"
+	"github.com/gate4ai/gate4ai/shared/config"
+	schema2025 "github.com/gate4ai/gate4ai/shared/mcp/2025/schema"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func Test_SRV_25_HTTP_POS_07_BodyLoggingLogsRequestAndResponse(t *testing.T) {
+	core, logs := observer.New(zapcore.DebugLevel)
+	logger := zap.New(core)
+	cfg := config.NewInternalConfig()
+	cfg.ServerNameValue = "TestServer"
+	mockManager := NewMockMCPManager(cfg, logger)
+	tp, err := transport.New(mockManager, logger, cfg, transport.WithBodyLogging(zapcore.DebugLevel, 64))
+	require.NoError(t, err)
+	tp.SetAuthManager(&MockAuthenticator{AllowAnon: true})
+	tp.NoStream2025 = true
+	mockManager.AddCapability(capability.NewBase(logger, mockManager), &MockTestCapability{})
+	mux := http.NewServeMux()
+	tp.RegisterMCPHandlers(mux)
+	server := httptest.NewServer(mux)
+	defer server.Close()
+	defer mockManager.CloseAllSessions()
+
+	requestBody := createJsonRpcRequestBody(1, "initialize", schema2025.InitializeRequestParams{
+		ProtocolVersion: schema2025.PROTOCOL_VERSION,
+		ClientInfo:      schema2025.Implementation{Name: "body-logging-client", Version: "1.0"},
+		Capabilities:    schema2025.ClientCapabilities{},
+	})
+	resp, err := makePostRequest(t, server.URL+transport.MCP2025_PATH, requestBody, nil)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	// The handler still sees the whole body
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	assertJsonRpcSuccess(t, resp.Body, float64(1))
+
+	requestLogs := logs.FilterMessage("HTTP request body").All()
+	require.Len(t, requestLogs, 1)
+	logged := requestLogs[0].ContextMap()["body"].(string)
+	assert.Len(t, logged, 64, "Logged body must be truncated to maxBodySize")
+	assert.True(t, strings.HasPrefix(requestBody, logged))
+	assert.EqualValues(t, len(requestBody), requestLogs[0].ContextMap()["size"])
+
+	responseLogs := logs.FilterMessage("HTTP response body").All()
+	require.NotEmpty(t, responseLogs)
+	assert.Contains(t, responseLogs[0].ContextMap()["body"], `"jsonrpc"`)
+}
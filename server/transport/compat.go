@@ -0,0 +1,164 @@
+package transport
+
+import (
+	"encoding/json"
+	"sync"
+
+	"github.com/gate4ai/gate4ai/shared"
+	schemaV2024 "github.com/gate4ai/gate4ai/shared/mcp/2024/schema"
+	"github.com/gate4ai/gate4ai/shared/mcp/2025/schema"
+	"go.uber.org/zap"
+)
+
+// resultDowngrader rewrites a decoded 2025 result into its 2024 shape in place.
+type resultDowngrader func(result map[string]interface{})
+
+// BackwardCompatibilityTransformer downgrades responses sent over the V2024 SSE transport,
+// so 2024-11-05 clients are not handed fields or values that only exist in the 2025 schema.
+// Clients that ask for 2025-03-26 in initialize (e.g. the gateway) are left untouched.
+// Requests are tracked on their way in (TrackRequest) because responses do not carry the method.
+type BackwardCompatibilityTransformer struct {
+	logger      *zap.Logger
+	downgraders map[string]resultDowngrader
+	mu          sync.Mutex
+	pending     map[string]map[string]string // sessionID -> request ID -> method
+}
+
+// NewBackwardCompatibilityTransformer creates a transformer for initialize and tools/list responses.
+func NewBackwardCompatibilityTransformer(logger *zap.Logger) *BackwardCompatibilityTransformer {
+	return &BackwardCompatibilityTransformer{
+		logger: logger.Named("compat2024"),
+		downgraders: map[string]resultDowngrader{
+			"initialize": downgradeInitializeResult,
+			"tools/list": downgradeToolsListResult,
+		},
+		pending: make(map[string]map[string]string),
+	}
+}
+
+// TrackRequest remembers the method of an incoming request whose response needs downgrading.
+func (c *BackwardCompatibilityTransformer) TrackRequest(session shared.ISession, msg *shared.Message) {
+	if msg == nil || msg.Method == nil || msg.ID.IsEmpty() {
+		return
+	}
+	if _, ok := c.downgraders[*msg.Method]; !ok {
+		return
+	}
+	if *msg.Method == "initialize" {
+		if requestsV2025(msg) {
+			return
+		}
+	} else if session.GetNegotiatedVersion() != schemaV2024.PROTOCOL_VERSION {
+		return
+	}
+	sessionID := session.GetID()
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.pending[sessionID] == nil {
+		c.pending[sessionID] = make(map[string]string)
+	}
+	c.pending[sessionID][msg.ID.String()] = *msg.Method
+}
+
+// TransformResponse returns msg, or a downgraded copy if it answers a tracked request.
+// A downgraded initialize response also pins the session to 2024-11-05.
+func (c *BackwardCompatibilityTransformer) TransformResponse(session shared.ISession, msg *shared.Message) *shared.Message {
+	if msg == nil || msg.Method != nil || msg.ID.IsEmpty() {
+		return msg
+	}
+	sessionID := session.GetID()
+	c.mu.Lock()
+	method, tracked := c.pending[sessionID][msg.ID.String()]
+	if tracked {
+		delete(c.pending[sessionID], msg.ID.String())
+	}
+	c.mu.Unlock()
+	if !tracked || msg.Result == nil {
+		return msg
+	}
+
+	var result map[string]interface{}
+	if err := json.Unmarshal(*msg.Result, &result); err != nil {
+		c.logger.Warn("Cannot decode result for downgrade, sending it unchanged", zap.String("method", method), zap.Error(err))
+		return msg
+	}
+	c.downgraders[method](result)
+	if method == "initialize" {
+		session.SetNegotiatedVersion(schemaV2024.PROTOCOL_VERSION)
+	}
+	data, err := json.Marshal(result)
+	if err != nil {
+		c.logger.Warn("Cannot encode downgraded result, sending it unchanged", zap.String("method", method), zap.Error(err))
+		return msg
+	}
+	raw := json.RawMessage(data)
+	downgraded := *msg
+	downgraded.Result = &raw
+	c.logger.Debug("Downgraded response for V2024 client", zap.String("method", method), zap.String("sessionId", sessionID))
+	return &downgraded
+}
+
+// ForgetSession drops the requests tracked for a closed session.
+func (c *BackwardCompatibilityTransformer) ForgetSession(sessionID string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.pending, sessionID)
+}
+
+// requestsV2025 reports whether an initialize request asks for the 2025-03-26 protocol.
+func requestsV2025(msg *shared.Message) bool {
+	if msg.Params == nil {
+		return false
+	}
+	var params schemaV2024.InitializeRequestParams
+	if err := json.Unmarshal(*msg.Params, &params); err != nil {
+		return false
+	}
+	return params.ProtocolVersion == schema.PROTOCOL_VERSION
+}
+
+// downgradeInitializeResult pins the protocol version to 2024-11-05 and drops 2025-only capabilities.
+func downgradeInitializeResult(result map[string]interface{}) {
+	result["protocolVersion"] = schemaV2024.PROTOCOL_VERSION
+
+	capabilities, _ := result["capabilities"].(map[string]interface{})
+	if capabilities == nil {
+		capabilities = make(map[string]interface{})
+	}
+	delete(capabilities, "completions")
+	// 2024 experimental capabilities are objects keyed by name
+	if experimental, ok := capabilities["experimental"].(map[string]interface{}); ok {
+		for name, value := range experimental {
+			if _, isObject := value.(map[string]interface{}); !isObject {
+				delete(experimental, name)
+			}
+		}
+	}
+	result["capabilities"] = capabilities
+
+	serverInfo, _ := result["serverInfo"].(map[string]interface{})
+	if serverInfo == nil {
+		serverInfo = make(map[string]interface{})
+	}
+	for _, field := range []string{"name", "version"} {
+		if _, ok := serverInfo[field].(string); !ok {
+			serverInfo[field] = ""
+		}
+	}
+	result["serverInfo"] = serverInfo
+}
+
+// downgradeToolsListResult removes tool annotations and fills in the inputSchema 2024 requires.
+func downgradeToolsListResult(result map[string]interface{}) {
+	tools, _ := result["tools"].([]interface{})
+	for _, item := range tools {
+		tool, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		delete(tool, "annotations")
+		if _, ok := tool["inputSchema"].(map[string]interface{}); !ok {
+			tool["inputSchema"] = map[string]interface{}{"type": "object"}
+		}
+	}
+}
@@ -79,17 +79,20 @@ func (t *Transport) handleA2APOST(w http.ResponseWriter, r *http.Request, logger
 	}
 	session.SetStatus(shared.StatusConnected)
 	defer session.SetStatus(shared.StatusDisconnected)
+	traceSession(r.Context(), session)
 
 	msg.Session = session // Associate session context
 	msg.Timestamp = time.Now()
+	// Let synchronous handlers stop when the client goes away
+	msg.SetContext(r.Context())
 
-	// 4. Handle A2A Streaming Request (`tasks/sendSubscribe`)
-	isStreamingRequest := method == "tasks/sendSubscribe"
+	// 4. Handle A2A Streaming Request (`tasks/sendSubscribe`, and `tasks/resubscribe` for SSE clients)
 	clientAcceptsSSE := false
 	acceptHeader := strings.ToLower(r.Header.Get("Accept"))
 	if strings.Contains(acceptHeader, "text/event-stream") {
 		clientAcceptsSSE = true
 	}
+	isStreamingRequest := method == "tasks/sendSubscribe" || (method == "tasks/resubscribe" && clientAcceptsSSE)
 
 	if isStreamingRequest && !clientAcceptsSSE {
 		sendA2AErrorResponse(w, msg.ID, shared.JSONRPCErrorInvalidRequest, "tasks/sendSubscribe requires 'Accept: text/event-stream' header", nil, logger)
@@ -120,14 +123,28 @@ func (t *Transport) handleA2APOST(w http.ResponseWriter, r *http.Request, logger
 		w.Header().Set("Access-Control-Allow-Origin", "*")
 
 		eventID := 0
+		keepAlive, stopKeepAlive := t.sseKeepAlive(logger)
+		defer stopKeepAlive()
+		// Keepalives do not restart the wait for the next response
+		timeout := time.NewTimer(responseTimeout)
+		defer timeout.Stop()
 		// Wait for the response or timeout
 		for {
 			select {
 			case response, ok := <-responseChan:
+				timeout.Reset(responseTimeout)
 				if !ok {
 					logger.Info("A2A SSE output channel closed, ending stream", zap.String("sessionId", session.GetID()))
 					return
 				}
+				if response.Error != nil && eventID > 0 {
+					// The stream has started, so the error goes out as its last SSE event
+					logger.Error("A2A stream ended with an error", zap.Error(response.Error), zap.Any("reqID", msg.ID))
+					if data, err := json.Marshal(response); err == nil {
+						shared.FlushIfNotDone(logger, r, w, "id: %d\ndata: %s\n\n", eventID+1, data)
+					}
+					return
+				}
 				if response.Error != nil {
 					logger.Error("tasks/sendSubscribe handler returned an error immediately", zap.Error(response.Error), zap.Any("reqID", msg.ID))
 					sendA2AErrorResponse(w, msg.ID, response.Error.Code, response.Error.Message, response.Error.Data, logger)
@@ -135,13 +152,25 @@ func (t *Transport) handleA2APOST(w http.ResponseWriter, r *http.Request, logger
 				}
 
 				eventID++
+				// Events numbered by the capability keep their ID, so clients can resume from it.
+				// The task snapshot answering the request is not a resumable event and has no ID.
+				idLine := fmt.Sprintf("id: %d\n", eventID)
+				if response.SSEEventID != 0 {
+					idLine = fmt.Sprintf("id: %d\n", response.SSEEventID)
+				} else if response.ID != nil {
+					idLine = ""
+				}
 				data, err := json.Marshal(response)
 				if err != nil {
 					logger.Error("Failed to marshal A2A SSE event", zap.Error(err), zap.Any("reqID", msg.ID))
 					sendA2AErrorResponse(w, msg.ID, shared.JSONRPCErrorInternal, "Failed to marshal A2A SSE event", nil, logger)
 					return
 				}
-				shared.FlushIfNotDone(logger, r, w, "id: %d\ndata: %s\n\n", eventID, data)
+				if a2aSSEEvents[response.SSEEvent] {
+					shared.FlushIfNotDone(logger, r, w, "%sevent: %s\ndata: %s\n\n", idLine, response.SSEEvent, data)
+				} else {
+					shared.FlushIfNotDone(logger, r, w, "%sdata: %s\n\n", idLine, data)
+				}
 				logger.Debug("Sent A2A SSE event", zap.String("eventData", string(*response.Result)))
 
 				// Is final?
@@ -151,7 +180,12 @@ func (t *Transport) handleA2APOST(w http.ResponseWriter, r *http.Request, logger
 					logger.Info("Final A2A event sent, closing SSE stream", zap.String("sessionId", session.GetID()))
 					return // Exit loop, which closes the stream
 				}
-			case <-time.After(responseTimeout): // Use constant defined elsewhere
+			case <-keepAlive:
+				// Before the first event, errors are still sent as a plain JSON-RPC response
+				if eventID > 0 {
+					shared.FlushIfNotDone(logger, r, w, sseKeepAliveComment)
+				}
+			case <-timeout.C:
 				logger.Error("Timeout waiting for initial response from tasks/sendSubscribe handler", zap.Any("reqID", msg.ID))
 				sendA2AErrorResponse(w, msg.ID, shared.JSONRPCErrorInternal, "Timeout waiting for initial task response", nil, logger)
 				// Cleanup the registered callback
@@ -196,6 +230,11 @@ func (t *Transport) handleA2APOST(w http.ResponseWriter, r *http.Request, logger
 	}
 }
 
+// a2aSSEEvents lists the named SSE events an A2A stream may carry; any other event is sent unnamed.
+var a2aSSEEvents = map[string]bool{
+	shared.A2ASSEEventHistory: true,
+}
+
 // --- Response Helpers ---
 
 func sendA2ASuccessResponse(w http.ResponseWriter, id *schema.RequestID, result *json.RawMessage, logger *zap.Logger) {
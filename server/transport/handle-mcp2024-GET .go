@@ -3,7 +3,6 @@ package transport
 import (
 	"encoding/json"
 	"net/http"
-	"time"
 
 	"github.com/gate4ai/gate4ai/shared"
 	"go.uber.org/zap"
@@ -12,7 +11,6 @@ import (
 const (
 	sseEventEndpoint = "endpoint"
 	sseEventMessage  = "message"
-	sseEventPing     = "ping"
 )
 
 // It handles V2024 initialization via SSE endpoint event and
@@ -49,16 +47,36 @@ func (t *Transport) handle2024GET(w http.ResponseWriter, r *http.Request, logger
 	session.SetStatus(shared.StatusConnected)
 	logger.Info("Session status set to Connected", zap.String("sessionId", session.GetID()))
 
-	ticker := time.NewTicker(15 * time.Second) // Keepalive ticker
-	defer ticker.Stop()
+	events := t.sseEventBufferFor(session.GetParams())
+	if lastID, ok := lastEventID(r, logger); ok {
+		missed, complete := events.since(lastID)
+		if !complete {
+			logger.Warn("Some events after the last event ID were evicted from the buffer", zap.String("sessionId", session.GetID()), zap.Uint64("lastEventId", lastID))
+		}
+		for _, event := range missed {
+			shared.FlushIfNotDone(logger, r, w, "id: %d\nevent: %s\ndata: %s\n\n", event.id, sseEventMessage, event.data)
+		}
+		logger.Info("Replayed missed SSE events", zap.String("sessionId", session.GetID()), zap.Uint64("lastEventId", lastID), zap.Int("count", len(missed)))
+	}
+
+	keepAlive, stopKeepAlive := t.sseKeepAlive(logger)
+	defer stopKeepAlive()
 	defer logger.Debug("Stopped forwarding session output to V2024 SSE stream", zap.String("sessionId", session.GetID()))
 
+	// The output is released only after the forwarding goroutine stops, so a reconnect never races it
+	forwardingDone := make(chan struct{})
 	go func() {
+		defer close(forwardingDone)
 		for {
 			select {
 			case <-r.Context().Done():
 				logger.Info("V2024 SSE client disconnected (context done)", zap.String("sessionId", session.GetID()))
+				if t.sseEventBufferSize > 0 {
+					logger.Debug("Keeping session open for reconnect", zap.String("sessionId", session.GetID()))
+					return
+				}
 				t.sessionManager.CloseSession(session.GetID())
+				t.compat2024.ForgetSession(session.GetID())
 				return
 			case msg, ok := <-output:
 				if !ok {
@@ -69,17 +87,17 @@ func (t *Transport) handle2024GET(w http.ResponseWriter, r *http.Request, logger
 					continue
 				}
 
-				data, err := json.Marshal(msg)
+				data, err := json.Marshal(t.compat2024.TransformResponse(session, msg))
 				if err != nil {
 					logger.Error("Failed to marshal message for SSE", zap.Error(err), zap.Any("msgId", msg.ID), zap.Stringp("method", msg.Method))
 					continue // Skip message if marshalling fails
 				}
 
-				// Send as 'message' event
-				shared.FlushIfNotDone(logger, r, w, "id: %d\nevent: %s\ndata: %s\n\n", time.Now().UnixNano(), sseEventMessage, data)
+				// Send as 'message' event, buffered first so it can be replayed if this write is lost
+				shared.FlushIfNotDone(logger, r, w, "id: %d\nevent: %s\ndata: %s\n\n", events.add(data), sseEventMessage, data)
 				session.UpdateLastActivity()
-			case <-ticker.C:
-				shared.FlushIfNotDone(logger, r, w, "event: %s\ndata: %s\n\n", sseEventPing, `{}`)
+			case <-keepAlive:
+				shared.FlushIfNotDone(logger, r, w, sseKeepAliveComment)
 			}
 		}
 	}()
@@ -87,4 +105,5 @@ func (t *Transport) handle2024GET(w http.ResponseWriter, r *http.Request, logger
 	// Keep the handler alive while the goroutine runs.
 	// The client disconnecting will cancel the request context.
 	<-r.Context().Done()
+	<-forwardingDone
 }
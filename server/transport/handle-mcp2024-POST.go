@@ -1,6 +1,8 @@
 package transport
 
 import (
+	"context"
+	"errors"
 	"io"
 	"net/http"
 	"time"
@@ -18,6 +20,12 @@ func (t *Transport) handle2024POST(w http.ResponseWriter, r *http.Request, logge
 		http.Error(w, "Session not found", http.StatusNotFound)
 		return
 	}
+	if !validateJSONContentType(w, r, logger) {
+		return
+	}
+	// The response is sent on the SSE stream after this request ends, so the trace must outlive it
+	requestCtx := context.WithoutCancel(r.Context())
+	traceSession(requestCtx, session)
 
 	// --- Process Message(s) ---
 	// If we reach here, it's a V2024 style POST (session determined by query param)
@@ -42,11 +50,15 @@ func (t *Transport) handle2024POST(w http.ResponseWriter, r *http.Request, logge
 	for _, msg := range msgs {
 		msg.Session = session
 		msg.Timestamp = time.Now()
+		msg.SetContext(requestCtx)
+		t.compat2024.TrackRequest(session, msg)
 		if handleErr := session.Input().Put(msg); handleErr != nil {
 			logger.Error("Error handling message in V2024 POST", zap.Error(handleErr), zap.String("sessionId", session.GetID()), zap.Any("msgId", msg.ID))
-			// V2024 POST doesn't have a standard way to return errors for individual messages here.
-			// The original spec just sends back the 'endpoint' event. The V2024 server would send errors via SSE.
-			// Since we're adapting, we just log the error.
+			// V2024 POST always returns 202; requests rejected by validation get their error via SSE.
+			// Put answers dropped requests itself.
+			if !msg.ID.IsEmpty() && !errors.Is(handleErr, shared.ErrInputBusy) {
+				session.SendResponse(msg.ID, nil, handleErr)
+			}
 		}
 	}
 
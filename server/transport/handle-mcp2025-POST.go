@@ -1,7 +1,9 @@
 package transport
 
 import (
+	"context"
 	"encoding/json"
+	"fmt"
 	"io"
 	"net/http"
 	"strings"
@@ -15,12 +17,19 @@ import (
 // handlePOST processes POST requests on the unified MCP endpoint.
 // It handles V2025 message posting according to the 2025-03-26 specification.
 func (t *Transport) handlePOST(w http.ResponseWriter, r *http.Request, logger *zap.Logger) {
+	// Checked before session lookup so rejected bodies never create a session
+	if !validateJSONContentType(w, r, logger) {
+		return
+	}
 	session, err := t.getSession(r, r.Header.Get(MCP_SESSION_HEADER), logger, true)
 	if err != nil {
 		logger.Error("Failed to get session", zap.Error(err))
 		sendJSONRPCErrorResponse(w, nil, shared.JSONRPCErrorUnauthorized, "Failed to get session", nil, logger)
 		return
 	}
+	// Handlers may outlive the request (e.g. notifications answered with 202), so only its trace is kept
+	requestCtx := context.WithoutCancel(r.Context())
+	traceSession(requestCtx, session)
 
 	// --- Process Message(s) ---
 	bodyBytes, bodyErr := io.ReadAll(r.Body)
@@ -31,12 +40,24 @@ func (t *Transport) handlePOST(w http.ResponseWriter, r *http.Request, logger *z
 	}
 	defer r.Body.Close()
 
-	msgs, err := shared.ParseMessages(session, bodyBytes)
+	// Batch items are parsed one by one: invalid items are answered with an error of their own
+	batch := shared.IsBatch(bodyBytes)
+	var msgs []*shared.Message
+	var invalidItems []shared.JSONRPCErrorResponse
+	if batch {
+		msgs, invalidItems, err = shared.ParseBatchItems(session, bodyBytes)
+	} else {
+		msgs, err = shared.ParseMessages(session, bodyBytes)
+	}
 	if err != nil {
 		logger.Error("Failed to parse JSON-RPC message(s)", zap.Error(err), zap.ByteString("body", bodyBytes))
 		sendJSONRPCErrorResponse(w, nil, shared.JSONRPCErrorParseError, "Invalid JSON", err.Error(), logger)
 		return
 	}
+	if batch && len(msgs) == 0 && len(invalidItems) == 0 {
+		sendJSONRPCErrorResponse(w, nil, shared.JSONRPCErrorInvalidRequest, "Empty batch", nil, logger)
+		return
+	}
 
 	// Determine the type of the first message to handle initialization correctly
 	isInitializeRequest := false
@@ -68,6 +89,7 @@ func (t *Transport) handlePOST(w http.ResponseWriter, r *http.Request, logger *z
 	for _, msg := range msgs {
 		msg.Session = session
 		msg.Timestamp = time.Now()
+		msg.SetContext(requestCtx)
 
 		// Check if this is a request (has ID and Method)
 		if msg.Method != nil && msg.ID != nil && !msg.ID.IsEmpty() {
@@ -82,8 +104,12 @@ func (t *Transport) handlePOST(w http.ResponseWriter, r *http.Request, logger *z
 		}
 	}
 
+	if len(invalidItems) > 0 {
+		logger.Warn("Batch contains invalid items", zap.String("sessionId", session.GetID()), zap.Int("invalidCount", len(invalidItems)))
+	}
+
 	// If the input consists solely of notifications or there are no messages expecting responses, return 202 Accepted
-	if len(requestIDs) == 0 {
+	if len(requestIDs) == 0 && len(invalidItems) == 0 {
 		w.WriteHeader(http.StatusAccepted)
 		logger.Debug("POST processed, returning 202 Accepted", zap.String("sessionId", session.GetID()), zap.Int("messageCount", len(msgs)))
 		return
@@ -100,15 +126,16 @@ func (t *Transport) handlePOST(w http.ResponseWriter, r *http.Request, logger *z
 
 	// Decide whether to respond with JSON or SSE
 	if clientAcceptsSSE {
-		t.responseToStream(w, r, session, logger, requestIDs) // Keep stream open
+		t.responseToStream(w, r, session, logger, requestIDs, invalidItems) // Keep stream open
 		logger.Info("SSE connection handler finished", zap.String("sessionId", session.GetID()))
 	} else {
-		t.responseAndCloseConnection(w, r, session, logger, requestIDs)
+		t.responseAndCloseConnection(w, r, session, logger, requestIDs, batch, invalidItems)
 	}
 }
 
 // responseAndCloseConnection handles sending JSON response for V2025 POST requests.
-func (t *Transport) responseAndCloseConnection(w http.ResponseWriter, r *http.Request, session shared.ISession, logger *zap.Logger, requestIDs []*schema.RequestID) {
+// A batch is answered with an array, holding the errors of its invalid items first.
+func (t *Transport) responseAndCloseConnection(w http.ResponseWriter, r *http.Request, session shared.ISession, logger *zap.Logger, requestIDs []*schema.RequestID, batch bool, invalidItems []shared.JSONRPCErrorResponse) {
 	// Set necessary headers
 	w.Header().Set("Content-Type", "application/json")
 	w.Header().Set("Cache-Control", "no-cache")
@@ -120,7 +147,12 @@ func (t *Transport) responseAndCloseConnection(w http.ResponseWriter, r *http.Re
 	}
 
 	// Collect responses until all are received or timeout
-	responses := make([]interface{}, 0)
+	responses := make([]interface{}, 0, len(invalidItems)+len(requestIDs))
+	for _, invalid := range invalidItems {
+		responses = append(responses, invalid)
+	}
+	expected := len(invalidItems) + len(requestIDs)
+	var streamed *shared.Message
 	responseTimer := time.NewTimer(responseTimeout) // Use a timer for better control
 	defer responseTimer.Stop()
 
@@ -132,7 +164,7 @@ func (t *Transport) responseAndCloseConnection(w http.ResponseWriter, r *http.Re
 
 	// Collect responses loop
 collectLoop:
-	for {
+	for len(responses) < expected {
 		select {
 		case respMsg, ok := <-output:
 			if !ok {
@@ -145,6 +177,13 @@ collectLoop:
 				continue
 			}
 
+			// A single streamed result is written straight to the connection, anything else is buffered
+			if respMsg.Stream != nil && respMsg.Error == nil && !batch {
+				streamed = respMsg
+				break collectLoop
+			}
+			respMsg.BufferStream()
+
 			if respMsg.Error != nil {
 				// For error responses, add error response
 				logger.Debug("Adding error response", zap.Any("msgId", respMsg.ID), zap.Error(respMsg.Error))
@@ -183,11 +222,6 @@ collectLoop:
 				}
 			}
 
-			// If we have collected all the expected responses, we can send them immediately
-			if len(responses) >= len(requestIDs) {
-				break collectLoop
-			}
-
 		case <-responseTimer.C:
 			logger.Warn("Timeout waiting for response(s)", zap.String("sessionId", session.GetID()))
 			break collectLoop // Exit loop on timeout
@@ -201,8 +235,13 @@ collectLoop:
 	// Send responses
 	w.WriteHeader(http.StatusOK)
 
+	if streamed != nil {
+		writeStreamedResponse(w, streamed, logger)
+		return
+	}
+
 	// Check if it was a single request or a batch
-	if len(requestIDs) == 1 && len(responses) == 1 {
+	if !batch && len(responses) == 1 {
 		// Encode single response directly
 		if err := json.NewEncoder(w).Encode(responses[0]); err != nil {
 			logger.Error("Failed to encode single response", zap.Error(err))
@@ -215,8 +254,47 @@ collectLoop:
 	}
 }
 
+// writeStreamedResponse writes a JSON-RPC response whose result comes from a StreamingHandler.
+// Every write is flushed, so the body goes out with chunked transfer encoding instead of being
+// buffered. Headers are already sent, so a failing stream can only be logged and cut short.
+func writeStreamedResponse(w http.ResponseWriter, msg *shared.Message, logger *zap.Logger) {
+	id, err := json.Marshal(msg.ID)
+	if err != nil {
+		logger.Error("Failed to encode response ID", zap.Error(err))
+		return
+	}
+	body := io.Writer(w)
+	if flusher, ok := w.(http.Flusher); ok {
+		body = &flushWriter{w: w, flusher: flusher}
+	}
+	if _, err := fmt.Fprintf(body, `{"jsonrpc":"2.0","id":%s,"result":`, id); err != nil {
+		logger.Error("Failed to write streamed response", zap.Error(err))
+		return
+	}
+	if err := msg.Stream.StreamResult(body); err != nil {
+		logger.Error("Failed to stream result, response is incomplete", zap.Any("msgId", msg.ID), zap.Error(err))
+		return
+	}
+	if _, err := io.WriteString(body, "}\n"); err != nil {
+		logger.Error("Failed to write streamed response", zap.Error(err))
+	}
+}
+
+// flushWriter flushes the response after every write.
+type flushWriter struct {
+	w       io.Writer
+	flusher http.Flusher
+}
+
+func (f *flushWriter) Write(p []byte) (int, error) {
+	n, err := f.w.Write(p)
+	f.flusher.Flush()
+	return n, err
+}
+
 // responseToStream handles streaming responses via SSE for V2025 POST requests.
-func (t *Transport) responseToStream(w http.ResponseWriter, r *http.Request, session shared.ISession, logger *zap.Logger, requestIDs []*schema.RequestID) {
+// The errors of invalid batch items are sent first.
+func (t *Transport) responseToStream(w http.ResponseWriter, r *http.Request, session shared.ISession, logger *zap.Logger, requestIDs []*schema.RequestID, invalidItems []shared.JSONRPCErrorResponse) {
 	flusher, ok := w.(http.Flusher)
 	if !ok {
 		logger.Error("Streaming unsupported for SSE", zap.String("sessionId", session.GetID()))
@@ -241,8 +319,22 @@ func (t *Transport) responseToStream(w http.ResponseWriter, r *http.Request, ses
 	w.WriteHeader(http.StatusOK)
 	flusher.Flush()
 
-	ticker := time.NewTicker(15 * time.Second) // Keepalive ticker // TODO: Make configurable
-	defer ticker.Stop()
+	eventID := time.Now().UnixNano() // Initial event ID for resumability
+	for _, invalid := range invalidItems {
+		eventData, err := json.Marshal(invalid)
+		if err != nil {
+			logger.Error("Failed to marshal SSE event data", zap.Error(err))
+			continue
+		}
+		shared.FlushIfNotDone(logger, r, w, "id: %d\ndata: %s\n\n", eventID, eventData)
+		eventID++
+	}
+	if len(pendingRequests) == 0 {
+		return
+	}
+
+	keepAlive, stopKeepAlive := t.sseKeepAlive(logger)
+	defer stopKeepAlive()
 	defer logger.Debug("Exiting responseToStream goroutine", zap.String("sessionId", session.GetID()))
 
 	closeSSE := make(chan struct{})
@@ -258,7 +350,6 @@ func (t *Transport) responseToStream(w http.ResponseWriter, r *http.Request, ses
 		defer close(closeSSE)
 		ctx := r.Context() // Store the context for checking cancellation
 
-		eventID := time.Now().UnixNano() // Initial event ID for resumability
 		for {
 			select {
 			case <-ctx.Done(): // Use the handler's context for cancellation
@@ -276,6 +367,7 @@ func (t *Transport) responseToStream(w http.ResponseWriter, r *http.Request, ses
 					logger.Error("Received nil message from session output channel", zap.String("sessionId", session.GetID()))
 					continue
 				}
+				msg.BufferStream()
 
 				// Process the message based on ID
 				if msg.ID != nil {
@@ -341,9 +433,8 @@ func (t *Transport) responseToStream(w http.ResponseWriter, r *http.Request, ses
 
 					return
 				}
-			case <-ticker.C:
-				// Send keepalive ping event
-				shared.FlushIfNotDone(logger, r, w, "event: %s\ndata: {}\n\n", sseEventPing)
+			case <-keepAlive:
+				shared.FlushIfNotDone(logger, r, w, sseKeepAliveComment)
 			}
 		}
 	}()
@@ -0,0 +1,31 @@
+package transport
+
+import (
+	"net/http"
+	"strings"
+
+	"go.uber.org/zap"
+)
+
+// originAllowed reports whether r may proceed. Only cross-site browser requests
+// (Sec-Fetch-Site: cross-site) with an Origin header are checked; non-browser clients
+// send neither header and are always allowed.
+func (t *Transport) originAllowed(r *http.Request, logger *zap.Logger) bool {
+	origin := r.Header.Get("Origin")
+	if origin == "" || r.Header.Get("Sec-Fetch-Site") != "cross-site" {
+		return true
+	}
+	allowed, err := t.config.AllowedOrigins()
+	if err != nil {
+		logger.Error("Failed to get allowed origins from config", zap.Error(err))
+		return false
+	}
+	origin = strings.TrimSuffix(origin, "/")
+	for _, a := range allowed {
+		if a == "*" || strings.EqualFold(strings.TrimSuffix(a, "/"), origin) {
+			return true
+		}
+	}
+	logger.Warn("Rejected cross-site request from untrusted origin", zap.String("origin", origin), zap.String("remoteAddr", r.RemoteAddr))
+	return false
+}
@@ -16,6 +16,7 @@ import (
 type ISessionManager interface {
 	CreateSession(userID string, id string, params *sync.Map) shared.ISession
 	GetSession(id string) (shared.ISession, error)
+	GetSessions() []shared.ISession
 	CloseSession(id string)
 	CloseAllSessions()
 	GetLogger() *zap.Logger
@@ -27,6 +28,21 @@ type ISessionManager interface {
 
 var _ ISessionManager = (*Manager)(nil)
 
+// SessionObserver is notified when the manager creates or drops a session, e.g. to export
+// the number of active sessions. Calls are made with the manager locked and must not block.
+type SessionObserver interface {
+	SessionCreated(session shared.ISession)
+	SessionClosed(session shared.ISession)
+}
+
+// SessionCloseListener is implemented by capabilities that keep state per session, e.g.
+// resource subscriptions, to drop it when the session closes. AddCapability registers the
+// capabilities implementing it; OnSessionClose is called once the session has been removed,
+// without the manager locked.
+type SessionCloseListener interface {
+	OnSessionClose(sessionID string)
+}
+
 // Manager handles all active sessions
 type Manager struct {
 	sessions       map[string]*Session
@@ -34,6 +50,8 @@ type Manager struct {
 	logger         *zap.Logger
 	ServerInfo     schema.Implementation
 	inputProcessor *shared.Input
+	observers      []SessionObserver      // Added by AddSessionObserver
+	closeListeners []SessionCloseListener // Capabilities added by AddCapability that implement it
 }
 
 // Input returns the manager's input processor.
@@ -92,6 +110,12 @@ func (m *Manager) AddCapability(capabilities ...shared.ICapability) {
 			m.inputProcessor.AddClientCapability(clientCap)
 		} else {
 			m.logger.Warn("Unknown capability type, cannot add", zap.String("type", fmt.Sprintf("%T", cap)))
+			continue
+		}
+		if listener, ok := cap.(SessionCloseListener); ok {
+			m.mu.Lock()
+			m.closeListeners = append(m.closeListeners, listener)
+			m.mu.Unlock()
 		}
 	}
 }
@@ -101,8 +125,11 @@ func (m *Manager) CreateSession(userID string, id string, params *sync.Map) shar
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
-	session := NewSession(m, userID, id, m.inputProcessor, params)
+	session := NewSession(m, id, userID, m.inputProcessor, params)
 	m.sessions[session.ID] = session
+	for _, observer := range m.observers {
+		observer.SessionCreated(session)
+	}
 
 	m.logger.Debug("Created new session",
 		zap.String("sessionID", session.ID),
@@ -126,24 +153,39 @@ func (m *Manager) GetSession(id string) (shared.ISession, error) {
 	return session, nil
 }
 
+// GetSessions returns a snapshot of all active sessions
+func (m *Manager) GetSessions() []shared.ISession {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	sessions := make([]shared.ISession, 0, len(m.sessions))
+	for _, session := range m.sessions {
+		sessions = append(sessions, session)
+	}
+	return sessions
+}
+
 // RemoveSession removes a session reference without calling Close.
 // Used by transport on disconnect detection.
 func (m *Manager) RemoveSession(id string) {
 	m.mu.Lock()
-	defer m.mu.Unlock()
-
-	_, exists := m.sessions[id]
+	session, exists := m.sessions[id]
 	if exists {
 		delete(m.sessions, id)
+		m.notifySessionClosed(session)
 		m.logger.Debug("Removed session reference", zap.String("sessionID", id))
 	}
+	listeners := m.closeListeners
+	m.mu.Unlock()
+
+	if exists {
+		notifySessionCloseListeners(listeners, id)
+	}
 }
 
 // CloseSession removes a session and cleans up resources
 func (m *Manager) CloseSession(id string) {
 	m.mu.Lock()
-	defer m.mu.Unlock()
-
 	session, exists := m.sessions[id]
 	if exists {
 		// Close the session resources
@@ -152,10 +194,17 @@ func (m *Manager) CloseSession(id string) {
 			m.logger.Error("Error closing session resources", zap.String("sessionID", id), zap.Error(err))
 		}
 		delete(m.sessions, id)
+		m.notifySessionClosed(session)
 		m.logger.Info("Closed session", zap.String("sessionID", id))
 	} else {
 		m.logger.Warn("Attempted to close non-existent session", zap.String("sessionID", id))
 	}
+	listeners := m.closeListeners
+	m.mu.Unlock()
+
+	if exists {
+		notifySessionCloseListeners(listeners, id)
+	}
 }
 
 func (m *Manager) CloseAllSessions() {
@@ -206,6 +255,28 @@ func (m *Manager) NotifyEligibleSessions(method string, params map[string]any) {
 	}
 }
 
+// AddSessionObserver registers observers of session creation and removal. Sessions that
+// already exist are not reported.
+func (m *Manager) AddSessionObserver(observers ...SessionObserver) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.observers = append(m.observers, observers...)
+}
+
+// notifySessionClosed reports a removed session to the observers. The caller holds m.mu.
+func (m *Manager) notifySessionClosed(session *Session) {
+	for _, observer := range m.observers {
+		observer.SessionClosed(session)
+	}
+}
+
+// notifySessionCloseListeners tells the listeners that the session was removed.
+func notifySessionCloseListeners(listeners []SessionCloseListener, sessionID string) {
+	for _, listener := range listeners {
+		listener.OnSessionClose(sessionID)
+	}
+}
+
 func (m *Manager) AddValidator(validators ...shared.MessageValidator) {
 	m.inputProcessor.AddValidator(validators...)
 }
@@ -14,6 +14,7 @@ type IDownstreamSession interface {
 }
 
 var _ IDownstreamSession = (*Session)(nil)
+var _ shared.UserSession = (*Session)(nil)
 
 // Session represents a client connection session
 type Session struct {
@@ -37,6 +38,11 @@ func NewSession(manager ISessionManager, id string, userID string, inputProcesso
 	}
 }
 
+// GetUserID returns the user the session belongs to, empty for anonymous sessions.
+func (s *Session) GetUserID() string {
+	return s.UserID
+}
+
 func (s *Session) Close() error {
 	//TODO add close all backend server connections
 	logger := s.BaseSession.Logger
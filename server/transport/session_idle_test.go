@@ -0,0 +1,148 @@
+package transport_test
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gate4ai/gate4ai/server/transport"
+	"github.com/gate4ai/gate4ai/shared/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestIdleSessionPingAndClose(t *testing.T) {
+	logger := zap.NewNop()
+	cfg := config.NewInternalConfig()
+	manager := NewMockMCPManager(cfg, logger)
+
+	idle, grace := 100*time.Millisecond, 200*time.Millisecond
+	tr, err := transport.New(manager, logger, cfg, transport.WithIdleSessionTimeout(idle, grace))
+	require.NoError(t, err)
+	defer tr.Close()
+	// The sweeper runs every min(idle, grace)/2, so pings and closes may come that much early
+	margin := idle / 2
+
+	session := manager.CreateSession("user", "idle-session", &sync.Map{})
+	output, ok := session.AcquireOutput()
+	require.True(t, ok)
+	start := session.GetLastInboundActivity()
+
+	// The client stays silent, so the first thing it receives must be the ping
+	select {
+	case msg, ok := <-output:
+		require.True(t, ok, "session closed before the ping was sent")
+		require.NotNil(t, msg.Method)
+		assert.Equal(t, "notifications/ping", *msg.Method)
+		assert.GreaterOrEqual(t, time.Since(start), idle-margin)
+	case <-time.After(2 * time.Second):
+		t.Fatal("ping notification was not sent")
+	}
+
+	// Without a reply the session is closed once the grace period is over
+	select {
+	case _, ok := <-output:
+		assert.False(t, ok, "expected the output channel to be closed")
+	case <-time.After(2 * time.Second):
+		t.Fatal("idle session was not closed")
+	}
+	assert.GreaterOrEqual(t, time.Since(start), idle+grace-margin)
+
+	manager.mu.RLock()
+	closed := manager.ClosedSessions["idle-session"]
+	manager.mu.RUnlock()
+	assert.True(t, closed)
+}
+
+func TestIdleSessionActivityCancelsClose(t *testing.T) {
+	logger := zap.NewNop()
+	cfg := config.NewInternalConfig()
+	manager := NewMockMCPManager(cfg, logger)
+
+	tr, err := transport.New(manager, logger, cfg, transport.WithIdleSessionTimeout(100*time.Millisecond, 200*time.Millisecond))
+	require.NoError(t, err)
+	defer tr.Close()
+
+	session := manager.CreateSession("user", "active-session", &sync.Map{})
+	output, ok := session.AcquireOutput()
+	require.True(t, ok)
+
+	// Answer each ping by recording activity, as an incoming message would
+	deadline := time.After(700 * time.Millisecond)
+	for {
+		select {
+		case msg, ok := <-output:
+			require.True(t, ok, "active session must not be closed")
+			require.NotNil(t, msg.Method)
+			time.Sleep(5 * time.Millisecond)
+			session.UpdateLastInboundActivity()
+		case <-deadline:
+			_, err := manager.GetSession("active-session")
+			assert.NoError(t, err)
+			return
+		}
+	}
+}
+
+func TestIdleSessionReceivingOnlyNotificationsIsClosed(t *testing.T) {
+	logger := zap.NewNop()
+	cfg := config.NewInternalConfig()
+	manager := NewMockMCPManager(cfg, logger)
+
+	tr, err := transport.New(manager, logger, cfg, transport.WithIdleSessionTimeout(100*time.Millisecond, 200*time.Millisecond))
+	require.NoError(t, err)
+	defer tr.Close()
+
+	session := manager.CreateSession("user", "listening-session", &sync.Map{})
+	output, ok := session.AcquireOutput()
+	require.True(t, ok)
+
+	// The server keeps sending, the client never answers
+	stop := make(chan struct{})
+	defer close(stop)
+	go func() {
+		ticker := time.NewTicker(20 * time.Millisecond)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				session.SendNotification("notifications/message", nil)
+			case <-stop:
+				return
+			}
+		}
+	}()
+
+	deadline := time.After(2 * time.Second)
+	for {
+		select {
+		case _, ok := <-output:
+			if !ok {
+				return // Closed although messages were sent to it
+			}
+		case <-deadline:
+			t.Fatal("session that only receives notifications was not closed")
+		}
+	}
+}
+
+func TestTransportCloseStopsIdleSweeper(t *testing.T) {
+	logger := zap.NewNop()
+	cfg := config.NewInternalConfig()
+	manager := NewMockMCPManager(cfg, logger)
+
+	tr, err := transport.New(manager, logger, cfg, transport.WithIdleSessionTimeout(50*time.Millisecond, 50*time.Millisecond))
+	require.NoError(t, err)
+	tr.Close()
+	tr.Close() // Closing again is harmless
+
+	session := manager.CreateSession("user", "unswept-session", &sync.Map{})
+	output, ok := session.AcquireOutput()
+	require.True(t, ok)
+	select {
+	case msg := <-output:
+		t.Fatalf("closed transport still swept sessions, got %+v", msg)
+	case <-time.After(300 * time.Millisecond):
+	}
+}
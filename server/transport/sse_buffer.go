@@ -0,0 +1,100 @@
+package transport
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"sync"
+
+	"go.uber.org/zap"
+)
+
+// LAST_EVENT_ID_KEY2024 is the query parameter alternative to the Last-Event-ID header
+// for resuming a V2024 SSE stream.
+const LAST_EVENT_ID_KEY2024 = "last_event_id"
+
+const sseEventBufferKey = "sse_event_buffer"
+
+// WithSSEEventBuffer keeps the last n message events of each V2024 SSE stream so a client
+// reconnecting with Last-Event-ID (or ?last_event_id=) gets the events it missed. With
+// buffering enabled, a disconnected session stays open until the idle cleanup closes it.
+func WithSSEEventBuffer(n int) TransportOption {
+	return func(t *Transport) error {
+		if n < 0 {
+			return errors.New("SSE event buffer size must not be negative")
+		}
+		t.sseEventBufferSize = n
+		return nil
+	}
+}
+
+// sseEvent is a message event that was sent on a V2024 SSE stream.
+type sseEvent struct {
+	id   uint64
+	data []byte
+}
+
+// sseEventBuffer numbers the message events of one session and keeps the last ones in a ring.
+type sseEventBuffer struct {
+	mu     sync.Mutex
+	lastID uint64
+	ring   []sseEvent
+	head   int // Index of the oldest event
+	size   int
+}
+
+// add assigns the next event ID to data and buffers it.
+func (b *sseEventBuffer) add(data []byte) uint64 {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.lastID++
+	if len(b.ring) == 0 {
+		return b.lastID
+	}
+	event := sseEvent{id: b.lastID, data: data}
+	if b.size < len(b.ring) {
+		b.ring[(b.head+b.size)%len(b.ring)] = event
+		b.size++
+	} else {
+		b.ring[b.head] = event
+		b.head = (b.head + 1) % len(b.ring)
+	}
+	return b.lastID
+}
+
+// since returns the buffered events after lastEventID, oldest first. complete is false
+// if some of those events were already evicted.
+func (b *sseEventBuffer) since(lastEventID uint64) (events []sseEvent, complete bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	complete = lastEventID >= b.lastID-uint64(b.size)
+	for i := 0; i < b.size; i++ {
+		if event := b.ring[(b.head+i)%len(b.ring)]; event.id > lastEventID {
+			events = append(events, event)
+		}
+	}
+	return events, complete
+}
+
+// sseEventBufferFor returns the event buffer of a session, creating it on first use.
+func (t *Transport) sseEventBufferFor(sessionParams *sync.Map) *sseEventBuffer {
+	buffer, _ := sessionParams.LoadOrStore(sseEventBufferKey, &sseEventBuffer{ring: make([]sseEvent, t.sseEventBufferSize)})
+	return buffer.(*sseEventBuffer)
+}
+
+// lastEventID reads the ID of the last event a reconnecting client received.
+func lastEventID(r *http.Request, logger *zap.Logger) (uint64, bool) {
+	value := r.Header.Get("Last-Event-ID")
+	if value == "" {
+		value = r.URL.Query().Get(LAST_EVENT_ID_KEY2024)
+	}
+	if value == "" {
+		return 0, false
+	}
+	id, err := strconv.ParseUint(value, 10, 64)
+	if err != nil {
+		logger.Warn("Ignoring malformed last event ID", zap.String("lastEventId", value))
+		return 0, false
+	}
+	return id, true
+}
@@ -0,0 +1,29 @@
+package transport
+
+import (
+	"time"
+
+	"github.com/gate4ai/gate4ai/shared/config"
+	"go.uber.org/zap"
+)
+
+// sseKeepAliveComment is written to idle SSE streams. Clients ignore comment lines, but
+// proxies see traffic and keep the connection open.
+const sseKeepAliveComment = ": keepalive\n\n"
+
+// sseKeepAlive returns a channel ticking at the configured SSE keepalive interval and a func
+// releasing it. The channel is nil, so it never fires in a select, when keepalives are disabled.
+// The ticks are meant for the goroutine that writes the stream, so they never interleave
+// with its events.
+func (t *Transport) sseKeepAlive(logger *zap.Logger) (<-chan time.Time, func()) {
+	interval, err := t.config.SSEKeepAliveInterval()
+	if err != nil {
+		logger.Warn("Failed to read SSE keepalive interval, using the default", zap.Error(err))
+		interval = config.DefaultSSEKeepAliveInterval
+	}
+	if interval <= 0 {
+		return nil, func() {}
+	}
+	ticker := time.NewTicker(interval)
+	return ticker.C, ticker.Stop
+}
@@ -0,0 +1,165 @@
+package transport
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/gate4ai/gate4ai/shared"
+	"go.uber.org/zap"
+)
+
+// StdioTransport serves one MCP session over a reader and writer, normally the stdin and stdout
+// of a server launched as a subprocess by an MCP host. Each line read is a JSON-RPC message
+// (single or batched), and every message of the session is written as one line. The host
+// started the process, so the session is not authenticated. Nothing but JSON-RPC messages may
+// be written to the writer, so the logger must not write to stdout.
+type StdioTransport struct {
+	manager ISessionManager
+	logger  *zap.Logger
+	in      io.Reader
+	out     io.Writer
+}
+
+// NewStdioTransport creates a transport reading messages from in and writing them to out.
+func NewStdioTransport(manager ISessionManager, logger *zap.Logger, in io.Reader, out io.Writer) *StdioTransport {
+	return &StdioTransport{
+		manager: manager,
+		logger:  logger.With(zap.String("protocol", "stdio")),
+		in:      in,
+		out:     out,
+	}
+}
+
+// Run creates the session and serves it until the input ends, ctx is done or the session is
+// closed. It returns nil when the input ends normally.
+func (st *StdioTransport) Run(ctx context.Context) error {
+	session := st.manager.CreateSession("", "", &sync.Map{})
+	logger := st.logger.With(zap.String("sessionId", session.GetID()))
+	output, ok := session.AcquireOutput()
+	if !ok {
+		st.manager.CloseSession(session.GetID())
+		return errors.New("failed to acquire output channel for stdio session")
+	}
+	session.SetStatus(shared.StatusConnected)
+	logger.Info("Stdio session ready")
+
+	replies := make(chan []byte, 1)
+	readErr := make(chan error, 1)
+	writerDone := make(chan struct{})
+	go func() {
+		readErr <- st.readLoop(ctx, session, replies, writerDone, logger)
+	}()
+	go func() {
+		defer close(writerDone)
+		st.writeLoop(output, replies, logger)
+	}()
+
+	var err error
+	select {
+	case err = <-readErr:
+	case <-writerDone:
+	case <-ctx.Done():
+	}
+	// The reader may still be blocked on the input; it stops when the input is closed
+	session.ReleaseOutput()
+	st.manager.CloseSession(session.GetID())
+	<-writerDone // Closing the session closed its output
+	logger.Info("Stdio session closed")
+	return err
+}
+
+// readLoop passes the messages read to the session until the input ends. Replies the
+// transport itself must send, such as parse errors, go to replies. The messages carry ctx.
+func (st *StdioTransport) readLoop(ctx context.Context, session shared.ISession, replies chan<- []byte, writerDone <-chan struct{}, logger *zap.Logger) error {
+	reader := bufio.NewReader(st.in)
+	for {
+		line, err := reader.ReadBytes('\n')
+		if line = bytes.TrimSpace(line); len(line) > 0 {
+			session.UpdateLastInboundActivity()
+			if !st.handleLine(ctx, session, line, replies, writerDone, logger) {
+				return nil
+			}
+		}
+		if errors.Is(err, io.EOF) {
+			logger.Info("Stdio input closed")
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read stdio input: %w", err)
+		}
+	}
+}
+
+// handleLine parses one line and passes its messages to the session. It returns false if the
+// writer stopped.
+func (st *StdioTransport) handleLine(ctx context.Context, session shared.ISession, line []byte, replies chan<- []byte, writerDone <-chan struct{}, logger *zap.Logger) bool {
+	msgs, err := shared.ParseMessages(session, line)
+	if err != nil {
+		logger.Error("Failed to parse JSON-RPC message(s) from stdio", zap.Error(err), zap.ByteString("data", line))
+		reply, _ := json.Marshal(shared.JSONRPCErrorResponse{
+			JSONRPC: shared.JSONRPCVersion,
+			Error:   &shared.JSONRPCError{Code: shared.JSONRPCErrorParseError, Message: "Parse error: " + err.Error()},
+		})
+		select {
+		case replies <- reply:
+			return true
+		case <-writerDone:
+			return false
+		}
+	}
+	for _, msg := range msgs {
+		msg.Session = session
+		msg.Timestamp = time.Now()
+		msg.SetContext(ctx)
+		if handleErr := session.Input().Put(msg); handleErr != nil {
+			logger.Error("Error handling stdio message", zap.Error(handleErr), zap.Any("msgId", msg.ID))
+			if !msg.ID.IsEmpty() && !errors.Is(handleErr, shared.ErrInputBusy) { // Put answers dropped requests itself
+				session.SendResponse(msg.ID, nil, handleErr)
+			}
+		}
+	}
+	return true
+}
+
+// writeLoop writes the session output and the transport's own replies, one per line, until the
+// session output is closed or a write fails. It is the only writer of the output.
+func (st *StdioTransport) writeLoop(output <-chan *shared.Message, replies <-chan []byte, logger *zap.Logger) {
+	write := func(data []byte) bool {
+		if _, err := st.out.Write(append(data, '\n')); err != nil {
+			logger.Warn("Stdio write failed", zap.Error(err))
+			return false
+		}
+		return true
+	}
+	for {
+		select {
+		case msg, ok := <-output:
+			if !ok {
+				logger.Info("Session output channel closed")
+				return
+			}
+			if msg == nil {
+				continue
+			}
+			data, err := json.Marshal(msg)
+			if err != nil {
+				logger.Error("Failed to marshal message for stdio", zap.Error(err), zap.Any("msgId", msg.ID), zap.Stringp("method", msg.Method))
+				continue
+			}
+			if !write(data) {
+				return
+			}
+		case reply := <-replies:
+			if !write(reply) {
+				return
+			}
+		}
+	}
+}
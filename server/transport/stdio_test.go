@@ -0,0 +1,140 @@
+package transport_test
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/gate4ai/gate4ai/server/transport"
+	"github.com/gate4ai/gate4ai/shared"
+	"github.com/gate4ai/gate4ai/shared/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+type stdioTest struct {
+	manager *MockMCPManager
+	stdin   *io.PipeWriter
+	lines   chan string
+	done    chan error
+}
+
+// setupStdioTest runs a stdio transport on pipes; lines receives what it writes to stdout.
+func setupStdioTest(t *testing.T) *stdioTest {
+	t.Helper()
+	logger := zap.NewNop()
+	manager := NewMockMCPManager(config.NewInternalConfig(), logger)
+	manager.AddCapability(&MockTestCapability{})
+	inReader, inWriter := io.Pipe()
+	outReader, outWriter := io.Pipe()
+	st := &stdioTest{manager: manager, stdin: inWriter, lines: make(chan string, 10), done: make(chan error, 1)}
+
+	go func() {
+		st.done <- transport.NewStdioTransport(manager, logger, inReader, outWriter).Run(context.Background())
+		outWriter.Close()
+	}()
+	go func() {
+		defer close(st.lines)
+		scanner := bufio.NewScanner(outReader)
+		for scanner.Scan() {
+			st.lines <- scanner.Text()
+		}
+	}()
+	t.Cleanup(func() { inWriter.Close() })
+	return st
+}
+
+func (st *stdioTest) send(t *testing.T, line string) {
+	t.Helper()
+	_, err := io.WriteString(st.stdin, line+"\n")
+	require.NoError(t, err)
+}
+
+// next returns the next message written to stdout.
+func (st *stdioTest) next(t *testing.T) shared.Message {
+	t.Helper()
+	select {
+	case line, ok := <-st.lines:
+		require.True(t, ok, "stdout closed")
+		var msg shared.Message
+		require.NoError(t, json.Unmarshal([]byte(line), &msg), "stdout line %q", line)
+		return msg
+	case <-time.After(3 * time.Second):
+		t.Fatal("Timed out waiting for a message on stdout")
+		return shared.Message{}
+	}
+}
+
+// Requirement: Requests read from stdin are answered on stdout, one message per line.
+func Test_SRV_STDIO_POS_01_RoundTrip(t *testing.T) {
+	st := setupStdioTest(t)
+
+	st.send(t, createJsonRpcRequestBody(1, "test/method", map[string]string{"data": "test"}))
+	msg := st.next(t)
+	require.NotNil(t, msg.ID)
+	assert.Equal(t, "1", msg.ID.String())
+	require.Nil(t, msg.Error)
+	assert.JSONEq(t, `{"status":"ok"}`, string(*msg.Result))
+
+	// Batches are answered message by message
+	st.send(t, "["+createJsonRpcRequestBody(101, "test", nil)+","+createJsonRpcRequestBody(102, "test", nil)+"]")
+	seen := map[string]string{}
+	for i := 0; i < 2; i++ {
+		msg := st.next(t)
+		require.NotNil(t, msg.ID)
+		seen[msg.ID.String()] = string(*msg.Result)
+	}
+	assert.JSONEq(t, `{"clientId":1}`, seen["101"])
+	assert.JSONEq(t, `{"clientId":2}`, seen["102"])
+}
+
+// Requirement: The single stdio session receives server notifications.
+func Test_SRV_STDIO_POS_02_PushesServerNotifications(t *testing.T) {
+	st := setupStdioTest(t)
+
+	var sessions []shared.ISession
+	require.Eventually(t, func() bool {
+		sessions = st.manager.GetSessions()
+		return len(sessions) == 1
+	}, time.Second, 10*time.Millisecond)
+	sessions[0].SendNotification("notifications/message", map[string]any{"level": "info"})
+
+	msg := st.next(t)
+	require.NotNil(t, msg.Method)
+	assert.Equal(t, "notifications/message", *msg.Method)
+}
+
+// Requirement: Closing stdin ends the session and Run returns without error.
+func Test_SRV_STDIO_POS_03_ClosedInputEndsSession(t *testing.T) {
+	st := setupStdioTest(t)
+	require.Eventually(t, func() bool { return len(st.manager.GetSessions()) == 1 }, time.Second, 10*time.Millisecond)
+
+	require.NoError(t, st.stdin.Close())
+	select {
+	case err := <-st.done:
+		require.NoError(t, err)
+	case <-time.After(3 * time.Second):
+		t.Fatal("Run did not return after stdin was closed")
+	}
+	assert.Empty(t, st.manager.GetSessions())
+}
+
+// Requirement: Malformed lines get a JSON-RPC parse error and the session stays usable.
+func Test_SRV_STDIO_NEG_01_MalformedLine(t *testing.T) {
+	st := setupStdioTest(t)
+
+	st.send(t, `{not json`)
+	msg := st.next(t)
+	require.NotNil(t, msg.Error)
+	assert.Equal(t, shared.JSONRPCErrorParseError, msg.Error.Code)
+
+	st.send(t, createJsonRpcRequestBody(2, "test/method", nil))
+	msg = st.next(t)
+	require.NotNil(t, msg.ID)
+	assert.Equal(t, "2", msg.ID.String())
+	assert.NotNil(t, msg.Result)
+}
@@ -0,0 +1,57 @@
+package transport
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gate4ai/gate4ai/shared"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+)
+
+// tracerName is the instrumentation scope of the spans created by this package.
+const tracerName = "github.com/gate4ai/gate4ai/server/transport"
+
+// SpanPOST is the span of one POST request on the MCP or A2A endpoints. It continues the W3C
+// trace context of the request headers, and capabilities find it in the Context of each message.
+const SpanPOST = "transport.post"
+
+// WithTracerProvider records request spans with tp instead of the global OpenTelemetry
+// tracer provider.
+func WithTracerProvider(tp trace.TracerProvider) TransportOption {
+	return func(t *Transport) error {
+		t.tracerProvider = tp
+		return nil
+	}
+}
+
+// tracer returns the tracer for request spans. Without a provider set by WithTracerProvider
+// or installed globally, it is a no-op tracer.
+func (t *Transport) tracer() trace.Tracer {
+	if t.tracerProvider != nil {
+		return t.tracerProvider.Tracer(tracerName)
+	}
+	return otel.GetTracerProvider().Tracer(tracerName)
+}
+
+// tracePOST runs handle in a SpanPOST span for the protocol.
+func (t *Transport) tracePOST(w http.ResponseWriter, r *http.Request, logger *zap.Logger, protocol string, handle func(http.ResponseWriter, *http.Request, *zap.Logger)) {
+	ctx := propagation.TraceContext{}.Extract(r.Context(), propagation.HeaderCarrier(r.Header))
+	ctx, span := t.tracer().Start(ctx, SpanPOST,
+		trace.WithSpanKind(trace.SpanKindServer),
+		trace.WithAttributes(
+			attribute.String("protocol", protocol),
+			attribute.String("http.path", r.URL.Path),
+		),
+	)
+	defer span.End()
+	handle(w, r.WithContext(ctx), logger)
+}
+
+// traceSession adds the session to the request span.
+func traceSession(ctx context.Context, session shared.ISession) {
+	trace.SpanFromContext(ctx).SetAttributes(attribute.String("session_id", session.GetID()))
+}
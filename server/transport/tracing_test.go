@@ -0,0 +1,171 @@
+package transport_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/gate4ai/gate4ai/server/transport"
+	"github.com/gate4ai/gate4ai/shared"
+	schema2025 "github.com/gate4ai/gate4ai/shared/mcp/2025/schema"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	remoteTraceID = "4bf92f3577b34da6a3ce929d0e0e4736"
+	remoteSpanID  = "00f067aa0ba902b7"
+)
+
+func initializeRequestBody() string {
+	return createJsonRpcRequestBody(1, "initialize", schema2025.InitializeRequestParams{
+		ProtocolVersion: schema2025.PROTOCOL_VERSION,
+		ClientInfo:      schema2025.Implementation{Name: "test-client", Version: "1.0"},
+	})
+}
+
+// endedSpans waits for the request span, which ends after the response is written.
+func endedSpans(t *testing.T, recorder *tracetest.SpanRecorder) []sdktrace.ReadOnlySpan {
+	t.Helper()
+	require.Eventually(t, func() bool { return len(recorder.Ended()) == 1 }, time.Second, 10*time.Millisecond)
+	return recorder.Ended()
+}
+
+// Requirement: A POST continues the W3C trace context of its headers in a server span.
+func Test_SRV_TRACE_POS_01_PostContinuesRemoteTrace(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	tp, _, _, server, cleanup := setupServerTest(t, transport.WithTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))))
+	defer cleanup()
+	tp.NoStream2025 = true
+
+	resp, err := makePostRequest(t, server.URL+transport.MCP2025_PATH, initializeRequestBody(), map[string]string{
+		"traceparent": "00-" + remoteTraceID + "-" + remoteSpanID + "-01",
+	})
+	require.NoError(t, err)
+	resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	spans := endedSpans(t, recorder)
+	span := spans[0]
+	assert.Equal(t, transport.SpanPOST, span.Name())
+	assert.Equal(t, trace.SpanKindServer, span.SpanKind())
+	assert.Equal(t, remoteTraceID, span.SpanContext().TraceID().String())
+	assert.Equal(t, remoteSpanID, span.Parent().SpanID().String())
+	assert.True(t, span.Parent().IsRemote())
+	assert.Contains(t, span.Attributes(), attribute.String("protocol", "MCP2025"))
+	assert.Contains(t, span.Attributes(), attribute.String("session_id", resp.Header.Get(transport.MCP_SESSION_HEADER)))
+}
+
+// Requirement: Without a traceparent header the request span starts a new trace.
+func Test_SRV_TRACE_POS_02_PostWithoutTraceContextIsRoot(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	tp, _, _, server, cleanup := setupServerTest(t, transport.WithTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))))
+	defer cleanup()
+	tp.NoStream2025 = true
+
+	resp, err := makePostRequest(t, server.URL+transport.MCP2025_PATH, initializeRequestBody(), nil)
+	require.NoError(t, err)
+	resp.Body.Close()
+
+	spans := endedSpans(t, recorder)
+	assert.False(t, spans[0].Parent().IsValid())
+	assert.True(t, spans[0].SpanContext().IsValid())
+}
+
+// Requirement: Malformed trace context is ignored rather than failing the request.
+func Test_SRV_TRACE_NEG_01_MalformedTraceparentIsIgnored(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	tp, _, _, server, cleanup := setupServerTest(t, transport.WithTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))))
+	defer cleanup()
+	tp.NoStream2025 = true
+
+	resp, err := makePostRequest(t, server.URL+transport.MCP2025_PATH, initializeRequestBody(), map[string]string{"traceparent": "not-a-trace"})
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	spans := endedSpans(t, recorder)
+	assert.False(t, spans[0].Parent().IsValid())
+}
+
+// traceIDCapability reports the trace ID of the context each message arrives with. The
+// test/slow notification waits for release and then sends its trace ID to slowTraceID.
+type traceIDCapability struct {
+	entered     chan struct{}
+	release     chan struct{}
+	slowTraceID chan string
+}
+
+func (c *traceIDCapability) GetHandlers() map[string]func(*shared.Message) (interface{}, error) {
+	return map[string]func(*shared.Message) (interface{}, error){
+		"test/fast": func(msg *shared.Message) (interface{}, error) {
+			return map[string]string{"traceId": trace.SpanContextFromContext(msg.Context()).TraceID().String()}, nil
+		},
+		"test/slow": func(msg *shared.Message) (interface{}, error) {
+			close(c.entered)
+			<-c.release
+			c.slowTraceID <- trace.SpanContextFromContext(msg.Context()).TraceID().String()
+			return nil, nil
+		},
+	}
+}
+
+func (c *traceIDCapability) SetCapabilities(s *schema2025.ServerCapabilities) {}
+func (c *traceIDCapability) SetEventBus(bus shared.EventBus)                  {}
+
+// Requirement: Each message carries the context of the POST that delivered it, even when
+// messages on the same session are handled concurrently.
+func Test_SRV_TRACE_POS_03_OverlappingRequestsKeepTheirContext(t *testing.T) {
+	tp, manager, _, server, cleanup := setupServerTest(t)
+	defer cleanup()
+	tp.NoStream2025 = true
+	capability := &traceIDCapability{entered: make(chan struct{}), release: make(chan struct{}), slowTraceID: make(chan string, 1)}
+	manager.AddCapability(capability)
+
+	resp, err := makePostRequest(t, server.URL+transport.MCP2025_PATH, initializeRequestBody(), nil)
+	require.NoError(t, err)
+	resp.Body.Close()
+	sessionID := resp.Header.Get(transport.MCP_SESSION_HEADER)
+	require.NotEmpty(t, sessionID)
+	traceHeaders := func(traceID string) map[string]string {
+		return map[string]string{
+			transport.MCP_SESSION_HEADER: sessionID,
+			"traceparent":                "00-" + traceID + "-" + remoteSpanID + "-01",
+		}
+	}
+
+	const slowTraceID, fastTraceID = "11111111111111111111111111111111", "22222222222222222222222222222222"
+	// A notification is answered with 202 while its handler keeps running
+	resp, err = makePostRequest(t, server.URL+transport.MCP2025_PATH, `{"jsonrpc":"2.0","method":"test/slow"}`, traceHeaders(slowTraceID))
+	require.NoError(t, err)
+	resp.Body.Close()
+	select {
+	case <-capability.entered:
+	case <-time.After(time.Second):
+		t.Fatal("test/slow was not handled")
+	}
+
+	resp, err = makePostRequest(t, server.URL+transport.MCP2025_PATH, createJsonRpcRequestBody(2, "test/fast", nil), traceHeaders(fastTraceID))
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	var response struct {
+		Result struct {
+			TraceID string `json:"traceId"`
+		} `json:"result"`
+	}
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&response))
+	assert.Equal(t, fastTraceID, response.Result.TraceID)
+
+	close(capability.release)
+	select {
+	case traceID := <-capability.slowTraceID:
+		assert.Equal(t, slowTraceID, traceID, "test/slow saw the context of the later request")
+	case <-time.After(time.Second):
+		t.Fatal("test/slow did not finish")
+	}
+}
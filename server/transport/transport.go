@@ -4,7 +4,9 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"mime"
 	"net/http"
+	"strings"
 	"sync"
 	"time"
 
@@ -12,6 +14,7 @@ import (
 	a2aSchema "github.com/gate4ai/gate4ai/shared/a2a/2025-draft/schema"
 	"github.com/gate4ai/gate4ai/shared/config"
 	"github.com/gate4ai/gate4ai/shared/mcp/2025/schema"
+	"go.opentelemetry.io/otel/trace"
 	"go.uber.org/zap"
 )
 
@@ -21,20 +24,30 @@ const (
 	A2A_PATH           = "/a2a"           // Dedicated path for A2A protocol
 	MCP2024_PATH       = "/sse"           // Unified endpoint path for V2024 (for V2024 compatibility)
 	MCP2025_PATH       = "/mcp"           // Unified endpoint path
+	WS_PATH            = "/ws"            // MCP over WebSocket (see WebSocketTransport)
 	MCP_SESSION_HEADER = "Mcp-Session-Id" // Header for session ID
 
 	// Content Types
 	contentTypeJSON = "application/json"
 
 	// HTTP Statuses
-	statusAccepted            = http.StatusAccepted            // 202
-	statusNotFound            = http.StatusNotFound            // 404
-	statusBadRequest          = http.StatusBadRequest          // 400
-	statusMethodNotAllowed    = http.StatusMethodNotAllowed    // 405
-	statusUnauthorized        = http.StatusUnauthorized        // 401
-	statusInternalServerError = http.StatusInternalServerError // 500
+	statusAccepted            = http.StatusAccepted             // 202
+	statusNotFound            = http.StatusNotFound             // 404
+	statusBadRequest          = http.StatusBadRequest           // 400
+	statusMethodNotAllowed    = http.StatusMethodNotAllowed     // 405
+	statusUnauthorized        = http.StatusUnauthorized         // 401
+	statusUnsupportedMedia    = http.StatusUnsupportedMediaType // 415
+	statusInternalServerError = http.StatusInternalServerError  // 500
 )
 
+// wrapHandler applies handlerWrapper to h, if set.
+func (t *Transport) wrapHandler(h http.HandlerFunc) http.HandlerFunc {
+	if t.handlerWrapper == nil {
+		return h
+	}
+	return t.handlerWrapper(h)
+}
+
 var responseTimeout = 600 * time.Second // Default timeout for waiting on responses
 
 // Transport manages MCP HTTP connections supporting multiple protocol versions.
@@ -47,6 +60,24 @@ type Transport struct {
 	NoStream2025    bool          // Whether server supports streaming responses in V2
 	sessionTimeout  time.Duration // Idle timeout for sessions
 	cleanupInterval time.Duration // How often to check for idle sessions
+	// Ping idle sessions before closing them (disabled when idleTimeout is 0)
+	idleTimeout    time.Duration
+	idleGrace      time.Duration
+	pingedMu       sync.Mutex
+	pingedSessions map[string]time.Time // sessionID -> when the ping was sent
+	// Closed by Close to stop the background routines
+	done      chan struct{}
+	closeOnce sync.Once
+	// IDs of server-initiated requests; nil keeps the session default (incrementing uint64)
+	requestIDGenerator shared.RequestIDGenerator
+	// Downgrades responses on the V2024 SSE transport
+	compat2024 *BackwardCompatibilityTransformer
+	// Message events kept per V2024 SSE stream for Last-Event-ID replay, set by WithSSEEventBuffer
+	sseEventBufferSize int
+	// Wraps the protocol handlers, set by debugging options such as WithBodyLogging
+	handlerWrapper func(http.HandlerFunc) http.HandlerFunc
+	// Tracer provider for request spans, set by WithTracerProvider; nil uses the global provider
+	tracerProvider trace.TracerProvider
 }
 
 // TransportOption defines a function type for configuring the Transport.
@@ -82,6 +113,31 @@ func WithCleanupInterval(interval time.Duration) TransportOption {
 	}
 }
 
+// WithIdleSessionTimeout sends "notifications/ping" to sessions that have been idle for `idle`
+// and closes them if they show no activity within the following `grace` period.
+func WithIdleSessionTimeout(idle time.Duration, grace time.Duration) TransportOption {
+	return func(t *Transport) error {
+		if idle <= 0 || grace <= 0 {
+			return errors.New("idle timeout and grace period must be positive")
+		}
+		t.idleTimeout = idle
+		t.idleGrace = grace
+		return nil
+	}
+}
+
+// WithRequestIDGenerator sets how sessions created by the transport generate the IDs of
+// requests they send to clients (e.g. sampling). See shared.UUIDRequestIDGenerator.
+func WithRequestIDGenerator(gen func() interface{}) TransportOption {
+	return func(t *Transport) error {
+		if gen == nil {
+			return errors.New("request ID generator must not be nil")
+		}
+		t.requestIDGenerator = gen
+		return nil
+	}
+}
+
 // New creates a new MCP HTTP transport handler.
 func New(mcpManager ISessionManager, logger *zap.Logger, cfg config.IConfig, options ...TransportOption) (*Transport, error) {
 	if logger == nil {
@@ -115,6 +171,9 @@ func New(mcpManager ISessionManager, logger *zap.Logger, cfg config.IConfig, opt
 		},
 		cleanupInterval: 5 * time.Minute,  // Default cleanup interval
 		sessionTimeout:  30 * time.Minute, // Default session timeout
+		pingedSessions:  make(map[string]time.Time),
+		done:            make(chan struct{}),
+		compat2024:      NewBackwardCompatibilityTransformer(logger),
 	}
 
 	// Apply configuration options
@@ -128,6 +187,9 @@ func New(mcpManager ISessionManager, logger *zap.Logger, cfg config.IConfig, opt
 	if transport.sessionTimeout > 0 { // TODO: Move cleanup to session manager?
 		go transport.startSessionCleanup()
 	}
+	if transport.idleTimeout > 0 {
+		go transport.startIdleSessionSweeper()
+	}
 
 	logger.Info("MCP HTTP Transport created",
 		zap.Bool("streamingSupport2025", transport.NoStream2025),
@@ -137,6 +199,12 @@ func New(mcpManager ISessionManager, logger *zap.Logger, cfg config.IConfig, opt
 	return transport, nil
 }
 
+// Close stops the background routines of the transport: session cleanup and the idle session
+// sweeper. Sessions stay open; close them through the session manager.
+func (t *Transport) Close() {
+	t.closeOnce.Do(func() { close(t.done) })
+}
+
 // SetAuthManager allows changing the authentication manager.
 func (t *Transport) SetAuthManager(authManager AuthenticationManager) {
 	t.authManager = authManager
@@ -144,26 +212,15 @@ func (t *Transport) SetAuthManager(authManager AuthenticationManager) {
 
 // RegisterMCPHandlers registers only the MCP protocol handlers.
 func (t *Transport) RegisterMCPHandlers(mux *http.ServeMux) {
-	mux.HandleFunc(MCP2024_PATH, t.Handle2024MCP())
-	mux.HandleFunc(MCP2025_PATH, t.HandleMCP())
+	mux.HandleFunc(MCP2024_PATH, t.wrapHandler(t.Handle2024MCP()))
+	mux.HandleFunc(MCP2025_PATH, t.wrapHandler(t.HandleMCP()))
 	t.logger.Info("Registered MCP protocol handlers", zap.String("path_v2025", MCP2025_PATH), zap.String("path_v2024", MCP2024_PATH))
 }
 
 // RegisterA2AHandlers registers only the A2A protocol handlers.
 func (t *Transport) RegisterA2AHandlers(mux *http.ServeMux, agentCard *a2aSchema.AgentCard) {
-	mux.HandleFunc(A2A_PATH, t.HandleA2A())
-	// Register /.well-known only if A2A path is different
-
-	handler := func(w http.ResponseWriter, r *http.Request) {
-		w.Header().Set("Content-Type", "application/json")
-		w.Header().Set("Access-Control-Allow-Origin", "*")
-		urlCopy := *r.URL
-		urlCopy.Path = A2A_PATH
-		agentCardCopy := *agentCard
-		agentCardCopy.URL = urlCopy.String()
-		json.NewEncoder(w).Encode(agentCardCopy)
-	}
-	mux.HandleFunc("/.well-known/agent.json", handler)
+	mux.HandleFunc(A2A_PATH, t.wrapHandler(t.HandleA2A()))
+	mux.HandleFunc("/.well-known/agent.json", t.handleAgentCard(agentCard))
 
 	t.logger.Info("Registered A2A protocol handlers", zap.String("path", A2A_PATH), zap.String("wellKnownPath", "/.well-known/agent.json"))
 }
@@ -171,6 +228,9 @@ func (t *Transport) RegisterA2AHandlers(mux *http.ServeMux, agentCard *a2aSchema
 func (t *Transport) Handle2024MCP() http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		logger := t.logger
+		if correlationID := r.Header.Get(shared.CorrelationIDHeader); correlationID != "" {
+			logger = logger.With(zap.String("correlationID", correlationID))
+		}
 
 		logger.Debug("Received request",
 			zap.String("method", r.Method),
@@ -179,12 +239,17 @@ func (t *Transport) Handle2024MCP() http.HandlerFunc {
 			zap.String("query", r.URL.RawQuery),
 		)
 
+		if r.Method != http.MethodOptions && !t.originAllowed(r, logger) {
+			http.Error(w, "Forbidden origin", http.StatusForbidden)
+			return
+		}
+
 		// Handle based on HTTP method
 		switch r.Method {
 		case http.MethodGet:
 			t.handle2024GET(w, r, logger)
 		case http.MethodPost:
-			t.handle2024POST(w, r, logger)
+			t.tracePOST(w, r, logger, "MCP2024", t.handle2024POST)
 		case http.MethodOptions:
 			w.Header().Set("Allow", "GET, POST, OPTIONS")
 			w.WriteHeader(http.StatusNoContent)
@@ -198,6 +263,9 @@ func (t *Transport) Handle2024MCP() http.HandlerFunc {
 func (t *Transport) HandleMCP() http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		logger := t.logger
+		if correlationID := r.Header.Get(shared.CorrelationIDHeader); correlationID != "" {
+			logger = logger.With(zap.String("correlationID", correlationID))
+		}
 
 		logger.Debug("Received request",
 			zap.String("method", r.Method),
@@ -206,11 +274,16 @@ func (t *Transport) HandleMCP() http.HandlerFunc {
 			zap.String("query", r.URL.RawQuery),
 		)
 
+		if r.Method != http.MethodOptions && !t.originAllowed(r, logger) {
+			http.Error(w, "Forbidden origin", http.StatusForbidden)
+			return
+		}
+
 		switch r.Method {
 		case http.MethodGet:
 			t.handleGET(w, r, logger)
 		case http.MethodPost:
-			t.handlePOST(w, r, logger)
+			t.tracePOST(w, r, logger, "MCP2025", t.handlePOST)
 		case http.MethodDelete:
 			t.handleDELETE(w, r, logger)
 		case http.MethodOptions:
@@ -240,7 +313,7 @@ func (t *Transport) HandleA2A() http.HandlerFunc {
 
 		switch r.Method {
 		case http.MethodPost:
-			t.handleA2APOST(w, r, logger)
+			t.tracePOST(w, r, logger, "A2A", t.handleA2APOST)
 		case http.MethodGet:
 			http.NotFound(w, r)
 		case http.MethodOptions:
@@ -260,10 +333,79 @@ func (t *Transport) startSessionCleanup() {
 		zap.Duration("interval", t.cleanupInterval),
 		zap.Duration("timeout", t.sessionTimeout),
 	)
-	for range ticker.C {
-		t.sessionManager.CleanupIdleSessions(t.sessionTimeout)
+	for {
+		select {
+		case <-ticker.C:
+			t.sessionManager.CleanupIdleSessions(t.sessionTimeout)
+		case <-t.done:
+			t.logger.Info("Session cleanup routine stopped")
+			return
+		}
+	}
+}
+
+// startIdleSessionSweeper periodically pings idle sessions and closes the ones that stay silent,
+// until the transport is closed.
+func (t *Transport) startIdleSessionSweeper() {
+	interval := min(t.idleTimeout, t.idleGrace) / 2
+	if interval <= 0 {
+		interval = t.idleTimeout
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	t.logger.Info("Starting idle session sweeper",
+		zap.Duration("interval", interval),
+		zap.Duration("idleTimeout", t.idleTimeout),
+		zap.Duration("grace", t.idleGrace),
+	)
+	for {
+		select {
+		case now := <-ticker.C:
+			t.sweepIdleSessions(now)
+		case <-t.done:
+			t.logger.Info("Idle session sweeper stopped")
+			return
+		}
+	}
+}
+
+// sweepIdleSessions pings sessions that received no message for idleTimeout and closes pinged
+// sessions that received none during the grace period either. Messages sent to a session, such
+// as notifications, do not keep it alive.
+func (t *Transport) sweepIdleSessions(now time.Time) {
+	t.pingedMu.Lock()
+	defer t.pingedMu.Unlock()
+
+	active := make(map[string]bool)
+	for _, session := range t.sessionManager.GetSessions() {
+		id := session.GetID()
+		active[id] = true
+		lastActivity := session.GetLastInboundActivity()
+
+		if pingedAt, pinged := t.pingedSessions[id]; pinged {
+			if lastActivity.After(pingedAt) {
+				t.logger.Debug("Idle session became active again", zap.String("sessionID", id))
+				delete(t.pingedSessions, id)
+			} else if now.Sub(pingedAt) >= t.idleGrace {
+				t.logger.Info("Closing idle session after grace period", zap.String("sessionID", id))
+				delete(t.pingedSessions, id)
+				t.sessionManager.CloseSession(id)
+			}
+			continue
+		}
+
+		if now.Sub(lastActivity) >= t.idleTimeout {
+			t.logger.Debug("Pinging idle session", zap.String("sessionID", id), zap.Duration("idle", now.Sub(lastActivity)))
+			session.SendNotification("notifications/ping", nil)
+			t.pingedSessions[id] = now
+		}
+	}
+
+	for id := range t.pingedSessions {
+		if !active[id] {
+			delete(t.pingedSessions, id)
+		}
 	}
-	t.logger.Info("Session cleanup routine stopped")
 }
 
 // --- Helper to send JSON responses ---
@@ -305,6 +447,26 @@ func sendJSONRPCErrorResponse(w http.ResponseWriter, id *schema.RequestID, code
 	}
 }
 
+// validateJSONContentType rejects POST bodies not declared as application/json (optionally with charset=utf-8).
+// On failure it writes a 415 response with a JSON-RPC parse error and returns false.
+func validateJSONContentType(w http.ResponseWriter, r *http.Request, logger *zap.Logger) bool {
+	mediaType, params, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if err == nil && mediaType == contentTypeJSON {
+		if charset, ok := params["charset"]; !ok || strings.EqualFold(charset, "utf-8") {
+			return true
+		}
+	}
+	logger.Warn("Rejected POST with unsupported Content-Type", zap.String("contentType", r.Header.Get("Content-Type")))
+	sendJSONResponse(w, statusUnsupportedMedia, shared.JSONRPCErrorResponse{
+		JSONRPC: shared.JSONRPCVersion,
+		Error: &shared.JSONRPCError{
+			Code:    shared.JSONRPCErrorParseError,
+			Message: "Unsupported Media Type: expected application/json",
+		},
+	}, logger)
+	return false
+}
+
 const HEADERKEY = "received_headers"
 
 func (t *Transport) getSession(r *http.Request, sessionID string, logger *zap.Logger, allowCreate bool) (shared.ISession, error) {
@@ -342,6 +504,9 @@ func (t *Transport) getSession(r *http.Request, sessionID string, logger *zap.Lo
 	sessionParams.Store(HEADERKEY, r.Header)
 
 	newSession := t.sessionManager.CreateSession(userID, sessionID, sessionParams)
+	if t.requestIDGenerator != nil {
+		newSession.SetRequestIDGenerator(t.requestIDGenerator)
+	}
 	logger.Info("Created new session", zap.String("newSessionId", newSession.GetID()), zap.String("userId", userID))
 	return newSession, nil
 }
@@ -7,15 +7,18 @@ import (
 	"io"
 	"net/http"
 	"net/url"
+	"regexp"
 	"strconv"
 	"strings"
 	"sync"
 	"testing"
 	"time"
 
+	"github.com/gate4ai/gate4ai/server/mcp/capability"
 	"github.com/gate4ai/gate4ai/server/transport"
 	"github.com/gate4ai/gate4ai/shared"
 	schema2024 "github.com/gate4ai/gate4ai/shared/mcp/2024/schema"
+	schema2025 "github.com/gate4ai/gate4ai/shared/mcp/2025/schema"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -479,3 +482,304 @@ func Test_SRV_24_SSE_NEG_05_PostWithInvalidMcpMessage(t *testing.T) {
 	assert.Equal(t, http.StatusAccepted, postResp.StatusCode, "POST with invalid JSON should return 202 Accepted in V2024 handler")
 	// Verification requires checking server logs (outside the scope of this HTTP test).
 }
+
+// Requirement: The POST endpoint accepts only application/json bodies and rejects others with 415 and a JSON-RPC parse error.
+func Test_SRV_24_SSE_NEG_06_PostWithUnsupportedContentType(t *testing.T) {
+	_, _, _, server, cleanup := setupServerTest(t)
+	defer cleanup()
+
+	// 1. Establish an SSE connection and keep it open
+	sseResp, err := makeSseGetRequest(t, server.URL+transport.MCP2024_PATH+"?key=valid-key", nil)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, sseResp.StatusCode, "Failed to connect SSE")
+	defer sseResp.Body.Close()
+
+	endpointChan := make(chan string, 1)
+	go func() {
+		event, endpointData, err := readFirstSseEvent(t, sseResp.Body)
+		if err != nil || event != "endpoint" {
+			close(endpointChan)
+			return
+		}
+		endpointChan <- endpointData
+		io.Copy(io.Discard, sseResp.Body)
+	}()
+	endpointData, ok := <-endpointChan
+	require.True(t, ok, "Did not receive endpoint event")
+	postURL := server.URL + endpointData
+	requestBody := createJsonRpcRequestBody(1, "ping", nil)
+
+	// 2. Non-JSON content types are rejected
+	for _, contentType := range []string{"text/plain", "application/xml", "multipart/form-data; boundary=xyz"} {
+		postResp, err := makePostRequest(t, postURL, requestBody, map[string]string{"Content-Type": contentType})
+		require.NoError(t, err)
+		var errResp shared.JSONRPCErrorResponse
+		require.NoError(t, json.NewDecoder(postResp.Body).Decode(&errResp))
+		postResp.Body.Close()
+		assert.Equal(t, http.StatusUnsupportedMediaType, postResp.StatusCode, "Content-Type %s", contentType)
+		require.NotNil(t, errResp.Error, "Content-Type %s", contentType)
+		assert.Equal(t, shared.JSONRPCErrorParseError, errResp.Error.Code)
+		assert.Equal(t, "Unsupported Media Type: expected application/json", errResp.Error.Message)
+	}
+
+	// 3. JSON with a charset suffix is accepted
+	postResp, err := makePostRequest(t, postURL, requestBody, map[string]string{"Content-Type": "application/json; charset=utf-8"})
+	require.NoError(t, err)
+	defer postResp.Body.Close()
+	assert.Equal(t, http.StatusAccepted, postResp.StatusCode)
+}
+
+// Requirement: Cross-site browser requests from origins outside config.AllowedOrigins are rejected with 403 before a session is created.
+func Test_SRV_24_SSE_NEG_07_RejectsCrossSiteRequestFromUntrustedOrigin(t *testing.T) {
+	_, mockManager, cfg, server, cleanup := setupServerTest(t)
+	defer cleanup()
+	cfg.AllowedOriginsValue = []string{"https://trusted.example"}
+
+	tests := []struct {
+		name       string
+		headers    map[string]string
+		wantStatus int
+	}{
+		{"foreign origin", map[string]string{"Origin": "https://evil.example", "Sec-Fetch-Site": "cross-site"}, http.StatusForbidden},
+		{"trusted origin", map[string]string{"Origin": "https://trusted.example", "Sec-Fetch-Site": "cross-site"}, http.StatusOK},
+		{"no origin", nil, http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sessionsBefore := len(mockManager.GetSessions())
+			resp, err := makeSseGetRequest(t, server.URL+transport.MCP2024_PATH+"?key=valid-key", tt.headers)
+			require.NoError(t, err)
+			resp.Body.Close()
+			assert.Equal(t, tt.wantStatus, resp.StatusCode)
+			if tt.wantStatus == http.StatusForbidden {
+				assert.Len(t, mockManager.GetSessions(), sessionsBefore, "Rejected request must not create a session")
+			}
+		})
+	}
+}
+
+// Requirement: With a UUID request ID generator, server-initiated requests carry distinct UUID IDs.
+func Test_SRV_24_SSE_POS_06_UUIDRequestIDGenerator(t *testing.T) {
+	_, mockManager, _, server, cleanup := setupServerTest(t, transport.WithRequestIDGenerator(shared.UUIDRequestIDGenerator()))
+	defer cleanup()
+
+	sseResp, err := makeSseGetRequest(t, server.URL+transport.MCP2024_PATH+"?key=valid-key", nil)
+	require.NoError(t, err)
+	defer sseResp.Body.Close()
+	reader := bufio.NewReader(sseResp.Body)
+	event, endpointData, _, err := readNextSseEvent(t, reader)
+	require.NoError(t, err)
+	require.Equal(t, "endpoint", event)
+	parsedURL, _ := url.Parse(endpointData)
+	session, err := mockManager.GetSession(parsedURL.Query().Get(transport.SESSION_ID_KEY2024))
+	require.NoError(t, err)
+
+	session.SendRequestSync("sampling/createMessage", nil)
+	session.SendRequestSync("sampling/createMessage", nil)
+
+	uuidPattern := regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`)
+	var ids []string
+	for len(ids) < 2 {
+		event, data, _, err := readNextSseEvent(t, reader)
+		require.NoError(t, err)
+		if event != "message" {
+			continue
+		}
+		var msg struct {
+			ID     interface{} `json:"id"`
+			Method string      `json:"method"`
+		}
+		require.NoError(t, json.Unmarshal([]byte(data), &msg))
+		require.Equal(t, "sampling/createMessage", msg.Method)
+		id, ok := msg.ID.(string)
+		require.True(t, ok, "request ID should be a string, got %T", msg.ID)
+		assert.Regexp(t, uuidPattern, id)
+		ids = append(ids, id)
+	}
+	assert.NotEqual(t, ids[0], ids[1], "request IDs should be distinct")
+}
+
+// Requirement: A 2024-11-05 client on the SSE transport receives a valid v2024 initialize response,
+// even when the server negotiates 2025-03-26 and advertises 2025-only capabilities.
+func Test_SRV_24_SSE_POS_07_DowngradesInitializeForV2024Client(t *testing.T) {
+	_, mockManager, _, server, cleanup := setupServerTest(t)
+	defer cleanup()
+	mockManager.AddCapability(capability.NewCompletionCapability(mockManager.Logger))
+
+	initialize := func(protocolVersion string) json.RawMessage {
+		sseResp, err := makeSseGetRequest(t, server.URL+transport.MCP2024_PATH+"?key=valid-key", nil)
+		require.NoError(t, err)
+		defer sseResp.Body.Close()
+		reader := bufio.NewReader(sseResp.Body)
+		event, endpointData, _, err := readNextSseEvent(t, reader)
+		require.NoError(t, err)
+		require.Equal(t, "endpoint", event)
+
+		body := createJsonRpcRequestBody(1, "initialize", map[string]interface{}{
+			"protocolVersion": protocolVersion,
+			"capabilities":    map[string]interface{}{},
+			"clientInfo":      map[string]string{"name": "legacy-client", "version": "0.1"},
+		})
+		postResp, err := makePostRequest(t, server.URL+endpointData, body, map[string]string{"Content-Type": "application/json"})
+		require.NoError(t, err)
+		postResp.Body.Close()
+
+		for {
+			event, data, _, err := readNextSseEvent(t, reader)
+			require.NoError(t, err)
+			if event != "message" {
+				continue
+			}
+			var msg shared.Message
+			require.NoError(t, json.Unmarshal([]byte(data), &msg))
+			if msg.ID != nil && msg.ID.String() == "1" {
+				require.Nil(t, msg.Error)
+				require.NotNil(t, msg.Result)
+				return *msg.Result
+			}
+		}
+	}
+
+	// A client asking for an unknown version gets the server's latest, downgraded to 2024-11-05
+	raw := initialize("2024-10-01")
+	decoder := json.NewDecoder(strings.NewReader(string(raw)))
+	decoder.DisallowUnknownFields()
+	var result schema2024.InitializeResult
+	require.NoError(t, decoder.Decode(&result), "response should match the v2024 schema exactly: %s", raw)
+	assert.Equal(t, schema2024.PROTOCOL_VERSION, result.ProtocolVersion)
+	assert.Equal(t, "TestServer", result.ServerInfo.Name)
+
+	// Clients asking for 2025-03-26 on the SSE transport keep the 2025 response
+	raw = initialize(schema2025.PROTOCOL_VERSION)
+	var result2025 schema2025.InitializeResult
+	require.NoError(t, json.Unmarshal(raw, &result2025))
+	assert.Equal(t, schema2025.PROTOCOL_VERSION, result2025.ProtocolVersion)
+	assert.NotNil(t, result2025.Capabilities.Completions)
+}
+
+// Requirement: With an SSE event buffer, message events carry incrementing IDs and a client reconnecting
+// with Last-Event-ID receives the buffered events after that ID before the live stream resumes.
+func Test_SRV_24_SSE_POS_08_ReplaysMissedEventsAfterReconnect(t *testing.T) {
+	_, mockManager, _, server, cleanup := setupServerTest(t, transport.WithSSEEventBuffer(10))
+	defer cleanup()
+
+	readMessages := func(reader *bufio.Reader, count int) (ids []string, data []string) {
+		for len(ids) < count {
+			event, eventData, id, err := readNextSseEvent(t, reader)
+			require.NoError(t, err)
+			if event != "message" {
+				continue
+			}
+			ids = append(ids, id)
+			data = append(data, eventData)
+		}
+		return ids, data
+	}
+
+	sseResp, err := makeSseGetRequest(t, server.URL+transport.MCP2024_PATH+"?key=valid-key", nil)
+	require.NoError(t, err)
+	reader := bufio.NewReader(sseResp.Body)
+	event, endpointData, _, err := readNextSseEvent(t, reader)
+	require.NoError(t, err)
+	require.Equal(t, "endpoint", event)
+	parsedURL, _ := url.Parse(endpointData)
+	sessionID := parsedURL.Query().Get(transport.SESSION_ID_KEY2024)
+	session, err := mockManager.GetSession(sessionID)
+	require.NoError(t, err)
+
+	for i := 1; i <= 5; i++ {
+		session.SendNotification("notifications/message", map[string]any{"n": i})
+	}
+	ids, data := readMessages(reader, 5)
+	assert.Equal(t, []string{"1", "2", "3", "4", "5"}, ids)
+	sseResp.Body.Close()
+
+	// The client lost the five events and resumes from the ID it had before them
+	var reconnectResp *http.Response
+	require.Eventually(t, func() bool {
+		resp, err := makeSseGetRequest(t, server.URL+transport.MCP2024_PATH+"?key=valid-key&"+transport.SESSION_ID_KEY2024+"="+sessionID, map[string]string{"Last-Event-ID": "0"})
+		if err != nil {
+			return false
+		}
+		if resp.StatusCode != http.StatusOK { // The previous stream has not released the session output yet
+			resp.Body.Close()
+			return false
+		}
+		reconnectResp = resp
+		return true
+	}, 2*time.Second, 50*time.Millisecond)
+	defer reconnectResp.Body.Close()
+	reader = bufio.NewReader(reconnectResp.Body)
+
+	replayedIDs, replayedData := readMessages(reader, 5)
+	assert.Equal(t, ids, replayedIDs)
+	assert.Equal(t, data, replayedData)
+
+	session.SendNotification("notifications/message", map[string]any{"n": 6})
+	liveIDs, liveData := readMessages(reader, 1)
+	assert.Equal(t, []string{"6"}, liveIDs)
+	assert.Contains(t, liveData[0], `"n":6`)
+
+	mockManager.mu.RLock()
+	closed := mockManager.ClosedSessions[sessionID]
+	mockManager.mu.RUnlock()
+	assert.False(t, closed, "session must stay open for reconnect while events are buffered")
+}
+
+// Requirement: Idle SSE streams get keepalive comments at the configured interval, so proxies
+// do not close them; an interval of 0 disables them.
+func Test_SRV_24_SSE_POS_09_SendsKeepAliveComments(t *testing.T) {
+	// readLines forwards the raw stream lines after the endpoint event
+	readLines := func(t *testing.T, interval time.Duration) <-chan string {
+		_, _, cfg, server, cleanup := setupServerTest(t)
+		t.Cleanup(cleanup)
+		cfg.SSEKeepAliveIntervalValue = interval
+
+		resp, err := makeSseGetRequest(t, server.URL+transport.MCP2024_PATH+"?key=valid-key", nil)
+		require.NoError(t, err)
+		t.Cleanup(func() { resp.Body.Close() })
+		reader := bufio.NewReader(resp.Body)
+		event, _, _, err := readNextSseEvent(t, reader)
+		require.NoError(t, err)
+		require.Equal(t, "endpoint", event)
+
+		lines := make(chan string, 100)
+		go func() {
+			defer close(lines)
+			for {
+				line, err := reader.ReadString('\n')
+				if err != nil {
+					return
+				}
+				lines <- strings.TrimRight(line, "\n")
+			}
+		}()
+		return lines
+	}
+
+	t.Run("enabled", func(t *testing.T) {
+		lines := readLines(t, 50*time.Millisecond)
+		keepAlives := 0
+		deadline := time.After(2 * time.Second)
+		for keepAlives < 2 {
+			select {
+			case line, ok := <-lines:
+				require.True(t, ok, "SSE stream closed")
+				if line == ": keepalive" {
+					keepAlives++
+				}
+			case <-deadline:
+				t.Fatalf("Received %d keepalive comments, want 2", keepAlives)
+			}
+		}
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		lines := readLines(t, 0)
+		select {
+		case line := <-lines:
+			t.Fatalf("Unexpected line on an idle stream without keepalives: %q", line)
+		case <-time.After(300 * time.Millisecond):
+		}
+	})
+}
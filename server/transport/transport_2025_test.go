@@ -3,8 +3,10 @@ package transport_test
 import (
 	"bufio"
 	"encoding/json"
+	"fmt"
 	"io"
 	"net/http"
+	"strings"
 	"testing"
 	"time"
 
@@ -330,3 +332,179 @@ func Test_SRV_25_HTTP_POS_05_PostBatchRequestNotificationReturnsResponse(t *test
 		assert.ErrorIs(t, errRead, io.EOF, "Expected SSE stream to close after batch responses")
 	})
 }
+
+// chunkedResult streams {"chunks":[...]} and waits for the client to see each chunk before writing the next.
+type chunkedResult struct {
+	chunks int
+	seen   <-chan int
+}
+
+func (c *chunkedResult) StreamResult(w io.Writer) error {
+	if _, err := io.WriteString(w, `{"chunks":[`); err != nil {
+		return err
+	}
+	for i := 0; i < c.chunks; i++ {
+		separator := ","
+		if i == 0 {
+			separator = ""
+		}
+		if _, err := fmt.Fprintf(w, `%s"chunk-%d"`, separator, i); err != nil {
+			return err
+		}
+		select {
+		case <-c.seen:
+		case <-time.After(time.Second):
+			return fmt.Errorf("client did not receive chunk %d while the response was still open", i)
+		}
+	}
+	_, err := io.WriteString(w, `]}`)
+	return err
+}
+
+type streamingTestCapability struct {
+	result *chunkedResult
+}
+
+func (c *streamingTestCapability) GetHandlers() map[string]func(*shared.Message) (interface{}, error) {
+	return map[string]func(*shared.Message) (interface{}, error){
+		"test/stream": func(msg *shared.Message) (interface{}, error) {
+			return c.result, nil
+		},
+	}
+}
+
+func (c *streamingTestCapability) SetCapabilities(s *schema2025.ServerCapabilities) {}
+func (c *streamingTestCapability) SetEventBus(bus shared.EventBus)                  {}
+
+// Requirement: A result implementing shared.StreamingHandler is sent with chunked transfer encoding,
+// each chunk reaching the client before the response is complete.
+func Test_SRV_25_HTTP_POS_06_PostStreamsChunkedResult(t *testing.T) {
+	tp, mockManager, _, server, cleanup := setupServerTest(t)
+	defer cleanup()
+	tp.NoStream2025 = true
+	seen := make(chan int)
+	mockManager.AddCapability(&streamingTestCapability{result: &chunkedResult{chunks: 10, seen: seen}})
+
+	initBody := createJsonRpcRequestBody(1, "initialize", schema2025.InitializeRequestParams{
+		ProtocolVersion: schema2025.PROTOCOL_VERSION,
+		ClientInfo:      schema2025.Implementation{Name: "test-client", Version: "1.0"},
+		Capabilities:    schema2025.ClientCapabilities{},
+	})
+	respInit, err := makePostRequest(t, server.URL+transport.MCP2025_PATH, initBody, nil)
+	require.NoError(t, err)
+	respInit.Body.Close()
+	sessionIDHeader := map[string]string{transport.MCP_SESSION_HEADER: respInit.Header.Get(transport.MCP_SESSION_HEADER)}
+
+	resp, err := makePostRequest(t, server.URL+transport.MCP2025_PATH, createJsonRpcRequestBody(2, "test/stream", nil), sessionIDHeader)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, []string{"chunked"}, resp.TransferEncoding)
+
+	var body []byte
+	buf := make([]byte, 256)
+	for i := 0; i < 10; i++ {
+		for !strings.Contains(string(body), fmt.Sprintf(`"chunk-%d"`, i)) {
+			n, err := resp.Body.Read(buf)
+			body = append(body, buf[:n]...)
+			require.NoError(t, err, "Response ended before chunk %d arrived: %s", i, string(body))
+		}
+		seen <- i
+	}
+	rest, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	body = append(body, rest...)
+
+	var rpcResp shared.JSONRPCResponse
+	require.NoError(t, json.Unmarshal(body, &rpcResp), "Assembled body is not valid JSON-RPC: %s", string(body))
+	assert.Equal(t, float64(2), rpcResp.ID.Value)
+	require.NotNil(t, rpcResp.Result)
+	var result struct {
+		Chunks []string `json:"chunks"`
+	}
+	require.NoError(t, json.Unmarshal(*rpcResp.Result, &result))
+	require.Len(t, result.Chunks, 10)
+	for i, chunk := range result.Chunks {
+		assert.Equal(t, fmt.Sprintf("chunk-%d", i), chunk)
+	}
+}
+
+// Specification requirement (JSON-RPC 2.0 batches): each request of a batch gets its own response in an array,
+// notifications get none, and an invalid item is answered with an Invalid Request error without affecting the others.
+func Test_SRV_25_HTTP_POS_08_BatchItemsAnsweredIndependently(t *testing.T) {
+	tp, _, _, server, cleanup := setupServerTest(t)
+	defer cleanup()
+	tp.NoStream2025 = true // JSON responses
+
+	initBody := createJsonRpcRequestBody(1, "initialize", schema2025.InitializeRequestParams{
+		ProtocolVersion: schema2025.PROTOCOL_VERSION,
+		ClientInfo:      schema2025.Implementation{Name: "test-client", Version: "1.0"},
+		Capabilities:    schema2025.ClientCapabilities{},
+	})
+	respInit, err := makePostRequest(t, server.URL+transport.MCP2025_PATH, initBody, nil)
+	require.NoError(t, err)
+	respInit.Body.Close()
+	sessionIDHeader := map[string]string{transport.MCP_SESSION_HEADER: respInit.Header.Get(transport.MCP_SESSION_HEADER)}
+	postBatch := func(body string) *http.Response {
+		resp, err := makePostRequest(t, server.URL+transport.MCP2025_PATH, body, sessionIDHeader)
+		require.NoError(t, err)
+		t.Cleanup(func() { resp.Body.Close() })
+		return resp
+	}
+	// byID decodes the batch response into its items by ID ("null" for items without ID)
+	byID := func(items []json.RawMessage) map[string]shared.JSONRPCErrorResponse {
+		responses := make(map[string]shared.JSONRPCErrorResponse)
+		for _, item := range items {
+			var response shared.JSONRPCErrorResponse
+			require.NoError(t, json.Unmarshal(item, &response))
+			id := "null"
+			if response.ID != nil {
+				id = fmt.Sprint(response.ID.Value)
+			}
+			responses[id] = response
+		}
+		return responses
+	}
+
+	t.Run("All notifications", func(t *testing.T) {
+		resp := postBatch(createJsonRpcBatchRequestBody(
+			createJsonRpcNotificationBody("notify/1", nil),
+			createJsonRpcNotificationBody("notify/2", nil),
+		))
+		assert.Equal(t, http.StatusAccepted, resp.StatusCode)
+		body, _ := io.ReadAll(resp.Body)
+		assert.Empty(t, body)
+	})
+
+	t.Run("Requests, notifications and failing items", func(t *testing.T) {
+		resp := postBatch(createJsonRpcBatchRequestBody(
+			createJsonRpcRequestBody(20, "ping", nil),
+			createJsonRpcNotificationBody("notify/1", nil),
+			createJsonRpcRequestBody(21, "no/such/method", nil),
+			`42`,
+			`{"jsonrpc": "2.0", "id": 22}`,
+			createJsonRpcRequestBody(23, "ping", nil),
+		))
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		responses := byID(assertJsonRpcBatchResponse(t, resp.Body, 5))
+		assert.Nil(t, responses["20"].Error)
+		assert.Nil(t, responses["23"].Error)
+		assert.NotNil(t, responses["21"].Error, "Unknown method must fail on its own")
+		require.NotNil(t, responses["null"].Error)
+		assert.Equal(t, shared.JSONRPCErrorInvalidRequest, responses["null"].Error.Code)
+		require.NotNil(t, responses["22"].Error)
+		assert.Equal(t, shared.JSONRPCErrorInvalidRequest, responses["22"].Error.Code)
+	})
+
+	t.Run("Single request", func(t *testing.T) {
+		resp := postBatch(createJsonRpcBatchRequestBody(createJsonRpcRequestBody(30, "ping", nil)))
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		responses := byID(assertJsonRpcBatchResponse(t, resp.Body, 1))
+		assert.Nil(t, responses["30"].Error)
+	})
+
+	t.Run("Empty batch", func(t *testing.T) {
+		resp := postBatch(`[]`)
+		assertJsonRpcError(t, resp.Body, shared.JSONRPCErrorInvalidRequest, "Empty batch")
+	})
+}
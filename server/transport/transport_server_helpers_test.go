@@ -118,6 +118,7 @@ func (m *MockTestCapability) GetHandlers() map[string]func(*shared.Message) (int
 
 // Implement IServerCapability (empty method is fine for this mock)
 func (m *MockTestCapability) SetCapabilities(s *schema2025.ServerCapabilities) {}
+func (m *MockTestCapability) SetEventBus(bus shared.EventBus)                  {}
 
 func (m *MockMCPManager) CreateSession(userID string, id string, params *sync.Map) shared.ISession {
 	m.mu.Lock()
@@ -149,6 +150,16 @@ func (m *MockMCPManager) GetSession(id string) (shared.ISession, error) {
 	return s, nil
 }
 
+func (m *MockMCPManager) GetSessions() []shared.ISession {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	sessions := make([]shared.ISession, 0, len(m.sessions))
+	for _, s := range m.sessions {
+		sessions = append(sessions, s)
+	}
+	return sessions
+}
+
 func (m *MockMCPManager) CloseSession(id string) {
 	m.mu.Lock()
 	defer m.mu.Unlock()
@@ -238,7 +249,7 @@ func (a *MockAuthenticator) Authenticate(authKey string, remoteAddr string) (str
 
 // --- Test Setup Helper ---
 
-func setupServerTest(t *testing.T) (*transport.Transport, *MockMCPManager, *config.InternalConfig, *httptest.Server, func()) {
+func setupServerTest(t *testing.T, options ...transport.TransportOption) (*transport.Transport, *MockMCPManager, *config.InternalConfig, *httptest.Server, func()) {
 	t.Helper()
 	logger, _ := zap.NewDevelopment() // Or NewNop() for less output
 	cfg := config.NewInternalConfig()
@@ -247,7 +258,7 @@ func setupServerTest(t *testing.T) (*transport.Transport, *MockMCPManager, *conf
 
 	mockManager := NewMockMCPManager(cfg, logger)
 
-	tp, err := transport.New(mockManager, logger, cfg)
+	tp, err := transport.New(mockManager, logger, cfg, options...)
 	require.NoError(t, err)
 
 	mockAuth := &MockAuthenticator{
@@ -0,0 +1,212 @@
+package transport
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gate4ai/gate4ai/shared"
+	"github.com/gorilla/websocket"
+	"go.uber.org/zap"
+)
+
+const (
+	// wsPingInterval is how often the server pings the client; like the SSE keepalive it also
+	// stops proxies from dropping an idle connection.
+	wsPingInterval = 15 * time.Second
+	// wsPongWait is how long the server waits for a pong before considering the client gone.
+	wsPongWait = 2 * wsPingInterval
+	// wsWriteWait bounds each write to the client.
+	wsWriteWait = 10 * time.Second
+)
+
+// WebSocketTransport serves MCP over a WebSocket on WS_PATH. Each connection is one session:
+// the client sends JSON-RPC messages (single or batched) as text frames and receives every
+// response, notification and server request on the same connection. Sessions are authenticated
+// like V2024 SSE streams, by the "key" query parameter or a bearer token, and share the session
+// manager of the Transport.
+type WebSocketTransport struct {
+	transport *Transport
+	upgrader  websocket.Upgrader
+}
+
+// NewWebSocketTransport creates a WebSocket transport using the sessions and authentication of t.
+func NewWebSocketTransport(t *Transport) *WebSocketTransport {
+	ws := &WebSocketTransport{transport: t}
+	ws.upgrader = websocket.Upgrader{
+		// Cross-site connections are checked against the allowed origins, like the HTTP transports
+		CheckOrigin: func(r *http.Request) bool { return t.originAllowed(r, t.logger) },
+	}
+	return ws
+}
+
+// RegisterHandlers registers the WebSocket handler on WS_PATH.
+func (ws *WebSocketTransport) RegisterHandlers(mux *http.ServeMux) {
+	mux.HandleFunc(WS_PATH, ws.transport.wrapHandler(ws.Handle()))
+	ws.transport.logger.Info("Registered MCP WebSocket handler", zap.String("path", WS_PATH))
+}
+
+// Handle upgrades GET requests to a WebSocket and serves the session until either side closes it.
+func (ws *WebSocketTransport) Handle() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		logger := ws.transport.logger.With(zap.String("protocol", "WebSocket"))
+		if correlationID := r.Header.Get(shared.CorrelationIDHeader); correlationID != "" {
+			logger = logger.With(zap.String("correlationID", correlationID))
+		}
+		logger.Debug("Received request",
+			zap.String("method", r.Method),
+			zap.String("path", r.URL.Path),
+			zap.String("remoteAddr", r.RemoteAddr),
+		)
+		if r.Method != http.MethodGet {
+			logger.Warn("Method not allowed", zap.String("method", r.Method))
+			http.Error(w, "Method Not Allowed", statusMethodNotAllowed)
+			return
+		}
+
+		// Authenticate before upgrading so a rejected client gets a plain HTTP 401
+		session, err := ws.transport.getSession(r, "", logger, true)
+		if err != nil {
+			logger.Error("Failed to create session", zap.Error(err))
+			http.Error(w, "Session failed", statusUnauthorized)
+			return
+		}
+		logger = logger.With(zap.String("sessionId", session.GetID()))
+		output, ok := session.AcquireOutput()
+		if !ok {
+			logger.Error("Failed to acquire output channel for WebSocket")
+			ws.transport.sessionManager.CloseSession(session.GetID())
+			http.Error(w, "Failed to acquire output channel", statusInternalServerError)
+			return
+		}
+
+		conn, err := ws.upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			// Upgrade has already replied with an HTTP error
+			logger.Warn("WebSocket upgrade failed", zap.Error(err))
+			session.ReleaseOutput()
+			ws.transport.sessionManager.CloseSession(session.GetID())
+			return
+		}
+		session.SetStatus(shared.StatusConnected)
+		logger.Info("WebSocket connected")
+
+		replies := make(chan []byte, 1)
+		readerDone := make(chan struct{})
+		writerDone := make(chan struct{})
+		go func() {
+			defer close(writerDone)
+			ws.writeLoop(conn, output, replies, readerDone, logger)
+		}()
+		ws.readLoop(r.Context(), conn, session, replies, writerDone, logger)
+		close(readerDone)
+		<-writerDone
+
+		conn.Close()
+		session.ReleaseOutput()
+		ws.transport.sessionManager.CloseSession(session.GetID())
+		logger.Info("WebSocket disconnected, session closed")
+	}
+}
+
+// readLoop passes the client's messages to the session until the connection fails or closes.
+// Replies the transport itself must send, such as parse errors, go to replies. The messages
+// carry ctx, which ends with the connection.
+func (ws *WebSocketTransport) readLoop(ctx context.Context, conn *websocket.Conn, session shared.ISession, replies chan<- []byte, writerDone <-chan struct{}, logger *zap.Logger) {
+	_ = conn.SetReadDeadline(time.Now().Add(wsPongWait))
+	conn.SetPongHandler(func(string) error {
+		return conn.SetReadDeadline(time.Now().Add(wsPongWait))
+	})
+	for {
+		messageType, data, err := conn.ReadMessage()
+		if err != nil {
+			if websocket.IsUnexpectedCloseError(err, websocket.CloseNormalClosure, websocket.CloseGoingAway) {
+				logger.Warn("WebSocket read failed", zap.Error(err))
+			}
+			return
+		}
+		_ = conn.SetReadDeadline(time.Now().Add(wsPongWait))
+		if messageType != websocket.TextMessage {
+			logger.Warn("Ignoring non-text WebSocket frame", zap.Int("type", messageType))
+			continue
+		}
+		session.UpdateLastInboundActivity()
+
+		msgs, err := shared.ParseMessages(session, data)
+		if err != nil {
+			logger.Error("Failed to parse JSON-RPC message(s) from WebSocket", zap.Error(err), zap.ByteString("data", data))
+			reply, _ := json.Marshal(shared.JSONRPCErrorResponse{
+				JSONRPC: shared.JSONRPCVersion,
+				Error:   &shared.JSONRPCError{Code: shared.JSONRPCErrorParseError, Message: "Parse error: " + err.Error()},
+			})
+			select {
+			case replies <- reply:
+			case <-writerDone:
+				return
+			}
+			continue
+		}
+		for _, msg := range msgs {
+			msg.Session = session
+			msg.Timestamp = time.Now()
+			msg.SetContext(ctx)
+			if handleErr := session.Input().Put(msg); handleErr != nil {
+				logger.Error("Error handling WebSocket message", zap.Error(handleErr), zap.Any("msgId", msg.ID))
+				if !msg.ID.IsEmpty() && !errors.Is(handleErr, shared.ErrInputBusy) { // Put answers dropped requests itself
+					session.SendResponse(msg.ID, nil, handleErr)
+				}
+			}
+		}
+	}
+}
+
+// writeLoop sends the session output, the transport's own replies and keepalive pings to the
+// client until the reader stops or the session output is closed. It is the connection's only writer.
+func (ws *WebSocketTransport) writeLoop(conn *websocket.Conn, output <-chan *shared.Message, replies <-chan []byte, readerDone <-chan struct{}, logger *zap.Logger) {
+	ticker := time.NewTicker(wsPingInterval)
+	defer ticker.Stop()
+	write := func(messageType int, data []byte) bool {
+		_ = conn.SetWriteDeadline(time.Now().Add(wsWriteWait))
+		if err := conn.WriteMessage(messageType, data); err != nil {
+			logger.Warn("WebSocket write failed", zap.Error(err))
+			conn.Close() // Unblocks the reader
+			return false
+		}
+		return true
+	}
+
+	for {
+		select {
+		case <-readerDone:
+			return
+		case msg, ok := <-output:
+			if !ok {
+				logger.Info("Session output channel closed")
+				_ = conn.WriteControl(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseNormalClosure, "session closed"), time.Now().Add(wsWriteWait))
+				conn.Close()
+				return
+			}
+			if msg == nil {
+				continue
+			}
+			data, err := json.Marshal(msg)
+			if err != nil {
+				logger.Error("Failed to marshal message for WebSocket", zap.Error(err), zap.Any("msgId", msg.ID), zap.Stringp("method", msg.Method))
+				continue
+			}
+			if !write(websocket.TextMessage, data) {
+				return
+			}
+		case reply := <-replies:
+			if !write(websocket.TextMessage, reply) {
+				return
+			}
+		case <-ticker.C:
+			if !write(websocket.PingMessage, nil) {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,182 @@
+package transport_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gate4ai/gate4ai/server/transport"
+	"github.com/gate4ai/gate4ai/shared"
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// setupWebSocketTest serves the WebSocket transport of a test transport and returns its ws:// URL.
+func setupWebSocketTest(t *testing.T) (*MockMCPManager, string) {
+	t.Helper()
+	tp, mockManager, _, _, cleanup := setupServerTest(t)
+	t.Cleanup(cleanup)
+	mux := http.NewServeMux()
+	transport.NewWebSocketTransport(tp).RegisterHandlers(mux)
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return mockManager, "ws" + strings.TrimPrefix(server.URL, "http") + transport.WS_PATH
+}
+
+func dialWebSocket(t *testing.T, url string) *websocket.Conn {
+	t.Helper()
+	conn, resp, err := websocket.DefaultDialer.Dial(url, nil)
+	require.NoError(t, err)
+	resp.Body.Close()
+	t.Cleanup(func() { conn.Close() })
+	return conn
+}
+
+// readWebSocketMessage reads messages until one with the given ID arrives.
+func readWebSocketMessage(t *testing.T, conn *websocket.Conn, id string) shared.Message {
+	t.Helper()
+	require.NoError(t, conn.SetReadDeadline(time.Now().Add(3*time.Second)))
+	for {
+		_, data, err := conn.ReadMessage()
+		require.NoError(t, err)
+		var msg shared.Message
+		require.NoError(t, json.Unmarshal(data, &msg))
+		if (msg.ID == nil && id == "") || (msg.ID != nil && msg.ID.String() == id) {
+			return msg
+		}
+	}
+}
+
+// Requirement: A request sent over the WebSocket is answered on the same connection.
+func Test_SRV_WS_POS_01_RequestAndResponseShareConnection(t *testing.T) {
+	_, url := setupWebSocketTest(t)
+	conn := dialWebSocket(t, url+"?key=valid-key")
+
+	require.NoError(t, conn.WriteMessage(websocket.TextMessage, []byte(createJsonRpcRequestBody(1, "test/method", map[string]string{"data": "test"}))))
+	msg := readWebSocketMessage(t, conn, "1")
+	require.Nil(t, msg.Error)
+	require.NotNil(t, msg.Result)
+	assert.JSONEq(t, `{"status":"ok"}`, string(*msg.Result))
+}
+
+// Requirement: Batched messages in one frame are all processed, and each response is its own frame.
+func Test_SRV_WS_POS_02_HandlesBatches(t *testing.T) {
+	_, url := setupWebSocketTest(t)
+	conn := dialWebSocket(t, url+"?key=valid-key")
+
+	batch := "[" + createJsonRpcRequestBody(101, "test", nil) + "," + createJsonRpcRequestBody(102, "test", nil) + "]"
+	require.NoError(t, conn.WriteMessage(websocket.TextMessage, []byte(batch)))
+
+	seen := map[string]string{}
+	require.NoError(t, conn.SetReadDeadline(time.Now().Add(3*time.Second)))
+	for len(seen) < 2 {
+		_, data, err := conn.ReadMessage()
+		require.NoError(t, err)
+		var msg shared.Message
+		require.NoError(t, json.Unmarshal(data, &msg))
+		if msg.ID != nil && msg.Result != nil {
+			seen[msg.ID.String()] = string(*msg.Result)
+		}
+	}
+	assert.JSONEq(t, `{"clientId":1}`, seen["101"])
+	assert.JSONEq(t, `{"clientId":2}`, seen["102"])
+}
+
+// Requirement: The server pushes notifications for the session over the WebSocket.
+func Test_SRV_WS_POS_03_PushesServerNotifications(t *testing.T) {
+	mockManager, url := setupWebSocketTest(t)
+	conn := dialWebSocket(t, url+"?key=valid-key")
+
+	var sessions []shared.ISession
+	require.Eventually(t, func() bool {
+		sessions = mockManager.GetSessions()
+		return len(sessions) == 1
+	}, time.Second, 10*time.Millisecond)
+	sessions[0].SendNotification("notifications/message", map[string]any{"level": "info"})
+
+	msg := readWebSocketMessage(t, conn, "")
+	require.NotNil(t, msg.Method)
+	assert.Equal(t, "notifications/message", *msg.Method)
+}
+
+// Requirement: Malformed frames get a JSON-RPC parse error and the connection stays usable.
+func Test_SRV_WS_NEG_01_MalformedMessage(t *testing.T) {
+	_, url := setupWebSocketTest(t)
+	conn := dialWebSocket(t, url+"?key=valid-key")
+
+	require.NoError(t, conn.WriteMessage(websocket.TextMessage, []byte(`{not json`)))
+	msg := readWebSocketMessage(t, conn, "")
+	require.NotNil(t, msg.Error)
+	assert.Equal(t, shared.JSONRPCErrorParseError, msg.Error.Code)
+
+	require.NoError(t, conn.WriteMessage(websocket.TextMessage, []byte(createJsonRpcRequestBody(2, "test/method", nil))))
+	assert.NotNil(t, readWebSocketMessage(t, conn, "2").Result)
+}
+
+// Requirement: Connections with an invalid key are rejected before the upgrade.
+func Test_SRV_WS_NEG_02_RejectsInvalidKey(t *testing.T) {
+	mockManager, url := setupWebSocketTest(t)
+
+	_, resp, err := websocket.DefaultDialer.Dial(url+"?key=wrong-key", nil)
+	require.ErrorIs(t, err, websocket.ErrBadHandshake)
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+	assert.Empty(t, mockManager.GetSessions())
+}
+
+// Requirement: Plain HTTP requests to the WebSocket path are not upgraded.
+func Test_SRV_WS_NEG_03_RejectsNonWebSocketRequests(t *testing.T) {
+	mockManager, url := setupWebSocketTest(t)
+	httpURL := "http" + strings.TrimPrefix(url, "ws") + "?key=valid-key"
+
+	resp, err := http.Post(httpURL, "application/json", strings.NewReader(`{}`))
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusMethodNotAllowed, resp.StatusCode)
+
+	resp, err = http.Get(httpURL)
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	assert.Empty(t, mockManager.GetSessions(), "Failed upgrades must not leave sessions behind")
+}
+
+// Requirement: Closing the WebSocket closes the session.
+func Test_SRV_WS_POS_04_ClosesSessionOnDisconnect(t *testing.T) {
+	mockManager, url := setupWebSocketTest(t)
+	conn := dialWebSocket(t, url+"?key=valid-key")
+
+	var sessionID string
+	require.Eventually(t, func() bool {
+		sessions := mockManager.GetSessions()
+		if len(sessions) == 1 {
+			sessionID = sessions[0].GetID()
+		}
+		return sessionID != ""
+	}, time.Second, 10*time.Millisecond)
+
+	require.NoError(t, conn.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseNormalClosure, "")))
+	conn.Close()
+	assert.Eventually(t, func() bool {
+		mockManager.mu.RLock()
+		defer mockManager.mu.RUnlock()
+		return mockManager.ClosedSessions[sessionID]
+	}, 2*time.Second, 20*time.Millisecond)
+}
+
+// Requirement: Closing the session on the server closes the WebSocket.
+func Test_SRV_WS_POS_05_ClosesConnectionWhenSessionCloses(t *testing.T) {
+	mockManager, url := setupWebSocketTest(t)
+	conn := dialWebSocket(t, url+"?key=valid-key")
+
+	require.Eventually(t, func() bool { return len(mockManager.GetSessions()) == 1 }, time.Second, 10*time.Millisecond)
+	mockManager.CloseAllSessions()
+
+	require.NoError(t, conn.SetReadDeadline(time.Now().Add(3*time.Second)))
+	_, _, err := conn.ReadMessage()
+	assert.True(t, websocket.IsCloseError(err, websocket.CloseNormalClosure), "unexpected error: %v", err)
+}
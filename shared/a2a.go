@@ -2,16 +2,37 @@ package shared
 
 import a2aSchema "github.com/gate4ai/gate4ai/shared/a2a/2025-draft/schema"
 
+// A2ASSEEventHistory is the SSE event name used for TaskHistoryUpdateEvent payloads.
+const A2ASSEEventHistory = "history"
+
+// A2AErrorCategory tells a stream client whether retrying the failed request can succeed.
+type A2AErrorCategory string
+
+const (
+	// A2AErrorTransient errors, such as network or storage failures, may succeed on retry.
+	A2AErrorTransient A2AErrorCategory = "transient"
+	// A2AErrorPermanent errors, such as an unknown task or missing authorization, fail again on retry.
+	A2AErrorPermanent A2AErrorCategory = "permanent"
+)
+
 // A2AStreamEvent holds data for A2A SSE events, used internally by transport
 type A2AStreamEvent struct {
-	// Type indicates whether this event is a status update or an artifact update.
-	Type string // "status" or "artifact"
+	// Type indicates whether this event is a status, artifact or history update.
+	Type string // "status", "artifact" or "history"
 	// Status contains the status update event data if Type is "status".
 	Status *a2aSchema.TaskStatusUpdateEvent `json:"status,omitempty"`
 	// Artifact contains the artifact update event data if Type is "artifact".
 	Artifact *a2aSchema.TaskArtifactUpdateEvent `json:"artifact,omitempty"`
+	// History contains the message appended to the task history if Type is "history".
+	History *a2aSchema.TaskHistoryUpdateEvent `json:"history,omitempty"`
+	// ID numbers the event within its task run; it is sent as the SSE event ID (0 lets the transport number events).
+	ID uint64 `json:"-"`
 	// Final indicates if this is the last event for the stream (usually set on the final status update).
 	Final bool `json:"final,omitempty"`
 	// Error holds any error encountered while processing the stream (e.g., parsing error, connection closed).
 	Error error `json:"-"` // Use Go error type for internal handling
+	// ErrorCategory classifies Error for the client; it is sent as the error's "category".
+	ErrorCategory A2AErrorCategory `json:"-"`
+	// RetryAfterSeconds is how long a rate-limited client should wait before retrying; it is sent as the error's "retry_after_seconds".
+	RetryAfterSeconds int `json:"-"`
 }
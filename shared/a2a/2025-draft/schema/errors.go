@@ -9,6 +9,8 @@ const (
 	ErrorCodePushNotificationNotSupported = -32003
 	ErrorCodeUnsupportedOperation         = -32004
 	ErrorCodeContentTypeNotSupported      = -32005
+	ErrorCodeRateLimitExceeded            = -32029 // Implementation-defined: too many requests
+	ErrorCodeTooManyConcurrentTasks       = -32030 // Implementation-defined: the session runs too many tasks
 )
 
 // PushNotificationNotSupportedError indicates the agent does not support push notifications.
@@ -36,12 +38,12 @@ type TaskNotCancelableError struct {
 	Data    any    `json:"data,omitempty"`
 }
 
-func NewTaskNotCancelableError(taskId string) *JSONRPCError {
-	data := map[string]string{"taskId": taskId}
+func NewTaskNotCancelableError(taskId string, currentState TaskState) *JSONRPCError {
+	data := map[string]string{"taskId": taskId, "currentState": string(currentState)}
 	var anyData any = data
 	return &JSONRPCError{
 		Code:    ErrorCodeTaskNotCancelable,
-		Message: fmt.Sprintf("Task '%s' cannot be canceled", taskId),
+		Message: fmt.Sprintf("Task '%s' cannot be canceled in state '%s'", taskId, currentState),
 		Data:    &anyData,
 	}
 }
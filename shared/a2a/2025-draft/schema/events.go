@@ -21,3 +21,14 @@ type TaskArtifactUpdateEvent struct {
 	// Optional metadata associated with the event.
 	Metadata *map[string]interface{} `json:"metadata,omitempty"`
 }
+
+// TaskHistoryUpdateEvent carries a message appended to the task's history during streaming.
+// Sent as a named "history" SSE event so clients can render the conversation as it happens.
+type TaskHistoryUpdateEvent struct {
+	// The ID of the task whose history grew.
+	ID string `json:"id"`
+	// The message appended to the history.
+	Message Message `json:"message"`
+	// Optional metadata associated with the event.
+	Metadata *map[string]interface{} `json:"metadata,omitempty"`
+}
@@ -1,5 +1,7 @@
 package schema
 
+import "time"
+
 // --- Request Parameter Structures ---
 
 // TaskIdParams provides the task ID for operations like cancel or get push config.
@@ -19,6 +21,16 @@ type TaskQueryParams struct {
 	HistoryLength *int `json:"historyLength,omitempty"`
 	// Optional metadata for the request context.
 	Metadata *map[string]interface{} `json:"metadata,omitempty"`
+	// Optional: Include the task's execution timeline, if one was recorded (gate4ai extension).
+	IncludeTimeline bool `json:"includeTimeline,omitempty"`
+}
+
+// TaskResubscribeParams provides parameters for resuming the event stream of a task.
+type TaskResubscribeParams struct {
+	TaskQueryParams
+	// Optional: SSE event ID of the last stream event the client received. Buffered events
+	// after it are replayed before live events (gate4ai extension).
+	LastEventID *uint64 `json:"lastEventId,omitempty"`
 }
 
 // TaskSendParams provides parameters for sending a message to initiate or continue a task.
@@ -35,6 +47,8 @@ type TaskSendParams struct {
 	HistoryLength *int `json:"historyLength,omitempty"`
 	// Optional metadata for the request context.
 	Metadata *map[string]interface{} `json:"metadata,omitempty"`
+	// Optional: Start the task at this time instead of immediately (gate4ai extension). The task stays 'submitted' until then.
+	ScheduledAt *time.Time `json:"scheduledAt,omitempty"`
 }
 
 // --- Concrete Request Structures ---
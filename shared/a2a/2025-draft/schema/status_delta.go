@@ -0,0 +1,131 @@
+package schema
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+)
+
+// StatusDeltaMetadataKey is the TaskStatusUpdateEvent metadata key that marks a delta-encoded status message.
+const StatusDeltaMetadataKey = "statusDelta"
+
+// StatusDelta describes how a delta-encoded status message relates to the previous one in the stream.
+type StatusDelta struct {
+	// Sequence number of the status event within the stream, starting at 1.
+	Seq int `json:"seq"`
+	// Number of parts of the previous status message that precede the parts carried by this event.
+	Offset int `json:"offset"`
+}
+
+// StatusDeltaEncoder strips status message parts that were already sent in the previous status event.
+// One encoder is used per stream; it is not safe for concurrent use.
+type StatusDeltaEncoder struct {
+	seq       int
+	lastParts []Part
+}
+
+// Encode returns a copy of the event whose status message only carries the parts that changed
+// since the previous event. The delta details are stored under StatusDeltaMetadataKey.
+func (e *StatusDeltaEncoder) Encode(event TaskStatusUpdateEvent) TaskStatusUpdateEvent {
+	e.seq++
+	delta := StatusDelta{Seq: e.seq}
+
+	if event.Status.Message != nil {
+		parts := event.Status.Message.Parts
+		for delta.Offset < len(parts) && delta.Offset < len(e.lastParts) && reflect.DeepEqual(parts[delta.Offset], e.lastParts[delta.Offset]) {
+			delta.Offset++
+		}
+		msgCopy := *event.Status.Message
+		msgCopy.Parts = append([]Part{}, parts[delta.Offset:]...)
+		event.Status.Message = &msgCopy
+		e.lastParts = append([]Part{}, parts...)
+	} else {
+		e.lastParts = nil
+	}
+
+	meta := make(map[string]interface{})
+	if event.Metadata != nil {
+		for k, v := range *event.Metadata {
+			meta[k] = v
+		}
+	}
+	meta[StatusDeltaMetadataKey] = delta
+	event.Metadata = &meta
+	return event
+}
+
+// StatusDeltaDecoder rebuilds full status messages from events produced by StatusDeltaEncoder.
+// One decoder is used per stream; it is not safe for concurrent use.
+type StatusDeltaDecoder struct {
+	seq   int
+	parts []Part
+}
+
+// Decode returns the event with its full status message restored. Events without delta
+// metadata are returned unchanged and become the base for subsequent deltas.
+func (d *StatusDeltaDecoder) Decode(event TaskStatusUpdateEvent) (TaskStatusUpdateEvent, error) {
+	delta, ok, err := statusDeltaFromMetadata(event.Metadata)
+	if err != nil {
+		return event, err
+	}
+	if !ok {
+		d.parts = nil
+		if event.Status.Message != nil {
+			d.parts = append([]Part{}, event.Status.Message.Parts...)
+		}
+		return event, nil
+	}
+	if delta.Seq != d.seq+1 {
+		return event, fmt.Errorf("status delta out of sequence: expected %d, got %d", d.seq+1, delta.Seq)
+	}
+	d.seq = delta.Seq
+
+	if event.Status.Message == nil {
+		d.parts = nil
+	} else {
+		if delta.Offset < 0 || delta.Offset > len(d.parts) {
+			return event, fmt.Errorf("status delta offset %d exceeds known parts (%d)", delta.Offset, len(d.parts))
+		}
+		parts := append(append([]Part{}, d.parts[:delta.Offset]...), event.Status.Message.Parts...)
+		msgCopy := *event.Status.Message
+		msgCopy.Parts = parts
+		event.Status.Message = &msgCopy
+		d.parts = append([]Part{}, parts...)
+	}
+
+	meta := make(map[string]interface{}, len(*event.Metadata))
+	for k, v := range *event.Metadata {
+		if k != StatusDeltaMetadataKey {
+			meta[k] = v
+		}
+	}
+	event.Metadata = nil
+	if len(meta) > 0 {
+		event.Metadata = &meta
+	}
+	return event, nil
+}
+
+// statusDeltaFromMetadata extracts StatusDelta from event metadata, which holds either
+// the struct itself or its JSON-decoded map form.
+func statusDeltaFromMetadata(metadata *map[string]interface{}) (StatusDelta, bool, error) {
+	var delta StatusDelta
+	if metadata == nil {
+		return delta, false, nil
+	}
+	raw, ok := (*metadata)[StatusDeltaMetadataKey]
+	if !ok {
+		return delta, false, nil
+	}
+	if d, ok := raw.(StatusDelta); ok {
+		return d, true, nil
+	}
+	data, err := json.Marshal(raw)
+	if err != nil {
+		return delta, false, fmt.Errorf("invalid status delta metadata: %w", err)
+	}
+	if err := json.Unmarshal(data, &delta); err != nil {
+		return delta, false, fmt.Errorf("invalid status delta metadata: %w", err)
+	}
+	return delta, true, nil
+}
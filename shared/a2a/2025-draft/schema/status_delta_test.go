@@ -0,0 +1,78 @@
+package schema
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestStatusDeltaRoundTrip(t *testing.T) {
+	var encoder StatusDeltaEncoder
+	var decoder StatusDeltaDecoder
+
+	// A handler yields 10 status updates, each repeating earlier parts and adding one more
+	var parts []Part
+	var last TaskStatusUpdateEvent
+	for i := 1; i <= 10; i++ {
+		text := fmt.Sprintf("chunk %d;", i)
+		parts = append(parts, Part{Type: strPtr("text"), Text: &text})
+		event := TaskStatusUpdateEvent{
+			ID:     "task-1",
+			Status: TaskStatus{State: TaskStateWorking, Message: &Message{Role: "agent", Parts: append([]Part{}, parts...)}},
+			Final:  i == 10,
+		}
+
+		encoded := encoder.Encode(event)
+		if got := len(encoded.Status.Message.Parts); got != 1 {
+			t.Fatalf("Event %d: expected 1 delta part, got %d", i, got)
+		}
+
+		// Send the encoded event over the wire, as the SSE transport does
+		data, err := json.Marshal(encoded)
+		if err != nil {
+			t.Fatalf("Event %d: failed to marshal: %v", i, err)
+		}
+		var received TaskStatusUpdateEvent
+		if err := json.Unmarshal(data, &received); err != nil {
+			t.Fatalf("Event %d: failed to unmarshal: %v", i, err)
+		}
+
+		last, err = decoder.Decode(received)
+		if err != nil {
+			t.Fatalf("Event %d: failed to decode: %v", i, err)
+		}
+		if got := len(last.Status.Message.Parts); got != i {
+			t.Fatalf("Event %d: expected %d reconstructed parts, got %d", i, i, got)
+		}
+		if last.Metadata != nil {
+			t.Errorf("Event %d: expected delta metadata to be removed, got %v", i, *last.Metadata)
+		}
+	}
+
+	var full strings.Builder
+	for _, p := range last.Status.Message.Parts {
+		full.WriteString(*p.Text)
+	}
+	want := "chunk 1;chunk 2;chunk 3;chunk 4;chunk 5;chunk 6;chunk 7;chunk 8;chunk 9;chunk 10;"
+	if full.String() != want {
+		t.Errorf("Reconstructed text mismatch:\n got: %s\nwant: %s", full.String(), want)
+	}
+}
+
+func TestStatusDeltaDecodeOutOfSequence(t *testing.T) {
+	text := "hello"
+	event := TaskStatusUpdateEvent{
+		ID:       "task-1",
+		Status:   TaskStatus{State: TaskStateWorking, Message: &Message{Role: "agent", Parts: []Part{{Text: &text}}}},
+		Metadata: &map[string]interface{}{StatusDeltaMetadataKey: StatusDelta{Seq: 2}},
+	}
+	var decoder StatusDeltaDecoder
+	if _, err := decoder.Decode(event); err == nil {
+		t.Fatalf("Expected an error for a delta that skips a sequence number")
+	}
+}
+
+func strPtr(s string) *string {
+	return &s
+}
@@ -10,6 +10,8 @@ type TaskState string
 const (
 	// TaskStateSubmitted indicates the task has been received but not yet started processing.
 	TaskStateSubmitted TaskState = "submitted"
+	// TaskStateQueued indicates the task waits for a free slot before processing starts (implementation-defined).
+	TaskStateQueued TaskState = "queued"
 	// TaskStateWorking indicates the task is actively being processed by the agent.
 	TaskStateWorking TaskState = "working"
 	// TaskStateInputRequired indicates the agent requires additional input from the client to proceed.
@@ -48,4 +50,22 @@ type Task struct {
 	History []Message `json:"history,omitempty"`
 	// Optional metadata associated with the task.
 	Metadata *map[string]interface{} `json:"metadata,omitempty"`
+	// Optional: Execution phases recorded for profiling (gate4ai extension, see TaskQueryParams.IncludeTimeline).
+	Timeline []TimelineEntry `json:"timeline,omitempty"`
+	// Optional: Time a deferred task is due to start (gate4ai extension, see TaskSendParams.ScheduledAt). Cleared once it starts.
+	ScheduledAt *time.Time `json:"scheduledAt,omitempty"`
+	// Optional: Time the task was created (gate4ai extension). Set by the server; tasks/list pages are ordered by it.
+	CreatedAt time.Time `json:"createdAt,omitzero"`
+	// Optional: User that created the task (gate4ai extension). Set by the server, which only lets that user see or configure the task.
+	UserID string `json:"userId,omitempty"`
+}
+
+// TimelineEntry records how long one phase of a task's execution took.
+type TimelineEntry struct {
+	// Name of the phase, e.g. "handler".
+	Phase string `json:"phase"`
+	// When the phase started.
+	Timestamp time.Time `json:"timestamp"`
+	// How long the phase took, in nanoseconds when encoded as JSON.
+	Duration time.Duration `json:"duration"`
 }
@@ -10,6 +10,8 @@ type ICapability interface {
 
 type IServerCapability interface {
 	SetCapabilities(s *schema.ServerCapabilities)
+	// SetEventBus gives the capability the server's EventBus, before the server starts.
+	SetEventBus(bus EventBus)
 }
 
 type IClientCapability interface {
@@ -0,0 +1,79 @@
+package shared
+
+import "sync"
+
+// CapabilityCache holds the capabilities registered on an Input and resolves method names to
+// their handlers. The handler table is built from GetHandlers() of every capability on the first
+// lookup and reused until OnCapabilityChanged is called, so each lookup is a single map access.
+// When several capabilities handle a method, the last registered one wins.
+type CapabilityCache struct {
+	mu           sync.RWMutex
+	capabilities []ICapability
+	handlers     map[string]func(*Message) (interface{}, error) // nil until the next lookup rebuilds it
+}
+
+// NewCapabilityCache creates an empty cache.
+func NewCapabilityCache() *CapabilityCache {
+	return &CapabilityCache{}
+}
+
+// Add registers a capability and invalidates the handler table.
+func (c *CapabilityCache) Add(capability ICapability) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.capabilities = append(c.capabilities, capability)
+	c.handlers = nil
+}
+
+// OnCapabilityChanged invalidates the handler table. Call it when the handlers returned by a
+// registered capability change.
+func (c *CapabilityCache) OnCapabilityChanged() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.handlers = nil
+}
+
+// Lookup returns the handler of method.
+func (c *CapabilityCache) Lookup(method string) (func(*Message) (interface{}, error), bool) {
+	c.mu.RLock()
+	handlers := c.handlers
+	c.mu.RUnlock()
+	if handlers == nil {
+		handlers = c.resolve()
+	}
+	handler, ok := handlers[method]
+	return handler, ok
+}
+
+// Handlers returns the handler table. It must not be modified.
+func (c *CapabilityCache) Handlers() map[string]func(*Message) (interface{}, error) {
+	c.mu.RLock()
+	handlers := c.handlers
+	c.mu.RUnlock()
+	if handlers == nil {
+		handlers = c.resolve()
+	}
+	return handlers
+}
+
+// Capabilities returns a snapshot of the registered capabilities in registration order.
+func (c *CapabilityCache) Capabilities() []ICapability {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return append([]ICapability(nil), c.capabilities...)
+}
+
+// resolve rebuilds the handler table unless another lookup already did.
+func (c *CapabilityCache) resolve() map[string]func(*Message) (interface{}, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.handlers == nil {
+		c.handlers = make(map[string]func(*Message) (interface{}, error))
+		for _, capability := range c.capabilities {
+			for method, handler := range capability.GetHandlers() {
+				c.handlers[method] = handler
+			}
+		}
+	}
+	return c.handlers
+}
@@ -0,0 +1,84 @@
+package shared
+
+import (
+	"fmt"
+	"testing"
+
+	"go.uber.org/zap"
+)
+
+// countingCapability handles count methods named <prefix>/<n> and counts GetHandlers calls.
+type countingCapability struct {
+	handlers map[string]func(*Message) (interface{}, error)
+	calls    int
+}
+
+func newCountingCapability(prefix string, count int) *countingCapability {
+	c := &countingCapability{handlers: make(map[string]func(*Message) (interface{}, error))}
+	for n := range count {
+		result := fmt.Sprintf("%s/%d", prefix, n)
+		c.handlers[result] = func(*Message) (interface{}, error) { return result, nil }
+	}
+	return c
+}
+
+func (c *countingCapability) GetHandlers() map[string]func(*Message) (interface{}, error) {
+	c.calls++
+	return c.handlers
+}
+
+// newCachedInput registers 5 capabilities with 10 handlers each; the last one handles tools/call.
+func newCachedInput() (*Input, []*countingCapability) {
+	input := NewInput(zap.NewNop())
+	var capabilities []*countingCapability
+	for i := range 5 {
+		capability := newCountingCapability(fmt.Sprintf("capability%d", i), 9)
+		capability.handlers["tools/call"] = func(*Message) (interface{}, error) { return fmt.Sprintf("tools/call from %d", i), nil }
+		input.addCapability(capability)
+		capabilities = append(capabilities, capability)
+	}
+	return input, capabilities
+}
+
+func TestCapabilityCacheResolvesHandlersOnce(t *testing.T) {
+	input, capabilities := newCachedInput()
+	callsAfterRegistration := capabilities[0].calls
+
+	for range 50 {
+		handler, ok := input.GetHandler("tools/call")
+		if !ok {
+			t.Fatal("tools/call handler not found")
+		}
+		// The last registered capability wins
+		if result, _ := handler(nil); result != "tools/call from 4" {
+			t.Fatalf("Unexpected handler result %v", result)
+		}
+	}
+	for i, capability := range capabilities {
+		if calls := capability.calls - callsAfterRegistration; calls != 1 {
+			t.Errorf("Capability %d: GetHandlers called %d times for 50 lookups, want 1", i, calls)
+		}
+	}
+
+	// Handlers added after registration are found once the cache is invalidated
+	capabilities[2].handlers["capability2/extra"] = func(*Message) (interface{}, error) { return "extra", nil }
+	if _, ok := input.capabilities.Lookup("capability2/extra"); ok {
+		t.Error("Cached lookup saw a handler added without OnCapabilityChanged")
+	}
+	input.OnCapabilityChanged()
+	if _, ok := input.capabilities.Lookup("capability2/extra"); !ok {
+		t.Error("Handler added before OnCapabilityChanged not found")
+	}
+}
+
+func BenchmarkCapabilityCacheLookup(b *testing.B) {
+	input, _ := newCachedInput()
+	b.ResetTimer()
+	for range b.N {
+		for range 50 {
+			if _, ok := input.GetHandler("tools/call"); !ok {
+				b.Fatal("tools/call handler not found")
+			}
+		}
+	}
+}
@@ -0,0 +1,100 @@
+package config
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// ConsulSlugTagPrefix marks the service tag that holds the gate4ai server slug, e.g. "gate4ai-slug=weather".
+const ConsulSlugTagPrefix = "gate4ai-slug="
+
+var _ ServiceDiscovery = (*ConsulBackendDiscovery)(nil)
+
+// ConsulBackendDiscovery finds backends among the healthy instances of a Consul service.
+// Each instance must carry a ConsulSlugTagPrefix tag; instances without one are skipped.
+type ConsulBackendDiscovery struct {
+	consulURL   string // Consul HTTP API address, e.g. http://consul:8500
+	serviceName string
+	backendPath string // Path appended to instance address, e.g. "/sse"
+	client      *http.Client
+	logger      *zap.Logger
+}
+
+// NewConsulBackendDiscovery creates a discovery for the instances of serviceName registered in Consul.
+func NewConsulBackendDiscovery(consulURL string, serviceName string, backendPath string, logger *zap.Logger) *ConsulBackendDiscovery {
+	return &ConsulBackendDiscovery{
+		consulURL:   strings.TrimRight(consulURL, "/"),
+		serviceName: serviceName,
+		backendPath: backendPath,
+		client:      &http.Client{Timeout: 10 * time.Second},
+		logger:      logger,
+	}
+}
+
+// consulServiceEntry is the part of Consul's /v1/health/service response we use.
+type consulServiceEntry struct {
+	Node struct {
+		Address string
+	}
+	Service struct {
+		ID      string
+		Address string
+		Port    int
+		Tags    []string
+	}
+}
+
+// Discover queries Consul for passing instances of the service and maps them to backends.
+func (d *ConsulBackendDiscovery) Discover(ctx context.Context) ([]Backend, error) {
+	endpoint := fmt.Sprintf("%s/v1/health/service/%s?passing=true", d.consulURL, url.PathEscape(d.serviceName))
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, fmt.Errorf("consul request: %w", err)
+	}
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("consul request: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("consul returned HTTP %d", resp.StatusCode)
+	}
+
+	var entries []consulServiceEntry
+	if err := json.NewDecoder(resp.Body).Decode(&entries); err != nil {
+		return nil, fmt.Errorf("decode consul response: %w", err)
+	}
+
+	backends := make([]Backend, 0, len(entries))
+	for _, entry := range entries {
+		slug := ""
+		for _, tag := range entry.Service.Tags {
+			if strings.HasPrefix(tag, ConsulSlugTagPrefix) {
+				slug = strings.TrimPrefix(tag, ConsulSlugTagPrefix)
+				break
+			}
+		}
+		if slug == "" {
+			d.logger.Debug("Skipping Consul instance without slug tag", zap.String("serviceID", entry.Service.ID))
+			continue
+		}
+		host := entry.Service.Address
+		if host == "" {
+			host = entry.Node.Address // Consul leaves Service.Address empty when it equals the node address
+		}
+		backends = append(backends, Backend{
+			Slug: slug,
+			URL:  "http://" + net.JoinHostPort(host, strconv.Itoa(entry.Service.Port)) + d.backendPath,
+		})
+	}
+	return backends, nil
+}
@@ -0,0 +1,99 @@
+package config
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"go.uber.org/zap"
+)
+
+// fakeConsul serves /v1/health/service/<name> from a mutable instance list.
+type fakeConsul struct {
+	mu        sync.Mutex
+	instances []map[string]interface{}
+}
+
+func (f *fakeConsul) set(instances ...map[string]interface{}) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.instances = instances
+}
+
+func (f *fakeConsul) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path != "/v1/health/service/mcp" || r.URL.Query().Get("passing") != "true" {
+		http.NotFound(w, r)
+		return
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	_ = json.NewEncoder(w).Encode(f.instances)
+}
+
+func consulInstance(nodeAddr, serviceAddr string, port int, tags ...string) map[string]interface{} {
+	return map[string]interface{}{
+		"Node":    map[string]interface{}{"Address": nodeAddr},
+		"Service": map[string]interface{}{"ID": serviceAddr, "Address": serviceAddr, "Port": port, "Tags": tags},
+	}
+}
+
+func TestDynamicConfigWithConsulDiscovery(t *testing.T) {
+	consul := &fakeConsul{}
+	server := httptest.NewServer(consul)
+	defer server.Close()
+
+	static := NewInternalConfig()
+	static.Backends["static"] = &Backend{URL: "http://static:8080/sse"}
+	static.Backends["weather"] = &Backend{URL: "http://old-weather:8080/sse"}
+
+	cfg := NewDynamicConfig(static, NewConsulBackendDiscovery(server.URL, "mcp", "/sse", zap.NewNop()), zap.NewNop())
+	backendURL := func(slug string) string {
+		b, err := cfg.GetBackendBySlug(slug)
+		if err != nil {
+			return err.Error()
+		}
+		return b.URL
+	}
+
+	consul.set(
+		consulInstance("10.0.0.1", "10.1.0.5", 8080, "gate4ai-slug=weather"),
+		consulInstance("10.0.0.2", "", 9090, "other", "gate4ai-slug=search"),
+		consulInstance("10.0.0.3", "10.1.0.7", 8080, "untagged"),
+	)
+	if err := cfg.Refresh(context.Background()); err != nil {
+		t.Fatalf("Refresh failed: %v", err)
+	}
+	if got := backendURL("weather"); got != "http://10.1.0.5:8080/sse" {
+		t.Errorf("Discovered backend should override static one, got %q", got)
+	}
+	if got := backendURL("search"); got != "http://10.0.0.2:9090/sse" {
+		t.Errorf("Expected node address when service address is empty, got %q", got)
+	}
+	if got := backendURL("static"); got != "http://static:8080/sse" {
+		t.Errorf("Static backend should still be served, got %q", got)
+	}
+
+	// The weather pod moved and search disappeared
+	consul.set(consulInstance("10.0.0.1", "10.1.0.9", 8080, "gate4ai-slug=weather"))
+	if err := cfg.Refresh(context.Background()); err != nil {
+		t.Fatalf("Refresh failed: %v", err)
+	}
+	if got := backendURL("weather"); got != "http://10.1.0.9:8080/sse" {
+		t.Errorf("Expected updated weather URL, got %q", got)
+	}
+	if got := backendURL("search"); got != ErrNotFound.Error() {
+		t.Errorf("Expected search to be gone, got %q", got)
+	}
+
+	// A failing registry keeps the last known backends
+	server.Close()
+	if err := cfg.Refresh(context.Background()); err == nil {
+		t.Errorf("Expected Refresh to fail when Consul is unreachable")
+	}
+	if got := backendURL("weather"); got != "http://10.1.0.9:8080/sse" {
+		t.Errorf("Expected previous weather URL to be kept, got %q", got)
+	}
+}
@@ -6,10 +6,13 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"strconv"
 	"strings"
+	"time"
 
 	a2aSchema "github.com/gate4ai/gate4ai/shared/a2a/2025-draft/schema"
-	_ "github.com/lib/pq"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/jackc/pgx/v5/stdlib"
 	"go.uber.org/zap"
 )
 
@@ -19,11 +22,18 @@ var _ IConfig = (*DatabaseConfig)(nil)
 type DatabaseConfig struct {
 	logger             *zap.Logger
 	dbConnectionString string
+	pool               *pgxpool.Pool
+	db                 *sql.DB // Queries go through the pool
 }
 
-// DatabaseConfigOptions contains options for configuring the DatabaseConfig
+// DatabaseConfigOptions contains options for configuring the DatabaseConfig.
+// Pool settings can also be given in the connection string (pool_max_conns, pool_min_conns,
+// pool_max_conn_lifetime, pool_health_check_period); zero-valued options keep those.
 type DatabaseConfigOptions struct {
-	// Optional parameters for database configuration
+	PoolMaxConns          int32
+	PoolMinConns          int32
+	PoolMaxConnLifetime   time.Duration
+	PoolHealthCheckPeriod time.Duration
 }
 
 // DefaultDatabaseConfigOptions returns the default options for DatabaseConfig
@@ -38,16 +48,51 @@ func NewDatabaseConfig(dbConnectionString string, logger *zap.Logger) (*Database
 
 // NewDatabaseConfigWithOptions creates a new DatabaseConfig instance with the specified options
 func NewDatabaseConfigWithOptions(dbConnectionString string, logger *zap.Logger, options DatabaseConfigOptions) (*DatabaseConfig, error) {
+	poolConfig, err := pgxpool.ParseConfig(dbConnectionString)
+	if err != nil {
+		return nil, fmt.Errorf("invalid database connection string: %w", err)
+	}
+	if options.PoolMaxConns > 0 {
+		poolConfig.MaxConns = options.PoolMaxConns
+	}
+	if options.PoolMinConns > 0 {
+		poolConfig.MinConns = options.PoolMinConns
+	}
+	if options.PoolMaxConnLifetime > 0 {
+		poolConfig.MaxConnLifetime = options.PoolMaxConnLifetime
+	}
+	if options.PoolHealthCheckPeriod > 0 {
+		poolConfig.HealthCheckPeriod = options.PoolHealthCheckPeriod
+	}
+	// Connections are opened on first use, so the database may start after the gateway
+	pool, err := pgxpool.NewWithConfig(context.Background(), poolConfig)
+	if err != nil {
+		return nil, fmt.Errorf("create database pool: %w", err)
+	}
+	logger.Debug("Created database pool",
+		zap.Int32("maxConns", poolConfig.MaxConns),
+		zap.Int32("minConns", poolConfig.MinConns),
+		zap.Duration("maxConnLifetime", poolConfig.MaxConnLifetime),
+		zap.Duration("healthCheckPeriod", poolConfig.HealthCheckPeriod))
 	config := &DatabaseConfig{
 		dbConnectionString: dbConnectionString,
 		logger:             logger,
+		pool:               pool,
+		db:                 stdlib.OpenDBFromPool(pool),
 	}
 	return config, nil
 }
 
-// Close closes any resources held by the config
+// Close closes the database pool
 func (c *DatabaseConfig) Close() error {
-	return nil
+	err := c.db.Close()
+	c.pool.Close()
+	return err
+}
+
+// PoolStats returns the current statistics of the database pool.
+func (c *DatabaseConfig) PoolStats() *pgxpool.Stat {
+	return c.pool.Stat()
 }
 
 // --- IConfig Implementation ---
@@ -94,15 +139,11 @@ func (c *DatabaseConfig) GetUserIDByKeyHash(keyHash string) (string, error) {
 		return "", nil
 	}
 
-	db, err := sql.Open("postgres", c.dbConnectionString)
-	if err != nil {
-		return "", fmt.Errorf("db connect: %w", err)
-	}
-	defer db.Close()
+	db := c.db
 
 	query := `SELECT "userId" FROM "ApiKey" WHERE "keyHash" = $1 LIMIT 1`
 	var userID string
-	err = db.QueryRow(query, keyHash).Scan(&userID)
+	err := db.QueryRow(query, keyHash).Scan(&userID)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return "", ErrNotFound
@@ -113,15 +154,11 @@ func (c *DatabaseConfig) GetUserIDByKeyHash(keyHash string) (string, error) {
 }
 
 func (c *DatabaseConfig) GetUserParams(userID string) (map[string]string, error) {
-	db, err := sql.Open("postgres", c.dbConnectionString)
-	if err != nil {
-		return nil, fmt.Errorf("db connect: %w", err)
-	}
-	defer db.Close()
+	db := c.db
 
 	query := `SELECT name, status, role, company FROM "User" WHERE id = $1 LIMIT 1`
 	var name, status, role, company sql.NullString
-	err = db.QueryRow(query, userID).Scan(&name, &status, &role, &company)
+	err := db.QueryRow(query, userID).Scan(&name, &status, &role, &company)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return make(map[string]string), nil
@@ -145,11 +182,7 @@ func (c *DatabaseConfig) GetUserParams(userID string) (map[string]string, error)
 }
 
 func (c *DatabaseConfig) GetUserSubscribes(userID string) ([]string, error) {
-	db, err := sql.Open("postgres", c.dbConnectionString)
-	if err != nil {
-		return nil, fmt.Errorf("db connect: %w", err)
-	}
-	defer db.Close()
+	db := c.db
 
 	userParams, err := c.GetUserParams(userID)
 	if err != nil {
@@ -193,15 +226,11 @@ func (c *DatabaseConfig) GetUserSubscribes(userID string) ([]string, error) {
 }
 
 func (c *DatabaseConfig) GetBackendBySlug(backendSlug string) (*Backend, error) {
-	db, err := sql.Open("postgres", c.dbConnectionString)
-	if err != nil {
-		return nil, fmt.Errorf("db connect: %w", err)
-	}
-	defer db.Close()
+	db := c.db
 
 	query := `SELECT "serverUrl" FROM "Server" WHERE slug = $1 LIMIT 1`
 	var serverURL sql.NullString
-	err = db.QueryRow(query, backendSlug).Scan(&serverURL)
+	err := db.QueryRow(query, backendSlug).Scan(&serverURL)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, ErrNotFound
@@ -215,17 +244,24 @@ func (c *DatabaseConfig) GetBackendBySlug(backendSlug string) (*Backend, error)
 	return &Backend{URL: serverURL.String}, nil
 }
 
+// IsDestructiveBlocked always returns false: the portal database has no such setting yet.
+func (c *DatabaseConfig) IsDestructiveBlocked(serverSlug string) (bool, error) {
+	return false, nil
+}
+
+// GetFeatureFlag always returns ErrNotFound: the portal database has no feature flags yet,
+// so callers fall back to their defaults.
+func (c *DatabaseConfig) GetFeatureFlag(name string, userID string) (bool, error) {
+	return false, ErrNotFound
+}
+
 // NEW: GetServerHeaders retrieves the server-specific headers.
 func (c *DatabaseConfig) GetServerHeaders(serverSlug string) (map[string]string, error) {
-	db, err := sql.Open("postgres", c.dbConnectionString)
-	if err != nil {
-		return nil, fmt.Errorf("db connect: %w", err)
-	}
-	defer db.Close()
+	db := c.db
 
 	query := `SELECT headers FROM "Server" WHERE slug = $1 LIMIT 1`
 	var headersJSON sql.NullString
-	err = db.QueryRow(query, serverSlug).Scan(&headersJSON)
+	err := db.QueryRow(query, serverSlug).Scan(&headersJSON)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, ErrNotFound
@@ -247,16 +283,12 @@ func (c *DatabaseConfig) GetServerHeaders(serverSlug string) (map[string]string,
 
 // NEW: GetSubscriptionHeaders retrieves the subscription-specific headers for a user and server.
 func (c *DatabaseConfig) GetSubscriptionHeaders(userID, serverSlug string) (map[string]string, error) {
-	db, err := sql.Open("postgres", c.dbConnectionString)
-	if err != nil {
-		return nil, fmt.Errorf("db connect: %w", err)
-	}
-	defer db.Close()
+	db := c.db
 
 	// Find server ID first
 	serverIDQuery := `SELECT id FROM "Server" WHERE slug = $1 LIMIT 1`
 	var serverID string
-	err = db.QueryRow(serverIDQuery, serverSlug).Scan(&serverID)
+	err := db.QueryRow(serverIDQuery, serverSlug).Scan(&serverID)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, fmt.Errorf("server not found for slug '%s'", serverSlug)
@@ -303,14 +335,31 @@ func (c *DatabaseConfig) DiscoveringHandlerPath() (string, error) {
 func (c *DatabaseConfig) FrontendAddressForProxy() (string, error) {
 	return c.getSettingString("url_how_gateway_proxy_connect_to_the_portal", "http://portal:3000")
 }
-func (c *DatabaseConfig) Status(ctx context.Context) error {
-	db, err := sql.Open("postgres", c.dbConnectionString)
+func (c *DatabaseConfig) AllowedOrigins() ([]string, error) {
+	return c.getSettingStringSlice("gateway_allowed_origins", []string{})
+}
+func (c *DatabaseConfig) SSEKeepAliveInterval() (time.Duration, error) {
+	value, err := c.getSettingString("gateway_sse_keepalive_interval", DefaultSSEKeepAliveInterval.String())
 	if err != nil {
-		c.logger.Error("DB connect failed", zap.Error(err))
-		return err
+		return DefaultSSEKeepAliveInterval, err
+	}
+	interval, err := time.ParseDuration(value)
+	if err != nil {
+		return DefaultSSEKeepAliveInterval, fmt.Errorf("invalid gateway_sse_keepalive_interval '%s': %w", value, err)
 	}
-	defer db.Close()
-	if err = db.PingContext(ctx); err != nil {
+	return interval, nil
+}
+func (c *DatabaseConfig) AdminToken() (string, error) {
+	return c.getSettingString("gateway_admin_token", "")
+}
+func (c *DatabaseConfig) MaxSubscriptionsPerUser() (int, error) {
+	return c.getSettingInt("gateway_max_subscriptions_per_user", 0)
+}
+func (c *DatabaseConfig) Schema() ConfigSchema {
+	return DefaultConfigSchema()
+}
+func (c *DatabaseConfig) Status(ctx context.Context) error {
+	if err := c.db.PingContext(ctx); err != nil {
 		c.logger.Error("DB ping failed", zap.Error(err))
 		return err
 	}
@@ -397,15 +446,48 @@ func (c *DatabaseConfig) GetA2AAgentCard(agentURL string) (*a2aSchema.AgentCard,
 	return info, nil
 }
 
-// --- Database Helper Functions (Unchanged) ---
-func (c *DatabaseConfig) getSettingRaw(key string) ([]byte, error) {
-	db, err := sql.Open("postgres", c.dbConnectionString)
+// GetA2ABackendForUser resolves the URL of the A2A backend the user's tasks are routed to.
+// Without an explicit agentSlug the first active A2A server the user can access is used.
+func (c *DatabaseConfig) GetA2ABackendForUser(userID string, agentSlug string) (string, error) {
+	subscribes, err := c.GetUserSubscribes(userID)
+	if err != nil {
+		return "", fmt.Errorf("get user subscriptions: %w", err)
+	}
+	if len(subscribes) == 0 {
+		return "", ErrNotFound
+	}
+
+	db := c.db
+
+	query := `SELECT slug, "serverUrl" FROM "Server"
+		WHERE slug = ANY($1) AND protocol = 'A2A' AND "serverUrl" IS NOT NULL
+		ORDER BY slug`
+	rows, err := db.Query(query, subscribes)
 	if err != nil {
-		return nil, fmt.Errorf("db connect: %w", err)
+		return "", fmt.Errorf("query A2A backends: %w", err)
 	}
-	defer db.Close()
+	defer rows.Close()
+
+	for rows.Next() {
+		var slug, serverURL string
+		if scanErr := rows.Scan(&slug, &serverURL); scanErr != nil {
+			return "", fmt.Errorf("scan A2A backend: %w", scanErr)
+		}
+		if agentSlug == "" || agentSlug == slug {
+			return serverURL, nil
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return "", fmt.Errorf("iterate A2A backends: %w", err)
+	}
+	return "", ErrNotFound
+}
+
+// --- Database Helper Functions (Unchanged) ---
+func (c *DatabaseConfig) getSettingRaw(key string) ([]byte, error) {
+	db := c.db
 	var valueStr sql.NullString // Use NullString to handle potential NULL
-	err = db.QueryRowContext(context.Background(), `SELECT value FROM "Settings" WHERE key = $1 LIMIT 1`, key).Scan(&valueStr)
+	err := db.QueryRowContext(context.Background(), `SELECT value FROM "Settings" WHERE key = $1 LIMIT 1`, key).Scan(&valueStr)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, ErrNotFound
@@ -445,6 +527,27 @@ func (c *DatabaseConfig) getSettingString(key string, defaultValue string) (stri
 		return defaultValue, fmt.Errorf("setting '%s' has unexpected type %T", key, value)
 	}
 }
+func (c *DatabaseConfig) getSettingInt(key string, defaultValue int) (int, error) {
+	value, err := c.getSettingJSON(key)
+	if err != nil {
+		if errors.Is(err, ErrNotFound) {
+			return defaultValue, nil
+		}
+		return defaultValue, err
+	}
+	switch v := value.(type) {
+	case float64:
+		return int(v), nil
+	case string:
+		intValue, err := strconv.Atoi(strings.TrimSpace(v))
+		if err != nil {
+			return defaultValue, fmt.Errorf("setting '%s' is not an integer: %w", key, err)
+		}
+		return intValue, nil
+	default:
+		return defaultValue, fmt.Errorf("setting '%s' is not a number (type: %T)", key, value)
+	}
+}
 func (c *DatabaseConfig) getSettingBool(key string, defaultValue bool) (bool, error) {
 	value, err := c.getSettingJSON(key)
 	if err != nil {
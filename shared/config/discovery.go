@@ -0,0 +1,97 @@
+package config
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// ServiceDiscovery finds backends at runtime, e.g. from a service registry.
+type ServiceDiscovery interface {
+	// Discover returns the currently available backends. Each Backend must have Slug set.
+	Discover(ctx context.Context) ([]Backend, error)
+}
+
+var _ IConfig = (*DynamicConfig)(nil)
+
+// DynamicConfig wraps a static IConfig and overlays backends found by a ServiceDiscovery.
+// Discovered backends take precedence over static ones with the same slug; everything
+// else (users, subscriptions, headers) comes from the static config.
+type DynamicConfig struct {
+	IConfig
+	discovery ServiceDiscovery
+	logger    *zap.Logger
+
+	mu       sync.RWMutex
+	backends map[string]*Backend
+}
+
+// NewDynamicConfig creates a DynamicConfig. Call Refresh or Run to load discovered backends.
+func NewDynamicConfig(static IConfig, discovery ServiceDiscovery, logger *zap.Logger) *DynamicConfig {
+	return &DynamicConfig{
+		IConfig:   static,
+		discovery: discovery,
+		logger:    logger,
+		backends:  make(map[string]*Backend),
+	}
+}
+
+// Refresh replaces the discovered backends with a fresh discovery result.
+// On error the previously discovered backends are kept.
+func (c *DynamicConfig) Refresh(ctx context.Context) error {
+	discovered, err := c.discovery.Discover(ctx)
+	if err != nil {
+		return err
+	}
+	backends := make(map[string]*Backend, len(discovered))
+	for i := range discovered {
+		if discovered[i].Slug == "" {
+			c.logger.Warn("Ignoring discovered backend without slug", zap.String("url", discovered[i].URL))
+			continue
+		}
+		b := discovered[i]
+		backends[b.Slug] = &b
+	}
+	c.mu.Lock()
+	c.backends = backends
+	c.mu.Unlock()
+	c.logger.Debug("Refreshed discovered backends", zap.Int("count", len(backends)))
+	return nil
+}
+
+// Run refreshes the discovered backends every interval until ctx is done.
+func (c *DynamicConfig) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		if err := c.Refresh(ctx); err != nil {
+			c.logger.Warn("Backend discovery failed, keeping previous backends", zap.Error(err))
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// GetBackendBySlug returns the discovered backend for slug, falling back to the static config.
+func (c *DynamicConfig) GetBackendBySlug(slug string) (*Backend, error) {
+	c.mu.RLock()
+	backend, exists := c.backends[slug]
+	c.mu.RUnlock()
+	if exists {
+		bc := *backend
+		return &bc, nil
+	}
+	return c.IConfig.GetBackendBySlug(slug)
+}
+
+// Subscribe forwards the change events of the static config, if it reloads at runtime.
+func (c *DynamicConfig) Subscribe(ch chan<- ConfigChangeEvent) {
+	if notifier, ok := c.IConfig.(ChangeNotifier); ok {
+		notifier.Subscribe(ch)
+	}
+}
@@ -4,6 +4,8 @@ import (
 	"context"
 	"crypto/sha256"
 	"encoding/hex"
+	"errors"
+	"time"
 
 	a2aSchema "github.com/gate4ai/gate4ai/shared/a2a/2025-draft/schema"
 )
@@ -30,8 +32,83 @@ func (at AuthorizationType) String() string {
 }
 
 type Backend struct {
+	Slug   string // Set by ServiceDiscovery; static configs key backends by slug instead
 	URL    string
 	Bearer string
+	// BlockDestructiveTools makes the gateway reject calls to tools annotated with destructiveHint
+	BlockDestructiveTools bool
+	// HealthPath is probed by the gateway before routing to a new backend session. It is resolved
+	// against URL, so "" (meaning "health") probes /prefix/health for a backend at /prefix/mcp.
+	HealthPath string
+	// RestTools makes the backend a REST API whose endpoints are exposed as MCP tools
+	RestTools []RestTool
+	// TLS configures the gateway's HTTPS connections to the backend (nil uses the system defaults)
+	TLS *BackendTLSConfig
+}
+
+// BackendTLSConfig configures the TLS client of the gateway for one backend.
+type BackendTLSConfig struct {
+	// CertFile and KeyFile hold the PEM client certificate presented to the backend (mTLS)
+	CertFile string
+	KeyFile  string
+	// CAFile holds the PEM CA certificates trusted for the backend instead of the system pool
+	CAFile string
+	// InsecureSkipVerify disables verification of the backend certificate
+	InsecureSkipVerify bool
+}
+
+// RestTool maps an MCP tool of a REST backend to an HTTP endpoint.
+type RestTool struct {
+	Name        string
+	Description string
+	// URL is resolved against the backend URL and may contain {name} placeholders, e.g. /users/{id}
+	URL string
+	// Method is the HTTP method, GET by default
+	Method string
+	// PathParams are the arguments substituted into the URL placeholders
+	PathParams []string
+	// BodyArgs are the arguments sent as a JSON object body; the remaining arguments become query parameters
+	BodyArgs []string
+	// ResponseMapping is a dot-separated path to the part of the JSON response returned as the tool result ("" returns the whole body)
+	ResponseMapping string
+}
+
+// FeatureFlag gates an optional behavior, globally or per user.
+type FeatureFlag struct {
+	Enabled bool
+	// UserOverrides maps user IDs to a value that replaces Enabled for that user
+	UserOverrides map[string]bool
+}
+
+// EnabledFor reports whether the flag is enabled for userID.
+func (f *FeatureFlag) EnabledFor(userID string) bool {
+	if enabled, ok := f.UserOverrides[userID]; ok {
+		return enabled
+	}
+	return f.Enabled
+}
+
+// DefaultSSEKeepAliveInterval is used when SSEKeepAliveInterval is not configured; it stays below
+// the 60 second idle timeout of common proxies.
+const DefaultSSEKeepAliveInterval = 15 * time.Second
+
+// Feature flags checked by the server and gateway.
+const (
+	// FeatureA2AStreaming gates tasks/sendSubscribe and tasks/resubscribe
+	FeatureA2AStreaming = "a2a_streaming"
+)
+
+// FeatureEnabled resolves flag name for userID, returning defaultValue when the flag
+// is not configured. On other errors defaultValue is returned along with the error.
+func FeatureEnabled(cfg IConfig, name string, userID string, defaultValue bool) (bool, error) {
+	enabled, err := cfg.GetFeatureFlag(name, userID)
+	if errors.Is(err, ErrNotFound) {
+		return defaultValue, nil
+	}
+	if err != nil {
+		return defaultValue, err
+	}
+	return enabled, nil
 }
 
 type IConfig interface {
@@ -43,6 +120,12 @@ type IConfig interface {
 	LogLevel() (string, error)
 	DiscoveringHandlerPath() (string, error)
 	FrontendAddressForProxy() (string, error)
+	// AllowedOrigins lists the origins allowed to make cross-site browser requests ("*" allows any)
+	AllowedOrigins() ([]string, error)
+	// SSEKeepAliveInterval is how often idle SSE streams get a keepalive comment (0 disables them)
+	SSEKeepAliveInterval() (time.Duration, error)
+	// AdminToken is the bearer token of the gateway admin API (empty disables the API)
+	AdminToken() (string, error)
 
 	// User & Auth Settings
 	GetUserIDByKeyHash(keyHash string) (userID string, err error)
@@ -53,6 +136,9 @@ type IConfig interface {
 	GetBackendBySlug(slug string) (backendCfg *Backend, err error)
 	GetServerHeaders(serverSlug string) (headers map[string]string, err error)
 	GetSubscriptionHeaders(userID, serverSlug string) (headers map[string]string, err error)
+	IsDestructiveBlocked(serverSlug string) (blocked bool, err error)
+	// MaxSubscriptionsPerUser caps the number of servers each user is subscribed to (0 means no limit)
+	MaxSubscriptionsPerUser() (int, error)
 
 	// SSL Settings
 	SSLEnabled() (bool, error)
@@ -65,6 +151,14 @@ type IConfig interface {
 
 	// A2A Settings
 	GetA2AAgentCard(agentURL string) (*a2aSchema.AgentCard, error)
+	GetA2ABackendForUser(userID string, agentSlug string) (url string, err error)
+
+	// Feature Flags
+	// GetFeatureFlag reports whether flag name is enabled for userID, ErrNotFound if it is not configured
+	GetFeatureFlag(name string, userID string) (enabled bool, err error)
+
+	// Schema declares the settings above for validation and documentation
+	Schema() ConfigSchema
 
 	// Lifecycle & Status
 	Status(ctx context.Context) error
@@ -4,7 +4,9 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"slices"
 	"sync"
+	"time"
 
 	a2aSchema "github.com/gate4ai/gate4ai/shared/a2a/2025-draft/schema"
 )
@@ -14,20 +16,26 @@ var ErrNotFound = errors.New("not found")
 
 // InternalConfig implements all configuration interfaces with in-memory storage
 type InternalConfig struct {
-	mu                          sync.RWMutex
-	ServerAddress               string
-	ServerNameValue             string
-	ServerVersionValue          string
-	AuthorizationTypeValue      AuthorizationType
-	LogLevelValue               string
-	DiscoveringHandlerPathValue string
-	FrontendAddressValue        string
-	UserKeyHashes               map[string]string            // keyHash -> userID
-	userParams                  map[string]map[string]string // userID -> paramName -> paramValue
-	UserSubscribes              map[string][]string          // userID -> serverSlugs
-	Backends                    map[string]*Backend          // serverSlug -> Server
-	serverHeaders               map[string]map[string]string // NEW: serverSlug -> {headerKey: headerValue}
-	subscriptionHeaders         map[string]map[string]string // NEW: subscriptionKey (userID:serverSlug) -> {headerKey: headerValue}
+	mu                           sync.RWMutex
+	ServerAddress                string
+	ServerNameValue              string
+	ServerVersionValue           string
+	AuthorizationTypeValue       AuthorizationType
+	LogLevelValue                string
+	DiscoveringHandlerPathValue  string
+	FrontendAddressValue         string
+	AllowedOriginsValue          []string
+	SSEKeepAliveIntervalValue    time.Duration
+	AdminTokenValue              string
+	MaxSubscriptionsPerUserValue int
+	UserKeyHashes                map[string]string            // keyHash -> userID
+	userParams                   map[string]map[string]string // userID -> paramName -> paramValue
+	UserSubscribes               map[string][]string          // userID -> serverSlugs
+	Backends                     map[string]*Backend          // serverSlug -> Server
+	UserA2AAgents                map[string]string            // userID -> default A2A agentSlug
+	serverHeaders                map[string]map[string]string // NEW: serverSlug -> {headerKey: headerValue}
+	subscriptionHeaders          map[string]map[string]string // NEW: subscriptionKey (userID:serverSlug) -> {headerKey: headerValue}
+	FeatureFlags                 map[string]*FeatureFlag      // flagName -> FeatureFlag
 
 	// SSL Fields
 	SSLEnabledValue      bool
@@ -53,18 +61,21 @@ type InternalConfig struct {
 // NewInternalConfig creates a new in-memory configuration
 func NewInternalConfig() *InternalConfig {
 	return &InternalConfig{
-		ServerAddress:        ":8080",
-		ServerNameValue:      "Unknown",
-		ServerVersionValue:   "0.0.0",
-		LogLevelValue:        "info",
-		FrontendAddressValue: "http://localhost:3000",
+		ServerAddress:             ":8080",
+		ServerNameValue:           "Unknown",
+		ServerVersionValue:        "0.0.0",
+		LogLevelValue:             "info",
+		FrontendAddressValue:      "http://localhost:3000",
+		SSEKeepAliveIntervalValue: DefaultSSEKeepAliveInterval,
 
 		UserKeyHashes:       make(map[string]string),
 		userParams:          make(map[string]map[string]string),
 		UserSubscribes:      make(map[string][]string),
 		Backends:            make(map[string]*Backend),
+		UserA2AAgents:       make(map[string]string),
 		serverHeaders:       make(map[string]map[string]string), // NEW
 		subscriptionHeaders: make(map[string]map[string]string), // NEW
+		FeatureFlags:        make(map[string]*FeatureFlag),
 
 		// Default SSL settings
 		SSLEnabledValue:      false,
@@ -124,6 +135,29 @@ func (c *InternalConfig) SetFrontendAddressForProxy(address string) {
 	defer c.mu.Unlock()
 	c.FrontendAddressValue = address
 }
+func (c *InternalConfig) AllowedOrigins() ([]string, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	origins := make([]string, len(c.AllowedOriginsValue))
+	copy(origins, c.AllowedOriginsValue)
+	return origins, nil
+}
+func (c *InternalConfig) SSEKeepAliveInterval() (time.Duration, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.SSEKeepAliveIntervalValue, nil
+}
+func (c *InternalConfig) AdminToken() (string, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.AdminTokenValue, nil
+}
+func (c *InternalConfig) MaxSubscriptionsPerUser() (int, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.MaxSubscriptionsPerUserValue, nil
+}
+
 func (c *InternalConfig) SSLEnabled() (bool, error) {
 	c.mu.RLock()
 	defer c.mu.RUnlock()
@@ -163,6 +197,7 @@ func (c *InternalConfig) SSLAcmeCacheDir() (string, error) {
 }
 func (c *InternalConfig) Status(ctx context.Context) error { return nil }
 func (c *InternalConfig) Close() error                     { return nil }
+func (c *InternalConfig) Schema() ConfigSchema             { return DefaultConfigSchema() }
 
 func (c *InternalConfig) GetUserIDByKeyHash(keyHash string) (string, error) {
 	c.mu.RLock()
@@ -229,6 +264,29 @@ func (c *InternalConfig) SetBackend(serverSlug string, url string, bearer string
 	c.Backends[serverSlug] = &Backend{URL: url, Bearer: bearer}
 }
 
+// IsDestructiveBlocked reports whether destructive tool calls are blocked for the backend.
+func (c *InternalConfig) IsDestructiveBlocked(serverSlug string) (bool, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	backend, exists := c.Backends[serverSlug]
+	if !exists {
+		return false, nil
+	}
+	return backend.BlockDestructiveTools, nil
+}
+
+// SetDestructiveBlocked blocks or unblocks destructive tool calls for an existing backend.
+func (c *InternalConfig) SetDestructiveBlocked(serverSlug string, blocked bool) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	backend, exists := c.Backends[serverSlug]
+	if !exists {
+		return ErrNotFound
+	}
+	backend.BlockDestructiveTools = blocked
+	return nil
+}
+
 // NEW: GetServerHeaders retrieves the server-specific headers.
 func (c *InternalConfig) GetServerHeaders(serverSlug string) (map[string]string, error) {
 	c.mu.RLock()
@@ -297,6 +355,79 @@ func (c *InternalConfig) GetA2AAgentCard(agentURL string) (*a2aSchema.AgentCard,
 	return info, nil
 }
 
+// GetA2ABackendForUser resolves the URL of the A2A backend the user's tasks are routed to.
+func (c *InternalConfig) GetA2ABackendForUser(userID string, agentSlug string) (string, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	slug, err := resolveA2AAgentSlug(agentSlug, c.UserA2AAgents[userID], c.UserSubscribes[userID])
+	if err != nil {
+		return "", err
+	}
+	backend, exists := c.Backends[slug]
+	if !exists {
+		return "", ErrNotFound
+	}
+	return backend.URL, nil
+}
+func (c *InternalConfig) SetUserA2AAgent(userID string, agentSlug string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.UserA2AAgents[userID] = agentSlug
+}
+
+// GetFeatureFlag reports whether flag name is enabled for userID.
+func (c *InternalConfig) GetFeatureFlag(name string, userID string) (bool, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	flag, exists := c.FeatureFlags[name]
+	if !exists {
+		return false, ErrNotFound
+	}
+	return flag.EnabledFor(userID), nil
+}
+
+// SetFeatureFlag enables or disables flag name globally, keeping its user overrides.
+func (c *InternalConfig) SetFeatureFlag(name string, enabled bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	flag, exists := c.FeatureFlags[name]
+	if !exists {
+		flag = &FeatureFlag{UserOverrides: make(map[string]bool)}
+		c.FeatureFlags[name] = flag
+	}
+	flag.Enabled = enabled
+}
+
+// SetUserFeatureFlag overrides flag name for userID. The flag must already exist.
+func (c *InternalConfig) SetUserFeatureFlag(name string, userID string, enabled bool) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	flag, exists := c.FeatureFlags[name]
+	if !exists {
+		return ErrNotFound
+	}
+	if flag.UserOverrides == nil {
+		flag.UserOverrides = make(map[string]bool)
+	}
+	flag.UserOverrides[userID] = enabled
+	return nil
+}
+
+// resolveA2AAgentSlug picks the agent to route to: an explicitly requested agent
+// the user is subscribed to, or the user's default agent when none is requested.
+func resolveA2AAgentSlug(requested, defaultSlug string, subscribes []string) (string, error) {
+	if requested == "" {
+		if defaultSlug == "" {
+			return "", ErrNotFound
+		}
+		return defaultSlug, nil
+	}
+	if requested == defaultSlug || slices.Contains(subscribes, requested) {
+		return requested, nil
+	}
+	return "", ErrNotFound
+}
+
 func derefString(s *string) string {
 	if s == nil {
 		return ""
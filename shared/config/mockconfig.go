@@ -0,0 +1,404 @@
+//go:build !production
+
+package config
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	a2aSchema "github.com/gate4ai/gate4ai/shared/a2a/2025-draft/schema"
+)
+
+var _ IConfig = (*MockConfig)(nil)
+
+// MockConfig is an IConfig for unit tests. Unlike InternalConfig, it fails with ErrNotFound
+// for every user, backend or string setting that was not configured, and SetError makes any
+// method fail, so tests can exercise the error paths of their callers.
+type MockConfig struct {
+	mu                           sync.RWMutex
+	ServerAddress                string
+	ServerNameValue              string
+	ServerVersionValue           string
+	AuthorizationTypeValue       AuthorizationType
+	LogLevelValue                string
+	DiscoveringHandlerPathValue  string
+	FrontendAddressValue         string
+	AllowedOriginsValue          []string
+	SSEKeepAliveIntervalValue    time.Duration
+	AdminTokenValue              string
+	MaxSubscriptionsPerUserValue int
+
+	SSLEnabledValue      bool
+	SSLModeValue         string
+	SSLCertFileValue     string
+	SSLKeyFileValue      string
+	SSLAcmeDomainsValue  []string
+	SSLAcmeEmailValue    string
+	SSLAcmeCacheDirValue string
+
+	// A2AAgentCardValue is returned by GetA2AAgentCard with the requested URL (nil means not configured)
+	A2AAgentCardValue *a2aSchema.AgentCard
+
+	userKeyHashes       map[string]string            // keyHash -> userID
+	userParams          map[string]map[string]string // userID -> paramName -> paramValue
+	userSubscribes      map[string][]string          // userID -> serverSlugs
+	backends            map[string]*Backend          // serverSlug -> Backend
+	serverHeaders       map[string]map[string]string // serverSlug -> headers
+	subscriptionHeaders map[string]map[string]string // userID:serverSlug -> headers
+	userA2AAgents       map[string]string            // userID -> default A2A agentSlug
+	featureFlags        map[string]*FeatureFlag      // flagName -> FeatureFlag
+	errors              map[string]error             // method name -> error it returns
+}
+
+// NewMockConfig creates a MockConfig with the core server settings set and nothing else configured.
+func NewMockConfig() *MockConfig {
+	return &MockConfig{
+		ServerAddress:             ":8080",
+		ServerNameValue:           "mock",
+		ServerVersionValue:        "0.0.0",
+		LogLevelValue:             "info",
+		SSEKeepAliveIntervalValue: DefaultSSEKeepAliveInterval,
+
+		userKeyHashes:       make(map[string]string),
+		userParams:          make(map[string]map[string]string),
+		userSubscribes:      make(map[string][]string),
+		backends:            make(map[string]*Backend),
+		serverHeaders:       make(map[string]map[string]string),
+		subscriptionHeaders: make(map[string]map[string]string),
+		userA2AAgents:       make(map[string]string),
+		featureFlags:        make(map[string]*FeatureFlag),
+		errors:              make(map[string]error),
+	}
+}
+
+// --- Setup ---
+
+// SetError makes the IConfig method named method return err; a nil err clears it.
+func (c *MockConfig) SetError(method string, err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err == nil {
+		delete(c.errors, method)
+		return
+	}
+	c.errors[method] = err
+}
+
+// AddUserKey makes the plaintext API key identify userID.
+func (c *MockConfig) AddUserKey(userID, key string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.userKeyHashes[HashAPIKey(key)] = userID
+}
+
+// SetUserParam sets a parameter of userID.
+func (c *MockConfig) SetUserParam(userID, paramName, paramValue string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, exists := c.userParams[userID]; !exists {
+		c.userParams[userID] = make(map[string]string)
+	}
+	c.userParams[userID][paramName] = paramValue
+}
+
+// AddUserSubscribe subscribes userID to the backend slug.
+func (c *MockConfig) AddUserSubscribe(userID, slug string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.userSubscribes[userID] = append(c.userSubscribes[userID], slug)
+}
+
+// SetBackendURL configures the backend slug with url, keeping its other settings.
+func (c *MockConfig) SetBackendURL(slug, url string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	backend, exists := c.backends[slug]
+	if !exists {
+		backend = &Backend{}
+		c.backends[slug] = backend
+	}
+	backend.URL = url
+}
+
+// SetBackend configures the backend slug with a copy of backend.
+func (c *MockConfig) SetBackend(slug string, backend Backend) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.backends[slug] = &backend
+}
+
+// SetServerHeaders sets the headers sent to the backend slug.
+func (c *MockConfig) SetServerHeaders(slug string, headers map[string]string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	headersCopy := make(map[string]string, len(headers))
+	copyMap(headers, headersCopy)
+	c.serverHeaders[slug] = headersCopy
+}
+
+// SetSubscriptionHeaders sets the headers sent to the backend slug for userID.
+func (c *MockConfig) SetSubscriptionHeaders(userID, slug string, headers map[string]string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	headersCopy := make(map[string]string, len(headers))
+	copyMap(headers, headersCopy)
+	c.subscriptionHeaders[fmt.Sprintf("%s:%s", userID, slug)] = headersCopy
+}
+
+// SetUserA2AAgent sets the A2A agent userID's tasks are routed to by default.
+func (c *MockConfig) SetUserA2AAgent(userID, agentSlug string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.userA2AAgents[userID] = agentSlug
+}
+
+// SetFeatureFlag configures flag name.
+func (c *MockConfig) SetFeatureFlag(name string, flag FeatureFlag) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.featureFlags[name] = &flag
+}
+
+// --- IConfig Implementation ---
+
+// stringSetting returns value, ErrNotFound if it is empty, or the error set for method.
+func (c *MockConfig) stringSetting(method string, value *string) (string, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	if err := c.errors[method]; err != nil {
+		return "", err
+	}
+	if *value == "" {
+		return "", ErrNotFound
+	}
+	return *value, nil
+}
+
+func (c *MockConfig) ListenAddr() (string, error) {
+	return c.stringSetting("ListenAddr", &c.ServerAddress)
+}
+func (c *MockConfig) ServerName() (string, error) {
+	return c.stringSetting("ServerName", &c.ServerNameValue)
+}
+func (c *MockConfig) ServerVersion() (string, error) {
+	return c.stringSetting("ServerVersion", &c.ServerVersionValue)
+}
+func (c *MockConfig) LogLevel() (string, error) {
+	return c.stringSetting("LogLevel", &c.LogLevelValue)
+}
+func (c *MockConfig) DiscoveringHandlerPath() (string, error) {
+	return c.stringSetting("DiscoveringHandlerPath", &c.DiscoveringHandlerPathValue)
+}
+func (c *MockConfig) FrontendAddressForProxy() (string, error) {
+	return c.stringSetting("FrontendAddressForProxy", &c.FrontendAddressValue)
+}
+func (c *MockConfig) SSLMode() (string, error) {
+	return c.stringSetting("SSLMode", &c.SSLModeValue)
+}
+func (c *MockConfig) SSLCertFile() (string, error) {
+	return c.stringSetting("SSLCertFile", &c.SSLCertFileValue)
+}
+func (c *MockConfig) SSLKeyFile() (string, error) {
+	return c.stringSetting("SSLKeyFile", &c.SSLKeyFileValue)
+}
+func (c *MockConfig) SSLAcmeEmail() (string, error) {
+	return c.stringSetting("SSLAcmeEmail", &c.SSLAcmeEmailValue)
+}
+func (c *MockConfig) SSLAcmeCacheDir() (string, error) {
+	return c.stringSetting("SSLAcmeCacheDir", &c.SSLAcmeCacheDirValue)
+}
+
+func (c *MockConfig) AuthorizationType() (AuthorizationType, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.AuthorizationTypeValue, c.errors["AuthorizationType"]
+}
+func (c *MockConfig) AllowedOrigins() ([]string, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	if err := c.errors["AllowedOrigins"]; err != nil {
+		return nil, err
+	}
+	return append([]string{}, c.AllowedOriginsValue...), nil
+}
+func (c *MockConfig) SSEKeepAliveInterval() (time.Duration, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.SSEKeepAliveIntervalValue, c.errors["SSEKeepAliveInterval"]
+}
+func (c *MockConfig) AdminToken() (string, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.AdminTokenValue, c.errors["AdminToken"]
+}
+func (c *MockConfig) MaxSubscriptionsPerUser() (int, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.MaxSubscriptionsPerUserValue, c.errors["MaxSubscriptionsPerUser"]
+}
+func (c *MockConfig) SSLEnabled() (bool, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.SSLEnabledValue, c.errors["SSLEnabled"]
+}
+func (c *MockConfig) SSLAcmeDomains() ([]string, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	if err := c.errors["SSLAcmeDomains"]; err != nil {
+		return nil, err
+	}
+	return append([]string{}, c.SSLAcmeDomainsValue...), nil
+}
+
+func (c *MockConfig) GetUserIDByKeyHash(keyHash string) (string, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	if err := c.errors["GetUserIDByKeyHash"]; err != nil {
+		return "", err
+	}
+	if keyHash == "" {
+		return "", nil // Anonymous, as in InternalConfig
+	}
+	userID, exists := c.userKeyHashes[keyHash]
+	if !exists {
+		return "", ErrNotFound
+	}
+	return userID, nil
+}
+func (c *MockConfig) GetUserParams(userID string) (map[string]string, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	if err := c.errors["GetUserParams"]; err != nil {
+		return nil, err
+	}
+	params, exists := c.userParams[userID]
+	if !exists {
+		return nil, ErrNotFound
+	}
+	paramsCopy := make(map[string]string, len(params))
+	copyMap(params, paramsCopy)
+	return paramsCopy, nil
+}
+func (c *MockConfig) GetUserSubscribes(userID string) ([]string, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	if err := c.errors["GetUserSubscribes"]; err != nil {
+		return nil, err
+	}
+	slugs, exists := c.userSubscribes[userID]
+	if !exists {
+		return nil, ErrNotFound
+	}
+	return append([]string{}, slugs...), nil
+}
+func (c *MockConfig) GetBackendBySlug(slug string) (*Backend, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	if err := c.errors["GetBackendBySlug"]; err != nil {
+		return nil, err
+	}
+	backend, exists := c.backends[slug]
+	if !exists {
+		return nil, ErrNotFound
+	}
+	backendCopy := *backend
+	return &backendCopy, nil
+}
+func (c *MockConfig) GetServerHeaders(slug string) (map[string]string, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	if err := c.errors["GetServerHeaders"]; err != nil {
+		return nil, err
+	}
+	headers, exists := c.serverHeaders[slug]
+	if !exists {
+		return nil, ErrNotFound
+	}
+	headersCopy := make(map[string]string, len(headers))
+	copyMap(headers, headersCopy)
+	return headersCopy, nil
+}
+func (c *MockConfig) GetSubscriptionHeaders(userID, slug string) (map[string]string, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	if err := c.errors["GetSubscriptionHeaders"]; err != nil {
+		return nil, err
+	}
+	headers, exists := c.subscriptionHeaders[fmt.Sprintf("%s:%s", userID, slug)]
+	if !exists {
+		return nil, ErrNotFound
+	}
+	headersCopy := make(map[string]string, len(headers))
+	copyMap(headers, headersCopy)
+	return headersCopy, nil
+}
+func (c *MockConfig) IsDestructiveBlocked(slug string) (bool, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	if err := c.errors["IsDestructiveBlocked"]; err != nil {
+		return false, err
+	}
+	backend, exists := c.backends[slug]
+	if !exists {
+		return false, ErrNotFound
+	}
+	return backend.BlockDestructiveTools, nil
+}
+
+func (c *MockConfig) GetA2AAgentCard(agentURL string) (*a2aSchema.AgentCard, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	if err := c.errors["GetA2AAgentCard"]; err != nil {
+		return nil, err
+	}
+	if c.A2AAgentCardValue == nil {
+		return nil, ErrNotFound
+	}
+	card := *c.A2AAgentCardValue
+	card.URL = agentURL
+	return &card, nil
+}
+func (c *MockConfig) GetA2ABackendForUser(userID string, agentSlug string) (string, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	if err := c.errors["GetA2ABackendForUser"]; err != nil {
+		return "", err
+	}
+	slug, err := resolveA2AAgentSlug(agentSlug, c.userA2AAgents[userID], c.userSubscribes[userID])
+	if err != nil {
+		return "", err
+	}
+	backend, exists := c.backends[slug]
+	if !exists {
+		return "", ErrNotFound
+	}
+	return backend.URL, nil
+}
+
+func (c *MockConfig) GetFeatureFlag(name string, userID string) (bool, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	if err := c.errors["GetFeatureFlag"]; err != nil {
+		return false, err
+	}
+	flag, exists := c.featureFlags[name]
+	if !exists {
+		return false, ErrNotFound
+	}
+	return flag.EnabledFor(userID), nil
+}
+
+func (c *MockConfig) Schema() ConfigSchema { return DefaultConfigSchema() }
+
+func (c *MockConfig) Status(ctx context.Context) error {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.errors["Status"]
+}
+func (c *MockConfig) Close() error {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.errors["Close"]
+}
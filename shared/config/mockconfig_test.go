@@ -0,0 +1,107 @@
+//go:build !production
+
+package config
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	a2aSchema "github.com/gate4ai/gate4ai/shared/a2a/2025-draft/schema"
+)
+
+func TestMockConfigReturnsConfiguredValues(t *testing.T) {
+	cfg := NewMockConfig()
+	cfg.AddUserKey("alice", "alice-key")
+	cfg.SetUserParam("alice", "plan", "pro")
+	cfg.AddUserSubscribe("alice", "search")
+	cfg.AddUserSubscribe("alice", "agent")
+	cfg.SetBackendURL("search", "http://search.local/sse")
+	cfg.SetBackend("agent", Backend{URL: "http://agent.local/a2a", BlockDestructiveTools: true})
+	cfg.SetServerHeaders("search", map[string]string{"X-Region": "eu"})
+	cfg.SetSubscriptionHeaders("alice", "search", map[string]string{"X-Api-Key": "alice-search"})
+	cfg.SetUserA2AAgent("alice", "agent")
+	cfg.SetFeatureFlag(FeatureA2AStreaming, FeatureFlag{UserOverrides: map[string]bool{"alice": true}})
+	cfg.A2AAgentCardValue = &a2aSchema.AgentCard{Name: "mock-agent"}
+
+	userID, err := cfg.GetUserIDByKeyHash(HashAPIKey("alice-key"))
+	if err != nil || userID != "alice" {
+		t.Errorf("GetUserIDByKeyHash() = %q, %v", userID, err)
+	}
+	if params, err := cfg.GetUserParams("alice"); err != nil || params["plan"] != "pro" {
+		t.Errorf("GetUserParams() = %v, %v", params, err)
+	}
+	if slugs, err := cfg.GetUserSubscribes("alice"); err != nil || !reflect.DeepEqual(slugs, []string{"search", "agent"}) {
+		t.Errorf("GetUserSubscribes() = %v, %v", slugs, err)
+	}
+	if backend, err := cfg.GetBackendBySlug("search"); err != nil || backend.URL != "http://search.local/sse" {
+		t.Errorf("GetBackendBySlug() = %+v, %v", backend, err)
+	}
+	if blocked, err := cfg.IsDestructiveBlocked("agent"); err != nil || !blocked {
+		t.Errorf("IsDestructiveBlocked() = %v, %v", blocked, err)
+	}
+	if headers, err := cfg.GetServerHeaders("search"); err != nil || headers["X-Region"] != "eu" {
+		t.Errorf("GetServerHeaders() = %v, %v", headers, err)
+	}
+	if headers, err := cfg.GetSubscriptionHeaders("alice", "search"); err != nil || headers["X-Api-Key"] != "alice-search" {
+		t.Errorf("GetSubscriptionHeaders() = %v, %v", headers, err)
+	}
+	if url, err := cfg.GetA2ABackendForUser("alice", ""); err != nil || url != "http://agent.local/a2a" {
+		t.Errorf("GetA2ABackendForUser() = %q, %v", url, err)
+	}
+	if enabled, err := cfg.GetFeatureFlag(FeatureA2AStreaming, "alice"); err != nil || !enabled {
+		t.Errorf("GetFeatureFlag() = %v, %v", enabled, err)
+	}
+	if card, err := cfg.GetA2AAgentCard("http://localhost/a2a"); err != nil || card.Name != "mock-agent" || card.URL != "http://localhost/a2a" {
+		t.Errorf("GetA2AAgentCard() = %+v, %v", card, err)
+	}
+	if name, err := cfg.ServerName(); err != nil || name != "mock" {
+		t.Errorf("ServerName() = %q, %v", name, err)
+	}
+}
+
+func TestMockConfigUnconfiguredKeys(t *testing.T) {
+	cfg := NewMockConfig()
+	cfg.AddUserSubscribe("alice", "search")
+
+	lookups := map[string]func() error{
+		"GetUserIDByKeyHash":     func() error { _, err := cfg.GetUserIDByKeyHash(HashAPIKey("unknown")); return err },
+		"GetUserParams":          func() error { _, err := cfg.GetUserParams("bob"); return err },
+		"GetUserSubscribes":      func() error { _, err := cfg.GetUserSubscribes("bob"); return err },
+		"GetBackendBySlug":       func() error { _, err := cfg.GetBackendBySlug("search"); return err },
+		"IsDestructiveBlocked":   func() error { _, err := cfg.IsDestructiveBlocked("search"); return err },
+		"GetServerHeaders":       func() error { _, err := cfg.GetServerHeaders("search"); return err },
+		"GetSubscriptionHeaders": func() error { _, err := cfg.GetSubscriptionHeaders("alice", "search"); return err },
+		"GetA2ABackendForUser":   func() error { _, err := cfg.GetA2ABackendForUser("alice", "search"); return err },
+		"GetFeatureFlag":         func() error { _, err := cfg.GetFeatureFlag(FeatureA2AStreaming, "alice"); return err },
+		"GetA2AAgentCard":        func() error { _, err := cfg.GetA2AAgentCard("http://localhost/a2a"); return err },
+		"SSLCertFile":            func() error { _, err := cfg.SSLCertFile(); return err },
+		"DiscoveringHandlerPath": func() error { _, err := cfg.DiscoveringHandlerPath(); return err },
+	}
+	for method, lookup := range lookups {
+		if err := lookup(); !errors.Is(err, ErrNotFound) {
+			t.Errorf("%s() error = %v, want ErrNotFound", method, err)
+		}
+	}
+}
+
+func TestMockConfigSetError(t *testing.T) {
+	cfg := NewMockConfig()
+	cfg.SetBackendURL("search", "http://search.local/sse")
+	unavailable := errors.New("config unavailable")
+	cfg.SetError("GetBackendBySlug", unavailable)
+	cfg.SetError("Status", unavailable)
+
+	if _, err := cfg.GetBackendBySlug("search"); !errors.Is(err, unavailable) {
+		t.Errorf("GetBackendBySlug() error = %v, want %v", err, unavailable)
+	}
+	if err := cfg.Status(context.Background()); !errors.Is(err, unavailable) {
+		t.Errorf("Status() error = %v, want %v", err, unavailable)
+	}
+
+	cfg.SetError("GetBackendBySlug", nil)
+	if _, err := cfg.GetBackendBySlug("search"); err != nil {
+		t.Errorf("GetBackendBySlug() after clearing the error = %v", err)
+	}
+}
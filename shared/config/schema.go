@@ -0,0 +1,202 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"reflect"
+	"slices"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// ConfigFieldType is the type of a config field value.
+type ConfigFieldType string
+
+const (
+	FieldTypeString      ConfigFieldType = "string"
+	FieldTypeBoolean     ConfigFieldType = "boolean"
+	FieldTypeInteger     ConfigFieldType = "integer"
+	FieldTypeStringArray ConfigFieldType = "array" // Of strings
+)
+
+// ConfigField describes one setting of an IConfig.
+type ConfigField struct {
+	// Name is the setting's key, e.g. "server.log_level" for the YAML server section
+	Name        string
+	Type        ConfigFieldType
+	Description string
+	// Required fields must have a non-empty value
+	Required bool
+	// Default is the value used when the setting is not configured; documentation only
+	Default interface{}
+	// Enum lists the allowed values of a string field (empty allows any)
+	Enum []string
+	// Validator checks a non-empty value, e.g. its range or format
+	Validator func(value interface{}) error
+	// Get reads the value from a config
+	Get func(cfg IConfig) (interface{}, error)
+}
+
+// ConfigSchema declares the settings of an IConfig for validation and documentation.
+type ConfigSchema []ConfigField
+
+// ConfigViolation is a setting that does not match its ConfigField.
+type ConfigViolation struct {
+	Field   string
+	Message string
+}
+
+func (v ConfigViolation) Error() string {
+	return fmt.Sprintf("%s: %s", v.Field, v.Message)
+}
+
+// Validate reads every field of the schema from cfg and returns all violations, in schema order.
+func (s ConfigSchema) Validate(cfg IConfig) []ConfigViolation {
+	var violations []ConfigViolation
+	for _, field := range s {
+		value, err := field.Get(cfg)
+		if err != nil && !errors.Is(err, ErrNotFound) {
+			violations = append(violations, ConfigViolation{Field: field.Name, Message: fmt.Sprintf("cannot be read: %v", err)})
+			continue
+		}
+		if err != nil || isEmptyValue(value) {
+			if field.Required {
+				violations = append(violations, ConfigViolation{Field: field.Name, Message: "is required"})
+			}
+			continue
+		}
+		if text, ok := value.(string); ok && len(field.Enum) > 0 && !slices.Contains(field.Enum, text) {
+			violations = append(violations, ConfigViolation{Field: field.Name, Message: fmt.Sprintf("must be one of %v, got %q", field.Enum, text)})
+			continue
+		}
+		if field.Validator != nil {
+			if err := field.Validator(value); err != nil {
+				violations = append(violations, ConfigViolation{Field: field.Name, Message: err.Error()})
+			}
+		}
+	}
+	return violations
+}
+
+// isEmptyValue reports whether value is nil, an empty string or an empty slice.
+func isEmptyValue(value interface{}) bool {
+	if value == nil {
+		return true
+	}
+	v := reflect.ValueOf(value)
+	switch v.Kind() {
+	case reflect.String, reflect.Slice, reflect.Map:
+		return v.Len() == 0
+	case reflect.Pointer:
+		return v.IsNil()
+	}
+	return false
+}
+
+// ToJSONSchema describes the schema as a JSON Schema object whose properties are the field names.
+func (s ConfigSchema) ToJSONSchema() json.RawMessage {
+	properties := make(map[string]interface{}, len(s))
+	required := []string{}
+	for _, field := range s {
+		property := map[string]interface{}{"type": string(field.Type)}
+		if field.Type == FieldTypeStringArray {
+			property["items"] = map[string]string{"type": string(FieldTypeString)}
+		}
+		if field.Description != "" {
+			property["description"] = field.Description
+		}
+		if field.Default != nil {
+			property["default"] = field.Default
+		}
+		if len(field.Enum) > 0 {
+			property["enum"] = field.Enum
+		}
+		properties[field.Name] = property
+		if field.Required {
+			required = append(required, field.Name)
+		}
+	}
+	data, err := json.Marshal(map[string]interface{}{
+		"$schema":    "https://json-schema.org/draft/2020-12/schema",
+		"type":       "object",
+		"properties": properties,
+		"required":   required,
+	})
+	if err != nil {
+		// Only defaults that cannot be marshaled get here
+		panic(fmt.Sprintf("config schema is not JSON serializable: %v", err))
+	}
+	return data
+}
+
+// IsHostPort validates a listen address such as ":8080" or "localhost:8080".
+func IsHostPort(value interface{}) error {
+	text, ok := value.(string)
+	if !ok {
+		return fmt.Errorf("must be a string, got %T", value)
+	}
+	if _, _, err := net.SplitHostPort(text); err != nil {
+		return fmt.Errorf("must be host:port: %v", err)
+	}
+	return nil
+}
+
+// IsNonNegativeInt validates an integer that is zero or more.
+func IsNonNegativeInt(value interface{}) error {
+	number, ok := value.(int)
+	if !ok {
+		return fmt.Errorf("must be an integer, got %T", value)
+	}
+	if number < 0 {
+		return fmt.Errorf("must not be negative, got %d", number)
+	}
+	return nil
+}
+
+// IsLogLevel validates a zap log level name.
+func IsLogLevel(value interface{}) error {
+	text, ok := value.(string)
+	if !ok {
+		return fmt.Errorf("must be a string, got %T", value)
+	}
+	var level zapcore.Level
+	return level.UnmarshalText([]byte(text))
+}
+
+// DefaultConfigSchema declares the core server settings every IConfig provides.
+func DefaultConfigSchema() ConfigSchema {
+	return ConfigSchema{
+		{Name: "server.address", Type: FieldTypeString, Description: "Address the server listens on", Default: ":8080", Validator: IsHostPort,
+			Get: func(cfg IConfig) (interface{}, error) { return cfg.ListenAddr() }},
+		{Name: "server.name", Type: FieldTypeString, Description: "Server name reported to clients",
+			Get: func(cfg IConfig) (interface{}, error) { return cfg.ServerName() }},
+		{Name: "server.version", Type: FieldTypeString, Description: "Server version reported to clients",
+			Get: func(cfg IConfig) (interface{}, error) { return cfg.ServerVersion() }},
+		{Name: "server.log_level", Type: FieldTypeString, Description: "Minimum level of logged messages (debug, info, warn, error, ...)", Default: "info", Validator: IsLogLevel,
+			Get: func(cfg IConfig) (interface{}, error) { return cfg.LogLevel() }},
+		{Name: "server.authorization", Type: FieldTypeString, Description: "Which requests need an API key", Default: AuthorizedUsersOnly.String(),
+			Enum: []string{AuthorizedUsersOnly.String(), NotAuthorizedToMarkedMethods.String(), NotAuthorizedEverywhere.String()},
+			Get: func(cfg IConfig) (interface{}, error) {
+				authorizationType, err := cfg.AuthorizationType()
+				return authorizationType.String(), err
+			}},
+		{Name: "server.allowed_origins", Type: FieldTypeStringArray, Description: "Origins allowed to make cross-site browser requests",
+			Get: func(cfg IConfig) (interface{}, error) { return cfg.AllowedOrigins() }},
+		{Name: "server.sse_keepalive_interval", Type: FieldTypeString, Description: "How often idle SSE streams get a keepalive comment (0s disables them)", Default: DefaultSSEKeepAliveInterval.String(),
+			Get: func(cfg IConfig) (interface{}, error) {
+				interval, err := cfg.SSEKeepAliveInterval()
+				return interval.String(), err
+			}},
+		{Name: "server.max_subscriptions_per_user", Type: FieldTypeInteger, Description: "Maximum number of servers each user can subscribe to (0 means no limit)", Default: 0, Validator: IsNonNegativeInt,
+			Get: func(cfg IConfig) (interface{}, error) { return cfg.MaxSubscriptionsPerUser() }},
+		{Name: "server.ssl.enabled", Type: FieldTypeBoolean, Description: "Serve HTTPS", Default: false,
+			Get: func(cfg IConfig) (interface{}, error) { return cfg.SSLEnabled() }},
+		{Name: "server.ssl.mode", Type: FieldTypeString, Description: "Certificate source when SSL is enabled", Default: "manual",
+			Enum: []string{"manual", "acme"},
+			Get:  func(cfg IConfig) (interface{}, error) { return cfg.SSLMode() }},
+		{Name: "server.ssl.acme_domains", Type: FieldTypeStringArray, Description: "Domains of the ACME certificate",
+			Get: func(cfg IConfig) (interface{}, error) { return cfg.SSLAcmeDomains() }},
+	}
+}
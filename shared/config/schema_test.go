@@ -0,0 +1,81 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestConfigSchemaValidate(t *testing.T) {
+	schema := ConfigSchema{
+		{Name: "server.name", Type: FieldTypeString, Required: true,
+			Get: func(cfg IConfig) (interface{}, error) { return cfg.ServerName() }},
+		{Name: "server.version", Type: FieldTypeString, Required: true,
+			Get: func(cfg IConfig) (interface{}, error) { return cfg.ServerVersion() }},
+		{Name: "server.allowed_origins", Type: FieldTypeStringArray, Required: true,
+			Get: func(cfg IConfig) (interface{}, error) { return cfg.AllowedOrigins() }},
+		{Name: "server.address", Type: FieldTypeString, Validator: IsHostPort,
+			Get: func(cfg IConfig) (interface{}, error) { return cfg.ListenAddr() }},
+		{Name: "server.ssl.mode", Type: FieldTypeString, Enum: []string{"manual", "acme"},
+			Get: func(cfg IConfig) (interface{}, error) { return cfg.SSLMode() }},
+		{Name: "server.log_level", Type: FieldTypeString, Default: "info",
+			Get: func(cfg IConfig) (interface{}, error) { return "", errors.New("settings table missing") }},
+		{Name: "feature.optional", Type: FieldTypeBoolean,
+			Get: func(cfg IConfig) (interface{}, error) { return nil, ErrNotFound }},
+	}
+	cfg := NewInternalConfig()
+	cfg.ServerNameValue = "gateway"
+	cfg.ServerVersionValue = ""
+	cfg.ServerAddress = "8080"
+	cfg.SSLModeValue = "self-signed"
+
+	violations := schema.Validate(cfg)
+	var fields []string
+	for _, violation := range violations {
+		fields = append(fields, violation.Field)
+	}
+	want := []string{"server.version", "server.allowed_origins", "server.address", "server.ssl.mode", "server.log_level"}
+	if !reflect.DeepEqual(fields, want) {
+		t.Fatalf("Violations %v, want fields %v", violations, want)
+	}
+	if violations[0].Error() != "server.version: is required" {
+		t.Errorf("Unexpected violation message %q", violations[0].Error())
+	}
+
+	cfg.ServerVersionValue = "1.0"
+	cfg.AllowedOriginsValue = []string{"*"}
+	cfg.ServerAddress = ":8080"
+	cfg.SSLModeValue = "acme"
+	if violations := schema[:5].Validate(cfg); len(violations) != 0 {
+		t.Errorf("Valid config reported violations: %v", violations)
+	}
+	if violations := DefaultConfigSchema().Validate(cfg); len(violations) != 0 {
+		t.Errorf("Valid config violates the default schema: %v", violations)
+	}
+}
+
+func TestConfigSchemaToJSONSchema(t *testing.T) {
+	schema := ConfigSchema{
+		{Name: "server.name", Type: FieldTypeString, Required: true, Description: "Server name"},
+		{Name: "server.ssl.mode", Type: FieldTypeString, Default: "manual", Enum: []string{"manual", "acme"}},
+		{Name: "server.allowed_origins", Type: FieldTypeStringArray},
+	}
+	var jsonSchema struct {
+		Type       string                            `json:"type"`
+		Required   []string                          `json:"required"`
+		Properties map[string]map[string]interface{} `json:"properties"`
+	}
+	if err := json.Unmarshal(schema.ToJSONSchema(), &jsonSchema); err != nil {
+		t.Fatalf("Invalid JSON schema: %v", err)
+	}
+	if jsonSchema.Type != "object" || !reflect.DeepEqual(jsonSchema.Required, []string{"server.name"}) {
+		t.Errorf("Unexpected JSON schema %+v", jsonSchema)
+	}
+	if mode := jsonSchema.Properties["server.ssl.mode"]; mode["default"] != "manual" || len(mode["enum"].([]interface{})) != 2 {
+		t.Errorf("Unexpected server.ssl.mode property %v", mode)
+	}
+	if origins := jsonSchema.Properties["server.allowed_origins"]; origins["type"] != "array" || origins["items"] == nil {
+		t.Errorf("Unexpected server.allowed_origins property %v", origins)
+	}
+}
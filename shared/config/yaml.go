@@ -2,10 +2,12 @@ package config
 
 import (
 	"context" // Import errors package
+	"crypto/sha256"
 	"fmt"
 	"os"
 	"strings"
 	"sync"
+	"time"
 
 	a2aSchema "github.com/gate4ai/gate4ai/shared/a2a/2025-draft/schema"
 	"go.uber.org/zap"
@@ -26,11 +28,17 @@ type YamlConfig struct {
 	logLevel                    string
 	DiscoveringHandlerPathValue string
 	frontendAddressValue        string
+	allowedOrigins              []string
+	sseKeepAliveInterval        time.Duration
+	adminToken                  string
+	maxSubscriptionsPerUser     int
 	authorizationType           AuthorizationType
 	userKeyHashes               map[string]string
 	userParams                  map[string]map[string]string
 	userSubscribes              map[string][]string
 	backends                    map[string]*Backend
+	userA2AAgents               map[string]string
+	featureFlags                map[string]*FeatureFlag
 
 	// SSL Fields
 	sslEnabled      bool
@@ -43,33 +51,79 @@ type YamlConfig struct {
 
 	// A2A Fields
 	a2a *a2aSchema.AgentCard
+
+	// Reloading, see yaml_watch.go
+	watchInterval time.Duration
+	loadedHash    [sha256.Size]byte // Contents last loaded by update
+	watchStop     chan struct{}
+	closeOnce     sync.Once
+	subscribersMu sync.Mutex
+	subscribers   []*changeSubscriber
 }
 
 // YAML configuration structure matching the required format
 type yamlConfig struct {
 	Server struct {
-		Address                string               `yaml:"address"`
-		Name                   string               `yaml:"name"`
-		Version                string               `yaml:"version"`
-		LogLevel               string               `yaml:"log_level"`
-		DiscoveringHandlerPath string               `yaml:"info_handler"`
-		FrontendAddress        string               `yaml:"frontend_address"`
-		Authorization          string               `yaml:"authorization"`
-		SSL                    yamlSSLConfig        `yaml:"ssl"`
-		A2A                    *a2aSchema.AgentCard `yaml:"a2a"`
+		Address                 string               `yaml:"address"`
+		Name                    string               `yaml:"name"`
+		Version                 string               `yaml:"version"`
+		LogLevel                string               `yaml:"log_level"`
+		DiscoveringHandlerPath  string               `yaml:"info_handler"`
+		FrontendAddress         string               `yaml:"frontend_address"`
+		Authorization           string               `yaml:"authorization"`
+		AllowedOrigins          []string             `yaml:"allowed_origins"`
+		SSEKeepAliveInterval    *time.Duration       `yaml:"sse_keepalive_interval"`
+		AdminToken              string               `yaml:"admin_token"`
+		MaxSubscriptionsPerUser int                  `yaml:"max_subscriptions_per_user"`
+		SSL                     yamlSSLConfig        `yaml:"ssl"`
+		A2A                     *a2aSchema.AgentCard `yaml:"a2a"`
 	} `yaml:"server"`
-	Users    map[string]yamlUserConfig    `yaml:"users"`
-	Backends map[string]yamlBackendConfig `yaml:"backends"`
+	Users        map[string]yamlUserConfig        `yaml:"users"`
+	Backends     map[string]yamlBackendConfig     `yaml:"backends"`
+	FeatureFlags map[string]yamlFeatureFlagConfig `yaml:"feature_flags"`
+}
+
+// yamlFeatureFlagConfig enables a flag globally; the user lists override that for individual users.
+type yamlFeatureFlagConfig struct {
+	Enabled       bool     `yaml:"enabled"`
+	EnabledUsers  []string `yaml:"enabled_users"`
+	DisabledUsers []string `yaml:"disabled_users"`
 }
 
 type yamlUserConfig struct {
 	Keys       []string `yaml:"keys"`
 	Subscribes []string `yaml:"subscribes"`
+	A2AAgent   string   `yaml:"a2a_agent"`
 }
 
 type yamlBackendConfig struct {
 	URL    string `yaml:"url"`
 	Bearer string `yaml:"bearer"` // Corrected yaml tag
+	// Reject calls to tools annotated with destructiveHint
+	BlockDestructiveTools bool `yaml:"blockDestructiveTools"`
+	// Health endpoint probed by the gateway, /health by default
+	HealthPath string `yaml:"healthPath"`
+	// Endpoints of a REST backend exposed as MCP tools
+	RestTools []yamlRestToolConfig `yaml:"restTools"`
+	// Client certificate and trusted CAs for HTTPS backends
+	TLS *yamlBackendTLSConfig `yaml:"tls"`
+}
+
+type yamlBackendTLSConfig struct {
+	CertFile           string `yaml:"certFile"`
+	KeyFile            string `yaml:"keyFile"`
+	CAFile             string `yaml:"caFile"`
+	InsecureSkipVerify bool   `yaml:"insecureSkipVerify"`
+}
+
+type yamlRestToolConfig struct {
+	Name            string   `yaml:"name"`
+	Description     string   `yaml:"description"`
+	URL             string   `yaml:"url"`
+	Method          string   `yaml:"method"`
+	PathParams      []string `yaml:"pathParams"`
+	BodyArgs        []string `yaml:"bodyArgs"`
+	ResponseMapping string   `yaml:"responseMapping"`
 }
 
 type yamlSSLConfig struct {
@@ -82,13 +136,14 @@ type yamlSSLConfig struct {
 	AcmeCacheDir string   `yaml:"acme_cache_dir"`
 }
 
-// NewYamlConfig creates a new YAML-based configuration
-func NewYamlConfig(configPath string, logger *zap.Logger) (*YamlConfig, error) {
-	return NewYamlConfigWithOptions(configPath, logger)
+// NewYamlConfig creates a new YAML-based configuration. The file is reloaded when it changes
+// (see WithWatchInterval) until Close is called.
+func NewYamlConfig(configPath string, logger *zap.Logger, options ...YamlConfigOption) (*YamlConfig, error) {
+	return NewYamlConfigWithOptions(configPath, logger, options...)
 }
 
 // NewYamlConfigWithOptions creates a new YAML-based configuration with specified options
-func NewYamlConfigWithOptions(configPath string, logger *zap.Logger) (*YamlConfig, error) {
+func NewYamlConfigWithOptions(configPath string, logger *zap.Logger, options ...YamlConfigOption) (*YamlConfig, error) {
 	if logger == nil {
 		logger, _ = zap.NewProduction()
 	}
@@ -99,20 +154,43 @@ func NewYamlConfigWithOptions(configPath string, logger *zap.Logger) (*YamlConfi
 		userParams:        make(map[string]map[string]string),
 		userSubscribes:    make(map[string][]string),
 		backends:          make(map[string]*Backend),
+		userA2AAgents:     make(map[string]string),
+		featureFlags:      make(map[string]*FeatureFlag),
 		authorizationType: AuthorizedUsersOnly, // Default
 		sslMode:           "manual",
 		sslAcmeCacheDir:   "./.autocert-cache",
+		watchInterval:     DefaultYamlWatchInterval,
+		watchStop:         make(chan struct{}),
+	}
+	for _, option := range options {
+		option(config)
 	}
 	if err := config.Update(); err != nil {
 		return nil, err
 	}
+	if config.watchInterval > 0 {
+		config.startWatching(config.watchInterval)
+	}
 	return config, nil
 }
 
-// Update reloads configuration from the YAML file
+// Update reloads configuration from the YAML file and notifies the subscribers of the changed
+// settings. If the file cannot be read or parsed, the current configuration is kept.
 func (c *YamlConfig) Update() error {
 	c.mu.Lock()
-	defer c.mu.Unlock()
+	before := c.changeSnapshot()
+	err := c.update()
+	after := c.changeSnapshot()
+	c.mu.Unlock()
+	if err != nil {
+		return err
+	}
+	c.notify(diffSnapshots(before, after))
+	return nil
+}
+
+// update replaces the settings with the file contents. Must be called with c.mu held.
+func (c *YamlConfig) update() error {
 	c.logger.Debug("Updating configuration from YAML", zap.String("path", c.configPath))
 	data, err := os.ReadFile(c.configPath)
 	if err != nil {
@@ -122,6 +200,8 @@ func (c *YamlConfig) Update() error {
 	if err := yaml.Unmarshal(data, &yamlCfg); err != nil {
 		return fmt.Errorf("parse YAML: %w", err)
 	}
+	// Only set once the contents parsed, so a failed file is loaded again when it is rewritten
+	c.loadedHash = sha256.Sum256(data)
 
 	// Process Server Section
 	c.serverAddress = yamlCfg.Server.Address
@@ -130,6 +210,13 @@ func (c *YamlConfig) Update() error {
 	c.logLevel = yamlCfg.Server.LogLevel
 	c.DiscoveringHandlerPathValue = yamlCfg.Server.DiscoveringHandlerPath
 	c.frontendAddressValue = yamlCfg.Server.FrontendAddress
+	c.allowedOrigins = yamlCfg.Server.AllowedOrigins
+	c.sseKeepAliveInterval = DefaultSSEKeepAliveInterval
+	if yamlCfg.Server.SSEKeepAliveInterval != nil {
+		c.sseKeepAliveInterval = *yamlCfg.Server.SSEKeepAliveInterval
+	}
+	c.adminToken = yamlCfg.Server.AdminToken
+	c.maxSubscriptionsPerUser = yamlCfg.Server.MaxSubscriptionsPerUser
 	switch strings.ToLower(yamlCfg.Server.Authorization) {
 	case "marked_methods":
 		c.authorizationType = NotAuthorizedToMarkedMethods
@@ -160,6 +247,7 @@ func (c *YamlConfig) Update() error {
 	// Process Users Section
 	newUserKeyHashes := make(map[string]string)
 	newUserSubscribes := make(map[string][]string)
+	newUserA2AAgents := make(map[string]string)
 	for userID, user := range yamlCfg.Users {
 		for _, keyHash := range user.Keys {
 			newUserKeyHashes[keyHash] = userID
@@ -169,23 +257,52 @@ func (c *YamlConfig) Update() error {
 			copy(ns, user.Subscribes)
 			newUserSubscribes[userID] = ns
 		}
+		if user.A2AAgent != "" {
+			newUserA2AAgents[userID] = user.A2AAgent
+		}
 	}
 	c.userKeyHashes = newUserKeyHashes
 	c.userSubscribes = newUserSubscribes
+	c.userA2AAgents = newUserA2AAgents
 
 	// Process Backends Section
 	newBackends := make(map[string]*Backend)
 	for backendID, backend := range yamlCfg.Backends {
-		newBackends[backendID] = &Backend{URL: backend.URL, Bearer: backend.Bearer}
+		newBackends[backendID] = &Backend{URL: backend.URL, Bearer: backend.Bearer, BlockDestructiveTools: backend.BlockDestructiveTools, HealthPath: backend.HealthPath}
+		for _, tool := range backend.RestTools {
+			newBackends[backendID].RestTools = append(newBackends[backendID].RestTools, RestTool(tool))
+		}
+		if backend.TLS != nil {
+			tlsConfig := BackendTLSConfig(*backend.TLS)
+			newBackends[backendID].TLS = &tlsConfig
+		}
 	}
 	c.backends = newBackends
 
+	// Process Feature Flags Section
+	newFeatureFlags := make(map[string]*FeatureFlag)
+	for name, flag := range yamlCfg.FeatureFlags {
+		overrides := make(map[string]bool)
+		for _, userID := range flag.EnabledUsers {
+			overrides[userID] = true
+		}
+		for _, userID := range flag.DisabledUsers {
+			overrides[userID] = false // Disabling wins if a user is listed twice
+		}
+		newFeatureFlags[name] = &FeatureFlag{Enabled: flag.Enabled, UserOverrides: overrides}
+	}
+	c.featureFlags = newFeatureFlags
+
 	return nil
 }
 
 // --- IConfig Implementation ---
 
-func (c *YamlConfig) Close() error { return nil }
+// Close stops reloading the file.
+func (c *YamlConfig) Close() error {
+	c.closeOnce.Do(func() { close(c.watchStop) })
+	return nil
+}
 func (c *YamlConfig) ListenAddr() (string, error) {
 	c.mu.RLock()
 	defer c.mu.RUnlock()
@@ -241,6 +358,28 @@ func (c *YamlConfig) SSLKeyFile() (string, error) {
 	defer c.mu.RUnlock()
 	return c.sslKeyFile, nil
 }
+func (c *YamlConfig) AllowedOrigins() ([]string, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	origins := make([]string, len(c.allowedOrigins))
+	copy(origins, c.allowedOrigins)
+	return origins, nil
+}
+func (c *YamlConfig) SSEKeepAliveInterval() (time.Duration, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.sseKeepAliveInterval, nil
+}
+func (c *YamlConfig) AdminToken() (string, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.adminToken, nil
+}
+func (c *YamlConfig) MaxSubscriptionsPerUser() (int, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.maxSubscriptionsPerUser, nil
+}
 func (c *YamlConfig) SSLAcmeDomains() ([]string, error) {
 	c.mu.RLock()
 	defer c.mu.RUnlock()
@@ -258,6 +397,9 @@ func (c *YamlConfig) SSLAcmeCacheDir() (string, error) {
 	defer c.mu.RUnlock()
 	return c.sslAcmeCacheDir, nil
 }
+func (c *YamlConfig) Schema() ConfigSchema {
+	return DefaultConfigSchema()
+}
 func (c *YamlConfig) Status(ctx context.Context) error {
 	if _, err := os.Stat(c.configPath); err != nil {
 		return fmt.Errorf("config file error: %w", err)
@@ -310,6 +452,17 @@ func (c *YamlConfig) GetBackendBySlug(backendID string) (*Backend, error) {
 	return &bc, nil
 }
 
+// IsDestructiveBlocked returns the backend's blockDestructiveTools setting.
+func (c *YamlConfig) IsDestructiveBlocked(serverSlug string) (bool, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	backend, exists := c.backends[serverSlug]
+	if !exists {
+		return false, nil
+	}
+	return backend.BlockDestructiveTools, nil
+}
+
 // NEW: GetServerHeaders returns empty map for YAML config.
 func (c *YamlConfig) GetServerHeaders(serverSlug string) (map[string]string, error) {
 	return make(map[string]string), nil
@@ -331,3 +484,29 @@ func (c *YamlConfig) GetA2AAgentCard(agentURL string) (*a2aSchema.AgentCard, err
 	cardCopy.URL = agentURL
 	return &cardCopy, nil
 }
+
+// GetA2ABackendForUser resolves the URL of the A2A backend the user's tasks are routed to.
+func (c *YamlConfig) GetA2ABackendForUser(userID string, agentSlug string) (string, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	slug, err := resolveA2AAgentSlug(agentSlug, c.userA2AAgents[userID], c.userSubscribes[userID])
+	if err != nil {
+		return "", err
+	}
+	backend, exists := c.backends[slug]
+	if !exists {
+		return "", ErrNotFound
+	}
+	return backend.URL, nil
+}
+
+// GetFeatureFlag reports whether flag name is enabled for userID.
+func (c *YamlConfig) GetFeatureFlag(name string, userID string) (bool, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	flag, exists := c.featureFlags[name]
+	if !exists {
+		return false, ErrNotFound
+	}
+	return flag.EnabledFor(userID), nil
+}
@@ -0,0 +1,264 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestYamlConfigFeatureFlags(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	yamlData := `
+server:
+  address: ":8080"
+feature_flags:
+  a2a_streaming:
+    enabled: true
+    disabled_users: ["user2"]
+  beta_tools:
+    enabled: false
+    enabled_users: ["user1"]
+`
+	if err := os.WriteFile(path, []byte(yamlData), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := NewYamlConfig(path, zap.NewNop())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		flag, userID string
+		want         bool
+	}{
+		{"a2a_streaming", "user1", true},
+		{"a2a_streaming", "user2", false},
+		{"a2a_streaming", "", true},
+		{"beta_tools", "user1", true},
+		{"beta_tools", "user2", false},
+	}
+	for _, tt := range tests {
+		got, err := cfg.GetFeatureFlag(tt.flag, tt.userID)
+		if err != nil {
+			t.Fatalf("GetFeatureFlag(%q, %q): %v", tt.flag, tt.userID, err)
+		}
+		if got != tt.want {
+			t.Errorf("GetFeatureFlag(%q, %q) = %v, want %v", tt.flag, tt.userID, got, tt.want)
+		}
+	}
+
+	if _, err := cfg.GetFeatureFlag("unknown", "user1"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("unknown flag: got %v, want ErrNotFound", err)
+	}
+	if enabled, err := FeatureEnabled(cfg, "unknown", "user1", true); err != nil || !enabled {
+		t.Errorf("FeatureEnabled on unknown flag = %v, %v, want the default", enabled, err)
+	}
+}
+
+func TestYamlConfigSSEKeepAliveInterval(t *testing.T) {
+	tests := []struct {
+		name, server string
+		want         time.Duration
+	}{
+		{"default", `address: ":8080"`, DefaultSSEKeepAliveInterval},
+		{"custom", `sse_keepalive_interval: 30s`, 30 * time.Second},
+		{"disabled", `sse_keepalive_interval: 0s`, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "config.yaml")
+			if err := os.WriteFile(path, []byte("server:\n  "+tt.server+"\n"), 0o600); err != nil {
+				t.Fatal(err)
+			}
+			cfg, err := NewYamlConfig(path, zap.NewNop())
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got, err := cfg.SSEKeepAliveInterval(); err != nil || got != tt.want {
+				t.Errorf("SSEKeepAliveInterval() = %s, %v, want %s", got, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestYamlConfigBackendTLS(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	yamlData := `
+backends:
+  secure:
+    url: "https://secure:4443/mcp"
+    tls:
+      certFile: /certs/client.pem
+      keyFile: /certs/client-key.pem
+      caFile: /certs/ca.pem
+  plain:
+    url: "http://plain:4000/mcp"
+`
+	if err := os.WriteFile(path, []byte(yamlData), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := NewYamlConfig(path, zap.NewNop())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cfg.Close()
+
+	backend, err := cfg.GetBackendBySlug("secure")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := BackendTLSConfig{CertFile: "/certs/client.pem", KeyFile: "/certs/client-key.pem", CAFile: "/certs/ca.pem"}
+	if backend.TLS == nil || *backend.TLS != want {
+		t.Errorf("TLS = %+v, want %+v", backend.TLS, want)
+	}
+	if backend, err := cfg.GetBackendBySlug("plain"); err != nil || backend.TLS != nil {
+		t.Errorf("Backend without tls section: TLS = %+v, %v, want nil", backend.TLS, err)
+	}
+}
+
+func TestYamlConfigReloadsChangedFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	write := func(data string) {
+		t.Helper()
+		if err := os.WriteFile(path, []byte(data), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write(`
+server:
+  address: ":8080"
+backends:
+  weather:
+    url: "http://weather-1:4000/mcp"
+`)
+	cfg, err := NewYamlConfig(path, zap.NewNop(), WithWatchInterval(10*time.Millisecond))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cfg.Close()
+	events := make(chan ConfigChangeEvent, 10)
+	cfg.Subscribe(events)
+
+	next := func() ConfigChangeEvent {
+		t.Helper()
+		select {
+		case event := <-events:
+			return event
+		case <-time.After(2 * time.Second):
+			t.Fatal("No change event after the config file was rewritten")
+			return ConfigChangeEvent{}
+		}
+	}
+
+	write(`
+server:
+  address: ":8080"
+backends:
+  weather:
+    url: "http://weather-2:4000/mcp"
+`)
+	event := next()
+	want := ConfigChangeEvent{Field: "backends.weather.url", OldValue: "http://weather-1:4000/mcp", NewValue: "http://weather-2:4000/mcp"}
+	if event != want {
+		t.Fatalf("Got event %+v, want %+v", event, want)
+	}
+	if backend, err := cfg.GetBackendBySlug("weather"); err != nil || backend.URL != "http://weather-2:4000/mcp" {
+		t.Fatalf("GetBackendBySlug after reload = %+v, %v", backend, err)
+	}
+
+	// A broken file keeps the previous configuration
+	write("server: [unclosed\n")
+	time.Sleep(100 * time.Millisecond)
+	if backend, err := cfg.GetBackendBySlug("weather"); err != nil || backend.URL != "http://weather-2:4000/mcp" {
+		t.Fatalf("GetBackendBySlug after a broken reload = %+v, %v", backend, err)
+	}
+	select {
+	case event := <-events:
+		t.Fatalf("Unexpected event %+v for a broken file", event)
+	default:
+	}
+
+	// Fixing the file reloads it again
+	write(`
+server:
+  address: ":9090"
+backends:
+  weather:
+    url: "http://weather-2:4000/mcp"
+`)
+	event = next()
+	want = ConfigChangeEvent{Field: "server.address", OldValue: ":8080", NewValue: ":9090"}
+	if event != want {
+		t.Fatalf("Got event %+v, want %+v", event, want)
+	}
+}
+
+func TestYamlConfigRetriesFailedFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, []byte("server:\n  address: \":8080\"\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	core, logs := observer.New(zapcore.ErrorLevel)
+	cfg, err := NewYamlConfig(path, zap.New(core), WithWatchInterval(10*time.Millisecond))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cfg.Close()
+
+	// The same broken contents are loaded again on every write, not remembered as loaded
+	for attempt := 1; attempt <= 2; attempt++ {
+		if err := os.WriteFile(path, []byte("server: [unclosed\n"), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		deadline := time.Now().Add(2 * time.Second)
+		for logs.FilterMessage("Failed to reload config file, keeping the previous configuration").Len() < attempt {
+			if time.Now().After(deadline) {
+				t.Fatalf("Broken config file was not loaded on write %d", attempt)
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+	}
+	if addr, err := cfg.ListenAddr(); err != nil || addr != ":8080" {
+		t.Fatalf("ListenAddr after broken reloads = %q, %v", addr, err)
+	}
+}
+
+func TestYamlConfigSlowSubscriberDoesNotBlockReloads(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	write := func(addr string) {
+		t.Helper()
+		if err := os.WriteFile(path, []byte("server:\n  address: \""+addr+"\"\n"), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write(":8080")
+	cfg, err := NewYamlConfig(path, zap.NewNop(), WithWatchInterval(10*time.Millisecond))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cfg.Close()
+	cfg.Subscribe(make(chan ConfigChangeEvent)) // Never read
+	events := make(chan ConfigChangeEvent)
+	cfg.Subscribe(events)
+
+	for _, addr := range []string{":8081", ":8082"} {
+		write(addr)
+		select {
+		case event := <-events:
+			if event.NewValue != addr {
+				t.Fatalf("Got event %+v, want server.address %s", event, addr)
+			}
+		case <-time.After(2 * time.Second):
+			t.Fatalf("No change event for server.address %s", addr)
+		}
+	}
+	if addr, err := cfg.ListenAddr(); err != nil || addr != ":8082" {
+		t.Fatalf("ListenAddr = %q, %v", addr, err)
+	}
+}
@@ -0,0 +1,254 @@
+package config
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"go.uber.org/zap"
+)
+
+// DefaultYamlWatchInterval is how long a changed config file must stay unchanged before a
+// YamlConfig reloads it.
+const DefaultYamlWatchInterval = 500 * time.Millisecond
+
+// ConfigChangeEvent reports a setting changed by a config reload. Field uses the YAML key path,
+// e.g. "server.log_level" or "backends.<slug>.url"; OldValue is nil for added settings and
+// NewValue is nil for removed ones.
+type ConfigChangeEvent struct {
+	Field    string
+	OldValue interface{}
+	NewValue interface{}
+}
+
+// ChangeNotifier is implemented by configs that reload at runtime.
+type ChangeNotifier interface {
+	// Subscribe sends the events of every later reload to ch, in order. Events are queued
+	// for each subscriber, so a slow subscriber delays neither reloads nor other subscribers.
+	Subscribe(ch chan<- ConfigChangeEvent)
+}
+
+var _ ChangeNotifier = (*YamlConfig)(nil)
+
+// YamlConfigOption configures a YamlConfig.
+type YamlConfigOption func(*YamlConfig)
+
+// WithWatchInterval sets how long the config file must stay unchanged after a write before
+// it is reloaded; 0 disables reloading. Defaults to DefaultYamlWatchInterval.
+func WithWatchInterval(interval time.Duration) YamlConfigOption {
+	return func(c *YamlConfig) {
+		c.watchInterval = interval
+	}
+}
+
+// changeSubscriber forwards queued events to ch from its own goroutine.
+type changeSubscriber struct {
+	ch     chan<- ConfigChangeEvent
+	mu     sync.Mutex
+	queue  []ConfigChangeEvent
+	queued chan struct{} // Signalled when events are added to queue
+}
+
+// Subscribe implements ChangeNotifier.
+func (c *YamlConfig) Subscribe(ch chan<- ConfigChangeEvent) {
+	subscriber := &changeSubscriber{ch: ch, queued: make(chan struct{}, 1)}
+	c.subscribersMu.Lock()
+	c.subscribers = append(c.subscribers, subscriber)
+	c.subscribersMu.Unlock()
+	go subscriber.forward(c.watchStop)
+}
+
+func (s *changeSubscriber) push(events []ConfigChangeEvent) {
+	s.mu.Lock()
+	s.queue = append(s.queue, events...)
+	s.mu.Unlock()
+	select {
+	case s.queued <- struct{}{}:
+	default: // Already signalled
+	}
+}
+
+// forward sends the queued events to the subscriber until stop is closed.
+func (s *changeSubscriber) forward(stop <-chan struct{}) {
+	for {
+		select {
+		case <-stop:
+			return
+		case <-s.queued:
+		}
+		s.mu.Lock()
+		events := s.queue
+		s.queue = nil
+		s.mu.Unlock()
+		for _, event := range events {
+			select {
+			case s.ch <- event:
+			case <-stop:
+				return
+			}
+		}
+	}
+}
+
+func (c *YamlConfig) notify(events []ConfigChangeEvent) {
+	if len(events) == 0 {
+		return
+	}
+	for _, event := range events {
+		c.logger.Info("Configuration changed", zap.String("field", event.Field))
+	}
+	c.subscribersMu.Lock()
+	defer c.subscribersMu.Unlock()
+	for _, subscriber := range c.subscribers {
+		subscriber.push(events)
+	}
+}
+
+// startWatching starts reloading the config whenever the file changes, until Close is called.
+// The directory is watched rather than the file, so files replaced by a rename (as most editors
+// save) keep being watched. If the file cannot be watched, the config is not reloaded.
+func (c *YamlConfig) startWatching(settle time.Duration) {
+	watcher, err := fsnotify.NewWatcher()
+	if err == nil {
+		err = watcher.Add(filepath.Dir(c.configPath))
+		if err != nil {
+			watcher.Close()
+		}
+	}
+	if err != nil {
+		c.logger.Error("Failed to watch config file, changes will not be reloaded", zap.String("path", c.configPath), zap.Error(err))
+		return
+	}
+	go c.watch(watcher, settle)
+}
+
+// watch loads a change once the file stayed untouched for settle, so a file caught in the
+// middle of a write is not applied, and only if its contents differ from the last loaded ones.
+// A file that fails to load is logged and the previous config is kept until the next write.
+func (c *YamlConfig) watch(watcher *fsnotify.Watcher, settle time.Duration) {
+	defer watcher.Close()
+	path := filepath.Clean(c.configPath)
+	timer := time.NewTimer(settle)
+	timer.Stop()
+	defer timer.Stop()
+	for {
+		select {
+		case <-c.watchStop:
+			return
+		case event, ok := <-watcher.Events:
+			if !ok {
+				return
+			}
+			if filepath.Clean(event.Name) == path && !event.Has(fsnotify.Chmod) {
+				timer.Reset(settle)
+			}
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return
+			}
+			c.logger.Warn("Config file watcher failed", zap.String("path", c.configPath), zap.Error(err))
+		case <-timer.C:
+			c.reloadIfChanged()
+		}
+	}
+}
+
+// reloadIfChanged reloads the file unless its contents are the ones last loaded.
+func (c *YamlConfig) reloadIfChanged() {
+	data, err := os.ReadFile(c.configPath)
+	if err != nil {
+		c.logger.Warn("Failed to read config file", zap.String("path", c.configPath), zap.Error(err))
+		return
+	}
+	hash := sha256.Sum256(data)
+	c.mu.RLock()
+	unchanged := hash == c.loadedHash
+	c.mu.RUnlock()
+	if unchanged {
+		return
+	}
+	if err := c.Update(); err != nil {
+		c.logger.Error("Failed to reload config file, keeping the previous configuration", zap.String("path", c.configPath), zap.Error(err))
+	}
+}
+
+// changeSnapshot flattens the settings into field -> value for diffing. Must be called with c.mu held.
+func (c *YamlConfig) changeSnapshot() map[string]interface{} {
+	s := map[string]interface{}{
+		"server.address":                c.serverAddress,
+		"server.name":                   c.serverName,
+		"server.version":                c.serverVersion,
+		"server.log_level":              c.logLevel,
+		"server.info_handler":           c.DiscoveringHandlerPathValue,
+		"server.frontend_address":       c.frontendAddressValue,
+		"server.authorization":          c.authorizationType.String(),
+		"server.allowed_origins":        append([]string(nil), c.allowedOrigins...),
+		"server.sse_keepalive_interval": c.sseKeepAliveInterval,
+		"server.ssl.enabled":            c.sslEnabled,
+		"server.ssl.mode":               c.sslMode,
+		"server.ssl.cert_file":          c.sslCertFile,
+		"server.ssl.key_file":           c.sslKeyFile,
+		"server.ssl.acme_domains":       append([]string(nil), c.sslAcmeDomains...),
+		"server.ssl.acme_email":         c.sslAcmeEmail,
+		"server.ssl.acme_cache_dir":     c.sslAcmeCacheDir,
+	}
+	if c.a2a != nil {
+		card := *c.a2a
+		s["server.a2a"] = card
+	}
+	for slug, backend := range c.backends {
+		prefix := "backends." + slug + "."
+		s[prefix+"url"] = backend.URL
+		s[prefix+"bearer"] = backend.Bearer
+		s[prefix+"blockDestructiveTools"] = backend.BlockDestructiveTools
+		s[prefix+"healthPath"] = backend.HealthPath
+		s[prefix+"restTools"] = append([]RestTool(nil), backend.RestTools...)
+		if backend.TLS != nil {
+			s[prefix+"tls"] = *backend.TLS
+		}
+	}
+	userKeys := make(map[string][]string)
+	for keyHash, userID := range c.userKeyHashes {
+		userKeys[userID] = append(userKeys[userID], keyHash)
+	}
+	for userID, keys := range userKeys {
+		sort.Strings(keys)
+		s["users."+userID+".keys"] = keys
+	}
+	for userID, subscribes := range c.userSubscribes {
+		s["users."+userID+".subscribes"] = append([]string(nil), subscribes...)
+	}
+	for userID, agent := range c.userA2AAgents {
+		s["users."+userID+".a2a_agent"] = agent
+	}
+	for name, flag := range c.featureFlags {
+		s["feature_flags."+name] = fmt.Sprintf("%+v", *flag)
+	}
+	return s
+}
+
+// diffSnapshots returns the fields that differ between two snapshots, sorted by field.
+func diffSnapshots(before, after map[string]interface{}) []ConfigChangeEvent {
+	var events []ConfigChangeEvent
+	for field, oldValue := range before {
+		newValue, ok := after[field]
+		if !ok {
+			events = append(events, ConfigChangeEvent{Field: field, OldValue: oldValue})
+		} else if !reflect.DeepEqual(oldValue, newValue) {
+			events = append(events, ConfigChangeEvent{Field: field, OldValue: oldValue, NewValue: newValue})
+		}
+	}
+	for field, newValue := range after {
+		if _, ok := before[field]; !ok {
+			events = append(events, ConfigChangeEvent{Field: field, NewValue: newValue})
+		}
+	}
+	sort.Slice(events, func(i, j int) bool { return events[i].Field < events[j].Field })
+	return events
+}
@@ -0,0 +1,23 @@
+package shared
+
+import "context"
+
+// CorrelationIDHeader carries the ID that correlates log entries of one request across the
+// gateway and its backends.
+const CorrelationIDHeader = "X-Correlation-Id"
+
+// CorrelationIDMetaKey is the result _meta key the gateway returns the correlation ID in.
+const CorrelationIDMetaKey = "correlationId"
+
+type correlationIDKey struct{}
+
+// ContextWithCorrelationID returns a copy of ctx carrying the correlation ID id.
+func ContextWithCorrelationID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, correlationIDKey{}, id)
+}
+
+// CorrelationIDFromContext returns the correlation ID stored in ctx, or "".
+func CorrelationIDFromContext(ctx context.Context) string {
+	id, _ := ctx.Value(correlationIDKey{}).(string)
+	return id
+}
@@ -0,0 +1,96 @@
+package shared
+
+import (
+	"sync"
+
+	"go.uber.org/zap"
+)
+
+// EventBus lets capabilities of the same server react to each other's events, e.g. a
+// capability refreshing a resource after a tool call. Topics are plain strings; capabilities
+// publishing events document their topics and payload types.
+type EventBus interface {
+	// Publish delivers payload to the handlers subscribed to topic before it returns.
+	Publish(topic string, payload interface{})
+	// Subscribe calls handler with the payload of each event published to topic until the
+	// returned Subscription is cancelled.
+	Subscribe(topic string, handler func(interface{})) Subscription
+}
+
+// Subscription is a handler subscribed to an EventBus topic.
+type Subscription interface {
+	// Unsubscribe stops the deliveries to the handler. Calling it again has no effect.
+	Unsubscribe()
+}
+
+var _ EventBus = (*LocalEventBus)(nil)
+
+// LocalEventBus is an in-process EventBus. Handlers run on the publishing goroutine in the
+// order they subscribed, so they must not block; a handler that panics is logged and skipped.
+type LocalEventBus struct {
+	logger *zap.Logger
+	mu     sync.RWMutex
+	topics map[string][]*localSubscription
+}
+
+type localSubscription struct {
+	bus     *LocalEventBus
+	topic   string
+	handler func(interface{})
+	once    sync.Once
+}
+
+// NewLocalEventBus creates an in-process EventBus without subscriptions.
+func NewLocalEventBus(logger *zap.Logger) *LocalEventBus {
+	return &LocalEventBus{
+		logger: logger.Named("event-bus"),
+		topics: make(map[string][]*localSubscription),
+	}
+}
+
+// Publish calls the handlers subscribed to topic with payload.
+func (b *LocalEventBus) Publish(topic string, payload interface{}) {
+	b.mu.RLock()
+	subscriptions := b.topics[topic] // Replaced, never modified, by Subscribe and Unsubscribe
+	b.mu.RUnlock()
+	for _, subscription := range subscriptions {
+		b.deliver(subscription, payload)
+	}
+}
+
+func (b *LocalEventBus) deliver(subscription *localSubscription, payload interface{}) {
+	defer func() {
+		if r := recover(); r != nil {
+			b.logger.Error("Panic in event handler", zap.String("topic", subscription.topic), zap.Any("panic", r))
+		}
+	}()
+	subscription.handler(payload)
+}
+
+// Subscribe adds handler to the handlers of topic.
+func (b *LocalEventBus) Subscribe(topic string, handler func(interface{})) Subscription {
+	subscription := &localSubscription{bus: b, topic: topic, handler: handler}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	subscriptions := make([]*localSubscription, 0, len(b.topics[topic])+1)
+	b.topics[topic] = append(append(subscriptions, b.topics[topic]...), subscription)
+	return subscription
+}
+
+func (s *localSubscription) Unsubscribe() {
+	s.once.Do(func() {
+		s.bus.mu.Lock()
+		defer s.bus.mu.Unlock()
+		remaining := make([]*localSubscription, 0, len(s.bus.topics[s.topic]))
+		for _, subscription := range s.bus.topics[s.topic] {
+			if subscription != s {
+				remaining = append(remaining, subscription)
+			}
+		}
+		if len(remaining) == 0 {
+			delete(s.bus.topics, s.topic)
+		} else {
+			s.bus.topics[s.topic] = remaining
+		}
+	})
+}
@@ -0,0 +1,32 @@
+package shared
+
+import (
+	"reflect"
+	"testing"
+
+	"go.uber.org/zap"
+)
+
+func TestLocalEventBus(t *testing.T) {
+	bus := NewLocalEventBus(zap.NewNop())
+	var got []string
+	first := bus.Subscribe("topic", func(payload interface{}) { got = append(got, "first:"+payload.(string)) })
+	bus.Subscribe("topic", func(payload interface{}) { panic("broken handler") })
+	bus.Subscribe("topic", func(payload interface{}) { got = append(got, "last:"+payload.(string)) })
+	bus.Subscribe("other", func(payload interface{}) { got = append(got, "other:"+payload.(string)) })
+
+	// Handlers of the topic run in subscription order before Publish returns; a panic skips one
+	bus.Publish("topic", "a")
+	if want := []string{"first:a", "last:a"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("Expected deliveries %v, got %v", want, got)
+	}
+
+	got = nil
+	first.Unsubscribe()
+	first.Unsubscribe()
+	bus.Publish("topic", "b")
+	bus.Publish("nobody", "c")
+	if want := []string{"last:b"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected deliveries %v after unsubscribing, got %v", want, got)
+	}
+}
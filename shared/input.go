@@ -16,15 +16,17 @@ type Input struct {
 	input           chan *Message
 	logger          *zap.Logger
 	validators      []MessageValidator
-	methodHandlers  sync.Map      // Maps method names to handler functions
-	notFoundHandler atomic.Value  // func(*shared.Message) (interface{}, error)
-	capabilities    []ICapability // List of capabilities
+	rateLimiter     RateLimiter      // Set by SetRateLimiter, nil means no limit
+	notFoundHandler atomic.Value     // func(*shared.Message) (interface{}, error)
+	capabilities    *CapabilityCache // Registered capabilities and their resolved method handlers
 }
 
 func NewInput(logger *zap.Logger) *Input {
 	i := &Input{
-		validators: []MessageValidator{},
-		logger:     logger,
+		input:        make(chan *Message, 100), // Created here so Put never races with Process starting up
+		validators:   []MessageValidator{},
+		logger:       logger,
+		capabilities: NewCapabilityCache(),
 	}
 	// Initialize notFoundHandler
 	i.notFoundHandler.Store(func(msg *Message) (interface{}, error) {
@@ -38,6 +40,10 @@ func NewInput(logger *zap.Logger) *Input {
 	return i
 }
 
+// ErrInputBusy is returned by Put when the input channel is full. Put has already answered the
+// request then, so callers must not send another response.
+var ErrInputBusy = errors.New("input processor busy, input channel full")
+
 type MessageValidator interface {
 	Validate(*Message) error
 }
@@ -54,7 +60,7 @@ func (i *Input) Put(msg *Message) error {
 			return err
 		}
 	}
-	msg.Session.UpdateLastActivity()
+	msg.Session.UpdateLastInboundActivity()
 
 	select {
 	case i.input <- msg:
@@ -73,19 +79,14 @@ func (i *Input) Put(msg *Message) error {
 		if !msg.ID.IsEmpty() {
 			go msg.Session.SendResponse(msg.ID, nil, errors.New("message processor busy, message dropped"))
 		}
-		return errors.New("input processor busy, input channel full")
+		return ErrInputBusy
 	}
 	return nil
 }
 
 func (i *Input) Process() {
 	i.logger.Debug("Input s- Message processing loop started.")
-	i.input = make(chan *Message, 100)
-	defer func() {
-		close(i.input)
-		i.input = nil
-		i.logger.Info("Input - Message processing loop stopped.")
-	}()
+	defer i.logger.Info("Input - Message processing loop stopped.")
 	for msg := range i.input {
 		i.logger.Debug("Processing message",
 			zap.String("sessionID", safeGetSessionID(msg.Session)),
@@ -107,6 +108,15 @@ func (i *Input) Process() {
 			continue
 		}
 
+		// Responses to our own requests are not limited
+		if msg.Method != nil && !i.allow(msg) {
+			logger.Warn("Rate limit exceeded", zap.String("method", *msg.Method), zap.Any("messageID", msg.ID))
+			if !msg.ID.IsEmpty() {
+				go msg.Session.SendResponse(msg.ID, nil, &JSONRPCError{Code: JSONRPCErrorServerError, Message: "Rate limit exceeded"})
+			}
+			continue
+		}
+
 		// Process each message in its own goroutine to prevent blocking the input channel
 		go func(msgToProcess *Message) {
 			defer func() {
@@ -178,7 +188,7 @@ func (i *Input) AddNotFoundHandle(handler func(*Message) (interface{}, error)) {
 
 // GetHandler retrieves a handler for a specific method
 func (i *Input) GetHandler(method string) (func(*Message) (interface{}, error), bool) {
-	handler, exists := i.methodHandlers.Load(method)
+	handler, exists := i.capabilities.Lookup(method)
 	if !exists {
 		// Use the stored notFoundHandler
 		notFoundFunc := i.notFoundHandler.Load()
@@ -188,7 +198,22 @@ func (i *Input) GetHandler(method string) (func(*Message) (interface{}, error),
 		// Fallback if notFoundHandler wasn't set correctly (shouldn't happen with NewManager)
 		return nil, false
 	}
-	return handler.(func(*Message) (interface{}, error)), true
+	return handler, true
+}
+
+// Handlers returns a snapshot of the registered method handlers (without the not-found handler).
+func (i *Input) Handlers() map[string]func(*Message) (interface{}, error) {
+	handlers := make(map[string]func(*Message) (interface{}, error))
+	for method, handler := range i.capabilities.Handlers() {
+		handlers[method] = handler
+	}
+	return handlers
+}
+
+// OnCapabilityChanged makes the next method lookup re-read the handlers of the registered
+// capabilities. Capabilities changing their handler set after registration must trigger it.
+func (i *Input) OnCapabilityChanged() {
+	i.capabilities.OnCapabilityChanged()
 }
 
 // AddValidator adds custom message validators
@@ -198,6 +223,29 @@ func (i *Input) AddValidator(validators ...MessageValidator) {
 	i.validators = append(i.validators, validators...)
 }
 
+// SetRateLimiter makes Process refuse the requests and notifications rl does not allow.
+// nil removes the limit.
+func (i *Input) SetRateLimiter(rl RateLimiter) {
+	i.Mu.Lock()
+	defer i.Mu.Unlock()
+	i.rateLimiter = rl
+}
+
+// allow asks the rate limiter, if any, whether msg may be processed.
+func (i *Input) allow(msg *Message) bool {
+	i.Mu.RLock()
+	rl := i.rateLimiter
+	i.Mu.RUnlock()
+	if rl == nil {
+		return true
+	}
+	userID := ""
+	if userSession, ok := msg.Session.(UserSession); ok {
+		userID = userSession.GetUserID()
+	}
+	return rl.Allow(msg.Session.GetID(), userID, *msg.Method)
+}
+
 // This method avoids the addition of incorrect capabilities (static analyzer assistance).
 func (i *Input) AddServerCapability(capabilities ...IServerCapability) {
 	for _, capability := range capabilities {
@@ -213,9 +261,8 @@ func (i *Input) AddClientCapability(capabilities ...IClientCapability) {
 }
 
 func (i *Input) addCapability(capability ICapability) {
-	i.capabilities = append(i.capabilities, capability)
-	for method, handler := range capability.GetHandlers() {
-		i.methodHandlers.Store(method, handler)
+	i.capabilities.Add(capability)
+	for method := range capability.GetHandlers() {
 		i.logger.Debug("Registered handler from capability",
 			zap.String("capability", fmt.Sprintf("%T", capability)),
 			zap.String("method", method))
@@ -225,7 +272,7 @@ func (i *Input) addCapability(capability ICapability) {
 func (i *Input) SetCapabilities(clientOrServerCapabilities any) {
 	// All capabilities must implement the same IServerCapability or IClientCapability interface
 	if clientCapabilities, ok := clientOrServerCapabilities.(*schema.ClientCapabilities); ok {
-		for _, capability := range i.capabilities {
+		for _, capability := range i.capabilities.Capabilities() {
 			if clientCapability, ok := capability.(IClientCapability); ok {
 				clientCapability.SetCapabilities(clientCapabilities)
 			} else {
@@ -234,7 +281,7 @@ func (i *Input) SetCapabilities(clientOrServerCapabilities any) {
 			}
 		}
 	} else if serverCapabilities, ok := clientOrServerCapabilities.(*schema.ServerCapabilities); ok {
-		for _, capability := range i.capabilities {
+		for _, capability := range i.capabilities.Capabilities() {
 			if serverCapability, ok := capability.(IServerCapability); ok {
 				serverCapability.SetCapabilities(serverCapabilities)
 			} else {
@@ -0,0 +1,43 @@
+package shared
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/gate4ai/gate4ai/shared/mcp/2025/schema"
+	"go.uber.org/zap"
+)
+
+func TestInputPutAnswersRequestsDroppedWhenFull(t *testing.T) {
+	logger := zap.NewNop()
+	input := NewInput(logger) // Not processed, so the channel fills up
+	session := NewBaseSession(logger, "busy", input, nil)
+	session.SetStatus(StatusConnected)
+	output, ok := session.AcquireOutput()
+	if !ok {
+		t.Fatalf("Failed to acquire session output")
+	}
+	defer session.ReleaseOutput()
+
+	method := "ping"
+	for n := uint64(1); n <= uint64(cap(input.input)); n++ {
+		id := schema.RequestID_FromUInt64(n)
+		if err := input.Put(&Message{ID: &id, Method: &method, Session: session}); err != nil {
+			t.Fatalf("Failed to put request %d: %v", n, err)
+		}
+	}
+	id := schema.RequestID_FromUInt64(uint64(cap(input.input)) + 1)
+	if err := input.Put(&Message{ID: &id, Method: &method, Session: session}); !errors.Is(err, ErrInputBusy) {
+		t.Fatalf("Expected ErrInputBusy for a full input, got %v", err)
+	}
+
+	select {
+	case response := <-output:
+		if response.Error == nil || response.ID.String() != id.String() {
+			t.Errorf("Expected an error response to the dropped request, got %+v", response)
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("Dropped request was not answered")
+	}
+}
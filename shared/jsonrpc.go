@@ -2,8 +2,10 @@ package shared
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 
+	a2aSchema "github.com/gate4ai/gate4ai/shared/a2a/2025-draft/schema"
 	"github.com/gate4ai/gate4ai/shared/mcp/2025/schema"
 )
 
@@ -21,6 +23,7 @@ const (
 	JSONRPCErrorServerError = -32000 // Generic server error
 
 	JSONRPCErrorUnauthorized = -32001 // Unauthorized
+	JSONRPCErrorCircuitOpen  = -32099 // The gateway's circuit breaker for the backend is open
 )
 
 type JSONRPCErrorResponse struct {
@@ -62,6 +65,9 @@ type JSONRPCError struct {
 	Code    int         `json:"code"`           // Error type code
 	Message string      `json:"message"`        // Short error description
 	Data    interface{} `json:"data,omitempty"` // Additional error information
+
+	Category          A2AErrorCategory `json:"category,omitempty"`            // Whether retrying can succeed, set on A2A stream errors
+	RetryAfterSeconds int              `json:"retry_after_seconds,omitempty"` // Delay before retrying a rate-limited A2A stream
 }
 
 // Error implements the Go error interface for JSONRPCError.
@@ -73,10 +79,30 @@ func (e *JSONRPCError) Error() string {
 	return fmt.Sprintf("%d: %s", e.Code, e.Message)
 }
 
+// WithData sets the error's data member and returns e for chaining.
+func (e *JSONRPCError) WithData(data interface{}) *JSONRPCError {
+	e.Data = data
+	return e
+}
+
+// NewJSONRPCError converts err to a JSON-RPC error. JSON-RPC errors (shared or A2A) keep
+// their code, message and data; any other error becomes an internal error.
 func NewJSONRPCError(err error) *JSONRPCError {
 	if err == nil {
 		return nil
 	}
+	var rpcErr *JSONRPCError
+	if errors.As(err, &rpcErr) {
+		return rpcErr
+	}
+	var a2aErr *a2aSchema.JSONRPCError
+	if errors.As(err, &a2aErr) {
+		converted := &JSONRPCError{Code: a2aErr.Code, Message: a2aErr.Message}
+		if a2aErr.Data != nil {
+			converted.Data = *a2aErr.Data
+		}
+		return converted
+	}
 	return &JSONRPCError{
 		Code:    JSONRPCErrorInternal,
 		Message: err.Error(),
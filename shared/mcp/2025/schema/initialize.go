@@ -32,6 +32,7 @@ type ServerCapabilities struct {
 	Prompts      *Capability                `json:"prompts,omitempty"`      // Present if the server offers any prompt templates
 	Resources    *CapabilityWithSubscribe   `json:"resources,omitempty"`    // Present if the server offers any resources to read
 	Tools        *Capability                `json:"tools,omitempty"`        // Present if the server offers any tools to call
+	Roots        *Capability                `json:"roots,omitempty"`        // Present if the server exposes file system roots
 }
 
 // InitializeResult is the server's response to initialization.
@@ -1,6 +1,9 @@
 package schema
 
 import (
+	"slices"
+	"time"
+
 	schema2024 "github.com/gate4ai/gate4ai/shared/mcp/2024/schema"
 )
 
@@ -89,7 +92,8 @@ type ListResourcesRequest struct {
 
 // ListResourcesRequestParams contains parameters for resource listing requests.
 type ListResourcesRequestParams struct {
-	PaginatedRequestParams // Embeds pagination cursor
+	PaginatedRequestParams          // Embeds pagination cursor
+	Tags                   []string `json:"tags,omitempty"` // Only list resources with at least one of these metadata tags
 }
 
 // ListResourcesResult is the response to a resources list request.
@@ -128,6 +132,34 @@ type Resource struct {
 	Description string       `json:"description,omitempty"` // A description of what this resource represents
 	MimeType    string       `json:"mimeType,omitempty"`    // The MIME type of this resource, if known
 	// Size field removed in 2025 schema
+	// Metadata describes the lifecycle of the resource (nil if none is set)
+	Metadata *ResourceMetadata `json:"metadata,omitempty"`
+	// Server-defined key/value metadata used for access control (e.g. "tier": "premium").
+	// Named AccessAnnotations to keep it apart from the MCP Annotations above. It stays on the
+	// server: clients must not learn the rules deciding their access.
+	AccessAnnotations map[string]string `json:"-"`
+}
+
+// ResourceMetadata describes the lifecycle of a resource.
+type ResourceMetadata struct {
+	Version            string    `json:"version,omitempty"`
+	Deprecated         bool      `json:"deprecated,omitempty"`
+	DeprecationMessage string    `json:"deprecationMessage,omitempty"`
+	Tags               []string  `json:"tags,omitempty"`
+	CreatedAt          time.Time `json:"createdAt,omitzero"`
+}
+
+// IsZero reports whether no metadata is set.
+func (m ResourceMetadata) IsZero() bool {
+	return m.Version == "" && !m.Deprecated && m.DeprecationMessage == "" && len(m.Tags) == 0 && m.CreatedAt.IsZero()
+}
+
+// HasAnyTag reports whether the resource metadata has at least one of tags.
+func (r *Resource) HasAnyTag(tags []string) bool {
+	if r.Metadata == nil {
+		return false
+	}
+	return slices.ContainsFunc(r.Metadata.Tags, func(tag string) bool { return slices.Contains(tags, tag) })
 }
 
 // ResourceListChangedNotification informs that available resources have changed.
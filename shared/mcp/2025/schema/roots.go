@@ -28,3 +28,9 @@ type ListRootsResult struct {
 	Meta  map[string]interface{} `json:"_meta,omitempty"` // Reserved for metadata
 	Roots []Root                 `json:"roots"`           // Available roots
 }
+
+// RootsAddedNotificationParams are the params of the gateway's "roots/add" notification,
+// sent by a server to tell the client about roots that became available.
+type RootsAddedNotificationParams struct {
+	Roots []Root `json:"roots"`
+}
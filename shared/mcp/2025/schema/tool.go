@@ -18,8 +18,8 @@ type Meta = schema2024.Meta
 // They are not guaranteed to provide a faithful description of tool behavior.
 // Clients should never make tool use decisions based on ToolAnnotations received from untrusted servers.
 type ToolAnnotations struct {
-	// A human-readable title for the tool.
-	Title string `json:"title,omitempty"`
+	// A human-readable title for the tool, displayed instead of the name when present.
+	Title *string `json:"title,omitempty"`
 	// If true, the tool does not modify its environment (Default: false).
 	ReadOnlyHint *bool `json:"readOnlyHint,omitempty"`
 	// If true, the tool may perform destructive updates (Default: true).
@@ -0,0 +1,105 @@
+package schema
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+)
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// FromStruct generates a tool input schema from the struct type T (or *T).
+//
+// Property names follow the json tag (fields tagged `json:"-"` and unexported fields are
+// skipped, embedded structs are flattened). string maps to "string", numeric kinds to
+// "number", bool to "boolean", slices and arrays to "array", and structs and maps to
+// "object", recursively. Pointer fields describe their element type. Only fields tagged
+// `schema:"required"` are listed as required, and a `description:"..."` tag sets the
+// property description. FromStruct panics if T is not a struct, like regexp.MustCompile it
+// is meant for package-level tool definitions.
+func FromStruct[T any]() *JSONSchemaProperty {
+	t := reflect.TypeOf((*T)(nil)).Elem()
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		panic(fmt.Sprintf("schema.FromStruct: %s is not a struct", t))
+	}
+	s := schemaForType(t, map[reflect.Type]bool{})
+	return &s
+}
+
+// schemaForType builds the schema of t. visiting holds the struct types being expanded so
+// recursive types end in a plain object instead of looping.
+func schemaForType(t reflect.Type, visiting map[reflect.Type]bool) JSONSchemaProperty {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t == timeType {
+		return JSONSchemaProperty{Type: "string", Format: "date-time"}
+	}
+	switch t.Kind() {
+	case reflect.String:
+		return JSONSchemaProperty{Type: "string"}
+	case reflect.Bool:
+		return JSONSchemaProperty{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return JSONSchemaProperty{Type: "number"}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return JSONSchemaProperty{Type: "string"} // encoding/json sends []byte as base64
+		}
+		items := schemaForType(t.Elem(), visiting)
+		return JSONSchemaProperty{Type: "array", Items: &items}
+	case reflect.Map:
+		return JSONSchemaProperty{Type: "object"}
+	case reflect.Struct:
+		if visiting[t] {
+			return JSONSchemaProperty{Type: "object"}
+		}
+		visiting[t] = true
+		defer delete(visiting, t)
+		s := JSONSchemaProperty{Type: "object", Properties: map[string]JSONSchemaProperty{}}
+		addStructFields(&s, t, visiting)
+		return s
+	default:
+		return JSONSchemaProperty{} // interface{} and other kinds accept any value
+	}
+}
+
+// addStructFields adds the properties of struct type t to s.
+func addStructFields(s *JSONSchemaProperty, t reflect.Type, visiting map[reflect.Type]bool) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		jsonTag := field.Tag.Get("json")
+		if jsonTag == "-" {
+			continue
+		}
+		name, _, _ := strings.Cut(jsonTag, ",")
+		if field.Anonymous && name == "" {
+			embedded := field.Type
+			if embedded.Kind() == reflect.Pointer {
+				embedded = embedded.Elem()
+			}
+			if embedded.Kind() == reflect.Struct {
+				addStructFields(s, embedded, visiting)
+				continue
+			}
+		}
+		if !field.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		property := schemaForType(field.Type, visiting)
+		property.Description = field.Tag.Get("description")
+		s.Properties[name] = property
+		if field.Tag.Get("schema") == "required" {
+			s.Required = append(s.Required, name)
+		}
+	}
+}
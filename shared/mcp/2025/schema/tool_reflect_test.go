@@ -0,0 +1,104 @@
+package schema
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestFromStructMatchesHandWrittenSchemas(t *testing.T) {
+	type echoArgs struct {
+		Message string `json:"message" schema:"required" description:"The message to echo back"`
+	}
+	type addArgs struct {
+		A float64 `json:"a" schema:"required" description:"First number to add"`
+		B int     `json:"b" schema:"required" description:"Second number to add"`
+	}
+
+	cases := []struct {
+		name string
+		got  *JSONSchemaProperty
+		want *JSONSchemaProperty
+	}{
+		{"echo", FromStruct[echoArgs](), &JSONSchemaProperty{
+			Type: "object",
+			Properties: map[string]JSONSchemaProperty{
+				"message": {Type: "string", Description: "The message to echo back"},
+			},
+			Required: []string{"message"},
+		}},
+		{"add", FromStruct[*addArgs](), &JSONSchemaProperty{
+			Type: "object",
+			Properties: map[string]JSONSchemaProperty{
+				"a": {Type: "number", Description: "First number to add"},
+				"b": {Type: "number", Description: "Second number to add"},
+			},
+			Required: []string{"a", "b"},
+		}},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if !reflect.DeepEqual(tc.got, tc.want) {
+				t.Errorf("Generated schema mismatch\ngot:  %+v\nwant: %+v", tc.got, tc.want)
+			}
+		})
+	}
+}
+
+func TestFromStructFieldKinds(t *testing.T) {
+	type Base struct {
+		ID string `json:"id" schema:"required"`
+	}
+	type node struct {
+		Base
+		Enabled  bool              `json:"enabled"`
+		Limit    *int              `json:"limit,omitempty"`
+		Tags     []string          `json:"tags"`
+		Labels   map[string]string `json:"labels"`
+		Payload  []byte            `json:"payload"`
+		Created  time.Time         `json:"created"`
+		Children []node            `json:"children"`
+		Owner    *struct {
+			Name string `json:"name" schema:"required"`
+		} `json:"owner"`
+		Any      interface{} `json:"any"`
+		Internal string      `json:"-"`
+		Untagged string
+		hidden   string
+	}
+
+	got := FromStruct[node]()
+	want := &JSONSchemaProperty{
+		Type: "object",
+		Properties: map[string]JSONSchemaProperty{
+			"id":       {Type: "string"},
+			"enabled":  {Type: "boolean"},
+			"limit":    {Type: "number"},
+			"tags":     {Type: "array", Items: &JSONSchemaProperty{Type: "string"}},
+			"labels":   {Type: "object"},
+			"payload":  {Type: "string"},
+			"created":  {Type: "string", Format: "date-time"},
+			"children": {Type: "array", Items: &JSONSchemaProperty{Type: "object"}},
+			"owner": {
+				Type:       "object",
+				Properties: map[string]JSONSchemaProperty{"name": {Type: "string"}},
+				Required:   []string{"name"},
+			},
+			"any":      {},
+			"Untagged": {Type: "string"},
+		},
+		Required: []string{"id"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Generated schema mismatch\ngot:  %+v\nwant: %+v", got, want)
+	}
+}
+
+func TestFromStructPanicsOnNonStruct(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Errorf("Expected FromStruct[string] to panic")
+		}
+	}()
+	FromStruct[string]()
+}
@@ -1,8 +1,11 @@
 package shared
 
 import (
+	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 	"time"
 
 	"github.com/gate4ai/gate4ai/shared/mcp/2025/schema"
@@ -16,8 +19,57 @@ type Message struct {
 	Result    *json.RawMessage  `json:"result,omitempty"`
 	Error     *JSONRPCError     `json:"error,omitempty"`
 
+	// Stream writes the result JSON when the handler returned a StreamingHandler; Result is nil then.
+	Stream StreamingHandler `json:"-"`
+
+	// SSEEvent names the SSE event used when this message is streamed (empty for unnamed "data:" events).
+	SSEEvent string `json:"-"`
+	// SSEEventID is the SSE event ID used when this message is streamed (0 lets the transport number events).
+	SSEEventID uint64 `json:"-"`
+
 	Processed bool     `json:"-"`
 	Session   ISession `json:"-"` // Will be either client.Session or mcp.Session
+
+	// Headers are added to the HTTP request that posts this message to a server (client sessions only).
+	Headers map[string]string `json:"-"`
+
+	// ctx is the context of the transport request that delivered the message (see Context).
+	ctx context.Context
+}
+
+// Context returns the context of the transport request that delivered the message, or
+// context.Background() if none was set. Handlers derive their own contexts from it.
+func (m *Message) Context() context.Context {
+	if m.ctx == nil {
+		return context.Background()
+	}
+	return m.ctx
+}
+
+// SetContext sets the context returned by Context. Transports call it before queuing the message.
+func (m *Message) SetContext(ctx context.Context) {
+	m.ctx = ctx
+}
+
+// StreamingHandler is implemented by handler results too large to buffer. Transports that can
+// stream write the result JSON straight to the connection, others buffer it (see BufferStream).
+type StreamingHandler interface {
+	StreamResult(w io.Writer) error
+}
+
+// BufferStream writes a streamed result into Result, or into Error if streaming fails.
+func (m *Message) BufferStream() {
+	if m.Stream == nil {
+		return
+	}
+	var buf bytes.Buffer
+	if err := m.Stream.StreamResult(&buf); err != nil {
+		m.Error = &JSONRPCError{Code: JSONRPCErrorInternal, Message: fmt.Sprintf("Failed to stream result: %v", err)}
+	} else {
+		raw := json.RawMessage(buf.Bytes())
+		m.Result = &raw
+	}
+	m.Stream = nil
 }
 
 func ParseMessages(s ISession, data []byte) ([]*Message, error) {
@@ -41,8 +93,54 @@ func ParseMessages(s ISession, data []byte) ([]*Message, error) {
 	return []*Message{&singleMessage}, nil
 }
 
+// IsBatch reports whether data is a JSON-RPC batch, i.e. a JSON array.
+func IsBatch(data []byte) bool {
+	trimmed := bytes.TrimLeft(data, " \t\r\n")
+	return len(trimmed) > 0 && trimmed[0] == '['
+}
+
+// ParseBatchItems parses the items of a JSON-RPC batch one by one, so an invalid item does not
+// reject the whole batch. It returns the messages of the valid items and an Invalid Request
+// error response for each other item, with the item's ID if it has one. It fails only when data
+// is not a JSON array.
+func ParseBatchItems(s ISession, data []byte) ([]*Message, []JSONRPCErrorResponse, error) {
+	var items []json.RawMessage
+	if err := json.Unmarshal(data, &items); err != nil {
+		return nil, nil, fmt.Errorf("invalid JSON-RPC batch: %w", err)
+	}
+	messages := make([]*Message, 0, len(items))
+	var invalid []JSONRPCErrorResponse
+	for _, item := range items {
+		var msg Message
+		trimmed := bytes.TrimSpace(item)
+		err := fmt.Errorf("batch item is not an object")
+		if len(trimmed) > 0 && trimmed[0] == '{' {
+			err = json.Unmarshal(trimmed, &msg)
+		}
+		if err == nil && msg.Method == nil && msg.Result == nil && msg.Error == nil {
+			err = fmt.Errorf("batch item is neither a request, a notification nor a response")
+		}
+		if err != nil {
+			var withID struct {
+				ID *schema.RequestID `json:"id"`
+			}
+			json.Unmarshal(trimmed, &withID) // Best effort, the ID stays nil otherwise
+			invalid = append(invalid, JSONRPCErrorResponse{
+				JSONRPC: JSONRPCVersion,
+				ID:      withID.ID,
+				Error:   &JSONRPCError{Code: JSONRPCErrorInvalidRequest, Message: "Invalid Request", Data: err.Error()},
+			})
+			continue
+		}
+		msg.Session = s
+		messages = append(messages, &msg)
+	}
+	return messages, invalid, nil
+}
+
 // MarshalJSON ensures the JSONRPC field is properly set before marshaling
 func (m *Message) MarshalJSON() ([]byte, error) {
+	m.BufferStream()
 	if m.Error != nil {
 		response := JSONRPCErrorResponse{
 			JSONRPC: "2.0",
@@ -0,0 +1,117 @@
+package shared
+
+import (
+	"math"
+	"sync"
+	"time"
+)
+
+// RateLimiter decides whether Input processes a request or notification. Messages it refuses
+// are answered with JSONRPCErrorServerError "Rate limit exceeded" before any handler runs.
+type RateLimiter interface {
+	Allow(sessionID, userID, method string) bool
+}
+
+// UserSession is implemented by sessions that know the user they belong to. Input passes
+// an empty user ID to the RateLimiter for other sessions.
+type UserSession interface {
+	GetUserID() string
+}
+
+var _ RateLimiter = (*TokenBucketRateLimiter)(nil)
+
+// TokenBucketRateLimiter limits the messages of each session and of each user with token
+// buckets. A bucket holds up to one second of requests (at least one), so short bursts up to
+// the rate pass and longer ones are refused.
+type TokenBucketRateLimiter struct {
+	perSessionRPS float64
+	perUserRPS    float64
+	now           func() time.Time // Replaced in tests
+
+	mu        sync.Mutex
+	sessions  map[string]*tokenBucket
+	users     map[string]*tokenBucket
+	lastPrune time.Time
+}
+
+// NewTokenBucketRateLimiter creates a limiter allowing perSessionRPS messages per second to
+// each session and perUserRPS messages per second to all sessions of a user together.
+// A rate <= 0 disables that limit; sessions without a user only get the session limit.
+func NewTokenBucketRateLimiter(perSessionRPS, perUserRPS float64) *TokenBucketRateLimiter {
+	return &TokenBucketRateLimiter{
+		perSessionRPS: perSessionRPS,
+		perUserRPS:    perUserRPS,
+		now:           time.Now,
+		sessions:      make(map[string]*tokenBucket),
+		users:         make(map[string]*tokenBucket),
+	}
+}
+
+// Allow takes a token from the session's bucket and the user's bucket. A message refused
+// by one bucket takes no token from the other.
+func (l *TokenBucketRateLimiter) Allow(sessionID, userID, method string) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	now := l.now()
+	l.prune(now)
+
+	var session, user *tokenBucket
+	if l.perSessionRPS > 0 {
+		session = bucketFor(l.sessions, sessionID, l.perSessionRPS, now)
+		session.refill(now)
+	}
+	if l.perUserRPS > 0 && userID != "" {
+		user = bucketFor(l.users, userID, l.perUserRPS, now)
+		user.refill(now)
+	}
+	if (session != nil && session.tokens < 1) || (user != nil && user.tokens < 1) {
+		return false
+	}
+	if session != nil {
+		session.tokens--
+	}
+	if user != nil {
+		user.tokens--
+	}
+	return true
+}
+
+// prune drops, at most once a minute, the buckets that have refilled completely: they
+// behave like new ones, and ended sessions would otherwise keep theirs forever.
+func (l *TokenBucketRateLimiter) prune(now time.Time) {
+	if now.Sub(l.lastPrune) < time.Minute {
+		return
+	}
+	l.lastPrune = now
+	for _, buckets := range []map[string]*tokenBucket{l.sessions, l.users} {
+		for key, bucket := range buckets {
+			if bucket.refill(now); bucket.tokens >= bucket.burst {
+				delete(buckets, key)
+			}
+		}
+	}
+}
+
+func bucketFor(buckets map[string]*tokenBucket, key string, rps float64, now time.Time) *tokenBucket {
+	bucket, ok := buckets[key]
+	if !ok {
+		burst := math.Max(1, math.Ceil(rps))
+		bucket = &tokenBucket{rps: rps, burst: burst, tokens: burst, updated: now}
+		buckets[key] = bucket
+	}
+	return bucket
+}
+
+type tokenBucket struct {
+	rps     float64
+	burst   float64
+	tokens  float64
+	updated time.Time
+}
+
+func (b *tokenBucket) refill(now time.Time) {
+	if elapsed := now.Sub(b.updated).Seconds(); elapsed > 0 {
+		b.tokens = math.Min(b.burst, b.tokens+elapsed*b.rps)
+		b.updated = now
+	}
+}
@@ -0,0 +1,118 @@
+package shared
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gate4ai/gate4ai/shared/mcp/2025/schema"
+	"go.uber.org/zap"
+)
+
+func newTestRateLimiter(perSessionRPS, perUserRPS float64) (*TokenBucketRateLimiter, *time.Time) {
+	now := time.Now()
+	l := NewTokenBucketRateLimiter(perSessionRPS, perUserRPS)
+	l.now = func() time.Time { return now }
+	return l, &now
+}
+
+func TestTokenBucketRateLimiterPerSession(t *testing.T) {
+	l, now := newTestRateLimiter(2, 0)
+
+	// A burst up to the rate passes, the rest is refused
+	for i := 0; i < 2; i++ {
+		if !l.Allow("s1", "", "tools/call") {
+			t.Fatalf("Request %d within the limit refused", i+1)
+		}
+	}
+	if l.Allow("s1", "", "tools/call") {
+		t.Fatalf("Request above the limit allowed")
+	}
+	if !l.Allow("s2", "", "tools/call") {
+		t.Fatalf("Another session was limited")
+	}
+
+	// Tokens come back at the configured rate
+	*now = now.Add(500 * time.Millisecond)
+	if !l.Allow("s1", "", "tools/call") {
+		t.Fatalf("Request refused after a token was refilled")
+	}
+	if l.Allow("s1", "", "tools/call") {
+		t.Fatalf("Request allowed before the next token was refilled")
+	}
+}
+
+func TestTokenBucketRateLimiterPerUser(t *testing.T) {
+	l, _ := newTestRateLimiter(10, 3)
+
+	// Sessions of the same user share the user bucket
+	for _, sessionID := range []string{"s1", "s2", "s1"} {
+		if !l.Allow(sessionID, "alice", "ping") {
+			t.Fatalf("Request of session %s within the user limit refused", sessionID)
+		}
+	}
+	if l.Allow("s3", "alice", "ping") {
+		t.Fatalf("Request above the user limit allowed")
+	}
+	if !l.Allow("s4", "bob", "ping") {
+		t.Fatalf("Another user was limited")
+	}
+	// Sessions without a user only have the session limit
+	for i := 0; i < 10; i++ {
+		if !l.Allow("anonymous", "", "ping") {
+			t.Fatalf("Anonymous request %d refused", i+1)
+		}
+	}
+}
+
+func TestInputRejectsRateLimitedRequests(t *testing.T) {
+	logger := zap.NewNop()
+	input := NewInput(logger)
+	calls := make(chan struct{}, 10)
+	input.addCapability(&countingCapability{handlers: map[string]func(*Message) (interface{}, error){
+		"ping": func(*Message) (interface{}, error) {
+			calls <- struct{}{}
+			return map[string]interface{}{}, nil
+		},
+	}})
+	input.SetRateLimiter(NewTokenBucketRateLimiter(2, 0))
+	go input.Process()
+
+	session := NewBaseSession(logger, "limited", input, nil)
+	session.SetStatus(StatusConnected)
+	output, ok := session.AcquireOutput()
+	if !ok {
+		t.Fatalf("Failed to acquire session output")
+	}
+	defer session.ReleaseOutput()
+
+	method := "ping"
+	for n := uint64(1); n <= 3; n++ {
+		id := schema.RequestID_FromUInt64(n)
+		if err := input.Put(&Message{ID: &id, Method: &method, Session: session}); err != nil {
+			t.Fatalf("Failed to put request %d: %v", n, err)
+		}
+	}
+
+	succeeded, limited := 0, 0
+	for i := 0; i < 3; i++ {
+		select {
+		case response := <-output:
+			switch {
+			case response.Error == nil:
+				succeeded++
+			case response.Error.Code == JSONRPCErrorServerError && response.Error.Message == "Rate limit exceeded":
+				limited++
+			default:
+				t.Fatalf("Unexpected error response %+v", response.Error)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("Timed out waiting for response %d", i+1)
+		}
+	}
+	if succeeded != 2 || limited != 1 {
+		t.Errorf("Expected 2 successful and 1 limited responses, got %d and %d", succeeded, limited)
+	}
+	if len(calls) != 2 {
+		t.Errorf("Expected the handler to run twice, got %d", len(calls))
+	}
+}
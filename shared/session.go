@@ -4,11 +4,13 @@ import (
 	"crypto/rand"
 	"encoding/base64"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"sync"
 	"sync/atomic"
 	"time"
 
+	a2aSchema "github.com/gate4ai/gate4ai/shared/a2a/2025-draft/schema"
 	"github.com/gate4ai/gate4ai/shared/mcp/2025/schema"
 	"go.uber.org/zap"
 )
@@ -23,6 +25,21 @@ const (
 	StatusDisconnected
 )
 
+func (s SessionStatus) String() string {
+	switch s {
+	case StatusNew:
+		return "new"
+	case StatusConnecting:
+		return "connecting"
+	case StatusConnected:
+		return "connected"
+	case StatusDisconnected:
+		return "disconnected"
+	default:
+		return fmt.Sprintf("unknown(%d)", int(s))
+	}
+}
+
 type ISession interface {
 	GetID() string
 
@@ -39,14 +56,20 @@ type ISession interface {
 	SetNegotiatedVersion(version string)
 	GetNegotiatedVersion() string
 
+	GetCreatedAt() time.Time
 	GetLastActivity() time.Time
 	UpdateLastActivity()
+	// GetLastInboundActivity is when the session last received a message from its peer
+	GetLastInboundActivity() time.Time
+	// UpdateLastInboundActivity records a message received from the peer, which is activity too
+	UpdateLastInboundActivity()
 
 	GetStatus() SessionStatus
 	SetStatus(status SessionStatus)
 	Close() error
 	GetRequestManager() *RequestManager
 	NextMessageID() schema.RequestID
+	SetRequestIDGenerator(gen RequestIDGenerator)
 	GetParamsMutex() *sync.RWMutex
 	GetParams() *sync.Map
 	GetLogger() *zap.Logger
@@ -61,6 +84,7 @@ type BaseSession struct {
 	messageID         uint64
 	CreatedAt         time.Time
 	LastActivity      atomic.Value
+	lastInbound       atomic.Value
 	status            SessionStatus
 	ParamsMutex       sync.RWMutex
 	Params            *sync.Map
@@ -70,6 +94,34 @@ type BaseSession struct {
 	Logger            *zap.Logger
 	negotiatedVersion string
 	inputProcessor    *Input
+	// Produces IDs of requests sent by this session; nil means incrementing uint64
+	requestIDGenerator RequestIDGenerator
+}
+
+// RequestIDGenerator returns the ID for the next request a session sends.
+// IDs must be unique among the session's pending requests.
+type RequestIDGenerator func() interface{}
+
+// SequentialRequestIDGenerator returns incrementing uint64 IDs starting at 1.
+func SequentialRequestIDGenerator() RequestIDGenerator {
+	var counter uint64
+	return func() interface{} {
+		return atomic.AddUint64(&counter, 1)
+	}
+}
+
+// UUIDRequestIDGenerator returns random (version 4) UUID strings, which stay unique
+// across sessions and processes and can be used for tracing and idempotency.
+func UUIDRequestIDGenerator() RequestIDGenerator {
+	return func() interface{} {
+		b := make([]byte, 16)
+		if _, err := rand.Read(b); err != nil {
+			panic(err)
+		}
+		b[6] = (b[6] & 0x0f) | 0x40 // Version 4
+		b[8] = (b[8] & 0x3f) | 0x80 // RFC 4122 variant
+		return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:])
+	}
 }
 
 // NewBaseSession creates a new base session with default values
@@ -95,7 +147,7 @@ func NewBaseSession(logger *zap.Logger, id string, inputProcessor *Input, params
 		output:         make(chan *Message, 100), // TODO: Make configurable
 		inputProcessor: inputProcessor,
 	}
-	s.UpdateLastActivity()
+	s.UpdateLastInboundActivity()
 	return s
 }
 
@@ -109,9 +161,22 @@ func RandomID() string {
 }
 
 func (s *BaseSession) NextMessageID() schema.RequestID {
+	s.Mu.RLock()
+	gen := s.requestIDGenerator
+	s.Mu.RUnlock()
+	if gen != nil {
+		return schema.RequestID{Value: gen()}
+	}
 	return schema.RequestID_FromUInt64(atomic.AddUint64(&s.messageID, 1))
 }
 
+// SetRequestIDGenerator replaces how IDs of requests sent by this session are generated.
+func (s *BaseSession) SetRequestIDGenerator(gen RequestIDGenerator) {
+	s.Mu.Lock()
+	defer s.Mu.Unlock()
+	s.requestIDGenerator = gen
+}
+
 // GetID returns the unique session identifier
 func (s *BaseSession) GetID() string {
 	return s.ID
@@ -144,10 +209,24 @@ func (s *BaseSession) UpdateLastActivity() {
 	s.LastActivity.Store(time.Now())
 }
 
+func (s *BaseSession) GetCreatedAt() time.Time {
+	return s.CreatedAt
+}
+
 func (s *BaseSession) GetLastActivity() time.Time {
 	return s.LastActivity.Load().(time.Time)
 }
 
+func (s *BaseSession) UpdateLastInboundActivity() {
+	now := time.Now()
+	s.lastInbound.Store(now)
+	s.LastActivity.Store(now)
+}
+
+func (s *BaseSession) GetLastInboundActivity() time.Time {
+	return s.lastInbound.Load().(time.Time)
+}
+
 // GetRequestManager returns the request manager for this session
 func (s *BaseSession) GetRequestManager() *RequestManager {
 	s.Mu.Lock()
@@ -228,6 +307,12 @@ func (s *BaseSession) SendNotification(method string, params map[string]any) {
 
 // SendRequest sends a request and waits for a response
 func (s *BaseSession) SendRequest(method string, params interface{}, callback RequestCallback) (*schema.RequestID, error) {
+	return s.SendRequestWithHeaders(method, params, nil, callback)
+}
+
+// SendRequestWithHeaders is SendRequest with HTTP headers for the request that posts the
+// message (see Message.Headers).
+func (s *BaseSession) SendRequestWithHeaders(method string, params interface{}, headers map[string]string, callback RequestCallback) (*schema.RequestID, error) {
 	if s.GetStatus() != StatusConnected && method != "initialize" {
 		s.Logger.Warn("Request sent to not connected session",
 			zap.String("method", method),
@@ -252,6 +337,7 @@ func (s *BaseSession) SendRequest(method string, params interface{}, callback Re
 		Session:   s,
 		Params:    jsonParams,
 		Timestamp: time.Now(),
+		Headers:   headers,
 	}
 
 	s.RequestManager.RegisterRequest(&msgID, callback)
@@ -308,6 +394,7 @@ func (s *BaseSession) SendResponse(msgId *schema.RequestID, result interface{},
 
 	var jsonResult *json.RawMessage
 	var jsonRpcError *JSONRPCError // Use the concrete struct pointer type
+	var streamResult StreamingHandler
 
 	if err != nil {
 		// Convert Go error to JSONRPCError structure
@@ -321,6 +408,9 @@ func (s *BaseSession) SendResponse(msgId *schema.RequestID, result interface{},
 		}
 		jsonResult = nil // Ensure result is nil when sending an error
 		result = nil     // Ensure original result interface is nil too
+	} else if stream, ok := result.(StreamingHandler); ok {
+		// Left to the transport, which writes it to the connection or buffers it
+		streamResult = stream
 	} else if result != nil {
 		// Marshal the successful result
 		data, marshalErr := json.Marshal(result)
@@ -343,6 +433,7 @@ func (s *BaseSession) SendResponse(msgId *schema.RequestID, result interface{},
 		Timestamp: time.Now(),
 		ID:        msgId,
 		Result:    jsonResult,
+		Stream:    streamResult,
 		// Assign the *JSONRPCError (which implements error) to the error interface field
 		Error: jsonRpcError,
 	}
@@ -402,14 +493,18 @@ func (s *BaseSession) SendA2AStreamEvent(event *A2AStreamEvent) error {
 	// For SSE, we often send just the data payload of the event.
 	// Let's marshal the specific event type (Status or Artifact) as the result.
 	var payloadToMarshal interface{}
+	sseEvent := ""
 	if event.Status != nil {
 		payloadToMarshal = event.Status
 	} else if event.Artifact != nil {
 		payloadToMarshal = event.Artifact
+	} else if event.History != nil {
+		payloadToMarshal = event.History
+		sseEvent = A2ASSEEventHistory
 	} else if event.Error != nil {
-		return s.sendErrorToOutput(nil, &JSONRPCError{Code: JSONRPCErrorInternal, Message: event.Error.Error()})
+		return s.sendErrorToOutput(nil, streamEventError(event))
 	} else {
-		return fmt.Errorf("A2AStreamEvent has no content (Status, Artifact, or Error)")
+		return fmt.Errorf("A2AStreamEvent has no content (Status, Artifact, History, or Error)")
 	}
 
 	jsonData, err := json.Marshal(payloadToMarshal)
@@ -417,7 +512,33 @@ func (s *BaseSession) SendA2AStreamEvent(event *A2AStreamEvent) error {
 		return fmt.Errorf("failed to marshal A2A event payload: %w", err)
 	}
 	rawResult := json.RawMessage(jsonData)
-	return s.sendResultToOutput(nil, &rawResult) // ID is nil for stream events
+	return s.sendMessageToOutput(&Message{ // ID is nil for stream events
+		Session:    s,
+		Timestamp:  time.Now(),
+		Result:     &rawResult,
+		SSEEvent:   sseEvent,
+		SSEEventID: event.ID,
+	})
+}
+
+// streamEventError converts the error of an A2A stream event into the JSON-RPC error sent to the client,
+// keeping the code of A2A and JSON-RPC errors.
+func streamEventError(event *A2AStreamEvent) *JSONRPCError {
+	rpcErr := &JSONRPCError{Code: JSONRPCErrorInternal, Message: event.Error.Error()}
+	var a2aErr *a2aSchema.JSONRPCError
+	var sharedErr *JSONRPCError
+	if errors.As(event.Error, &a2aErr) {
+		rpcErr.Code, rpcErr.Message = a2aErr.Code, a2aErr.Message
+		if a2aErr.Data != nil {
+			rpcErr.Data = *a2aErr.Data
+		}
+	} else if errors.As(event.Error, &sharedErr) {
+		copied := *sharedErr
+		rpcErr = &copied
+	}
+	rpcErr.Category = event.ErrorCategory
+	rpcErr.RetryAfterSeconds = event.RetryAfterSeconds
+	return rpcErr
 }
 
 // Helper to reduce duplication in sending messages
@@ -0,0 +1,117 @@
+//go:build !production
+
+// Package sharedtesting provides helpers for testing MCP and A2A capabilities:
+// an in-memory session, a message builder and JSON-RPC result assertions.
+package sharedtesting
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/gate4ai/gate4ai/shared"
+	a2aSchema "github.com/gate4ai/gate4ai/shared/a2a/2025-draft/schema"
+	"github.com/gate4ai/gate4ai/shared/mcp/2025/schema"
+	"go.uber.org/zap"
+)
+
+// MockSession is a connected in-memory session. Whatever a capability sends to the
+// client (responses, notifications, requests, A2A events) is queued and can be read with Sent.
+type MockSession struct {
+	*shared.BaseSession
+}
+
+var _ shared.ISession = (*MockSession)(nil)
+
+// NewMockSession creates a connected session with the given ID and empty params.
+func NewMockSession(id string) *MockSession {
+	logger := zap.NewNop()
+	session := &MockSession{BaseSession: shared.NewBaseSession(logger, id, shared.NewInput(logger), nil)}
+	session.SetStatus(shared.StatusConnected)
+	return session
+}
+
+// Sent returns the messages queued for the client since the last call, without blocking.
+func (s *MockSession) Sent() []*shared.Message {
+	output, ok := s.AcquireOutput()
+	if !ok {
+		return nil
+	}
+	defer s.ReleaseOutput()
+	var sent []*shared.Message
+	for {
+		select {
+		case msg := <-output:
+			sent = append(sent, msg)
+		default:
+			return sent
+		}
+	}
+}
+
+// BuildMessage builds a request for method with params marshaled to JSON, attached to a
+// new MockSession. Replace Session when the handler needs a specific one. A params value
+// of type json.RawMessage is used as is; params that cannot be marshaled cause a panic.
+func BuildMessage(method string, params interface{}) *shared.Message {
+	id := schema.RequestID_FromUInt64(1)
+	msg := &shared.Message{ID: &id, Method: &method, Session: NewMockSession("test-session")}
+	if params == nil {
+		return msg
+	}
+	raw, ok := params.(json.RawMessage)
+	if !ok {
+		data, err := json.Marshal(params)
+		if err != nil {
+			panic("sharedtesting.BuildMessage: cannot marshal params: " + err.Error())
+		}
+		raw = data
+	}
+	msg.Params = &raw
+	return msg
+}
+
+// AssertJSONRPCError fails t unless err is a JSON-RPC error (shared or A2A) with expectedCode.
+func AssertJSONRPCError(t testing.TB, result interface{}, err error, expectedCode int) {
+	t.Helper()
+	if err == nil {
+		t.Fatalf("Expected JSON-RPC error %d, got result %+v", expectedCode, result)
+		return
+	}
+	var code int
+	var sharedErr *shared.JSONRPCError
+	var a2aErr *a2aSchema.JSONRPCError
+	switch {
+	case errors.As(err, &sharedErr):
+		code = sharedErr.Code
+	case errors.As(err, &a2aErr):
+		code = a2aErr.Code
+	default:
+		t.Fatalf("Expected JSON-RPC error %d, got %T: %v", expectedCode, err, err)
+		return
+	}
+	if code != expectedCode {
+		t.Fatalf("Expected JSON-RPC error code %d, got %d: %v", expectedCode, code, err)
+	}
+}
+
+// AssertJSONRPCSuccess fails t if err is set and returns result as T. Results of another
+// type (e.g. maps) are converted through JSON.
+func AssertJSONRPCSuccess[T any](t testing.TB, result interface{}, err error) T {
+	t.Helper()
+	var typed T
+	if err != nil {
+		t.Fatalf("Expected success, got error: %v", err)
+		return typed
+	}
+	if value, ok := result.(T); ok {
+		return value
+	}
+	data, marshalErr := json.Marshal(result)
+	if marshalErr == nil {
+		marshalErr = json.Unmarshal(data, &typed)
+	}
+	if marshalErr != nil {
+		t.Fatalf("Result %+v cannot be converted to %T: %v", result, typed, marshalErr)
+	}
+	return typed
+}
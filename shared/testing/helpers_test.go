@@ -0,0 +1,147 @@
+//go:build !production
+
+package sharedtesting
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"runtime"
+	"sync"
+	"testing"
+
+	"github.com/gate4ai/gate4ai/shared"
+	a2aSchema "github.com/gate4ai/gate4ai/shared/a2a/2025-draft/schema"
+	"github.com/gate4ai/gate4ai/shared/mcp/2025/schema"
+)
+
+// recordingTB captures failures instead of failing the real test.
+type recordingTB struct {
+	testing.TB
+	failed  bool
+	message string
+}
+
+func (r *recordingTB) Helper() {}
+
+func (r *recordingTB) Fatalf(format string, args ...interface{}) {
+	r.failed = true
+	r.message = fmt.Sprintf(format, args...)
+	runtime.Goexit()
+}
+
+// run calls assertion with a recordingTB in its own goroutine so Fatalf can stop it.
+func run(t *testing.T, assertion func(tb testing.TB)) *recordingTB {
+	tb := &recordingTB{TB: t}
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		assertion(tb)
+	}()
+	wg.Wait()
+	return tb
+}
+
+func TestMockSession(t *testing.T) {
+	session := NewMockSession("mock-1")
+	if session.GetID() != "mock-1" {
+		t.Errorf("Expected ID mock-1, got %s", session.GetID())
+	}
+	if session.GetStatus() != shared.StatusConnected {
+		t.Errorf("Expected connected session, got status %d", session.GetStatus())
+	}
+	if sent := session.Sent(); len(sent) != 0 {
+		t.Errorf("Expected nothing sent yet, got %d messages", len(sent))
+	}
+
+	session.SendNotification("notifications/test", map[string]any{"n": 1})
+	id := schema.RequestID_FromUInt64(7)
+	session.SendResponse(&id, map[string]string{"ok": "yes"}, nil)
+	sent := session.Sent()
+	if len(sent) != 2 || sent[0].Method == nil || *sent[0].Method != "notifications/test" || sent[1].ID.String() != "7" {
+		t.Fatalf("Expected the notification and the response, got %+v", sent)
+	}
+	if len(session.Sent()) != 0 {
+		t.Errorf("Expected Sent to return each message once")
+	}
+}
+
+func TestBuildMessage(t *testing.T) {
+	msg := BuildMessage("tools/call", map[string]string{"name": "echo"})
+	if msg.Method == nil || *msg.Method != "tools/call" || msg.ID.IsEmpty() || msg.Session == nil {
+		t.Fatalf("Expected a request with method, ID and session, got %+v", msg)
+	}
+	if string(*msg.Params) != `{"name":"echo"}` {
+		t.Errorf("Unexpected params %s", *msg.Params)
+	}
+
+	raw := BuildMessage("resources/read", json.RawMessage(`{"uri":"a://b"}`))
+	if string(*raw.Params) != `{"uri":"a://b"}` {
+		t.Errorf("Expected raw params to be kept, got %s", *raw.Params)
+	}
+	if BuildMessage("ping", nil).Params != nil {
+		t.Errorf("Expected no params for nil")
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Errorf("Expected panic for params that cannot be marshaled")
+		}
+	}()
+	BuildMessage("tools/call", map[string]interface{}{"bad": make(chan int)})
+}
+
+func TestAssertJSONRPCError(t *testing.T) {
+	sharedErr := &shared.JSONRPCError{Code: shared.JSONRPCErrorInvalidParams, Message: "bad"}
+	a2aErr := &a2aSchema.JSONRPCError{Code: a2aSchema.ErrorCodeTaskNotFound, Message: "missing"}
+
+	cases := []struct {
+		name       string
+		err        error
+		code       int
+		wantFailed bool
+	}{
+		{"shared error", sharedErr, shared.JSONRPCErrorInvalidParams, false},
+		{"wrapped shared error", fmt.Errorf("wrapped: %w", sharedErr), shared.JSONRPCErrorInvalidParams, false},
+		{"a2a error", a2aErr, a2aSchema.ErrorCodeTaskNotFound, false},
+		{"wrong code", sharedErr, shared.JSONRPCErrorInternal, true},
+		{"no error", nil, shared.JSONRPCErrorInternal, true},
+		{"plain error", errors.New("plain"), shared.JSONRPCErrorInternal, true},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			tb := run(t, func(tb testing.TB) { AssertJSONRPCError(tb, nil, tc.err, tc.code) })
+			if tb.failed != tc.wantFailed {
+				t.Errorf("Expected failed=%v, got %v (%s)", tc.wantFailed, tb.failed, tb.message)
+			}
+		})
+	}
+}
+
+func TestAssertJSONRPCSuccess(t *testing.T) {
+	direct := schema.ListToolsResult{Tools: []schema.Tool{{Name: "echo"}}}
+	var got schema.ListToolsResult
+	tb := run(t, func(tb testing.TB) { got = AssertJSONRPCSuccess[schema.ListToolsResult](tb, direct, nil) })
+	if tb.failed || len(got.Tools) != 1 || got.Tools[0].Name != "echo" {
+		t.Errorf("Expected the result back unchanged, got %+v (%s)", got, tb.message)
+	}
+
+	var converted map[string]int
+	tb = run(t, func(tb testing.TB) {
+		converted = AssertJSONRPCSuccess[map[string]int](tb, struct{ A int }{A: 1}, nil)
+	})
+	if tb.failed || converted["A"] != 1 {
+		t.Errorf("Expected the result converted through JSON, got %v (%s)", converted, tb.message)
+	}
+
+	tb = run(t, func(tb testing.TB) { AssertJSONRPCSuccess[schema.ListToolsResult](tb, nil, errors.New("boom")) })
+	if !tb.failed {
+		t.Errorf("Expected failure for an error")
+	}
+
+	tb = run(t, func(tb testing.TB) { AssertJSONRPCSuccess[int](tb, "not a number", nil) })
+	if !tb.failed {
+		t.Errorf("Expected failure for a result that cannot be converted")
+	}
+}
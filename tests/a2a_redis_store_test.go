@@ -0,0 +1,153 @@
+package tests
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gate4ai/gate4ai/server/a2a"
+	"github.com/gate4ai/gate4ai/shared"
+	a2aSchema "github.com/gate4ai/gate4ai/shared/a2a/2025-draft/schema"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/testcontainers/testcontainers-go"
+	"github.com/testcontainers/testcontainers-go/wait"
+)
+
+// startRedis starts a Redis container for the test and returns a client connected to it.
+func startRedis(tb testing.TB) *redis.Client {
+	tb.Helper()
+	ctx := context.Background()
+	container, err := testcontainers.GenericContainer(ctx, testcontainers.GenericContainerRequest{
+		ContainerRequest: testcontainers.ContainerRequest{
+			Image:        "redis:7-alpine",
+			ExposedPorts: []string{"6379/tcp"},
+			WaitingFor:   wait.ForListeningPort("6379/tcp").WithStartupTimeout(60 * time.Second),
+		},
+		Started: true,
+	})
+	require.NoError(tb, err, "Failed to start redis container")
+	tb.Cleanup(func() { _ = container.Terminate(context.Background()) })
+
+	host, err := container.Host(ctx)
+	require.NoError(tb, err)
+	port, err := container.MappedPort(ctx, "6379/tcp")
+	require.NoError(tb, err)
+	client := redis.NewClient(&redis.Options{Addr: fmt.Sprintf("%s:%s", host, port.Port())})
+	tb.Cleanup(func() { client.Close() })
+	return client
+}
+
+func redisTestTask(id string) *a2aSchema.Task {
+	return &a2aSchema.Task{
+		ID:        id,
+		SessionID: "redis-session",
+		Status:    a2aSchema.TaskStatus{State: a2aSchema.TaskStateWorking, Timestamp: time.Now().UTC().Truncate(time.Millisecond)},
+		History:   []a2aSchema.Message{{Role: "user", Parts: []a2aSchema.Part{{Type: shared.PointerTo("text"), Text: shared.PointerTo("hello")}}}},
+	}
+}
+
+func TestA2ARedisTaskStore(t *testing.T) {
+	ctx := context.Background()
+	client := startRedis(t)
+	// Two stores on one Redis stand for two gateway instances
+	first := a2a.NewRedisTaskStore(client, a2a.WithRedisKeyPrefix("it:"))
+	second := a2a.NewRedisTaskStore(client, a2a.WithRedisKeyPrefix("it:"))
+
+	t.Run("shared between instances", func(t *testing.T) {
+		task := redisTestTask("shared")
+		require.NoError(t, first.Save(ctx, task))
+		loaded, err := second.Load(ctx, task.ID)
+		require.NoError(t, err)
+		assert.Equal(t, task, loaded)
+	})
+
+	t.Run("not found and delete", func(t *testing.T) {
+		require.NoError(t, first.Save(ctx, redisTestTask("deleted")))
+		require.NoError(t, second.Delete(ctx, "deleted"))
+		_, err := first.Load(ctx, "deleted")
+		var jsonErr *a2aSchema.JSONRPCError
+		require.ErrorAs(t, err, &jsonErr)
+		assert.Equal(t, a2aSchema.ErrorCodeTaskNotFound, jsonErr.Code)
+	})
+
+	t.Run("corrupt task", func(t *testing.T) {
+		require.NoError(t, client.Set(ctx, "it:task:corrupt", "{not json", 0).Err())
+		_, err := first.Load(ctx, "corrupt")
+		var jsonErr *a2aSchema.JSONRPCError
+		require.ErrorAs(t, err, &jsonErr)
+		assert.Equal(t, a2aSchema.ErrorInternalError, jsonErr.Code)
+		require.NoError(t, client.Del(ctx, "it:task:corrupt").Err())
+	})
+
+	t.Run("task TTL", func(t *testing.T) {
+		require.NoError(t, first.SaveWithTTL(ctx, redisTestTask("expiring"), time.Second))
+		_, err := second.Load(ctx, "expiring")
+		require.NoError(t, err)
+		require.Eventually(t, func() bool {
+			_, err := second.Load(ctx, "expiring")
+			return err != nil
+		}, 5*time.Second, 100*time.Millisecond)
+	})
+
+	t.Run("concurrent saves", func(t *testing.T) {
+		var wg sync.WaitGroup
+		for i := 0; i < 20; i++ {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				store := first
+				if i%2 == 1 {
+					store = second
+				}
+				assert.NoError(t, store.Save(ctx, redisTestTask(fmt.Sprintf("concurrent-%02d", i))))
+			}(i)
+		}
+		wg.Wait()
+		tasks, _, err := first.List(ctx, a2a.TaskFilter{SessionID: "redis-session"})
+		require.NoError(t, err)
+		assert.GreaterOrEqual(t, len(tasks), 20)
+	})
+
+	t.Run("snapshot rollback", func(t *testing.T) {
+		task := redisTestTask("rollback")
+		require.NoError(t, first.Save(ctx, task))
+		version, err := first.SnapshotBeforeUpdate(ctx, task.ID)
+		require.NoError(t, err)
+		task.Status.State = a2aSchema.TaskStateFailed
+		require.NoError(t, second.Save(ctx, task))
+		require.NoError(t, second.RollbackToSnapshot(ctx, task.ID, version))
+		loaded, err := first.Load(ctx, task.ID)
+		require.NoError(t, err)
+		assert.Equal(t, a2aSchema.TaskStateWorking, loaded.Status.State)
+	})
+}
+
+// BenchmarkA2ATaskStore compares a Save and Load round trip of the in-memory and Redis stores.
+func BenchmarkA2ATaskStore(b *testing.B) {
+	stores := []struct {
+		name  string
+		store a2a.TaskStore
+	}{
+		{"in-memory", a2a.NewInMemoryTaskStore()},
+		{"redis", a2a.NewRedisTaskStore(startRedis(b), a2a.WithRedisKeyPrefix("bench:"))},
+	}
+	for _, s := range stores {
+		b.Run(s.name, func(b *testing.B) {
+			ctx := context.Background()
+			task := redisTestTask("bench")
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				if err := s.store.Save(ctx, task); err != nil {
+					b.Fatal(err)
+				}
+				if _, err := s.store.Load(ctx, task.ID); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,66 @@
+package compliance_test
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/gate4ai/gate4ai/server"
+	"github.com/gate4ai/gate4ai/server/cmd/mcp-example-server/exampleCapability"
+	"github.com/gate4ai/gate4ai/shared/config"
+	"github.com/gate4ai/gate4ai/tests"
+	"github.com/gate4ai/gate4ai/tests/compliance"
+	"go.uber.org/zap"
+)
+
+var (
+	mcpURL       = flag.String("mcp-url", "", "SSE endpoint of the MCP server under test; starts the example server when empty")
+	apiKey       = flag.String("api-key", "", "API key sent as a Bearer token")
+	samplingTool = flag.String("sampling-tool", "", "Tool that triggers sampling/createMessage; the sampling check is skipped when empty")
+)
+
+// TestCompliance runs the suite against -mcp-url, or against the example server by default:
+//
+//	go test ./compliance -run TestCompliance -mcp-url=http://localhost:4001/sse -api-key=...
+func TestCompliance(t *testing.T) {
+	suite := compliance.NewComplianceSuite(*mcpURL, *apiKey)
+	suite.SamplingTool = *samplingTool
+	if *mcpURL == "" {
+		suite.URL, suite.APIKey = startExampleServer(t)
+		suite.SamplingTool = "sampleLLM"
+		suite.SamplingArgs = map[string]interface{}{"prompt": "compliance", "maxTokens": 10}
+	}
+	suite.Run(t)
+}
+
+func startExampleServer(t *testing.T) (string, string) {
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	port, err := tests.FindAvailablePort()
+	if err != nil {
+		t.Fatalf("Failed to find available port: %v", err)
+	}
+	logger := zap.NewNop()
+	cfg := config.NewInternalConfig()
+	cfg.UserKeyHashes[config.HashAPIKey("compliance-key")] = "compliance"
+	options := append(exampleCapability.BuildOptions(logger), server.WithListenAddr(fmt.Sprintf(":%d", port)))
+	if _, err := server.Start(ctx, logger, cfg, options...); err != nil {
+		t.Fatalf("Failed to start example server: %v", err)
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		conn, err := net.DialTimeout("tcp", fmt.Sprintf("localhost:%d", port), 100*time.Millisecond)
+		if err == nil {
+			conn.Close()
+			return fmt.Sprintf("http://localhost:%d/sse", port), "compliance-key"
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+	t.Fatalf("Example server on port %d did not start in time", port)
+	return "", ""
+}
@@ -0,0 +1,524 @@
+// Package compliance checks MCP servers against behavior mandated by the specification.
+// It speaks the 2024-11-05 HTTP+SSE transport directly, so it works with any MCP server,
+// not only gate4ai's.
+package compliance
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// ProtocolVersion is the MCP revision the suite negotiates.
+const ProtocolVersion = "2024-11-05"
+
+// ComplianceSuite runs spec compliance checks against one MCP server.
+type ComplianceSuite struct {
+	// URL is the SSE endpoint of the server, e.g. http://localhost:8080/sse
+	URL string
+	// APIKey is sent as a Bearer token; leave empty for open servers
+	APIKey string
+	// SamplingTool names a tool whose call makes the server send sampling/createMessage.
+	// The sampling check is skipped when empty.
+	SamplingTool string
+	SamplingArgs map[string]interface{}
+	// Timeout bounds every single request
+	Timeout time.Duration
+}
+
+// NewComplianceSuite creates a suite for the server at url.
+func NewComplianceSuite(url string, apiKey string) *ComplianceSuite {
+	return &ComplianceSuite{URL: url, APIKey: apiKey, Timeout: 10 * time.Second}
+}
+
+// Run executes every check as a subtest of t.
+func (s *ComplianceSuite) Run(t *testing.T) {
+	t.Run("Initialize", s.testInitialize)
+	t.Run("Ping", s.testPing)
+	t.Run("ToolDiscovery", s.testToolDiscovery)
+	t.Run("ResourceRead", s.testResourceRead)
+	t.Run("PromptGet", s.testPromptGet)
+	t.Run("Sampling", s.testSampling)
+	t.Run("BatchRequest", s.testBatchRequest)
+	t.Run("NotificationGetsNoResponse", s.testNotificationGetsNoResponse)
+	t.Run("ErrorCodes", s.testErrorCodes)
+}
+
+// --- Checks ---
+
+// Spec: the initialize result MUST carry the protocol version, server info and capabilities.
+func (s *ComplianceSuite) testInitialize(t *testing.T) {
+	c := s.connect(t, nil)
+	var result struct {
+		ProtocolVersion string                 `json:"protocolVersion"`
+		Capabilities    map[string]interface{} `json:"capabilities"`
+		ServerInfo      struct {
+			Name    string `json:"name"`
+			Version string `json:"version"`
+		} `json:"serverInfo"`
+	}
+	c.decode(c.initResult, &result)
+	if result.ProtocolVersion == "" {
+		t.Errorf("initialize result has no protocolVersion")
+	}
+	if result.Capabilities == nil {
+		t.Errorf("initialize result has no capabilities object")
+	}
+	if result.ServerInfo.Name == "" {
+		t.Errorf("initialize result has no serverInfo.name")
+	}
+}
+
+// Spec: the receiver MUST respond promptly to ping with an empty result.
+func (s *ComplianceSuite) testPing(t *testing.T) {
+	c := s.connect(t, nil)
+	var result map[string]interface{}
+	c.decode(c.call("ping", nil).Result, &result)
+	if len(result) != 0 {
+		t.Errorf("ping result should be empty, got %v", result)
+	}
+}
+
+// Spec: tools/list returns tools with a unique name and an object input schema.
+func (s *ComplianceSuite) testToolDiscovery(t *testing.T) {
+	c := s.connect(t, nil)
+	c.requireCapability("tools")
+	var result struct {
+		Tools []struct {
+			Name        string `json:"name"`
+			InputSchema struct {
+				Type string `json:"type"`
+			} `json:"inputSchema"`
+		} `json:"tools"`
+	}
+	c.decode(c.call("tools/list", map[string]interface{}{}).Result, &result)
+	seen := make(map[string]bool)
+	for _, tool := range result.Tools {
+		if tool.Name == "" {
+			t.Errorf("tool without name")
+		}
+		if seen[tool.Name] {
+			t.Errorf("duplicate tool name %q", tool.Name)
+		}
+		seen[tool.Name] = true
+		if tool.InputSchema.Type != "object" {
+			t.Errorf("tool %q: inputSchema.type must be \"object\", got %q", tool.Name, tool.InputSchema.Type)
+		}
+	}
+}
+
+// Spec: resources/read returns contents with a uri and either text or blob.
+func (s *ComplianceSuite) testResourceRead(t *testing.T) {
+	c := s.connect(t, nil)
+	c.requireCapability("resources")
+	var list struct {
+		Resources []struct {
+			URI string `json:"uri"`
+		} `json:"resources"`
+	}
+	c.decode(c.call("resources/list", map[string]interface{}{}).Result, &list)
+	if len(list.Resources) == 0 {
+		t.Skip("server lists no resources")
+	}
+	uri := list.Resources[0].URI
+	var result struct {
+		Contents []struct {
+			URI  string  `json:"uri"`
+			Text *string `json:"text"`
+			Blob *string `json:"blob"`
+		} `json:"contents"`
+	}
+	c.decode(c.call("resources/read", map[string]interface{}{"uri": uri}).Result, &result)
+	if len(result.Contents) == 0 {
+		t.Fatalf("resources/read %q returned no contents", uri)
+	}
+	for _, content := range result.Contents {
+		if content.URI == "" {
+			t.Errorf("resource content without uri")
+		}
+		if (content.Text == nil) == (content.Blob == nil) {
+			t.Errorf("resource content %q must have exactly one of text or blob", content.URI)
+		}
+	}
+}
+
+// Spec: prompts/get returns messages with role user or assistant.
+func (s *ComplianceSuite) testPromptGet(t *testing.T) {
+	c := s.connect(t, nil)
+	c.requireCapability("prompts")
+	var list struct {
+		Prompts []struct {
+			Name      string `json:"name"`
+			Arguments []struct {
+				Name     string `json:"name"`
+				Required bool   `json:"required"`
+			} `json:"arguments"`
+		} `json:"prompts"`
+	}
+	c.decode(c.call("prompts/list", map[string]interface{}{}).Result, &list)
+	if len(list.Prompts) == 0 {
+		t.Skip("server lists no prompts")
+	}
+	prompt := list.Prompts[0]
+	arguments := make(map[string]string)
+	for _, argument := range prompt.Arguments {
+		if argument.Required {
+			arguments[argument.Name] = "compliance"
+		}
+	}
+	var result struct {
+		Messages []struct {
+			Role string `json:"role"`
+		} `json:"messages"`
+	}
+	c.decode(c.call("prompts/get", map[string]interface{}{"name": prompt.Name, "arguments": arguments}).Result, &result)
+	if len(result.Messages) == 0 {
+		t.Fatalf("prompts/get %q returned no messages", prompt.Name)
+	}
+	for _, message := range result.Messages {
+		if message.Role != "user" && message.Role != "assistant" {
+			t.Errorf("prompt message has invalid role %q", message.Role)
+		}
+	}
+}
+
+// Spec: a server sends sampling/createMessage to clients declaring the sampling capability
+// and uses the client's result.
+func (s *ComplianceSuite) testSampling(t *testing.T) {
+	if s.SamplingTool == "" {
+		t.Skip("no sampling tool configured")
+	}
+	var mu sync.Mutex
+	var samplingParams map[string]interface{}
+	handler := func(msg *rpcMessage) (interface{}, *rpcError) {
+		if msg.Method != "sampling/createMessage" {
+			return nil, &rpcError{Code: -32601, Message: "Method not found: " + msg.Method}
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		_ = json.Unmarshal(msg.Params, &samplingParams)
+		return map[string]interface{}{
+			"role":    "assistant",
+			"content": map[string]interface{}{"type": "text", "text": "compliance sample"},
+			"model":   "compliance-suite",
+		}, nil
+	}
+	c := s.connect(t, handler)
+	var result struct {
+		IsError bool `json:"isError"`
+	}
+	c.decode(c.call("tools/call", map[string]interface{}{"name": s.SamplingTool, "arguments": s.SamplingArgs}).Result, &result)
+	if result.IsError {
+		t.Errorf("tool %q reported an error after sampling", s.SamplingTool)
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if samplingParams == nil {
+		t.Fatalf("server did not send sampling/createMessage")
+	}
+	if messages, _ := samplingParams["messages"].([]interface{}); len(messages) == 0 {
+		t.Errorf("sampling/createMessage has no messages")
+	}
+	if _, ok := samplingParams["maxTokens"]; !ok {
+		t.Errorf("sampling/createMessage has no maxTokens")
+	}
+}
+
+// Spec (2024-11-05): implementations MUST support receiving JSON-RPC batches.
+func (s *ComplianceSuite) testBatchRequest(t *testing.T) {
+	c := s.connect(t, nil)
+	first, second := c.newID(), c.newID()
+	c.post([]interface{}{
+		map[string]interface{}{"jsonrpc": "2.0", "id": first, "method": "ping"},
+		map[string]interface{}{"jsonrpc": "2.0", "id": second, "method": "ping"},
+	})
+	for _, id := range []int{first, second} {
+		if response := c.await(id); response.Error != nil {
+			t.Errorf("batched ping %d failed: %v", id, response.Error)
+		}
+	}
+}
+
+// Spec: the receiver MUST NOT send a response to a notification.
+func (s *ComplianceSuite) testNotificationGetsNoResponse(t *testing.T) {
+	c := s.connect(t, nil)
+	c.post(map[string]interface{}{"jsonrpc": "2.0", "method": "notifications/cancelled", "params": map[string]interface{}{"requestId": "compliance-unknown", "reason": "compliance check"}})
+	id := c.newID()
+	c.post(map[string]interface{}{"jsonrpc": "2.0", "id": id, "method": "ping"})
+	c.await(id)
+	if len(c.unmatched) > 0 {
+		t.Errorf("server answered a notification: %v", c.unmatched)
+	}
+}
+
+// Spec: unknown methods fail with -32601 and invalid requests fail with an error response.
+func (s *ComplianceSuite) testErrorCodes(t *testing.T) {
+	c := s.connect(t, nil)
+	response := c.call("compliance/unknownMethod", map[string]interface{}{})
+	if response.Error == nil || response.Error.Code != -32601 {
+		t.Errorf("unknown method: expected error -32601, got %+v", response.Error)
+	}
+	if c.capabilities["tools"] != nil {
+		response = c.call("tools/call", map[string]interface{}{"name": "compliance-unknown-tool", "arguments": map[string]interface{}{}})
+		if response.Error == nil {
+			t.Errorf("calling an unknown tool must return an error")
+		}
+	}
+}
+
+// --- Connection ---
+
+type rpcError struct {
+	Code    int             `json:"code"`
+	Message string          `json:"message"`
+	Data    json.RawMessage `json:"data,omitempty"`
+}
+
+func (e *rpcError) String() string {
+	return fmt.Sprintf("%d: %s", e.Code, e.Message)
+}
+
+type rpcMessage struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id,omitempty"`
+	Method  string          `json:"method,omitempty"`
+	Params  json.RawMessage `json:"params,omitempty"`
+	Result  json.RawMessage `json:"result,omitempty"`
+	Error   *rpcError       `json:"error,omitempty"`
+}
+
+// requestHandler answers requests the server sends to the client.
+type requestHandler func(msg *rpcMessage) (interface{}, *rpcError)
+
+// conn is one initialized MCP session over the 2024-11-05 SSE transport.
+type conn struct {
+	t            *testing.T
+	suite        *ComplianceSuite
+	postURL      string
+	nextID       int
+	responses    chan *rpcMessage
+	unmatched    map[string]*rpcMessage // Responses received while awaiting another ID
+	initResult   json.RawMessage
+	capabilities map[string]interface{}
+}
+
+// connect opens the SSE stream and performs the initialize handshake.
+func (s *ComplianceSuite) connect(t *testing.T, handler requestHandler) *conn {
+	t.Helper()
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.URL, nil)
+	if err != nil {
+		t.Fatalf("create SSE request: %v", err)
+	}
+	req.Header.Set("Accept", "text/event-stream")
+	s.authorize(req)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("open SSE stream: %v", err)
+	}
+	t.Cleanup(func() { resp.Body.Close() })
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("open SSE stream: HTTP %d", resp.StatusCode)
+	}
+	if !strings.Contains(resp.Header.Get("Content-Type"), "text/event-stream") {
+		t.Fatalf("SSE stream has Content-Type %q", resp.Header.Get("Content-Type"))
+	}
+
+	c := &conn{t: t, suite: s, responses: make(chan *rpcMessage, 100), unmatched: make(map[string]*rpcMessage)}
+	endpoint := make(chan string, 1)
+	go c.readStream(bufio.NewReader(resp.Body), endpoint, handler)
+
+	select {
+	case path := <-endpoint:
+		base, _ := url.Parse(s.URL)
+		ref, err := url.Parse(path)
+		if err != nil {
+			t.Fatalf("invalid endpoint event %q: %v", path, err)
+		}
+		c.postURL = base.ResolveReference(ref).String()
+	case <-time.After(s.Timeout):
+		t.Fatalf("server sent no endpoint event")
+	}
+
+	c.initResult = c.call("initialize", map[string]interface{}{
+		"protocolVersion": ProtocolVersion,
+		"capabilities":    map[string]interface{}{"sampling": map[string]interface{}{}},
+		"clientInfo":      map[string]interface{}{"name": "mcp-compliance-suite", "version": "1.0.0"},
+	}).Result
+	var init struct {
+		Capabilities map[string]interface{} `json:"capabilities"`
+	}
+	c.decode(c.initResult, &init)
+	c.capabilities = init.Capabilities
+	c.post(map[string]interface{}{"jsonrpc": "2.0", "method": "notifications/initialized"})
+	return c
+}
+
+func (s *ComplianceSuite) authorize(req *http.Request) {
+	if s.APIKey != "" {
+		req.Header.Set("Authorization", "Bearer "+s.APIKey)
+	}
+}
+
+// readStream dispatches SSE events: responses go to c.responses, server requests to handler.
+func (c *conn) readStream(reader *bufio.Reader, endpoint chan<- string, handler requestHandler) {
+	event, data := "message", ""
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			return
+		}
+		line = strings.TrimRight(line, "\r\n")
+		switch {
+		case strings.HasPrefix(line, "event:"):
+			event = strings.TrimSpace(strings.TrimPrefix(line, "event:"))
+		case strings.HasPrefix(line, "data:"):
+			data += strings.TrimSpace(strings.TrimPrefix(line, "data:"))
+		case line == "":
+			if data != "" {
+				c.dispatch(event, data, endpoint, handler)
+			}
+			event, data = "message", ""
+		}
+	}
+}
+
+func (c *conn) dispatch(event string, data string, endpoint chan<- string, handler requestHandler) {
+	if event == "endpoint" {
+		endpoint <- data
+		return
+	}
+	if event != "message" {
+		return
+	}
+	var msg rpcMessage
+	if err := json.Unmarshal([]byte(data), &msg); err != nil {
+		c.t.Errorf("server sent invalid JSON-RPC message %q: %v", data, err)
+		return
+	}
+	if msg.JSONRPC != "2.0" {
+		c.t.Errorf("server message has jsonrpc %q, want \"2.0\"", msg.JSONRPC)
+	}
+	switch {
+	case msg.Method != "" && isNullID(msg.ID):
+		// Server notification; nothing to answer
+	case msg.Method != "":
+		if msg.Method == "ping" {
+			c.respond(msg.ID, map[string]interface{}{}, nil)
+		} else if handler != nil {
+			result, rpcErr := handler(&msg)
+			c.respond(msg.ID, result, rpcErr)
+		} else {
+			c.respond(msg.ID, nil, &rpcError{Code: -32601, Message: "Method not found: " + msg.Method})
+		}
+	default:
+		c.responses <- &msg
+	}
+}
+
+func (c *conn) respond(id json.RawMessage, result interface{}, rpcErr *rpcError) {
+	response := map[string]interface{}{"jsonrpc": "2.0", "id": id}
+	if rpcErr != nil {
+		response["error"] = rpcErr
+	} else {
+		response["result"] = result
+	}
+	c.post(response)
+}
+
+func (c *conn) newID() int {
+	c.nextID++
+	return c.nextID
+}
+
+// post sends one message or batch to the session endpoint.
+func (c *conn) post(body interface{}) {
+	data, err := json.Marshal(body)
+	if err != nil {
+		c.t.Errorf("marshal request: %v", err)
+		return
+	}
+	req, err := http.NewRequest(http.MethodPost, c.postURL, bytes.NewReader(data))
+	if err != nil {
+		c.t.Errorf("create POST request: %v", err)
+		return
+	}
+	req.Header.Set("Content-Type", "application/json")
+	c.suite.authorize(req)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		c.t.Errorf("POST %s: %v", c.postURL, err)
+		return
+	}
+	resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		c.t.Errorf("POST %s: HTTP %d", c.postURL, resp.StatusCode)
+	}
+}
+
+// call sends a request and waits for its response.
+func (c *conn) call(method string, params interface{}) *rpcMessage {
+	c.t.Helper()
+	id := c.newID()
+	request := map[string]interface{}{"jsonrpc": "2.0", "id": id, "method": method}
+	if params != nil {
+		request["params"] = params
+	}
+	c.post(request)
+	return c.await(id)
+}
+
+// await returns the response with the given ID, keeping others in c.unmatched.
+func (c *conn) await(id int) *rpcMessage {
+	c.t.Helper()
+	key := fmt.Sprint(id)
+	if msg, ok := c.unmatched[key]; ok {
+		delete(c.unmatched, key)
+		return msg
+	}
+	timeout := time.After(c.suite.Timeout)
+	for {
+		select {
+		case msg := <-c.responses:
+			if string(msg.ID) == key {
+				return msg
+			}
+			c.unmatched[string(msg.ID)] = msg
+		case <-timeout:
+			c.t.Fatalf("no response to request %d within %s", id, c.suite.Timeout)
+		}
+	}
+}
+
+// decode unmarshals a successful result; a missing result fails the test.
+func (c *conn) decode(raw json.RawMessage, v interface{}) {
+	c.t.Helper()
+	if len(raw) == 0 {
+		c.t.Fatalf("expected a result, got none")
+	}
+	if err := json.Unmarshal(raw, v); err != nil {
+		c.t.Fatalf("decode result %s: %v", raw, err)
+	}
+}
+
+// requireCapability skips the test when the server did not declare the capability.
+func (c *conn) requireCapability(name string) {
+	c.t.Helper()
+	if c.capabilities[name] == nil {
+		c.t.Skipf("server does not declare the %s capability", name)
+	}
+}
+
+func isNullID(id json.RawMessage) bool {
+	return len(id) == 0 || string(id) == "null"
+}
@@ -5,6 +5,8 @@ import (
 	"fmt"
 	"log"
 	"net"
+	"slices"
+	"strings"
 	"sync"
 	"time"
 
@@ -157,7 +159,7 @@ func (e *Envs) Execute(ctx context.Context) error {
 	for name := range e.components {
 		if !overallVisited[name] { // Only start DFS from unvisited nodes
 			recStack := make(map[string]bool) // Reset recursion stack for each DFS run
-			cycle := detectCycle(name, dependenciesMap, overallVisited, recStack)
+			cycle := detectCycle(name, dependenciesMap, overallVisited, recStack, nil)
 			if cycle != "" {
 				err := fmt.Errorf("dependency cycle detected: %s", cycle)
 				log.Printf("ERROR: %v", err)
@@ -388,11 +390,20 @@ func (e *Envs) cleanupStarted(started map[string]struct{}) {
 }
 
 // detectCycle performs a depth-first search to find cycles in the dependency graph.
-// Returns a string representation of the detected cycle, or empty string if no cycle found.
-func detectCycle(node string, depMap map[string][]string, visited map[string]bool, recStack map[string]bool) string {
-	// If node is already in recursion stack, cycle detected
+// path holds the nodes of the current recursion stack in visiting order. Returns the full
+// cycle such as "A -> B -> C -> A", or an empty string if no cycle is found.
+func detectCycle(node string, depMap map[string][]string, visited map[string]bool, recStack map[string]bool, path []string) string {
+	// If node is already in recursion stack, the path from its first occurrence is the cycle
 	if recStack[node] {
-		return node // Return the node where the cycle is detected
+		cycle := []string{node}
+		for i := len(path) - 1; i >= 0; i-- {
+			cycle = append(cycle, path[i])
+			if path[i] == node {
+				break
+			}
+		}
+		slices.Reverse(cycle)
+		return strings.Join(cycle, " -> ")
 	}
 
 	// If node has already been fully visited in this DFS run, no need to revisit
@@ -403,19 +414,12 @@ func detectCycle(node string, depMap map[string][]string, visited map[string]boo
 	// Mark node as visited for this DFS run and add to recursion stack
 	visited[node] = true
 	recStack[node] = true
+	path = append(path, node)
 
 	// Visit all dependencies of this node
 	for _, dep := range depMap[node] {
-		if cycleStartNode := detectCycle(dep, depMap, visited, recStack); cycleStartNode != "" {
-			// If a cycle is found downstream, prepend current node if it's part of the cycle path
-			// The cycle path starts building up from the node that detected the back edge.
-			if cycleStartNode == node {
-				// We completed the cycle back to the start of this recursive call chain
-				return node // Just return the start node name
-			} else {
-				// Part of an ongoing cycle string, prepend current node
-				return fmt.Sprintf("%s -> %s", node, cycleStartNode)
-			}
+		if cycle := detectCycle(dep, depMap, visited, recStack, path); cycle != "" {
+			return cycle
 		}
 	}
 
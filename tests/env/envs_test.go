@@ -0,0 +1,38 @@
+package env
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+// dependentEnv is a no-op component that depends on the given components.
+type dependentEnv struct {
+	BaseEnv
+	deps []string
+}
+
+func (d *dependentEnv) Configure(envs *Envs) ([]string, error) {
+	return d.deps, nil
+}
+
+func TestExecuteReportsFullDependencyCycle(t *testing.T) {
+	envs := NewEnvs()
+	envs.Register(
+		&dependentEnv{BaseEnv: BaseEnv{name: "A"}, deps: []string{"B"}},
+		&dependentEnv{BaseEnv: BaseEnv{name: "B"}, deps: []string{"C"}},
+		&dependentEnv{BaseEnv: BaseEnv{name: "C"}, deps: []string{"A"}},
+	)
+
+	err := envs.Execute(context.Background())
+	if err == nil {
+		t.Fatal("Execute succeeded despite a dependency cycle")
+	}
+	// The DFS may enter the cycle at any component
+	for _, cycle := range []string{"A -> B -> C -> A", "B -> C -> A -> B", "C -> A -> B -> C"} {
+		if strings.Contains(err.Error(), cycle) {
+			return
+		}
+	}
+	t.Fatalf("Error %q does not contain the full cycle A -> B -> C -> A", err)
+}
@@ -161,8 +161,8 @@ func addMCPServer(am *ArtifactManager, user *User, slug string) (*CatalogServer,
 		return nil, fmt.Errorf("failed to click Add MCP Server button: %w", err)
 	}
 
-	// Wait for navigation to the server details page
-	expectedServerUrlPattern := fmt.Sprintf("**/servers/%s", slug)
+	// Wait for navigation to the server details page. The portal adds a numeric suffix to a taken slug.
+	expectedServerUrlPattern := fmt.Sprintf("**/servers/%s*", slug)
 	if err := am.Page.WaitForURL(expectedServerUrlPattern, playwright.PageWaitForURLOptions{
 		Timeout:   playwright.Float(30000),              // Generous timeout for potential redirects/loads
 		WaitUntil: playwright.WaitUntilStateNetworkidle, // Wait for network activity to cease
@@ -177,10 +177,12 @@ func addMCPServer(am *ArtifactManager, user *User, slug string) (*CatalogServer,
 
 	// Extract slug from URL for verification (optional, but good practice)
 	extractedSlug := extractServerSlugFromURL(am.Page.URL())
-	if extractedSlug == "" || extractedSlug != slug {
-		am.T.Logf("Warning: Extracted slug '%s' does not match expected '%s' from URL: %s", extractedSlug, slug, am.Page.URL())
+	if extractedSlug == "" {
+		am.T.Logf("Warning: Could not extract slug from URL: %s", am.Page.URL())
 		// Use the originally intended slug for the return object if extraction fails
 		extractedSlug = slug
+	} else if extractedSlug != slug {
+		am.T.Logf("Slug %s was taken, the server was saved as %s", slug, extractedSlug)
 	}
 
 	server := &CatalogServer{
@@ -403,11 +405,14 @@ func TestAddMCPServer(t *testing.T) {
 	_, err = am.WaitForLocatorWithDebug(toolsHeadingSelector, "tools_section_heading_wait")
 	require.NoError(t, err, "Tools section heading not found on server details page")
 
-	// Wait for the expansion panel for the 'add' tool using data-testid
-	addToolPanelSelector := ".v-expansion-panel:has(.v-expansion-panel-title:has-text('add'))" // More specific selector
+	// The 'add' tool carries the annotation title "Add Numbers", which is shown instead of its internal name
+	addToolPanelSelector := ".v-expansion-panel:has([data-testid='tool-title']:text-is('Add Numbers'))"
 	addToolTitleSelector := addToolPanelSelector + " .v-expansion-panel-title"
 	addToolPanelTitle, err := am.WaitForLocatorWithDebug(addToolTitleSelector, "add_tool_panel_title_wait")
 	require.NoError(t, err, "Could not find the panel title for the 'add' tool")
+	addToolTitleText, err := am.Page.Locator(addToolPanelSelector + " [data-testid='tool-title']").TextContent()
+	require.NoError(t, err, "Failed to get the displayed title of the 'add' tool")
+	require.Equal(t, "Add Numbers", strings.TrimSpace(addToolTitleText), "Tool title should be displayed instead of the internal name")
 
 	// Click to expand the panel
 	err = addToolPanelTitle.Click(playwright.LocatorClickOptions{Timeout: playwright.Float(5000)})
@@ -474,12 +479,11 @@ func TestAddServer_NonUniqueSlug(t *testing.T) {
 	require.NotEmpty(t, server.Slug, "Server slug should not be empty after creation")
 
 	am.T.Logf("Attempting to add server with the same slug: %s", validSlug)
-	_, err = addMCPServer(am, user, validSlug)
-	require.Error(t, err, "Should have failed with duplicate slug")
-	// Error message comes from the slug check logic now
-	require.Contains(t, err.Error(), "invalid server slug: This slug is already taken.", "Error message should indicate that slug already exists")
-	am.T.Log("Verified adding with duplicate slug failed as expected.")
-	am.SaveScreenshot("add_server_non_unique_slug_fail")
+	second, err := addMCPServer(am, user, validSlug)
+	require.NoError(t, err, "Adding a server with a taken slug should resolve it to a free one")
+	require.Equal(t, validSlug+"-2", second.Slug, "Taken slug should get the -2 suffix")
+	am.T.Log("Verified duplicate slug was resolved with a numeric suffix.")
+	am.SaveScreenshot("add_server_non_unique_slug_resolved")
 }
 
 func TestAddServer_InvalidSlugFormat(t *testing.T) {
@@ -82,3 +82,40 @@ func TestServerSubscriptions(t *testing.T) {
 	err = subscribeToServer(am, subscriber, server)
 	require.NoError(t, err, "Failed to subscribe to server")
 }
+
+// TestServerSubscriptionQuota tests that the portal refuses subscriptions over gateway_max_subscriptions_per_user
+func TestServerSubscriptionQuota(t *testing.T) {
+	am := NewArtifactManager(t)
+	defer am.Close()
+
+	require.NoError(t, updateSetting("gateway_max_subscriptions_per_user", 1), "Failed to set the subscription quota")
+	defer func() {
+		require.NoError(t, updateSetting("gateway_max_subscriptions_per_user", 0), "Failed to reset the subscription quota")
+	}()
+
+	owner, err := createUser(am)
+	require.NoError(t, err, "Failed to create owner user")
+
+	subscriber, err := createUser(am)
+	require.NoError(t, err, "Failed to create subscriber user")
+
+	var servers []*CatalogServer
+	for _, slug := range []string{"test-server-quota-first", "test-server-quota-second"} {
+		server, err := addMCPServer(am, owner, slug)
+		require.NoError(t, err, "Failed to add server %s", slug)
+		require.NoError(t, doServerAcvite(am, owner, server), "Failed to activate server %s", slug)
+		servers = append(servers, server)
+	}
+
+	// The first subscription is within the quota
+	require.NoError(t, subscribeToServer(am, subscriber, servers[0]), "Failed to subscribe within the quota")
+
+	// The second one is refused
+	subscribeBtnSelector := "button:has(span.v-btn__content > i.mdi-account-plus):has-text('Subscribe')"
+	am.OpenPageWithURL(fmt.Sprintf("/servers/%s", servers[1].Slug))
+	require.NoError(t, am.ClickWithDebug(subscribeBtnSelector, "subscribe_button_over_quota"))
+	_, err = am.WaitForLocatorWithDebug(".v-snackbar:has-text('Subscription limit reached')", "subscription_quota_snackbar")
+	require.NoError(t, err, "Subscription over the quota was not refused")
+	_, err = am.WaitForLocatorWithDebug(subscribeBtnSelector, "subscribe_button_after_refusal")
+	require.NoError(t, err, "Subscribe button should remain after the refused subscription")
+}
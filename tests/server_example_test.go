@@ -65,8 +65,8 @@ func TestMCP2024SuccessfulToolCall(t *testing.T) {
 	require.NoError(t, err, "Failed to create request")
 
 	// Set headers
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("Accept", "application/json")
+	requestPOST.Header.Set("Content-Type", "application/json")
+	requestPOST.Header.Set("Accept", "application/json")
 
 	// Send the request
 	client2 := &http.Client{Timeout: 10 * time.Second} // Add timeout